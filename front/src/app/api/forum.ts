@@ -717,6 +717,98 @@ export function connectToGeneralChatWebSocket(
   };
 }
 
+// --- Комнаты (постоянные общие чаты) ---
+// Комнату можно указывать числовым id или постоянным именем, например 'general'
+
+export interface RoomMessageDto {
+  id: number;
+  room_id: number;
+  author_id: number;
+  author_name: string;
+  content: string;
+  content_html: string;
+  created_at: string;
+  updated_at?: string;
+}
+
+export const mapRoomMessageToChatMessage = (dto: RoomMessageDto): ChatMessage => ({
+  id: String(dto.id),
+  text: dto.content,
+  author: { id: String(dto.author_id), name: dto.author_name },
+  createdAt: normalizeIsoDateString(dto.created_at),
+});
+
+export const fetchRoomMessages = async (room: string): Promise<ChatMessage[]> => {
+  const response = await fetchWithAuth(`/rooms/${room}/messages`);
+  if (!response.ok) {
+    throw new Error(`Failed to fetch messages for room ${room}. Status: ${response.status}`);
+  }
+  const apiMessages: RoomMessageDto[] = await response.json();
+  // сервер отдаёт новые сверху, в чате они нужны снизу
+  return apiMessages.map(mapRoomMessageToChatMessage).reverse();
+};
+
+export const sendRoomMessage = async (room: string, content: string): Promise<ChatMessage> => {
+  const response = await fetchWithAuth(`/rooms/${room}/messages`, {
+    method: 'POST',
+    body: JSON.stringify({ content }),
+  });
+  if (!response.ok) {
+    const errorText = await response.text().catch(() => '');
+    throw new Error(`Failed to send message. Status: ${response.status}. ${errorText}`);
+  }
+  return mapRoomMessageToChatMessage(await response.json());
+};
+
+export const editRoomMessage = async (room: string, messageId: string, content: string): Promise<void> => {
+  const response = await fetchWithAuth(`/rooms/${room}/messages/${messageId}`, {
+    method: 'PUT',
+    body: JSON.stringify({ content }),
+  });
+  if (!response.ok) {
+    throw new Error(`Failed to edit message. Status: ${response.status}`);
+  }
+};
+
+export const deleteRoomMessage = async (room: string, messageId: string): Promise<void> => {
+  const response = await fetchWithAuth(`/rooms/${room}/messages/${messageId}`, {
+    method: 'DELETE',
+  });
+  if (!response.ok && response.status !== 404) {
+    throw new Error(`Failed to delete message. Status: ${response.status}`);
+  }
+};
+
+/**
+ * Подключение к WebSocket комнаты. Токен передаётся в query, чтобы сервер учёл пользователя в присутствии.
+ * @param room id или постоянное имя комнаты
+ * @param handlers { onMessage, onOpen, onClose, onError }
+ * @returns disconnect(): void
+ */
+export function connectToRoomWebSocket(
+  room: string,
+  handlers: {
+    onMessage: (data: { action: string; room_message?: RoomMessageDto; message_id?: number; presence?: unknown }) => void,
+    onOpen?: () => void,
+    onClose?: (ev: CloseEvent) => void,
+    onError?: (ev: Event) => void,
+  }
+) {
+  const token = localStorage.getItem('accessToken');
+  const wsProtocol = window.location.protocol === 'https:' ? 'wss' : 'ws';
+  const query = token ? `?access_token=${encodeURIComponent(token)}` : '';
+  const ws = new WebSocket(`${wsProtocol}://${window.location.host}/ws/rooms/${room}${query}`);
+
+  ws.onopen = () => handlers.onOpen?.();
+  ws.onmessage = (event) => handlers.onMessage(JSON.parse(event.data));
+  ws.onclose = (ev) => handlers.onClose?.(ev);
+  ws.onerror = (ev) => handlers.onError?.(ev);
+
+  return () => {
+    ws.close();
+  };
+}
+
 // Убедимся, что все экспорты на месте
 // fetchCategories, addCategory, deleteCategory - обновлены
 // fetchTopicsByCategoryId, fetchCategoryById, fetchTopicById - пока моки или частично API
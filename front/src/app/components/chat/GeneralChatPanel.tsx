@@ -1,7 +1,7 @@
 import { useEffect, useRef, useState, type FormEvent } from 'react';
 import { useQuery, useMutation } from '@tanstack/react-query';
-import { fetchMessagesByTopicId, sendTopicMessage, connectToTopicWebSocket, editTopicMessage, deleteTopicMessage } from '@/app/api/forum';
-import type { TopicMessage, ChatMessage } from '@/types/forum';
+import { fetchRoomMessages, sendRoomMessage, connectToRoomWebSocket, editRoomMessage, deleteRoomMessage, mapRoomMessageToChatMessage } from '@/app/api/forum';
+import type { ChatMessage } from '@/types/forum';
 import { useAuth } from '@/app/contexts/AuthContext';
 import { Button } from '@/components/ui/button';
 import { Input } from '@/components/ui/input';
@@ -22,11 +22,8 @@ import {
   AlertDialogTrigger,
 } from '@/components/ui/alert-dialog';
 
-// --- ВСПОМОГАТЕЛЬНАЯ ФУНКЦИЯ ДЛЯ ДАТЫ ИЗ WEBSOCKET ---
-function normalizeIsoDateString(iso: string): string {
-  if (typeof iso !== 'string') return '';
-  return iso.replace(/(\.\d{3})\d*(Z)/, '$1$2');
-}
+// Общий чат — комната с постоянным именем general
+const GENERAL_ROOM = 'general';
 
 export function GeneralChatPanel() {
   const { user, isAuthenticated } = useAuth();
@@ -39,44 +36,26 @@ export function GeneralChatPanel() {
   const [editingMessageId, setEditingMessageId] = useState<string | null>(null);
   const [editingContent, setEditingContent] = useState('');
 
-  // Получение сообщений через API топика id=0
-  const { data: initialMessages, isLoading, isError, error } = useQuery<TopicMessage[], Error>({
+  // Получение последних сообщений комнаты
+  const { data: initialMessages, isLoading, isError, error } = useQuery<ChatMessage[], Error>({
     queryKey: ['generalChatMessages'],
-    queryFn: () => fetchMessagesByTopicId('0'),
+    queryFn: () => fetchRoomMessages(GENERAL_ROOM),
     staleTime: Infinity,
   });
 
   useEffect(() => {
     if (initialMessages) {
-      // Преобразуем TopicMessage[] в ChatMessage[] для совместимости
-      setMessages(
-        initialMessages.map((msg) => ({
-          id: msg.id,
-          text: msg.content,
-          author: { id: msg.author, name: msg.authorName },
-          createdAt: msg.createdAt,
-        }))
-      );
+      setMessages(initialMessages);
     }
   }, [initialMessages]);
 
-  // Подписка на новые сообщения через WebSocket
+  // Подписка на новые сообщения через WebSocket комнаты
   useEffect(() => {
-    const disconnect = connectToTopicWebSocket('0', {
-      onMessage: (wsData: any) => {
-        // wsData: { action: 'created'|'updated'|'deleted', message, message_id }
-        console.log('wsData', wsData);
-        if ((wsData.action === 'created' || wsData.action === 'updated') && wsData.message) {
-          const msg: ChatMessage = {
-            id: String(wsData.message.ID ?? wsData.message.id),
-            text: wsData.message.Content ?? wsData.message.content ?? wsData.message.text,
-            author: {
-              id: String(wsData.message.author ?? wsData.message.author_id ?? wsData.message.AuthorID),
-              name: wsData.message.authorName ?? wsData.message.author_name ?? (wsData.message.author && wsData.message.author.name) ?? '',
-            },
-            createdAt: normalizeIsoDateString(wsData.message.CreatedAt ?? wsData.message.created_at ?? wsData.message.createdAt),
-          };
-          console.log('msg', msg);
+    const disconnect = connectToRoomWebSocket(GENERAL_ROOM, {
+      onMessage: (wsData) => {
+        // wsData: { action: 'created'|'updated'|'deleted'|'presence', room_message, message_id }
+        if ((wsData.action === 'created' || wsData.action === 'updated') && wsData.room_message) {
+          const msg = mapRoomMessageToChatMessage(wsData.room_message);
           setMessages((prev) => {
             if (wsData.action === 'created') {
               if (prev.find((m) => m.id === msg.id)) return prev;
@@ -140,11 +119,11 @@ export function GeneralChatPanel() {
   // --- Мутация для редактирования сообщения ---
   const editMessageMutation = useMutation({
     mutationFn: (payload: { messageId: string; newContent: string }) =>
-      editTopicMessage({ topicId: '0', messageId: payload.messageId, newContent: payload.newContent }),
+      editRoomMessage(GENERAL_ROOM, payload.messageId, payload.newContent),
     onSuccess: (_, variables) => {
       toast.success('Сообщение успешно обновлено');
       setMessages((prev) => prev.map(msg =>
-        msg.id === variables.messageId ? { ...msg, content: variables.newContent } : msg
+        msg.id === variables.messageId ? { ...msg, text: variables.newContent } : msg
       ));
       setEditingMessageId(null);
       setEditingContent('');
@@ -156,7 +135,7 @@ export function GeneralChatPanel() {
 
   // --- Мутация для удаления сообщения ---
   const deleteMessageMutation = useMutation({
-    mutationFn: (messageId: string) => deleteTopicMessage('0', messageId),
+    mutationFn: (messageId: string) => deleteRoomMessage(GENERAL_ROOM, messageId),
     onSuccess: (_, messageId) => {
       toast.success('Сообщение успешно удалено');
       setMessages((prev) => prev.filter(msg => msg.id !== messageId));
@@ -167,7 +146,7 @@ export function GeneralChatPanel() {
   });
 
   const sendMessageMutation = useMutation({
-    mutationFn: (content: string) => sendTopicMessage('0', { author: user?.name || 'Аноним', content }),
+    mutationFn: (content: string) => sendRoomMessage(GENERAL_ROOM, content),
     onSuccess: () => {
       setNewMessage('');
       inputRef.current?.focus();
@@ -15,6 +15,10 @@ export const Route = createFileRoute('/register')({
 });
 
 const registerSchema = z.object({
+  // Те же правила, что у auth-service: латиница, цифры, '_', '.', '-'; точка и дефис не на краях
+  username: z
+    .string()
+    .regex(/^@?[A-Za-z0-9_][A-Za-z0-9_.-]{1,30}[A-Za-z0-9_]$/, 'Username: 3-32 латинские буквы, цифры, _, . или -'),
   name: z.string().min(1, 'Имя не может быть пустым'),
   email: z.string().email('Некорректный email'),
   // Убедись, что minLength для пароля соответствует требованиям API (swagger: minLength: 8)
@@ -30,6 +34,7 @@ function RegisterPage() {
 
   const form = useForm<RegisterFormData>({
     defaultValues: {
+      username: '',
       name: '',
       email: '',
       password: '',
@@ -62,6 +67,32 @@ function RegisterPage() {
           }}
         >
           <CardContent className="space-y-4">
+            <form.Field
+              name="username"
+              validators={{ onChange: registerSchema.shape.username }}
+              children={(field) => (
+                <div className="space-y-1">
+                  <Label htmlFor={field.name}>Username</Label>
+                  <Input
+                    id={field.name}
+                    name={field.name}
+                    value={field.state.value}
+                    onBlur={field.handleBlur}
+                    onChange={(e) => field.handleChange(e.target.value)}
+                    placeholder="например, ivan_petrov"
+                  />
+                  {field.state.meta.isTouched && field.state.meta.errors?.length > 0 ? (
+                    <p className="text-sm text-destructive">
+                      {field.state.meta.errors.map((err) => {
+                        if (typeof err === 'string') return err;
+                        if (err && typeof err === 'object' && 'message' in err) return (err as { message: string }).message;
+                        return 'Неверное значение'; // Fallback
+                      }).join(', ')}
+                    </p>
+                  ) : null}
+                </div>
+              )}
+            />
             <form.Field
               name="name"
               validators={{ onChange: registerSchema.shape.name }}
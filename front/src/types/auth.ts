@@ -4,6 +4,7 @@ export interface LoginRequest {
 }
 
 export interface RegisterRequest {
+  username: string;
   email: string;
   name: string;
   password?: string;
@@ -15,6 +16,7 @@ export interface TokenResponse {
 
 export interface User {
   id: number | string; // В swagger id - integer, но может быть и uuid
+  username: string;
   name: string;
   email: string;
   role: string; // Например, 'user', 'admin'
@@ -17,6 +17,9 @@ type (
 		JWT                JWT
 		Swagger            Swagger
 		SessionCleanupCron SessionCleanupCron
+		AuditRetention     AuditRetention
+		BlockExpiry        BlockExpiry
+		Usernames          Usernames
 	}
 
 	// App -.
@@ -34,6 +37,23 @@ type (
 		Schedule string `env:"SESSION_CLEANUP_CRON,required"`
 	}
 
+	// AuditRetention — сколько хранить audit_events и когда чистить
+	AuditRetention struct {
+		Schedule string `env:"AUDIT_CLEANUP_CRON" envDefault:"30 0 * * *"`
+		Days     int    `env:"AUDIT_RETENTION_DAYS" envDefault:"180"`
+	}
+
+	// BlockExpiry — как часто снимать истёкшие временные блокировки
+	BlockExpiry struct {
+		Schedule string `env:"BLOCK_EXPIRY_CRON" envDefault:"*/5 * * * *"`
+	}
+
+	// Usernames — как часто можно менять username и сколько прежний держится за владельцем
+	Usernames struct {
+		ChangeCooldown    time.Duration `env:"USERNAME_CHANGE_COOLDOWN" envDefault:"720h"`
+		ReservationPeriod time.Duration `env:"USERNAME_RESERVATION" envDefault:"2160h"`
+	}
+
 	JWT struct {
 		Secret     string        `env:"JWT_SECRET,required"`
 		AccessTTL  time.Duration `env:"JWT_ACCESS_TTL,required"`  // 15m
@@ -44,6 +44,8 @@ func Run(cfg *config.Config) {
 	// Repositories
 	userRepo := repo.NewUserRepo(pg)
 	sessRepo := repo.NewSessionRepo(pg)
+	modRepo := repo.NewModeratorRepo(pg)
+	auditRepo := repo.NewAuditRepo(pg)
 
 	// Services
 	hasherSvc := hasher.NewHasher()
@@ -54,17 +56,29 @@ func Run(cfg *config.Config) {
 	)
 
 	// Use-cases
-	userUC := usecase.NewUserUsecase(userRepo, sessRepo, hasherSvc, tokens, l)
-	sessUC := usecase.NewSessionUsecase(sessRepo, userRepo, tokens, l)
+	userUC := usecase.NewUserUsecase(userRepo, sessRepo, modRepo, auditRepo, hasherSvc, tokens, usecase.UsernamePolicy{
+		ChangeCooldown:    cfg.Usernames.ChangeCooldown,
+		ReservationPeriod: cfg.Usernames.ReservationPeriod,
+	}, l)
+	sessUC := usecase.NewSessionUsecase(sessRepo, userRepo, auditRepo, tokens, l)
+	auditUC := usecase.NewAuditUsecase(auditRepo, time.Duration(cfg.AuditRetention.Days)*24*time.Hour, l)
 
 	// Router
-	router := httpd.NewRouter(l, userUC, sessUC, tokens, cfg)
+	router := httpd.NewRouter(l, userUC, sessUC, auditUC, cfg)
 
 	// Cron
-	cron := cron.NewSessionCleanupCron(l, sessUC)
-	if err := cron.Start(cfg.SessionCleanupCron.Schedule); err != nil {
+	sessionCron := cron.NewSessionCleanupCron(l, sessUC)
+	if err := sessionCron.Start(cfg.SessionCleanupCron.Schedule); err != nil {
 		l.Fatal("cron startup failed", "err", err)
 	}
+	auditCron := cron.NewAuditCleanupCron(l, auditUC)
+	if err := auditCron.Start(cfg.AuditRetention.Schedule); err != nil {
+		l.Fatal("audit cron startup failed", "err", err)
+	}
+	blockCron := cron.NewBlockExpiryCron(l, userUC)
+	if err := blockCron.Start(cfg.BlockExpiry.Schedule); err != nil {
+		l.Fatal("block expiry cron startup failed", "err", err)
+	}
 
 	// HTTP Server
 	srv := &http.Server{
@@ -83,7 +97,7 @@ func Run(cfg *config.Config) {
 		}
 	}()
 
-	grpcSrv := transportgrpc.NewRouter(l, tokens)
+	grpcSrv := transportgrpc.NewRouter(l, userUC, sessUC)
 
 	addr := ":" + cfg.GRPC.Port // фикс
 
@@ -27,3 +27,22 @@ func FromContext(ctx context.Context) (userID int64, role string) {
 	}
 	return
 }
+
+type clientKey struct{}
+
+// Client — откуда пришёл запрос; пишется в журнал аудита
+type Client struct {
+	IP        string
+	UserAgent string
+}
+
+// WithClient кладёт IP и User-Agent запроса в контекст
+func WithClient(ctx context.Context, ip, userAgent string) context.Context {
+	return context.WithValue(ctx, clientKey{}, Client{IP: ip, UserAgent: userAgent})
+}
+
+// ClientFromContext возвращает IP и User-Agent; для фоновых задач — пустые
+func ClientFromContext(ctx context.Context) Client {
+	c, _ := ctx.Value(clientKey{}).(Client)
+	return c
+}
@@ -0,0 +1,42 @@
+package auth
+
+// Роли пользователей (значение users.role и claim role в JWT)
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Permission — именованное право; проверяется вместо сравнения ролей
+type Permission string
+
+const (
+	PermUserList   Permission = "user.list"   // список всех пользователей с email
+	PermUserBlock  Permission = "user.block"  // блокировать и разблокировать пользователей
+	PermRoleManage Permission = "role.manage" // выдавать и снимать роли, назначать категории модераторам
+	PermAuditView  Permission = "audit.view"  // читать журнал аудита
+)
+
+// policy — таблица прав: право → роли, у которых оно есть. Чего нет в таблице, то запрещено.
+// Область модератора (категории) проверяет chat-service, здесь права глобальные.
+var policy = map[Permission]map[string]bool{
+	PermUserList:   {RoleAdmin: true},
+	PermUserBlock:  {RoleAdmin: true},
+	PermRoleManage: {RoleAdmin: true},
+	PermAuditView:  {RoleAdmin: true},
+}
+
+// Can сообщает, есть ли у роли право perm
+func Can(role string, perm Permission) bool {
+	return policy[perm][role]
+}
+
+// ValidRole сообщает, что роль известна
+func ValidRole(role string) bool {
+	switch role {
+	case RoleUser, RoleModerator, RoleAdmin:
+		return true
+	default:
+		return false
+	}
+}
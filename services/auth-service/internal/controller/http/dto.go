@@ -3,6 +3,7 @@ package http
 import "time"
 
 type RegisterRequest struct {
+	Username string `json:"username" binding:"required"`
 	Name     string `json:"name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=8"`
@@ -20,12 +21,64 @@ type UpdateUserRequest struct {
 }
 
 type UserResponse struct {
-	ID        int64     `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	Role      string    `json:"role"`
-	IsBlocked bool      `json:"is_blocked"`
-	CreatedAt time.Time `json:"created_at"`
+	ID           int64      `json:"id"`
+	Username     string     `json:"username"`
+	Name         string     `json:"name"`
+	Email        string     `json:"email"`
+	Role         string     `json:"role"`
+	IsBlocked    bool       `json:"is_blocked"`
+	BlockedUntil *time.Time `json:"blocked_until,omitempty"` // у бессрочной блокировки не заполняется
+	BlockReason  string     `json:"block_reason,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type ChangeUsernameRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+// UsernameAvailabilityResponse — reason заполняется, когда username занять нельзя
+type UsernameAvailabilityResponse struct {
+	Username  string `json:"username"`
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+type SetRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user moderator admin"`
+}
+
+type ModeratedCategoriesResponse struct {
+	CategoryIDs []int64 `json:"category_ids"`
+}
+
+// AuditQuery — фильтры журнала аудита; from/to в RFC3339
+type AuditQuery struct {
+	ActorID  int64     `form:"actor_id" binding:"omitempty,min=1"`
+	TargetID int64     `form:"target_id" binding:"omitempty,min=1"`
+	Action   string    `form:"action"`
+	IP       string    `form:"ip"`
+	From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit    int       `form:"limit,default=50" binding:"min=1,max=200"`
+	Offset   int       `form:"offset" binding:"omitempty,min=0"`
+}
+
+type AuditEventResponse struct {
+	ID        int64          `json:"id"`
+	ActorID   *int64         `json:"actor_id"`
+	TargetID  *int64         `json:"target_id"`
+	Action    string         `json:"action"`
+	IP        string         `json:"ip,omitempty"`
+	UserAgent string         `json:"user_agent,omitempty"`
+	Metadata  map[string]any `json:"metadata"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type AuditListResponse struct {
+	Items  []AuditEventResponse `json:"items"`
+	Total  int64                `json:"total"`
+	Limit  int                  `json:"limit"`
+	Offset int                  `json:"offset"`
 }
 
 type TokenResponse struct {
@@ -2,6 +2,8 @@ package http
 
 import (
 	"auth-service/config"
+	"auth-service/internal/auth"
+	"auth-service/internal/entity"
 	dbErrors "auth-service/internal/errors"
 	"auth-service/internal/usecase"
 	"errors"
@@ -9,6 +11,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 const (
@@ -21,6 +24,7 @@ type Handler struct {
 	cfg    *config.Config
 	userUC usecase.User
 	sessUC usecase.Session
+	audUC  usecase.Audit
 }
 
 // NewHandler создаёт новый Handler
@@ -28,14 +32,15 @@ func NewHandler(
 	log logger.Interface,
 	u usecase.User,
 	s usecase.Session,
+	a usecase.Audit,
 	cfg *config.Config,
 ) *Handler {
-	return &Handler{log: log, cfg: cfg, userUC: u, sessUC: s}
+	return &Handler{log: log, cfg: cfg, userUC: u, sessUC: s, audUC: a}
 }
 
 // Register — POST /auth/register
 // @Summary      Register new user
-// @Description  Create a new user account
+// @Description  Create a new user account. username is a unique handle (3-32 latin letters, digits, '_', '.' or '-', case-insensitive) used in mentions; name is the display name.
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
@@ -52,11 +57,14 @@ func (h *Handler) Register(c *gin.Context) {
 		return
 	}
 
-	if err := h.userUC.Register(c.Request.Context(), req.Name, req.Email, req.Password); err != nil {
+	if err := h.userUC.Register(c.Request.Context(), req.Username, req.Name, req.Email, req.Password); err != nil {
 		if errors.Is(err, usecase.ErrUserExists) {
 			c.AbortWithStatusJSON(http.StatusConflict, ErrorResponse{Code: "USER_EXISTS", Message: "user already exists"})
 			return
 		}
+		if abortIfUsernameError(c, err) {
+			return
+		}
 
 		h.log.Error("Register failed", "err", err)
 		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
@@ -156,6 +164,7 @@ func (h *Handler) Me(c *gin.Context) {
 
 	resp := UserResponse{
 		ID:        user.ID,
+		Username:  user.Username,
 		Name:      user.Name,
 		Email:     user.Email,
 		Role:      user.Role,
@@ -207,6 +216,11 @@ func (h *Handler) UpdateUser(c *gin.Context) {
 				Code:    "USER_EXISTS",
 				Message: "email already in use",
 			})
+		case errors.Is(err, usecase.ErrInvalidName):
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{
+				Code:    "INVALID_NAME",
+				Message: err.Error(),
+			})
 		case errors.Is(err, dbErrors.ErrNotFound):
 			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{
 				Code:    "USER_NOT_FOUND",
@@ -222,6 +236,95 @@ func (h *Handler) UpdateUser(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// CheckUsername — GET /auth/username-available
+// @Summary      Check username availability
+// @Description  Tells whether the username can be taken right now. A username freed by a rename stays reserved for its previous owner for a while and is reported as taken.
+// @Tags         Auth
+// @Produce      json
+// @Param        username  query     string  true  "Username"
+// @Success      200       {object}  UsernameAvailabilityResponse
+// @Failure      400       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /auth/username-available [get]
+func (h *Handler) CheckUsername(c *gin.Context) {
+	raw := c.Query("username")
+	if raw == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "username is required"})
+		return
+	}
+
+	username, err := h.userUC.CheckUsername(c.Request.Context(), raw)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, UsernameAvailabilityResponse{Username: username, Available: true})
+	case errors.Is(err, usecase.ErrInvalidUsername),
+		errors.Is(err, usecase.ErrReservedUsername),
+		errors.Is(err, usecase.ErrUsernameTaken):
+		c.JSON(http.StatusOK, UsernameAvailabilityResponse{Username: raw, Reason: err.Error()})
+	default:
+		h.log.Error("CheckUsername failed", "err", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
+
+// ChangeUsername — PUT /auth/username
+// @Summary      Change own username
+// @Description  Changes the username of the current user. It can be changed once per cooldown period, except for case-only changes; the previous username stays reserved for its owner for a while.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      ChangeUsernameRequest  true  "New username"
+// @Success      204
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /auth/username [put]
+func (h *Handler) ChangeUsername(c *gin.Context) {
+	var req ChangeUsernameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	userID, _ := UserIDFromCtx(c.Request.Context())
+	if err := h.userUC.ChangeUsername(c.Request.Context(), userID, req.Username); err != nil {
+		switch {
+		case abortIfUsernameError(c, err):
+		case errors.Is(err, usecase.ErrUserBlocked):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Code: "USER_BLOCKED", Message: "account is blocked"})
+		case errors.Is(err, dbErrors.ErrNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Code: "USER_NOT_FOUND", Message: "user not found"})
+		default:
+			h.log.Error("ChangeUsername failed", "err", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// abortIfUsernameError отвечает на ошибки проверки username; false — ошибка другая
+func abortIfUsernameError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidUsername), errors.Is(err, usecase.ErrReservedUsername):
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Code: "INVALID_USERNAME", Message: err.Error()})
+	case errors.Is(err, usecase.ErrInvalidName):
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Code: "INVALID_NAME", Message: err.Error()})
+	case errors.Is(err, usecase.ErrUsernameTaken):
+		c.AbortWithStatusJSON(http.StatusConflict, ErrorResponse{Code: "USERNAME_TAKEN", Message: err.Error()})
+	case errors.Is(err, usecase.ErrUsernameCooldown):
+		c.AbortWithStatusJSON(http.StatusConflict, ErrorResponse{Code: "USERNAME_COOLDOWN", Message: err.Error()})
+	default:
+		return false
+	}
+	return true
+}
+
 // Refresh — POST /auth/refresh
 // @Summary      Refresh tokens
 // @Description  Generate new accessToken and refreshToken; reads refreshToken from HttpOnly cookie
@@ -376,9 +479,12 @@ func (h *Handler) DeleteAllSessions(c *gin.Context) {
 
 // BlockUser — POST /users/{id}/block
 // @Summary      Block user (admin only)
+// @Description  Without duration the block is permanent; a temporary block is lifted by cron when it expires.
 // @Tags         Users
 // @Produce      json
-// @Param        id   path      int  true  "User ID"
+// @Param        id        path      int     true   "User ID"
+// @Param        reason    query     string  false  "Reason, stored in the audit log"
+// @Param        duration  query     string  false  "Block duration, e.g. 72h"
 // @Success      204
 // @Failure      400 {object} ErrorResponse
 // @Failure      401 {object} ErrorResponse
@@ -389,7 +495,7 @@ func (h *Handler) DeleteAllSessions(c *gin.Context) {
 // @Router       /users/{id}/block [post]
 func (h *Handler) BlockUser(c *gin.Context) {
 	_, role := UserIDFromCtx(c.Request.Context())
-	if role != "admin" {
+	if !auth.Can(role, auth.PermUserBlock) {
 		c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{
 			Code:    "FORBIDDEN",
 			Message: "admin only",
@@ -405,8 +511,21 @@ func (h *Handler) BlockUser(c *gin.Context) {
 		return
 	}
 
-	if err := h.userUC.Block(c.Request.Context(), id); err != nil {
+	var duration time.Duration
+	if d := c.Query("duration"); d != "" {
+		if duration, err = time.ParseDuration(d); err != nil || duration <= 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{
+				Message: "invalid duration",
+			})
+			return
+		}
+	}
+
+	p := usecase.BlockParams{Reason: c.Query("reason"), Duration: duration}
+	if err := h.userUC.Block(c.Request.Context(), id, p); err != nil {
 		switch {
+		case errors.Is(err, usecase.ErrInvalidBlock):
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
 		case errors.Is(err, dbErrors.ErrNotFound):
 			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{
 				Code:    "USER_NOT_FOUND",
@@ -437,7 +556,7 @@ func (h *Handler) BlockUser(c *gin.Context) {
 // @Router       /users/{id}/unblock [post]
 func (h *Handler) UnblockUser(c *gin.Context) {
 	_, role := UserIDFromCtx(c.Request.Context())
-	if role != "admin" {
+	if !auth.Can(role, auth.PermUserBlock) {
 		c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{
 			Code:    "FORBIDDEN",
 			Message: "admin only",
@@ -498,14 +617,275 @@ func (h *Handler) GetAllUsers(c *gin.Context) {
 	var resp []UserResponse
 	for _, u := range users {
 		resp = append(resp, UserResponse{
-			ID:        u.ID,
-			Name:      u.Name,
-			Email:     u.Email,
-			Role:      u.Role,
-			IsBlocked: u.IsBlocked,
-			CreatedAt: u.CreatedAt,
+			ID:           u.ID,
+			Username:     u.Username,
+			Name:         u.Name,
+			Email:        u.Email,
+			Role:         u.Role,
+			IsBlocked:    u.IsBlocked,
+			BlockedUntil: u.BlockedUntil,
+			BlockReason:  u.BlockReason,
+			CreatedAt:    u.CreatedAt,
 		})
 	}
 	c.JSON(http.StatusOK, resp)
 
 }
+
+// roleErrorResponse — общий разбор ошибок управления ролями
+func (h *Handler) roleErrorResponse(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrForbidden):
+		c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Code: "FORBIDDEN", Message: "admin only"})
+	case errors.Is(err, usecase.ErrInvalidRole):
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Code: "INVALID_ROLE", Message: err.Error()})
+	case errors.Is(err, usecase.ErrSelfRoleChange):
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Code: "SELF_ROLE_CHANGE", Message: err.Error()})
+	case errors.Is(err, usecase.ErrNotModerator):
+		c.AbortWithStatusJSON(http.StatusConflict, ErrorResponse{Code: "NOT_MODERATOR", Message: err.Error()})
+	case errors.Is(err, usecase.ErrCategoryNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Code: "CATEGORY_NOT_FOUND", Message: err.Error()})
+	case errors.Is(err, dbErrors.ErrNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Code: "NOT_FOUND", Message: "user or assignment not found"})
+	default:
+		h.log.Error(op+" failed", "err", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
+
+// SetUserRole — PUT /users/{id}/role
+// @Summary      Grant role to user (admin only)
+// @Description  Sets the user's role: user, moderator or admin. Leaving the moderator role drops all category assignments. Access tokens carrying the old role are rejected with TOKEN_STALE, so the client refreshes and receives the new role. Every change is written to the audit log.
+// @Tags         Users
+// @Accept       json
+// @Param        id       path      int             true  "User ID"
+// @Param        request  body      SetRoleRequest  true  "New role"
+// @Success      204
+// @Failure      400 {object} ErrorResponse
+// @Failure      401 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/{id}/role [put]
+func (h *Handler) SetUserRole(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid user id"})
+		return
+	}
+
+	var req SetRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if err := h.userUC.SetRole(c.Request.Context(), id, req.Role); err != nil {
+		h.roleErrorResponse(c, "set role", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RevokeUserRole — DELETE /users/{id}/role
+// @Summary      Revoke role from user (admin only)
+// @Description  Returns the user to the base role "user"
+// @Tags         Users
+// @Param        id   path      int  true  "User ID"
+// @Success      204
+// @Failure      400 {object} ErrorResponse
+// @Failure      401 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/{id}/role [delete]
+func (h *Handler) RevokeUserRole(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid user id"})
+		return
+	}
+
+	if err := h.userUC.RevokeRole(c.Request.Context(), id); err != nil {
+		h.roleErrorResponse(c, "revoke role", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetModeratedCategories — GET /users/{id}/moderated-categories
+// @Summary      List categories assigned to moderator (admin only)
+// @Tags         Users
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200 {object} ModeratedCategoriesResponse
+// @Failure      400 {object} ErrorResponse
+// @Failure      401 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/{id}/moderated-categories [get]
+func (h *Handler) GetModeratedCategories(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid user id"})
+		return
+	}
+
+	ids, err := h.userUC.ListModeratorCategories(c.Request.Context(), id)
+	if err != nil {
+		h.roleErrorResponse(c, "list moderated categories", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ModeratedCategoriesResponse{CategoryIDs: ids})
+}
+
+// GrantModeratedCategory — PUT /users/{id}/moderated-categories/{category_id}
+// @Summary      Assign moderator to category (admin only)
+// @Description  The user must already have the moderator role. Rights extend to subcategories.
+// @Tags         Users
+// @Param        id           path      int  true  "User ID"
+// @Param        category_id  path      int  true  "Category ID"
+// @Success      204
+// @Failure      400 {object} ErrorResponse
+// @Failure      401 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      409 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/{id}/moderated-categories/{category_id} [put]
+func (h *Handler) GrantModeratedCategory(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid user id"})
+		return
+	}
+	categoryID, err := strconv.ParseInt(c.Param("category_id"), 10, 64)
+	if err != nil || categoryID <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid category id"})
+		return
+	}
+
+	if err := h.userUC.GrantModeratorCategory(c.Request.Context(), id, categoryID); err != nil {
+		h.roleErrorResponse(c, "grant moderated category", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RevokeModeratedCategory — DELETE /users/{id}/moderated-categories/{category_id}
+// @Summary      Remove moderator from category (admin only)
+// @Tags         Users
+// @Param        id           path      int  true  "User ID"
+// @Param        category_id  path      int  true  "Category ID"
+// @Success      204
+// @Failure      400 {object} ErrorResponse
+// @Failure      401 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/{id}/moderated-categories/{category_id} [delete]
+func (h *Handler) RevokeModeratedCategory(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid user id"})
+		return
+	}
+	categoryID, err := strconv.ParseInt(c.Param("category_id"), 10, 64)
+	if err != nil || categoryID <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid category id"})
+		return
+	}
+
+	if err := h.userUC.RevokeModeratorCategory(c.Request.Context(), id, categoryID); err != nil {
+		h.roleErrorResponse(c, "revoke moderated category", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetAuditEvents — GET /audit/events
+// @Summary      Query audit log (admin only)
+// @Description  Newest first. Filters are combined with AND; from/to are RFC3339, to is exclusive.
+// @Tags         Audit
+// @Produce      json
+// @Param        actor_id   query     int     false  "Who performed the action"
+// @Param        target_id  query     int     false  "Affected user"
+// @Param        action     query     string  false  "Action, e.g. auth.login_failed"
+// @Param        ip         query     string  false  "Client IP"
+// @Param        from       query     string  false  "Created at or after (RFC3339)"
+// @Param        to         query     string  false  "Created before (RFC3339)"
+// @Param        limit      query     int     false  "Page size (default 50, max 200)"
+// @Param        offset     query     int     false  "Offset"
+// @Success      200 {object} AuditListResponse
+// @Failure      400 {object} ErrorResponse
+// @Failure      401 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Security     BearerAuth
+// @Router       /audit/events [get]
+func (h *Handler) GetAuditEvents(c *gin.Context) {
+	var q AuditQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{
+			Code:    "INVALID_QUERY",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	f := entity.AuditFilter{
+		ActorID:  q.ActorID,
+		TargetID: q.TargetID,
+		Action:   q.Action,
+		IP:       q.IP,
+		From:     q.From,
+		To:       q.To,
+		Limit:    q.Limit,
+		Offset:   q.Offset,
+	}
+	events, total, err := h.audUC.List(c.Request.Context(), f)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{
+				Code:    "FORBIDDEN",
+				Message: "admin only",
+			})
+		default:
+			h.log.Error("list audit events failed", "err", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+		return
+	}
+
+	out := AuditListResponse{
+		Items:  make([]AuditEventResponse, 0, len(events)),
+		Total:  total,
+		Limit:  q.Limit,
+		Offset: q.Offset,
+	}
+	for _, e := range events {
+		out.Items = append(out.Items, AuditEventResponse{
+			ID:        e.ID,
+			ActorID:   e.ActorID,
+			TargetID:  e.TargetID,
+			Action:    e.Action,
+			IP:        e.IP,
+			UserAgent: e.UserAgent,
+			Metadata:  e.Metadata,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, out)
+}
@@ -1,13 +1,15 @@
 package http
 
 import (
+	"auth-service/internal/auth"
+	"auth-service/internal/usecase"
 	"context"
+	"errors"
 	"github.com/ZoyaDenisova/go-common/contextkeys"
 	"net/http"
 	"strings"
 	"time"
 
-	"github.com/ZoyaDenisova/go-common/jwt"
 	"github.com/ZoyaDenisova/go-common/logger"
 	"github.com/gin-gonic/gin"
 )
@@ -19,8 +21,9 @@ func UserIDFromCtx(ctx context.Context) (int64, string) {
 	return uid, role
 }
 
-// AuthMiddleware проверяет Bearer токен и кладёт userID/role в контекст
-func AuthMiddleware(tm jwt.TokenManager) gin.HandlerFunc {
+// AuthMiddleware проверяет Bearer токен и кладёт userID/role в контекст.
+// Роль сверяется с базой: токен со старой ролью отклоняется с кодом TOKEN_STALE — клиенту нужен refresh.
+func AuthMiddleware(s usecase.Session) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		const bearer = "Bearer "
 		h := c.GetHeader("Authorization")
@@ -29,9 +32,16 @@ func AuthMiddleware(tm jwt.TokenManager) gin.HandlerFunc {
 			return
 		}
 		token := strings.TrimPrefix(h, bearer)
-		uid, role, err := tm.ValidateAccess(token)
+		uid, role, err := s.VerifyAccess(c.Request.Context(), token)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid token"})
+			switch {
+			case errors.Is(err, usecase.ErrStaleToken):
+				c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Code: "TOKEN_STALE", Message: err.Error()})
+			case errors.Is(err, usecase.ErrUserBlocked):
+				c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Code: "USER_BLOCKED", Message: "user is blocked"})
+			default:
+				c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid token"})
+			}
 			return
 		}
 		ctx := context.WithValue(c.Request.Context(), contextkeys.UserIDKey{}, uid)
@@ -41,6 +51,15 @@ func AuthMiddleware(tm jwt.TokenManager) gin.HandlerFunc {
 	}
 }
 
+// ClientMiddleware кладёт IP и User-Agent запроса в контекст для журнала аудита
+func ClientMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := auth.WithClient(c.Request.Context(), c.ClientIP(), c.Request.UserAgent())
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
 // LoggingMiddleware логирует каждый HTTP-запрос
 func LoggingMiddleware(log logger.Interface) gin.HandlerFunc {
 	return func(c *gin.Context) {
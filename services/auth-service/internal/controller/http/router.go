@@ -13,7 +13,6 @@ import (
 	_ "auth-service/cmd/app/docs"
 	"auth-service/config"
 	"auth-service/internal/usecase"
-	"github.com/ZoyaDenisova/go-common/jwt"
 	"github.com/ZoyaDenisova/go-common/logger"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -26,13 +25,14 @@ func NewRouter(
 	log logger.Interface,
 	u usecase.User,
 	s usecase.Session,
-	tm jwt.TokenManager,
+	a usecase.Audit,
 	cfg *config.Config,
 ) http.Handler {
 	r := gin.New()
 
 	// middlewares
 	r.Use(LoggingMiddleware(log))
+	r.Use(ClientMiddleware())
 	r.Use(gin.Recovery())
 	//r.Use(cors.New(cors.Config{
 	//	AllowOrigins:     []string{"http://localhost:5173"},
@@ -52,7 +52,7 @@ func NewRouter(
 	})
 
 	// init handler
-	h := NewHandler(log, u, s, cfg)
+	h := NewHandler(log, u, s, a, cfg)
 
 	corsConfig := cors.Config{
 		// Явный список origins (нужен, чтобы gin-contrib не ругался на AllowCredentials с "*")
@@ -99,24 +99,37 @@ func NewRouter(
 		r.POST("/auth/register", h.Register)
 		r.POST("/auth/login", h.Login)
 		r.POST("/auth/refresh", h.Refresh)
+		r.GET("/auth/username-available", h.CheckUsername)
 
 		// PROTECTED
 		secured := r.Group("/users")
-		secured.Use(AuthMiddleware(tm))
+		secured.Use(AuthMiddleware(s))
 		{
 			secured.GET("", h.GetAllUsers)
 			secured.POST("/:id/block", h.BlockUser)
 			secured.POST("/:id/unblock", h.UnblockUser)
+			secured.PUT("/:id/role", h.SetUserRole)
+			secured.DELETE("/:id/role", h.RevokeUserRole)
+			secured.GET("/:id/moderated-categories", h.GetModeratedCategories)
+			secured.PUT("/:id/moderated-categories/:category_id", h.GrantModeratedCategory)
+			secured.DELETE("/:id/moderated-categories/:category_id", h.RevokeModeratedCategory)
 		}
 
 		securedAuth := r.Group("/auth")
-		securedAuth.Use(AuthMiddleware(tm))
+		securedAuth.Use(AuthMiddleware(s))
 		{
 			securedAuth.DELETE("/session", h.DeleteSession)
 			securedAuth.DELETE("/sessions", h.DeleteAllSessions)
 			securedAuth.GET("/sessions", h.GetSessions)
 			securedAuth.GET("/me", h.Me)
 			securedAuth.PATCH("/user", h.UpdateUser)
+			securedAuth.PUT("/username", h.ChangeUsername)
+		}
+
+		securedAudit := r.Group("/audit")
+		securedAudit.Use(AuthMiddleware(s))
+		{
+			securedAudit.GET("/events", h.GetAuditEvents)
 		}
 	}
 
@@ -1,8 +1,14 @@
 package transportgrpc
 
 import (
+	dbErrors "auth-service/internal/errors"
+	"auth-service/internal/usecase"
 	"context"
+	"errors"
 	"github.com/ZoyaDenisova/go-common/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
 
 	authpb "auth-service/cmd/app/docs/proto"
 )
@@ -10,11 +16,13 @@ import (
 type AuthServer struct {
 	authpb.UnimplementedAuthServiceServer
 	logger logger.Interface
+	users  usecase.User
 }
 
-func NewAuthServer(logger logger.Interface) *AuthServer {
+func NewAuthServer(logger logger.Interface, users usecase.User) *AuthServer {
 	return &AuthServer{
 		logger: logger,
+		users:  users,
 	}
 }
 
@@ -36,3 +44,74 @@ func (s *AuthServer) VerifyToken(
 	s.logger.Info("VerifyToken successful", "userID", userID, "role", role)
 	return resp, nil
 }
+
+// SetUserRole меняет роль пользователя от имени вызывающего (нужно право role.manage)
+func (s *AuthServer) SetUserRole(
+	ctx context.Context,
+	req *authpb.SetUserRoleRequest,
+) (*authpb.SetUserRoleResponse, error) {
+	s.logger.Info("SetUserRole called", "userID", req.GetUserId(), "role", req.GetRole())
+
+	if err := s.users.SetRole(ctx, req.GetUserId(), req.GetRole()); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrForbidden):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		case errors.Is(err, usecase.ErrInvalidRole), errors.Is(err, usecase.ErrSelfRoleChange):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, dbErrors.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		default:
+			s.logger.Error("SetUserRole failed", "err", err)
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &authpb.SetUserRoleResponse{UserId: req.GetUserId(), Role: req.GetRole()}, nil
+}
+
+// BlockUser блокирует пользователя от имени вызывающего (нужно право user.block)
+func (s *AuthServer) BlockUser(
+	ctx context.Context,
+	req *authpb.BlockUserRequest,
+) (*authpb.BlockUserResponse, error) {
+	s.logger.Info("BlockUser called", "userID", req.GetUserId())
+
+	if err := s.users.Block(ctx, req.GetUserId(), usecase.BlockParams{
+		Reason:   req.GetReason(),
+		Duration: time.Duration(req.GetDurationSeconds()) * time.Second,
+	}); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrForbidden):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		case errors.Is(err, usecase.ErrInvalidBlock):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, dbErrors.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		default:
+			s.logger.Error("BlockUser failed", "err", err)
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &authpb.BlockUserResponse{UserId: req.GetUserId()}, nil
+}
+
+// ResolveUsernames возвращает id пользователей по именам (для упоминаний в чате)
+func (s *AuthServer) ResolveUsernames(
+	ctx context.Context,
+	req *authpb.ResolveUsernamesRequest,
+) (*authpb.ResolveUsernamesResponse, error) {
+	s.logger.Info("ResolveUsernames called", "count", len(req.GetUsernames()))
+
+	users, err := s.users.ResolveUsernames(ctx, req.GetUsernames())
+	if err != nil {
+		s.logger.Error("ResolveUsernames failed", "err", err)
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	resp := &authpb.ResolveUsernamesResponse{Users: make([]*authpb.ResolvedUser, 0, len(users))}
+	for _, u := range users {
+		resp.Users = append(resp.Users, &authpb.ResolvedUser{Id: u.ID, Username: u.Username})
+	}
+	return resp, nil
+}
@@ -1,27 +1,30 @@
 package transportgrpc
 
 import (
+	authctx "auth-service/internal/auth"
+	"auth-service/internal/usecase"
 	"context"
 	"github.com/ZoyaDenisova/go-common/contextkeys"
 	"github.com/ZoyaDenisova/go-common/logger"
+	"net"
 	"strings"
 
-	"github.com/ZoyaDenisova/go-common/jwt"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
 type AuthInterceptor struct {
-	tokens jwt.TokenManager
-	logger logger.Interface
+	sessions usecase.Session
+	logger   logger.Interface
 }
 
-func NewAuthInterceptor(tokens jwt.TokenManager, log logger.Interface) *AuthInterceptor {
+func NewAuthInterceptor(sessions usecase.Session, log logger.Interface) *AuthInterceptor {
 	return &AuthInterceptor{
-		tokens: tokens,
-		logger: log,
+		sessions: sessions,
+		logger:   log,
 	}
 }
 
@@ -56,7 +59,8 @@ func (i *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 		}
 
 		token := strings.TrimPrefix(auth, "Bearer ")
-		userID, role, err := i.tokens.ValidateAccess(token)
+		// роль сверяется с базой, так что смена роли и блокировка действуют сразу
+		userID, role, err := i.sessions.VerifyAccess(ctx, token)
 		if err != nil {
 			i.logger.Error("token validation failed: %v", err)
 			return nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
@@ -66,11 +70,31 @@ func (i *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 
 		newCtx := context.WithValue(ctx, contextkeys.UserIDKey{}, userID)
 		newCtx = context.WithValue(newCtx, contextkeys.RoleKey{}, role)
+		newCtx = authctx.WithClient(newCtx, peerIP(ctx), firstValue(md, "user-agent"))
 
 		return handler(newCtx, req)
 	}
 }
 
+// peerIP — адрес вызывающего сервиса (без порта)
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if vals := md.Get(key); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
 func FromContext(ctx context.Context) (userID int64, role string) {
 	if v, ok := ctx.Value(contextkeys.UserIDKey{}).(int64); ok {
 		userID = v
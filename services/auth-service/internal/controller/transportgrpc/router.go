@@ -2,15 +2,15 @@ package transportgrpc
 
 import (
 	authpb "auth-service/cmd/app/docs/proto"
-	"github.com/ZoyaDenisova/go-common/jwt"
+	"auth-service/internal/usecase"
 	"github.com/ZoyaDenisova/go-common/logger"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 )
 
-func NewRouter(log logger.Interface, tm jwt.TokenManager) *grpc.Server {
+func NewRouter(log logger.Interface, u usecase.User, s usecase.Session) *grpc.Server {
 	// UnaryInterceptor для аутентификации (из interceptor.go)
-	authInterceptor := NewAuthInterceptor(tm, log)
+	authInterceptor := NewAuthInterceptor(s, log)
 
 	opts := []grpc.ServerOption{
 		grpc.UnaryInterceptor(authInterceptor.Unary()),
@@ -18,7 +18,7 @@ func NewRouter(log logger.Interface, tm jwt.TokenManager) *grpc.Server {
 
 	srv := grpc.NewServer(opts...)
 
-	authpb.RegisterAuthServiceServer(srv, NewAuthServer(log))
+	authpb.RegisterAuthServiceServer(srv, NewAuthServer(log, u))
 
 	reflection.Register(srv)
 
@@ -0,0 +1,47 @@
+package cron
+
+import (
+	"context"
+	"time"
+
+	"auth-service/internal/usecase"
+	"github.com/ZoyaDenisova/go-common/logger"
+	"github.com/robfig/cron/v3"
+)
+
+type AuditCleanupCron struct {
+	log logger.Interface
+	uc  usecase.Audit
+}
+
+func NewAuditCleanupCron(log logger.Interface, uc usecase.Audit) *AuditCleanupCron {
+	return &AuditCleanupCron{
+		log: log,
+		uc:  uc,
+	}
+}
+
+func (c *AuditCleanupCron) Start(schedule string) error {
+	cronScheduler := cron.New()
+
+	_, err := cronScheduler.AddFunc(schedule, func() {
+		c.log.Info("cron: starting audit cleanup")
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := c.uc.DeleteExpired(ctx); err != nil {
+			c.log.Error("cron: audit cleanup failed", "err", err)
+		} else {
+			c.log.Info("cron: audit cleanup completed")
+		}
+	})
+	if err != nil {
+		c.log.Error("failed to register audit cleanup cron job", "err", err)
+		return err
+	}
+
+	c.log.Info("cron: audit cleanup job scheduled", "schedule", schedule)
+	cronScheduler.Start()
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package cron
+
+import (
+	"context"
+	"time"
+
+	"auth-service/internal/usecase"
+	"github.com/ZoyaDenisova/go-common/logger"
+	"github.com/robfig/cron/v3"
+)
+
+// BlockExpiryCron снимает временные блокировки, срок которых истёк
+type BlockExpiryCron struct {
+	log logger.Interface
+	uc  usecase.User
+}
+
+func NewBlockExpiryCron(log logger.Interface, uc usecase.User) *BlockExpiryCron {
+	return &BlockExpiryCron{
+		log: log,
+		uc:  uc,
+	}
+}
+
+func (c *BlockExpiryCron) Start(schedule string) error {
+	cronScheduler := cron.New()
+
+	_, err := cronScheduler.AddFunc(schedule, func() {
+		c.log.Info("cron: lifting expired blocks")
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := c.uc.UnblockExpired(ctx); err != nil {
+			c.log.Error("cron: block expiry failed", "err", err)
+		} else {
+			c.log.Info("cron: block expiry completed")
+		}
+	})
+	if err != nil {
+		c.log.Error("failed to register block expiry cron job", "err", err)
+		return err
+	}
+
+	c.log.Info("cron: block expiry job scheduled", "schedule", schedule)
+	cronScheduler.Start()
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package entity
+
+import "time"
+
+// Действия, которые пишутся в audit_events
+const (
+	AuditUserRegistered           = "user.registered"
+	AuditUserBlocked              = "user.blocked"
+	AuditUserUnblocked            = "user.unblocked"
+	AuditPasswordChanged          = "user.password_changed"
+	AuditEmailChanged             = "user.email_changed"
+	AuditUsernameChanged          = "user.username_changed"
+	AuditLogin                    = "auth.login"
+	AuditLoginFailed              = "auth.login_failed"
+	AuditRefresh                  = "auth.refresh"
+	AuditSessionRevoked           = "session.revoked"
+	AuditSessionsRevokedAll       = "session.revoked_all"
+	AuditRoleChanged              = "role.changed"
+	AuditModeratorCategoryGranted = "moderator.category_granted"
+	AuditModeratorCategoryRevoked = "moderator.category_revoked"
+)
+
+type AuditEvent struct {
+	ID        int64
+	ActorID   *int64 // nil — система или аноним
+	TargetID  *int64
+	Action    string
+	IP        string
+	UserAgent string
+	Metadata  map[string]any
+	CreatedAt time.Time
+}
+
+// AuditFilter — условия выборки журнала; нулевые поля не фильтруют
+type AuditFilter struct {
+	ActorID  int64
+	TargetID int64
+	Action   string
+	IP       string
+	From     time.Time
+	To       time.Time
+	Limit    int
+	Offset   int
+}
@@ -4,10 +4,21 @@ import "time"
 
 type User struct {
 	ID           int64
-	Name         string
+	Username     string // уникальный без учёта регистра адрес для упоминаний и ссылок
+	Name         string // отображаемое имя, может повторяться
 	Email        string
 	PasswordHash string // bcrypt-хэш
 	Role         string
 	IsBlocked    bool
+	BlockedUntil *time.Time // nil — блокировка бессрочная
+	BlockReason  string
 	CreatedAt    time.Time
+	// UsernameChangedAt — последняя смена username; nil — не менялся с регистрации
+	UsernameChangedAt *time.Time
+}
+
+// BlockedAt сообщает, действует ли блокировка в момент now. Истёкшая временная
+// блокировка не мешает входу ещё до того, как cron снимет флаг.
+func (u *User) BlockedAt(now time.Time) bool {
+	return u.IsBlocked && (u.BlockedUntil == nil || now.Before(*u.BlockedUntil))
 }
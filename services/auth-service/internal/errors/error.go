@@ -11,4 +11,7 @@ var (
 
 	// ErrConflict возвращается при попытке создать ресурс, который уже существует (уникальные поля нарушены).
 	ErrConflict = errors.New("conflict")
+
+	// ErrInvalidReference возвращается, когда запись ссылается на несуществующую (нарушен внешний ключ).
+	ErrInvalidReference = errors.New("invalid reference")
 )
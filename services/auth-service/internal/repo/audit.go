@@ -0,0 +1,98 @@
+package repo
+
+import (
+	"auth-service/internal/entity"
+	"context"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/postgres"
+	"time"
+)
+
+type AuditRepoPostgres struct {
+	*postgres.Postgres
+}
+
+func NewAuditRepo(pg *postgres.Postgres) *AuditRepoPostgres {
+	return &AuditRepoPostgres{pg}
+}
+
+// Save пишет событие; пустые IP и User-Agent сохраняются как NULL
+func (r *AuditRepoPostgres) Save(ctx context.Context, e *entity.AuditEvent) error {
+	const op = "AuditRepo.Save"
+	const query = `
+        INSERT INTO audit_events (actor_id, target_id, action, ip, user_agent, metadata)
+        VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6)
+        RETURNING id, created_at
+    `
+	meta := e.Metadata
+	if meta == nil {
+		meta = map[string]any{}
+	}
+	if err := r.Pool.QueryRow(ctx, query,
+		e.ActorID, e.TargetID, e.Action, e.IP, e.UserAgent, meta).
+		Scan(&e.ID, &e.CreatedAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// List возвращает события по фильтру (новые сверху) и общее число подходящих событий
+func (r *AuditRepoPostgres) List(ctx context.Context, f entity.AuditFilter) ([]*entity.AuditEvent, int64, error) {
+	const op = "AuditRepo.List"
+	const query = `
+        SELECT id, actor_id, target_id, action, COALESCE(ip, ''), COALESCE(user_agent, ''),
+               metadata, created_at, count(*) OVER () AS total
+        FROM audit_events
+        WHERE ($1 = 0 OR actor_id = $1)
+          AND ($2 = 0 OR target_id = $2)
+          AND ($3 = '' OR action = $3)
+          AND ($4 = '' OR ip = $4)
+          AND ($5::timestamptz IS NULL OR created_at >= $5)
+          AND ($6::timestamptz IS NULL OR created_at < $6)
+        ORDER BY created_at DESC, id DESC
+        LIMIT $7 OFFSET $8
+    `
+	rows, err := r.Pool.Query(ctx, query,
+		f.ActorID, f.TargetID, f.Action, f.IP, nullTime(f.From), nullTime(f.To), f.Limit, f.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	var (
+		events = make([]*entity.AuditEvent, 0)
+		total  int64
+	)
+	for rows.Next() {
+		var e entity.AuditEvent
+		if err := rows.Scan(&e.ID, &e.ActorID, &e.TargetID, &e.Action, &e.IP, &e.UserAgent,
+			&e.Metadata, &e.CreatedAt, &total); err != nil {
+			return nil, 0, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		events = append(events, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return events, total, nil
+}
+
+// DeleteOlderThan удаляет события, записанные раньше before
+func (r *AuditRepoPostgres) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	const op = "AuditRepo.DeleteOlderThan"
+	const query = `DELETE FROM audit_events WHERE created_at < $1`
+
+	tag, err := r.Pool.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("%s: exec: %w", op, err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// nullTime — нулевое время передаётся в запрос как NULL
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
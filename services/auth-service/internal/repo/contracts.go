@@ -3,6 +3,7 @@ package repo
 import (
 	"auth-service/internal/entity"
 	"context"
+	"time"
 )
 
 // todo запускать DeleteExpired как cron-задачу или из периодического фонового воркера
@@ -12,10 +13,37 @@ type (
 		Update(ctx context.Context, u *entity.User) error
 		GetByID(ctx context.Context, userID int64) (*entity.User, error)
 		GetByEmail(ctx context.Context, email string) (*entity.User, error)
+		// GetByUsername ищет по username без учёта регистра
 		GetByUsername(ctx context.Context, username string) (*entity.User, error)
+		// UsernameAvailable — username никто другой не носит и не держит в резерве; userID = 0 для нового пользователя
+		UsernameAvailable(ctx context.Context, username string, userID int64) (bool, error)
+		// ChangeUsername меняет username, резервируя прежний за владельцем до reserveUntil; занят — errors.ErrConflict
+		ChangeUsername(ctx context.Context, userID int64, username string, reserveUntil time.Time) (string, error)
 		GetAll(ctx context.Context) ([]*entity.User, error)
 		Unblock(ctx context.Context, id int64) error
-		Block(ctx context.Context, id int64) error
+		// Block блокирует пользователя до until; nil — бессрочно.
+		Block(ctx context.Context, id int64, until *time.Time, reason string) error
+		// UnblockExpired снимает истёкшие временные блокировки и возвращает ID разблокированных.
+		UnblockExpired(ctx context.Context, now time.Time) ([]int64, error)
+		// SetRole меняет роль пользователя и возвращает прежнюю.
+		SetRole(ctx context.Context, id int64, role string) (string, error)
+	}
+	ModeratorRepo interface {
+		// AddCategory назначает модератора на категорию; несуществующая категория — ErrInvalidReference.
+		AddCategory(ctx context.Context, userID, categoryID, grantedBy int64) error
+		// RemoveCategory снимает модератора с категории.
+		RemoveCategory(ctx context.Context, userID, categoryID int64) error
+		// RemoveAll снимает модератора со всех категорий (при смене роли).
+		RemoveAll(ctx context.Context, userID int64) error
+		// ListCategories возвращает ID категорий, назначенных модератору.
+		ListCategories(ctx context.Context, userID int64) ([]int64, error)
+	}
+	AuditRepo interface {
+		Save(ctx context.Context, e *entity.AuditEvent) error
+		// List возвращает страницу событий по фильтру и общее число подходящих.
+		List(ctx context.Context, f entity.AuditFilter) ([]*entity.AuditEvent, int64, error)
+		// DeleteOlderThan удаляет события старше before (для периодической очистки).
+		DeleteOlderThan(ctx context.Context, before time.Time) (int64, error)
 	}
 	SessionRepo interface {
 		Save(ctx context.Context, s *entity.Session) error
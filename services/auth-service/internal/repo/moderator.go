@@ -0,0 +1,83 @@
+package repo
+
+import (
+	"auth-service/internal/errors"
+	"context"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/postgres"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type ModeratorRepoPostgres struct {
+	*postgres.Postgres
+}
+
+func NewModeratorRepo(pg *postgres.Postgres) *ModeratorRepoPostgres {
+	return &ModeratorRepoPostgres{pg}
+}
+
+// AddCategory идемпотентна: повторное назначение ничего не меняет
+func (r *ModeratorRepoPostgres) AddCategory(ctx context.Context, userID, categoryID, grantedBy int64) error {
+	const op = "ModeratorRepo.AddCategory"
+	const query = `
+        INSERT INTO category_moderators (user_id, category_id, granted_by)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (user_id, category_id) DO NOTHING
+    `
+	if _, err := r.Pool.Exec(ctx, query, userID, categoryID, grantedBy); err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			return fmt.Errorf("%s: %w", op, errors.ErrInvalidReference)
+		}
+		return fmt.Errorf("%s: exec: %w", op, err)
+	}
+	return nil
+}
+
+func (r *ModeratorRepoPostgres) RemoveCategory(ctx context.Context, userID, categoryID int64) error {
+	const op = "ModeratorRepo.RemoveCategory"
+	const query = `DELETE FROM category_moderators WHERE user_id = $1 AND category_id = $2`
+
+	tag, err := r.Pool.Exec(ctx, query, userID, categoryID)
+	if err != nil {
+		return fmt.Errorf("%s: exec: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *ModeratorRepoPostgres) RemoveAll(ctx context.Context, userID int64) error {
+	const op = "ModeratorRepo.RemoveAll"
+	const query = `DELETE FROM category_moderators WHERE user_id = $1`
+
+	if _, err := r.Pool.Exec(ctx, query, userID); err != nil {
+		return fmt.Errorf("%s: exec: %w", op, err)
+	}
+	return nil
+}
+
+func (r *ModeratorRepoPostgres) ListCategories(ctx context.Context, userID int64) ([]int64, error) {
+	const op = "ModeratorRepo.ListCategories"
+	const query = `
+        SELECT category_id
+        FROM category_moderators
+        WHERE user_id = $1
+        ORDER BY category_id
+    `
+	rows, err := r.Pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"github.com/ZoyaDenisova/go-common/postgres"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"strings"
+	"time"
 )
 
 type UserRepoPostgres struct {
@@ -17,16 +20,23 @@ func NewUserRepo(pg *postgres.Postgres) *UserRepoPostgres {
 	return &UserRepoPostgres{pg}
 }
 
-// Create сохраняет нового пользователя.
+// Create сохраняет нового пользователя. Email или username уже заняты — errors.ErrConflict.
 func (r *UserRepoPostgres) Create(ctx context.Context, u *entity.User) error {
+	const op = "UserRepo.Create"
 	const query = `
-        INSERT INTO users (name, email, password_hash, role, is_blocked, created_at)
-        VALUES ($1, $2, $3, $4, $5, $6)
+        INSERT INTO users (username, name, email, password_hash, role, is_blocked, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
         RETURNING id
     `
-	return r.Pool.QueryRow(ctx, query,
-		u.Name, u.Email, u.PasswordHash, u.Role, u.IsBlocked, u.CreatedAt).
+	err := r.Pool.QueryRow(ctx, query,
+		u.Username, u.Name, u.Email, u.PasswordHash, u.Role, u.IsBlocked, u.CreatedAt).
 		Scan(&u.ID)
+	if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+		return fmt.Errorf("%s: %w", op, errors.ErrConflict)
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
 }
 
 // Update изменяет всё, включая флаг блокировки.
@@ -55,19 +65,23 @@ func (r *UserRepoPostgres) Update(ctx context.Context, u *entity.User) error {
 func scanUser(row pgx.Row, u *entity.User) error {
 	return row.Scan(
 		&u.ID,
+		&u.Username,
 		&u.Name,
 		&u.Email,
 		&u.PasswordHash,
 		&u.Role,
 		&u.IsBlocked,
+		&u.BlockedUntil,
+		&u.BlockReason,
 		&u.CreatedAt,
+		&u.UsernameChangedAt,
 	)
 }
 
 func (r *UserRepoPostgres) GetByID(ctx context.Context, id int64) (*entity.User, error) {
 	const op = "UserRepo.GetByID"
 	const query = `
-        SELECT id, name, email, password_hash, role, is_blocked, created_at
+        SELECT id, username, name, email, password_hash, role, is_blocked, blocked_until, block_reason, created_at, username_changed_at
         FROM users
         WHERE id = $1
     `
@@ -84,7 +98,7 @@ func (r *UserRepoPostgres) GetByID(ctx context.Context, id int64) (*entity.User,
 func (r *UserRepoPostgres) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
 	const op = "UserRepo.GetByEmail"
 	const query = `
-        SELECT id, name, email, password_hash, role, is_blocked, created_at
+        SELECT id, username, name, email, password_hash, role, is_blocked, blocked_until, block_reason, created_at, username_changed_at
         FROM users
         WHERE email = $1
     `
@@ -98,12 +112,13 @@ func (r *UserRepoPostgres) GetByEmail(ctx context.Context, email string) (*entit
 	return &u, nil
 }
 
+// GetByUsername ищет пользователя по username без учёта регистра
 func (r *UserRepoPostgres) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
 	const op = "UserRepo.GetByUsername"
 	const query = `
-        SELECT id, name, email, password_hash, role, is_blocked, created_at
+        SELECT id, username, name, email, password_hash, role, is_blocked, blocked_until, block_reason, created_at, username_changed_at
         FROM users
-        WHERE name = $1
+        WHERE lower(username) = lower($1)
     `
 	var u entity.User
 	if err := scanUser(r.Pool.QueryRow(ctx, query, username), &u); err != nil {
@@ -117,7 +132,7 @@ func (r *UserRepoPostgres) GetByUsername(ctx context.Context, username string) (
 
 func (r *UserRepoPostgres) GetAll(ctx context.Context) ([]*entity.User, error) {
 	const query = `
-		SELECT id, name, email, password_hash, role, is_blocked, created_at
+		SELECT id, username, name, email, password_hash, role, is_blocked, blocked_until, block_reason, created_at, username_changed_at
 		FROM users
 		ORDER BY id
 	`
@@ -139,11 +154,12 @@ func (r *UserRepoPostgres) GetAll(ctx context.Context) ([]*entity.User, error) {
 	return users, nil
 }
 
+// Unblock снимает блокировку вместе со сроком и причиной.
 func (r *UserRepoPostgres) Unblock(ctx context.Context, id int64) error {
-	const op = "UserRepo.block"
-	const query = `UPDATE users SET is_blocked = $1 WHERE id = $2`
+	const op = "UserRepo.Unblock"
+	const query = `UPDATE users SET is_blocked = FALSE, blocked_until = NULL, block_reason = '' WHERE id = $1`
 
-	tag, err := r.Pool.Exec(ctx, query, false, id)
+	tag, err := r.Pool.Exec(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("%s: exec: %w", op, err)
 	}
@@ -153,11 +169,13 @@ func (r *UserRepoPostgres) Unblock(ctx context.Context, id int64) error {
 	return nil
 }
 
-func (r *UserRepoPostgres) Block(ctx context.Context, id int64) error {
-	const op = "UserRepo.block"
-	const query = `UPDATE users SET is_blocked = $1 WHERE id = $2`
+// Block блокирует пользователя до until (nil — бессрочно). Повторная блокировка
+// перезаписывает срок и причину.
+func (r *UserRepoPostgres) Block(ctx context.Context, id int64, until *time.Time, reason string) error {
+	const op = "UserRepo.Block"
+	const query = `UPDATE users SET is_blocked = TRUE, blocked_until = $1, block_reason = $2 WHERE id = $3`
 
-	tag, err := r.Pool.Exec(ctx, query, true, id)
+	tag, err := r.Pool.Exec(ctx, query, until, reason, id)
 	if err != nil {
 		return fmt.Errorf("%s: exec: %w", op, err)
 	}
@@ -166,3 +184,134 @@ func (r *UserRepoPostgres) Block(ctx context.Context, id int64) error {
 	}
 	return nil
 }
+
+// UnblockExpired снимает временные блокировки, срок которых истёк к now, и возвращает ID разблокированных.
+func (r *UserRepoPostgres) UnblockExpired(ctx context.Context, now time.Time) ([]int64, error) {
+	const op = "UserRepo.UnblockExpired"
+	const query = `
+        UPDATE users
+        SET is_blocked = FALSE, blocked_until = NULL, block_reason = ''
+        WHERE is_blocked AND blocked_until IS NOT NULL AND blocked_until <= $1
+        RETURNING id
+    `
+	rows, err := r.Pool.Query(ctx, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return ids, nil
+}
+
+// SetRole меняет роль под блокировкой строки, чтобы прежняя роль в журнале была точной
+func (r *UserRepoPostgres) SetRole(ctx context.Context, id int64, role string) (string, error) {
+	const op = "UserRepo.SetRole"
+	const query = `
+        UPDATE users u
+        SET role = $1
+        FROM (SELECT id, role FROM users WHERE id = $2 FOR UPDATE) old
+        WHERE u.id = old.id
+        RETURNING old.role
+    `
+	var prev string
+	if err := r.Pool.QueryRow(ctx, query, role, id).Scan(&prev); err != nil {
+		if err == pgx.ErrNoRows {
+			return "", fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	return prev, nil
+}
+
+// UsernameAvailable сообщает, свободен ли username для userID: его не носит другой пользователь
+// и он не зарезервирован за другим пользователем после переименования. userID = 0 — для нового пользователя.
+func (r *UserRepoPostgres) UsernameAvailable(ctx context.Context, username string, userID int64) (bool, error) {
+	const op = "UserRepo.UsernameAvailable"
+	const query = `
+        SELECT NOT EXISTS (SELECT 1 FROM users WHERE lower(username) = lower($1) AND id <> $2)
+           AND NOT EXISTS (SELECT 1
+                           FROM username_reservations
+                           WHERE lower(username) = lower($1) AND user_id <> $2 AND reserved_until > now())
+    `
+	var ok bool
+	if err := r.Pool.QueryRow(ctx, query, username, userID).Scan(&ok); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return ok, nil
+}
+
+// ChangeUsername меняет username и резервирует прежний за владельцем до reserveUntil.
+// Новый адрес занят или зарезервирован другим — errors.ErrConflict; свою же прежнюю резервацию можно занять снова.
+// Смена только регистра ничего не резервирует. Возвращает прежний username.
+func (r *UserRepoPostgres) ChangeUsername(ctx context.Context, userID int64, username string, reserveUntil time.Time) (string, error) {
+	const op = "UserRepo.ChangeUsername"
+	const selectQuery = `SELECT username FROM users WHERE id = $1 FOR UPDATE`
+	const reservedQuery = `
+        SELECT EXISTS (SELECT 1
+                       FROM username_reservations
+                       WHERE lower(username) = lower($1) AND user_id <> $2 AND reserved_until > now())
+    `
+	const updateQuery = `UPDATE users SET username = $1, username_changed_at = now() WHERE id = $2`
+	// резервация нового адреса — своя или истёкшая чужая — больше не нужна
+	const releaseQuery = `DELETE FROM username_reservations WHERE lower(username) = lower($1)`
+	const reserveQuery = `
+        INSERT INTO username_reservations (username, user_id, reserved_until)
+        VALUES ($1, $2, $3)
+        ON CONFLICT ((lower(username))) DO UPDATE
+            SET username = EXCLUDED.username, user_id = EXCLUDED.user_id,
+                reserved_until = EXCLUDED.reserved_until, created_at = now()
+    `
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%s: begin: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }() // после Commit откат ничего не делает
+
+	var previous string
+	if err := tx.QueryRow(ctx, selectQuery, userID).Scan(&previous); err != nil {
+		if err == pgx.ErrNoRows {
+			return "", fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+		}
+		return "", fmt.Errorf("%s: select: %w", op, err)
+	}
+
+	var reserved bool
+	if err := tx.QueryRow(ctx, reservedQuery, username, userID).Scan(&reserved); err != nil {
+		return "", fmt.Errorf("%s: reserved: %w", op, err)
+	}
+	if reserved {
+		return "", fmt.Errorf("%s: %w", op, errors.ErrConflict)
+	}
+
+	if _, err := tx.Exec(ctx, updateQuery, username, userID); err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return "", fmt.Errorf("%s: %w", op, errors.ErrConflict)
+		}
+		return "", fmt.Errorf("%s: update: %w", op, err)
+	}
+	if _, err := tx.Exec(ctx, releaseQuery, username); err != nil {
+		return "", fmt.Errorf("%s: release: %w", op, err)
+	}
+	if !strings.EqualFold(previous, username) {
+		if _, err := tx.Exec(ctx, reserveQuery, previous, userID, reserveUntil); err != nil {
+			return "", fmt.Errorf("%s: reserve: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return previous, nil
+}
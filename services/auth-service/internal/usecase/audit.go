@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"auth-service/internal/auth"
+	"auth-service/internal/entity"
+	"auth-service/internal/repo"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ZoyaDenisova/go-common/logger"
+)
+
+// Границы страницы журнала аудита
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 200
+)
+
+// auditor пишет события в журнал аудита; общий для user- и session-usecase.
+// Сбой записи не отменяет само действие — он логируется.
+type auditor struct {
+	repo repo.AuditRepo
+	log  logger.Interface
+}
+
+// record сохраняет событие. actorID = 0 — инициатор берётся из контекста (для публичных
+// ручек вроде login его там нет, и его передают явно). IP и User-Agent — из контекста.
+func (a auditor) record(ctx context.Context, action string, actorID, targetID int64, meta map[string]any) {
+	if a.repo == nil {
+		return
+	}
+
+	e := &entity.AuditEvent{
+		Action:   action,
+		Metadata: meta,
+	}
+	if actorID == 0 {
+		actorID, _ = auth.FromContext(ctx)
+	}
+	if actorID != 0 {
+		e.ActorID = &actorID
+	}
+	if targetID != 0 {
+		e.TargetID = &targetID
+	}
+	client := auth.ClientFromContext(ctx)
+	e.IP, e.UserAgent = client.IP, client.UserAgent
+
+	if err := a.repo.Save(ctx, e); err != nil {
+		a.log.Error("audit event not saved", "action", action, "targetID", targetID, "err", err)
+	}
+}
+
+type AuditUsecase struct {
+	auditRepo repo.AuditRepo
+	retention time.Duration
+	log       logger.Interface
+}
+
+func NewAuditUsecase(auditRepo repo.AuditRepo, retention time.Duration, log logger.Interface) *AuditUsecase {
+	return &AuditUsecase{
+		auditRepo: auditRepo,
+		retention: retention,
+		log:       log,
+	}
+}
+
+// List отдаёт страницу журнала по фильтру (только для админов).
+func (uc *AuditUsecase) List(ctx context.Context, f entity.AuditFilter) ([]*entity.AuditEvent, int64, error) {
+	if uid, role := auth.FromContext(ctx); !auth.Can(role, auth.PermAuditView) {
+		uc.log.Warn("non‑admin tried to read audit log", "initiator", uid)
+		return nil, 0, ErrForbidden
+	}
+
+	if f.Limit <= 0 {
+		f.Limit = defaultAuditLimit
+	} else if f.Limit > maxAuditLimit {
+		f.Limit = maxAuditLimit
+	}
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+
+	events, total, err := uc.auditRepo.List(ctx, f)
+	if err != nil {
+		uc.log.Error("audit list failed", "err", err)
+		return nil, 0, fmt.Errorf("AuditUsecase.List: %w", err)
+	}
+	return events, total, nil
+}
+
+// DeleteExpired удаляет события старше срока хранения; вызывается из cron.
+func (uc *AuditUsecase) DeleteExpired(ctx context.Context) error {
+	uc.log.Debug("audit.DeleteExpired called")
+
+	if uc.retention <= 0 {
+		uc.log.Info("audit retention disabled, nothing to delete")
+		return nil
+	}
+
+	before := time.Now().UTC().Add(-uc.retention)
+	n, err := uc.auditRepo.DeleteOlderThan(ctx, before)
+	if err != nil {
+		uc.log.Error("failed to delete expired audit events", "err", err)
+		return fmt.Errorf("AuditUsecase.DeleteExpired: %w", err)
+	}
+
+	uc.log.Info("expired audit events deleted", "count", n, "before", before)
+	return nil
+}
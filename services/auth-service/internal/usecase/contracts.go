@@ -7,19 +7,34 @@ import (
 
 type (
 	User interface {
-		Register(ctx context.Context, name, email, password string) error
+		Register(ctx context.Context, username, name, email, password string) error
+		// CheckUsername возвращает username в сохраняемом виде или причину, по которой его нельзя занять
+		CheckUsername(ctx context.Context, username string) (string, error)
+		ChangeUsername(ctx context.Context, id int64, username string) error
 		Update(ctx context.Context, id int64, params UpdateUserParams) error
 		Login(ctx context.Context, email, password, ua string) (string, string, error)
 		GetByID(ctx context.Context, id int64) (*entity.User, error)
+		ResolveUsernames(ctx context.Context, names []string) ([]*entity.User, error)
 		Unblock(ctx context.Context, targetID int64) error
-		Block(ctx context.Context, targetID int64) error
+		Block(ctx context.Context, targetID int64, p BlockParams) error
+		UnblockExpired(ctx context.Context) error
 		GetAll(ctx context.Context) ([]*entity.User, error)
+		SetRole(ctx context.Context, targetID int64, role string) error
+		RevokeRole(ctx context.Context, targetID int64) error
+		GrantModeratorCategory(ctx context.Context, targetID, categoryID int64) error
+		RevokeModeratorCategory(ctx context.Context, targetID, categoryID int64) error
+		ListModeratorCategories(ctx context.Context, targetID int64) ([]int64, error)
 	}
 	Session interface {
 		Refresh(ctx context.Context, oldToken string) (string, string, error)
+		VerifyAccess(ctx context.Context, token string) (int64, string, error)
 		List(ctx context.Context, userID int64) ([]entity.Session, error)
 		Revoke(ctx context.Context, token string) error
 		RevokeAll(ctx context.Context, userID int64) error
 		DeleteExpired(ctx context.Context) error
 	}
+	Audit interface {
+		List(ctx context.Context, f entity.AuditFilter) ([]*entity.AuditEvent, int64, error)
+		DeleteExpired(ctx context.Context) error
+	}
 )
@@ -1,7 +1,15 @@
 package usecase
 
+import "time"
+
 type UpdateUserParams struct {
 	Name     *string
 	Email    *string
 	Password *string
 }
+
+// BlockParams — параметры блокировки; Duration = 0 — бессрочно
+type BlockParams struct {
+	Reason   string
+	Duration time.Duration
+}
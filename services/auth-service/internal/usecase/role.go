@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"auth-service/internal/auth"
+	"auth-service/internal/entity"
+	dbErrors "auth-service/internal/errors"
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidRole      = errors.New("invalid role: must be user, moderator or admin")
+	ErrSelfRoleChange   = errors.New("admins cannot change their own role")
+	ErrNotModerator     = errors.New("user is not a moderator")
+	ErrCategoryNotFound = errors.New("category not found")
+)
+
+// SetRole выдаёт пользователю роль (только с правом role.manage).
+// При уходе с роли moderator назначения на категории снимаются.
+// Выданные ранее access-токены со старой ролью перестают проходить проверку (см. SessionUsecase.VerifyAccess),
+// а refresh выдаёт токены уже с новой ролью. Каждая смена пишется в журнал аудита.
+func (uc *UserUsecase) SetRole(ctx context.Context, targetID int64, role string) error {
+	uc.log.Debug("SetRole called", "targetID", targetID, "role", role)
+
+	uid, initiatorRole := auth.FromContext(ctx)
+	if !auth.Can(initiatorRole, auth.PermRoleManage) {
+		uc.log.Warn("non‑admin tried to change role", "initiator", uid)
+		return ErrForbidden
+	}
+	if !auth.ValidRole(role) {
+		return ErrInvalidRole
+	}
+	if uid == targetID {
+		uc.log.Warn("admin tried to change own role", "initiator", uid)
+		return ErrSelfRoleChange
+	}
+
+	prev, err := uc.userRepo.SetRole(ctx, targetID, role)
+	if err != nil {
+		uc.log.Error("set role failed", "err", err)
+		return fmt.Errorf("user.SetRole: %w", err)
+	}
+	if prev == role {
+		uc.log.Info("user role unchanged", "targetID", targetID, "role", role)
+		return nil
+	}
+
+	if role != auth.RoleModerator {
+		if err := uc.modRepo.RemoveAll(ctx, targetID); err != nil {
+			uc.log.Error("moderator categories cleanup failed", "err", err)
+			return fmt.Errorf("user.SetRole: remove categories: %w", err)
+		}
+	}
+
+	uc.audit.record(ctx, entity.AuditRoleChanged, 0, targetID, map[string]any{"from": prev, "to": role})
+	uc.log.Info("user role changed", "targetID", targetID, "from", prev, "to", role, "by", uid)
+	return nil
+}
+
+// RevokeRole возвращает пользователю базовую роль user
+func (uc *UserUsecase) RevokeRole(ctx context.Context, targetID int64) error {
+	return uc.SetRole(ctx, targetID, auth.RoleUser)
+}
+
+// GrantModeratorCategory назначает модератора на категорию; права действуют и в её подкатегориях
+func (uc *UserUsecase) GrantModeratorCategory(ctx context.Context, targetID, categoryID int64) error {
+	uc.log.Debug("GrantModeratorCategory called", "targetID", targetID, "categoryID", categoryID)
+
+	uid, role := auth.FromContext(ctx)
+	if !auth.Can(role, auth.PermRoleManage) {
+		uc.log.Warn("non‑admin tried to grant moderator category", "initiator", uid)
+		return ErrForbidden
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, targetID)
+	if err != nil {
+		uc.log.Error("user lookup failed", "err", err)
+		return fmt.Errorf("user.GrantModeratorCategory: lookup: %w", err)
+	}
+	if user.Role != auth.RoleModerator {
+		uc.log.Warn("category granted to non-moderator", "targetID", targetID, "role", user.Role)
+		return ErrNotModerator
+	}
+
+	if err := uc.modRepo.AddCategory(ctx, targetID, categoryID, uid); err != nil {
+		if errors.Is(err, dbErrors.ErrInvalidReference) {
+			return ErrCategoryNotFound
+		}
+		uc.log.Error("grant moderator category failed", "err", err)
+		return fmt.Errorf("user.GrantModeratorCategory: %w", err)
+	}
+
+	uc.audit.record(ctx, entity.AuditModeratorCategoryGranted, 0, targetID, map[string]any{"category_id": categoryID})
+	uc.log.Info("moderator category granted", "targetID", targetID, "categoryID", categoryID, "by", uid)
+	return nil
+}
+
+// RevokeModeratorCategory снимает модератора с категории
+func (uc *UserUsecase) RevokeModeratorCategory(ctx context.Context, targetID, categoryID int64) error {
+	uc.log.Debug("RevokeModeratorCategory called", "targetID", targetID, "categoryID", categoryID)
+
+	uid, role := auth.FromContext(ctx)
+	if !auth.Can(role, auth.PermRoleManage) {
+		uc.log.Warn("non‑admin tried to revoke moderator category", "initiator", uid)
+		return ErrForbidden
+	}
+
+	if err := uc.modRepo.RemoveCategory(ctx, targetID, categoryID); err != nil {
+		uc.log.Error("revoke moderator category failed", "err", err)
+		return fmt.Errorf("user.RevokeModeratorCategory: %w", err)
+	}
+
+	uc.audit.record(ctx, entity.AuditModeratorCategoryRevoked, 0, targetID, map[string]any{"category_id": categoryID})
+	uc.log.Info("moderator category revoked", "targetID", targetID, "categoryID", categoryID, "by", uid)
+	return nil
+}
+
+// ListModeratorCategories возвращает категории, назначенные модератору
+func (uc *UserUsecase) ListModeratorCategories(ctx context.Context, targetID int64) ([]int64, error) {
+	uid, role := auth.FromContext(ctx)
+	if !auth.Can(role, auth.PermRoleManage) {
+		uc.log.Warn("non‑admin tried to list moderator categories", "initiator", uid)
+		return nil, ErrForbidden
+	}
+
+	ids, err := uc.modRepo.ListCategories(ctx, targetID)
+	if err != nil {
+		uc.log.Error("list moderator categories failed", "err", err)
+		return nil, fmt.Errorf("user.ListModeratorCategories: %w", err)
+	}
+	return ids, nil
+}
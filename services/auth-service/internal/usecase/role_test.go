@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"auth-service/internal/auth"
+	"auth-service/internal/entity"
+	dbErrors "auth-service/internal/errors"
+	"auth-service/internal/usecase/mocks"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_Can(t *testing.T) {
+	admin := []auth.Permission{auth.PermUserList, auth.PermUserBlock, auth.PermRoleManage, auth.PermAuditView}
+
+	cases := []struct {
+		name     string
+		role     string
+		expected bool
+	}{
+		{name: "admin has every right", role: auth.RoleAdmin, expected: true},
+		{name: "moderator has no admin rights", role: auth.RoleModerator},
+		{name: "user has no admin rights", role: auth.RoleUser},
+		{name: "unknown role has nothing", role: "guest"},
+		{name: "anonymous has nothing", role: ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for _, perm := range admin {
+				require.Equal(t, c.expected, auth.Can(c.role, perm), perm)
+			}
+		})
+	}
+
+	t.Run("unknown permission is denied", func(t *testing.T) {
+		require.False(t, auth.Can(auth.RoleAdmin, "user.delete"))
+	})
+}
+
+func TestPolicy_ValidRole(t *testing.T) {
+	cases := map[string]bool{
+		auth.RoleUser:      true,
+		auth.RoleModerator: true,
+		auth.RoleAdmin:     true,
+		"":                 false,
+		"Admin":            false,
+		"guest":            false,
+	}
+	for role, expected := range cases {
+		require.Equal(t, expected, auth.ValidRole(role), role)
+	}
+}
+
+func newRoleUC(users *mocks.MockUserRepo, mods *mocks.MockModeratorRepo, audit *mocks.MockAuditRepo) *UserUsecase {
+	return NewUserUsecase(users, nil, mods, audit, nil, nil, UsernamePolicy{}, mocks.FakeLogger{})
+}
+
+func TestUserUsecase_SetRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	users := mocks.NewMockUserRepo(ctrl)
+	mods := mocks.NewMockModeratorRepo(ctrl)
+	audit := mocks.NewMockAuditRepo(ctrl)
+	uc := newRoleUC(users, mods, audit)
+
+	admin := auth.WithUser(context.Background(), 1, auth.RoleAdmin)
+
+	t.Run("promote to moderator keeps categories and is audited", func(t *testing.T) {
+		users.EXPECT().SetRole(admin, int64(5), auth.RoleModerator).Return(auth.RoleUser, nil)
+		audit.EXPECT().Save(admin, gomock.Any()).DoAndReturn(func(_ context.Context, e *entity.AuditEvent) error {
+			require.Equal(t, entity.AuditRoleChanged, e.Action)
+			require.Equal(t, int64(1), *e.ActorID)
+			require.Equal(t, int64(5), *e.TargetID)
+			require.Equal(t, map[string]any{"from": auth.RoleUser, "to": auth.RoleModerator}, e.Metadata)
+			return nil
+		})
+
+		require.NoError(t, uc.SetRole(admin, 5, auth.RoleModerator))
+	})
+
+	t.Run("demoting moderator drops categories", func(t *testing.T) {
+		users.EXPECT().SetRole(admin, int64(5), auth.RoleUser).Return(auth.RoleModerator, nil)
+		mods.EXPECT().RemoveAll(admin, int64(5)).Return(nil)
+		audit.EXPECT().Save(admin, gomock.Any()).Return(nil)
+
+		require.NoError(t, uc.RevokeRole(admin, 5))
+	})
+
+	t.Run("unchanged role is not audited", func(t *testing.T) {
+		users.EXPECT().SetRole(admin, int64(5), auth.RoleAdmin).Return(auth.RoleAdmin, nil)
+
+		require.NoError(t, uc.SetRole(admin, 5, auth.RoleAdmin))
+	})
+
+	t.Run("audit failure does not undo the change", func(t *testing.T) {
+		users.EXPECT().SetRole(admin, int64(6), auth.RoleAdmin).Return(auth.RoleUser, nil)
+		mods.EXPECT().RemoveAll(admin, int64(6)).Return(nil)
+		audit.EXPECT().Save(admin, gomock.Any()).Return(errors.New("db down"))
+
+		require.NoError(t, uc.SetRole(admin, 6, auth.RoleAdmin))
+	})
+
+	cases := []struct {
+		name     string
+		ctx      context.Context
+		target   int64
+		role     string
+		expected error
+	}{
+		{name: "moderator cannot change roles", ctx: auth.WithUser(context.Background(), 2, auth.RoleModerator), target: 5, role: auth.RoleAdmin, expected: ErrForbidden},
+		{name: "user cannot change roles", ctx: auth.WithUser(context.Background(), 3, auth.RoleUser), target: 5, role: auth.RoleAdmin, expected: ErrForbidden},
+		{name: "anonymous cannot change roles", ctx: context.Background(), target: 5, role: auth.RoleUser, expected: ErrForbidden},
+		{name: "unknown role", ctx: admin, target: 5, role: "owner", expected: ErrInvalidRole},
+		{name: "own role", ctx: admin, target: 1, role: auth.RoleUser, expected: ErrSelfRoleChange},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.ErrorIs(t, uc.SetRole(c.ctx, c.target, c.role), c.expected)
+		})
+	}
+
+	t.Run("repo error", func(t *testing.T) {
+		users.EXPECT().SetRole(admin, int64(9), auth.RoleUser).Return("", dbErrors.ErrNotFound)
+
+		err := uc.SetRole(admin, 9, auth.RoleUser)
+		require.ErrorIs(t, err, dbErrors.ErrNotFound)
+		require.ErrorContains(t, err, "user.SetRole")
+	})
+}
+
+func TestUserUsecase_ModeratorCategories(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	users := mocks.NewMockUserRepo(ctrl)
+	mods := mocks.NewMockModeratorRepo(ctrl)
+	audit := mocks.NewMockAuditRepo(ctrl)
+	uc := newRoleUC(users, mods, audit)
+
+	admin := auth.WithUser(context.Background(), 1, auth.RoleAdmin)
+	moderator := auth.WithUser(context.Background(), 2, auth.RoleModerator)
+
+	t.Run("grant to moderator", func(t *testing.T) {
+		users.EXPECT().GetByID(admin, int64(5)).Return(&entity.User{ID: 5, Role: auth.RoleModerator}, nil)
+		mods.EXPECT().AddCategory(admin, int64(5), int64(10), int64(1)).Return(nil)
+		audit.EXPECT().Save(admin, gomock.Any()).DoAndReturn(func(_ context.Context, e *entity.AuditEvent) error {
+			require.Equal(t, entity.AuditModeratorCategoryGranted, e.Action)
+			require.Equal(t, map[string]any{"category_id": int64(10)}, e.Metadata)
+			return nil
+		})
+
+		require.NoError(t, uc.GrantModeratorCategory(admin, 5, 10))
+	})
+
+	t.Run("grant to non-moderator", func(t *testing.T) {
+		users.EXPECT().GetByID(admin, int64(6)).Return(&entity.User{ID: 6, Role: auth.RoleUser}, nil)
+
+		require.ErrorIs(t, uc.GrantModeratorCategory(admin, 6, 10), ErrNotModerator)
+	})
+
+	t.Run("grant unknown category", func(t *testing.T) {
+		users.EXPECT().GetByID(admin, int64(5)).Return(&entity.User{ID: 5, Role: auth.RoleModerator}, nil)
+		mods.EXPECT().AddCategory(admin, int64(5), int64(99), int64(1)).Return(dbErrors.ErrInvalidReference)
+
+		require.ErrorIs(t, uc.GrantModeratorCategory(admin, 5, 99), ErrCategoryNotFound)
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		mods.EXPECT().RemoveCategory(admin, int64(5), int64(10)).Return(nil)
+		audit.EXPECT().Save(admin, gomock.Any()).DoAndReturn(func(_ context.Context, e *entity.AuditEvent) error {
+			require.Equal(t, entity.AuditModeratorCategoryRevoked, e.Action)
+			return nil
+		})
+
+		require.NoError(t, uc.RevokeModeratorCategory(admin, 5, 10))
+	})
+
+	t.Run("revoke repo error", func(t *testing.T) {
+		mods.EXPECT().RemoveCategory(admin, int64(5), int64(10)).Return(errors.New("db down"))
+
+		require.ErrorContains(t, uc.RevokeModeratorCategory(admin, 5, 10), "user.RevokeModeratorCategory")
+	})
+
+	t.Run("list", func(t *testing.T) {
+		mods.EXPECT().ListCategories(admin, int64(5)).Return([]int64{10, 11}, nil)
+
+		ids, err := uc.ListModeratorCategories(admin, 5)
+		require.NoError(t, err)
+		require.Equal(t, []int64{10, 11}, ids)
+	})
+
+	t.Run("list repo error", func(t *testing.T) {
+		mods.EXPECT().ListCategories(admin, int64(5)).Return(nil, errors.New("db down"))
+
+		_, err := uc.ListModeratorCategories(admin, 5)
+		require.ErrorContains(t, err, "user.ListModeratorCategories")
+	})
+
+	t.Run("only admins manage categories", func(t *testing.T) {
+		require.ErrorIs(t, uc.GrantModeratorCategory(moderator, 5, 10), ErrForbidden)
+		require.ErrorIs(t, uc.RevokeModeratorCategory(moderator, 5, 10), ErrForbidden)
+		_, err := uc.ListModeratorCategories(moderator, 5)
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+}
@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"auth-service/internal/entity"
+	stdErrors "errors"
+
 	"auth-service/internal/errors"
 	"auth-service/internal/repo"
 	"github.com/ZoyaDenisova/go-common/jwt"
@@ -12,9 +14,13 @@ import (
 	"time"
 )
 
+// ErrStaleToken — роль в access-токене расходится с текущей: клиенту нужно обновить токен через refresh
+var ErrStaleToken = stdErrors.New("token is outdated: role has changed, refresh the token")
+
 type SessionUsecase struct {
 	sessionRepo repo.SessionRepo
 	userRepo    repo.UserRepo
+	audit       auditor
 	tokens      jwt.TokenManager
 	log         logger.Interface
 }
@@ -22,12 +28,14 @@ type SessionUsecase struct {
 func NewSessionUsecase(
 	sessionRepo repo.SessionRepo,
 	userRepo repo.UserRepo,
+	auditRepo repo.AuditRepo,
 	tokens jwt.TokenManager,
 	log logger.Interface,
 ) *SessionUsecase {
 	return &SessionUsecase{
 		sessionRepo: sessionRepo,
 		userRepo:    userRepo,
+		audit:       auditor{repo: auditRepo, log: log},
 		tokens:      tokens,
 		log:         log,
 	}
@@ -55,6 +63,21 @@ func (uc *SessionUsecase) Refresh(ctx context.Context, oldToken string) (string,
 		return "", "", fmt.Errorf("session.Refresh - userID mismatch: %w", err)
 	}
 
+	// роль берём из базы, а не из старого токена: после смены роли refresh выдаёт уже новую
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		uc.log.Error("failed to load user for refresh", "err", err)
+		return "", "", fmt.Errorf("session.Refresh - get user: %w", err)
+	}
+	if user.BlockedAt(time.Now()) {
+		uc.log.Warn("blocked user tried to refresh", "userID", userID)
+		return "", "", ErrUserBlocked
+	}
+	if user.Role != role {
+		uc.log.Info("role changed since last refresh", "userID", userID, "from", role, "to", user.Role)
+		role = user.Role
+	}
+
 	// 3) Проверка истечения сессии
 	if time.Now().After(sess.ExpiresAt) {
 		uc.log.Warn("refresh token expired")
@@ -88,10 +111,35 @@ func (uc *SessionUsecase) Refresh(ctx context.Context, oldToken string) (string,
 		return "", "", fmt.Errorf("session.Refresh - save session: %w", err)
 	}
 
+	uc.audit.record(ctx, entity.AuditRefresh, userID, userID, nil)
 	uc.log.Info("refresh successful", "userID", userID)
 	return tokens.AccessToken, tokens.RefreshToken, nil
 }
 
+// VerifyAccess проверяет access-токен и сверяет роль с текущей в базе, чтобы смена роли
+// и блокировка действовали сразу, не дожидаясь истечения токена.
+func (uc *SessionUsecase) VerifyAccess(ctx context.Context, token string) (int64, string, error) {
+	userID, role, err := uc.tokens.ValidateAccess(token)
+	if err != nil {
+		return 0, "", fmt.Errorf("session.VerifyAccess - validate token: %w", err)
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		uc.log.Warn("token of unknown user", "userID", userID, "err", err)
+		return 0, "", fmt.Errorf("session.VerifyAccess - get user: %w", err)
+	}
+	if user.BlockedAt(time.Now()) {
+		return 0, "", ErrUserBlocked
+	}
+	if user.Role != role {
+		uc.log.Info("stale access token", "userID", userID, "tokenRole", role, "role", user.Role)
+		return 0, "", ErrStaleToken
+	}
+
+	return userID, role, nil
+}
+
 func (uc *SessionUsecase) List(ctx context.Context, userID int64) ([]entity.Session, error) {
 	uc.log.Debug("session.List called", "userID", userID)
 	sessions, err := uc.sessionRepo.ListActiveByUser(ctx, userID)
@@ -109,10 +157,18 @@ func (uc *SessionUsecase) List(ctx context.Context, userID int64) ([]entity.Sess
 
 func (uc *SessionUsecase) Revoke(ctx context.Context, token string) error {
 	uc.log.Debug("session.Revoke called")
+
+	// владелец сессии нужен только для журнала
+	var ownerID int64
+	if sess, err := uc.sessionRepo.GetByToken(ctx, token); err == nil {
+		ownerID = sess.UserID
+	}
+
 	if err := uc.sessionRepo.DeleteByToken(ctx, token); err != nil {
 		uc.log.Error("failed to revoke session", "err", err)
 		return fmt.Errorf("session.Revoke - delete by token: %w", err)
 	}
+	uc.audit.record(ctx, entity.AuditSessionRevoked, 0, ownerID, nil)
 	uc.log.Info("session revoked")
 	return nil
 }
@@ -123,6 +179,7 @@ func (uc *SessionUsecase) RevokeAll(ctx context.Context, userID int64) error {
 		uc.log.Error("failed to revoke all sessions", "err", err)
 		return fmt.Errorf("session.RevokeAll - delete by userID: %w", err)
 	}
+	uc.audit.record(ctx, entity.AuditSessionsRevokedAll, 0, userID, nil)
 	uc.log.Info("all sessions revoked", "userID", userID)
 	return nil
 }
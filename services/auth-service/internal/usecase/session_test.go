@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"auth-service/internal/auth"
+	"auth-service/internal/entity"
+	"auth-service/internal/usecase/mocks"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ZoyaDenisova/go-common/jwt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTokens — токены без подписи: ValidateAccess и ValidateRefresh отдают заданные userID и role,
+// Generate запоминает роль, с которой выпущена пара
+type fakeTokens struct {
+	jwt.TokenManager
+	userID    int64
+	role      string
+	generated string
+}
+
+func (f *fakeTokens) ValidateAccess(string) (int64, string, error)  { return f.userID, f.role, nil }
+func (f *fakeTokens) ValidateRefresh(string) (int64, string, error) { return f.userID, f.role, nil }
+
+func (f *fakeTokens) Generate(userID int64, role string) (*jwt.Tokens, error) {
+	f.generated = role
+	return &jwt.Tokens{
+		AccessToken:    "access",
+		RefreshToken:   "refresh",
+		RefreshExpires: time.Now().Add(time.Hour),
+	}, nil
+}
+
+func TestSessionUsecase_VerifyAccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	users := mocks.NewMockUserRepo(ctrl)
+	tokens := &fakeTokens{userID: 5, role: auth.RoleModerator}
+	uc := NewSessionUsecase(nil, users, nil, tokens, mocks.FakeLogger{})
+	ctx := context.Background()
+
+	t.Run("current role", func(t *testing.T) {
+		users.EXPECT().GetByID(ctx, int64(5)).Return(&entity.User{ID: 5, Role: auth.RoleModerator}, nil)
+
+		id, role, err := uc.VerifyAccess(ctx, "token")
+		require.NoError(t, err)
+		require.Equal(t, int64(5), id)
+		require.Equal(t, auth.RoleModerator, role)
+	})
+
+	t.Run("role changed since the token was issued", func(t *testing.T) {
+		users.EXPECT().GetByID(ctx, int64(5)).Return(&entity.User{ID: 5, Role: auth.RoleUser}, nil)
+
+		_, _, err := uc.VerifyAccess(ctx, "token")
+		require.ErrorIs(t, err, ErrStaleToken)
+	})
+
+	t.Run("blocked after the token was issued", func(t *testing.T) {
+		users.EXPECT().GetByID(ctx, int64(5)).Return(&entity.User{ID: 5, Role: auth.RoleModerator, IsBlocked: true}, nil)
+
+		_, _, err := uc.VerifyAccess(ctx, "token")
+		require.ErrorIs(t, err, ErrUserBlocked)
+	})
+}
+
+func TestSessionUsecase_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessions := mocks.NewMockSessionRepo(ctrl)
+	users := mocks.NewMockUserRepo(ctrl)
+	audit := mocks.NewMockAuditRepo(ctrl)
+	ctx := context.Background()
+	session := &entity.Session{UserID: 5, RefreshToken: "old", ExpiresAt: time.Now().Add(time.Hour)}
+
+	t.Run("new pair carries the role from the database", func(t *testing.T) {
+		tokens := &fakeTokens{userID: 5, role: auth.RoleUser}
+		uc := NewSessionUsecase(sessions, users, audit, tokens, mocks.FakeLogger{})
+
+		sessions.EXPECT().GetByToken(ctx, "old").Return(session, nil)
+		users.EXPECT().GetByID(ctx, int64(5)).Return(&entity.User{ID: 5, Role: auth.RoleAdmin}, nil)
+		sessions.EXPECT().DeleteByToken(ctx, "old").Return(nil)
+		sessions.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+		audit.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+
+		access, refresh, err := uc.Refresh(ctx, "old")
+		require.NoError(t, err)
+		require.Equal(t, "access", access)
+		require.Equal(t, "refresh", refresh)
+		require.Equal(t, auth.RoleAdmin, tokens.generated)
+	})
+
+	t.Run("blocked user cannot refresh", func(t *testing.T) {
+		tokens := &fakeTokens{userID: 5, role: auth.RoleUser}
+		uc := NewSessionUsecase(sessions, users, audit, tokens, mocks.FakeLogger{})
+
+		sessions.EXPECT().GetByToken(ctx, "old").Return(session, nil)
+		users.EXPECT().GetByID(ctx, int64(5)).Return(&entity.User{ID: 5, Role: auth.RoleUser, IsBlocked: true}, nil)
+
+		_, _, err := uc.Refresh(ctx, "old")
+		require.ErrorIs(t, err, ErrUserBlocked)
+		require.Empty(t, tokens.generated)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/ZoyaDenisova/go-common/hasher"
@@ -20,36 +21,56 @@ var (
 	ErrInvalidCreds = errors.New("invalid credentials")
 	ErrUserBlocked  = errors.New("user is blocked")
 	ErrForbidden    = errors.New("forbidden")
+	// ErrInvalidBlock — отрицательный срок блокировки
+	ErrInvalidBlock = errors.New("block duration must not be negative")
 )
 
 type UserUsecase struct {
 	userRepo    repo.UserRepo
 	sessionRepo repo.SessionRepo
+	modRepo     repo.ModeratorRepo
+	audit       auditor
 	hasher      hasher.PasswordHasher
 	tokens      jwt.TokenManager
+	names       UsernamePolicy
 	log         logger.Interface
 }
 
 func NewUserUsecase(
 	userRepo repo.UserRepo,
 	sessionRepo repo.SessionRepo,
+	modRepo repo.ModeratorRepo,
+	auditRepo repo.AuditRepo,
 	hasher hasher.PasswordHasher,
 	tokens jwt.TokenManager,
+	names UsernamePolicy,
 	log logger.Interface,
 ) *UserUsecase {
 	return &UserUsecase{
 		userRepo:    userRepo,
 		sessionRepo: sessionRepo,
+		modRepo:     modRepo,
+		audit:       auditor{repo: auditRepo, log: log},
 		hasher:      hasher,
 		tokens:      tokens,
+		names:       names,
 		log:         log,
 	}
 }
 
-// Register создаёт нового пользователя с is_blocked = FALSE.
-func (uc *UserUsecase) Register(ctx context.Context, name, email, password string) error {
+// Register создаёт нового пользователя с is_blocked = FALSE. username — уникальный адрес
+// для упоминаний и ссылок, name — отображаемое имя.
+func (uc *UserUsecase) Register(ctx context.Context, username, name, email, password string) error {
 	uc.log.Debug("Register called", "email", email)
 
+	name, err := normalizeName(name)
+	if err != nil {
+		return err
+	}
+	if username, err = uc.CheckUsername(ctx, username); err != nil {
+		return err
+	}
+
 	if _, err := uc.userRepo.GetByEmail(ctx, email); err == nil {
 		uc.log.Warn("email already exists", "email", email)
 		return ErrUserExists
@@ -65,19 +86,25 @@ func (uc *UserUsecase) Register(ctx context.Context, name, email, password strin
 	}
 
 	user := &entity.User{
+		Username:     username,
 		Name:         name,
 		Email:        email,
 		PasswordHash: hash,
-		Role:         "user",
+		Role:         auth.RoleUser,
 		CreatedAt:    time.Now().UTC(),
 		IsBlocked:    false,
 	}
 
-	if err := uc.userRepo.Create(ctx, user); err != nil {
+	if err := uc.userRepo.Create(ctx, user); errors.Is(err, dbErrors.ErrConflict) {
+		// email проверен выше, так что гонка почти наверняка за username
+		uc.log.Warn("user conflict on create", "email", email)
+		return ErrUsernameTaken
+	} else if err != nil {
 		uc.log.Error("failed to create user", "err", err)
 		return fmt.Errorf("user.Register: create user: %w", err)
 	}
 
+	uc.audit.record(ctx, entity.AuditUserRegistered, user.ID, user.ID, nil)
 	uc.log.Info("user registered", "userID", user.ID)
 	return nil
 }
@@ -89,17 +116,20 @@ func (uc *UserUsecase) Login(ctx context.Context, email, password, ua string) (s
 	user, err := uc.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		uc.log.Warn("invalid credentials: email not found", "email", email)
+		uc.audit.record(ctx, entity.AuditLoginFailed, 0, 0, map[string]any{"email": email, "reason": "unknown_email"})
 		return "", "", ErrInvalidCreds
 	}
 
 	// Заблокированный пользователь не может войти
-	if user.IsBlocked {
+	if user.BlockedAt(time.Now()) {
 		uc.log.Warn("blocked user tried to login", "userID", user.ID)
+		uc.audit.record(ctx, entity.AuditLoginFailed, user.ID, user.ID, map[string]any{"reason": "blocked"})
 		return "", "", ErrUserBlocked
 	}
 
 	if err := uc.hasher.Verify(user.PasswordHash, password); err != nil {
 		uc.log.Warn("invalid credentials: password mismatch", "email", email)
+		uc.audit.record(ctx, entity.AuditLoginFailed, 0, user.ID, map[string]any{"reason": "bad_password"})
 		return "", "", ErrInvalidCreds
 	}
 
@@ -122,6 +152,7 @@ func (uc *UserUsecase) Login(ctx context.Context, email, password, ua string) (s
 		return "", "", fmt.Errorf("user.Login - save session: %w", err)
 	}
 
+	uc.audit.record(ctx, entity.AuditLogin, user.ID, user.ID, nil)
 	uc.log.Info("user logged in", "userID", user.ID)
 	return tokens.AccessToken, tokens.RefreshToken, nil
 }
@@ -136,15 +167,18 @@ func (uc *UserUsecase) Update(ctx context.Context, id int64, params UpdateUserPa
 		return fmt.Errorf("user.Update: lookup: %w", err)
 	}
 
-	if user.IsBlocked {
+	if user.BlockedAt(time.Now()) {
 		uc.log.Warn("blocked user tried to update profile", "userID", id)
 		return ErrUserBlocked
 	}
 
 	if params.Name != nil {
-		user.Name = *params.Name
+		if user.Name, err = normalizeName(*params.Name); err != nil {
+			return err
+		}
 	}
 
+	oldEmail := user.Email
 	if params.Email != nil && *params.Email != user.Email {
 		if _, e := uc.userRepo.GetByEmail(ctx, *params.Email); e == nil {
 			uc.log.Warn("email already exists", "email", *params.Email)
@@ -170,6 +204,13 @@ func (uc *UserUsecase) Update(ctx context.Context, id int64, params UpdateUserPa
 		return fmt.Errorf("user.Update: update: %w", err)
 	}
 
+	if user.Email != oldEmail {
+		uc.audit.record(ctx, entity.AuditEmailChanged, 0, user.ID, map[string]any{"from": oldEmail, "to": user.Email})
+	}
+	if params.Password != nil {
+		uc.audit.record(ctx, entity.AuditPasswordChanged, 0, user.ID, nil)
+	}
+
 	uc.log.Info("user updated", "userID", user.ID)
 	return nil
 }
@@ -192,17 +233,60 @@ func (uc *UserUsecase) GetByID(ctx context.Context, id int64) (*entity.User, err
 	return user, nil
 }
 
-// Block ставит is_blocked = TRUE и удаляет активные refresh‑сессии.
-func (uc *UserUsecase) Block(ctx context.Context, targetID int64) error {
-	uc.log.Debug("Block called", "targetID", targetID)
+// MaxResolveUsernames — больше имён за один вызов ResolveUsernames не разбирается
+const MaxResolveUsernames = 20
 
-	// Проверка роли инициатора (админ). Предполагаем middleware, но дублируем для безопасности.
-	if uid, role := auth.FromContext(ctx); role != "admin" {
+// ResolveUsernames находит пользователей по username без учёта регистра. Неизвестные имена пропускаются,
+// повторы и всё сверх MaxResolveUsernames отбрасываются.
+func (uc *UserUsecase) ResolveUsernames(ctx context.Context, names []string) ([]*entity.User, error) {
+	uc.log.Debug("ResolveUsernames called", "count", len(names))
+
+	seen := make(map[string]bool, len(names))
+	users := make([]*entity.User, 0, len(names))
+	for _, name := range names {
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		if len(seen) == MaxResolveUsernames {
+			break
+		}
+		seen[key] = true
+
+		user, err := uc.userRepo.GetByUsername(ctx, name)
+		if errors.Is(err, dbErrors.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			uc.log.Error("get user by username failed", "err", err)
+			return nil, fmt.Errorf("user.ResolveUsernames: %w", err)
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// Block ставит is_blocked = TRUE и удаляет активные refresh‑сессии. С p.Duration > 0 блокировка
+// временная: по истечении срока её снимает UnblockExpired. Причина и срок попадают в журнал аудита.
+func (uc *UserUsecase) Block(ctx context.Context, targetID int64, p BlockParams) error {
+	uc.log.Debug("Block called", "targetID", targetID, "duration", p.Duration)
+
+	// Проверка прав инициатора. Предполагаем middleware, но дублируем для безопасности.
+	if uid, role := auth.FromContext(ctx); !auth.Can(role, auth.PermUserBlock) {
 		uc.log.Warn("non‑admin tried to block user", "initiator", uid)
 		return ErrForbidden
 	}
+	if p.Duration < 0 {
+		return ErrInvalidBlock
+	}
 
-	if err := uc.userRepo.Block(ctx, targetID); err != nil {
+	var until *time.Time
+	if p.Duration > 0 {
+		t := time.Now().UTC().Add(p.Duration)
+		until = &t
+	}
+
+	if err := uc.userRepo.Block(ctx, targetID, until, p.Reason); err != nil {
 		uc.log.Error("block failed", "err", err)
 		return fmt.Errorf("user.Block: %w", err)
 	}
@@ -212,7 +296,15 @@ func (uc *UserUsecase) Block(ctx context.Context, targetID int64) error {
 		uc.log.Error("session cleanup failed", "err", err)
 	}
 
-	uc.log.Info("user blocked", "targetID", targetID)
+	meta := map[string]any{}
+	if p.Reason != "" {
+		meta["reason"] = p.Reason
+	}
+	if until != nil {
+		meta["until"] = until.Format(time.RFC3339)
+	}
+	uc.audit.record(ctx, entity.AuditUserBlocked, 0, targetID, meta)
+	uc.log.Info("user blocked", "targetID", targetID, "until", until)
 	return nil
 }
 
@@ -220,7 +312,7 @@ func (uc *UserUsecase) Block(ctx context.Context, targetID int64) error {
 func (uc *UserUsecase) Unblock(ctx context.Context, targetID int64) error {
 	uc.log.Debug("Unblock called", "targetID", targetID)
 
-	if uid, role := auth.FromContext(ctx); role != "admin" {
+	if uid, role := auth.FromContext(ctx); !auth.Can(role, auth.PermUserBlock) {
 		uc.log.Warn("non‑admin tried to unblock user", "initiator", uid)
 		return ErrForbidden
 	}
@@ -230,13 +322,31 @@ func (uc *UserUsecase) Unblock(ctx context.Context, targetID int64) error {
 		return fmt.Errorf("user.Unblock: %w", err)
 	}
 
+	uc.audit.record(ctx, entity.AuditUserUnblocked, 0, targetID, nil)
 	uc.log.Info("user unblocked", "targetID", targetID)
 	return nil
 }
 
+// UnblockExpired снимает временные блокировки с истёкшим сроком (вызывается из cron)
+func (uc *UserUsecase) UnblockExpired(ctx context.Context) error {
+	uc.log.Debug("UnblockExpired called")
+
+	ids, err := uc.userRepo.UnblockExpired(ctx, time.Now().UTC())
+	if err != nil {
+		uc.log.Error("unblock expired failed", "err", err)
+		return fmt.Errorf("user.UnblockExpired: %w", err)
+	}
+
+	for _, id := range ids {
+		uc.audit.record(ctx, entity.AuditUserUnblocked, 0, id, map[string]any{"reason": "expired"})
+	}
+	uc.log.Info("expired blocks lifted", "count", len(ids))
+	return nil
+}
+
 func (uc *UserUsecase) GetAll(ctx context.Context) ([]*entity.User, error) {
 	_, role := auth.FromContext(ctx)
-	if role != "admin" {
+	if !auth.Can(role, auth.PermUserList) {
 		return nil, ErrForbidden
 	}
 
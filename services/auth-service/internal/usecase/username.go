@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"auth-service/internal/entity"
+	dbErrors "auth-service/internal/errors"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidUsername  = errors.New("invalid username: 3-32 latin letters, digits, '_', '.' or '-', starting and ending with a letter, digit or '_'")
+	ErrReservedUsername = errors.New("this username is reserved")
+	ErrUsernameTaken    = errors.New("username is already taken")
+	ErrUsernameCooldown = errors.New("username was changed recently")
+	ErrInvalidName      = errors.New("invalid name: must be 1-64 characters")
+)
+
+// maxNameLength — отображаемое имя (users.name VARCHAR(64))
+const maxNameLength = 64
+
+// usernamePattern — только латиница: похожие буквы из других алфавитов позволили бы выдать себя за другого.
+// Точка и дефис не на краях — иначе @упоминание в конце предложения теряло бы их.
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{1,30}[A-Za-z0-9_]$`)
+
+// reservedUsernames нельзя занять при регистрации и переименовании: их легко принять за служебные
+var reservedUsernames = map[string]bool{
+	"admin": true, "administrator": true, "moderator": true, "mod": true, "root": true, "system": true,
+	"support": true, "help": true, "me": true, "all": true, "everyone": true, "here": true,
+	"null": true, "undefined": true, "api": true, "auth": true, "guest": true,
+}
+
+// UsernamePolicy — правила переименования
+type UsernamePolicy struct {
+	ChangeCooldown    time.Duration // не чаще одной смены за это время
+	ReservationPeriod time.Duration // столько прежний username держится за владельцем
+}
+
+// normalizeUsername убирает пробелы и '@' в начале и проверяет формат. Регистр сохраняется:
+// уникальность проверяется без его учёта, а показывается username так, как его выбрали.
+func normalizeUsername(username string) (string, error) {
+	username = strings.TrimPrefix(strings.TrimSpace(username), "@")
+	if !usernamePattern.MatchString(username) {
+		return "", ErrInvalidUsername
+	}
+	if reservedUsernames[strings.ToLower(username)] {
+		return "", ErrReservedUsername
+	}
+	return username, nil
+}
+
+func normalizeName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxNameLength {
+		return "", ErrInvalidName
+	}
+	return name, nil
+}
+
+// CheckUsername проверяет, можно ли занять username: формат, служебные имена, занятость и резерв
+// после чужих переименований. Возвращает username в том виде, в каком он будет сохранён.
+func (uc *UserUsecase) CheckUsername(ctx context.Context, username string) (string, error) {
+	username, err := normalizeUsername(username)
+	if err != nil {
+		return "", err
+	}
+	ok, err := uc.userRepo.UsernameAvailable(ctx, username, 0)
+	if err != nil {
+		uc.log.Error("username availability check failed", "err", err)
+		return "", fmt.Errorf("user.CheckUsername: %w", err)
+	}
+	if !ok {
+		return "", ErrUsernameTaken
+	}
+	return username, nil
+}
+
+// ChangeUsername меняет username пользователя. Менять можно не чаще раза в ChangeCooldown
+// (кроме смены одного регистра); прежний username ReservationPeriod остаётся за владельцем.
+func (uc *UserUsecase) ChangeUsername(ctx context.Context, id int64, username string) error {
+	uc.log.Debug("ChangeUsername called", "userID", id)
+
+	user, err := uc.userRepo.GetByID(ctx, id)
+	if err != nil {
+		uc.log.Error("user lookup failed", "err", err)
+		return fmt.Errorf("user.ChangeUsername: lookup: %w", err)
+	}
+	if user.BlockedAt(time.Now()) {
+		uc.log.Warn("blocked user tried to change username", "userID", id)
+		return ErrUserBlocked
+	}
+
+	username, err = normalizeUsername(username)
+	if err != nil {
+		return err
+	}
+	if username == user.Username {
+		return nil
+	}
+	if !strings.EqualFold(username, user.Username) && user.UsernameChangedAt != nil {
+		if next := user.UsernameChangedAt.Add(uc.names.ChangeCooldown); time.Now().Before(next) {
+			return fmt.Errorf("%w: next change is possible after %s", ErrUsernameCooldown, next.UTC().Format(time.RFC3339))
+		}
+	}
+
+	previous, err := uc.userRepo.ChangeUsername(ctx, id, username, time.Now().UTC().Add(uc.names.ReservationPeriod))
+	if errors.Is(err, dbErrors.ErrConflict) {
+		return ErrUsernameTaken
+	} else if err != nil {
+		uc.log.Error("username change failed", "err", err)
+		return fmt.Errorf("user.ChangeUsername: %w", err)
+	}
+
+	uc.audit.record(ctx, entity.AuditUsernameChanged, 0, id, map[string]any{"from": previous, "to": username})
+	uc.log.Info("username changed", "userID", id)
+	return nil
+}
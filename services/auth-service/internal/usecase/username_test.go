@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"auth-service/internal/entity"
+	dbErrors "auth-service/internal/errors"
+	"auth-service/internal/usecase/mocks"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeUsername(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+		err      error
+	}{
+		{name: "plain", input: "ivan_petrov", expected: "ivan_petrov"},
+		{name: "case is kept", input: "IvanPetrov", expected: "IvanPetrov"},
+		{name: "spaces and @ are trimmed", input: "  @ivan.petrov ", expected: "ivan.petrov"},
+		{name: "digits and dash inside", input: "ivan-2000", expected: "ivan-2000"},
+		{name: "underscore on the edges", input: "_ivan_", expected: "_ivan_"},
+		{name: "shortest", input: "abc", expected: "abc"},
+		{name: "longest", input: strings.Repeat("a", 32), expected: strings.Repeat("a", 32)},
+		{name: "too short", input: "ab", err: ErrInvalidUsername},
+		{name: "too long", input: strings.Repeat("a", 33), err: ErrInvalidUsername},
+		{name: "dot at the end", input: "ivan.", err: ErrInvalidUsername},
+		{name: "dash at the start", input: "-ivan", err: ErrInvalidUsername},
+		{name: "space inside", input: "ivan petrov", err: ErrInvalidUsername},
+		{name: "cyrillic look-alike", input: "ivаn", err: ErrInvalidUsername},
+		{name: "empty", input: "", err: ErrInvalidUsername},
+		{name: "reserved", input: "admin", err: ErrReservedUsername},
+		{name: "reserved in another case", input: "@Moderator", err: ErrReservedUsername},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := normalizeUsername(c.input)
+			if c.err != nil {
+				require.ErrorIs(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expected, got)
+		})
+	}
+}
+
+func TestUserUsecase_CheckUsername(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	users := mocks.NewMockUserRepo(ctrl)
+	uc := NewUserUsecase(users, nil, nil, nil, nil, nil, UsernamePolicy{}, mocks.FakeLogger{})
+	ctx := context.Background()
+
+	t.Run("available", func(t *testing.T) {
+		users.EXPECT().UsernameAvailable(ctx, "ivan", int64(0)).Return(true, nil)
+
+		got, err := uc.CheckUsername(ctx, "@ivan")
+		require.NoError(t, err)
+		require.Equal(t, "ivan", got)
+	})
+
+	t.Run("taken or reserved by someone else", func(t *testing.T) {
+		users.EXPECT().UsernameAvailable(ctx, "ivan", int64(0)).Return(false, nil)
+
+		_, err := uc.CheckUsername(ctx, "ivan")
+		require.ErrorIs(t, err, ErrUsernameTaken)
+	})
+
+	t.Run("invalid format does not reach the repo", func(t *testing.T) {
+		_, err := uc.CheckUsername(ctx, "a")
+		require.ErrorIs(t, err, ErrInvalidUsername)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		users.EXPECT().UsernameAvailable(ctx, "ivan", int64(0)).Return(false, errors.New("db down"))
+
+		_, err := uc.CheckUsername(ctx, "ivan")
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrUsernameTaken)
+	})
+}
+
+func TestUserUsecase_ChangeUsername(t *testing.T) {
+	const (
+		cooldown    = 30 * 24 * time.Hour
+		reservation = 7 * 24 * time.Hour
+	)
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*mocks.MockUserRepo, *mocks.MockAuditRepo, *UserUsecase) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		users := mocks.NewMockUserRepo(ctrl)
+		audit := mocks.NewMockAuditRepo(ctrl)
+		policy := UsernamePolicy{ChangeCooldown: cooldown, ReservationPeriod: reservation}
+		return users, audit, NewUserUsecase(users, nil, nil, audit, nil, nil, policy, mocks.FakeLogger{})
+	}
+	ago := func(d time.Duration) *time.Time {
+		at := time.Now().Add(-d)
+		return &at
+	}
+
+	t.Run("first change reserves the old username", func(t *testing.T) {
+		users, audit, uc := setup(t)
+
+		users.EXPECT().GetByID(ctx, int64(5)).Return(&entity.User{ID: 5, Username: "ivan"}, nil)
+		users.EXPECT().ChangeUsername(ctx, int64(5), "ivan_petrov", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ int64, _ string, reserveUntil time.Time) (string, error) {
+				require.WithinDuration(t, time.Now().Add(reservation), reserveUntil, time.Minute)
+				return "ivan", nil
+			})
+		audit.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e *entity.AuditEvent) error {
+			require.Equal(t, entity.AuditUsernameChanged, e.Action)
+			require.Equal(t, int64(5), *e.TargetID)
+			require.Equal(t, map[string]any{"from": "ivan", "to": "ivan_petrov"}, e.Metadata)
+			return nil
+		})
+
+		require.NoError(t, uc.ChangeUsername(ctx, 5, "@ivan_petrov"))
+	})
+
+	t.Run("change after the cooldown", func(t *testing.T) {
+		users, audit, uc := setup(t)
+
+		users.EXPECT().GetByID(ctx, int64(5)).Return(&entity.User{ID: 5, Username: "ivan", UsernameChangedAt: ago(cooldown + time.Hour)}, nil)
+		users.EXPECT().ChangeUsername(ctx, int64(5), "petrov", gomock.Any()).Return("ivan", nil)
+		audit.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+
+		require.NoError(t, uc.ChangeUsername(ctx, 5, "petrov"))
+	})
+
+	t.Run("change within the cooldown", func(t *testing.T) {
+		users, _, uc := setup(t)
+
+		users.EXPECT().GetByID(ctx, int64(5)).Return(&entity.User{ID: 5, Username: "ivan", UsernameChangedAt: ago(time.Hour)}, nil)
+
+		err := uc.ChangeUsername(ctx, 5, "petrov")
+		require.ErrorIs(t, err, ErrUsernameCooldown)
+		require.Contains(t, err.Error(), "next change is possible after")
+	})
+
+	t.Run("case-only change ignores the cooldown", func(t *testing.T) {
+		users, audit, uc := setup(t)
+
+		users.EXPECT().GetByID(ctx, int64(5)).Return(&entity.User{ID: 5, Username: "ivan", UsernameChangedAt: ago(time.Hour)}, nil)
+		users.EXPECT().ChangeUsername(ctx, int64(5), "Ivan", gomock.Any()).Return("ivan", nil)
+		audit.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+
+		require.NoError(t, uc.ChangeUsername(ctx, 5, "Ivan"))
+	})
+
+	t.Run("same username is a no-op", func(t *testing.T) {
+		users, _, uc := setup(t)
+
+		users.EXPECT().GetByID(ctx, int64(5)).Return(&entity.User{ID: 5, Username: "ivan", UsernameChangedAt: ago(time.Hour)}, nil)
+
+		require.NoError(t, uc.ChangeUsername(ctx, 5, "@ivan"))
+	})
+
+	t.Run("taken or reserved by someone else", func(t *testing.T) {
+		users, _, uc := setup(t)
+
+		users.EXPECT().GetByID(ctx, int64(5)).Return(&entity.User{ID: 5, Username: "ivan"}, nil)
+		users.EXPECT().ChangeUsername(ctx, int64(5), "petrov", gomock.Any()).Return("", dbErrors.ErrConflict)
+
+		require.ErrorIs(t, uc.ChangeUsername(ctx, 5, "petrov"), ErrUsernameTaken)
+	})
+
+	t.Run("rejected before touching the repo", func(t *testing.T) {
+		cases := []struct {
+			name     string
+			user     *entity.User
+			username string
+			err      error
+		}{
+			{name: "blocked user", user: &entity.User{ID: 5, Username: "ivan", IsBlocked: true}, username: "petrov", err: ErrUserBlocked},
+			{name: "invalid username", user: &entity.User{ID: 5, Username: "ivan"}, username: "p", err: ErrInvalidUsername},
+			{name: "reserved username", user: &entity.User{ID: 5, Username: "ivan"}, username: "support", err: ErrReservedUsername},
+		}
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				users, _, uc := setup(t)
+
+				users.EXPECT().GetByID(ctx, int64(5)).Return(c.user, nil)
+
+				require.ErrorIs(t, uc.ChangeUsername(ctx, 5, c.username), c.err)
+			})
+		}
+	})
+
+	t.Run("expired temporary block does not stop the change", func(t *testing.T) {
+		users, audit, uc := setup(t)
+
+		until := time.Now().Add(-time.Minute)
+		users.EXPECT().GetByID(ctx, int64(5)).Return(&entity.User{ID: 5, Username: "ivan", IsBlocked: true, BlockedUntil: &until}, nil)
+		users.EXPECT().ChangeUsername(ctx, int64(5), "petrov", gomock.Any()).Return("ivan", nil)
+		audit.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+
+		require.NoError(t, uc.ChangeUsername(ctx, 5, "petrov"))
+	})
+}
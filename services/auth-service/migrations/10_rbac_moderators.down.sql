@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS category_moderators;
+
+UPDATE users SET role = 'user' WHERE role = 'moderator';
@@ -0,0 +1,11 @@
+-- роли: user, moderator, admin; модератор действует только в назначенных категориях (и их подкатегориях)
+CREATE TABLE IF NOT EXISTS category_moderators
+(
+    user_id     INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    category_id INTEGER     NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    granted_by  INTEGER     REFERENCES users (id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, category_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_category_moderators_category ON category_moderators (category_id);
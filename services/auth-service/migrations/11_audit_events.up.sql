@@ -0,0 +1,17 @@
+-- журнал действий, важных для безопасности: кто (actor) что сделал (action) с кем (target)
+CREATE TABLE IF NOT EXISTS audit_events
+(
+    id         BIGSERIAL PRIMARY KEY,
+    actor_id   INTEGER     REFERENCES users (id) ON DELETE SET NULL,
+    target_id  INTEGER     REFERENCES users (id) ON DELETE SET NULL,
+    action     VARCHAR(64) NOT NULL,
+    ip         VARCHAR(64),
+    user_agent TEXT,
+    metadata   JSONB       NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_created ON audit_events (created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_events_target ON audit_events (target_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor ON audit_events (actor_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_events_action ON audit_events (action, created_at);
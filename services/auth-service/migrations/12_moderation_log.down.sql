@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS topic_audit
+(
+    id               SERIAL PRIMARY KEY,
+    topic_id         INTEGER     NOT NULL,
+    action           VARCHAR(16) NOT NULL,
+    actor_id         INTEGER     REFERENCES users (id) ON DELETE SET NULL,
+    from_category_id INTEGER,
+    to_category_id   INTEGER,
+    merged_into_id   INTEGER,
+    messages_moved   INTEGER     NOT NULL DEFAULT 0,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_topic_audit_topic ON topic_audit (topic_id, created_at);
+
+INSERT INTO topic_audit (topic_id, action, actor_id, from_category_id, to_category_id,
+                         merged_into_id, messages_moved, created_at)
+SELECT target_id,
+       substring(action FROM 7),
+       actor_id,
+       (details ->> 'from_category_id')::int,
+       (details ->> 'to_category_id')::int,
+       (details ->> 'merged_into_id')::int,
+       COALESCE((details ->> 'messages_moved')::int, 0),
+       created_at
+FROM moderation_log
+WHERE action IN ('topic.move', 'topic.merge');
+
+DROP TABLE IF EXISTS moderation_log;
@@ -0,0 +1,39 @@
+-- журнал модерации: кто (actor) что сделал (action) с чужим контентом (target) и почему.
+-- snapshot — содержимое на момент действия, details — параметры действия (перенос, слияние)
+CREATE TABLE IF NOT EXISTS moderation_log
+(
+    id               BIGSERIAL PRIMARY KEY,
+    actor_id         INTEGER     REFERENCES users (id) ON DELETE SET NULL,
+    action           VARCHAR(32) NOT NULL,
+    target_type      VARCHAR(16) NOT NULL,
+    target_id        BIGINT      NOT NULL,
+    target_author_id INTEGER     REFERENCES users (id) ON DELETE SET NULL,
+    reason           TEXT        NOT NULL DEFAULT '',
+    snapshot         JSONB       NOT NULL DEFAULT '{}'::jsonb,
+    details          JSONB       NOT NULL DEFAULT '{}'::jsonb,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_log_created ON moderation_log (created_at);
+CREATE INDEX IF NOT EXISTS idx_moderation_log_actor ON moderation_log (actor_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_moderation_log_author ON moderation_log (target_author_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_moderation_log_target ON moderation_log (target_type, target_id);
+
+-- переносы и слияния топиков теперь пишутся сюда же
+INSERT INTO moderation_log (actor_id, action, target_type, target_id, target_author_id, details, created_at)
+SELECT a.actor_id,
+       'topic.' || a.action,
+       'topic',
+       a.topic_id,
+       t.author_id,
+       CASE a.action
+           WHEN 'move' THEN jsonb_build_object('from_category_id', a.from_category_id,
+                                               'to_category_id', a.to_category_id)
+           ELSE jsonb_build_object('merged_into_id', a.merged_into_id,
+                                   'messages_moved', a.messages_moved)
+           END,
+       a.created_at
+FROM topic_audit a
+         LEFT JOIN topics t ON t.id = a.topic_id;
+
+DROP TABLE IF EXISTS topic_audit;
@@ -0,0 +1,6 @@
+ALTER TABLE topics
+    DROP COLUMN IF EXISTS hidden_at;
+ALTER TABLE messages
+    DROP COLUMN IF EXISTS hidden_at;
+
+DROP TABLE IF EXISTS reports;
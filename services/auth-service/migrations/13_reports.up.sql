@@ -0,0 +1,27 @@
+-- жалобы пользователей на сообщения и топики; один пользователь — одна жалоба на объект
+CREATE TABLE IF NOT EXISTS reports
+(
+    id          BIGSERIAL PRIMARY KEY,
+    message_id  INTEGER REFERENCES messages (id) ON DELETE CASCADE,
+    topic_id    INTEGER REFERENCES topics (id) ON DELETE CASCADE,
+    reporter_id INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    reason      VARCHAR(32) NOT NULL,
+    comment     TEXT        NOT NULL DEFAULT '',
+    state       VARCHAR(16) NOT NULL DEFAULT 'open',
+    resolved_by INTEGER     REFERENCES users (id) ON DELETE SET NULL,
+    resolved_at TIMESTAMPTZ,
+    resolution  TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT reports_one_target CHECK ((message_id IS NULL) <> (topic_id IS NULL)),
+    CONSTRAINT reports_state CHECK (state IN ('open', 'dismissed', 'actioned'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_reports_message_reporter ON reports (message_id, reporter_id) WHERE message_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_reports_topic_reporter ON reports (topic_id, reporter_id) WHERE topic_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_reports_state ON reports (state, created_at);
+
+-- скрытие после нескольких жалоб: контент остаётся, но виден только модераторам
+ALTER TABLE messages
+    ADD COLUMN hidden_at TIMESTAMPTZ;
+ALTER TABLE topics
+    ADD COLUMN hidden_at TIMESTAMPTZ;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS mutes;
+
+DROP INDEX IF EXISTS idx_users_blocked_until;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS block_reason,
+    DROP COLUMN IF EXISTS blocked_until;
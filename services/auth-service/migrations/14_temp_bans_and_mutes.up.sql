@@ -0,0 +1,23 @@
+-- временная блокировка: blocked_until = NULL — бессрочно; причина видна администраторам
+ALTER TABLE users
+    ADD COLUMN blocked_until TIMESTAMPTZ,
+    ADD COLUMN block_reason  TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_users_blocked_until ON users (blocked_until) WHERE is_blocked AND blocked_until IS NOT NULL;
+
+-- запрет писать в конкретном топике или категории (с подкатегориями) без полной блокировки
+CREATE TABLE IF NOT EXISTS mutes
+(
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    topic_id    INTEGER REFERENCES topics (id) ON DELETE CASCADE,
+    category_id INTEGER REFERENCES categories (id) ON DELETE CASCADE,
+    reason      TEXT        NOT NULL DEFAULT '',
+    muted_by    INTEGER     REFERENCES users (id) ON DELETE SET NULL,
+    expires_at  TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT mutes_one_scope CHECK ((topic_id IS NULL) <> (category_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_mutes_user ON mutes (user_id);
+CREATE INDEX IF NOT EXISTS idx_mutes_expires_at ON mutes (expires_at) WHERE expires_at IS NOT NULL;
@@ -0,0 +1,6 @@
+DELETE FROM reports WHERE reporter_id IS NULL;
+ALTER TABLE reports
+    ALTER COLUMN reporter_id SET NOT NULL;
+
+DROP TABLE IF EXISTS filter_settings;
+DROP TABLE IF EXISTS filter_rules;
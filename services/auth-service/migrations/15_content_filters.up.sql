@@ -0,0 +1,36 @@
+-- правила фильтра контента: запрещённые слова и списки доменов; меняются админом без рестарта
+CREATE TABLE IF NOT EXISTS filter_rules
+(
+    id         BIGSERIAL PRIMARY KEY,
+    kind       VARCHAR(16) NOT NULL,
+    pattern    TEXT        NOT NULL,
+    action     VARCHAR(16) NOT NULL DEFAULT '',
+    created_by INTEGER     REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT filter_rules_kind CHECK (kind IN ('word', 'link_deny', 'link_allow')),
+    CONSTRAINT filter_rules_action CHECK (action IN ('', 'reject', 'mask', 'flag'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_filter_rules_kind_pattern ON filter_rules (kind, lower(pattern));
+
+-- общие настройки фильтров; ровно одна строка
+CREATE TABLE IF NOT EXISTS filter_settings
+(
+    id                   SMALLINT PRIMARY KEY DEFAULT 1,
+    max_length           INTEGER     NOT NULL DEFAULT 10000,
+    length_action        VARCHAR(16) NOT NULL DEFAULT 'reject',
+    max_links            INTEGER     NOT NULL DEFAULT 5,
+    link_flood_action    VARCHAR(16) NOT NULL DEFAULT 'flag',
+    unlisted_link_action VARCHAR(16) NOT NULL DEFAULT 'flag',
+    duplicate_window_sec INTEGER     NOT NULL DEFAULT 60,
+    duplicate_action     VARCHAR(16) NOT NULL DEFAULT 'reject',
+    updated_by           INTEGER     REFERENCES users (id) ON DELETE SET NULL,
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT filter_settings_single CHECK (id = 1)
+);
+
+INSERT INTO filter_settings (id) VALUES (1) ON CONFLICT DO NOTHING;
+
+-- жалобы от фильтра контента приходят без автора
+ALTER TABLE reports
+    ALTER COLUMN reporter_id DROP NOT NULL;
@@ -0,0 +1,9 @@
+-- вёдра лимитов частоты действий (token bucket), общие для всех экземпляров chat-service
+CREATE TABLE IF NOT EXISTS rate_limits
+(
+    key        VARCHAR(128)     PRIMARY KEY,
+    tokens     DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ      NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limits_updated ON rate_limits (updated_at);
@@ -0,0 +1,10 @@
+-- упоминания @имя в сообщениях; пересобираются при правке сообщения
+CREATE TABLE IF NOT EXISTS message_mentions
+(
+    message_id INTEGER     NOT NULL REFERENCES messages (id) ON DELETE CASCADE,
+    user_id    INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (message_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_mentions_user ON message_mentions (user_id, created_at DESC);
@@ -0,0 +1,21 @@
+-- уведомления пользователей: ответы, упоминания, цитаты, модерация.
+-- type не ограничен CHECK, а всё, что зависит от типа, лежит в data — новые типы не требуют миграций
+CREATE TABLE IF NOT EXISTS notifications
+(
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    type       VARCHAR(32) NOT NULL,
+    actor_id   INTEGER     REFERENCES users (id) ON DELETE SET NULL,
+    topic_id   INTEGER     REFERENCES topics (id) ON DELETE CASCADE,
+    message_id INTEGER     REFERENCES messages (id) ON DELETE CASCADE,
+    data       JSONB       NOT NULL DEFAULT '{}',
+    read_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications (user_id) WHERE read_at IS NULL;
+
+-- цитируемое сообщение: его автор получает уведомление
+ALTER TABLE messages
+    ADD COLUMN IF NOT EXISTS quote_id INTEGER REFERENCES messages (id) ON DELETE SET NULL;
@@ -0,0 +1,27 @@
+-- подписки на топик или категорию (с подкатегориями); новое в них попадает в email-дайджест
+CREATE TABLE IF NOT EXISTS subscriptions
+(
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    topic_id    INTEGER REFERENCES topics (id) ON DELETE CASCADE,
+    category_id INTEGER REFERENCES categories (id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT subscriptions_one_scope CHECK ((topic_id IS NULL) <> (category_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_user_topic ON subscriptions (user_id, topic_id) WHERE topic_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_user_category ON subscriptions (user_id, category_id) WHERE category_id IS NOT NULL;
+
+-- настройки дайджеста; строка появляется при первой подписке.
+-- unsubscribe_token — для отписки по ссылке из письма без входа
+CREATE TABLE IF NOT EXISTS digest_settings
+(
+    user_id           INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    frequency         VARCHAR(16) NOT NULL DEFAULT 'daily',
+    last_sent_at      TIMESTAMPTZ,
+    unsubscribe_token TEXT        NOT NULL UNIQUE DEFAULT gen_random_uuid()::text,
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT digest_settings_frequency CHECK (frequency IN ('off', 'daily', 'weekly'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_digest_settings_due ON digest_settings (last_sent_at) WHERE frequency <> 'off';
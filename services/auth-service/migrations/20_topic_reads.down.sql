@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_messages_topic_id;
+DROP TABLE IF EXISTS topic_reads;
@@ -0,0 +1,13 @@
+-- позиция чтения: до какого сообщения пользователь дочитал топик. Только растёт.
+-- Непрочитанные считаются лишь в топиках, которые пользователь хоть раз открывал.
+CREATE TABLE IF NOT EXISTS topic_reads
+(
+    user_id              INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    topic_id             INTEGER     NOT NULL REFERENCES topics (id) ON DELETE CASCADE,
+    last_read_message_id INTEGER     NOT NULL DEFAULT 0,
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, topic_id)
+);
+
+-- подсчёт сообщений после позиции
+CREATE INDEX IF NOT EXISTS idx_messages_topic_id ON messages (topic_id, id);
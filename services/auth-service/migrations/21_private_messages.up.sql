@@ -0,0 +1,49 @@
+-- личные переписки: один на один или небольшая группа.
+-- direct_key ("меньший_id:больший_id") не даёт завести вторую переписку один на один между теми же людьми
+CREATE TABLE IF NOT EXISTS conversations
+(
+    id              BIGSERIAL PRIMARY KEY,
+    title           VARCHAR(128) NOT NULL DEFAULT '',
+    is_group        BOOLEAN      NOT NULL DEFAULT FALSE,
+    direct_key      VARCHAR(32) UNIQUE,
+    created_by      INTEGER      REFERENCES users (id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    last_message_at TIMESTAMPTZ,
+    CONSTRAINT conversations_direct_key CHECK (is_group = (direct_key IS NULL))
+);
+
+-- участники; вышедший из группы участник удаляется
+CREATE TABLE IF NOT EXISTS conversation_members
+(
+    conversation_id      BIGINT      NOT NULL REFERENCES conversations (id) ON DELETE CASCADE,
+    user_id              INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    last_read_message_id BIGINT      NOT NULL DEFAULT 0,
+    joined_at            TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (conversation_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_conversation_members_user ON conversation_members (user_id);
+
+CREATE TABLE IF NOT EXISTS private_messages
+(
+    id              BIGSERIAL PRIMARY KEY,
+    conversation_id BIGINT      NOT NULL REFERENCES conversations (id) ON DELETE CASCADE,
+    author_id       INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    content         TEXT        NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    deleted_at      TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_private_messages_conversation ON private_messages (conversation_id, id DESC);
+
+-- кто кому запретил писать себе в личку
+CREATE TABLE IF NOT EXISTS user_blocks
+(
+    user_id         INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    blocked_user_id INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, blocked_user_id),
+    CONSTRAINT user_blocks_not_self CHECK (user_id <> blocked_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_blocks_blocked ON user_blocks (blocked_user_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS room_messages;
+DROP TABLE IF EXISTS rooms;
@@ -0,0 +1,41 @@
+-- комнаты — постоянные общие чаты вне дерева категорий (вместо топика 0 на фронтенде).
+-- Политика хранения как у категорий и топиков; rate_burst/rate_period_seconds — лимит сообщений
+-- одного пользователя в комнате, 0 — только общий лимит на сообщения
+CREATE TABLE IF NOT EXISTS rooms
+(
+    id                  SERIAL PRIMARY KEY,
+    slug                VARCHAR(64)  NOT NULL UNIQUE,
+    title               VARCHAR(255) NOT NULL,
+    description         TEXT         NOT NULL DEFAULT '',
+    position            INTEGER      NOT NULL DEFAULT 0,
+    retention_mode      VARCHAR(16),
+    retention_value     INTEGER,
+    rate_burst          INTEGER      NOT NULL DEFAULT 0,
+    rate_period_seconds INTEGER      NOT NULL DEFAULT 0,
+    created_by          INTEGER      REFERENCES users (id) ON DELETE SET NULL,
+    created_at          TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    CONSTRAINT rooms_retention_check CHECK (
+        retention_mode IS NULL
+        OR retention_mode = 'forever'
+        OR (retention_mode IN ('days', 'last_n') AND retention_value > 0)
+    ),
+    CONSTRAINT rooms_rate_check CHECK (rate_burst >= 0 AND rate_period_seconds >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS room_messages
+(
+    id         BIGSERIAL PRIMARY KEY,
+    room_id    INTEGER     NOT NULL REFERENCES rooms (id) ON DELETE CASCADE,
+    author_id  INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    content    TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_room_messages_room ON room_messages (room_id, id DESC);
+
+-- общий чат, который раньше изображал несуществующий топик 0
+INSERT INTO rooms (slug, title, description)
+VALUES ('general', 'Общий чат', 'Разговоры обо всём')
+ON CONFLICT (slug) DO NOTHING;
@@ -0,0 +1,23 @@
+-- вложения сообщений. Файл загружается заранее и ждёт отправки сообщения (message_id IS NULL);
+-- содержимое и миниатюра лежат в blob-хранилище под storage_key и thumbnail_key.
+-- Строки без сообщения — неотправленные или оставшиеся от удалённых сообщений — cron удаляет
+-- вместе с файлами, поэтому ссылки на пользователя и сообщение при удалении обнуляются, а не каскадят.
+CREATE TABLE IF NOT EXISTS attachments
+(
+    id            BIGSERIAL PRIMARY KEY,
+    uploader_id   INTEGER      REFERENCES users (id) ON DELETE SET NULL,
+    message_id    INTEGER      REFERENCES messages (id) ON DELETE SET NULL,
+    position      SMALLINT     NOT NULL DEFAULT 0,
+    file_name     VARCHAR(255) NOT NULL,
+    content_type  VARCHAR(127) NOT NULL,
+    size          BIGINT       NOT NULL,
+    width         INTEGER,
+    height        INTEGER,
+    storage_key   VARCHAR(255) NOT NULL UNIQUE,
+    thumbnail_key VARCHAR(255),
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    CONSTRAINT attachments_size_check CHECK (size > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_message ON attachments (message_id, position) WHERE message_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_attachments_orphans ON attachments (created_at) WHERE message_id IS NULL;
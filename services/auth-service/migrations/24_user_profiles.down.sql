@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_topics_author;
+DROP INDEX IF EXISTS idx_messages_author;
+DROP TABLE IF EXISTS user_profiles;
@@ -0,0 +1,18 @@
+-- публичный профиль пользователя. Строка появляется при первом изменении профиля, до этого
+-- профиль пустой. Email сюда не копируется: публичные эндпоинты его не показывают.
+-- avatar_key — ключ картинки в blob-хранилище; при смене аватара меняется, чтобы сбросить кэши
+CREATE TABLE IF NOT EXISTS user_profiles
+(
+    user_id           INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    bio               TEXT         NOT NULL DEFAULT '',
+    location          VARCHAR(100) NOT NULL DEFAULT '',
+    website           VARCHAR(255) NOT NULL DEFAULT '',
+    signature         VARCHAR(300) NOT NULL DEFAULT '',
+    avatar_key        VARCHAR(255) UNIQUE,
+    avatar_updated_at TIMESTAMPTZ,
+    updated_at        TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+-- статистика профиля и последняя активность считаются по сообщениям и топикам автора
+CREATE INDEX IF NOT EXISTS idx_messages_author ON messages (author_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_topics_author ON topics (author_id);
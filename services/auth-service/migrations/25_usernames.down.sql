@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS username_reservations;
+DROP INDEX IF EXISTS users_username_lower_key;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS username_changed_at,
+    DROP COLUMN IF EXISTS username;
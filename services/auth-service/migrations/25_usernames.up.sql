@@ -0,0 +1,50 @@
+-- username — уникальный адрес пользователя для упоминаний и ссылок на профиль; name остаётся
+-- произвольным отображаемым именем. Уникальность без учёта регистра: регистр сохраняется для показа.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS username            VARCHAR(32),
+    ADD COLUMN IF NOT EXISTS username_changed_at TIMESTAMPTZ;
+
+-- существующим пользователям адрес строится из имени: латиница, цифры и '_', в нижнем регистре;
+-- если из имени ничего не осталось — user<id>; занятые получают суффикс _2, _3, ...
+DO
+$$
+    DECLARE
+        u    RECORD;
+        base TEXT;
+        cand TEXT;
+        n    INTEGER;
+    BEGIN
+        FOR u IN SELECT id, name FROM users WHERE username IS NULL ORDER BY id
+            LOOP
+                base := trim(BOTH '_' FROM left(trim(BOTH '_' FROM lower(regexp_replace(u.name, '[^A-Za-z0-9_]+', '_', 'g'))), 24));
+                IF length(base) < 3 THEN
+                    base := 'user' || u.id;
+                END IF;
+                cand := base;
+                n := 1;
+                WHILE EXISTS (SELECT 1 FROM users WHERE lower(username) = cand)
+                    LOOP
+                        n := n + 1;
+                        cand := base || '_' || n;
+                    END LOOP;
+                UPDATE users SET username = cand WHERE id = u.id;
+            END LOOP;
+    END
+$$;
+
+ALTER TABLE users
+    ALTER COLUMN username SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_lower_key ON users (lower(username));
+
+-- прежние адреса после переименования: до reserved_until их может снова занять только прежний владелец,
+-- чтобы старые ссылки и упоминания не перешли к другому человеку
+CREATE TABLE IF NOT EXISTS username_reservations
+(
+    username       VARCHAR(32) NOT NULL,
+    user_id        INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    reserved_until TIMESTAMPTZ NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS username_reservations_lower_key ON username_reservations (lower(username));
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS user_reputation;
+ALTER TABLE messages
+    DROP COLUMN IF EXISTS score;
+DROP TABLE IF EXISTS message_votes;
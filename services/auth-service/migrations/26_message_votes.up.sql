@@ -0,0 +1,22 @@
+-- голоса за сообщения: один голос пользователя за сообщение, +1 или -1
+CREATE TABLE IF NOT EXISTS message_votes
+(
+    message_id INTEGER     NOT NULL REFERENCES messages (id) ON DELETE CASCADE,
+    user_id    INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    value      SMALLINT    NOT NULL CHECK (value IN (-1, 1)),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (message_id, user_id)
+);
+
+-- счёт сообщения — сумма голосов за него; меняется вместе с голосом, а не пересчитывается
+ALTER TABLE messages
+    ADD COLUMN IF NOT EXISTS score INTEGER NOT NULL DEFAULT 0;
+
+-- репутация — сумма голосов за сообщения пользователя, тоже меняется на разницу при каждом голосе.
+-- Строка появляется с первым голосом; если сообщение потом удалено очисткой, заработанное за него остаётся
+CREATE TABLE IF NOT EXISTS user_reputation
+(
+    user_id    INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    reputation INTEGER     NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
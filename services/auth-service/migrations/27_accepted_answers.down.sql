@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_topics_accepted_message;
+ALTER TABLE topics
+    DROP COLUMN IF EXISTS accepted_message_id;
+ALTER TABLE categories
+    DROP COLUMN IF EXISTS qa;
@@ -0,0 +1,11 @@
+-- категория вопросов и ответов: в её топиках можно отметить принятый ответ
+ALTER TABLE categories
+    ADD COLUMN IF NOT EXISTS qa BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- принятый ответ топика; топик с ним считается решённым. Очистка сообщения снимает отметку
+ALTER TABLE topics
+    ADD COLUMN IF NOT EXISTS accepted_message_id INTEGER REFERENCES messages (id) ON DELETE SET NULL;
+
+-- очистка сообщений снимает отметку через внешний ключ — без индекса каждое удаление просматривало бы topics
+CREATE INDEX IF NOT EXISTS idx_topics_accepted_message ON topics (accepted_message_id)
+    WHERE accepted_message_id IS NOT NULL;
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_topics_deleted_at;
+DROP INDEX IF EXISTS idx_messages_deleted_at;
+
+ALTER TABLE topics
+    DROP COLUMN IF EXISTS delete_reason,
+    DROP COLUMN IF EXISTS deleted_by,
+    DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE messages
+    DROP COLUMN IF EXISTS delete_reason,
+    DROP COLUMN IF EXISTS deleted_by,
+    DROP COLUMN IF EXISTS deleted_at;
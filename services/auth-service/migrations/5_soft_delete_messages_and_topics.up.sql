@@ -0,0 +1,12 @@
+ALTER TABLE messages
+    ADD COLUMN deleted_at    TIMESTAMPTZ,
+    ADD COLUMN deleted_by    INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    ADD COLUMN delete_reason TEXT;
+
+ALTER TABLE topics
+    ADD COLUMN deleted_at    TIMESTAMPTZ,
+    ADD COLUMN deleted_by    INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    ADD COLUMN delete_reason TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_messages_deleted_at ON messages (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_topics_deleted_at ON topics (deleted_at) WHERE deleted_at IS NOT NULL;
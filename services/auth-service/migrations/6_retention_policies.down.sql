@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_messages_topic_created;
+
+ALTER TABLE topics
+    DROP CONSTRAINT IF EXISTS topics_retention_check,
+    DROP COLUMN IF EXISTS retention_value,
+    DROP COLUMN IF EXISTS retention_mode;
+
+ALTER TABLE categories
+    DROP CONSTRAINT IF EXISTS categories_retention_check,
+    DROP COLUMN IF EXISTS retention_value,
+    DROP COLUMN IF EXISTS retention_mode;
@@ -0,0 +1,19 @@
+ALTER TABLE categories
+    ADD COLUMN retention_mode  VARCHAR(16),
+    ADD COLUMN retention_value INTEGER,
+    ADD CONSTRAINT categories_retention_check CHECK (
+        retention_mode IS NULL
+        OR retention_mode = 'forever'
+        OR (retention_mode IN ('days', 'last_n') AND retention_value > 0)
+    );
+
+ALTER TABLE topics
+    ADD COLUMN retention_mode  VARCHAR(16),
+    ADD COLUMN retention_value INTEGER,
+    ADD CONSTRAINT topics_retention_check CHECK (
+        retention_mode IS NULL
+        OR retention_mode = 'forever'
+        OR (retention_mode IN ('days', 'last_n') AND retention_value > 0)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_messages_topic_created ON messages (topic_id, created_at DESC, id DESC);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_topics_category_pinned;
+
+ALTER TABLE topics
+    DROP COLUMN IF EXISTS locked,
+    DROP COLUMN IF EXISTS pinned;
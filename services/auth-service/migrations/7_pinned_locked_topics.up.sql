@@ -0,0 +1,5 @@
+ALTER TABLE topics
+    ADD COLUMN pinned BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN locked BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_topics_category_pinned ON topics (category_id, pinned DESC, created_at);
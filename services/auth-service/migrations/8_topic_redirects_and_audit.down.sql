@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS topic_audit;
+
+DROP INDEX IF EXISTS idx_topics_redirect_to;
+
+ALTER TABLE topics
+    DROP COLUMN IF EXISTS redirect_to;
@@ -0,0 +1,19 @@
+ALTER TABLE topics
+    ADD COLUMN redirect_to INTEGER REFERENCES topics (id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_topics_redirect_to ON topics (redirect_to) WHERE redirect_to IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS topic_audit
+(
+    id               SERIAL PRIMARY KEY,
+    topic_id         INTEGER     NOT NULL,
+    action           VARCHAR(16) NOT NULL,
+    actor_id         INTEGER     REFERENCES users (id) ON DELETE SET NULL,
+    from_category_id INTEGER,
+    to_category_id   INTEGER,
+    merged_into_id   INTEGER,
+    messages_moved   INTEGER     NOT NULL DEFAULT 0,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_topic_audit_topic ON topic_audit (topic_id, created_at);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_categories_parent_position;
+
+ALTER TABLE categories
+    DROP CONSTRAINT IF EXISTS categories_parent_not_self,
+    DROP COLUMN IF EXISTS position,
+    DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,12 @@
+ALTER TABLE categories
+    ADD COLUMN parent_id INTEGER REFERENCES categories (id) ON DELETE RESTRICT,
+    ADD COLUMN position  INTEGER NOT NULL DEFAULT 0,
+    ADD CONSTRAINT categories_parent_not_self CHECK (parent_id <> id);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_position ON categories (parent_id, position);
+
+-- существующие категории выстраиваются в порядке, в котором их раньше отдавал GET /categories
+UPDATE categories c
+   SET position = o.rn
+  FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY title) - 1 AS rn FROM categories) o
+ WHERE c.id = o.id;
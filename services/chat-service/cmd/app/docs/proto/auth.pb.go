@@ -117,6 +117,354 @@ func (x *VerifyTokenResponse) GetRole() string {
 	return ""
 }
 
+type SetUserRoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUserRoleRequest) Reset() {
+	*x = SetUserRoleRequest{}
+	mi := &file_cmd_app_docs_proto_auth_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserRoleRequest) ProtoMessage() {}
+
+func (x *SetUserRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_app_docs_proto_auth_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserRoleRequest.ProtoReflect.Descriptor instead.
+func (*SetUserRoleRequest) Descriptor() ([]byte, []int) {
+	return file_cmd_app_docs_proto_auth_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SetUserRoleRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *SetUserRoleRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type SetUserRoleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUserRoleResponse) Reset() {
+	*x = SetUserRoleResponse{}
+	mi := &file_cmd_app_docs_proto_auth_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserRoleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserRoleResponse) ProtoMessage() {}
+
+func (x *SetUserRoleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_app_docs_proto_auth_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserRoleResponse.ProtoReflect.Descriptor instead.
+func (*SetUserRoleResponse) Descriptor() ([]byte, []int) {
+	return file_cmd_app_docs_proto_auth_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SetUserRoleResponse) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *SetUserRoleResponse) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type BlockUserRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Reason          string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	DurationSeconds int64                  `protobuf:"varint,3,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *BlockUserRequest) Reset() {
+	*x = BlockUserRequest{}
+	mi := &file_cmd_app_docs_proto_auth_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockUserRequest) ProtoMessage() {}
+
+func (x *BlockUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_app_docs_proto_auth_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockUserRequest.ProtoReflect.Descriptor instead.
+func (*BlockUserRequest) Descriptor() ([]byte, []int) {
+	return file_cmd_app_docs_proto_auth_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BlockUserRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *BlockUserRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *BlockUserRequest) GetDurationSeconds() int64 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+type BlockUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockUserResponse) Reset() {
+	*x = BlockUserResponse{}
+	mi := &file_cmd_app_docs_proto_auth_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockUserResponse) ProtoMessage() {}
+
+func (x *BlockUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_app_docs_proto_auth_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockUserResponse.ProtoReflect.Descriptor instead.
+func (*BlockUserResponse) Descriptor() ([]byte, []int) {
+	return file_cmd_app_docs_proto_auth_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BlockUserResponse) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type ResolveUsernamesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Usernames     []string               `protobuf:"bytes,1,rep,name=usernames,proto3" json:"usernames,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveUsernamesRequest) Reset() {
+	*x = ResolveUsernamesRequest{}
+	mi := &file_cmd_app_docs_proto_auth_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveUsernamesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveUsernamesRequest) ProtoMessage() {}
+
+func (x *ResolveUsernamesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_app_docs_proto_auth_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveUsernamesRequest.ProtoReflect.Descriptor instead.
+func (*ResolveUsernamesRequest) Descriptor() ([]byte, []int) {
+	return file_cmd_app_docs_proto_auth_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ResolveUsernamesRequest) GetUsernames() []string {
+	if x != nil {
+		return x.Usernames
+	}
+	return nil
+}
+
+type ResolvedUser struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolvedUser) Reset() {
+	*x = ResolvedUser{}
+	mi := &file_cmd_app_docs_proto_auth_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolvedUser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolvedUser) ProtoMessage() {}
+
+func (x *ResolvedUser) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_app_docs_proto_auth_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolvedUser.ProtoReflect.Descriptor instead.
+func (*ResolvedUser) Descriptor() ([]byte, []int) {
+	return file_cmd_app_docs_proto_auth_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ResolvedUser) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ResolvedUser) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type ResolveUsernamesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*ResolvedUser        `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveUsernamesResponse) Reset() {
+	*x = ResolveUsernamesResponse{}
+	mi := &file_cmd_app_docs_proto_auth_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveUsernamesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveUsernamesResponse) ProtoMessage() {}
+
+func (x *ResolveUsernamesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_app_docs_proto_auth_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveUsernamesResponse.ProtoReflect.Descriptor instead.
+func (*ResolveUsernamesResponse) Descriptor() ([]byte, []int) {
+	return file_cmd_app_docs_proto_auth_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ResolveUsernamesResponse) GetUsers() []*ResolvedUser {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
 var File_cmd_app_docs_proto_auth_proto protoreflect.FileDescriptor
 
 const file_cmd_app_docs_proto_auth_proto_rawDesc = "" +
@@ -126,9 +474,31 @@ const file_cmd_app_docs_proto_auth_proto_rawDesc = "" +
 	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\"B\n" +
 	"\x13VerifyTokenResponse\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x12\n" +
-	"\x04role\x18\x02 \x01(\tR\x04role2S\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\"A\n" +
+	"\x12SetUserRoleRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\"B\n" +
+	"\x13SetUserRoleResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\"n\n" +
+	"\x10BlockUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12)\n" +
+	"\x10duration_seconds\x18\x03 \x01(\x03R\x0fdurationSeconds\",\n" +
+	"\x11BlockUserResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\"7\n" +
+	"\x17ResolveUsernamesRequest\x12\x1c\n" +
+	"\tusernames\x18\x01 \x03(\tR\tusernames\":\n" +
+	"\fResolvedUser\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\"E\n" +
+	"\x18ResolveUsernamesResponse\x12)\n" +
+	"\x05users\x18\x01 \x03(\v2\x13.proto.ResolvedUserR\x05users2\xae\x02\n" +
 	"\vAuthService\x12D\n" +
-	"\vVerifyToken\x12\x19.proto.VerifyTokenRequest\x1a\x1a.proto.VerifyTokenResponseB\x19Z\x17auth-service/docs/protob\x06proto3"
+	"\vVerifyToken\x12\x19.proto.VerifyTokenRequest\x1a\x1a.proto.VerifyTokenResponse\x12D\n" +
+	"\vSetUserRole\x12\x19.proto.SetUserRoleRequest\x1a\x1a.proto.SetUserRoleResponse\x12>\n" +
+	"\tBlockUser\x12\x17.proto.BlockUserRequest\x1a\x18.proto.BlockUserResponse\x12S\n" +
+	"\x10ResolveUsernames\x12\x1e.proto.ResolveUsernamesRequest\x1a\x1f.proto.ResolveUsernamesResponseB\x19Z\x17auth-service/docs/protob\x06proto3"
 
 var (
 	file_cmd_app_docs_proto_auth_proto_rawDescOnce sync.Once
@@ -142,19 +512,33 @@ func file_cmd_app_docs_proto_auth_proto_rawDescGZIP() []byte {
 	return file_cmd_app_docs_proto_auth_proto_rawDescData
 }
 
-var file_cmd_app_docs_proto_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_cmd_app_docs_proto_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_cmd_app_docs_proto_auth_proto_goTypes = []any{
-	(*VerifyTokenRequest)(nil),  // 0: proto.VerifyTokenRequest
-	(*VerifyTokenResponse)(nil), // 1: proto.VerifyTokenResponse
+	(*VerifyTokenRequest)(nil),       // 0: proto.VerifyTokenRequest
+	(*VerifyTokenResponse)(nil),      // 1: proto.VerifyTokenResponse
+	(*SetUserRoleRequest)(nil),       // 2: proto.SetUserRoleRequest
+	(*SetUserRoleResponse)(nil),      // 3: proto.SetUserRoleResponse
+	(*BlockUserRequest)(nil),         // 4: proto.BlockUserRequest
+	(*BlockUserResponse)(nil),        // 5: proto.BlockUserResponse
+	(*ResolveUsernamesRequest)(nil),  // 6: proto.ResolveUsernamesRequest
+	(*ResolvedUser)(nil),             // 7: proto.ResolvedUser
+	(*ResolveUsernamesResponse)(nil), // 8: proto.ResolveUsernamesResponse
 }
 var file_cmd_app_docs_proto_auth_proto_depIdxs = []int32{
-	0, // 0: proto.AuthService.VerifyToken:input_type -> proto.VerifyTokenRequest
-	1, // 1: proto.AuthService.VerifyToken:output_type -> proto.VerifyTokenResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	7, // 0: proto.ResolveUsernamesResponse.users:type_name -> proto.ResolvedUser
+	0, // 1: proto.AuthService.VerifyToken:input_type -> proto.VerifyTokenRequest
+	2, // 2: proto.AuthService.SetUserRole:input_type -> proto.SetUserRoleRequest
+	4, // 3: proto.AuthService.BlockUser:input_type -> proto.BlockUserRequest
+	6, // 4: proto.AuthService.ResolveUsernames:input_type -> proto.ResolveUsernamesRequest
+	1, // 5: proto.AuthService.VerifyToken:output_type -> proto.VerifyTokenResponse
+	3, // 6: proto.AuthService.SetUserRole:output_type -> proto.SetUserRoleResponse
+	5, // 7: proto.AuthService.BlockUser:output_type -> proto.BlockUserResponse
+	8, // 8: proto.AuthService.ResolveUsernames:output_type -> proto.ResolveUsernamesResponse
+	5, // [5:9] is the sub-list for method output_type
+	1, // [1:5] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_cmd_app_docs_proto_auth_proto_init() }
@@ -168,7 +552,7 @@ func file_cmd_app_docs_proto_auth_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_cmd_app_docs_proto_auth_proto_rawDesc), len(file_cmd_app_docs_proto_auth_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -1,20 +1,30 @@
 package config
 
 import (
+	"chat-service/internal/ratelimit"
 	"fmt"
 	"github.com/caarlos0/env/v11"
+	"time"
 )
 
 type (
 	// Config -.
 	Config struct {
-		App      App
-		HTTP     HTTP
-		Log      Log
-		PG       PG
-		Swagger  Swagger
-		AuthGRPC AuthGRPC
-		Cleanup  Cleanup
+		App         App
+		HTTP        HTTP
+		Log         Log
+		PG          PG
+		Swagger     Swagger
+		AuthGRPC    AuthGRPC
+		Cleanup     Cleanup
+		Reports     Reports
+		Filters     Filters
+		RateLimit   RateLimit
+		Digest      Digest
+		Mail        Mail
+		Attachments Attachments
+		Profiles    Profiles
+		Reputation  Reputation
 	}
 
 	// App -.
@@ -53,7 +63,100 @@ type (
 
 	Cleanup struct {
 		Cron     string `env:"CLEANUP_CRON"`
-		HoursAgo int    `env:"CLEANUP_THRESHOLD_HOURS"`
+		HoursAgo int    `env:"CLEANUP_THRESHOLD_HOURS"` // глобальный порог для категорий и топиков без своей политики хранения
+		// DryRun — cron только считает и логирует, что было бы удалено.
+		DryRun    bool `env:"CLEANUP_DRY_RUN" envDefault:"false"`
+		BatchSize int  `env:"CLEANUP_BATCH_SIZE" envDefault:"1000"`
+		// TombstoneRetentionHours — сколько часов мягко удалённые сообщения и топики
+		// можно восстановить; после этого cron удаляет их окончательно.
+		TombstoneRetentionHours int `env:"TOMBSTONE_RETENTION_HOURS" envDefault:"720"`
+		// OrphanAttachmentHours — через сколько часов удаляются вложения, так и не отправленные с сообщением
+		OrphanAttachmentHours int `env:"ORPHAN_ATTACHMENT_HOURS" envDefault:"24"`
+	}
+
+	Reports struct {
+		// HideAfter — после стольких открытых жалоб объект скрывается до решения модератора; 0 — не скрывать.
+		HideAfter int `env:"REPORT_HIDE_AFTER" envDefault:"3"`
+	}
+
+	Filters struct {
+		// ReloadInterval — как часто перечитывать правила фильтра контента, изменённые другими экземплярами сервиса
+		ReloadInterval time.Duration `env:"FILTER_RELOAD_INTERVAL" envDefault:"1m"`
+	}
+
+	// RateLimit — лимиты вида "10/1m" (10 действий в минуту с запасом на всплеск); "0" — без лимита.
+	// *Restricted — для аккаунтов моложе NewAccountAge или с числом сообщений меньше MinPosts.
+	RateLimit struct {
+		Store         string        `env:"RATE_LIMIT_STORE" envDefault:"memory"` // memory или postgres (общий для нескольких экземпляров)
+		NewAccountAge time.Duration `env:"RATE_LIMIT_NEW_ACCOUNT_AGE" envDefault:"72h"`
+		MinPosts      int64         `env:"RATE_LIMIT_MIN_POSTS" envDefault:"5"`
+		MinReputation int64         `env:"RATE_LIMIT_MIN_REPUTATION" envDefault:"0"`
+
+		Message           ratelimit.Limit `env:"RATE_LIMIT_MESSAGE" envDefault:"10/1m"`
+		MessageRestricted ratelimit.Limit `env:"RATE_LIMIT_MESSAGE_RESTRICTED" envDefault:"3/1m"`
+		MessageIP         ratelimit.Limit `env:"RATE_LIMIT_MESSAGE_IP" envDefault:"30/1m"`
+		Topic             ratelimit.Limit `env:"RATE_LIMIT_TOPIC" envDefault:"3/10m"`
+		TopicRestricted   ratelimit.Limit `env:"RATE_LIMIT_TOPIC_RESTRICTED" envDefault:"1/10m"`
+		TopicIP           ratelimit.Limit `env:"RATE_LIMIT_TOPIC_IP" envDefault:"10/10m"`
+		Edit              ratelimit.Limit `env:"RATE_LIMIT_EDIT" envDefault:"20/1m"`
+		EditRestricted    ratelimit.Limit `env:"RATE_LIMIT_EDIT_RESTRICTED" envDefault:"5/1m"`
+		EditIP            ratelimit.Limit `env:"RATE_LIMIT_EDIT_IP" envDefault:"60/1m"`
+	}
+
+	// Digest — email-дайджесты по подпискам. Cron проверяет, кому пора отправить дайджест, поэтому
+	// запускать его стоит часто: частоту (daily, weekly) каждый пользователь выбирает сам.
+	Digest struct {
+		Cron             string `env:"DIGEST_CRON" envDefault:"0 * * * *"`
+		PublicURL        string `env:"DIGEST_PUBLIC_URL" envDefault:"http://localhost:8081"` // адрес chat-service для ссылок отписки
+		SiteURL          string `env:"DIGEST_SITE_URL" envDefault:"http://localhost:5173"`   // адрес фронтенда для ссылок на топики
+		MaxTopics        int    `env:"DIGEST_MAX_TOPICS" envDefault:"20"`
+		MessagesPerTopic int    `env:"DIGEST_MESSAGES_PER_TOPIC" envDefault:"3"`
+	}
+
+	// Mail — как отправляются письма: smtp или file (в каталог FileDir, для разработки)
+	Mail struct {
+		Driver       string `env:"MAIL_DRIVER" envDefault:"file"`
+		From         string `env:"MAIL_FROM" envDefault:"Forum <noreply@localhost>"`
+		SMTPHost     string `env:"SMTP_HOST"`
+		SMTPPort     string `env:"SMTP_PORT" envDefault:"587"`
+		SMTPUser     string `env:"SMTP_USER"`
+		SMTPPassword string `env:"SMTP_PASSWORD"`
+		FileDir      string `env:"MAIL_FILE_DIR" envDefault:"logs/mail"`
+	}
+
+	// Attachments — вложения сообщений. Storage: local (каталог Dir, для одного экземпляра и разработки)
+	// или s3 (AWS S3, MinIO и другие совместимые; для MinIO нужен S3_PATH_STYLE=true).
+	// Types — разрешённые MIME-типы; тип определяется по содержимому файла, а не по имени.
+	Attachments struct {
+		Storage       string   `env:"ATTACHMENT_STORAGE" envDefault:"local"`
+		Dir           string   `env:"ATTACHMENT_DIR" envDefault:"data/attachments"`
+		MaxSize       int64    `env:"ATTACHMENT_MAX_SIZE" envDefault:"10485760"` // байт
+		MaxPerMessage int      `env:"ATTACHMENT_MAX_PER_MESSAGE" envDefault:"10"`
+		MaxPending    int      `env:"ATTACHMENT_MAX_PENDING" envDefault:"20"`
+		Types         []string `env:"ATTACHMENT_TYPES" envDefault:"image/jpeg,image/png,image/gif,image/webp,application/pdf,text/plain,application/zip"`
+		ThumbnailSize int      `env:"ATTACHMENT_THUMBNAIL_SIZE" envDefault:"320"`
+
+		S3Endpoint  string `env:"S3_ENDPOINT"`
+		S3Region    string `env:"S3_REGION" envDefault:"us-east-1"`
+		S3Bucket    string `env:"S3_BUCKET"`
+		S3AccessKey string `env:"S3_ACCESS_KEY"`
+		S3SecretKey string `env:"S3_SECRET_KEY"`
+		S3PathStyle bool   `env:"S3_PATH_STYLE" envDefault:"false"`
+	}
+
+	// Profiles — публичные профили. Аватары лежат в хранилище вложений (ATTACHMENT_STORAGE).
+	Profiles struct {
+		AvatarMaxSize  int64 `env:"AVATAR_MAX_SIZE" envDefault:"2097152"` // байт в загружаемом файле
+		AvatarSize     int   `env:"AVATAR_SIZE" envDefault:"256"`         // сторона квадрата в пикселях
+		RecentActivity int   `env:"PROFILE_RECENT_ACTIVITY" envDefault:"10"`
+	}
+
+	// Reputation — сколько репутации нужно для действия; 0 — доступно всем.
+	// Модераторов и администраторов пороги не касаются.
+	Reputation struct {
+		CreateTopic int64 `env:"REPUTATION_CREATE_TOPIC" envDefault:"0"`
+		PostLinks   int64 `env:"REPUTATION_POST_LINKS" envDefault:"0"`
+		Downvote    int64 `env:"REPUTATION_DOWNVOTE" envDefault:"0"`
 	}
 )
 
@@ -3,10 +3,15 @@ package app
 import (
 	authpb "chat-service/cmd/app/docs/proto"
 	"chat-service/config"
+	"chat-service/internal/blob"
 	httpd "chat-service/internal/controller/http"
 	wsCtrl "chat-service/internal/controller/ws"
 	cronjob "chat-service/internal/cron"
+	"chat-service/internal/entity"
+	"chat-service/internal/mailer"
+	"chat-service/internal/ratelimit"
 	"chat-service/internal/repo"
+	"chat-service/internal/repo/webapi"
 	"chat-service/internal/usecase"
 	"context"
 	"errors"
@@ -19,6 +24,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 )
@@ -45,15 +51,46 @@ func Run(cfg *config.Config) {
 	catRepo := repo.NewCategoryRepo(pg)
 	topicRepo := repo.NewTopicRepo(pg)
 	msgRepo := repo.NewMessageRepo(pg)
+	modRepo := repo.NewModeratorRepo(pg)
+	modLogRepo := repo.NewModerationRepo(pg)
+	reportRepo := repo.NewReportRepo(pg)
+	muteRepo := repo.NewMuteRepo(pg)
+	filterRepo := repo.NewFilterRepo(pg)
+	userRepo := repo.NewUserRepo(pg)
+	mentionRepo := repo.NewMentionRepo(pg)
+	notifRepo := repo.NewNotificationRepo(pg)
+	subRepo := repo.NewSubscriptionRepo(pg)
+	readRepo := repo.NewReadRepo(pg)
+	convRepo := repo.NewConversationRepo(pg)
+	roomRepo := repo.NewRoomRepo(pg)
+	attRepo := repo.NewAttachmentRepo(pg)
+	profileRepo := repo.NewProfileRepo(pg)
+
+	// лимиты в памяти годятся для одного экземпляра; несколько экземпляров делят их через базу
+	var limitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.RateLimit.Store == "postgres" {
+		limitStore = repo.NewRateLimitRepo(pg)
+	}
 
 	// Use-cases
 	hub := wsCtrl.NewHub()
+	retention := time.Duration(cfg.Cleanup.TombstoneRetentionHours) * time.Hour
 	catUC := usecase.NewCategoryUsecase(catRepo, l)
-	topicUC := usecase.NewTopicUsecase(topicRepo, l)
-	msgUC := usecase.NewMessageUsecase(msgRepo, hub, l)
-
-	cleanupCron := cronjob.NewCleanupCron(l, msgUC)
-	cleanupCron.Start(cfg.Cleanup.Cron, cfg.Cleanup.HoursAgo)
+	notifUC := usecase.NewNotificationUsecase(notifRepo, hub, l)
+	filterUC := usecase.NewFilterUsecase(filterRepo, msgRepo, reportRepo, l, cfg.Filters.ReloadInterval)
+	voteRepo := repo.NewVoteRepo(pg)
+	repUC := usecase.NewReputationUsecase(voteRepo, reputationPolicy(cfg.Reputation), l)
+	topicUC := usecase.NewTopicUsecase(topicRepo, modRepo, muteRepo, modLogRepo, filterUC, repUC, notifUC, hub, l, retention)
+	modUC := usecase.NewModerationUsecase(modLogRepo, l)
+	muteUC := usecase.NewMuteUsecase(muteRepo, topicRepo, modRepo, modLogRepo, notifUC, l)
+	rateUC := usecase.NewRateLimitUsecase(limitStore, userRepo, rateLimitPolicy(cfg.RateLimit), l)
+	subUC := usecase.NewSubscriptionUsecase(subRepo, topicRepo, newMailer(cfg.Mail, l), digestOptions(cfg.Digest), l)
+	readUC := usecase.NewReadUsecase(readRepo, topicRepo, hub, l)
+	convUC := usecase.NewConversationUsecase(convRepo, filterUC, repUC, hub, l)
+	roomUC := usecase.NewRoomUsecase(roomRepo, filterUC, repUC, rateUC, hub, l)
+	blobStore := newBlobStore(cfg.Attachments, l)
+	attUC := usecase.NewAttachmentUsecase(attRepo, blobStore, attachmentPolicy(cfg.Attachments), l)
+	profileUC := usecase.NewProfileUsecase(profileRepo, blobStore, filterUC, profileOptions(cfg.Profiles), l)
 
 	// gRPC auth-service connection
 	authAddr := fmt.Sprintf("%s:%s", cfg.AuthGRPC.Host, cfg.AuthGRPC.Port)
@@ -92,9 +129,27 @@ func Run(cfg *config.Config) {
 	}
 
 	authClient := authpb.NewAuthServiceClient(conn)
+	authAPI := webapi.NewAuthGRPC(authClient)
+	reportUC := usecase.NewReportUsecase(reportRepo, msgRepo, topicRepo, modRepo, modLogRepo,
+		authAPI, notifUC, hub, l, cfg.Reports.HideAfter)
+	mentionUC := usecase.NewMentionUsecase(mentionRepo, authAPI, notifUC, l)
+	msgUC := usecase.NewMessageUsecase(msgRepo, topicRepo, modRepo, muteRepo, modLogRepo, usecase.MessageDeps{
+		Content:    filterUC,
+		Privileges: repUC,
+		Mentions:   mentionUC,
+		Files:      attUC,
+		Notifier:   notifUC,
+		Publisher:  hub,
+	}, l, retention)
+	voteUC := usecase.NewVoteUsecase(voteRepo, msgRepo, topicRepo, repUC, l)
+
+	cleanupCron := cronjob.NewCleanupCron(l, msgUC, topicUC, muteUC, rateUC, roomUC, attUC)
+	cleanupCron.Start(cfg.Cleanup)
+	digestCron := cronjob.NewDigestCron(l, subUC)
+	digestCron.Start(cfg.Digest)
 
 	// Router
-	router := httpd.NewRouter(l, catUC, topicUC, msgUC, hub, authClient, cfg)
+	router := httpd.NewRouter(l, catUC, topicUC, msgUC, modUC, reportUC, muteUC, filterUC, rateUC, mentionUC, notifUC, subUC, readUC, convUC, roomUC, attUC, profileUC, voteUC, hub, authClient, cfg)
 
 	// HTTP Server
 	srv := &http.Server{
@@ -128,3 +183,90 @@ func Run(cfg *config.Config) {
 		l.Info("server stopped gracefully")
 	}
 }
+
+// rateLimitPolicy раскладывает лимиты из конфига по действиям
+func rateLimitPolicy(cfg config.RateLimit) usecase.RateLimitPolicy {
+	return usecase.RateLimitPolicy{
+		Actions: map[string]usecase.ActionLimits{
+			usecase.ActionMessage: {User: cfg.Message, Restricted: cfg.MessageRestricted, IP: cfg.MessageIP},
+			usecase.ActionTopic:   {User: cfg.Topic, Restricted: cfg.TopicRestricted, IP: cfg.TopicIP},
+			usecase.ActionEdit:    {User: cfg.Edit, Restricted: cfg.EditRestricted, IP: cfg.EditIP},
+		},
+		NewAccountAge: cfg.NewAccountAge,
+		MinPosts:      cfg.MinPosts,
+		MinReputation: cfg.MinReputation,
+	}
+}
+
+// reputationPolicy сопоставляет пороги из конфига привилегиям
+func reputationPolicy(cfg config.Reputation) usecase.ReputationPolicy {
+	return usecase.ReputationPolicy{
+		Thresholds: map[string]int64{
+			entity.PrivilegeCreateTopic: cfg.CreateTopic,
+			entity.PrivilegePostLinks:   cfg.PostLinks,
+			entity.PrivilegeDownvote:    cfg.Downvote,
+		},
+	}
+}
+
+// newMailer выбирает способ отправки писем: SMTP или файлы в каталоге (для разработки)
+func newMailer(cfg config.Mail, l logger.Interface) mailer.Mailer {
+	if cfg.Driver == "smtp" {
+		return mailer.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPassword, cfg.From)
+	}
+	m, err := mailer.NewFileMailer(cfg.FileDir, cfg.From)
+	if err != nil {
+		l.Fatal("failed to init file mailer", "dir", cfg.FileDir, "err", err)
+	}
+	return m
+}
+
+// newBlobStore выбирает хранилище вложений и аватаров: S3-совместимое или каталог на диске
+func newBlobStore(cfg config.Attachments, l logger.Interface) blob.Store {
+	if cfg.Storage == "s3" {
+		s, err := blob.NewS3Store(blob.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PathStyle: cfg.S3PathStyle,
+		})
+		if err != nil {
+			l.Fatal("failed to init s3 storage", "err", err)
+		}
+		return s
+	}
+	s, err := blob.NewLocalStore(cfg.Dir)
+	if err != nil {
+		l.Fatal("failed to init attachment storage", "dir", cfg.Dir, "err", err)
+	}
+	return s
+}
+
+func attachmentPolicy(cfg config.Attachments) usecase.AttachmentPolicy {
+	return usecase.AttachmentPolicy{
+		MaxSize:       cfg.MaxSize,
+		MaxPerMessage: cfg.MaxPerMessage,
+		MaxPending:    cfg.MaxPending,
+		Types:         cfg.Types,
+		ThumbnailSize: cfg.ThumbnailSize,
+	}
+}
+
+func profileOptions(cfg config.Profiles) usecase.ProfileOptions {
+	return usecase.ProfileOptions{
+		AvatarMaxSize:  cfg.AvatarMaxSize,
+		AvatarSize:     cfg.AvatarSize,
+		RecentActivity: cfg.RecentActivity,
+	}
+}
+
+func digestOptions(cfg config.Digest) usecase.DigestOptions {
+	return usecase.DigestOptions{
+		PublicURL:        strings.TrimRight(cfg.PublicURL, "/"),
+		SiteURL:          strings.TrimRight(cfg.SiteURL, "/"),
+		MaxTopics:        cfg.MaxTopics,
+		MessagesPerTopic: cfg.MessagesPerTopic,
+	}
+}
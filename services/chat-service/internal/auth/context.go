@@ -27,3 +27,16 @@ func FromContext(ctx context.Context) (userID int64, role string) {
 	}
 	return
 }
+
+type tokenKey struct{}
+
+// WithToken кладёт access-токен запроса в контекст, чтобы вызывать auth-service от имени пользователя
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+// TokenFromContext возвращает access-токен запроса или пустую строку
+func TokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(tokenKey{}).(string)
+	return token
+}
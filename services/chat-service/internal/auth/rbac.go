@@ -0,0 +1,68 @@
+package auth
+
+// Роли пользователей (значение users.role и claim role в JWT)
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Permission — именованное право; проверяется в usecase вместо сравнения ролей
+type Permission string
+
+const (
+	PermTopicWrite        Permission = "topic.write"         // создавать топики, править и удалять свои
+	PermTopicModerate     Permission = "topic.moderate"      // править и удалять чужие топики, закреплять, закрывать
+	PermTopicMove         Permission = "topic.move"          // переносить и сливать топики
+	PermMessageWrite      Permission = "message.write"       // писать сообщения, править и удалять свои
+	PermMessageModerate   Permission = "message.moderate"    // удалять чужие сообщения, видеть удалённые, писать в закрытые топики
+	PermContentRestore    Permission = "content.restore"     // восстанавливать удалённые топики и сообщения, смотреть корзину
+	PermCategoryManage    Permission = "category.manage"     // создавать, править, удалять и переставлять категории
+	PermRetentionManage   Permission = "retention.manage"    // политики хранения и предпросмотр очистки
+	PermModerationLogView Permission = "moderation_log.view" // читать журнал модерации целиком
+	PermContentReport     Permission = "content.report"      // жаловаться на сообщения и топики
+	PermReportReview      Permission = "report.review"       // разбирать очередь жалоб
+	PermUserMute          Permission = "user.mute"           // запрещать пользователю писать в топике или категории
+	PermUserBlock         Permission = "user.block"          // блокировать на всём форуме; выполняет auth-service, там право только у admin
+	PermFilterManage      Permission = "filter.manage"       // править правила и настройки фильтра контента
+	PermConversationWrite Permission = "conversation.write"  // начинать личные переписки и писать в них
+	PermRoomWrite         Permission = "room.write"          // писать в комнаты, править и удалять свои сообщения
+	PermRoomModerate      Permission = "room.moderate"       // удалять чужие сообщения в комнатах
+	PermRoomManage        Permission = "room.manage"         // создавать, настраивать и удалять комнаты
+)
+
+// Scope — где действует право
+type Scope int
+
+const (
+	ScopeNone     Scope = iota // права нет
+	ScopeCategory              // только в категориях, назначенных модератору (и их подкатегориях)
+	ScopeGlobal                // везде
+)
+
+// policy — таблица прав: право → роль → область действия. Чего нет в таблице, то запрещено.
+var policy = map[Permission]map[string]Scope{
+	PermTopicWrite:        {RoleUser: ScopeGlobal, RoleModerator: ScopeGlobal, RoleAdmin: ScopeGlobal},
+	PermMessageWrite:      {RoleUser: ScopeGlobal, RoleModerator: ScopeGlobal, RoleAdmin: ScopeGlobal},
+	PermTopicModerate:     {RoleModerator: ScopeCategory, RoleAdmin: ScopeGlobal},
+	PermMessageModerate:   {RoleModerator: ScopeCategory, RoleAdmin: ScopeGlobal},
+	PermTopicMove:         {RoleAdmin: ScopeGlobal},
+	PermContentRestore:    {RoleAdmin: ScopeGlobal},
+	PermCategoryManage:    {RoleAdmin: ScopeGlobal},
+	PermRetentionManage:   {RoleAdmin: ScopeGlobal},
+	PermModerationLogView: {RoleAdmin: ScopeGlobal},
+	PermContentReport:     {RoleUser: ScopeGlobal, RoleModerator: ScopeGlobal, RoleAdmin: ScopeGlobal},
+	PermReportReview:      {RoleModerator: ScopeCategory, RoleAdmin: ScopeGlobal},
+	PermUserMute:          {RoleModerator: ScopeCategory, RoleAdmin: ScopeGlobal},
+	PermUserBlock:         {RoleAdmin: ScopeGlobal},
+	PermFilterManage:      {RoleAdmin: ScopeGlobal},
+	PermConversationWrite: {RoleUser: ScopeGlobal, RoleModerator: ScopeGlobal, RoleAdmin: ScopeGlobal},
+	PermRoomWrite:         {RoleUser: ScopeGlobal, RoleModerator: ScopeGlobal, RoleAdmin: ScopeGlobal},
+	PermRoomModerate:      {RoleModerator: ScopeGlobal, RoleAdmin: ScopeGlobal}, // комнаты вне категорий — модератор следит за всеми
+	PermRoomManage:        {RoleAdmin: ScopeGlobal},
+}
+
+// ScopeOf возвращает область действия права perm для роли role
+func ScopeOf(role string, perm Permission) Scope {
+	return policy[perm][role]
+}
@@ -0,0 +1,35 @@
+// Package blob — хранилище файлов (вложений и их миниатюр) по ключу: локальный каталог
+// или S3-совместимый сервис (AWS S3, MinIO). Метаданные файлов хранятся в базе, здесь — только содержимое.
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+var ErrNotFound = errors.New("blob: not found")
+
+type Store interface {
+	// Put сохраняет size байт из r под ключом key; существующий объект перезаписывается.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open открывает объект на чтение; объекта нет — ErrNotFound.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete удаляет объект; отсутствие объекта ошибкой не считается.
+	Delete(ctx context.Context, key string) error
+}
+
+// validKey — ключ из сегментов через "/", без пустых сегментов, "." и "..":
+// такой ключ не выходит за пределы каталога LocalStore и одинаково понимается S3.
+func validKey(key string) bool {
+	if key == "" || len(key) > 1024 {
+		return false
+	}
+	for _, seg := range strings.Split(key, "/") {
+		if seg == "" || seg == "." || seg == ".." || strings.ContainsAny(seg, "\\\x00") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,202 @@
+package blob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// S3Config — подключение к S3-совместимому хранилищу.
+// Для MinIO и других самостоятельно развёрнутых сервисов обычно нужен PathStyle:
+// адрес вида http://minio:9000/bucket/key вместо http://bucket.minio:9000/key.
+type S3Config struct {
+	Endpoint  string // например "https://s3.eu-central-1.amazonaws.com" или "http://localhost:9000"
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool
+}
+
+// S3Store работает с объектами через REST API S3 с подписью AWS Signature Version 4.
+// Тело запросов не подписывается (UNSIGNED-PAYLOAD), поэтому файл не нужно читать дважды.
+type S3Store struct {
+	endpoint *url.URL
+	cfg      S3Config
+	client   *http.Client
+}
+
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	u, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("S3Store: invalid endpoint %q", cfg.Endpoint)
+	}
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("S3Store: bucket is required")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	return &S3Store{endpoint: u, cfg: cfg, client: &http.Client{Timeout: time.Minute}}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	const op = "S3Store.Put"
+
+	resp, err := s.do(ctx, http.MethodPut, key, r, size, contentType)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer drain(resp)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %w", op, responseError(resp))
+	}
+	return nil
+}
+
+func (s *S3Store) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	const op = "S3Store.Open"
+
+	resp, err := s.do(ctx, http.MethodGet, key, nil, 0, "")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		drain(resp)
+		return nil, fmt.Errorf("%s: %w", op, ErrNotFound)
+	default:
+		defer drain(resp)
+		return nil, fmt.Errorf("%s: %w", op, responseError(resp))
+	}
+}
+
+// Delete — S3 отвечает 204 и на удаление несуществующего объекта
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	const op = "S3Store.Delete"
+
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, 0, "")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer drain(resp)
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("%s: %w", op, responseError(resp))
+	}
+	return nil
+}
+
+func (s *S3Store) do(ctx context.Context, method, key string, body io.Reader, size int64, contentType string) (*http.Response, error) {
+	if !validKey(key) {
+		return nil, fmt.Errorf("invalid key %q", key)
+	}
+	u := *s.endpoint
+	path := "/" + key
+	if s.cfg.PathStyle {
+		path = "/" + s.cfg.Bucket + path
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+	}
+	u.Path = u.Path + path
+	u.RawPath = s.endpoint.EscapedPath() + escapePath(path)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+	}
+	s.sign(req, time.Now().UTC())
+	return s.client.Do(req)
+}
+
+// sign добавляет заголовки подписи AWS Signature Version 4.
+// Подписываются host, x-amz-content-sha256 и x-amz-date — этого достаточно для S3 и MinIO.
+func (s *S3Store) sign(req *http.Request, now time.Time) {
+	const (
+		algorithm     = "AWS4-HMAC-SHA256"
+		payload       = "UNSIGNED-PAYLOAD"
+		signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	)
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payload)
+
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payload,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payload,
+	}, "\n")
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	hash := sha256.Sum256([]byte(canonical))
+	toSign := algorithm + "\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), date)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	req.Header.Set("Authorization", algorithm+" Credential="+s.cfg.AccessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// escapePath кодирует путь так, как его ожидает подпись S3: всё, кроме A-Z a-z 0-9 - _ . ~ и "/", — в %XX
+func escapePath(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// responseError достаёт из ответа S3 код ошибки (тело — XML с <Code>)
+func responseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	code := ""
+	if _, rest, ok := strings.Cut(string(body), "<Code>"); ok {
+		code, _, _ = strings.Cut(rest, "</Code>")
+	}
+	if code == "" {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return fmt.Errorf("unexpected status %s: %s", resp.Status, code)
+}
+
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,221 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"chat-service/internal/entity"
+	"chat-service/internal/media"
+	"chat-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead — запас на заголовки multipart сверх самого файла
+const multipartOverhead = 64 << 10
+
+type AttachmentHandler struct {
+	uc      usecase.AttachmentUsecase
+	maxSize int64 // предел тела запроса на загрузку; точный лимит на файл проверяет usecase
+}
+
+func NewAttachmentHandler(uc usecase.AttachmentUsecase, maxSize int64) *AttachmentHandler {
+	return &AttachmentHandler{uc: uc, maxSize: maxSize}
+}
+
+func toAttachmentResponse(a *entity.Attachment) attachmentResponse {
+	id := strconv.FormatInt(a.ID, 10)
+	resp := attachmentResponse{
+		ID:          a.ID,
+		FileName:    a.FileName,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		Width:       a.Width,
+		Height:      a.Height,
+		URL:         "/attachments/" + id,
+	}
+	if a.HasThumbnail() {
+		resp.ThumbnailURL = "/attachments/" + id + "/thumbnail"
+	}
+	return resp
+}
+
+func toAttachmentResponses(list []*entity.Attachment) []attachmentResponse {
+	if len(list) == 0 {
+		return nil
+	}
+	resp := make([]attachmentResponse, 0, len(list))
+	for _, a := range list {
+		resp = append(resp, toAttachmentResponse(a))
+	}
+	return resp
+}
+
+// UploadAttachment — POST /attachments
+// @Summary      Upload attachment
+// @Description  Uploads a file to attach to a message: pass the returned id in attachment_ids when sending. The type is detected from the file content, not from its name; unsupported types get 415, files over the size limit 413. Images get a thumbnail. Files not sent with a message within a day are deleted.
+// @Tags         Attachment
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file  formData  file  true  "File"
+// @Success      201   {object}  attachmentResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      409   {object}  ErrorResponse  "Too many unsent attachments"
+// @Failure      413   {object}  ErrorResponse
+// @Failure      415   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /attachments [post]
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxSize+multipartOverhead)
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, ErrorResponse{Message: usecase.ErrAttachmentTooLarge.Error()})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "file is required"})
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "cannot read file"})
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	a, err := h.uc.Upload(c.Request.Context(), usecase.UploadParams{FileName: fh.Filename, Body: f})
+	if err != nil {
+		attachmentError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, toAttachmentResponse(a))
+}
+
+// GetAttachment — GET /attachments/{id}
+// @Summary      Download attachment
+// @Description  Returns the file. Images, PDF and text are shown inline, other types are downloaded. An attachment not yet sent is available only to its uploader (the token may be passed as access_token for <img> tags); attachments of deleted or hidden messages are not available.
+// @Tags         Attachment
+// @Produce      octet-stream
+// @Param        id  path  int  true  "Attachment ID"
+// @Success      200
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /attachments/{id} [get]
+func (h *AttachmentHandler) GetAttachment(c *gin.Context) {
+	h.serve(c, false)
+}
+
+// GetThumbnail — GET /attachments/{id}/thumbnail
+// @Summary      Download attachment thumbnail
+// @Description  Returns a reduced image (JPEG, or PNG for images with transparency). Only images have thumbnails.
+// @Tags         Attachment
+// @Produce      image/jpeg
+// @Param        id  path  int  true  "Attachment ID"
+// @Success      200
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /attachments/{id}/thumbnail [get]
+func (h *AttachmentHandler) GetThumbnail(c *gin.Context) {
+	h.serve(c, true)
+}
+
+func (h *AttachmentHandler) serve(c *gin.Context, thumbnail bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid attachment id"})
+		return
+	}
+
+	a, rc, err := h.uc.Open(c.Request.Context(), id, thumbnail)
+	if err != nil {
+		attachmentError(c, err)
+		return
+	}
+	defer func() { _ = rc.Close() }()
+
+	headers := map[string]string{
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, max-age=86400",
+	}
+	if thumbnail {
+		// размер и тип миниатюры в базе не хранятся; она маленькая — читаем целиком и определяем тип по содержимому
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+			return
+		}
+		for k, v := range headers {
+			c.Header(k, v)
+		}
+		c.Data(http.StatusOK, media.Sniff(data), data)
+		return
+	}
+	disposition := "attachment"
+	if inlineType(a.ContentType) {
+		disposition = "inline"
+	}
+	headers["Content-Disposition"] = mime.FormatMediaType(disposition, map[string]string{"filename": a.FileName})
+	c.DataFromReader(http.StatusOK, a.Size, a.ContentType, rc, headers)
+}
+
+// DeleteAttachment — DELETE /attachments/{id}
+// @Summary      Delete unsent attachment
+// @Description  Deletes an attachment of the current user that has not been sent with a message yet.
+// @Tags         Attachment
+// @Param        id  path  int  true  "Attachment ID"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse  "Already sent with a message"
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /attachments/{id} [delete]
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid attachment id"})
+		return
+	}
+	if err := h.uc.Delete(c.Request.Context(), id); err != nil {
+		attachmentError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// inlineType — типы, которые браузер может безопасно показать сам; остальное скачивается
+func inlineType(contentType string) bool {
+	return strings.HasPrefix(contentType, "image/") || contentType == "application/pdf" || contentType == "text/plain"
+}
+
+func attachmentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrUnauthenticated):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+	case errors.Is(err, usecase.ErrForbidden):
+		c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden: insufficient privileges"})
+	case errors.Is(err, usecase.ErrAttachmentNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	case errors.Is(err, usecase.ErrAttachmentTooLarge):
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, ErrorResponse{Message: err.Error()})
+	case errors.Is(err, usecase.ErrAttachmentType):
+		c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, ErrorResponse{Message: err.Error()})
+	case errors.Is(err, usecase.ErrAttachmentEmpty):
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	case errors.Is(err, usecase.ErrTooManyPending),
+		errors.Is(err, usecase.ErrAttachmentInUse):
+		c.AbortWithStatusJSON(http.StatusConflict, ErrorResponse{Message: err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
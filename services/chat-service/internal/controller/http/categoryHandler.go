@@ -11,40 +11,70 @@ import (
 )
 
 type CategoryHandler struct {
-	uc usecase.CategoryUsecase
+	uc    usecase.CategoryUsecase
+	reads usecase.ReadUsecase
 }
 
 //todo покрытие тестами
 
-func NewCategoryHandler(uc usecase.CategoryUsecase) *CategoryHandler {
-	return &CategoryHandler{uc: uc}
+func NewCategoryHandler(uc usecase.CategoryUsecase, reads usecase.ReadUsecase) *CategoryHandler {
+	return &CategoryHandler{uc: uc, reads: reads}
 }
 
 // ListCategories — GET /categories
 // @Summary      List all categories
-// @Description  Returns all forum categories
+// @Description  Returns forum categories as a tree: root categories with nested children, ordered by position. Each node carries its own topic and message counters and, with a token, my unread counters. Pass flat=true for a plain list.
 // @Tags         Category
 // @Produce      json
+// @Param        flat  query     bool  false  "Return a flat list instead of a tree"
 // @Success      200 {array} categoryResponse
 // @Failure      500 {object} ErrorResponse
 // @Router       /categories [get]
 func (h *CategoryHandler) ListCategories(c *gin.Context) {
-	list, err := h.uc.ListCategories(c.Request.Context())
+	var (
+		list []*entity.Category
+		err  error
+	)
+	if c.Query("flat") == "true" {
+		list, err = h.uc.ListCategories(c.Request.Context())
+	} else {
+		list, err = h.uc.ListCategoryTree(c.Request.Context())
+	}
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: err.Error()})
 		return
 	}
+	h.reads.AnnotateCategories(c.Request.Context(), list)
+
+	c.JSON(http.StatusOK, toCategoryResponses(list))
+}
 
+// toCategoryResponses рекурсивно переводит категории (и их Children) в ответ
+func toCategoryResponses(list []*entity.Category) []categoryResponse {
 	resp := make([]categoryResponse, 0, len(list))
-	for _, c := range list {
+	for _, cat := range list {
 		resp = append(resp, categoryResponse{
-			ID:          c.ID,
-			Title:       c.Title,
-			Description: c.Description,
+			ID:           cat.ID,
+			Title:        cat.Title,
+			Description:  cat.Description,
+			ParentID:     cat.ParentID,
+			Position:     cat.Position,
+			QA:           cat.QA,
+			TopicCount:   cat.TopicCount,
+			MessageCount: cat.MessageCount,
+			Retention:    toRetentionResponse(cat.Retention),
+			Children:     toCategoryChildren(cat.Children),
+			Unread:       toUnreadCountResponse(cat.Unread),
 		})
 	}
+	return resp
+}
 
-	c.JSON(http.StatusOK, resp)
+func toCategoryChildren(children []*entity.Category) []categoryResponse {
+	if len(children) == 0 {
+		return nil
+	}
+	return toCategoryResponses(children)
 }
 
 // GetCategory — GET /categories/{id}
@@ -79,18 +109,23 @@ func (h *CategoryHandler) GetCategory(c *gin.Context) {
 		ID:          cat.ID,
 		Title:       cat.Title,
 		Description: cat.Description,
+		ParentID:    cat.ParentID,
+		Position:    cat.Position,
+		QA:          cat.QA,
+		Retention:   toRetentionResponse(cat.Retention),
 	})
 }
 
 // CreateCategory — POST /categories
 // @Summary      Create new category
-// @Description  Adds a forum category
+// @Description  Adds a forum category; with parent_id it becomes the last subcategory of that parent
 // @Tags         Category
 // @Accept       json
 // @Produce      json
 // @Param        request  body      createCategoryRequest  true  "New category"
 // @Success      201      {object}  categoryResponse
 // @Failure      400      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
 // @Failure      401  {object}  ErrorResponse
 // @Failure      403  {object}  ErrorResponse
 // @Failure      500      {object}  ErrorResponse
@@ -106,6 +141,7 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 	id, err := h.uc.CreateCategory(c.Request.Context(), usecase.CreateCategoryParams{
 		Title:       req.Title,
 		Description: req.Description,
+		ParentID:    req.ParentID,
 	})
 	if err != nil {
 		switch {
@@ -113,6 +149,8 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
 		case errors.Is(err, usecase.ErrForbidden):
 			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden: insufficient privileges"})
+		case errors.Is(err, usecase.ErrParentCategoryNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
 		default:
 			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
 		}
@@ -123,6 +161,7 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 		ID:          id,
 		Title:       req.Title,
 		Description: req.Description,
+		ParentID:    req.ParentID,
 	})
 }
 
@@ -132,7 +171,7 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 // @Tags         Category
 // @Accept       json
 // @Param        id       path      int                     true  "Category ID"
-// @Param        request  body      createCategoryRequest  true  "Updated data"
+// @Param        request  body      updateCategoryRequest  true  "Updated data"
 // @Success 204
 // @Failure      400      {object}  ErrorResponse
 // @Failure      401      {object}  ErrorResponse
@@ -147,7 +186,7 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid id"})
 		return
 	}
-	var req createCategoryRequest
+	var req updateCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
 		return
@@ -176,7 +215,7 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 
 // DeleteCategory — DELETE /categories/{id}
 // @Summary      Delete category
-// @Description  Removes a category
+// @Description  Removes an empty category. Categories that still have subcategories or topics are rejected with 409.
 // @Tags         Category
 // @Param        id   path      int  true  "Category ID"
 // @Success      204
@@ -184,6 +223,7 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 // @Failure      401  {object}  ErrorResponse
 // @Failure      403  {object}  ErrorResponse
 // @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
 // @Security     BearerAuth
 // @Router       /categories/{id} [delete]
@@ -195,6 +235,151 @@ func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 	}
 
 	if err := h.uc.DeleteCategory(c.Request.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnauthenticated):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+		case errors.Is(err, usecase.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden: insufficient privileges"})
+		case errors.Is(err, usecase.ErrCategoryNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+		case errors.Is(err, usecase.ErrCategoryNotEmpty):
+			c.AbortWithStatusJSON(http.StatusConflict, ErrorResponse{Message: err.Error()})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ReorderCategories — PUT /admin/categories/reorder
+// @Summary      Reorder categories (admin only)
+// @Description  Sets parent and position for the listed categories in one transaction. Moves that would nest a category inside its own subtree are rejected.
+// @Tags         Category
+// @Accept       json
+// @Param        request  body      reorderCategoriesRequest  true  "New positions"
+// @Success      204
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/categories/reorder [put]
+func (h *CategoryHandler) ReorderCategories(c *gin.Context) {
+	var req reorderCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	items := make([]entity.CategoryPosition, 0, len(req.Items))
+	for _, it := range req.Items {
+		items = append(items, entity.CategoryPosition{
+			ID:       it.ID,
+			ParentID: it.ParentID,
+			Position: it.Position,
+		})
+	}
+
+	if err := h.uc.ReorderCategories(c.Request.Context(), items); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnauthenticated):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+		case errors.Is(err, usecase.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden: insufficient privileges"})
+		case errors.Is(err, usecase.ErrCategoryCycle):
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		case errors.Is(err, usecase.ErrCategoryNotFound), errors.Is(err, usecase.ErrParentCategoryNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetCategoryRetention — PUT /admin/categories/{id}/retention
+// @Summary      Set category retention policy (admin only)
+// @Description  Sets how long messages are kept in all topics of the category: default (global threshold), forever, days (value = N days) or last_n (value = N newest messages per topic). Topic policies override it.
+// @Tags         Retention
+// @Accept       json
+// @Param        id       path      int                  true  "Category ID"
+// @Param        request  body      setRetentionRequest  true  "Retention policy"
+// @Success      204
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/categories/{id}/retention [put]
+func (h *CategoryHandler) SetCategoryRetention(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid id"})
+		return
+	}
+	var req setRetentionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	err = h.uc.SetCategoryRetention(c.Request.Context(), id, entity.RetentionPolicy{
+		Mode:  entity.RetentionMode(req.Mode),
+		Value: req.Value,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnauthenticated):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+		case errors.Is(err, usecase.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden: insufficient privileges"})
+		case errors.Is(err, usecase.ErrInvalidRetention):
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		case errors.Is(err, usecase.ErrCategoryNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetCategoryQA — PUT /admin/categories/{id}/qa
+// @Summary      Turn Q&A mode on or off (admin only)
+// @Description  In a Q&A category the topic author or a category moderator can mark one message of a topic as the accepted answer. Turning the mode off keeps answers accepted earlier.
+// @Tags         Category
+// @Accept       json
+// @Param        id       path      int           true  "Category ID"
+// @Param        request  body      setQARequest  true  "Q&A mode"
+// @Success      204
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/categories/{id}/qa [put]
+func (h *CategoryHandler) SetCategoryQA(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid id"})
+		return
+	}
+	var req setQARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if err := h.uc.SetCategoryQA(c.Request.Context(), id, *req.QA); err != nil {
 		switch {
 		case errors.Is(err, usecase.ErrUnauthenticated):
 			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
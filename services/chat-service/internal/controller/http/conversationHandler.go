@@ -0,0 +1,469 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"chat-service/internal/entity"
+	"chat-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type ConversationHandler struct {
+	uc usecase.ConversationUsecase
+}
+
+func NewConversationHandler(uc usecase.ConversationUsecase) *ConversationHandler {
+	return &ConversationHandler{uc: uc}
+}
+
+func toPrivateMessageResponse(m *entity.PrivateMessage) privateMessageResponse {
+	return privateMessageResponse{
+		ID:             m.ID,
+		ConversationID: m.ConversationID,
+		AuthorID:       m.AuthorID,
+		AuthorName:     m.AuthorName,
+		Content:        m.Content,
+		ContentHTML:    m.ContentHTML,
+		CreatedAt:      m.CreatedAt,
+		DeletedAt:      m.DeletedAt,
+	}
+}
+
+func toConversationResponse(c *entity.Conversation) conversationResponse {
+	resp := conversationResponse{
+		ID:            c.ID,
+		Title:         c.Title,
+		IsGroup:       c.IsGroup,
+		CreatedBy:     c.CreatedBy,
+		CreatedAt:     c.CreatedAt,
+		LastMessageAt: c.LastMessageAt,
+		Members:       make([]conversationMemberResponse, 0, len(c.Members)),
+		Unread:        c.Unread,
+	}
+	for _, m := range c.Members {
+		resp.Members = append(resp.Members, conversationMemberResponse{
+			UserID:            m.UserID,
+			Name:              m.Name,
+			LastReadMessageID: m.LastReadMessageID,
+		})
+	}
+	if c.LastMessage != nil {
+		lm := toPrivateMessageResponse(c.LastMessage)
+		resp.LastMessage = &lm
+	}
+	return resp
+}
+
+// StartConversation — POST /conversations
+// @Summary      Start private conversation
+// @Description  One user in user_ids starts a one-to-one conversation (the existing one is returned if there already is one); several start a group of up to 10 people. Users banned on the forum cannot start conversations; users who blocked me cannot be added. An optional first message is sent right away.
+// @Tags         Conversations
+// @Accept       json
+// @Produce      json
+// @Param        request  body      startConversationRequest  true  "Who to talk to"
+// @Success      201      {object}  conversationResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse  "Banned, or blocked by a recipient"
+// @Failure      404      {object}  ErrorResponse
+// @Failure      422      {object}  ErrorResponse
+// @Failure      429      {object}  ErrorResponse  "Too many requests, see Retry-After"
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /conversations [post]
+func (h *ConversationHandler) StartConversation(c *gin.Context) {
+	var req startConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	conv, err := h.uc.StartConversation(c.Request.Context(), usecase.StartConversationParams{
+		UserIDs: req.UserIDs,
+		Title:   req.Title,
+		Content: req.Content,
+	})
+	if err != nil {
+		conversationError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, toConversationResponse(conv))
+}
+
+// ListConversations — GET /conversations
+// @Summary      My conversations
+// @Description  Most recently active first, each with its last message and my unread count. New messages are also pushed to /ws/me as "conversation_message" events.
+// @Tags         Conversations
+// @Produce      json
+// @Param        limit   query     int  false  "Page size (default 50, max 200)"
+// @Param        offset  query     int  false  "Offset"
+// @Success      200     {object}  conversationListResponse
+// @Failure      400     {object}  ErrorResponse
+// @Failure      401     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /conversations [get]
+func (h *ConversationHandler) ListConversations(c *gin.Context) {
+	var q pageQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	list, total, err := h.uc.ListConversations(c.Request.Context(), q.Limit, q.Offset)
+	if err != nil {
+		conversationError(c, err)
+		return
+	}
+
+	resp := conversationListResponse{
+		Items:  make([]conversationResponse, 0, len(list)),
+		Total:  total,
+		Limit:  q.Limit,
+		Offset: q.Offset,
+	}
+	for _, conv := range list {
+		resp.Items = append(resp.Items, toConversationResponse(conv))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetConversation — GET /conversations/{id}
+// @Summary      Get conversation
+// @Description  Only members see a conversation; for everyone else it does not exist.
+// @Tags         Conversations
+// @Produce      json
+// @Param        id   path      int  true  "Conversation ID"
+// @Success      200  {object}  conversationResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /conversations/{id} [get]
+func (h *ConversationHandler) GetConversation(c *gin.Context) {
+	id, ok := conversationID(c)
+	if !ok {
+		return
+	}
+
+	conv, err := h.uc.GetConversation(c.Request.Context(), id)
+	if err != nil {
+		conversationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toConversationResponse(conv))
+}
+
+// GetMessages — GET /conversations/{id}/messages
+// @Summary      Conversation messages
+// @Description  Newest first. Pass the smallest ID seen as before_id to load older messages. Deleted messages stay in place without text.
+// @Tags         Conversations
+// @Produce      json
+// @Param        id         path      int  true   "Conversation ID"
+// @Param        before_id  query     int  false  "Only messages older than this one"
+// @Param        limit      query     int  false  "Page size (default 50, max 200)"
+// @Success      200        {array}   privateMessageResponse
+// @Failure      400        {object}  ErrorResponse
+// @Failure      401        {object}  ErrorResponse
+// @Failure      404        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /conversations/{id}/messages [get]
+func (h *ConversationHandler) GetMessages(c *gin.Context) {
+	id, ok := conversationID(c)
+	if !ok {
+		return
+	}
+	var q olderMessagesQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	list, err := h.uc.GetMessages(c.Request.Context(), id, q.BeforeID, q.Limit)
+	if err != nil {
+		conversationError(c, err)
+		return
+	}
+
+	resp := make([]privateMessageResponse, 0, len(list))
+	for _, m := range list {
+		resp = append(resp, toPrivateMessageResponse(m))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// SendMessage — POST /conversations/{id}/messages
+// @Summary      Send private message
+// @Description  Delivered live to /ws/conversations/{id} ("created") and to every member's /ws/me ("conversation_message"). In a one-to-one conversation a block by either side stops new messages.
+// @Tags         Conversations
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                        true  "Conversation ID"
+// @Param        request  body      sendPrivateMessageRequest  true  "Message"
+// @Success      201      {object}  privateMessageResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      422      {object}  ErrorResponse
+// @Failure      429      {object}  ErrorResponse  "Too many requests, see Retry-After"
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /conversations/{id}/messages [post]
+func (h *ConversationHandler) SendMessage(c *gin.Context) {
+	id, ok := conversationID(c)
+	if !ok {
+		return
+	}
+	var req sendPrivateMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	m, err := h.uc.SendMessage(c.Request.Context(), id, req.Content)
+	if err != nil {
+		conversationError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, toPrivateMessageResponse(m))
+}
+
+// DeleteMessage — DELETE /conversations/{id}/messages/{messageId}
+// @Summary      Delete my private message
+// @Tags         Conversations
+// @Param        id         path  int  true  "Conversation ID"
+// @Param        messageId  path  int  true  "Message ID"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /conversations/{id}/messages/{messageId} [delete]
+func (h *ConversationHandler) DeleteMessage(c *gin.Context) {
+	id, ok := conversationID(c)
+	if !ok {
+		return
+	}
+	messageID, err := strconv.ParseInt(c.Param("messageId"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid message id"})
+		return
+	}
+
+	if err := h.uc.DeleteMessage(c.Request.Context(), id, messageID); err != nil {
+		conversationError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// MarkRead — POST /conversations/{id}/read
+// @Summary      Mark conversation read
+// @Description  Moves my read position forward to message_id, or to the latest message when the body is empty. Other sessions get a "conversation_read" event on /ws/me.
+// @Tags         Conversations
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int              true   "Conversation ID"
+// @Param        request  body      markReadRequest  false  "Last read message; omit to mark everything read"
+// @Success      200      {object}  conversationReadResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /conversations/{id}/read [post]
+func (h *ConversationHandler) MarkRead(c *gin.Context) {
+	id, ok := conversationID(c)
+	if !ok {
+		return
+	}
+	// тело необязательно: без него прочитано всё
+	var req markReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	pos, err := h.uc.MarkRead(c.Request.Context(), id, req.MessageID)
+	if err != nil {
+		conversationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, conversationReadResponse{ConversationID: id, LastReadMessageID: pos})
+}
+
+// AddMember — POST /conversations/{id}/members
+// @Summary      Add member to group conversation
+// @Description  Any member may add people while the group has fewer than 10 members. Blocks between me and the new member apply.
+// @Tags         Conversations
+// @Accept       json
+// @Param        id       path  int               true  "Conversation ID"
+// @Param        request  body  addMemberRequest  true  "Who to add"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /conversations/{id}/members [post]
+func (h *ConversationHandler) AddMember(c *gin.Context) {
+	id, ok := conversationID(c)
+	if !ok {
+		return
+	}
+	var req addMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if err := h.uc.AddMember(c.Request.Context(), id, req.UserID); err != nil {
+		conversationError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// LeaveConversation — DELETE /conversations/{id}/members/me
+// @Summary      Leave group conversation
+// @Description  One-to-one conversations cannot be left; block the other user instead.
+// @Tags         Conversations
+// @Param        id   path  int  true  "Conversation ID"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /conversations/{id}/members/me [delete]
+func (h *ConversationHandler) LeaveConversation(c *gin.Context) {
+	id, ok := conversationID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.LeaveConversation(c.Request.Context(), id); err != nil {
+		conversationError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// BlockUser — POST /users/{id}/block
+// @Summary      Block user from messaging me
+// @Description  The user can no longer start conversations with me, add me to groups or write to our one-to-one conversation. Shared groups stay as they are. Blocking twice is not an error.
+// @Tags         Conversations
+// @Param        id   path  int  true  "User ID"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/{id}/block [post]
+func (h *ConversationHandler) BlockUser(c *gin.Context) {
+	h.block(c, h.uc.BlockUser)
+}
+
+// UnblockUser — DELETE /users/{id}/block
+// @Summary      Unblock user
+// @Tags         Conversations
+// @Param        id   path  int  true  "User ID"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /users/{id}/block [delete]
+func (h *ConversationHandler) UnblockUser(c *gin.Context) {
+	h.block(c, h.uc.UnblockUser)
+}
+
+func (h *ConversationHandler) block(c *gin.Context, action func(ctx context.Context, userID int64) error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid id"})
+		return
+	}
+	if err := action(c.Request.Context(), id); err != nil {
+		conversationError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListBlockedUsers — GET /me/blocks
+// @Summary      Users I blocked
+// @Tags         Conversations
+// @Produce      json
+// @Success      200  {array}   blockedUserResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /me/blocks [get]
+func (h *ConversationHandler) ListBlockedUsers(c *gin.Context) {
+	list, err := h.uc.ListBlockedUsers(c.Request.Context())
+	if err != nil {
+		conversationError(c, err)
+		return
+	}
+
+	resp := make([]blockedUserResponse, 0, len(list))
+	for _, b := range list {
+		resp = append(resp, blockedUserResponse{UserID: b.BlockedUserID, Name: b.BlockedUserName, CreatedAt: b.CreatedAt})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func conversationID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid conversation id"})
+		return 0, false
+	}
+	return id, true
+}
+
+func conversationError(c *gin.Context, err error) {
+	if abortIfRejected(c, err) || abortIfUnprivileged(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, usecase.ErrUnauthenticated):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+	case errors.Is(err, usecase.ErrForbidden):
+		c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden: insufficient privileges"})
+	case errors.Is(err, usecase.ErrBannedFromMessaging):
+		c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Code: "banned", Message: err.Error()})
+	case errors.Is(err, usecase.ErrMessagingBlocked),
+		errors.Is(err, usecase.ErrYouBlockedUser):
+		c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Code: "messaging_blocked", Message: err.Error()})
+	case errors.Is(err, usecase.ErrConversationNotFound),
+		errors.Is(err, usecase.ErrMessageNotFound),
+		errors.Is(err, usecase.ErrRecipientNotFound),
+		errors.Is(err, usecase.ErrNotBlocked):
+		c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	case errors.Is(err, usecase.ErrNoRecipients),
+		errors.Is(err, usecase.ErrConversationTooLarge),
+		errors.Is(err, usecase.ErrNotGroupConversation),
+		errors.Is(err, usecase.ErrEmptyMessage),
+		errors.Is(err, usecase.ErrCannotBlockSelf):
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	case errors.Is(err, usecase.ErrAlreadyMember):
+		c.AbortWithStatusJSON(http.StatusConflict, ErrorResponse{Message: err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
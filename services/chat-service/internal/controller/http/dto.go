@@ -1,20 +1,50 @@
 package http
 
-import "time"
+import (
+	"time"
+
+	"chat-service/internal/entity"
+)
 
 type createCategoryRequest struct {
 	Title       string `json:"title" binding:"required"`
 	Description string `json:"description" binding:"required"`
+	ParentID    *int64 `json:"parent_id"` // нет — корневая категория
+}
+
+type updateCategoryRequest struct {
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description" binding:"required"`
 }
 
 type categoryResponse struct {
-	ID          int64  `json:"id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
+	ID           int64                `json:"id"`
+	Title        string               `json:"title"`
+	Description  string               `json:"description"`
+	ParentID     *int64               `json:"parent_id"`
+	Position     int                  `json:"position"`
+	QA           bool                 `json:"qa"` // вопросы и ответы: в топиках отмечается принятый ответ
+	TopicCount   int64                `json:"topic_count"`
+	MessageCount int64                `json:"message_count"`
+	Retention    *retentionResponse   `json:"retention,omitempty"` // нет — действует глобальный порог
+	Children     []categoryResponse   `json:"children,omitempty"`  // только в дереве
+	Unread       *unreadCountResponse `json:"unread,omitempty"`    // только для вошедшего пользователя
+}
+
+type categoryPositionRequest struct {
+	ID       int64  `json:"id" binding:"required"`
+	ParentID *int64 `json:"parent_id"` // нет — в корень
+	Position int    `json:"position" binding:"min=0"`
+}
+
+type reorderCategoriesRequest struct {
+	Items []categoryPositionRequest `json:"items" binding:"required,dive"`
 }
 
 type sendMessageRequest struct {
-	Content string `json:"content" binding:"required"`
+	Content        string  `json:"content" binding:"required_without=AttachmentIDs"`
+	QuoteMessageID *int64  `json:"quote_message_id,omitempty"` // цитируемое сообщение того же топика; его автор получит уведомление
+	AttachmentIDs  []int64 `json:"attachment_ids,omitempty"`   // загруженные через POST /attachments; без текста допустимы только вместе с ними
 }
 
 type updateMessageRequest struct {
@@ -23,12 +53,49 @@ type updateMessageRequest struct {
 
 // добавила имя автора, проверить ошибки
 type messageResponse struct {
-	ID         int64  `json:"id"`
-	TopicID    int64  `json:"topic_id"`
-	AuthorID   int64  `json:"author_id"`
-	AuthorName string `json:"author_name"`
-	Content    string `json:"content"`
-	CreatedAt  int64  `json:"created_at"` // unix timestamp
+	ID           int64                `json:"id"`
+	TopicID      int64                `json:"topic_id"`
+	AuthorID     int64                `json:"author_id"`
+	AuthorName   string               `json:"author_name"`
+	Content      string               `json:"content"`                 // исходный Markdown — для редактирования
+	ContentHTML  string               `json:"content_html"`            // безопасный HTML для показа; сырой HTML из content экранирован
+	CreatedAt    int64                `json:"created_at"`              // unix timestamp
+	Deleted      bool                 `json:"deleted"`                 // tombstone: content скрыт для обычных пользователей
+	DeletedAt    *int64               `json:"deleted_at,omitempty"`    // unix timestamp
+	DeletedBy    *int64               `json:"deleted_by,omitempty"`    // только для admin
+	DeleteReason string               `json:"delete_reason,omitempty"` // только для admin
+	Hidden       bool                 `json:"hidden,omitempty"`        // скрыто после жалоб: content виден только модераторам
+	QuoteID      *int64               `json:"quote_id,omitempty"`      // цитируемое сообщение
+	Attachments  []attachmentResponse `json:"attachments,omitempty"`
+	Score        int64                `json:"score"`              // сумма голосов
+	MyVote       int                  `json:"my_vote,omitempty"`  // мой голос: 1 или -1; только с токеном
+	Accepted     bool                 `json:"accepted,omitempty"` // принятый ответ топика
+}
+
+// messagesQuery — порядок сообщений топика
+type messagesQuery struct {
+	Order string `form:"order" binding:"omitempty,oneof=oldest score"`
+}
+
+type voteRequest struct {
+	Value int `json:"value" binding:"required,oneof=1 -1"`
+}
+
+type voteResponse struct {
+	MessageID int64 `json:"message_id"`
+	Score     int64 `json:"score"`
+	MyVote    int   `json:"my_vote"` // 0 — голос снят
+}
+
+type attachmentResponse struct {
+	ID           int64  `json:"id"`
+	FileName     string `json:"file_name"`
+	ContentType  string `json:"content_type"`
+	Size         int64  `json:"size"`
+	Width        int    `json:"width,omitempty"`
+	Height       int    `json:"height,omitempty"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"` // только для картинок
 }
 
 type createTopicRequest struct {
@@ -51,9 +118,515 @@ type topicResponse struct {
 	AuthorID    int64     `json:"author_id"`
 	AuthorName  string    `json:"author_name"`
 	CreatedAt   time.Time `json:"created_at"`
+	Pinned      bool      `json:"pinned"`
+	Locked      bool      `json:"locked"`
+	// Solved — в топике есть принятый ответ AcceptedMessageID
+	Solved            bool   `json:"solved"`
+	AcceptedMessageID *int64 `json:"accepted_message_id,omitempty"`
+	// Retention — собственная политика топика; нет — наследуется от категории
+	Retention *retentionResponse `json:"retention,omitempty"`
+	// RedirectedFrom — запрошенный ID, если он принадлежал топику, слитому в этот
+	RedirectedFrom int64 `json:"redirected_from,omitempty"`
+	// LastReadMessageID и Unread — позиция чтения вошедшего пользователя; нет — топик он ещё не открывал
+	LastReadMessageID *int64 `json:"last_read_message_id,omitempty"`
+	Unread            *int64 `json:"unread,omitempty"`
+}
+
+type deletedTopicResponse struct {
+	topicResponse
+	DeletedAt    time.Time `json:"deleted_at"`
+	DeletedBy    *int64    `json:"deleted_by,omitempty"`
+	DeleteReason string    `json:"delete_reason,omitempty"`
+}
+
+type moveTopicRequest struct {
+	CategoryID int64 `json:"category_id" binding:"required"`
+}
+
+type mergeTopicRequest struct {
+	TargetTopicID int64 `json:"target_topic_id" binding:"required"`
+}
+
+type setPinnedRequest struct {
+	Pinned *bool `json:"pinned" binding:"required"`
+}
+
+type setLockedRequest struct {
+	Locked *bool `json:"locked" binding:"required"`
+}
+
+type setQARequest struct {
+	QA *bool `json:"qa" binding:"required"`
+}
+
+// topicsQuery — отбор топиков категории
+type topicsQuery struct {
+	Unsolved bool `form:"unsolved"` // только без принятого ответа
+}
+
+type setRetentionRequest struct {
+	Mode  string `json:"mode" binding:"required" enums:"default,forever,days,last_n"`
+	Value int    `json:"value"` // дни для days, число сообщений для last_n
+}
+
+type retentionResponse struct {
+	Mode  string `json:"mode"`
+	Value int    `json:"value,omitempty"`
+}
+
+func toRetentionResponse(p entity.RetentionPolicy) *retentionResponse {
+	if p.IsDefault() {
+		return nil
+	}
+	return &retentionResponse{Mode: string(p.Mode), Value: p.Value}
+}
+
+type retentionStatResponse struct {
+	TopicID    int64  `json:"topic_id"`
+	CategoryID int64  `json:"category_id"`
+	Policy     string `json:"policy"`
+	Count      int64  `json:"count"`
+}
+
+type cleanupReportResponse struct {
+	DryRun bool                    `json:"dry_run"`
+	Total  int64                   `json:"total"`
+	Topics []retentionStatResponse `json:"topics"`
+}
+
+type moderationLogQuery struct {
+	ActorID    int64  `form:"actor_id" binding:"omitempty,min=1"`
+	AuthorID   int64  `form:"author_id" binding:"omitempty,min=1"`
+	TargetType string `form:"target_type" binding:"omitempty,oneof=message topic"`
+	TargetID   int64  `form:"target_id" binding:"omitempty,min=1"`
+	Action     string `form:"action"`
+	Limit      int    `form:"limit,default=50" binding:"min=1,max=200"`
+	Offset     int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+type pageQuery struct {
+	Limit  int `form:"limit,default=50" binding:"min=1,max=200"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
+
+type moderationEntryResponse struct {
+	ID             int64          `json:"id"`
+	ActorID        *int64         `json:"actor_id,omitempty"` // автору своего контента не показывается
+	Action         string         `json:"action"`
+	TargetType     string         `json:"target_type"`
+	TargetID       int64          `json:"target_id"`
+	TargetAuthorID *int64         `json:"target_author_id,omitempty"`
+	Reason         string         `json:"reason,omitempty"`
+	Snapshot       map[string]any `json:"snapshot,omitempty"`
+	Details        map[string]any `json:"details,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+type moderationLogResponse struct {
+	Items  []moderationEntryResponse `json:"items"`
+	Total  int64                     `json:"total"`
+	Limit  int                       `json:"limit"`
+	Offset int                       `json:"offset"`
+}
+
+type createReportRequest struct {
+	Reason  string `json:"reason" binding:"required" enums:"spam,abuse,harassment,illegal,off_topic,other"`
+	Comment string `json:"comment" binding:"max=1000"`
+}
+
+type reportQuery struct {
+	State      string `form:"state,default=open" binding:"omitempty,oneof=open dismissed actioned"`
+	TargetType string `form:"target_type" binding:"omitempty,oneof=message topic"`
+	Limit      int    `form:"limit,default=50" binding:"min=1,max=200"`
+	Offset     int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+type reportResponse struct {
+	ID             int64      `json:"id"`
+	TargetType     string     `json:"target_type"`
+	TargetID       int64      `json:"target_id"`
+	TopicID        int64      `json:"topic_id"`
+	CategoryID     int64      `json:"category_id"`
+	TargetAuthorID int64      `json:"target_author_id"`
+	Excerpt        string     `json:"excerpt"` // текст сообщения или заголовок топика
+	TargetHidden   bool       `json:"target_hidden"`
+	OpenReports    int64      `json:"open_reports"` // открытых жалоб на тот же объект
+	ReporterID     int64      `json:"reporter_id"`
+	Reason         string     `json:"reason"`
+	Comment        string     `json:"comment,omitempty"`
+	State          string     `json:"state"`
+	ResolvedBy     *int64     `json:"resolved_by,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	Resolution     string     `json:"resolution,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type reportListResponse struct {
+	Items  []reportResponse `json:"items"`
+	Total  int64            `json:"total"`
+	Limit  int              `json:"limit"`
+	Offset int              `json:"offset"`
+}
+
+type resolveReportRequest struct {
+	Action string `json:"action" binding:"required,oneof=dismiss delete block"`
+	Note   string `json:"note" binding:"max=1000"`
+}
+
+type createMuteRequest struct {
+	UserID     int64  `json:"user_id" binding:"required,min=1"`
+	TopicID    int64  `json:"topic_id" binding:"omitempty,min=1"`
+	CategoryID int64  `json:"category_id" binding:"omitempty,min=1"`
+	Duration   string `json:"duration" example:"24h"` // пусто — бессрочно
+	Reason     string `json:"reason" binding:"max=1000"`
+}
+
+type muteQuery struct {
+	UserID int64 `form:"user_id" binding:"omitempty,min=1"`
+}
+
+type muteResponse struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"user_id"`
+	TopicID    *int64     `json:"topic_id,omitempty"`
+	CategoryID *int64     `json:"category_id,omitempty"`
+	Reason     string     `json:"reason,omitempty"`
+	MutedBy    *int64     `json:"muted_by,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at"` // null — бессрочно
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// mutedResponse — ответ на попытку писать под мьютом
+type mutedResponse struct {
+	Code       string     `json:"code" example:"muted"`
+	Message    string     `json:"message"`
+	MutedUntil *time.Time `json:"muted_until"` // null — бессрочно
+	Reason     string     `json:"reason,omitempty"`
 }
 
 type ErrorResponse struct {
 	Code    string `json:"code,omitempty"`
 	Message string `json:"message"`
 }
+
+type filterRuleRequest struct {
+	Kind    string `json:"kind" binding:"required,oneof=word link_deny link_allow"`
+	Pattern string `json:"pattern" binding:"required,max=100"`
+	Action  string `json:"action" binding:"omitempty,oneof=reject mask flag"` // для link_allow не нужен
+}
+
+type updateFilterRuleRequest struct {
+	Pattern string `json:"pattern" binding:"required,max=100"`
+	Action  string `json:"action" binding:"omitempty,oneof=reject mask flag"`
+}
+
+type filterRuleResponse struct {
+	ID        int64     `json:"id"`
+	Kind      string    `json:"kind"`
+	Pattern   string    `json:"pattern"`
+	Action    string    `json:"action,omitempty"`
+	CreatedBy *int64    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// filterSettingsRequest — нулевой лимит отключает проверку
+type filterSettingsRequest struct {
+	MaxLength          int    `json:"max_length" binding:"min=0"`
+	LengthAction       string `json:"length_action" binding:"required,oneof=reject mask flag"`
+	MaxLinks           int    `json:"max_links" binding:"min=0"`
+	LinkFloodAction    string `json:"link_flood_action" binding:"required,oneof=reject mask flag"`
+	UnlistedLinkAction string `json:"unlisted_link_action" binding:"required,oneof=reject mask flag"`
+	DuplicateWindowSec int    `json:"duplicate_window_sec" binding:"min=0,max=86400"`
+	DuplicateAction    string `json:"duplicate_action" binding:"required,oneof=reject flag"`
+}
+
+type filterSettingsResponse struct {
+	MaxLength          int       `json:"max_length"`
+	LengthAction       string    `json:"length_action"`
+	MaxLinks           int       `json:"max_links"`
+	LinkFloodAction    string    `json:"link_flood_action"`
+	UnlistedLinkAction string    `json:"unlisted_link_action"`
+	DuplicateWindowSec int       `json:"duplicate_window_sec"`
+	DuplicateAction    string    `json:"duplicate_action"`
+	UpdatedBy          *int64    `json:"updated_by,omitempty"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+type filterTestRequest struct {
+	Text string `json:"text" binding:"required"`
+}
+
+type filterHitResponse struct {
+	Filter string `json:"filter"`
+	Action string `json:"action"`
+	Reason string `json:"reason"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type filterTestResponse struct {
+	Text     string              `json:"text"` // текст после маскирования
+	Rejected *filterHitResponse  `json:"rejected,omitempty"`
+	Flags    []filterHitResponse `json:"flags"`
+	Masked   int                 `json:"masked"`
+}
+
+type mentionResponse struct {
+	Message     messageResponse `json:"message"`
+	TopicTitle  string          `json:"topic_title"`
+	MentionedAt time.Time       `json:"mentioned_at"`
+}
+
+type mentionListResponse struct {
+	Items  []mentionResponse `json:"items"`
+	Total  int64             `json:"total"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+}
+
+type notificationQuery struct {
+	pageQuery
+	Unread bool `form:"unread"`
+}
+
+type notificationResponse struct {
+	ID        int64          `json:"id"`
+	Type      string         `json:"type" enums:"reply,mention,quote,moderation"` // новые типы могут появиться — неизвестные клиент показывает как есть
+	ActorID   *int64         `json:"actor_id,omitempty"`
+	ActorName string         `json:"actor_name,omitempty"`
+	TopicID   *int64         `json:"topic_id,omitempty"`
+	MessageID *int64         `json:"message_id,omitempty"`
+	Data      map[string]any `json:"data,omitempty"`
+	Read      bool           `json:"read"`
+	ReadAt    *time.Time     `json:"read_at,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type notificationListResponse struct {
+	Items  []notificationResponse `json:"items"`
+	Total  int64                  `json:"total"`
+	Unread int64                  `json:"unread"`
+	Limit  int                    `json:"limit"`
+	Offset int                    `json:"offset"`
+}
+
+type markAllReadResponse struct {
+	Marked int64 `json:"marked"`
+}
+
+type subscriptionResponse struct {
+	ID         int64     `json:"id"`
+	TopicID    *int64    `json:"topic_id,omitempty"`
+	CategoryID *int64    `json:"category_id,omitempty"`
+	Title      string    `json:"title"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type digestSettingsResponse struct {
+	Frequency  string     `json:"frequency" enums:"off,daily,weekly"`
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+}
+
+type digestSettingsRequest struct {
+	Frequency string `json:"frequency" binding:"required,oneof=off daily weekly"`
+}
+
+type unreadCountResponse struct {
+	Topics   int64 `json:"topics"` // топики, где есть непрочитанное
+	Messages int64 `json:"messages"`
+}
+
+type markReadRequest struct {
+	MessageID *int64 `json:"message_id"` // нет — прочитано всё
+}
+
+type topicReadResponse struct {
+	TopicID           int64     `json:"topic_id"`
+	LastReadMessageID int64     `json:"last_read_message_id"`
+	Unread            int64     `json:"unread"` // осталось непрочитанных после позиции
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type unreadCategoryResponse struct {
+	CategoryID int64  `json:"category_id"`
+	Title      string `json:"title"`
+	unreadCountResponse
+}
+
+type unreadTopicResponse struct {
+	TopicID           int64     `json:"topic_id"`
+	CategoryID        int64     `json:"category_id"`
+	Title             string    `json:"title"`
+	LastReadMessageID int64     `json:"last_read_message_id"`
+	Unread            int64     `json:"unread"`
+	LastMessageAt     time.Time `json:"last_message_at"`
+}
+
+type unreadResponse struct {
+	unreadCountResponse
+	Categories []unreadCategoryResponse `json:"categories"`
+	TopicList  []unreadTopicResponse    `json:"topic_list"` // самые свежие сверху, не больше limit
+}
+
+type unreadQuery struct {
+	Limit int `form:"limit,default=50" binding:"min=1,max=200"`
+}
+
+type startConversationRequest struct {
+	UserIDs []int64 `json:"user_ids" binding:"required,min=1,dive,min=1"` // собеседники; больше одного — группа
+	Title   string  `json:"title" binding:"max=128"`                      // только для группы
+	Content string  `json:"content"`                                      // первое сообщение, необязательно
+}
+
+type conversationMemberResponse struct {
+	UserID            int64  `json:"user_id"`
+	Name              string `json:"name"`
+	LastReadMessageID int64  `json:"last_read_message_id"`
+}
+
+type privateMessageResponse struct {
+	ID             int64      `json:"id"`
+	ConversationID int64      `json:"conversation_id"`
+	AuthorID       int64      `json:"author_id"`
+	AuthorName     string     `json:"author_name"`
+	Content        string     `json:"content"`
+	ContentHTML    string     `json:"content_html"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"` // удалено автором; текста нет
+}
+
+type conversationResponse struct {
+	ID            int64                        `json:"id"`
+	Title         string                       `json:"title,omitempty"`
+	IsGroup       bool                         `json:"is_group"`
+	CreatedBy     *int64                       `json:"created_by,omitempty"`
+	CreatedAt     time.Time                    `json:"created_at"`
+	LastMessageAt *time.Time                   `json:"last_message_at,omitempty"`
+	Members       []conversationMemberResponse `json:"members"`
+	LastMessage   *privateMessageResponse      `json:"last_message,omitempty"` // только в списке
+	Unread        int64                        `json:"unread"`                 // только в списке
+}
+
+type conversationListResponse struct {
+	Items  []conversationResponse `json:"items"`
+	Total  int64                  `json:"total"`
+	Limit  int                    `json:"limit"`
+	Offset int                    `json:"offset"`
+}
+
+type sendPrivateMessageRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+type olderMessagesQuery struct {
+	BeforeID int64 `form:"before_id" binding:"omitempty,min=0"` // 0 — последние
+	Limit    int   `form:"limit,default=50" binding:"min=1,max=200"`
+}
+
+type conversationReadResponse struct {
+	ConversationID    int64 `json:"conversation_id"`
+	LastReadMessageID int64 `json:"last_read_message_id"`
+}
+
+type addMemberRequest struct {
+	UserID int64 `json:"user_id" binding:"required"`
+}
+
+type blockedUserResponse struct {
+	UserID    int64     `json:"user_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type roomRateLimitRequest struct {
+	Burst         int `json:"burst" binding:"min=0"`          // сообщений за период от одного пользователя
+	PeriodSeconds int `json:"period_seconds" binding:"min=0"` // от 1 секунды до суток
+}
+
+type roomRequest struct {
+	Slug        string                `json:"slug" binding:"required"` // латиница в нижнем регистре, цифры и дефисы
+	Title       string                `json:"title" binding:"required"`
+	Description string                `json:"description"`
+	Position    int                   `json:"position"`             // при создании не учитывается
+	Retention   *setRetentionRequest  `json:"retention,omitempty"`  // нет — глобальный порог
+	RateLimit   *roomRateLimitRequest `json:"rate_limit,omitempty"` // нет — только общий лимит на сообщения
+}
+
+type roomRateLimitResponse struct {
+	Burst         int `json:"burst"`
+	PeriodSeconds int `json:"period_seconds"`
+}
+
+type roomResponse struct {
+	ID          int64                  `json:"id"`
+	Slug        string                 `json:"slug"`
+	Title       string                 `json:"title"`
+	Description string                 `json:"description"`
+	Position    int                    `json:"position"`
+	Retention   *retentionResponse     `json:"retention,omitempty"`  // нет — действует глобальный порог
+	RateLimit   *roomRateLimitResponse `json:"rate_limit,omitempty"` // нет — только общий лимит на сообщения
+	Online      int                    `json:"online"`               // подключений к каналу комнаты сейчас
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+type roomMessageRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+type roomMessageResponse struct {
+	ID          int64      `json:"id"`
+	RoomID      int64      `json:"room_id"`
+	AuthorID    int64      `json:"author_id"`
+	AuthorName  string     `json:"author_name"`
+	Content     string     `json:"content"`
+	ContentHTML string     `json:"content_html"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+type roomViewerResponse struct {
+	UserID int64  `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+type roomPresenceResponse struct {
+	RoomID    int64                `json:"room_id"`
+	Users     []roomViewerResponse `json:"users"`
+	Anonymous int                  `json:"anonymous"` // подключения без входа
+}
+
+type updateProfileRequest struct {
+	Bio       string `json:"bio"`
+	Location  string `json:"location"`
+	Website   string `json:"website"` // http(s)-ссылка или пусто
+	Signature string `json:"signature"`
+}
+
+// profileResponse — публичный профиль; email здесь нет и быть не должно
+type profileResponse struct {
+	ID             int64                     `json:"id"`
+	Username       string                    `json:"username"`
+	Name           string                    `json:"name"`
+	Bio            string                    `json:"bio"`
+	Location       string                    `json:"location"`
+	Website        string                    `json:"website"`
+	Signature      string                    `json:"signature"`
+	AvatarURL      string                    `json:"avatar_url,omitempty"` // меняется при смене аватара, поэтому кэшируется надолго
+	JoinedAt       time.Time                 `json:"joined_at"`
+	Stats          *profileStatsResponse     `json:"stats,omitempty"` // только в публичном профиле
+	RecentActivity []profileActivityResponse `json:"recent_activity,omitempty"`
+}
+
+type profileStatsResponse struct {
+	Topics       int64      `json:"topics"`
+	Messages     int64      `json:"messages"`
+	LastActiveAt *time.Time `json:"last_active_at,omitempty"`
+	Reputation   int64      `json:"reputation"`
+}
+
+type profileActivityResponse struct {
+	MessageID  int64     `json:"message_id"`
+	TopicID    int64     `json:"topic_id"`
+	TopicTitle string    `json:"topic_title"`
+	Excerpt    string    `json:"excerpt"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,313 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"chat-service/internal/entity"
+	"chat-service/internal/filter"
+	"chat-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type FilterHandler struct {
+	uc usecase.FilterUsecase
+}
+
+func NewFilterHandler(uc usecase.FilterUsecase) *FilterHandler {
+	return &FilterHandler{uc: uc}
+}
+
+func toFilterRuleResponse(r *entity.FilterRule) filterRuleResponse {
+	return filterRuleResponse{
+		ID:        r.ID,
+		Kind:      r.Kind,
+		Pattern:   r.Pattern,
+		Action:    string(r.Action),
+		CreatedBy: r.CreatedBy,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func toFilterSettingsResponse(s *entity.FilterSettings) filterSettingsResponse {
+	return filterSettingsResponse{
+		MaxLength:          s.MaxLength,
+		LengthAction:       string(s.LengthAction),
+		MaxLinks:           s.MaxLinks,
+		LinkFloodAction:    string(s.LinkFloodAction),
+		UnlistedLinkAction: string(s.UnlistedLinkAction),
+		DuplicateWindowSec: int(s.DuplicateWindow / time.Second),
+		DuplicateAction:    string(s.DuplicateAction),
+		UpdatedBy:          s.UpdatedBy,
+		UpdatedAt:          s.UpdatedAt,
+	}
+}
+
+func toFilterHitResponse(h filter.Hit) filterHitResponse {
+	return filterHitResponse{
+		Filter: h.Filter,
+		Action: string(h.Action),
+		Reason: h.Reason,
+		Detail: h.Detail,
+	}
+}
+
+// abortIfRejected отвечает 422 с кодом "content_rejected", если текст не прошёл фильтры
+func abortIfRejected(c *gin.Context, err error) bool {
+	var rejected *usecase.ContentRejectedError
+	if !errors.As(err, &rejected) {
+		return false
+	}
+	c.AbortWithStatusJSON(http.StatusUnprocessableEntity, ErrorResponse{
+		Code:    "content_rejected",
+		Message: rejected.Error(),
+	})
+	return true
+}
+
+func (h *FilterHandler) writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrUnauthenticated):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+	case errors.Is(err, usecase.ErrForbidden):
+		c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden: insufficient privileges"})
+	case errors.Is(err, usecase.ErrInvalidFilterRule), errors.Is(err, usecase.ErrInvalidFilterSettings):
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	case errors.Is(err, usecase.ErrFilterRuleNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: "filter rule not found"})
+	case errors.Is(err, usecase.ErrFilterRuleExists):
+		c.AbortWithStatusJSON(http.StatusConflict, ErrorResponse{Message: "filter rule already exists"})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
+
+// ListFilterRules — GET /admin/filters/rules
+// @Summary      Content filter rules (admin only)
+// @Tags         Filters
+// @Produce      json
+// @Success      200  {array}   filterRuleResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/filters/rules [get]
+func (h *FilterHandler) ListFilterRules(c *gin.Context) {
+	list, err := h.uc.ListFilterRules(c.Request.Context())
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	resp := make([]filterRuleResponse, 0, len(list))
+	for _, r := range list {
+		resp = append(resp, toFilterRuleResponse(r))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreateFilterRule — POST /admin/filters/rules
+// @Summary      Add content filter rule (admin only)
+// @Description  word — banned word, matched with its word forms, leetspeak and look-alike letters; link_deny — banned domain with subdomains; link_allow — allowed domain (no action). The rule applies immediately.
+// @Tags         Filters
+// @Accept       json
+// @Produce      json
+// @Param        request  body      filterRuleRequest  true  "Rule"
+// @Success      201      {object}  filterRuleResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/filters/rules [post]
+func (h *FilterHandler) CreateFilterRule(c *gin.Context) {
+	var req filterRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	r := &entity.FilterRule{
+		Kind:    req.Kind,
+		Pattern: req.Pattern,
+		Action:  entity.FilterAction(req.Action),
+	}
+	if err := h.uc.CreateFilterRule(c.Request.Context(), r); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toFilterRuleResponse(r))
+}
+
+// UpdateFilterRule — PUT /admin/filters/rules/{id}
+// @Summary      Change content filter rule (admin only)
+// @Description  Changes the pattern and action; the kind of the rule stays the same.
+// @Tags         Filters
+// @Accept       json
+// @Param        id       path  int                      true  "Rule ID"
+// @Param        request  body  updateFilterRuleRequest  true  "Rule"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/filters/rules/{id} [put]
+func (h *FilterHandler) UpdateFilterRule(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid id"})
+		return
+	}
+
+	var req updateFilterRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	r := &entity.FilterRule{
+		ID:      id,
+		Pattern: req.Pattern,
+		Action:  entity.FilterAction(req.Action),
+	}
+	if err := h.uc.UpdateFilterRule(c.Request.Context(), r); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteFilterRule — DELETE /admin/filters/rules/{id}
+// @Summary      Delete content filter rule (admin only)
+// @Tags         Filters
+// @Param        id  path  int  true  "Rule ID"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/filters/rules/{id} [delete]
+func (h *FilterHandler) DeleteFilterRule(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid id"})
+		return
+	}
+
+	if err := h.uc.DeleteFilterRule(c.Request.Context(), id); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetFilterSettings — GET /admin/filters/settings
+// @Summary      Content filter settings (admin only)
+// @Tags         Filters
+// @Produce      json
+// @Success      200  {object}  filterSettingsResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/filters/settings [get]
+func (h *FilterHandler) GetFilterSettings(c *gin.Context) {
+	s, err := h.uc.GetFilterSettings(c.Request.Context())
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toFilterSettingsResponse(s))
+}
+
+// UpdateFilterSettings — PUT /admin/filters/settings
+// @Summary      Change content filter settings (admin only)
+// @Description  Length, link flood and duplicate limits with their actions. A zero limit disables the check.
+// @Tags         Filters
+// @Accept       json
+// @Produce      json
+// @Param        request  body      filterSettingsRequest  true  "Settings"
+// @Success      200      {object}  filterSettingsResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/filters/settings [put]
+func (h *FilterHandler) UpdateFilterSettings(c *gin.Context) {
+	var req filterSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	s := &entity.FilterSettings{
+		MaxLength:          req.MaxLength,
+		LengthAction:       entity.FilterAction(req.LengthAction),
+		MaxLinks:           req.MaxLinks,
+		LinkFloodAction:    entity.FilterAction(req.LinkFloodAction),
+		UnlistedLinkAction: entity.FilterAction(req.UnlistedLinkAction),
+		DuplicateWindow:    time.Duration(req.DuplicateWindowSec) * time.Second,
+		DuplicateAction:    entity.FilterAction(req.DuplicateAction),
+	}
+	if err := h.uc.UpdateFilterSettings(c.Request.Context(), s); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toFilterSettingsResponse(s))
+}
+
+// TestFilter — POST /admin/filters/test
+// @Summary      Dry-run content filters (admin only)
+// @Description  Runs the text through the current rules without saving anything. Duplicate check is skipped.
+// @Tags         Filters
+// @Accept       json
+// @Produce      json
+// @Param        request  body      filterTestRequest  true  "Text"
+// @Success      200      {object}  filterTestResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/filters/test [post]
+func (h *FilterHandler) TestFilter(c *gin.Context) {
+	var req filterTestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	v, err := h.uc.TestContent(c.Request.Context(), req.Text)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	resp := filterTestResponse{
+		Text:   v.Text,
+		Flags:  make([]filterHitResponse, 0, len(v.Flags)),
+		Masked: v.Masked,
+	}
+	if v.Rejected != nil {
+		hit := toFilterHitResponse(*v.Rejected)
+		resp.Rejected = &hit
+	}
+	for _, f := range v.Flags {
+		resp.Flags = append(resp.Flags, toFilterHitResponse(f))
+	}
+	c.JSON(http.StatusOK, resp)
+}
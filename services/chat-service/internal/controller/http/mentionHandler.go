@@ -0,0 +1,62 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"chat-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type MentionHandler struct {
+	uc usecase.MentionUsecase
+}
+
+func NewMentionHandler(uc usecase.MentionUsecase) *MentionHandler {
+	return &MentionHandler{uc: uc}
+}
+
+// ListMyMentions — GET /me/mentions
+// @Summary      Messages mentioning me
+// @Description  Messages where the current user was mentioned as @username, newest first. Deleted and hidden messages are skipped. New mentions also appear in GET /notifications and are pushed live to /ws/me
+// @Tags         Mentions
+// @Produce      json
+// @Param        limit   query     int  false  "Page size (default 50, max 200)"
+// @Param        offset  query     int  false  "Offset"
+// @Success      200  {object}  mentionListResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /me/mentions [get]
+func (h *MentionHandler) ListMyMentions(c *gin.Context) {
+	var q pageQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	list, total, err := h.uc.ListMyMentions(c.Request.Context(), q.Limit, q.Offset)
+	if errors.Is(err, usecase.ErrUnauthenticated) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+		return
+	} else if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		return
+	}
+
+	resp := mentionListResponse{
+		Items:  make([]mentionResponse, 0, len(list)),
+		Total:  total,
+		Limit:  q.Limit,
+		Offset: q.Offset,
+	}
+	for _, m := range list {
+		resp.Items = append(resp.Items, mentionResponse{
+			Message:     toMessageResponse(m.Message),
+			TopicTitle:  m.TopicTitle,
+			MentionedAt: m.CreatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -4,25 +4,56 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
+	"chat-service/internal/entity"
 	"chat-service/internal/usecase"
 	"github.com/gin-gonic/gin"
 )
 
 type MessageHandler struct {
-	uc usecase.MessageUsecase
+	uc                    usecase.MessageUsecase
+	votes                 usecase.VoteUsecase
+	defaultThresholdHours int // глобальный порог очистки (CLEANUP_THRESHOLD_HOURS) для dry-run
 }
 
-func NewMessageHandler(uc usecase.MessageUsecase) *MessageHandler {
-	return &MessageHandler{uc: uc}
+func NewMessageHandler(uc usecase.MessageUsecase, votes usecase.VoteUsecase, defaultThresholdHours int) *MessageHandler {
+	return &MessageHandler{uc: uc, votes: votes, defaultThresholdHours: defaultThresholdHours}
+}
+
+func toMessageResponse(m *entity.Message) messageResponse {
+	resp := messageResponse{
+		ID:           m.ID,
+		TopicID:      m.TopicID,
+		AuthorID:     m.AuthorID,
+		AuthorName:   m.AuthorName, // будет "", если не наполняли — это ок
+		Content:      m.Content,
+		ContentHTML:  m.ContentHTML,
+		CreatedAt:    m.CreatedAt.Unix(),
+		Deleted:      m.IsDeleted(),
+		DeletedBy:    m.DeletedBy,
+		DeleteReason: m.DeleteReason,
+		Hidden:       m.Hidden,
+		QuoteID:      m.QuoteID,
+		Attachments:  toAttachmentResponses(m.Attachments),
+		Score:        m.Score,
+		MyVote:       m.MyVote,
+		Accepted:     m.Accepted,
+	}
+	if m.DeletedAt != nil {
+		ts := m.DeletedAt.Unix()
+		resp.DeletedAt = &ts
+	}
+	return resp
 }
 
 // GetMessages — GET /topics/{id}/messages
 // @Summary      List messages
-// @Description  Returns all messages in a topic
+// @Description  Returns all messages in a topic; deleted messages are returned as tombstones without content. content is the Markdown source, content_html is sanitized HTML rendered from it. order=score puts the highest-voted messages first. In Q&A topics the accepted answer always comes first. With a token, my_vote shows my vote.
 // @Tags         Message
 // @Produce      json
-// @Param        id  path      int  true  "Topic ID"
+// @Param        id     path      int     true   "Topic ID"
+// @Param        order  query     string  false  "oldest (default) or score"  Enums(oldest, score)
 // @Success      200      {array}   messageResponse
 // @Failure      400      {object}  ErrorResponse
 // @Failure      500      {object}  ErrorResponse
@@ -34,22 +65,25 @@ func (h *MessageHandler) GetMessages(c *gin.Context) {
 		return
 	}
 
-	list, err := h.uc.GetMessages(c.Request.Context(), tid)
+	var q messagesQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+	if q.Order == "" {
+		q.Order = usecase.OrderOldest
+	}
+
+	list, err := h.uc.GetMessages(c.Request.Context(), tid, q.Order)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: err.Error()})
 		return
 	}
+	h.votes.AnnotateMessages(c.Request.Context(), list...)
 
 	resp := make([]messageResponse, 0, len(list))
 	for _, m := range list {
-		resp = append(resp, messageResponse{
-			ID:         m.ID,
-			TopicID:    m.TopicID,
-			AuthorID:   m.AuthorID,
-			AuthorName: m.AuthorName, // добавлено
-			Content:    m.Content,
-			CreatedAt:  m.CreatedAt.Unix(),
-		})
+		resp = append(resp, toMessageResponse(m))
 	}
 
 	c.JSON(http.StatusOK, resp)
@@ -57,7 +91,7 @@ func (h *MessageHandler) GetMessages(c *gin.Context) {
 
 // SendMessage — POST /topics/{id}/messages
 // @Summary      Send message
-// @Description  Creates a new message in topic
+// @Description  Creates a new message in topic. Locked topics reject new messages with 423 and code "topic_locked"; a muted user gets 403 with code "muted" and the mute expiry. Content filters may mask parts of the text, send it to moderators, or reject it with 422 and code "content_rejected". Links need enough reputation, otherwise 403 with code "insufficient_reputation". The topic author, the author of the quoted message and mentioned users are notified. Files uploaded via POST /attachments are attached by attachment_ids; content may be empty when attachments are present.
 // @Tags         Message
 // @Accept       json
 // @Produce      json
@@ -65,6 +99,11 @@ func (h *MessageHandler) GetMessages(c *gin.Context) {
 // @Param        request  body      sendMessageRequest  true  "Message text"
 // @Success      201      {object}  messageResponse
 // @Failure      400      {object}  ErrorResponse
+// @Failure      403      {object}  mutedResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      422      {object}  ErrorResponse
+// @Failure      423      {object}  ErrorResponse
+// @Failure      429      {object}  ErrorResponse  "Too many requests, see Retry-After"
 // @Failure      500      {object}  ErrorResponse
 // @Security     BearerAuth
 // @Router       /topics/{id}/messages [post]
@@ -84,35 +123,45 @@ func (h *MessageHandler) SendMessage(c *gin.Context) {
 	authorID, _ := UserIDFromCtx(c.Request.Context())
 
 	msg, err := h.uc.SendMessage(c.Request.Context(), usecase.SendMessageParams{
-		TopicID:  tid,
-		AuthorID: authorID,
-		Content:  req.Content,
+		TopicID:       tid,
+		AuthorID:      authorID,
+		Content:       req.Content,
+		QuoteID:       req.QuoteMessageID,
+		AttachmentIDs: req.AttachmentIDs,
 	})
 	if err != nil {
+		if abortIfMuted(c, err) {
+			return
+		}
+		if abortIfRejected(c, err) || abortIfUnprivileged(c, err) {
+			return
+		}
 		switch {
 		case errors.Is(err, usecase.ErrUnauthenticated):
 			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
 		case errors.Is(err, usecase.ErrForbidden):
 			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden: insufficient privileges"})
+		case errors.Is(err, usecase.ErrTopicNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: "topic not found"})
+		case errors.Is(err, usecase.ErrInvalidQuote),
+			errors.Is(err, usecase.ErrEmptyMessage),
+			errors.Is(err, usecase.ErrInvalidAttachment),
+			errors.Is(err, usecase.ErrTooManyAttachments):
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		case errors.Is(err, usecase.ErrTopicLocked):
+			c.AbortWithStatusJSON(http.StatusLocked, ErrorResponse{Code: "topic_locked", Message: err.Error()})
 		default:
 			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
 		}
 		return
 	}
 
-	c.JSON(http.StatusCreated, messageResponse{
-		ID:         msg.ID,
-		TopicID:    msg.TopicID,
-		AuthorID:   msg.AuthorID,
-		AuthorName: msg.AuthorName, // будет "", если не наполняли — это ок
-		Content:    msg.Content,
-		CreatedAt:  msg.CreatedAt.Unix(),
-	})
+	c.JSON(http.StatusCreated, toMessageResponse(msg))
 }
 
 // UpdateMessage — PUT /messages/{id}
 // @Summary      Update message
-// @Description  Changes text of a message
+// @Description  Changes text of a message. Adding links needs enough reputation, otherwise 403 with code "insufficient_reputation".
 // @Tags         Message
 // @Accept       json
 // @Produce      json
@@ -123,6 +172,8 @@ func (h *MessageHandler) SendMessage(c *gin.Context) {
 // @Failure      401      {object}  ErrorResponse  "Unauthorized"
 // @Failure      403      {object}  ErrorResponse  "Forbidden"
 // @Failure      404      {object}  ErrorResponse
+// @Failure      422      {object}  ErrorResponse
+// @Failure      429      {object}  ErrorResponse  "Too many requests, see Retry-After"
 // @Failure      500      {object}  ErrorResponse
 // @Security     BearerAuth
 // @Router       /messages/{id} [put]
@@ -141,6 +192,9 @@ func (h *MessageHandler) UpdateMessage(c *gin.Context) {
 
 	err = h.uc.UpdateMessage(c.Request.Context(), id, req.Content)
 	if err != nil {
+		if abortIfRejected(c, err) || abortIfUnprivileged(c, err) {
+			return
+		}
 		switch err {
 		case usecase.ErrUnauthenticated:
 			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
@@ -159,9 +213,10 @@ func (h *MessageHandler) UpdateMessage(c *gin.Context) {
 
 // DeleteMessage — DELETE /messages/{id}
 // @Summary      Delete message
-// @Description  Removes a message
+// @Description  Soft-deletes a message; it stays in the topic as a tombstone and can be restored by an admin
 // @Tags         Message
-// @Param        id   path      int  true  "Message ID"
+// @Param        id      path      int     true   "Message ID"
+// @Param        reason  query     string  false  "Deletion reason"
 // @Success      204
 // @Failure      400  {object}  ErrorResponse
 // @Failure      401  {object}  ErrorResponse  "Unauthorized"
@@ -177,7 +232,7 @@ func (h *MessageHandler) DeleteMessage(c *gin.Context) {
 		return
 	}
 
-	err = h.uc.DeleteMessage(c.Request.Context(), id)
+	err = h.uc.DeleteMessage(c.Request.Context(), id, c.Query("reason"))
 	if err != nil {
 		switch err {
 		case usecase.ErrUnauthenticated:
@@ -194,3 +249,192 @@ func (h *MessageHandler) DeleteMessage(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// ListDeletedMessages — GET /admin/messages/deleted
+// @Summary      List deleted messages (admin only)
+// @Description  Returns soft-deleted messages that are still inside the restore window
+// @Tags         Moderation
+// @Produce      json
+// @Success      200  {array}   messageResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/messages/deleted [get]
+func (h *MessageHandler) ListDeletedMessages(c *gin.Context) {
+	list, err := h.uc.ListDeletedMessages(c.Request.Context())
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnauthenticated):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+		case errors.Is(err, usecase.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden: insufficient privileges"})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+		return
+	}
+
+	resp := make([]messageResponse, 0, len(list))
+	for _, m := range list {
+		resp = append(resp, toMessageResponse(m))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RestoreMessage — POST /admin/messages/{id}/restore
+// @Summary      Restore deleted message (admin only)
+// @Description  Restores a soft-deleted message if it is still inside the restore window
+// @Tags         Moderation
+// @Param        id   path      int  true  "Message ID"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/messages/{id}/restore [post]
+func (h *MessageHandler) RestoreMessage(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid message id"})
+		return
+	}
+
+	if err := h.uc.RestoreMessage(c.Request.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnauthenticated):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+		case errors.Is(err, usecase.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden: insufficient privileges"})
+		case errors.Is(err, usecase.ErrMessageNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: "message not found or restore window expired"})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PreviewCleanup — GET /admin/retention/preview
+// @Summary      Dry-run message cleanup (admin only)
+// @Description  Reports, per topic, how many messages the cleanup job would purge under the current retention policies. Nothing is deleted.
+// @Tags         Retention
+// @Produce      json
+// @Param        threshold_hours  query     int  false  "Global threshold for topics without a policy (defaults to CLEANUP_THRESHOLD_HOURS)"
+// @Success      200  {object}  cleanupReportResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/retention/preview [get]
+func (h *MessageHandler) PreviewCleanup(c *gin.Context) {
+	hours := h.defaultThresholdHours
+	if raw := c.Query("threshold_hours"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid threshold_hours"})
+			return
+		}
+		hours = v
+	}
+	threshold := time.Now().UTC().Add(-time.Duration(hours) * time.Hour)
+
+	report, err := h.uc.PreviewCleanup(c.Request.Context(), threshold)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnauthenticated):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+		case errors.Is(err, usecase.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden"})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+		return
+	}
+
+	resp := cleanupReportResponse{
+		DryRun: report.DryRun,
+		Total:  report.Total,
+		Topics: make([]retentionStatResponse, 0, len(report.Topics)),
+	}
+	for _, st := range report.Topics {
+		resp.Topics = append(resp.Topics, retentionStatResponse{
+			TopicID:    st.TopicID,
+			CategoryID: st.CategoryID,
+			Policy:     string(st.Mode),
+			Count:      st.Count,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AcceptAnswer — PUT /messages/{id}/accepted
+// @Summary      Mark message as the accepted answer
+// @Description  In a Q&A category the topic author or a category moderator marks the message as the topic's accepted answer; it replaces the previous one. Topic subscribers get an answer_accepted event.
+// @Tags         Message
+// @Param        id   path      int  true  "Message ID"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse  "Not a Q&A category"
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /messages/{id}/accepted [put]
+func (h *MessageHandler) AcceptAnswer(c *gin.Context) {
+	h.setAccepted(c, true)
+}
+
+// UnacceptAnswer — DELETE /messages/{id}/accepted
+// @Summary      Unmark the accepted answer
+// @Description  Removes the accepted answer mark from the message; the topic becomes unsolved. Topic subscribers get an answer_unaccepted event.
+// @Tags         Message
+// @Param        id   path      int  true  "Message ID"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse  "Not a Q&A category"
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /messages/{id}/accepted [delete]
+func (h *MessageHandler) UnacceptAnswer(c *gin.Context) {
+	h.setAccepted(c, false)
+}
+
+func (h *MessageHandler) setAccepted(c *gin.Context, accepted bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid message id"})
+		return
+	}
+
+	if err := h.uc.SetAcceptedAnswer(c.Request.Context(), id, accepted); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnauthenticated):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+		case errors.Is(err, usecase.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden: only the topic author or a moderator can do this"})
+		case errors.Is(err, usecase.ErrMessageNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: "message not found"})
+		case errors.Is(err, usecase.ErrTopicNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: "topic not found"})
+		case errors.Is(err, usecase.ErrNotQACategory):
+			c.AbortWithStatusJSON(http.StatusConflict, ErrorResponse{Message: err.Error()})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -2,15 +2,20 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/ZoyaDenisova/go-common/contextkeys"
 	"github.com/ZoyaDenisova/go-common/logger"
 	"google.golang.org/grpc/metadata"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	authpb "chat-service/cmd/app/docs/proto"
+	"chat-service/internal/auth"
+	"chat-service/internal/usecase"
 	"github.com/gin-gonic/gin"
 )
 
@@ -20,41 +25,68 @@ func UserIDFromCtx(ctx context.Context) (int64, string) {
 	return uid, role
 }
 
-func AuthMiddleware(authClient authpb.AuthServiceClient) gin.HandlerFunc {
+func AuthMiddleware(authClient authpb.AuthServiceClient, log logger.Interface) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		const bearer = "Bearer "
-		header := c.GetHeader("Authorization")
-		if !strings.HasPrefix(header, bearer) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "missing bearer token"})
+		authenticate(c, authClient, log)
+	}
+}
+
+// OptionalAuthMiddleware — для публичных маршрутов, где вошедшему пользователю отдаётся больше
+// (например, непрочитанное): без заголовка Authorization запрос идёт анонимно,
+// а присланный токен проверяется так же строго, как в AuthMiddleware.
+func OptionalAuthMiddleware(authClient authpb.AuthServiceClient, log logger.Interface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
 			return
 		}
-		token := strings.TrimPrefix(header, bearer)
+		authenticate(c, authClient, log)
+	}
+}
 
-		md := metadata.New(map[string]string{
-			"authorization": bearer + token,
-		})
-		ctx := metadata.NewOutgoingContext(c.Request.Context(), md)
+func authenticate(c *gin.Context, authClient authpb.AuthServiceClient, log logger.Interface) {
+	const bearer = "Bearer "
+	header := c.GetHeader("Authorization")
+	if !strings.HasPrefix(header, bearer) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "missing bearer token"})
+		return
+	}
+	token := strings.TrimPrefix(header, bearer)
 
-		fmt.Printf("outgoing jwt: value=%s\n", token) //убрать
+	md := metadata.New(map[string]string{
+		"authorization": bearer + token,
+	})
+	ctx := metadata.NewOutgoingContext(c.Request.Context(), md)
 
-		resp, err := authClient.VerifyToken(ctx, &authpb.VerifyTokenRequest{
-			AccessToken: token,
-		})
-		fmt.Println("err", err)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "invalid token"})
-			return
-		}
+	resp, err := authClient.VerifyToken(ctx, &authpb.VerifyTokenRequest{
+		AccessToken: token,
+	})
+	if err != nil {
+		log.Warn("token verification failed", "path", c.FullPath(), "err", err)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "invalid token"})
+		return
+	}
 
-		ctx = context.WithValue(c.Request.Context(), contextkeys.UserIDKey{}, resp.UserId)
-		ctx = context.WithValue(ctx, contextkeys.RoleKey{}, resp.Role)
-		c.Request = c.Request.WithContext(ctx)
+	ctx = context.WithValue(c.Request.Context(), contextkeys.UserIDKey{}, resp.UserId)
+	ctx = context.WithValue(ctx, contextkeys.RoleKey{}, resp.Role)
+	ctx = auth.WithToken(ctx, token)
+	c.Request = c.Request.WithContext(ctx)
+
+	c.Next()
+}
 
+// LoggingMiddleware логирует каждый HTTP-запрос
+// QueryTokenMiddleware берёт токен из ?access_token=, если заголовка Authorization нет:
+// браузер не может передать заголовок при открытии WebSocket. Подключается перед AuthMiddleware.
+func QueryTokenMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := c.Query("access_token"); token != "" && c.GetHeader("Authorization") == "" {
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
 		c.Next()
 	}
 }
 
-// LoggingMiddleware логирует каждый HTTP-запрос
 func LoggingMiddleware(log logger.Interface) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
@@ -67,3 +99,39 @@ func LoggingMiddleware(log logger.Interface) gin.HandlerFunc {
 		)
 	}
 }
+
+// RateLimitMiddleware ограничивает частоту действия action для текущего пользователя и его IP.
+// Ставит заголовки RateLimit-Limit/Remaining/Reset/Policy, а при превышении отвечает 429 с Retry-After.
+// Подключается после AuthMiddleware.
+func RateLimitMiddleware(uc usecase.RateLimitUsecase, action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		res, err := uc.Allow(c.Request.Context(), action, c.ClientIP())
+		if res != nil {
+			h := c.Writer.Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(res.Limit.Burst))
+			h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+			h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", res.Limit.Burst, ceilSeconds(res.Limit.Period)))
+		}
+		if abortIfRateLimited(c, err) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// abortIfRateLimited отвечает 429 с Retry-After и кодом "rate_limited", если лимит исчерпан
+func abortIfRateLimited(c *gin.Context, err error) bool {
+	var limited *usecase.RateLimitedError
+	if !errors.As(err, &limited) {
+		return false
+	}
+	c.Header("Retry-After", strconv.Itoa(max(1, ceilSeconds(limited.Result.RetryAfter))))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, ErrorResponse{Code: "rate_limited", Message: limited.Error()})
+	return true
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,125 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"chat-service/internal/entity"
+	"chat-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type ModerationHandler struct {
+	uc usecase.ModerationUsecase
+}
+
+func NewModerationHandler(uc usecase.ModerationUsecase) *ModerationHandler {
+	return &ModerationHandler{uc: uc}
+}
+
+// ListModerationLog — GET /admin/moderation-log
+// @Summary      Browse moderation log (admin only)
+// @Description  Newest first. Filters are combined with AND.
+// @Tags         Moderation
+// @Produce      json
+// @Param        actor_id     query     int     false  "Moderator who acted"
+// @Param        author_id    query     int     false  "Author of the affected content"
+// @Param        target_type  query     string  false  "message or topic"
+// @Param        target_id    query     int     false  "Message or topic ID"
+// @Param        action       query     string  false  "Action, e.g. message.delete"
+// @Param        limit        query     int     false  "Page size (default 50, max 200)"
+// @Param        offset       query     int     false  "Offset"
+// @Success      200  {object}  moderationLogResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/moderation-log [get]
+func (h *ModerationHandler) ListModerationLog(c *gin.Context) {
+	var q moderationLogQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	list, total, err := h.uc.ListModerationLog(c.Request.Context(), entity.ModerationFilter{
+		ActorID:        q.ActorID,
+		TargetAuthorID: q.AuthorID,
+		TargetType:     q.TargetType,
+		TargetID:       q.TargetID,
+		Action:         q.Action,
+		Limit:          q.Limit,
+		Offset:         q.Offset,
+	})
+	if err != nil {
+		moderationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toModerationLogResponse(list, total, q.Limit, q.Offset))
+}
+
+// ListMyModerationLog — GET /me/moderation-log
+// @Summary      Moderation actions on my content
+// @Description  Deletions, restores, moves and merges of the current user's messages and topics, newest first
+// @Tags         Moderation
+// @Produce      json
+// @Param        limit   query     int  false  "Page size (default 50, max 200)"
+// @Param        offset  query     int  false  "Offset"
+// @Success      200  {object}  moderationLogResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /me/moderation-log [get]
+func (h *ModerationHandler) ListMyModerationLog(c *gin.Context) {
+	var q pageQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	list, total, err := h.uc.ListMyModerationLog(c.Request.Context(), q.Limit, q.Offset)
+	if err != nil {
+		moderationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toModerationLogResponse(list, total, q.Limit, q.Offset))
+}
+
+func moderationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrUnauthenticated):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+	case errors.Is(err, usecase.ErrForbidden):
+		c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden"})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
+
+func toModerationLogResponse(list []*entity.ModerationEntry, total int64, limit, offset int) moderationLogResponse {
+	resp := moderationLogResponse{
+		Items:  make([]moderationEntryResponse, 0, len(list)),
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+	for _, e := range list {
+		resp.Items = append(resp.Items, moderationEntryResponse{
+			ID:             e.ID,
+			ActorID:        e.ActorID,
+			Action:         e.Action,
+			TargetType:     e.TargetType,
+			TargetID:       e.TargetID,
+			TargetAuthorID: e.TargetAuthorID,
+			Reason:         e.Reason,
+			Snapshot:       e.Snapshot,
+			Details:        e.Details,
+			CreatedAt:      e.CreatedAt,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,198 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"chat-service/internal/entity"
+	"chat-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type MuteHandler struct {
+	uc usecase.MuteUsecase
+}
+
+func NewMuteHandler(uc usecase.MuteUsecase) *MuteHandler {
+	return &MuteHandler{uc: uc}
+}
+
+func toMuteResponse(m *entity.Mute) muteResponse {
+	return muteResponse{
+		ID:         m.ID,
+		UserID:     m.UserID,
+		TopicID:    m.TopicID,
+		CategoryID: m.CategoryID,
+		Reason:     m.Reason,
+		MutedBy:    m.MutedBy,
+		ExpiresAt:  m.ExpiresAt,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+func toMuteList(list []*entity.Mute) []muteResponse {
+	resp := make([]muteResponse, 0, len(list))
+	for _, m := range list {
+		resp = append(resp, toMuteResponse(m))
+	}
+	return resp
+}
+
+// abortIfMuted отвечает 403 с кодом "muted" и сроком мьюта, если err — *usecase.MutedError
+func abortIfMuted(c *gin.Context, err error) bool {
+	var muted *usecase.MutedError
+	if !errors.As(err, &muted) {
+		return false
+	}
+	c.AbortWithStatusJSON(http.StatusForbidden, mutedResponse{
+		Code:       "muted",
+		Message:    muted.Error(),
+		MutedUntil: muted.Mute.ExpiresAt,
+		Reason:     muted.Mute.Reason,
+	})
+	return true
+}
+
+func (h *MuteHandler) writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrUnauthenticated):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+	case errors.Is(err, usecase.ErrForbidden):
+		c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden: insufficient privileges"})
+	case errors.Is(err, usecase.ErrInvalidMute):
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	case errors.Is(err, usecase.ErrMuteReference):
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, ErrorResponse{Message: err.Error()})
+	case errors.Is(err, usecase.ErrTopicNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: "topic not found"})
+	case errors.Is(err, usecase.ErrMuteNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: "mute not found"})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
+
+// CreateMute — POST /admin/mutes
+// @Summary      Mute user in topic or category
+// @Description  Forbids the user to post in one topic or in a category with its subcategories. Set exactly one of topic_id and category_id; without duration the mute is permanent. Moderators can mute only in their categories.
+// @Tags         Moderation
+// @Accept       json
+// @Produce      json
+// @Param        request  body      createMuteRequest  true  "Mute"
+// @Success      201      {object}  muteResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      422      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/mutes [post]
+func (h *MuteHandler) CreateMute(c *gin.Context) {
+	var req createMuteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	var duration time.Duration
+	if req.Duration != "" {
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil || d <= 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid duration"})
+			return
+		}
+		duration = d
+	}
+
+	m, err := h.uc.Mute(c.Request.Context(), usecase.MuteParams{
+		UserID:     req.UserID,
+		TopicID:    req.TopicID,
+		CategoryID: req.CategoryID,
+		Duration:   duration,
+		Reason:     req.Reason,
+	})
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toMuteResponse(m))
+}
+
+// DeleteMute — DELETE /admin/mutes/{id}
+// @Summary      Lift mute
+// @Tags         Moderation
+// @Param        id  path  int  true  "Mute ID"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/mutes/{id} [delete]
+func (h *MuteHandler) DeleteMute(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid id"})
+		return
+	}
+
+	if err := h.uc.Unmute(c.Request.Context(), id); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListMutes — GET /admin/mutes
+// @Summary      Active mutes (admin only)
+// @Tags         Moderation
+// @Produce      json
+// @Param        user_id  query     int  false  "Only mutes of this user"
+// @Success      200      {array}   muteResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/mutes [get]
+func (h *MuteHandler) ListMutes(c *gin.Context) {
+	var q muteQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	list, err := h.uc.ListMutes(c.Request.Context(), q.UserID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toMuteList(list))
+}
+
+// ListMyMutes — GET /me/mutes
+// @Summary      My active mutes
+// @Description  Where the current user cannot post and until when.
+// @Tags         Moderation
+// @Produce      json
+// @Success      200  {array}   muteResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /me/mutes [get]
+func (h *MuteHandler) ListMyMutes(c *gin.Context) {
+	list, err := h.uc.ListMyMutes(c.Request.Context())
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toMuteList(list))
+}
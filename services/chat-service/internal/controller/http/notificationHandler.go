@@ -0,0 +1,128 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"chat-service/internal/entity"
+	"chat-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	uc usecase.NotificationUsecase
+}
+
+func NewNotificationHandler(uc usecase.NotificationUsecase) *NotificationHandler {
+	return &NotificationHandler{uc: uc}
+}
+
+func toNotificationResponse(n *entity.Notification) notificationResponse {
+	return notificationResponse{
+		ID:        n.ID,
+		Type:      n.Type,
+		ActorID:   n.ActorID,
+		ActorName: n.ActorName,
+		TopicID:   n.TopicID,
+		MessageID: n.MessageID,
+		Data:      n.Data,
+		Read:      n.ReadAt != nil,
+		ReadAt:    n.ReadAt,
+		CreatedAt: n.CreatedAt,
+	}
+}
+
+// ListNotifications — GET /notifications
+// @Summary      My notifications
+// @Description  Replies in my topics, mentions, quotes of my messages and moderation actions on my content, newest first. The same notifications are pushed live to /ws/me as "notification" events. Type-specific details (excerpt, topic_title, moderation action and reason) are in data.
+// @Tags         Notifications
+// @Produce      json
+// @Param        unread  query     bool  false  "Only unread"
+// @Param        limit   query     int   false  "Page size (default 50, max 200)"
+// @Param        offset  query     int   false  "Offset"
+// @Success      200  {object}  notificationListResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /notifications [get]
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	var q notificationQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	list, total, unread, err := h.uc.ListNotifications(c.Request.Context(), q.Unread, q.Limit, q.Offset)
+	if err != nil {
+		notificationError(c, err)
+		return
+	}
+
+	resp := notificationListResponse{
+		Items:  make([]notificationResponse, 0, len(list)),
+		Total:  total,
+		Unread: unread,
+		Limit:  q.Limit,
+		Offset: q.Offset,
+	}
+	for _, n := range list {
+		resp.Items = append(resp.Items, toNotificationResponse(n))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// MarkNotificationRead — POST /notifications/{id}/read
+// @Summary      Mark notification read
+// @Tags         Notifications
+// @Param        id   path  int  true  "Notification ID"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /notifications/{id}/read [post]
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid notification id"})
+		return
+	}
+
+	if err := h.uc.MarkNotificationRead(c.Request.Context(), id); err != nil {
+		notificationError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// MarkAllNotificationsRead — POST /notifications/read-all
+// @Summary      Mark all notifications read
+// @Tags         Notifications
+// @Produce      json
+// @Success      200  {object}  markAllReadResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /notifications/read-all [post]
+func (h *NotificationHandler) MarkAllNotificationsRead(c *gin.Context) {
+	n, err := h.uc.MarkAllNotificationsRead(c.Request.Context())
+	if err != nil {
+		notificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, markAllReadResponse{Marked: n})
+}
+
+func notificationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrUnauthenticated):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+	case errors.Is(err, usecase.ErrNotificationNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: "notification not found"})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
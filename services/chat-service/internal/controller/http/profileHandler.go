@@ -0,0 +1,258 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"chat-service/internal/entity"
+	"chat-service/internal/media"
+	"chat-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileHandler struct {
+	uc            usecase.ProfileUsecase
+	avatarMaxSize int64 // предел тела запроса на загрузку аватара; точный лимит проверяет usecase
+}
+
+func NewProfileHandler(uc usecase.ProfileUsecase, avatarMaxSize int64) *ProfileHandler {
+	return &ProfileHandler{uc: uc, avatarMaxSize: avatarMaxSize}
+}
+
+func toProfileResponse(p *entity.Profile, withStats bool) profileResponse {
+	resp := profileResponse{
+		ID:        p.UserID,
+		Username:  p.Username,
+		Name:      p.Name,
+		Bio:       p.Bio,
+		Location:  p.Location,
+		Website:   p.Website,
+		Signature: p.Signature,
+		JoinedAt:  p.JoinedAt,
+	}
+	if p.HasAvatar() {
+		resp.AvatarURL = fmt.Sprintf("/users/%d/avatar", p.UserID)
+		if p.AvatarUpdatedAt != nil {
+			resp.AvatarURL += fmt.Sprintf("?v=%d", p.AvatarUpdatedAt.Unix())
+		}
+	}
+	if withStats {
+		resp.Stats = &profileStatsResponse{
+			Topics:       p.Stats.Topics,
+			Messages:     p.Stats.Messages,
+			LastActiveAt: p.Stats.LastActiveAt,
+			Reputation:   p.Stats.Reputation,
+		}
+		resp.RecentActivity = make([]profileActivityResponse, 0, len(p.Recent))
+		for _, a := range p.Recent {
+			resp.RecentActivity = append(resp.RecentActivity, profileActivityResponse{
+				MessageID:  a.MessageID,
+				TopicID:    a.TopicID,
+				TopicTitle: a.TopicTitle,
+				Excerpt:    a.Excerpt,
+				CreatedAt:  a.CreatedAt,
+			})
+		}
+	}
+	return resp
+}
+
+// GetProfile — GET /users/{id}/profile
+// @Summary      Get public profile
+// @Description  Returns the public profile of a user with stats and recent activity. Only topics and messages visible to everyone are counted and listed. Email is never included.
+// @Tags         Profile
+// @Produce      json
+// @Param        id  path      int  true  "User ID"
+// @Success      200  {object}  profileResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /users/{id}/profile [get]
+func (h *ProfileHandler) GetProfile(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid user id"})
+		return
+	}
+
+	p, err := h.uc.GetProfile(c.Request.Context(), id)
+	if err != nil {
+		profileError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toProfileResponse(p, true))
+}
+
+// GetProfileByUsername — GET /profiles/{username}
+// @Summary      Get public profile by username
+// @Description  Same as GET /users/{id}/profile, but looks the user up by username, case-insensitively.
+// @Tags         Profile
+// @Produce      json
+// @Param        username  path      string  true  "Username"
+// @Success      200       {object}  profileResponse
+// @Failure      404       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /profiles/{username} [get]
+func (h *ProfileHandler) GetProfileByUsername(c *gin.Context) {
+	p, err := h.uc.GetProfileByUsername(c.Request.Context(), c.Param("username"))
+	if err != nil {
+		profileError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toProfileResponse(p, true))
+}
+
+// UpdateProfile — PUT /me/profile
+// @Summary      Update my profile
+// @Description  Replaces bio, location, website and signature. Website must be an http or https link. Text goes through the content filter: parts may be masked, or the update rejected with 422 and code "content_rejected".
+// @Tags         Profile
+// @Accept       json
+// @Produce      json
+// @Param        request  body      updateProfileRequest  true  "Profile fields"
+// @Success      200      {object}  profileResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      422      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /me/profile [put]
+func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
+	var req updateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	p, err := h.uc.UpdateProfile(c.Request.Context(), usecase.ProfileParams{
+		Bio:       req.Bio,
+		Location:  req.Location,
+		Website:   req.Website,
+		Signature: req.Signature,
+	})
+	if err != nil {
+		profileError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toProfileResponse(p, false))
+}
+
+// SetAvatar — PUT /me/avatar
+// @Summary      Upload my avatar
+// @Description  Accepts a JPEG, PNG or GIF image; it is cropped to a centered square and scaled down. The previous avatar is deleted.
+// @Tags         Profile
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file  formData  file  true  "Image"
+// @Success      200   {object}  profileResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      413   {object}  ErrorResponse
+// @Failure      415   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /me/avatar [put]
+func (h *ProfileHandler) SetAvatar(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.avatarMaxSize+multipartOverhead)
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, ErrorResponse{Message: usecase.ErrAvatarTooLarge.Error()})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "file is required"})
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "cannot read file"})
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	p, err := h.uc.SetAvatar(c.Request.Context(), f)
+	if err != nil {
+		profileError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toProfileResponse(p, false))
+}
+
+// DeleteAvatar — DELETE /me/avatar
+// @Summary      Delete my avatar
+// @Tags         Profile
+// @Success      204
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /me/avatar [delete]
+func (h *ProfileHandler) DeleteAvatar(c *gin.Context) {
+	if err := h.uc.DeleteAvatar(c.Request.Context()); err != nil {
+		profileError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetAvatar — GET /users/{id}/avatar
+// @Summary      Get avatar image
+// @Description  Returns the avatar image (JPEG, or PNG for images with transparency). Use avatar_url from the profile: it changes with every new avatar.
+// @Tags         Profile
+// @Produce      image/jpeg
+// @Param        id  path  int  true  "User ID"
+// @Success      200
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /users/{id}/avatar [get]
+func (h *ProfileHandler) GetAvatar(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid user id"})
+		return
+	}
+
+	rc, err := h.uc.OpenAvatar(c.Request.Context(), id)
+	if err != nil {
+		profileError(c, err)
+		return
+	}
+	defer func() { _ = rc.Close() }()
+
+	// аватар небольшой; тип в базе не хранится — определяем по содержимому
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		return
+	}
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, media.Sniff(data), data)
+}
+
+func profileError(c *gin.Context, err error) {
+	if abortIfRejected(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, usecase.ErrUnauthenticated):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+	case errors.Is(err, usecase.ErrForbidden):
+		c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden: insufficient privileges"})
+	case errors.Is(err, usecase.ErrProfileNotFound),
+		errors.Is(err, usecase.ErrAvatarNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	case errors.Is(err, usecase.ErrInvalidProfile):
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	case errors.Is(err, usecase.ErrAvatarTooLarge):
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, ErrorResponse{Message: err.Error()})
+	case errors.Is(err, usecase.ErrAvatarType):
+		c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, ErrorResponse{Message: err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
@@ -0,0 +1,139 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"chat-service/internal/entity"
+	"chat-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type ReadHandler struct {
+	uc usecase.ReadUsecase
+}
+
+func NewReadHandler(uc usecase.ReadUsecase) *ReadHandler {
+	return &ReadHandler{uc: uc}
+}
+
+// MarkTopicRead — POST /topics/{id}/read
+// @Summary      Mark topic read
+// @Description  Moves my read position in the topic forward to message_id, or to the latest message when the body is empty. The position never moves back. Marking a merged topic marks the topic it was merged into. Other sessions get a "topic_read" event on /ws/me. The same can be done over /ws/topics/{id} by sending {"action":"read","message_id":...}.
+// @Tags         Unread
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int              true   "Topic ID"
+// @Param        request  body      markReadRequest  false  "Last read message; omit to mark everything read"
+// @Success      200      {object}  topicReadResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /topics/{id}/read [post]
+func (h *ReadHandler) MarkTopicRead(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid id"})
+		return
+	}
+	// тело необязательно: без него прочитано всё
+	var req markReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	rd, err := h.uc.MarkTopicRead(c.Request.Context(), id, req.MessageID)
+	if err != nil {
+		readError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, topicReadResponse{
+		TopicID:           rd.TopicID,
+		LastReadMessageID: rd.LastReadMessageID,
+		Unread:            rd.Unread,
+		UpdatedAt:         rd.UpdatedAt,
+	})
+}
+
+// Unread — GET /unread
+// @Summary      Unread summary
+// @Description  Unread messages across the forum: totals, a breakdown by category and the topics with the freshest unread messages. Only topics I have opened at least once are counted; my own, deleted and hidden messages are not.
+// @Tags         Unread
+// @Produce      json
+// @Param        limit  query     int  false  "How many topics to list (default 50, max 200)"
+// @Success      200    {object}  unreadResponse
+// @Failure      400    {object}  ErrorResponse
+// @Failure      401    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /unread [get]
+func (h *ReadHandler) Unread(c *gin.Context) {
+	var q unreadQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	s, err := h.uc.Unread(c.Request.Context(), q.Limit)
+	if err != nil {
+		readError(c, err)
+		return
+	}
+
+	resp := unreadResponse{
+		unreadCountResponse: unreadCountResponse{Topics: s.Topics, Messages: s.Messages},
+		Categories:          make([]unreadCategoryResponse, 0, len(s.Categories)),
+		TopicList:           make([]unreadTopicResponse, 0, len(s.TopicList)),
+	}
+	for _, cu := range s.Categories {
+		resp.Categories = append(resp.Categories, unreadCategoryResponse{
+			CategoryID:          cu.CategoryID,
+			Title:               cu.Title,
+			unreadCountResponse: unreadCountResponse{Topics: cu.Topics, Messages: cu.Messages},
+		})
+	}
+	for _, ut := range s.TopicList {
+		resp.TopicList = append(resp.TopicList, unreadTopicResponse{
+			TopicID:           ut.TopicID,
+			CategoryID:        ut.CategoryID,
+			Title:             ut.Title,
+			LastReadMessageID: ut.LastReadMessageID,
+			Unread:            ut.Unread,
+			LastMessageAt:     ut.LastMessageAt,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// withTopicRead добавляет к ответу позицию чтения, если она известна
+func withTopicRead(resp *topicResponse, rd *entity.TopicRead) {
+	if rd == nil {
+		return
+	}
+	resp.LastReadMessageID = &rd.LastReadMessageID
+	resp.Unread = &rd.Unread
+}
+
+func toUnreadCountResponse(cu *entity.CategoryUnread) *unreadCountResponse {
+	if cu == nil {
+		return nil
+	}
+	return &unreadCountResponse{Topics: cu.Topics, Messages: cu.Messages}
+}
+
+func readError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrUnauthenticated):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+	case errors.Is(err, usecase.ErrTopicNotFound),
+		errors.Is(err, usecase.ErrMessageNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
@@ -0,0 +1,226 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"chat-service/internal/entity"
+	"chat-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	uc usecase.ReportUsecase
+}
+
+func NewReportHandler(uc usecase.ReportUsecase) *ReportHandler {
+	return &ReportHandler{uc: uc}
+}
+
+// ReportMessage — POST /messages/{id}/report
+// @Summary      Report message
+// @Description  One report per user and message. After several open reports the message is hidden until a moderator decides.
+// @Tags         Report
+// @Accept       json
+// @Param        id    path      int                  true  "Message ID"
+// @Param        body  body      createReportRequest  true  "Reason category and optional comment"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /messages/{id}/report [post]
+func (h *ReportHandler) ReportMessage(c *gin.Context) {
+	h.report(c, entity.TargetMessage)
+}
+
+// ReportTopic — POST /topics/{id}/report
+// @Summary      Report topic
+// @Description  One report per user and topic. After several open reports the topic is hidden until a moderator decides.
+// @Tags         Report
+// @Accept       json
+// @Param        id    path      int                  true  "Topic ID"
+// @Param        body  body      createReportRequest  true  "Reason category and optional comment"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /topics/{id}/report [post]
+func (h *ReportHandler) ReportTopic(c *gin.Context) {
+	h.report(c, entity.TargetTopic)
+}
+
+func (h *ReportHandler) report(c *gin.Context, targetType string) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid id"})
+		return
+	}
+
+	var req createReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	err = h.uc.CreateReport(c.Request.Context(), usecase.ReportParams{
+		TargetType: targetType,
+		TargetID:   id,
+		Reason:     entity.ReportReason(req.Reason),
+		Comment:    req.Comment,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnauthenticated):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+		case errors.Is(err, usecase.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden"})
+		case errors.Is(err, usecase.ErrInvalidReport):
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		case errors.Is(err, usecase.ErrMessageNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: "message not found"})
+		case errors.Is(err, usecase.ErrTopicNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: "topic not found"})
+		case errors.Is(err, usecase.ErrAlreadyReported):
+			c.AbortWithStatusJSON(http.StatusConflict, ErrorResponse{Code: "ALREADY_REPORTED", Message: err.Error()})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListReports — GET /admin/reports
+// @Summary      Report queue (admin or category moderator)
+// @Description  Oldest first. Moderators see only reports from the categories they moderate.
+// @Tags         Report
+// @Produce      json
+// @Param        state        query     string  false  "open (default), dismissed or actioned"
+// @Param        target_type  query     string  false  "message or topic"
+// @Param        limit        query     int     false  "Page size (default 50, max 200)"
+// @Param        offset       query     int     false  "Offset"
+// @Success      200  {object}  reportListResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/reports [get]
+func (h *ReportHandler) ListReports(c *gin.Context) {
+	var q reportQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	list, total, err := h.uc.ListReports(c.Request.Context(), entity.ReportFilter{
+		State:      q.State,
+		TargetType: q.TargetType,
+		Limit:      q.Limit,
+		Offset:     q.Offset,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnauthenticated):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+		case errors.Is(err, usecase.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden"})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+		return
+	}
+
+	resp := reportListResponse{
+		Items:  make([]reportResponse, 0, len(list)),
+		Total:  total,
+		Limit:  q.Limit,
+		Offset: q.Offset,
+	}
+	for _, r := range list {
+		resp.Items = append(resp.Items, reportResponse{
+			ID:             r.ID,
+			TargetType:     r.TargetType,
+			TargetID:       r.TargetID,
+			TopicID:        r.TopicID,
+			CategoryID:     r.CategoryID,
+			TargetAuthorID: r.TargetAuthorID,
+			Excerpt:        r.Excerpt,
+			TargetHidden:   r.TargetHidden,
+			OpenReports:    r.OpenReports,
+			ReporterID:     r.ReporterID,
+			Reason:         string(r.Reason),
+			Comment:        r.Comment,
+			State:          r.State,
+			ResolvedBy:     r.ResolvedBy,
+			ResolvedAt:     r.ResolvedAt,
+			Resolution:     r.Resolution,
+			CreatedAt:      r.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ResolveReport — POST /admin/reports/{id}/resolve
+// @Summary      Resolve report (admin or category moderator)
+// @Description  Closes every open report on the same item. dismiss unhides the item, delete soft-deletes it,
+// @Description  block blocks the author in auth-service and is admin-only: a category moderator gets 403
+// @Description  before anything is changed.
+// @Tags         Report
+// @Accept       json
+// @Param        id    path      int                   true  "Report ID"
+// @Param        body  body      resolveReportRequest  true  "Action and optional note"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/reports/{id}/resolve [post]
+func (h *ReportHandler) ResolveReport(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid id"})
+		return
+	}
+
+	var req resolveReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	err = h.uc.ResolveReport(c.Request.Context(), id, usecase.ResolveReportParams{Action: req.Action, Note: req.Note})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnauthenticated):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+		case errors.Is(err, usecase.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden"})
+		case errors.Is(err, usecase.ErrInvalidResolution):
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		case errors.Is(err, usecase.ErrReportNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: "report not found"})
+		case errors.Is(err, usecase.ErrReportUserNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+		case errors.Is(err, usecase.ErrReportResolved):
+			c.AbortWithStatusJSON(http.StatusConflict, ErrorResponse{Code: "REPORT_RESOLVED", Message: err.Error()})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,425 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"chat-service/internal/entity"
+	"chat-service/internal/ratelimit"
+	"chat-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type RoomHandler struct {
+	uc usecase.RoomUsecase
+}
+
+func NewRoomHandler(uc usecase.RoomUsecase) *RoomHandler {
+	return &RoomHandler{uc: uc}
+}
+
+func toRoomResponse(rm *entity.Room) roomResponse {
+	resp := roomResponse{
+		ID:          rm.ID,
+		Slug:        rm.Slug,
+		Title:       rm.Title,
+		Description: rm.Description,
+		Position:    rm.Position,
+		Retention:   toRetentionResponse(rm.Retention),
+		Online:      rm.Online,
+		CreatedAt:   rm.CreatedAt,
+	}
+	if rm.RateBurst > 0 {
+		resp.RateLimit = &roomRateLimitResponse{Burst: rm.RateBurst, PeriodSeconds: int(rm.RatePeriod / time.Second)}
+	}
+	return resp
+}
+
+func toRoomMessageResponse(m *entity.RoomMessage) roomMessageResponse {
+	return roomMessageResponse{
+		ID:          m.ID,
+		RoomID:      m.RoomID,
+		AuthorID:    m.AuthorID,
+		AuthorName:  m.AuthorName,
+		Content:     m.Content,
+		ContentHTML: m.ContentHTML,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+func toRoomParams(req roomRequest) usecase.RoomParams {
+	p := usecase.RoomParams{
+		Slug:        req.Slug,
+		Title:       req.Title,
+		Description: req.Description,
+		Position:    req.Position,
+	}
+	if req.Retention != nil {
+		p.Retention = entity.RetentionPolicy{Mode: entity.RetentionMode(req.Retention.Mode), Value: req.Retention.Value}
+	}
+	if req.RateLimit != nil {
+		p.RateLimit = ratelimit.Limit{Burst: req.RateLimit.Burst, Period: time.Duration(req.RateLimit.PeriodSeconds) * time.Second}
+	}
+	return p
+}
+
+// ListRooms — GET /rooms
+// @Summary      List chat rooms
+// @Description  Always-on chat rooms outside the category tree, such as the general chat (slug "general"), with the number of clients connected right now.
+// @Tags         Rooms
+// @Produce      json
+// @Success      200  {array}   roomResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /rooms [get]
+func (h *RoomHandler) ListRooms(c *gin.Context) {
+	list, err := h.uc.ListRooms(c.Request.Context())
+	if err != nil {
+		roomError(c, err)
+		return
+	}
+
+	resp := make([]roomResponse, 0, len(list))
+	for _, rm := range list {
+		resp = append(resp, toRoomResponse(rm))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetRoom — GET /rooms/{id}
+// @Summary      Get chat room
+// @Tags         Rooms
+// @Produce      json
+// @Param        id   path      string  true  "Room ID or slug"
+// @Success      200  {object}  roomResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /rooms/{id} [get]
+func (h *RoomHandler) GetRoom(c *gin.Context) {
+	rm, err := h.room(c)
+	if err != nil {
+		roomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toRoomResponse(rm))
+}
+
+// GetMessages — GET /rooms/{id}/messages
+// @Summary      Room messages
+// @Description  Newest first. Pass the smallest ID seen as before_id to load older messages. Readable without logging in.
+// @Tags         Rooms
+// @Produce      json
+// @Param        id         path      string  true   "Room ID or slug"
+// @Param        before_id  query     int     false  "Only messages older than this one"
+// @Param        limit      query     int     false  "Page size (default 50, max 200)"
+// @Success      200        {array}   roomMessageResponse
+// @Failure      400        {object}  ErrorResponse
+// @Failure      404        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Router       /rooms/{id}/messages [get]
+func (h *RoomHandler) GetMessages(c *gin.Context) {
+	id, ok := h.roomID(c)
+	if !ok {
+		return
+	}
+	var q olderMessagesQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	list, err := h.uc.GetMessages(c.Request.Context(), id, q.BeforeID, q.Limit)
+	if err != nil {
+		roomError(c, err)
+		return
+	}
+
+	resp := make([]roomMessageResponse, 0, len(list))
+	for _, m := range list {
+		resp = append(resp, toRoomMessageResponse(m))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// SendMessage — POST /rooms/{id}/messages
+// @Summary      Send message to room
+// @Description  Delivered live to /ws/rooms/{id} as "created". Besides the general message limit, the room's own rate_limit applies (moderators and admins are exempt).
+// @Tags         Rooms
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string              true  "Room ID or slug"
+// @Param        request  body      roomMessageRequest  true  "Message"
+// @Success      201      {object}  roomMessageResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      422      {object}  ErrorResponse
+// @Failure      429      {object}  ErrorResponse  "Too many requests, see Retry-After"
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /rooms/{id}/messages [post]
+func (h *RoomHandler) SendMessage(c *gin.Context) {
+	id, ok := h.roomID(c)
+	if !ok {
+		return
+	}
+	var req roomMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	m, err := h.uc.SendMessage(c.Request.Context(), id, req.Content)
+	if err != nil {
+		roomError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, toRoomMessageResponse(m))
+}
+
+// UpdateMessage — PUT /rooms/{id}/messages/{messageId}
+// @Summary      Edit my room message
+// @Tags         Rooms
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string              true  "Room ID or slug"
+// @Param        messageId  path      int                 true  "Message ID"
+// @Param        request    body      roomMessageRequest  true  "New text"
+// @Success      200        {object}  roomMessageResponse
+// @Failure      400        {object}  ErrorResponse
+// @Failure      401        {object}  ErrorResponse
+// @Failure      403        {object}  ErrorResponse
+// @Failure      404        {object}  ErrorResponse
+// @Failure      422        {object}  ErrorResponse
+// @Failure      429        {object}  ErrorResponse  "Too many requests, see Retry-After"
+// @Failure      500        {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /rooms/{id}/messages/{messageId} [put]
+func (h *RoomHandler) UpdateMessage(c *gin.Context) {
+	id, ok := h.roomID(c)
+	if !ok {
+		return
+	}
+	messageID, err := strconv.ParseInt(c.Param("messageId"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid message id"})
+		return
+	}
+	var req roomMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	m, err := h.uc.UpdateMessage(c.Request.Context(), id, messageID, req.Content)
+	if err != nil {
+		roomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toRoomMessageResponse(m))
+}
+
+// DeleteMessage — DELETE /rooms/{id}/messages/{messageId}
+// @Summary      Delete room message
+// @Description  Authors delete their own messages; moderators and admins delete anyone's.
+// @Tags         Rooms
+// @Param        id         path  string  true  "Room ID or slug"
+// @Param        messageId  path  int     true  "Message ID"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /rooms/{id}/messages/{messageId} [delete]
+func (h *RoomHandler) DeleteMessage(c *gin.Context) {
+	id, ok := h.roomID(c)
+	if !ok {
+		return
+	}
+	messageID, err := strconv.ParseInt(c.Param("messageId"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid message id"})
+		return
+	}
+
+	if err := h.uc.DeleteMessage(c.Request.Context(), id, messageID); err != nil {
+		roomError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Presence — GET /rooms/{id}/presence
+// @Summary      Who is in the room
+// @Description  Logged-in users with the room open (each once, however many tabs) and the number of anonymous connections. Changes are pushed to /ws/rooms/{id} as "presence" events.
+// @Tags         Rooms
+// @Produce      json
+// @Param        id   path      string  true  "Room ID or slug"
+// @Success      200  {object}  roomPresenceResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /rooms/{id}/presence [get]
+func (h *RoomHandler) Presence(c *gin.Context) {
+	id, ok := h.roomID(c)
+	if !ok {
+		return
+	}
+
+	p, err := h.uc.Presence(c.Request.Context(), id)
+	if err != nil {
+		roomError(c, err)
+		return
+	}
+
+	resp := roomPresenceResponse{RoomID: p.RoomID, Users: make([]roomViewerResponse, 0, len(p.Users)), Anonymous: p.Anonymous}
+	for _, u := range p.Users {
+		resp.Users = append(resp.Users, roomViewerResponse{UserID: u.UserID, Name: u.Name})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreateRoom — POST /admin/rooms
+// @Summary      Create chat room (admin only)
+// @Tags         Rooms
+// @Accept       json
+// @Produce      json
+// @Param        request  body      roomRequest  true  "Room settings"
+// @Success      201      {object}  roomResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse  "Slug is taken"
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/rooms [post]
+func (h *RoomHandler) CreateRoom(c *gin.Context) {
+	var req roomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	rm, err := h.uc.CreateRoom(c.Request.Context(), toRoomParams(req))
+	if err != nil {
+		roomError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, toRoomResponse(rm))
+}
+
+// UpdateRoom — PUT /admin/rooms/{id}
+// @Summary      Update chat room (admin only)
+// @Description  Replaces all settings: omitted retention or rate_limit are reset to the global defaults.
+// @Tags         Rooms
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int          true  "Room ID"
+// @Param        request  body      roomRequest  true  "Room settings"
+// @Success      200      {object}  roomResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse  "Slug is taken"
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/rooms/{id} [put]
+func (h *RoomHandler) UpdateRoom(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid room id"})
+		return
+	}
+	var req roomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	rm, err := h.uc.UpdateRoom(c.Request.Context(), id, toRoomParams(req))
+	if err != nil {
+		roomError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toRoomResponse(rm))
+}
+
+// DeleteRoom — DELETE /admin/rooms/{id}
+// @Summary      Delete chat room (admin only)
+// @Description  The room is deleted together with all its messages.
+// @Tags         Rooms
+// @Param        id   path  int  true  "Room ID"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/rooms/{id} [delete]
+func (h *RoomHandler) DeleteRoom(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid room id"})
+		return
+	}
+
+	if err := h.uc.DeleteRoom(c.Request.Context(), id); err != nil {
+		roomError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// room находит комнату по id или slug из пути
+func (h *RoomHandler) room(c *gin.Context) (*entity.Room, error) {
+	ref := c.Param("id")
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		return h.uc.GetRoom(c.Request.Context(), id)
+	}
+	return h.uc.GetRoomBySlug(c.Request.Context(), ref)
+}
+
+// roomID возвращает id комнаты из пути; slug переводится в id отдельным запросом
+func (h *RoomHandler) roomID(c *gin.Context) (int64, bool) {
+	if id, err := strconv.ParseInt(c.Param("id"), 10, 64); err == nil {
+		return id, true
+	}
+	rm, err := h.uc.GetRoomBySlug(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		roomError(c, err)
+		return 0, false
+	}
+	return rm.ID, true
+}
+
+func roomError(c *gin.Context, err error) {
+	if abortIfRejected(c, err) || abortIfUnprivileged(c, err) || abortIfRateLimited(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, usecase.ErrUnauthenticated):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+	case errors.Is(err, usecase.ErrForbidden):
+		c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden: insufficient privileges"})
+	case errors.Is(err, usecase.ErrBannedFromRooms):
+		c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Code: "banned", Message: err.Error()})
+	case errors.Is(err, usecase.ErrRoomNotFound),
+		errors.Is(err, usecase.ErrMessageNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	case errors.Is(err, usecase.ErrInvalidRoom),
+		errors.Is(err, usecase.ErrInvalidRoomLimit),
+		errors.Is(err, usecase.ErrInvalidRetention),
+		errors.Is(err, usecase.ErrEmptyMessage):
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	case errors.Is(err, usecase.ErrRoomSlugTaken):
+		c.AbortWithStatusJSON(http.StatusConflict, ErrorResponse{Message: err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
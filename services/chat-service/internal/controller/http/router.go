@@ -26,6 +26,20 @@ func NewRouter(
 	catUC usecase.CategoryUsecase,
 	topicUC usecase.TopicUsecase,
 	msgUC usecase.MessageUsecase,
+	modUC usecase.ModerationUsecase,
+	reportUC usecase.ReportUsecase,
+	muteUC usecase.MuteUsecase,
+	filterUC usecase.FilterUsecase,
+	rateUC usecase.RateLimitUsecase,
+	mentionUC usecase.MentionUsecase,
+	notifUC usecase.NotificationUsecase,
+	subUC usecase.SubscriptionUsecase,
+	readUC usecase.ReadUsecase,
+	convUC usecase.ConversationUsecase,
+	roomUC usecase.RoomUsecase,
+	attUC usecase.AttachmentUsecase,
+	profileUC usecase.ProfileUsecase,
+	voteUC usecase.VoteUsecase,
 	hub *wsCtrl.Hub,
 	authClient authpb.AuthServiceClient,
 	cfg *config.Config,
@@ -46,10 +60,23 @@ func NewRouter(
 	})
 
 	// инициализируем обработчики
-	catH := NewCategoryHandler(catUC)
-	topicH := NewTopicHandler(topicUC)
-	msgH := NewMessageHandler(msgUC)
-	wsH := NewWSHandler(hub)
+	catH := NewCategoryHandler(catUC, readUC)
+	topicH := NewTopicHandler(topicUC, readUC)
+	msgH := NewMessageHandler(msgUC, voteUC, cfg.Cleanup.HoursAgo)
+	voteH := NewVoteHandler(voteUC)
+	modH := NewModerationHandler(modUC)
+	reportH := NewReportHandler(reportUC)
+	muteH := NewMuteHandler(muteUC)
+	filterH := NewFilterHandler(filterUC)
+	mentionH := NewMentionHandler(mentionUC)
+	notifH := NewNotificationHandler(notifUC)
+	subH := NewSubscriptionHandler(subUC)
+	readH := NewReadHandler(readUC)
+	convH := NewConversationHandler(convUC)
+	roomH := NewRoomHandler(roomUC)
+	attH := NewAttachmentHandler(attUC, cfg.Attachments.MaxSize)
+	profileH := NewProfileHandler(profileUC, cfg.Profiles.AvatarMaxSize)
+	wsH := NewWSHandler(hub, readUC, convUC, roomUC, log)
 
 	// CORS как в auth-сервисе
 	corsConfig := cors.Config{
@@ -66,24 +93,46 @@ func NewRouter(
 		},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Authorization", "Content-Type"},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}
 	r.Use(cors.New(corsConfig))
 
 	// PUBLIC
-	r.GET("/categories", catH.ListCategories)
+	// списки доступны всем; вошедшему пользователю к ним добавляется непрочитанное
+	optionalAuth := OptionalAuthMiddleware(authClient, log)
+	r.GET("/categories", optionalAuth, catH.ListCategories)
 	r.GET("/categories/:id", catH.GetCategory)
-	r.GET("/categories/:id/topics", topicH.ListTopics)
-	r.GET("/topics/:id", topicH.GetTopic)
-	r.GET("/topics/:id/messages", msgH.GetMessages)
-	// подписка по WebSocket (можно без авторизации чтения, но мы всё же проверяем токен)
-	r.GET("/ws/topics/:id", wsH.ServeWS)
+	r.GET("/categories/:id/topics", optionalAuth, topicH.ListTopics)
+	r.GET("/topics/:id", optionalAuth, topicH.GetTopic)
+	r.GET("/topics/:id/messages", optionalAuth, msgH.GetMessages)
+	// подписка по WebSocket: читать можно без входа, с токеном клиент ещё и отмечает прочитанное
+	r.GET("/ws/topics/:id", QueryTokenMiddleware(), optionalAuth, wsH.ServeWS)
+	// личный канал пользователя: уведомления и другие адресные события
+	r.GET("/ws/me", QueryTokenMiddleware(), AuthMiddleware(authClient, log), wsH.ServeUserWS)
+	// канал личной переписки — только для её участников
+	r.GET("/ws/conversations/:id", QueryTokenMiddleware(), AuthMiddleware(authClient, log), wsH.ServeConversationWS)
+	// комнаты — общие чаты вне категорий; читать можно без входа, присутствие видно всем
+	r.GET("/rooms", roomH.ListRooms)
+	r.GET("/rooms/:id", roomH.GetRoom)
+	r.GET("/rooms/:id/messages", roomH.GetMessages)
+	r.GET("/rooms/:id/presence", roomH.Presence)
+	r.GET("/ws/rooms/:id", QueryTokenMiddleware(), optionalAuth, wsH.ServeRoomWS)
+	// вложения: токен можно передать в access_token — для <img>; неотправленные видны только загрузившему
+	r.GET("/attachments/:id", QueryTokenMiddleware(), optionalAuth, attH.GetAttachment)
+	r.GET("/attachments/:id/thumbnail", QueryTokenMiddleware(), optionalAuth, attH.GetThumbnail)
+	// публичные профили: без email
+	r.GET("/users/:id/profile", profileH.GetProfile)
+	r.GET("/users/:id/avatar", profileH.GetAvatar)
+	r.GET("/profiles/:username", profileH.GetProfileByUsername)
+	// отписка от дайджеста по ссылке из письма — без входа, по токену
+	r.GET("/digest/unsubscribe", subH.UnsubscribePage)
+	r.POST("/digest/unsubscribe", subH.Unsubscribe)
 
 	// PROTECTED
 	secured := r.Group("/")
-	secured.Use(AuthMiddleware(authClient))
+	secured.Use(AuthMiddleware(authClient, log))
 	{
 		// Category
 		secured.POST("/categories", catH.CreateCategory)
@@ -91,14 +140,110 @@ func NewRouter(
 		secured.DELETE("/categories/:id", catH.DeleteCategory)
 
 		// Topic
-		secured.POST("/topics", topicH.CreateTopic)
-		secured.PUT("/topics/:id", topicH.UpdateTopic)
+		secured.POST("/topics", RateLimitMiddleware(rateUC, usecase.ActionTopic), topicH.CreateTopic)
+		secured.PUT("/topics/:id", RateLimitMiddleware(rateUC, usecase.ActionEdit), topicH.UpdateTopic)
 		secured.DELETE("/topics/:id", topicH.DeleteTopic)
 
 		// Message
-		secured.POST("/topics/:id/messages", msgH.SendMessage)
-		secured.PUT("/messages/:id", msgH.UpdateMessage)
+		secured.POST("/topics/:id/messages", RateLimitMiddleware(rateUC, usecase.ActionMessage), msgH.SendMessage)
+		secured.PUT("/messages/:id", RateLimitMiddleware(rateUC, usecase.ActionEdit), msgH.UpdateMessage)
 		secured.DELETE("/messages/:id", msgH.DeleteMessage)
+		secured.PUT("/messages/:id/vote", voteH.Vote)
+		secured.DELETE("/messages/:id/vote", voteH.Unvote)
+		secured.PUT("/messages/:id/accepted", msgH.AcceptAnswer)
+		secured.DELETE("/messages/:id/accepted", msgH.UnacceptAnswer)
+
+		// Mentions and notifications
+		secured.GET("/me/mentions", mentionH.ListMyMentions)
+		secured.GET("/notifications", notifH.ListNotifications)
+		secured.POST("/notifications/read-all", notifH.MarkAllNotificationsRead)
+		secured.POST("/notifications/:id/read", notifH.MarkNotificationRead)
+
+		// Subscriptions and digests
+		secured.POST("/topics/:id/follow", subH.FollowTopic)
+		secured.DELETE("/topics/:id/follow", subH.UnfollowTopic)
+		secured.POST("/categories/:id/follow", subH.FollowCategory)
+		secured.DELETE("/categories/:id/follow", subH.UnfollowCategory)
+		secured.GET("/me/follows", subH.ListFollows)
+		secured.GET("/me/digest", subH.GetDigestSettings)
+		secured.PUT("/me/digest", subH.UpdateDigestSettings)
+
+		// Read positions
+		secured.POST("/topics/:id/read", readH.MarkTopicRead)
+		secured.GET("/unread", readH.Unread)
+
+		// Private conversations
+		secured.POST("/conversations", RateLimitMiddleware(rateUC, usecase.ActionTopic), convH.StartConversation)
+		secured.GET("/conversations", convH.ListConversations)
+		secured.GET("/conversations/:id", convH.GetConversation)
+		secured.GET("/conversations/:id/messages", convH.GetMessages)
+		secured.POST("/conversations/:id/messages", RateLimitMiddleware(rateUC, usecase.ActionMessage), convH.SendMessage)
+		secured.DELETE("/conversations/:id/messages/:messageId", convH.DeleteMessage)
+		secured.POST("/conversations/:id/read", convH.MarkRead)
+		secured.POST("/conversations/:id/members", convH.AddMember)
+		secured.DELETE("/conversations/:id/members/me", convH.LeaveConversation)
+		secured.POST("/users/:id/block", convH.BlockUser)
+		secured.DELETE("/users/:id/block", convH.UnblockUser)
+		secured.GET("/me/blocks", convH.ListBlockedUsers)
+
+		// Rooms
+		secured.POST("/rooms/:id/messages", RateLimitMiddleware(rateUC, usecase.ActionMessage), roomH.SendMessage)
+		secured.PUT("/rooms/:id/messages/:messageId", RateLimitMiddleware(rateUC, usecase.ActionEdit), roomH.UpdateMessage)
+		secured.DELETE("/rooms/:id/messages/:messageId", roomH.DeleteMessage)
+
+		// Profile
+		secured.PUT("/me/profile", profileH.UpdateProfile)
+		secured.PUT("/me/avatar", profileH.SetAvatar)
+		secured.DELETE("/me/avatar", profileH.DeleteAvatar)
+
+		// Attachments
+		secured.POST("/attachments", attH.UploadAttachment)
+		secured.DELETE("/attachments/:id", attH.DeleteAttachment)
+
+		// Reports
+		secured.POST("/messages/:id/report", reportH.ReportMessage)
+		secured.POST("/topics/:id/report", reportH.ReportTopic)
+		secured.GET("/admin/reports", reportH.ListReports)
+		secured.POST("/admin/reports/:id/resolve", reportH.ResolveReport)
+
+		// Moderation (admin)
+		secured.GET("/admin/messages/deleted", msgH.ListDeletedMessages)
+		secured.POST("/admin/messages/:id/restore", msgH.RestoreMessage)
+		secured.GET("/admin/topics/deleted", topicH.ListDeletedTopics)
+		secured.POST("/admin/topics/:id/restore", topicH.RestoreTopic)
+		secured.PUT("/admin/topics/:id/pinned", topicH.SetTopicPinned)
+		secured.PUT("/admin/topics/:id/locked", topicH.SetTopicLocked)
+		secured.POST("/admin/topics/:id/move", topicH.MoveTopic)
+		secured.POST("/admin/topics/:id/merge", topicH.MergeTopic)
+		secured.GET("/admin/moderation-log", modH.ListModerationLog)
+		secured.GET("/me/moderation-log", modH.ListMyModerationLog)
+		secured.POST("/admin/mutes", muteH.CreateMute)
+		secured.GET("/admin/mutes", muteH.ListMutes)
+		secured.DELETE("/admin/mutes/:id", muteH.DeleteMute)
+		secured.GET("/me/mutes", muteH.ListMyMutes)
+
+		// Content filters (admin)
+		secured.GET("/admin/filters/rules", filterH.ListFilterRules)
+		secured.POST("/admin/filters/rules", filterH.CreateFilterRule)
+		secured.PUT("/admin/filters/rules/:id", filterH.UpdateFilterRule)
+		secured.DELETE("/admin/filters/rules/:id", filterH.DeleteFilterRule)
+		secured.GET("/admin/filters/settings", filterH.GetFilterSettings)
+		secured.PUT("/admin/filters/settings", filterH.UpdateFilterSettings)
+		secured.POST("/admin/filters/test", filterH.TestFilter)
+
+		// Category tree (admin)
+		secured.PUT("/admin/categories/reorder", catH.ReorderCategories)
+
+		// Rooms (admin)
+		secured.POST("/admin/rooms", roomH.CreateRoom)
+		secured.PUT("/admin/rooms/:id", roomH.UpdateRoom)
+		secured.DELETE("/admin/rooms/:id", roomH.DeleteRoom)
+
+		// Retention (admin)
+		secured.PUT("/admin/categories/:id/retention", catH.SetCategoryRetention)
+		secured.PUT("/admin/categories/:id/qa", catH.SetCategoryQA)
+		secured.PUT("/admin/topics/:id/retention", topicH.SetTopicRetention)
+		secured.GET("/admin/retention/preview", msgH.PreviewCleanup)
 	}
 
 	return r
@@ -0,0 +1,251 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"chat-service/internal/entity"
+	"chat-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type SubscriptionHandler struct {
+	uc usecase.SubscriptionUsecase
+}
+
+func NewSubscriptionHandler(uc usecase.SubscriptionUsecase) *SubscriptionHandler {
+	return &SubscriptionHandler{uc: uc}
+}
+
+// страницы отписки открываются из письма в браузере, поэтому это HTML, а не JSON
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head><meta charset="utf-8"><title>Отписка от дайджеста</title></head>
+<body style="font-family: sans-serif">
+{{if .Done}}<p>Готово: дайджест больше не будет приходить. Включить его снова можно в настройках профиля.</p>
+{{else if .Invalid}}<p>Ссылка для отписки недействительна.</p>
+{{else}}<form method="post" action="?token={{.Token}}">
+<p>Больше не присылать email-дайджест с новостями форума?</p>
+<button type="submit">Отписаться</button>
+</form>{{end}}
+</body>
+</html>
+`))
+
+func renderUnsubscribePage(c *gin.Context, status int, token string, done, invalid bool) {
+	var buf bytes.Buffer
+	_ = unsubscribePage.Execute(&buf, struct {
+		Token         string
+		Done, Invalid bool
+	}{token, done, invalid})
+	c.Data(status, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// FollowTopic — POST /topics/{id}/follow
+// @Summary      Follow topic
+// @Description  New messages in the topic will appear in my email digest. Following a merged topic follows the topic it was merged into. Following twice is not an error.
+// @Tags         Subscriptions
+// @Param        id   path  int  true  "Topic ID"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /topics/{id}/follow [post]
+func (h *SubscriptionHandler) FollowTopic(c *gin.Context) {
+	h.follow(c, h.uc.FollowTopic)
+}
+
+// UnfollowTopic — DELETE /topics/{id}/follow
+// @Summary      Unfollow topic
+// @Tags         Subscriptions
+// @Param        id   path  int  true  "Topic ID"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /topics/{id}/follow [delete]
+func (h *SubscriptionHandler) UnfollowTopic(c *gin.Context) {
+	h.follow(c, h.uc.UnfollowTopic)
+}
+
+// FollowCategory — POST /categories/{id}/follow
+// @Summary      Follow category
+// @Description  New topics and messages in the category and all its subcategories will appear in my email digest.
+// @Tags         Subscriptions
+// @Param        id   path  int  true  "Category ID"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /categories/{id}/follow [post]
+func (h *SubscriptionHandler) FollowCategory(c *gin.Context) {
+	h.follow(c, h.uc.FollowCategory)
+}
+
+// UnfollowCategory — DELETE /categories/{id}/follow
+// @Summary      Unfollow category
+// @Tags         Subscriptions
+// @Param        id   path  int  true  "Category ID"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /categories/{id}/follow [delete]
+func (h *SubscriptionHandler) UnfollowCategory(c *gin.Context) {
+	h.follow(c, h.uc.UnfollowCategory)
+}
+
+func (h *SubscriptionHandler) follow(c *gin.Context, action func(ctx context.Context, id int64) error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid id"})
+		return
+	}
+	if err := action(c.Request.Context(), id); err != nil {
+		subscriptionError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListFollows — GET /me/follows
+// @Summary      What I follow
+// @Tags         Subscriptions
+// @Produce      json
+// @Success      200  {array}   subscriptionResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /me/follows [get]
+func (h *SubscriptionHandler) ListFollows(c *gin.Context) {
+	list, err := h.uc.ListFollows(c.Request.Context())
+	if err != nil {
+		subscriptionError(c, err)
+		return
+	}
+
+	resp := make([]subscriptionResponse, 0, len(list))
+	for _, s := range list {
+		resp = append(resp, subscriptionResponse{
+			ID:         s.ID,
+			TopicID:    s.TopicID,
+			CategoryID: s.CategoryID,
+			Title:      s.Title,
+			CreatedAt:  s.CreatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func toDigestSettingsResponse(s *entity.DigestSettings) digestSettingsResponse {
+	return digestSettingsResponse{Frequency: s.Frequency, LastSentAt: s.LastSentAt}
+}
+
+// GetDigestSettings — GET /me/digest
+// @Summary      My digest settings
+// @Description  How often the email digest of followed topics and categories is sent. Daily until changed.
+// @Tags         Subscriptions
+// @Produce      json
+// @Success      200  {object}  digestSettingsResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /me/digest [get]
+func (h *SubscriptionHandler) GetDigestSettings(c *gin.Context) {
+	s, err := h.uc.GetDigestSettings(c.Request.Context())
+	if err != nil {
+		subscriptionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toDigestSettingsResponse(s))
+}
+
+// UpdateDigestSettings — PUT /me/digest
+// @Summary      Change digest frequency
+// @Tags         Subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        request  body      digestSettingsRequest  true  "off, daily or weekly"
+// @Success      200      {object}  digestSettingsResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /me/digest [put]
+func (h *SubscriptionHandler) UpdateDigestSettings(c *gin.Context) {
+	var req digestSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	s, err := h.uc.UpdateDigestSettings(c.Request.Context(), req.Frequency)
+	if err != nil {
+		subscriptionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toDigestSettingsResponse(s))
+}
+
+// UnsubscribePage — GET /digest/unsubscribe
+// @Summary      Unsubscribe page
+// @Description  Opened from the link in a digest email. Shows a one-button confirmation form: mail scanners follow links with GET, so GET alone does not unsubscribe.
+// @Tags         Subscriptions
+// @Produce      html
+// @Param        token  query  string  true  "Token from the email"
+// @Success      200
+// @Router       /digest/unsubscribe [get]
+func (h *SubscriptionHandler) UnsubscribePage(c *gin.Context) {
+	token := c.Query("token")
+	renderUnsubscribePage(c, http.StatusOK, token, false, token == "")
+}
+
+// Unsubscribe — POST /digest/unsubscribe
+// @Summary      Unsubscribe from digest
+// @Description  Turns the digest off without logging in. Sent by the confirmation page and by mail clients supporting one-click unsubscribe (RFC 8058, List-Unsubscribe-Post header).
+// @Tags         Subscriptions
+// @Produce      html
+// @Param        token  query  string  true  "Token from the email"
+// @Success      200
+// @Failure      404
+// @Failure      500  {object}  ErrorResponse
+// @Router       /digest/unsubscribe [post]
+func (h *SubscriptionHandler) Unsubscribe(c *gin.Context) {
+	token := c.Query("token")
+	err := h.uc.Unsubscribe(c.Request.Context(), token)
+	switch {
+	case errors.Is(err, usecase.ErrInvalidUnsubscribe):
+		renderUnsubscribePage(c, http.StatusNotFound, token, false, true)
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	default:
+		renderUnsubscribePage(c, http.StatusOK, token, true, false)
+	}
+}
+
+func subscriptionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrUnauthenticated):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+	case errors.Is(err, usecase.ErrTopicNotFound),
+		errors.Is(err, usecase.ErrCategoryNotFound),
+		errors.Is(err, usecase.ErrNotFollowing):
+		c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	case errors.Is(err, usecase.ErrInvalidDigestFrequency):
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
@@ -2,31 +2,35 @@ package http
 
 import (
 	"chat-service/internal/auth"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
+	"chat-service/internal/entity"
 	"chat-service/internal/usecase"
 	"github.com/gin-gonic/gin"
 )
 
 type TopicHandler struct {
-	uc usecase.TopicUsecase
+	uc    usecase.TopicUsecase
+	reads usecase.ReadUsecase
 }
 
-func NewTopicHandler(uc usecase.TopicUsecase) *TopicHandler {
-	return &TopicHandler{uc: uc}
+func NewTopicHandler(uc usecase.TopicUsecase, reads usecase.ReadUsecase) *TopicHandler {
+	return &TopicHandler{uc: uc, reads: reads}
 }
 
 // ListTopics — GET /categories/{id}/topics
 // @Summary      List topics in category
-// @Description  Returns all topics under a given category
+// @Description  Returns all topics under a given category. With a token, topics I have opened carry my read position and unread count. In Q&A categories solved marks topics with an accepted answer; unsolved=true leaves only topics without one.
 // @Tags         Topic
 // @Produce      json
-// @Param        id   path      int  true  "Category ID"
-// @Success      200  {array}   topicResponse
-// @Failure      400  {object}  ErrorResponse
-// @Failure      500  {object}  ErrorResponse
+// @Param        id        path      int   true   "Category ID"
+// @Param        unsolved  query     bool  false  "Only topics without an accepted answer"
+// @Success      200       {array}   topicResponse
+// @Failure      400       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
 // @Router       /categories/{id}/topics [get]
 func (h *TopicHandler) ListTopics(c *gin.Context) {
 	cid, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -35,15 +39,22 @@ func (h *TopicHandler) ListTopics(c *gin.Context) {
 		return
 	}
 
-	list, err := h.uc.ListTopics(c.Request.Context(), cid)
+	var q topicsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	list, err := h.uc.ListTopics(c.Request.Context(), cid, usecase.TopicFilter{Unsolved: q.Unsolved})
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: err.Error()})
 		return
 	}
+	h.reads.AnnotateTopics(c.Request.Context(), list...)
 
 	resp := make([]topicResponse, 0, len(list))
 	for _, t := range list {
-		resp = append(resp, topicResponse{
+		tr := topicResponse{
 			ID:          t.ID,
 			CategoryID:  t.CategoryID,
 			Title:       t.Title,
@@ -51,7 +62,12 @@ func (h *TopicHandler) ListTopics(c *gin.Context) {
 			AuthorID:    t.AuthorID,
 			AuthorName:  t.AuthorName,
 			CreatedAt:   t.CreatedAt,
-		})
+			Pinned:      t.Pinned,
+			Locked:      t.Locked,
+		}
+		withTopicRead(&tr, t.Read)
+		withAnswer(&tr, t)
+		resp = append(resp, tr)
 	}
 
 	c.JSON(http.StatusOK, resp)
@@ -59,7 +75,7 @@ func (h *TopicHandler) ListTopics(c *gin.Context) {
 
 // GetTopic — GET /topics/{id}
 // @Summary      Get topic by ID
-// @Description  Returns a single topic
+// @Description  Returns a single topic. IDs of merged topics resolve to the topic they were merged into; redirected_from is then set. With a token, last_read_message_id marks where I stopped reading last time.
 // @Tags         Topic
 // @Produce      json
 // @Param        id   path      int  true  "Topic ID"
@@ -85,8 +101,9 @@ func (h *TopicHandler) GetTopic(c *gin.Context) {
 		}
 		return
 	}
+	h.reads.AnnotateTopics(c.Request.Context(), t)
 
-	c.JSON(http.StatusOK, topicResponse{
+	resp := topicResponse{
 		ID:          t.ID,
 		CategoryID:  t.CategoryID,
 		Title:       t.Title,
@@ -94,12 +111,22 @@ func (h *TopicHandler) GetTopic(c *gin.Context) {
 		AuthorID:    t.AuthorID,
 		AuthorName:  t.AuthorName,
 		CreatedAt:   t.CreatedAt,
-	})
+		Pinned:      t.Pinned,
+		Locked:      t.Locked,
+		Retention:   toRetentionResponse(t.Retention),
+	}
+	if t.ID != id {
+		resp.RedirectedFrom = id
+	}
+	withTopicRead(&resp, t.Read)
+	withAnswer(&resp, t)
+
+	c.JSON(http.StatusOK, resp)
 }
 
 // CreateTopic — POST /topics
 // @Summary      Create new topic
-// @Description  Adds a topic under a category
+// @Description  Adds a topic under a category. A user muted in the category gets 403 with code "muted" and the mute expiry. Creating topics and putting links in them need enough reputation, otherwise 403 with code "insufficient_reputation".
 // @Tags         Topic
 // @Accept       json
 // @Produce      json
@@ -107,7 +134,9 @@ func (h *TopicHandler) GetTopic(c *gin.Context) {
 // @Success      201      {object}  topicResponse
 // @Failure      400      {object}  ErrorResponse
 // @Failure      401      {object}  ErrorResponse
-// @Failure      403      {object}  ErrorResponse
+// @Failure      403      {object}  mutedResponse
+// @Failure      422      {object}  ErrorResponse
+// @Failure      429      {object}  ErrorResponse  "Too many requests, see Retry-After"
 // @Failure      500      {object}  ErrorResponse
 // @Security     BearerAuth
 // @Router       /topics [post]
@@ -126,6 +155,12 @@ func (h *TopicHandler) CreateTopic(c *gin.Context) {
 		AuthorID:    authorID,
 	})
 	if err != nil {
+		if abortIfMuted(c, err) {
+			return
+		}
+		if abortIfRejected(c, err) || abortIfUnprivileged(c, err) {
+			return
+		}
 		switch err {
 		case usecase.ErrUnauthenticated:
 			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
@@ -151,7 +186,7 @@ func (h *TopicHandler) CreateTopic(c *gin.Context) {
 
 // UpdateTopic — PUT /topics/{id}
 // @Summary      Update topic
-// @Description  Modifies an existing topic
+// @Description  Modifies an existing topic. Adding links needs enough reputation, otherwise 403 with code "insufficient_reputation".
 // @Tags         Topic
 // @Accept       json
 // @Produce      json
@@ -162,6 +197,8 @@ func (h *TopicHandler) CreateTopic(c *gin.Context) {
 // @Failure      401      {object}  ErrorResponse
 // @Failure      403      {object}  ErrorResponse
 // @Failure      404      {object}  ErrorResponse
+// @Failure      422      {object}  ErrorResponse
+// @Failure      429      {object}  ErrorResponse  "Too many requests, see Retry-After"
 // @Failure      500      {object}  ErrorResponse
 // @Security     BearerAuth
 // @Router       /topics/{id} [put]
@@ -183,6 +220,9 @@ func (h *TopicHandler) UpdateTopic(c *gin.Context) {
 		Description: req.Description,
 	})
 	if err != nil {
+		if abortIfRejected(c, err) || abortIfUnprivileged(c, err) {
+			return
+		}
 		switch err {
 		case usecase.ErrUnauthenticated:
 			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
@@ -201,9 +241,10 @@ func (h *TopicHandler) UpdateTopic(c *gin.Context) {
 
 // DeleteTopic — DELETE /topics/{id}
 // @Summary      Delete topic
-// @Description  Removes a topic
+// @Description  Soft-deletes a topic; its messages are hidden until an admin restores it
 // @Tags         Topic
-// @Param        id   path      int  true  "Topic ID"
+// @Param        id      path      int     true   "Topic ID"
+// @Param        reason  query     string  false  "Deletion reason"
 // @Success      204
 // @Failure      400  {object}  ErrorResponse
 // @Failure      401  {object}  ErrorResponse
@@ -219,7 +260,7 @@ func (h *TopicHandler) DeleteTopic(c *gin.Context) {
 		return
 	}
 
-	err = h.uc.DeleteTopic(c.Request.Context(), id)
+	err = h.uc.DeleteTopic(c.Request.Context(), id, c.Query("reason"))
 	if err != nil {
 		switch err {
 		case usecase.ErrUnauthenticated:
@@ -236,3 +277,327 @@ func (h *TopicHandler) DeleteTopic(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// ListDeletedTopics — GET /admin/topics/deleted
+// @Summary      List deleted topics (admin only)
+// @Description  Returns soft-deleted topics that are still inside the restore window
+// @Tags         Moderation
+// @Produce      json
+// @Success      200  {array}   deletedTopicResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/topics/deleted [get]
+func (h *TopicHandler) ListDeletedTopics(c *gin.Context) {
+	list, err := h.uc.ListDeletedTopics(c.Request.Context())
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnauthenticated):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+		case errors.Is(err, usecase.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden"})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+		return
+	}
+
+	resp := make([]deletedTopicResponse, 0, len(list))
+	for _, t := range list {
+		item := deletedTopicResponse{
+			topicResponse: topicResponse{
+				ID:          t.ID,
+				CategoryID:  t.CategoryID,
+				Title:       t.Title,
+				Description: t.Description,
+				AuthorID:    t.AuthorID,
+				AuthorName:  t.AuthorName,
+				CreatedAt:   t.CreatedAt,
+			},
+			DeletedBy:    t.DeletedBy,
+			DeleteReason: t.DeleteReason,
+		}
+		if t.DeletedAt != nil {
+			item.DeletedAt = *t.DeletedAt
+		}
+		resp = append(resp, item)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RestoreTopic — POST /admin/topics/{id}/restore
+// @Summary      Restore deleted topic (admin only)
+// @Description  Restores a soft-deleted topic if it is still inside the restore window
+// @Tags         Moderation
+// @Param        id   path      int  true  "Topic ID"
+// @Success      204
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/topics/{id}/restore [post]
+func (h *TopicHandler) RestoreTopic(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid id"})
+		return
+	}
+
+	if err := h.uc.RestoreTopic(c.Request.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnauthenticated):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+		case errors.Is(err, usecase.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden"})
+		case errors.Is(err, usecase.ErrTopicNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: "topic not found or restore window expired"})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetTopicRetention — PUT /admin/topics/{id}/retention
+// @Summary      Set topic retention policy (admin only)
+// @Description  Overrides the category policy for one topic: default (inherit), forever, days (value = N days) or last_n (value = N newest messages)
+// @Tags         Retention
+// @Accept       json
+// @Param        id       path      int                  true  "Topic ID"
+// @Param        request  body      setRetentionRequest  true  "Retention policy"
+// @Success      204
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/topics/{id}/retention [put]
+func (h *TopicHandler) SetTopicRetention(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid id"})
+		return
+	}
+	var req setRetentionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	err = h.uc.SetTopicRetention(c.Request.Context(), id, entity.RetentionPolicy{
+		Mode:  entity.RetentionMode(req.Mode),
+		Value: req.Value,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnauthenticated):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+		case errors.Is(err, usecase.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden"})
+		case errors.Is(err, usecase.ErrInvalidRetention):
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		case errors.Is(err, usecase.ErrTopicNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: "topic not found"})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetTopicPinned — PUT /admin/topics/{id}/pinned
+// @Summary      Pin or unpin topic (admin or category moderator)
+// @Description  Pinned topics are listed first in their category
+// @Tags         Moderation
+// @Accept       json
+// @Param        id       path      int               true  "Topic ID"
+// @Param        request  body      setPinnedRequest  true  "Pinned flag"
+// @Success      204
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/topics/{id}/pinned [put]
+func (h *TopicHandler) SetTopicPinned(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid id"})
+		return
+	}
+	var req setPinnedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if err := h.uc.PinTopic(c.Request.Context(), id, *req.Pinned); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnauthenticated):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+		case errors.Is(err, usecase.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden"})
+		case errors.Is(err, usecase.ErrTopicNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: "topic not found"})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetTopicLocked — PUT /admin/topics/{id}/locked
+// @Summary      Lock or unlock topic (admin or category moderator)
+// @Description  Locked topics reject new messages; subscribers get a topic_locked / topic_unlocked WebSocket event
+// @Tags         Moderation
+// @Accept       json
+// @Param        id       path      int               true  "Topic ID"
+// @Param        request  body      setLockedRequest  true  "Locked flag"
+// @Success      204
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/topics/{id}/locked [put]
+func (h *TopicHandler) SetTopicLocked(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid id"})
+		return
+	}
+	var req setLockedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if err := h.uc.LockTopic(c.Request.Context(), id, *req.Locked); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnauthenticated):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+		case errors.Is(err, usecase.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden"})
+		case errors.Is(err, usecase.ErrTopicNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: "topic not found"})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// MoveTopic — POST /admin/topics/{id}/move
+// @Summary      Move topic to another category (admin only)
+// @Description  Moves a topic to another category. The move is recorded in the topic audit log and subscribers get a topic_moved WebSocket event.
+// @Tags         Moderation
+// @Accept       json
+// @Param        id       path      int               true  "Topic ID"
+// @Param        request  body      moveTopicRequest  true  "Target category"
+// @Success      204
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/topics/{id}/move [post]
+func (h *TopicHandler) MoveTopic(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid id"})
+		return
+	}
+	var req moveTopicRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if err := h.uc.MoveTopic(c.Request.Context(), id, req.CategoryID); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnauthenticated):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+		case errors.Is(err, usecase.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden"})
+		case errors.Is(err, usecase.ErrTopicNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: "topic not found"})
+		case errors.Is(err, usecase.ErrCategoryNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: "category not found"})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// MergeTopic — POST /admin/topics/{id}/merge
+// @Summary      Merge topic into another (admin only)
+// @Description  Moves all messages of the topic into the target topic, keeping their timestamps. The old topic ID keeps resolving to the target. Subscribers of both topics get a topic_merged WebSocket event.
+// @Tags         Moderation
+// @Accept       json
+// @Param        id       path      int                true  "Source topic ID"
+// @Param        request  body      mergeTopicRequest  true  "Target topic"
+// @Success      204
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/topics/{id}/merge [post]
+func (h *TopicHandler) MergeTopic(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid id"})
+		return
+	}
+	var req mergeTopicRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if err := h.uc.MergeTopics(c.Request.Context(), id, req.TargetTopicID); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnauthenticated):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+		case errors.Is(err, usecase.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden"})
+		case errors.Is(err, usecase.ErrInvalidMerge):
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		case errors.Is(err, usecase.ErrTopicNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: "topic not found"})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// withAnswer отмечает решённый топик и его принятый ответ
+func withAnswer(resp *topicResponse, t *entity.Topic) {
+	resp.Solved = t.IsSolved()
+	if resp.Solved {
+		resp.AcceptedMessageID = t.AcceptedID
+	}
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"chat-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type VoteHandler struct {
+	uc usecase.VoteUsecase
+}
+
+func NewVoteHandler(uc usecase.VoteUsecase) *VoteHandler {
+	return &VoteHandler{uc: uc}
+}
+
+// Vote — PUT /messages/{id}/vote
+// @Summary      Vote for a message
+// @Description  Sets my vote: 1 for, -1 against. A new vote replaces my previous one; voting for my own messages is not allowed. The vote changes the message score and the author's reputation. Downvoting needs enough reputation, otherwise 403 with code "insufficient_reputation".
+// @Tags         Message
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int          true  "Message ID"
+// @Param        request  body      voteRequest  true  "Vote"
+// @Success      200      {object}  voteResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /messages/{id}/vote [put]
+func (h *VoteHandler) Vote(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid message id"})
+		return
+	}
+	var req voteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+	h.vote(c, id, req.Value)
+}
+
+// Unvote — DELETE /messages/{id}/vote
+// @Summary      Remove my vote
+// @Description  Removes my vote from a message; without a vote nothing changes.
+// @Tags         Message
+// @Produce      json
+// @Param        id   path      int  true  "Message ID"
+// @Success      200  {object}  voteResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /messages/{id}/vote [delete]
+func (h *VoteHandler) Unvote(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: "invalid message id"})
+		return
+	}
+	h.vote(c, id, 0)
+}
+
+func (h *VoteHandler) vote(c *gin.Context, id int64, value int) {
+	res, err := h.uc.Vote(c.Request.Context(), id, value)
+	if err != nil {
+		if abortIfUnprivileged(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, usecase.ErrUnauthenticated):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+		case errors.Is(err, usecase.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Message: "forbidden: insufficient privileges"})
+		case errors.Is(err, usecase.ErrSelfVote):
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Code: "self_vote", Message: err.Error()})
+		case errors.Is(err, usecase.ErrInvalidVote):
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		case errors.Is(err, usecase.ErrMessageNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Message: "message not found"})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, voteResponse{MessageID: res.MessageID, Score: res.Score, MyVote: res.Value})
+}
+
+// abortIfUnprivileged отвечает 403 с кодом insufficient_reputation, если репутации не хватило на действие
+func abortIfUnprivileged(c *gin.Context, err error) bool {
+	var e *usecase.InsufficientReputationError
+	if !errors.As(err, &e) {
+		return false
+	}
+	c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Code: "insufficient_reputation", Message: e.Error()})
+	return true
+}
@@ -2,10 +2,12 @@ package http
 
 import (
 	"chat-service/internal/entity"
-	"fmt"
+	"chat-service/internal/usecase"
+	"context"
 	"net/http"
 	"strconv"
 
+	"github.com/ZoyaDenisova/go-common/logger"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 
@@ -17,18 +19,23 @@ var upgrader = websocket.Upgrader{
 }
 
 type WSHandler struct {
-	Hub *ws.Hub
+	Hub           *ws.Hub
+	reads         usecase.ReadUsecase
+	conversations usecase.ConversationUsecase
+	rooms         usecase.RoomUsecase
+	log           logger.Interface
 }
 
-func NewWSHandler(h *ws.Hub) *WSHandler {
-	return &WSHandler{Hub: h}
+func NewWSHandler(h *ws.Hub, reads usecase.ReadUsecase, conversations usecase.ConversationUsecase, rooms usecase.RoomUsecase, l logger.Interface) *WSHandler {
+	return &WSHandler{Hub: h, reads: reads, conversations: conversations, rooms: rooms, log: l}
 }
 
 // ServeWS — GET /ws/topics/{id}
 // @Summary      WebSocket for real-time chat
-// @Description  Subscribes to live messages in a topic
+// @Description  Subscribes to live messages in a topic. Works without a token; with one (header or ?access_token=) the client may send {"action":"read","message_id":N} to move its read position, same as POST /topics/{id}/read (message_id 0 or omitted — read to the end).
 // @Tags         WebSocket
-// @Param        id   path      int  true  "Topic ID"
+// @Param        id            path      int     true   "Topic ID"
+// @Param        access_token  query     string  false  "Access token, if the Authorization header cannot be set"
 // @Success      101  {string}  string  "Switching Protocols"
 // @Failure      400  {object}  ErrorResponse
 // @Failure      401  {object}  ErrorResponse
@@ -43,7 +50,7 @@ func (h *WSHandler) ServeWS(c *gin.Context) {
 
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
-		fmt.Println("WebSocket upgrade failed:", err)
+		h.log.Warn("websocket upgrade failed", "path", c.FullPath(), "err", err)
 		return
 	}
 
@@ -53,6 +60,150 @@ func (h *WSHandler) ServeWS(c *gin.Context) {
 		TopicID: tid,
 		Send:    make(chan *entity.WSEvent, 32),
 	}
+	if uid, _ := UserIDFromCtx(c.Request.Context()); uid != 0 {
+		ctx := c.Request.Context()
+		client.OnCommand = func(cmd *entity.WSCommand) {
+			if cmd.Action != entity.WSCommandRead {
+				return
+			}
+			var messageID *int64
+			if cmd.MessageID > 0 {
+				messageID = &cmd.MessageID
+			}
+			// ошибки (чужое или удалённое сообщение) уже залогированы usecase; соединение не рвём
+			_, _ = h.reads.MarkTopicRead(ctx, tid, messageID)
+		}
+	}
+	h.Hub.Register(client)
+	client.Listen()
+}
+
+// ServeUserWS — GET /ws/me
+// @Summary      Personal WebSocket channel
+// @Description  Live events for the current user: "notification" with a new entry of GET /notifications (replies, mentions, quotes, moderation). Browsers that cannot set the Authorization header may pass the token as ?access_token=
+// @Tags         WebSocket
+// @Param        access_token  query     string  false  "Access token, if the Authorization header cannot be set"
+// @Success      101  {string}  string  "Switching Protocols"
+// @Failure      401  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /ws/me [get]
+func (h *WSHandler) ServeUserWS(c *gin.Context) {
+	uid, _ := UserIDFromCtx(c.Request.Context())
+	if uid == 0 {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Message: "unauthenticated"})
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		h.log.Warn("websocket upgrade failed", "path", c.FullPath(), "err", err)
+		return
+	}
+
+	client := &ws.Client{
+		Conn:   conn,
+		Hub:    h.Hub,
+		UserID: uid,
+		Send:   make(chan *entity.WSEvent, 32),
+	}
+	h.Hub.Register(client)
+	client.Listen()
+}
+
+// ServeConversationWS — GET /ws/conversations/{id}
+// @Summary      Private conversation WebSocket
+// @Description  Live events of a conversation I am a member of: "created" with private_message, "deleted" with message_id. The client may send {"action":"read","message_id":N} to move its read position (0 or omitted — read to the end). Browsers that cannot set the Authorization header may pass the token as ?access_token=
+// @Tags         WebSocket
+// @Param        id            path      int     true   "Conversation ID"
+// @Param        access_token  query     string  false  "Access token, if the Authorization header cannot be set"
+// @Success      101  {string}  string  "Switching Protocols"
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /ws/conversations/{id} [get]
+func (h *WSHandler) ServeConversationWS(c *gin.Context) {
+	id, ok := conversationID(c)
+	if !ok {
+		return
+	}
+	// участие проверяется до апгрейда, чтобы чужой получил обычный 404
+	if _, err := h.conversations.GetConversation(c.Request.Context(), id); err != nil {
+		conversationError(c, err)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		h.log.Warn("websocket upgrade failed", "path", c.FullPath(), "err", err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	client := &ws.Client{
+		Conn:           conn,
+		Hub:            h.Hub,
+		ConversationID: id,
+		Send:           make(chan *entity.WSEvent, 32),
+		OnCommand: func(cmd *entity.WSCommand) {
+			if cmd.Action != entity.WSCommandRead {
+				return
+			}
+			var messageID *int64
+			if cmd.MessageID > 0 {
+				messageID = &cmd.MessageID
+			}
+			_, _ = h.conversations.MarkRead(ctx, id, messageID)
+		},
+	}
+	h.Hub.Register(client)
+	client.Listen()
+}
+
+// ServeRoomWS — GET /ws/rooms/{id}
+// @Summary      Chat room WebSocket
+// @Description  Live events of a room: "created" and "updated" with room_message, "deleted" with message_id, and "presence" with the current list of people in the room whenever someone joins or leaves. Works without a token; with one (header or ?access_token=) the user is listed in presence.
+// @Tags         WebSocket
+// @Param        id            path      string  true   "Room ID or slug"
+// @Param        access_token  query     string  false  "Access token, if the Authorization header cannot be set"
+// @Success      101  {string}  string  "Switching Protocols"
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Security     BearerAuth
+// @Router       /ws/rooms/{id} [get]
+func (h *WSHandler) ServeRoomWS(c *gin.Context) {
+	var (
+		rm  *entity.Room
+		err error
+	)
+	if id, perr := strconv.ParseInt(c.Param("id"), 10, 64); perr == nil {
+		rm, err = h.rooms.GetRoom(c.Request.Context(), id)
+	} else {
+		rm, err = h.rooms.GetRoomBySlug(c.Request.Context(), c.Param("id"))
+	}
+	if err != nil {
+		roomError(c, err)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		h.log.Warn("websocket upgrade failed", "path", c.FullPath(), "err", err)
+		return
+	}
+
+	uid, _ := UserIDFromCtx(c.Request.Context())
+	client := &ws.Client{
+		Conn:     conn,
+		Hub:      h.Hub,
+		RoomID:   rm.ID,
+		ViewerID: uid,
+		Send:     make(chan *entity.WSEvent, 32),
+	}
+	// контекст запроса после ухода клиента уже отменён, поэтому присутствие рассылается в фоновом
+	ctx := context.WithoutCancel(c.Request.Context())
 	h.Hub.Register(client)
+	h.rooms.BroadcastPresence(ctx, rm.ID)
 	client.Listen()
+	h.rooms.BroadcastPresence(ctx, rm.ID)
 }
@@ -2,6 +2,7 @@ package ws
 
 import (
 	"chat-service/internal/entity"
+	"encoding/json"
 	"github.com/gorilla/websocket"
 	"time"
 )
@@ -10,7 +11,15 @@ type Client struct {
 	Conn    *websocket.Conn
 	Hub     *Hub
 	TopicID int64
-	Send    chan *entity.WSEvent
+	UserID  int64 // личный канал пользователя; 0 — подписка на топик TopicID, переписку ConversationID или комнату RoomID
+	// ConversationID — канал личной переписки; 0 — канал топика или личный
+	ConversationID int64
+	// RoomID — канал комнаты; ViewerID — кто его открыл (для присутствия), 0 — аноним
+	RoomID   int64
+	ViewerID int64
+	Send     chan *entity.WSEvent
+	// OnCommand получает кадры клиента (например, {"action":"read"}); nil — входящие кадры отбрасываются
+	OnCommand func(cmd *entity.WSCommand)
 }
 
 func (c *Client) Listen() {
@@ -24,11 +33,22 @@ func (c *Client) Listen() {
 
 	go c.writePump()
 
-	// простая «пин-понг» петля, чтобы держать соединение живым
+	// простая «пин-понг» петля, чтобы держать соединение живым; заодно разбираем команды клиента
 	for {
-		if _, _, err := c.Conn.NextReader(); err != nil {
+		if c.OnCommand == nil {
+			if _, _, err := c.Conn.NextReader(); err != nil {
+				break
+			}
+			continue
+		}
+		_, data, err := c.Conn.ReadMessage()
+		if err != nil {
 			break
 		}
+		var cmd entity.WSCommand
+		if json.Unmarshal(data, &cmd) == nil {
+			c.OnCommand(&cmd)
+		}
 	}
 }
 
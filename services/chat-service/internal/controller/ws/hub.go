@@ -35,7 +35,7 @@ func (h *Hub) Publish(topicID int64, ev *entity.WSEvent) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 	for c := range h.clients {
-		if c.TopicID == topicID {
+		if c.UserID == 0 && c.ConversationID == 0 && c.RoomID == 0 && c.TopicID == topicID {
 			select {
 			case c.Send <- ev:
 			default:
@@ -43,3 +43,69 @@ func (h *Hub) Publish(topicID int64, ev *entity.WSEvent) {
 		}
 	}
 }
+
+// PublishToUser отправляет событие во все личные каналы пользователя (у него может быть несколько вкладок)
+func (h *Hub) PublishToUser(userID int64, ev *entity.WSEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for c := range h.clients {
+		if c.UserID == userID {
+			select {
+			case c.Send <- ev:
+			default:
+			}
+		}
+	}
+}
+
+// PublishToConversation рассылает событие всем, у кого открыт канал переписки
+func (h *Hub) PublishToConversation(conversationID int64, ev *entity.WSEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for c := range h.clients {
+		if c.ConversationID == conversationID {
+			select {
+			case c.Send <- ev:
+			default:
+			}
+		}
+	}
+}
+
+// PublishToRoom рассылает событие всем, у кого открыт канал комнаты
+func (h *Hub) PublishToRoom(roomID int64, ev *entity.WSEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for c := range h.clients {
+		if c.RoomID == roomID {
+			select {
+			case c.Send <- ev:
+			default:
+			}
+		}
+	}
+}
+
+// RoomViewers возвращает вошедших пользователей, у которых открыта комната (каждого один раз),
+// и число анонимных подключений к ней
+func (h *Hub) RoomViewers(roomID int64) ([]int64, int) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	seen := make(map[int64]struct{})
+	users := make([]int64, 0)
+	anonymous := 0
+	for c := range h.clients {
+		if c.RoomID != roomID {
+			continue
+		}
+		if c.ViewerID == 0 {
+			anonymous++
+			continue
+		}
+		if _, ok := seen[c.ViewerID]; !ok {
+			seen[c.ViewerID] = struct{}{}
+			users = append(users, c.ViewerID)
+		}
+	}
+	return users, anonymous
+}
@@ -1,6 +1,7 @@
 package cron
 
 import (
+	"chat-service/config"
 	"chat-service/internal/usecase"
 	"context"
 	"github.com/ZoyaDenisova/go-common/logger"
@@ -10,35 +11,84 @@ import (
 )
 
 type CleanupCron struct {
-	log logger.Interface
-	uc  usecase.MessageUsecase
+	log     logger.Interface
+	uc      usecase.MessageUsecase
+	topicUC usecase.TopicUsecase
+	muteUC  usecase.MuteUsecase
+	rateUC  usecase.RateLimitUsecase
+	roomUC  usecase.RoomUsecase
+	attUC   usecase.AttachmentUsecase
 }
 
-func NewCleanupCron(log logger.Interface, uc usecase.MessageUsecase) *CleanupCron {
+func NewCleanupCron(log logger.Interface, uc usecase.MessageUsecase, topicUC usecase.TopicUsecase, muteUC usecase.MuteUsecase, rateUC usecase.RateLimitUsecase, roomUC usecase.RoomUsecase, attUC usecase.AttachmentUsecase) *CleanupCron {
 	return &CleanupCron{
-		log: log,
-		uc:  uc,
+		log:     log,
+		uc:      uc,
+		topicUC: topicUC,
+		muteUC:  muteUC,
+		rateUC:  rateUC,
+		roomUC:  roomUC,
+		attUC:   attUC,
 	}
 }
 
-func (c *CleanupCron) Start(schedule string, thresholdHours int) {
+// Start регистрирует очистку сообщений топиков и комнат по политикам хранения, окончательное удаление
+// tombstone-записей, пролежавших дольше TombstoneRetentionHours, удаление вложений без сообщения,
+// истёкших мьютов и уже наполнившихся вёдер лимитов частоты.
+func (c *CleanupCron) Start(cfg config.Cleanup) {
 	cronScheduler := cron.New()
 
-	_, err := cronScheduler.AddFunc(schedule, func() {
-		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	_, err := cronScheduler.AddFunc(cfg.Cron, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 		defer cancel()
 
-		threshold := time.Now().UTC().Add(-time.Duration(thresholdHours) * time.Hour)
+		threshold := time.Now().UTC().Add(-time.Duration(cfg.HoursAgo) * time.Hour)
 
-		c.log.Info("cron: running message cleanup", "threshold", threshold)
-		if err := c.uc.CleanupOldMessages(ctx, threshold); err != nil {
+		c.log.Info("cron: running message cleanup", "threshold", threshold, "dry_run", cfg.DryRun)
+		if _, err := c.uc.CleanupOldMessages(ctx, usecase.CleanupParams{
+			Threshold: threshold,
+			DryRun:    cfg.DryRun,
+			BatchSize: cfg.BatchSize,
+		}); err != nil {
 			c.log.Error("cron: cleanup failed", "err", err)
 		}
+
+		if cfg.DryRun {
+			return
+		}
+
+		if err := c.roomUC.CleanupRoomMessages(ctx, threshold, cfg.BatchSize); err != nil {
+			c.log.Error("cron: room cleanup failed", "err", err)
+		}
+
+		purgeBefore := time.Now().UTC().Add(-time.Duration(cfg.TombstoneRetentionHours) * time.Hour)
+
+		c.log.Info("cron: purging tombstones", "before", purgeBefore)
+		if err := c.uc.PurgeDeletedMessages(ctx, purgeBefore); err != nil {
+			c.log.Error("cron: message tombstone purge failed", "err", err)
+		}
+		if err := c.topicUC.PurgeDeletedTopics(ctx, purgeBefore); err != nil {
+			c.log.Error("cron: topic tombstone purge failed", "err", err)
+		}
+		if err := c.roomUC.PurgeDeletedRoomMessages(ctx, purgeBefore); err != nil {
+			c.log.Error("cron: room message tombstone purge failed", "err", err)
+		}
+		// после окончательного удаления сообщений их вложения тоже остаются без сообщения
+		orphansBefore := time.Now().UTC().Add(-time.Duration(cfg.OrphanAttachmentHours) * time.Hour)
+		if err := c.attUC.CleanupOrphans(ctx, orphansBefore, cfg.BatchSize); err != nil {
+			c.log.Error("cron: orphan attachment cleanup failed", "err", err)
+		}
+		if err := c.muteUC.PurgeExpiredMutes(ctx, time.Now().UTC()); err != nil {
+			c.log.Error("cron: expired mutes purge failed", "err", err)
+		}
+		if err := c.rateUC.PurgeRateLimits(ctx); err != nil {
+			c.log.Error("cron: rate limit purge failed", "err", err)
+		}
 	})
 	if err != nil {
 		c.log.Fatal("failed to register cron job", "err", err)
 	}
 
-	c.log.Info("cron: cleanup job scheduled", "schedule", schedule)
+	c.log.Info("cron: cleanup job scheduled", "schedule", cfg.Cron)
 	cronScheduler.Start()
 }
@@ -0,0 +1,43 @@
+package cron
+
+import (
+	"chat-service/config"
+	"chat-service/internal/usecase"
+	"context"
+	"github.com/ZoyaDenisova/go-common/logger"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+type DigestCron struct {
+	log logger.Interface
+	uc  usecase.SubscriptionUsecase
+}
+
+func NewDigestCron(log logger.Interface, uc usecase.SubscriptionUsecase) *DigestCron {
+	return &DigestCron{log: log, uc: uc}
+}
+
+// Start регистрирует рассылку дайджестов: каждый запуск отправляет их тем, у кого с прошлого дайджеста
+// прошли сутки или неделя — в зависимости от выбранной частоты.
+func (c *DigestCron) Start(cfg config.Digest) {
+	cronScheduler := cron.New()
+
+	_, err := cronScheduler.AddFunc(cfg.Cron, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+		defer cancel()
+
+		now := time.Now().UTC()
+		c.log.Info("cron: sending digests", "now", now)
+		if _, err := c.uc.SendDigests(ctx, now); err != nil {
+			c.log.Error("cron: digests failed", "err", err)
+		}
+	})
+	if err != nil {
+		c.log.Fatal("failed to register cron job", "err", err)
+	}
+
+	c.log.Info("cron: digest job scheduled", "schedule", cfg.Cron)
+	cronScheduler.Start()
+}
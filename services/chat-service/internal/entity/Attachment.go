@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+// Attachment — файл, приложенный к сообщению. Пока сообщение не отправлено, MessageID пуст
+// и вложение видит только тот, кто его загрузил.
+type Attachment struct {
+	ID           int64     `json:"id"`
+	UploaderID   int64     `json:"uploader_id"`
+	MessageID    *int64    `json:"message_id,omitempty"`
+	FileName     string    `json:"file_name"`
+	ContentType  string    `json:"content_type"` // определён по содержимому файла
+	Size         int64     `json:"size"`
+	Width        int       `json:"width,omitempty"` // для картинок
+	Height       int       `json:"height,omitempty"`
+	StorageKey   string    `json:"-"`
+	ThumbnailKey string    `json:"-"` // пусто, если миниатюры нет
+	CreatedAt    time.Time `json:"created_at"`
+	// Hidden — сообщение удалено или скрыто после жалоб; файл тогда не отдаётся
+	Hidden bool `json:"-"`
+}
+
+// HasThumbnail сообщает, что для вложения есть уменьшенная картинка
+func (a *Attachment) HasThumbnail() bool {
+	return a.ThumbnailKey != ""
+}
+
+// IsPending — вложение загружено, но ещё не привязано к сообщению
+func (a *Attachment) IsPending() bool {
+	return a.MessageID == nil
+}
@@ -1,7 +1,22 @@
 package entity
 
 type Category struct {
-	ID          int64  `db:"id"`
-	Title       string `db:"title"`
-	Description string `db:"description"`
+	ID           int64  `db:"id"`
+	Title        string `db:"title"`
+	Description  string `db:"description"`
+	ParentID     *int64 `db:"parent_id"` // nil — корневая категория
+	Position     int    `db:"position"`  // порядок среди соседей
+	QA           bool   `db:"qa"`        // вопросы и ответы: в топиках отмечается принятый ответ
+	Retention    RetentionPolicy
+	TopicCount   int64           // живые топики самой категории, без подкатегорий
+	MessageCount int64           // сообщения в этих топиках
+	Children     []*Category     // заполняется при построении дерева
+	Unread       *CategoryUnread // непрочитанное текущего пользователя; nil — аноним
+}
+
+// CategoryPosition — новое место категории в дереве
+type CategoryPosition struct {
+	ID       int64
+	ParentID *int64
+	Position int
 }
@@ -0,0 +1,72 @@
+package entity
+
+import "time"
+
+// Conversation — личная переписка: один на один или небольшая группа
+type Conversation struct {
+	ID            int64
+	Title         string // только у группы
+	IsGroup       bool
+	CreatedBy     *int64
+	CreatedAt     time.Time
+	LastMessageAt *time.Time
+	Members       []*ConversationMember
+	LastMessage   *PrivateMessage // в списке переписок
+	Unread        int64           // непрочитанные чужие сообщения — для того, кто запрашивает
+}
+
+// HasMember сообщает, состоит ли пользователь в переписке
+func (c *Conversation) HasMember(userID int64) bool {
+	return c.Member(userID) != nil
+}
+
+// Member возвращает участника или nil
+func (c *Conversation) Member(userID int64) *ConversationMember {
+	for _, m := range c.Members {
+		if m.UserID == userID {
+			return m
+		}
+	}
+	return nil
+}
+
+type ConversationMember struct {
+	UserID            int64
+	Name              string
+	LastReadMessageID int64
+	JoinedAt          time.Time
+}
+
+// PrivateMessage — сообщение личной переписки; у удалённого Content пустой
+type PrivateMessage struct {
+	ID             int64      `json:"id"`
+	ConversationID int64      `json:"conversation_id"`
+	AuthorID       int64      `json:"author_id"`
+	AuthorName     string     `json:"author_name"`
+	Content        string     `json:"content"`
+	ContentHTML    string     `json:"content_html"` // Content, отрисованный из Markdown; в базе не хранится
+	CreatedAt      time.Time  `json:"created_at"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+}
+
+// IsDeleted сообщает, что сообщение удалено автором
+func (m *PrivateMessage) IsDeleted() bool {
+	return m.DeletedAt != nil
+}
+
+// Correspondent — пользователь, которому собираются написать, глазами отправителя
+type Correspondent struct {
+	UserID          int64
+	Name            string
+	Banned          bool // заблокирован на форуме
+	BlocksSender    bool // запретил отправителю писать ему
+	BlockedBySender bool // отправитель сам запретил ему писать
+}
+
+// UserBlock — запрет пользователю BlockedUserID писать пользователю UserID
+type UserBlock struct {
+	UserID          int64
+	BlockedUserID   int64
+	BlockedUserName string
+	CreatedAt       time.Time
+}
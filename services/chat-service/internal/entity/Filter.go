@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+// FilterAction — что делать с текстом, на котором сработал фильтр
+type FilterAction string
+
+const (
+	FilterReject FilterAction = "reject" // не сохранять, вернуть автору ошибку
+	FilterMask   FilterAction = "mask"   // заменить совпадение звёздочками (ссылку — заглушкой)
+	FilterFlag   FilterAction = "flag"   // сохранить как есть и отправить в очередь жалоб
+)
+
+// Valid проверяет, что действие известно
+func (a FilterAction) Valid() bool {
+	return a == FilterReject || a == FilterMask || a == FilterFlag
+}
+
+// Виды правил фильтра
+const (
+	FilterWord      = "word"       // запрещённое слово (с учётом словоформ)
+	FilterLinkDeny  = "link_deny"  // запрещённый домен (вместе с поддоменами)
+	FilterLinkAllow = "link_allow" // разрешённый домен; если список не пуст, остальные ссылки — UnlistedLinkAction
+)
+
+// FilterRule — правило фильтра контента. У link_allow действия нет.
+type FilterRule struct {
+	ID        int64
+	Kind      string
+	Pattern   string
+	Action    FilterAction
+	CreatedBy *int64
+	CreatedAt time.Time
+}
+
+// FilterSettings — общие настройки фильтров. Нулевой лимит отключает соответствующую проверку.
+type FilterSettings struct {
+	MaxLength          int // в символах
+	LengthAction       FilterAction
+	MaxLinks           int // больше ссылок в одном тексте — флуд
+	LinkFloodAction    FilterAction
+	UnlistedLinkAction FilterAction
+	DuplicateWindow    time.Duration // повтор своего же сообщения за это время — спам
+	DuplicateAction    FilterAction  // mask для повторов не имеет смысла
+	UpdatedBy          *int64
+	UpdatedAt          time.Time
+}
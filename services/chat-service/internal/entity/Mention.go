@@ -0,0 +1,10 @@
+package entity
+
+import "time"
+
+// Mention — упоминание пользователя в сообщении
+type Mention struct {
+	Message    *Message
+	TopicTitle string
+	CreatedAt  time.Time // когда упомянули; при правке сообщения сохраняется для уже упомянутых
+}
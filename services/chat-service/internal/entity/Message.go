@@ -3,10 +3,25 @@ package entity
 import "time"
 
 type Message struct {
-	ID         int64     `db:"id"         json:"id"`
-	TopicID    int64     `db:"topic_id"   json:"topic_id"`
-	AuthorID   int64     `db:"author_id"  json:"author_id"`
-	AuthorName string    `db:"author_name" json:"author_name"`
-	Content    string    `db:"content"    json:"content"`
-	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	ID           int64         `db:"id"         json:"id"`
+	TopicID      int64         `db:"topic_id"   json:"topic_id"`
+	AuthorID     int64         `db:"author_id"  json:"author_id"`
+	AuthorName   string        `db:"author_name" json:"author_name"`
+	Content      string        `db:"content"    json:"content"`
+	ContentHTML  string        `db:"-"          json:"content_html"` // Content, отрисованный из Markdown; в базе не хранится
+	CreatedAt    time.Time     `db:"created_at" json:"created_at"`
+	DeletedAt    *time.Time    `db:"deleted_at"    json:"deleted_at,omitempty"`
+	DeletedBy    *int64        `db:"deleted_by"    json:"deleted_by,omitempty"`
+	DeleteReason string        `db:"delete_reason" json:"delete_reason,omitempty"`
+	Hidden       bool          `db:"hidden"        json:"hidden,omitempty"`   // скрыто после жалоб до решения модератора
+	QuoteID      *int64        `db:"quote_id"      json:"quote_id,omitempty"` // цитируемое сообщение того же топика
+	Score        int64         `db:"score"         json:"score"`              // сумма голосов
+	MyVote       int           `db:"-"             json:"my_vote,omitempty"`  // голос текущего пользователя: 1, -1 или 0
+	Accepted     bool          `db:"accepted"      json:"accepted,omitempty"` // принятый ответ топика
+	Attachments  []*Attachment `db:"-"          json:"attachments,omitempty"`
+}
+
+// IsDeleted сообщает, что сообщение мягко удалено (tombstone)
+func (m *Message) IsDeleted() bool {
+	return m.DeletedAt != nil
 }
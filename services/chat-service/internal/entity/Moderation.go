@@ -0,0 +1,53 @@
+package entity
+
+import "time"
+
+// Действия модерации, которые пишутся в moderation_log
+const (
+	ModDeleteMessage  = "message.delete"
+	ModRestoreMessage = "message.restore"
+	ModDeleteTopic    = "topic.delete"
+	ModRestoreTopic   = "topic.restore"
+	ModPinTopic       = "topic.pin"
+	ModUnpinTopic     = "topic.unpin"
+	ModLockTopic      = "topic.lock"
+	ModUnlockTopic    = "topic.unlock"
+	ModMoveTopic      = "topic.move"
+	ModMergeTopic     = "topic.merge"
+	ModBlockUser      = "user.block"
+	ModMuteUser       = "user.mute"
+	ModUnmuteUser     = "user.unmute"
+)
+
+// Типы объектов модерации
+const (
+	TargetMessage = "message"
+	TargetTopic   = "topic"
+	TargetUser    = "user"
+)
+
+// ModerationEntry — запись журнала модерации.
+// Snapshot хранит содержимое объекта на момент действия, Details — параметры действия.
+type ModerationEntry struct {
+	ID             int64
+	ActorID        *int64
+	Action         string
+	TargetType     string
+	TargetID       int64
+	TargetAuthorID *int64
+	Reason         string
+	Snapshot       map[string]any
+	Details        map[string]any
+	CreatedAt      time.Time
+}
+
+// ModerationFilter — условия выборки журнала; нулевые поля не фильтруют
+type ModerationFilter struct {
+	ActorID        int64
+	TargetAuthorID int64
+	TargetType     string
+	TargetID       int64
+	Action         string
+	Limit          int
+	Offset         int
+}
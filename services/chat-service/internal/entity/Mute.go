@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// Mute — запрет пользователю писать в топике (TopicID) или категории с подкатегориями (CategoryID).
+// Заполнено ровно одно из двух. ExpiresAt = nil — бессрочно.
+type Mute struct {
+	ID         int64
+	UserID     int64
+	TopicID    *int64
+	CategoryID *int64
+	Reason     string
+	MutedBy    *int64
+	ExpiresAt  *time.Time
+	CreatedAt  time.Time
+}
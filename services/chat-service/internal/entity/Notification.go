@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// Типы уведомлений. Новый тип — это новая константа и место, где уведомление создаётся:
+// хранилище тип не ограничивает, а всё специфичное для типа кладётся в Data.
+const (
+	NotifyReply      = "reply"      // ответ в моём топике
+	NotifyMention    = "mention"    // меня упомянули как @имя
+	NotifyQuote      = "quote"      // процитировали моё сообщение
+	NotifyModeration = "moderation" // модератор что-то сделал с моим контентом или со мной
+)
+
+// Notification — уведомление пользователя UserID.
+// ActorID — кто вызвал уведомление; у модерации не заполняется, как и в журнале, который видит автор.
+type Notification struct {
+	ID        int64          `json:"id"`
+	UserID    int64          `json:"user_id"`
+	Type      string         `json:"type"`
+	ActorID   *int64         `json:"actor_id,omitempty"`
+	ActorName string         `json:"actor_name,omitempty"`
+	TopicID   *int64         `json:"topic_id,omitempty"`
+	MessageID *int64         `json:"message_id,omitempty"`
+	Data      map[string]any `json:"data,omitempty"`
+	ReadAt    *time.Time     `json:"read_at,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// NotificationFilter — страница уведомлений пользователя
+type NotificationFilter struct {
+	UserID     int64
+	UnreadOnly bool
+	Limit      int
+	Offset     int
+}
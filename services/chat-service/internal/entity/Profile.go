@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// Profile — публичный профиль пользователя. Email сюда не попадает никогда.
+type Profile struct {
+	UserID          int64
+	Username        string // уникальный адрес для упоминаний и ссылок на профиль
+	Name            string // отображаемое имя
+	Bio             string
+	Location        string
+	Website         string
+	Signature       string // подпись под сообщениями
+	AvatarKey       string // ключ в blob-хранилище; пусто — аватара нет
+	AvatarUpdatedAt *time.Time
+	JoinedAt        time.Time // регистрация аккаунта
+
+	Stats  ProfileStats       // заполняет usecase для публичного профиля
+	Recent []*ProfileActivity // последние сообщения
+}
+
+// HasAvatar сообщает, что пользователь загрузил аватар
+func (p *Profile) HasAvatar() bool {
+	return p.AvatarKey != ""
+}
+
+// ProfileStats — публичная статистика: считаются только видимые всем топики и сообщения
+type ProfileStats struct {
+	Topics       int64
+	Messages     int64
+	LastActiveAt *time.Time // последнее видимое сообщение; nil — ещё ничего не писал
+	Reputation   int64
+}
+
+// ProfileActivity — сообщение в ленте последней активности профиля
+type ProfileActivity struct {
+	MessageID  int64
+	TopicID    int64
+	TopicTitle string
+	Excerpt    string
+	CreatedAt  time.Time
+}
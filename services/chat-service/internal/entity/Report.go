@@ -0,0 +1,67 @@
+package entity
+
+import "time"
+
+// Состояния жалобы
+const (
+	ReportOpen      = "open"
+	ReportDismissed = "dismissed" // жалоба отклонена, скрытый контент снова виден
+	ReportActioned  = "actioned"  // по жалобе приняты меры
+)
+
+// ReportReason — категория жалобы
+type ReportReason string
+
+const (
+	ReasonSpam       ReportReason = "spam"
+	ReasonAbuse      ReportReason = "abuse"
+	ReasonHarassment ReportReason = "harassment"
+	ReasonIllegal    ReportReason = "illegal"
+	ReasonOffTopic   ReportReason = "off_topic"
+	ReasonOther      ReportReason = "other"
+
+	// ReasonAutoFilter ставит фильтр контента; пользователи так жаловаться не могут
+	ReasonAutoFilter ReportReason = "auto_filter"
+)
+
+// Valid проверяет, что категория жалобы известна
+func (r ReportReason) Valid() bool {
+	switch r {
+	case ReasonSpam, ReasonAbuse, ReasonHarassment, ReasonIllegal, ReasonOffTopic, ReasonOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// Report — жалоба на сообщение или топик (TargetType — TargetMessage или TargetTopic)
+type Report struct {
+	ID         int64
+	TargetType string
+	TargetID   int64
+	ReporterID int64 // 0 — жалоба от фильтра контента
+	Reason     ReportReason
+	Comment    string
+	State      string
+	ResolvedBy *int64
+	ResolvedAt *time.Time
+	Resolution string
+	CreatedAt  time.Time
+
+	// заполняются при выборке
+	TargetAuthorID int64
+	TopicID        int64 // топик объекта (для жалобы на топик — он сам)
+	CategoryID     int64
+	OpenReports    int64 // сколько открытых жалоб на тот же объект
+	TargetHidden   bool
+	Excerpt        string // текст сообщения или заголовок топика
+}
+
+// ReportFilter — условия выборки очереди; ModeratorID != 0 — только категории этого модератора
+type ReportFilter struct {
+	State       string
+	TargetType  string
+	ModeratorID int64
+	Limit       int
+	Offset      int
+}
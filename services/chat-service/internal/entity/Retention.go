@@ -0,0 +1,50 @@
+package entity
+
+// RetentionMode — режим хранения сообщений в категории или топике
+type RetentionMode string
+
+const (
+	RetentionDefault RetentionMode = "default" // своей политики нет, действует родительская или глобальный порог
+	RetentionForever RetentionMode = "forever" // не удалять никогда
+	RetentionDays    RetentionMode = "days"    // хранить Value дней
+	RetentionLastN   RetentionMode = "last_n"  // хранить последние Value сообщений
+)
+
+// RetentionPolicy задаётся на категорию или топик; политика топика важнее политики категории
+type RetentionPolicy struct {
+	Mode  RetentionMode `db:"retention_mode"`
+	Value int           `db:"retention_value"`
+}
+
+// Valid проверяет, что режим известен и для days/last_n задано положительное значение
+func (p RetentionPolicy) Valid() bool {
+	switch p.Mode {
+	case RetentionDefault, RetentionForever:
+		return true
+	case RetentionDays, RetentionLastN:
+		return p.Value > 0
+	default:
+		return false
+	}
+}
+
+// IsDefault сообщает, что собственная политика не задана
+func (p RetentionPolicy) IsDefault() bool {
+	return p.Mode == "" || p.Mode == RetentionDefault
+}
+
+// RetentionStat — сколько сообщений топика попадает под очистку и по какой политике
+type RetentionStat struct {
+	TopicID    int64
+	CategoryID int64
+	Mode       RetentionMode
+	Count      int64
+}
+
+// CleanupReport — итог прогона очистки (или dry-run)
+type CleanupReport struct {
+	DryRun  bool
+	Total   int64 // удалено (или было бы удалено при dry-run)
+	Batches int
+	Topics  []*RetentionStat // заполняется только при dry-run
+}
@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+// Room — постоянный общий чат вне дерева категорий (например, «Общий чат»).
+// Комнатами управляют администраторы.
+type Room struct {
+	ID          int64
+	Slug        string // постоянное имя для ссылок, например "general"
+	Title       string
+	Description string
+	Position    int
+	Retention   RetentionPolicy
+	// RateBurst сообщений за RatePeriod от одного пользователя; нулевые — только общий лимит на сообщения
+	RateBurst  int
+	RatePeriod time.Duration
+	CreatedBy  *int64
+	CreatedAt  time.Time
+	Online     int // сколько клиентов сейчас подключено к комнате; в базе не хранится
+}
+
+// RoomMessage — сообщение в комнате
+type RoomMessage struct {
+	ID          int64      `json:"id"`
+	RoomID      int64      `json:"room_id"`
+	AuthorID    int64      `json:"author_id"`
+	AuthorName  string     `json:"author_name"`
+	Content     string     `json:"content"`
+	ContentHTML string     `json:"content_html"` // Content, отрисованный из Markdown; в базе не хранится
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+// IsDeleted сообщает, что сообщение удалено автором или модератором
+func (m *RoomMessage) IsDeleted() bool {
+	return m.DeletedAt != nil
+}
+
+// RoomViewer — вошедший пользователь, у которого открыта комната
+type RoomViewer struct {
+	UserID int64  `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+// RoomPresence — кто сейчас в комнате
+type RoomPresence struct {
+	RoomID    int64         `json:"room_id"`
+	Users     []*RoomViewer `json:"users"`
+	Anonymous int           `json:"anonymous"` // подключения без входа
+}
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// Standing — насколько пользователю можно доверять: возраст аккаунта, сколько он уже написал
+// и как его сообщения оценили другие
+type Standing struct {
+	UserID     int64
+	CreatedAt  time.Time // регистрация аккаунта
+	Posts      int64     // неудалённые сообщения
+	Reputation int64     // сумма голосов за его сообщения
+}
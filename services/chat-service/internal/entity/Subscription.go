@@ -0,0 +1,60 @@
+package entity
+
+import "time"
+
+// Частота email-дайджеста
+const (
+	DigestOff    = "off"
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// Subscription — пользователь следит за топиком (TopicID) или категорией с подкатегориями (CategoryID).
+// Заполнено ровно одно из двух; Title — название топика или категории.
+type Subscription struct {
+	ID         int64
+	UserID     int64
+	TopicID    *int64
+	CategoryID *int64
+	Title      string
+	CreatedAt  time.Time
+}
+
+// DigestSettings — как часто пользователь получает дайджест.
+// UnsubscribeToken позволяет отписаться по ссылке из письма без входа.
+type DigestSettings struct {
+	UserID           int64
+	Frequency        string
+	LastSentAt       *time.Time
+	UnsubscribeToken string
+	UpdatedAt        time.Time
+}
+
+// Period — промежуток между дайджестами; 0 — дайджест выключен
+func (s *DigestSettings) Period() time.Duration {
+	switch s.Frequency {
+	case DigestDaily:
+		return 24 * time.Hour
+	case DigestWeekly:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+// DigestRecipient — подписчик, которому пора отправить дайджест
+type DigestRecipient struct {
+	Settings DigestSettings
+	Name     string
+	Email    string
+}
+
+// DigestTopic — топик с новыми сообщениями для дайджеста.
+// Messages — последние из новых, свежие сверху; IsNew — сам топик создан после начала периода.
+type DigestTopic struct {
+	TopicID     int64
+	Title       string
+	IsNew       bool
+	NewMessages int64
+	LastAt      time.Time
+	Messages    []*Message
+}
@@ -3,11 +3,39 @@ package entity
 import "time"
 
 type Topic struct {
-	ID          int64     `db:"id"`
-	CategoryID  int64     `db:"category_id"`
-	Title       string    `db:"title"`
-	Description string    `db:"description"`
-	AuthorID    int64     `db:"author_id"`
-	AuthorName  string    `db:"author_name"`
-	CreatedAt   time.Time `db:"created_at"`
+	ID           int64      `db:"id"`
+	CategoryID   int64      `db:"category_id"`
+	Title        string     `db:"title"`
+	Description  string     `db:"description"`
+	AuthorID     int64      `db:"author_id"`
+	AuthorName   string     `db:"author_name"`
+	CreatedAt    time.Time  `db:"created_at"`
+	Pinned       bool       `db:"pinned"`      // закреплён вверху категории
+	Locked       bool       `db:"locked"`      // закрыт: новые сообщения не принимаются
+	RedirectTo   *int64     `db:"redirect_to"` // заглушка: топик слит в указанный
+	Hidden       bool       `db:"hidden"`      // скрыт после жалоб до решения модератора
+	DeletedAt    *time.Time `db:"deleted_at"`
+	DeletedBy    *int64     `db:"deleted_by"`
+	DeleteReason string     `db:"delete_reason"`
+	QA           bool       `db:"qa"`                  // категория топика — вопросы и ответы
+	AcceptedID   *int64     `db:"accepted_message_id"` // принятый ответ, пока он не удалён и не скрыт
+	Retention    RetentionPolicy
+	Read         *TopicRead // позиция чтения текущего пользователя; nil — аноним или топик ещё не открывал
+}
+
+// IsDeleted сообщает, что топик мягко удалён
+func (t *Topic) IsDeleted() bool {
+	return t.DeletedAt != nil
+}
+
+// IsSolved сообщает, что топик в категории вопросов и ответов и в нём есть принятый ответ.
+// Если категорию вывели из режима вопросов и ответов, прежние отметки не считаются. Отметка
+// удалённого или скрытого ответа в базе остаётся: восстановленный ответ снова решает топик.
+func (t *Topic) IsSolved() bool {
+	return t.QA && t.AcceptedID != nil
+}
+
+// IsRedirect сообщает, что топик — заглушка, оставшаяся после слияния
+func (t *Topic) IsRedirect() bool {
+	return t.RedirectTo != nil
 }
@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+// TopicRead — до какого сообщения пользователь дочитал топик
+type TopicRead struct {
+	UserID            int64
+	TopicID           int64
+	LastReadMessageID int64 // 0 — топик открыт, когда сообщений в нём ещё не было
+	Unread            int64 // видимые чужие сообщения после позиции
+	UpdatedAt         time.Time
+}
+
+// CategoryUnread — непрочитанное в топиках категории (без подкатегорий, как и счётчики категории)
+type CategoryUnread struct {
+	CategoryID int64
+	Title      string
+	Topics     int64 // топики, где есть непрочитанное
+	Messages   int64
+}
+
+// UnreadTopic — топик с непрочитанными сообщениями
+type UnreadTopic struct {
+	TopicID           int64
+	CategoryID        int64
+	Title             string
+	LastReadMessageID int64
+	Unread            int64
+	LastMessageAt     time.Time
+}
+
+// UnreadSummary — сводка непрочитанного по всему форуму
+type UnreadSummary struct {
+	Topics     int64
+	Messages   int64
+	Categories []*CategoryUnread
+	TopicList  []*UnreadTopic // самые свежие сверху
+}
@@ -0,0 +1,21 @@
+package entity
+
+// Значения голоса за сообщение
+const (
+	VoteUp   = 1
+	VoteDown = -1
+)
+
+// Привилегии, которые открываются с репутацией
+const (
+	PrivilegeCreateTopic = "create_topic" // создавать топики
+	PrivilegePostLinks   = "post_links"   // ставить ссылки в сообщениях, топиках, комнатах и личных сообщениях
+	PrivilegeDownvote    = "downvote"     // голосовать против
+)
+
+// MessageVote — счёт сообщения после голоса и голос проголосовавшего (0 — голос снят)
+type MessageVote struct {
+	MessageID int64
+	Score     int64
+	Value     int
+}
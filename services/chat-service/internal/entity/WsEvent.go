@@ -3,13 +3,52 @@ package entity
 type WSAction string
 
 const (
-	ActionCreated WSAction = "created"
-	ActionUpdated WSAction = "updated"
-	ActionDeleted WSAction = "deleted"
+	ActionCreated  WSAction = "created"
+	ActionUpdated  WSAction = "updated"
+	ActionDeleted  WSAction = "deleted"
+	ActionRestored WSAction = "restored"
+	ActionHidden   WSAction = "hidden"   // сообщение скрыто после жалоб
+	ActionUnhidden WSAction = "unhidden" // жалобы отклонены, сообщение снова видно
+
+	ActionNotification WSAction = "notification" // в личный канал: новое уведомление
+	ActionTopicRead    WSAction = "topic_read"   // в личный канал: топик дочитан до message_id (например, в другой вкладке)
+
+	ActionConversationMessage WSAction = "conversation_message" // в личный канал: новое сообщение в личной переписке
+	ActionConversationRead    WSAction = "conversation_read"    // в личный канал: переписка дочитана до message_id
+
+	ActionPresence WSAction = "presence" // в канале комнаты: изменился список присутствующих
+
+	ActionTopicLocked   WSAction = "topic_locked"
+	ActionTopicUnlocked WSAction = "topic_unlocked"
+	ActionTopicMoved    WSAction = "topic_moved"
+	ActionTopicMerged   WSAction = "topic_merged"
+
+	ActionAnswerAccepted   WSAction = "answer_accepted"   // в канале топика: message_id — принятый ответ
+	ActionAnswerUnaccepted WSAction = "answer_unaccepted" // в канале топика: отметка снята с message_id
 )
 
 type WSEvent struct {
-	Action    WSAction `json:"action"`               // created / updated / deleted
-	Message   *Message `json:"message,omitempty"`    // для created / updated
-	MessageID int64    `json:"message_id,omitempty"` // для deleted
+	Action        WSAction      `json:"action"`                    // created / updated / deleted / restored / notification / presence / topic_*
+	Message       *Message      `json:"message,omitempty"`         // для created / updated / restored
+	MessageID     int64         `json:"message_id,omitempty"`      // для deleted / hidden / unhidden / topic_read / conversation_read / answer_*
+	TopicID       int64         `json:"topic_id,omitempty"`        // для topic_* и topic_read
+	CategoryID    int64         `json:"category_id,omitempty"`     // для topic_moved — новая категория
+	TargetTopicID int64         `json:"target_topic_id,omitempty"` // для topic_merged — куда переехали сообщения
+	Notification  *Notification `json:"notification,omitempty"`    // для notification
+
+	ConversationID int64           `json:"conversation_id,omitempty"` // для conversation_* и событий канала переписки
+	PrivateMessage *PrivateMessage `json:"private_message,omitempty"` // для conversation_message и created в канале переписки
+
+	RoomID      int64         `json:"room_id,omitempty"`      // для событий канала комнаты
+	RoomMessage *RoomMessage  `json:"room_message,omitempty"` // для created / updated в канале комнаты
+	Presence    *RoomPresence `json:"presence,omitempty"`     // для presence
+}
+
+// WSCommandRead — клиент в канале топика или переписки сообщает, что дочитал до message_id (0 — до конца)
+const WSCommandRead = "read"
+
+// WSCommand — кадр, присланный клиентом в канал топика или переписки
+type WSCommand struct {
+	Action    string `json:"action"`
+	MessageID int64  `json:"message_id,omitempty"`
 }
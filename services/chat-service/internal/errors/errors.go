@@ -6,9 +6,15 @@ var (
 	// ErrNotFound возвращается, когда нужный ресурс не найден в хранилище.
 	ErrNotFound = errors.New("not found")
 
+	// ErrInvalidReference возвращается, когда запись ссылается на несуществующий ресурс (нарушение FK).
+	ErrInvalidReference = errors.New("invalid reference")
+
+	// ErrConflict возвращается, когда операция невозможна из-за текущего состояния связанных данных.
+	ErrConflict = errors.New("conflict")
+
+	// ErrPermissionDenied возвращается, когда внешний сервис отказал вызывающему в доступе.
+	ErrPermissionDenied = errors.New("permission denied")
+
 	//// ErrExpiredToken возвращается, когда токен существует, но просрочен.
 	//ErrExpiredToken = errors.New("token expired")
-	//
-	//// ErrConflict возвращается при попытке создать ресурс, который уже существует (уникальные поля нарушены).
-	//ErrConflict = errors.New("conflict")
 )
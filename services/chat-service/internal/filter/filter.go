@@ -0,0 +1,192 @@
+// Package filter — цепочка фильтров контента для сообщений и топиков.
+// Каждый фильтр сообщает о срабатываниях с действием reject, mask или flag;
+// цепочка сводит их в один Verdict. Правила приходят снаружи, пакет не ходит в базу.
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"chat-service/internal/entity"
+)
+
+// Input — проверяемый текст
+type Input struct {
+	Text string
+	// Recent — недавние тексты того же автора для проверки повторов; nil — не проверять
+	Recent []string
+}
+
+// Span — участок исходного текста (байтовые смещения) и чем его заменить при маскировании
+type Span struct {
+	Start, End int
+	Repl       string
+}
+
+// Hit — срабатывание фильтра. Reason можно показать автору, Detail — только модераторам.
+type Hit struct {
+	Filter string
+	Action entity.FilterAction
+	Reason string
+	Detail string
+	Span   *Span // nil — маскировать нечего, mask считается как flag
+}
+
+// Filter — звено цепочки
+type Filter interface {
+	Name() string
+	Check(in Input) []Hit
+}
+
+// Verdict — итог проверки
+type Verdict struct {
+	Text     string // текст после маскирования
+	Rejected *Hit   // первое срабатывание с reject; текст сохранять нельзя
+	Flags    []Hit  // срабатывания с flag — текст сохраняется и уходит модераторам
+	Masked   int    // сколько участков замаскировано
+}
+
+// Chain — упорядоченный набор фильтров
+type Chain struct {
+	filters []Filter
+}
+
+func NewChain(filters ...Filter) *Chain {
+	return &Chain{filters: filters}
+}
+
+// Build собирает стандартную цепочку из правил и настроек: длина, слова, ссылки, повторы
+func Build(rules []*entity.FilterRule, s entity.FilterSettings) *Chain {
+	var (
+		words []*entity.FilterRule
+		deny  []*entity.FilterRule
+		allow []string
+	)
+	for _, r := range rules {
+		switch r.Kind {
+		case entity.FilterWord:
+			words = append(words, r)
+		case entity.FilterLinkDeny:
+			deny = append(deny, r)
+		case entity.FilterLinkAllow:
+			allow = append(allow, r.Pattern)
+		}
+	}
+
+	filters := []Filter{NewLengthFilter(s.MaxLength, s.LengthAction)}
+	if len(words) > 0 {
+		filters = append(filters, NewWordFilter(words))
+	}
+	filters = append(filters, NewLinkFilter(deny, allow, s.UnlistedLinkAction, s.MaxLinks, s.LinkFloodAction))
+	if s.DuplicateWindow > 0 {
+		filters = append(filters, NewDuplicateFilter(s.DuplicateAction))
+	}
+	return NewChain(filters...)
+}
+
+// Check прогоняет текст через все фильтры. При первом reject проверка останавливается.
+func (c *Chain) Check(in Input) Verdict {
+	v := Verdict{Text: in.Text}
+	var masks []Span
+
+	for _, f := range c.filters {
+		for _, h := range f.Check(in) {
+			h := h
+			switch {
+			case h.Action == entity.FilterReject:
+				v.Rejected = &h
+				v.Flags = nil
+				return v
+			case h.Action == entity.FilterMask && h.Span != nil:
+				masks = append(masks, *h.Span)
+			default:
+				v.Flags = append(v.Flags, h)
+			}
+		}
+	}
+
+	v.Text, v.Masked = applyMasks(in.Text, masks)
+	return v
+}
+
+// applyMasks заменяет участки; пересекающиеся с уже заменёнными пропускаются
+func applyMasks(text string, spans []Span) (string, int) {
+	if len(spans) == 0 {
+		return text, 0
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].Start < spans[j].Start })
+
+	var (
+		b    strings.Builder
+		pos  int
+		done int
+	)
+	for _, s := range spans {
+		if s.Start < pos || s.End > len(text) {
+			continue
+		}
+		b.WriteString(text[pos:s.Start])
+		b.WriteString(s.Repl)
+		pos = s.End
+		done++
+	}
+	b.WriteString(text[pos:])
+	return b.String(), done
+}
+
+// maxPattern — предел длины шаблона правила
+const maxPattern = 100
+
+// NormalizeRule проверяет правило и приводит шаблон к каноническому виду:
+// слово — одно, без пробелов; домен — без схемы, www. и пути. У link_allow действие сбрасывается.
+func NormalizeRule(r *entity.FilterRule) error {
+	p := strings.TrimSpace(r.Pattern)
+	if p == "" || len([]rune(p)) > maxPattern {
+		return fmt.Errorf("pattern must be 1..%d characters", maxPattern)
+	}
+
+	switch r.Kind {
+	case entity.FilterWord:
+		if strings.IndexFunc(p, func(c rune) bool { return !isWordRune(c) }) >= 0 {
+			return errors.New("word pattern must be a single word")
+		}
+		r.Pattern = strings.ToLower(p)
+	case entity.FilterLinkDeny, entity.FilterLinkAllow:
+		host := linkHost(p)
+		if host == "" || !strings.Contains(host, ".") || strings.ContainsAny(host, " \t") {
+			return errors.New("link pattern must be a domain like example.com")
+		}
+		r.Pattern = host
+	default:
+		return fmt.Errorf("unknown rule kind %q", r.Kind)
+	}
+
+	if r.Kind == entity.FilterLinkAllow {
+		r.Action = ""
+	} else if !r.Action.Valid() {
+		return errors.New("action must be reject, mask or flag")
+	}
+	return nil
+}
+
+// maxDuplicateWindow — дольше повторы не ищутся: запрос истории идёт на каждое сообщение
+const maxDuplicateWindow = 24 * time.Hour
+
+// ValidateSettings проверяет общие настройки фильтров
+func ValidateSettings(s entity.FilterSettings) error {
+	if s.MaxLength < 0 || s.MaxLinks < 0 || s.DuplicateWindow < 0 || s.DuplicateWindow > maxDuplicateWindow {
+		return fmt.Errorf("limits must not be negative; duplicate window must not exceed %s", maxDuplicateWindow)
+	}
+	for _, a := range []entity.FilterAction{s.LengthAction, s.LinkFloodAction, s.UnlistedLinkAction, s.DuplicateAction} {
+		if !a.Valid() {
+			return errors.New("action must be reject, mask or flag")
+		}
+	}
+	if s.DuplicateAction == entity.FilterMask {
+		return errors.New("duplicate action must be reject or flag")
+	}
+	return nil
+}
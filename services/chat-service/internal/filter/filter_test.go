@@ -0,0 +1,58 @@
+package filter
+
+import (
+	"chat-service/internal/entity"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWordFilter_Check(t *testing.T) {
+	words := func(patterns ...string) []*entity.FilterRule {
+		rules := make([]*entity.FilterRule, 0, len(patterns))
+		for _, p := range patterns {
+			rules = append(rules, &entity.FilterRule{Kind: entity.FilterWord, Pattern: p, Action: entity.FilterMask})
+		}
+		return rules
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		hits    int
+	}{
+		{"exact word", "ass", "you ass", 1},
+		{"upper case", "ass", "YOU ASS", 1},
+		{"word form", "ass", "asses", 1},
+		{"leet", "ass", "a$$", 1},
+		{"stretched double letter", "ass", "asssss", 1},
+		{"stretched first letter", "ass", "aaaass", 1},
+		{"shorter word is not a match", "ass", "as soon as possible", 0},
+		{"longer word with the pattern inside", "ass", "first class", 0},
+		{"doubled letter in text is kept", "as", "ass", 0},
+		{"stretched russian", "дурак", "дууурак", 1},
+		{"russian word form", "дурак", "дураками", 1},
+		{"ё", "ёж", "ЕЖ", 1},
+		{"latin letters in russian word", "дурак", "дуpaк", 1},
+		{"cyrillic letters in english word", "idiot", "idiоt", 1},
+		{"short stem matched whole", "сука", "сукно", 0},
+		{"numbers untouched", "100", "1000", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := NewWordFilter(words(tt.pattern)).Check(Input{Text: tt.text})
+			require.Len(t, hits, tt.hits)
+		})
+	}
+}
+
+func TestChain_Check_Masks(t *testing.T) {
+	chain := NewChain(NewWordFilter([]*entity.FilterRule{
+		{Kind: entity.FilterWord, Pattern: "ass", Action: entity.FilterMask},
+	}))
+
+	v := chain.Check(Input{Text: "as a class, you a$$"})
+	require.Nil(t, v.Rejected)
+	require.Equal(t, 1, v.Masked)
+	require.Equal(t, "as a class, you ***", v.Text)
+}
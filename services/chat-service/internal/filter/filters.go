@@ -0,0 +1,211 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"chat-service/internal/entity"
+)
+
+// Имена фильтров (Hit.Filter)
+const (
+	NameLength    = "length"
+	NameWords     = "banned_words"
+	NameLinks     = "links"
+	NameDuplicate = "duplicate"
+)
+
+// linkMask — чем заменяется замаскированная ссылка
+const linkMask = "[link removed]"
+
+// LengthFilter ограничивает длину текста; mask обрезает текст до лимита
+type LengthFilter struct {
+	max    int
+	action entity.FilterAction
+}
+
+func NewLengthFilter(max int, action entity.FilterAction) *LengthFilter {
+	return &LengthFilter{max: max, action: action}
+}
+
+func (f *LengthFilter) Name() string { return NameLength }
+
+func (f *LengthFilter) Check(in Input) []Hit {
+	if f.max <= 0 || utf8.RuneCountInString(in.Text) <= f.max {
+		return nil
+	}
+	h := Hit{
+		Filter: NameLength,
+		Action: f.action,
+		Reason: fmt.Sprintf("text is longer than %d characters", f.max),
+	}
+	// байтовое смещение символа с номером max
+	cut, n := 0, 0
+	for i := range in.Text {
+		if n == f.max {
+			cut = i
+			break
+		}
+		n++
+	}
+	h.Span = &Span{Start: cut, End: len(in.Text)}
+	return []Hit{h}
+}
+
+type wordRule struct {
+	stem    string
+	pattern string
+	action  entity.FilterAction
+}
+
+// WordFilter ищет запрещённые слова с учётом словоформ, регистра, ё, leet-замен и смешения алфавитов
+type WordFilter struct {
+	rules []wordRule
+}
+
+func NewWordFilter(rules []*entity.FilterRule) *WordFilter {
+	f := &WordFilter{}
+	for _, r := range rules {
+		if s := normalizePattern(r.Pattern); s != "" {
+			f.rules = append(f.rules, wordRule{stem: s, pattern: r.Pattern, action: r.Action})
+		}
+	}
+	return f
+}
+
+func (f *WordFilter) Name() string { return NameWords }
+
+func (f *WordFilter) Check(in Input) []Hit {
+	var hits []Hit
+	for _, t := range tokenize(in.Text) {
+		stems := make([]string, len(t.forms))
+		for i, form := range t.forms {
+			stems[i] = stem(form)
+		}
+		for _, r := range f.rules {
+			if !matchAny(r.stem, stems) {
+				continue
+			}
+			hits = append(hits, Hit{
+				Filter: NameWords,
+				Action: r.action,
+				Reason: "text contains a banned word",
+				Detail: fmt.Sprintf("%q matches %q", in.Text[t.start:t.end], r.pattern),
+				Span:   &Span{Start: t.start, End: t.end, Repl: strings.Repeat("*", utf8.RuneCountInString(in.Text[t.start:t.end]))},
+			})
+			break
+		}
+	}
+	return hits
+}
+
+var linkRe = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>()"']+`)
+
+// LinkFilter проверяет ссылки по чёрному и белому спискам доменов и ограничивает их число
+type LinkFilter struct {
+	deny        []*entity.FilterRule
+	allow       []string
+	unlisted    entity.FilterAction
+	maxLinks    int
+	floodAction entity.FilterAction
+}
+
+func NewLinkFilter(deny []*entity.FilterRule, allow []string, unlisted entity.FilterAction, maxLinks int, flood entity.FilterAction) *LinkFilter {
+	return &LinkFilter{deny: deny, allow: allow, unlisted: unlisted, maxLinks: maxLinks, floodAction: flood}
+}
+
+func (f *LinkFilter) Name() string { return NameLinks }
+
+func (f *LinkFilter) Check(in Input) []Hit {
+	locs := linkRe.FindAllStringIndex(in.Text, -1)
+	if len(locs) == 0 {
+		return nil
+	}
+
+	var hits []Hit
+	for i, loc := range locs {
+		host := linkHost(in.Text[loc[0]:loc[1]])
+		span := &Span{Start: loc[0], End: loc[1], Repl: linkMask}
+
+		if r := f.denied(host); r != nil {
+			hits = append(hits, Hit{Filter: NameLinks, Action: r.Action, Reason: "link to a forbidden site",
+				Detail: fmt.Sprintf("%s matches %q", host, r.Pattern), Span: span})
+			continue
+		}
+		if len(f.allow) > 0 && !f.allowed(host) {
+			hits = append(hits, Hit{Filter: NameLinks, Action: f.unlisted, Reason: "link to a site that is not allowed",
+				Detail: host + " is not in the allow list", Span: span})
+			continue
+		}
+		if f.maxLinks > 0 && i >= f.maxLinks {
+			hits = append(hits, Hit{Filter: NameLinks, Action: f.floodAction, Reason: "too many links",
+				Detail: fmt.Sprintf("%d links, limit %d", len(locs), f.maxLinks), Span: span})
+		}
+	}
+	return hits
+}
+
+func (f *LinkFilter) denied(host string) *entity.FilterRule {
+	for _, r := range f.deny {
+		if domainMatch(host, r.Pattern) {
+			return r
+		}
+	}
+	return nil
+}
+
+func (f *LinkFilter) allowed(host string) bool {
+	for _, d := range f.allow {
+		if domainMatch(host, d) {
+			return true
+		}
+	}
+	return false
+}
+
+// linkHost достаёт хост из ссылки без схемы, www., порта и пути
+func linkHost(link string) string {
+	s := strings.ToLower(link)
+	if i := strings.Index(s, "://"); i >= 0 {
+		s = s[i+3:]
+	}
+	if i := strings.IndexAny(s, "/?#"); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.LastIndex(s, "@"); i >= 0 {
+		s = s[i+1:] // user:pass@host
+	}
+	if i := strings.Index(s, ":"); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(s, "www."), ".")
+}
+
+// domainMatch — host совпадает с доменом или является его поддоменом
+func domainMatch(host, domain string) bool {
+	domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "www.")
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// DuplicateFilter ловит повтор недавнего текста того же автора
+type DuplicateFilter struct {
+	action entity.FilterAction
+}
+
+func NewDuplicateFilter(action entity.FilterAction) *DuplicateFilter {
+	return &DuplicateFilter{action: action}
+}
+
+func (f *DuplicateFilter) Name() string { return NameDuplicate }
+
+func (f *DuplicateFilter) Check(in Input) []Hit {
+	text := normalizeText(in.Text)
+	for _, prev := range in.Recent {
+		if normalizeText(prev) == text {
+			return []Hit{{Filter: NameDuplicate, Action: f.action, Reason: "the same message was sent moments ago"}}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package filter
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// token — слово исходного текста: байтовые границы и нормализованные формы —
+// с похожими буквами, сведёнными к кириллице и к латинице
+type token struct {
+	start, end int
+	forms      []string
+}
+
+// leet — цифры и символы, которыми заменяют буквы
+var leet = map[rune]rune{'0': 'o', '1': 'i', '3': 'e', '4': 'a', '@': 'a', '$': 's'}
+
+// toCyrillic — латинские буквы, похожие на кириллические
+var toCyrillic = map[rune]rune{
+	'a': 'а', 'b': 'в', 'c': 'с', 'e': 'е', 'h': 'н', 'k': 'к', 'm': 'м',
+	'o': 'о', 'p': 'р', 't': 'т', 'x': 'х', 'y': 'у',
+}
+
+// toLatin — кириллические буквы, похожие на латинские
+var toLatin = map[rune]rune{
+	'а': 'a', 'в': 'b', 'с': 'c', 'е': 'e', 'н': 'h', 'к': 'k', 'м': 'm',
+	'о': 'o', 'р': 'p', 'т': 't', 'х': 'x', 'у': 'y', 'і': 'i',
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '@' || r == '$'
+}
+
+// tokenize разбивает текст на слова
+func tokenize(text string) []token {
+	var (
+		tokens []token
+		start  = -1
+	)
+	add := func(end int) {
+		tokens = append(tokens, token{start: start, end: end, forms: normalizeWord(text[start:end])})
+	}
+	for i, r := range text {
+		if isWordRune(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			add(i)
+			start = -1
+		}
+	}
+	if start >= 0 {
+		add(len(text))
+	}
+	return tokens
+}
+
+// normalizeWord сводит варианты написания к одному: регистр, ё, leet-замены,
+// растянутые буквы («дууурак» → «дурак»). Похожие буквы сводятся и к кириллице, и к латинице:
+// «xyй» и «idiоt» с буквами другого алфавита ловятся той формой, где алфавит один.
+// Растянутой считается буква, повторённая три раза и больше: двойные буквы бывают в самих словах
+// («ass», «class»), поэтому не схлопываются. Растянутая буква даёт две формы — с одной и с двумя
+// буквами, чтобы «assss» ловилось шаблоном «ass», а «дууурак» — шаблоном «дурак».
+func normalizeWord(w string) []string {
+	rs := []rune(strings.ToLower(w))
+
+	hasLetter := false
+	for _, r := range rs {
+		if unicode.IsLetter(r) {
+			hasLetter = true
+			break
+		}
+	}
+	if !hasLetter {
+		return []string{string(rs)} // числа не трогаем
+	}
+
+	var forms []string
+	add := func(f string) {
+		for _, have := range forms {
+			if have == f {
+				return
+			}
+		}
+		forms = append(forms, f)
+	}
+	for _, script := range []map[rune]rune{toCyrillic, toLatin} {
+		mapped := toScript(rs, script)
+		add(foldStretched(mapped, 1))
+		add(foldStretched(mapped, 2))
+	}
+	return forms
+}
+
+// toScript применяет leet-замены, ё → е и сводит похожие буквы к одному алфавиту
+func toScript(rs []rune, script map[rune]rune) []rune {
+	out := make([]rune, len(rs))
+	for i, r := range rs {
+		if l, ok := leet[r]; ok {
+			r = l
+		}
+		if r == 'ё' {
+			r = 'е'
+		}
+		if m, ok := script[r]; ok {
+			r = m
+		}
+		out[i] = r
+	}
+	return out
+}
+
+// stretchedRun — с такой длины повтор буквы считается растягиванием
+const stretchedRun = 3
+
+// foldStretched заменяет каждый повтор буквы длиной от stretchedRun на keep букв
+func foldStretched(rs []rune, keep int) string {
+	out := make([]rune, 0, len(rs))
+	for i := 0; i < len(rs); {
+		j := i
+		for j < len(rs) && rs[j] == rs[i] {
+			j++
+		}
+		n := j - i
+		if n >= stretchedRun {
+			n = keep
+		}
+		for k := 0; k < n; k++ {
+			out = append(out, rs[i])
+		}
+		i = j
+	}
+	return string(out)
+}
+
+// normalizePattern приводит запрещённое слово к той форме, с которой будут сравниваться слова текста.
+// Двойные буквы шаблона сохраняются: «ass» не должно ловить «as».
+func normalizePattern(p string) string {
+	script := toLatin
+	for _, r := range p {
+		if unicode.Is(unicode.Cyrillic, r) {
+			script = toCyrillic
+			break
+		}
+	}
+	return stem(foldStretched(toScript([]rune(strings.ToLower(p)), script), 1))
+}
+
+// endings — окончания, которые отрезаются перед сравнением; длинные раньше коротких
+var endings = []string{
+	// русские
+	"иями", "ями", "ами", "ого", "его", "ому", "ему", "ыми", "ими", "ых", "их",
+	"ов", "ев", "ей", "ий", "ый", "ой", "ая", "яя", "ое", "ее", "ые", "ие",
+	"ам", "ям", "ах", "ях", "ом", "ем", "ою", "ею",
+	"а", "я", "о", "е", "ы", "и", "у", "ю", "ь", "й",
+	// английские
+	"ing", "ers", "er", "ed", "es", "s",
+}
+
+// minStem — короче основа не обрезается; совпадение по префиксу разрешено только с основы такой длины
+const minStem = 4
+
+// stem отрезает одно окончание, если после этого остаётся хотя бы три буквы
+func stem(w string) string {
+	for _, e := range endings {
+		if strings.HasSuffix(w, e) && utf8.RuneCountInString(w)-utf8.RuneCountInString(e) >= 3 {
+			return strings.TrimSuffix(w, e)
+		}
+	}
+	return w
+}
+
+// matchStem — слово с основой token совпадает с запрещённой основой pattern.
+// Длинная основа сравнивается по префиксу («дурак» ловит «дураками»), короткая — целиком,
+// чтобы «сука» не ловила «сукно».
+func matchStem(pattern, token string) bool {
+	if utf8.RuneCountInString(pattern) >= minStem {
+		return strings.HasPrefix(token, pattern)
+	}
+	return token == pattern
+}
+
+// matchAny — хотя бы одна из форм слова совпадает с запрещённой основой
+func matchAny(pattern string, stems []string) bool {
+	for _, s := range stems {
+		if matchStem(pattern, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeText — для сравнения целых текстов: без учёта регистра, пробелов и знаков препинания
+func normalizeText(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,54 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// FileMailer складывает письма в каталог файлами .eml — их открывает любой почтовый клиент.
+// Для разработки и тестов: настоящей отправки нет.
+type FileMailer struct {
+	dir  string
+	from string
+	seq  atomic.Int64
+}
+
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("FileMailer: %w", err)
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+func (f *FileMailer) Send(_ context.Context, m *Message) error {
+	const op = "FileMailer.Send"
+
+	now := time.Now()
+	body, err := Compose(f.from, m, now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	to, _ := mail.ParseAddress(m.To) // Compose уже проверил адрес
+	name := fmt.Sprintf("%s-%d-%s.eml", now.UTC().Format("20060102T150405"), f.seq.Add(1), safeName(to.Address))
+	if err := os.WriteFile(filepath.Join(f.dir, name), body, 0o644); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// safeName оставляет от адреса только то, что безопасно в имени файла
+func safeName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '@', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,112 @@
+// Package mailer — отправка писем. Письмо собирается один раз (Compose) и уходит через Mailer:
+// по SMTP или в файлы на диске, чтобы смотреть письма при разработке без почтового сервера.
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Message — письмо одному адресату. HTML необязателен; Headers — дополнительные заголовки
+// (например, List-Unsubscribe), значения должны быть в ASCII.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	Headers map[string]string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, m *Message) error
+}
+
+// Compose собирает письмо в формате RFC 5322: текст и HTML — альтернативные части multipart/alternative
+func Compose(from string, m *Message, now time.Time) ([]byte, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("mailer: from %q: %w", from, err)
+	}
+	rcpt, err := mail.ParseAddress(m.To)
+	if err != nil {
+		return nil, fmt.Errorf("mailer: to %q: %w", m.To, err)
+	}
+
+	var buf bytes.Buffer
+	header := func(k, v string) { fmt.Fprintf(&buf, "%s: %s\r\n", k, v) }
+	header("From", sender.String())
+	header("To", rcpt.String())
+	header("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", messageID(sender.Address))
+	header("MIME-Version", "1.0")
+	keys := make([]string, 0, len(m.Headers))
+	for k := range m.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		header(textproto.CanonicalMIMEHeaderKey(k), m.Headers[k])
+	}
+
+	if m.HTML == "" {
+		header("Content-Type", `text/plain; charset="utf-8"`)
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQP(&buf, m.Text); err != nil {
+			return nil, fmt.Errorf("mailer: text: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	w := multipart.NewWriter(&buf)
+	header("Content-Type", `multipart/alternative; boundary="`+w.Boundary()+`"`)
+	buf.WriteString("\r\n")
+	// порядок важен: клиент показывает последнюю понятную ему часть
+	for _, part := range []struct{ typ, body string }{{"text/plain", m.Text}, {"text/html", m.HTML}} {
+		pw, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.typ + `; charset="utf-8"`},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("mailer: %s part: %w", part.typ, err)
+		}
+		if err := writeQP(pw, part.body); err != nil {
+			return nil, fmt.Errorf("mailer: %s part: %w", part.typ, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("mailer: close multipart: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func writeQP(w io.Writer, s string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(s, "\n", "\r\n"))); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if _, d, ok := strings.Cut(from, "@"); ok && d != "" {
+		domain = d
+	}
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
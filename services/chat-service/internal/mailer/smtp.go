@@ -0,0 +1,82 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+)
+
+// SMTPMailer отправляет письма через SMTP-сервер; STARTTLS включается, если сервер его поддерживает.
+// Без пользователя письма уходят без авторизации (локальный relay).
+type SMTPMailer struct {
+	addr string
+	host string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTPMailer(host, port, user, password, from string) *SMTPMailer {
+	m := &SMTPMailer{addr: net.JoinHostPort(host, port), host: host, from: from}
+	if user != "" {
+		m.auth = smtp.PlainAuth("", user, password, host)
+	}
+	return m
+}
+
+func (s *SMTPMailer) Send(ctx context.Context, m *Message) error {
+	const op = "SMTPMailer.Send"
+
+	body, err := Compose(s.from, m, time.Now())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	from, _ := mail.ParseAddress(s.from) // Compose уже проверил оба адреса
+	to, _ := mail.ParseAddress(m.To)
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("%s: dial: %w", op, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("%s: hello: %w", op, err)
+	}
+	defer func() { _ = c.Close() }()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return fmt.Errorf("%s: starttls: %w", op, err)
+		}
+	}
+	if s.auth != nil {
+		if err := c.Auth(s.auth); err != nil {
+			return fmt.Errorf("%s: auth: %w", op, err)
+		}
+	}
+	if err := c.Mail(from.Address); err != nil {
+		return fmt.Errorf("%s: mail from: %w", op, err)
+	}
+	if err := c.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("%s: rcpt to: %w", op, err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("%s: data: %w", op, err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("%s: write: %w", op, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("%s: data end: %w", op, err)
+	}
+	return c.Quit()
+}
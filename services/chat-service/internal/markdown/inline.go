@@ -0,0 +1,415 @@
+package markdown
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	maxInlineDepth = 16
+	maxLabel       = 1000 // длиннее текст ссылки не ищется — защита от квадратичного перебора
+	maxURL         = 2048
+)
+
+// linkRel — ссылки из пользовательского текста не передают вес и доступ к окну
+const linkRel = `rel="nofollow ugc noopener noreferrer"`
+
+// inline рисует строчную разметку одного абзаца
+type inline struct {
+	b       *strings.Builder
+	noLinks bool // внутри текста ссылки другие ссылки не нужны
+	depth   int
+}
+
+func renderInline(b *strings.Builder, s string) {
+	(&inline{b: b}).render(s)
+}
+
+func (in *inline) text(s string) {
+	in.b.WriteString(html.EscapeString(s))
+}
+
+func (in *inline) sub(s string, noLinks bool) {
+	(&inline{b: in.b, noLinks: in.noLinks || noLinks, depth: in.depth + 1}).render(s)
+}
+
+func (in *inline) render(s string) {
+	if in.depth > maxInlineDepth {
+		in.text(s)
+		return
+	}
+	// missing запоминает, каких закрывающих разделителей дальше уже нет, чтобы не искать их снова
+	missing := make(map[int]bool)
+
+	for i := 0; i < len(s); {
+		switch c := s[i]; c {
+		case '\\':
+			if i+1 < len(s) && isPunct(s[i+1]) {
+				in.text(s[i+1 : i+2])
+				i += 2
+				continue
+			}
+		case '`':
+			if n, ok := in.codeSpan(s, i, missing); ok {
+				i = n
+				continue
+			}
+			j := runEnd(s, i)
+			in.text(s[i:j])
+			i = j
+			continue
+		case '*', '_':
+			if n, ok := in.emphasis(s, i, missing); ok {
+				i = n
+				continue
+			}
+			j := runEnd(s, i)
+			in.text(s[i:j])
+			i = j
+			continue
+		case '[':
+			if n, ok := in.link(s, i); ok {
+				i = n
+				continue
+			}
+		case '<':
+			if n, ok := in.autolink(s, i); ok {
+				i = n
+				continue
+			}
+		case 'h', 'H':
+			if n, ok := in.bareURL(s, i); ok {
+				i = n
+				continue
+			}
+		case '\n':
+			in.b.WriteString("<br>\n")
+			i++
+			continue
+		}
+
+		// обычный текст до следующего символа, который может начинать разметку
+		j := i + 1
+		for j < len(s) && !strings.ContainsRune("\\`*_[<hH\n", rune(s[j])) {
+			j++
+		}
+		in.text(s[i:j])
+		i = j
+	}
+}
+
+// runEnd — конец серии одинаковых символов, начинающейся с i
+func runEnd(s string, i int) int {
+	j := i
+	for j < len(s) && s[j] == s[i] {
+		j++
+	}
+	return j
+}
+
+// codeSpan — `код` между сериями обратных кавычек одинаковой длины
+func (in *inline) codeSpan(s string, i int, missing map[int]bool) (int, bool) {
+	open := runEnd(s, i)
+	n := open - i
+	if missing[n] {
+		return 0, false
+	}
+	for j := open; j < len(s); {
+		k := strings.IndexByte(s[j:], '`')
+		if k < 0 {
+			break
+		}
+		j += k
+		e := runEnd(s, j)
+		if e-j != n {
+			j = e
+			continue
+		}
+
+		code := strings.ReplaceAll(s[open:j], "\n", " ")
+		if len(code) >= 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+			code = code[1 : len(code)-1]
+		}
+		in.b.WriteString("<code>")
+		in.text(code)
+		in.b.WriteString("</code>")
+		return e, true
+	}
+	missing[n] = true
+	return 0, false
+}
+
+// emphasis — **жирный** / __жирный__ и *курсив* / _курсив_.
+// Подчёркивание внутри слова разметкой не считается: snake_case остаётся как есть.
+func (in *inline) emphasis(s string, i int, missing map[int]bool) (int, bool) {
+	c := s[i]
+	n := runEnd(s, i) - i
+	after, _ := utf8.DecodeRuneInString(s[i+n:])
+	if i+n >= len(s) || unicode.IsSpace(after) {
+		return 0, false
+	}
+	if c == '_' {
+		if before, _ := utf8.DecodeLastRuneInString(s[:i]); i > 0 && isWord(before) {
+			return 0, false
+		}
+	}
+
+	for _, k := range []int{2, 1} {
+		if n < k {
+			continue
+		}
+		key := int(c)*10 + k
+		if missing[key] {
+			continue
+		}
+		j := findCloser(s, i+k, c, k)
+		if j < 0 {
+			missing[key] = true
+			continue
+		}
+		tag := "em"
+		if k == 2 {
+			tag = "strong"
+		}
+		in.b.WriteString("<" + tag + ">")
+		in.sub(s[i+k:j], false)
+		in.b.WriteString("</" + tag + ">")
+		return j + k, true
+	}
+	return 0, false
+}
+
+// findCloser ищет закрывающий разделитель из k символов c после from, пропуская `код` и экранирование.
+// Для k=2 подходит серия из двух и более символов (берутся последние два),
+// для k=1 — одиночный символ или серия из трёх и более, чтобы *a **b** c* закрывался правильно.
+func findCloser(s string, from int, c byte, k int) int {
+	for j := from; j < len(s); {
+		switch s[j] {
+		case '\\':
+			j += 2
+			continue
+		case '`':
+			e := runEnd(s, j)
+			if end := strings.Index(s[e:], s[j:e]); end >= 0 {
+				j = e + end + (e - j)
+			} else {
+				j = e
+			}
+			continue
+		case c:
+		default:
+			j++
+			continue
+		}
+
+		e := runEnd(s, j)
+		m := e - j
+		before, _ := utf8.DecodeLastRuneInString(s[:j])
+		next, _ := utf8.DecodeRuneInString(s[e:])
+		flanking := j > from && !unicode.IsSpace(before)
+		if c == '_' && e < len(s) && isWord(next) {
+			flanking = false
+		}
+		if flanking {
+			switch {
+			case k == 2 && m >= 2:
+				return e - 2
+			case k == 1 && (m == 1 || m >= 3):
+				return e - 1
+			}
+		}
+		j = e
+	}
+	return -1
+}
+
+// link — [текст](адрес)
+func (in *inline) link(s string, i int) (int, bool) {
+	if in.noLinks {
+		return 0, false
+	}
+	depth, close := 0, -1
+	for j := i; j < len(s) && j-i <= maxLabel; j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+		if depth == 0 {
+			close = j
+			break
+		}
+	}
+	if close < 0 || close+1 >= len(s) || s[close+1] != '(' {
+		return 0, false
+	}
+
+	// адрес — до парной закрывающей скобки, без пробелов
+	start, parens, end := close+2, 0, -1
+	for j := start; j < len(s) && j-start <= maxURL; j++ {
+		switch s[j] {
+		case '\\':
+			j++
+			continue
+		case '(':
+			parens++
+		case ')':
+			if parens == 0 {
+				end = j
+			}
+			parens--
+		case ' ', '\t', '\n', '<', '>':
+			return 0, false
+		}
+		if end >= 0 {
+			break
+		}
+	}
+	if end < 0 {
+		return 0, false
+	}
+
+	label := s[i+1 : close]
+	href, ok := safeURL(unescape(s[start:end]))
+	if !ok {
+		// опасный или кривой адрес — остаётся только текст ссылки
+		in.sub(label, true)
+		return end + 1, true
+	}
+	if strings.TrimSpace(label) == "" {
+		label = href
+	}
+	in.anchor(href, func() { in.sub(label, true) })
+	return end + 1, true
+}
+
+// autolink — <https://...>
+func (in *inline) autolink(s string, i int) (int, bool) {
+	if in.noLinks {
+		return 0, false
+	}
+	end := strings.IndexByte(s[i:min(len(s), i+maxURL+2)], '>')
+	if end < 0 {
+		return 0, false
+	}
+	url := s[i+1 : i+end]
+	if !hasScheme(url) {
+		return 0, false
+	}
+	href, ok := safeURL(url)
+	if !ok {
+		return 0, false
+	}
+	in.anchor(href, func() { in.text(url) })
+	return i + end + 1, true
+}
+
+// bareURL — адрес http(s):// прямо в тексте
+func (in *inline) bareURL(s string, i int) (int, bool) {
+	if in.noLinks {
+		return 0, false
+	}
+	if before, _ := utf8.DecodeLastRuneInString(s[:i]); i > 0 && isWord(before) {
+		return 0, false
+	}
+	rest := s[i:min(len(s), i+maxURL+1)]
+	if !hasPrefixFold(rest, "http://") && !hasPrefixFold(rest, "https://") {
+		return 0, false
+	}
+
+	end := strings.IndexFunc(rest, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '<' || r == '>' || r == '"' || r == '`'
+	})
+	if end < 0 {
+		end = len(rest)
+	}
+	url := rest[:end]
+	// знаки препинания после адреса к нему не относятся, как и непарная закрывающая скобка
+	for url != "" {
+		last := url[len(url)-1]
+		if strings.IndexByte(".,:;!?'*_~", last) >= 0 ||
+			last == ')' && strings.Count(url, ")") > strings.Count(url, "(") {
+			url = url[:len(url)-1]
+			continue
+		}
+		break
+	}
+	if strings.Index(url, "://")+3 >= len(url) || len(url) > maxURL {
+		return 0, false
+	}
+
+	href, ok := safeURL(url)
+	if !ok {
+		return 0, false
+	}
+	in.anchor(href, func() { in.text(url) })
+	return i + len(url), true
+}
+
+func (in *inline) anchor(href string, label func()) {
+	in.b.WriteString(`<a href="` + html.EscapeString(href) + `" ` + linkRel + `>`)
+	label()
+	in.b.WriteString("</a>")
+}
+
+// safeURL пропускает http, https, mailto и относительные адреса.
+// Пробелы и управляющие символы запрещены: браузеры вырезают их из схемы ("java\tscript:").
+// Адрес без схемы, начинающийся с двух косых ("//host", браузер читает "\\" как "//"), ведёт
+// на чужой сайт мимо фильтра ссылок, поэтому относительным не считается.
+func safeURL(u string) (string, bool) {
+	if u == "" || len(u) > maxURL {
+		return "", false
+	}
+	if len(u) >= 2 && (u[0] == '/' || u[0] == '\\') && (u[1] == '/' || u[1] == '\\') {
+		return "", false
+	}
+	for _, r := range u {
+		if r < 0x20 || r == 0x7f || unicode.IsSpace(r) {
+			return "", false
+		}
+	}
+	if i := strings.IndexAny(u, ":/?#"); i >= 0 && u[i] == ':' {
+		switch strings.ToLower(u[:i]) {
+		case "http", "https", "mailto":
+		default:
+			return "", false
+		}
+	}
+	return u, true
+}
+
+func hasScheme(u string) bool {
+	return hasPrefixFold(u, "http://") || hasPrefixFold(u, "https://") || hasPrefixFold(u, "mailto:")
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// unescape убирает обратную косую черту перед знаками препинания
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && isPunct(s[i+1]) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func isPunct(c byte) bool {
+	return c < 0x80 && unicode.IsPunct(rune(c)) || strings.IndexByte("$+<=>^`|~", c) >= 0
+}
+
+func isWord(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
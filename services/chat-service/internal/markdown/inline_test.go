@@ -0,0 +1,34 @@
+package markdown
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRender_LinkTargets(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		link bool
+	}{
+		{"https", "[x](https://example.com)", true},
+		{"mailto", "[x](mailto:a@example.com)", true},
+		{"relative path", "[x](/topics/5)", true},
+		{"relative fragment", "[x](#m10)", true},
+		{"javascript", "[x](javascript:alert(1))", false},
+		{"protocol-relative", "[x](//evil.example)", false},
+		{"protocol-relative autolink", "<//evil.example>", false},
+		{"backslashes", `[x](\\\\evil.example)`, false}, // после снятия экранирования — \\evil.example
+		{"mixed slashes", `[x](/\evil.example)`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := Render(tt.src)
+			require.Equal(t, tt.link, HasLinks(tt.src), out)
+			if !tt.link {
+				require.NotContains(t, out, "<a ")
+				require.NotContains(t, out, "evil.example\"")
+			}
+		})
+	}
+}
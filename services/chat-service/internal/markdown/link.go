@@ -0,0 +1,12 @@
+package markdown
+
+import "strings"
+
+// HasLinks сообщает, что в отрисованном тексте будет ссылка: Markdown-ссылка, <автоссылка>
+// или голый http(s)-адрес. Ссылки внутри кода ссылками не считаются, как и в Render.
+func HasLinks(src string) bool {
+	if !strings.Contains(src, ":") && !strings.Contains(src, "](") {
+		return false
+	}
+	return strings.Contains(Render(src), "<a ")
+}
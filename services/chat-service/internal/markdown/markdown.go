@@ -0,0 +1,402 @@
+// Package markdown превращает текст сообщения в HTML по безопасному подмножеству Markdown:
+// блоки кода (``` с языком, ~~~ и отступ в 4 пробела), `код`, ссылки, списки, цитаты, **жирный** и *курсив*.
+// Сырой HTML не пропускается никогда: весь текст экранируется, а теги пишет только сам рендерер,
+// поэтому результат можно вставлять в страницу как есть. Ссылки — только http, https, mailto и относительные.
+// Перевод строки внутри абзаца сохраняется как <br>: так пишут в чатах.
+package markdown
+
+import (
+	"html"
+	"strconv"
+	"strings"
+)
+
+// maxDepth — глубже вложенные цитаты и списки выводятся обычным текстом
+const maxDepth = 8
+
+// Render возвращает безопасный HTML для Markdown-текста src
+func Render(src string) string {
+	if strings.TrimSpace(src) == "" {
+		return ""
+	}
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\r", "\n")
+	src = strings.ReplaceAll(src, "\x00", "�")
+
+	var b strings.Builder
+	renderBlocks(&b, strings.Split(src, "\n"), 0, false)
+	return b.String()
+}
+
+// renderBlocks разбирает строки на блоки. tight — абзацы без <p> (пункты «плотного» списка).
+func renderBlocks(b *strings.Builder, lines []string, depth int, tight bool) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		if isBlank(line) {
+			i++
+			continue
+		}
+		if fence, ind, lang, ok := fenceOpen(line); ok {
+			i = codeFence(b, lines, i, fence, ind, lang)
+			continue
+		}
+		if depth < maxDepth {
+			if _, ok := quoteLine(line); ok {
+				i = quote(b, lines, i, depth)
+				continue
+			}
+			if m, _, ok := parseMarker(line); ok {
+				i = list(b, lines, i, m, depth)
+				continue
+			}
+		}
+		if cols, _ := indent(line); cols >= 4 {
+			i = indentedCode(b, lines, i)
+			continue
+		}
+		i = paragraph(b, lines, i, depth, tight)
+	}
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// indent возвращает ширину отступа в колонках (таб — до следующей позиции, кратной 4) и его длину в байтах
+func indent(line string) (cols, n int) {
+	for ; n < len(line); n++ {
+		switch line[n] {
+		case ' ':
+			cols++
+		case '\t':
+			cols += 4 - cols%4
+		default:
+			return cols, n
+		}
+	}
+	return cols, n
+}
+
+// stripIndent убирает до cols колонок отступа
+func stripIndent(line string, cols int) string {
+	c := 0
+	for i := 0; i < len(line); i++ {
+		if c >= cols {
+			return line[i:]
+		}
+		switch line[i] {
+		case ' ':
+			c++
+		case '\t':
+			w := 4 - c%4
+			if c+w > cols {
+				// таб шире, чем нужно убрать: остаток становится пробелами
+				return strings.Repeat(" ", c+w-cols) + line[i+1:]
+			}
+			c += w
+		default:
+			return line[i:]
+		}
+	}
+	return ""
+}
+
+// fenceOpen распознаёт начало блока кода: ``` или ~~~ (не меньше трёх) и необязательный язык
+func fenceOpen(line string) (fence string, ind int, lang string, ok bool) {
+	cols, n := indent(line)
+	if cols > 3 {
+		return "", 0, "", false
+	}
+	rest := line[n:]
+	if len(rest) < 3 || (rest[0] != '`' && rest[0] != '~') {
+		return "", 0, "", false
+	}
+	k := 0
+	for k < len(rest) && rest[k] == rest[0] {
+		k++
+	}
+	if k < 3 {
+		return "", 0, "", false
+	}
+	info := strings.TrimSpace(rest[k:])
+	if rest[0] == '`' && strings.Contains(info, "`") {
+		return "", 0, "", false // это `код` в строке, а не блок
+	}
+	return rest[:k], cols, codeLang(info), true
+}
+
+// fenceClose — строка закрывает блок, открытый fence
+func fenceClose(line, fence string) bool {
+	cols, n := indent(line)
+	if cols > 3 {
+		return false
+	}
+	rest := strings.TrimRight(line[n:], " \t")
+	if len(rest) < len(fence) {
+		return false
+	}
+	for i := 0; i < len(rest); i++ {
+		if rest[i] != fence[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// codeLang оставляет от подсказки языка только безопасные для имени класса символы
+func codeLang(info string) string {
+	if i := strings.IndexAny(info, " \t{"); i >= 0 {
+		info = info[:i]
+	}
+	var b strings.Builder
+	for _, r := range strings.ToLower(info) {
+		if b.Len() >= 32 {
+			break
+		}
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || strings.ContainsRune("_+#.-", r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func codeFence(b *strings.Builder, lines []string, i int, fence string, ind int, lang string) int {
+	var code []string
+	for i++; i < len(lines); i++ {
+		if fenceClose(lines[i], fence) {
+			i++
+			break
+		}
+		code = append(code, stripIndent(lines[i], ind))
+	}
+	writeCode(b, code, lang)
+	return i
+}
+
+func indentedCode(b *strings.Builder, lines []string, i int) int {
+	var code []string
+	for ; i < len(lines); i++ {
+		if cols, _ := indent(lines[i]); cols < 4 && !isBlank(lines[i]) {
+			break
+		}
+		code = append(code, stripIndent(lines[i], 4))
+	}
+	for len(code) > 0 && isBlank(code[len(code)-1]) {
+		code = code[:len(code)-1]
+	}
+	writeCode(b, code, "")
+	return i
+}
+
+func writeCode(b *strings.Builder, code []string, lang string) {
+	b.WriteString("<pre><code")
+	if lang != "" {
+		b.WriteString(` class="language-` + html.EscapeString(lang) + `"`)
+	}
+	b.WriteString(">")
+	if len(code) > 0 {
+		b.WriteString(html.EscapeString(strings.Join(code, "\n")))
+		b.WriteString("\n")
+	}
+	b.WriteString("</code></pre>\n")
+}
+
+// quoteLine — строка цитаты: "> текст" без маркера
+func quoteLine(line string) (string, bool) {
+	cols, n := indent(line)
+	if cols > 3 || n >= len(line) || line[n] != '>' {
+		return "", false
+	}
+	rest := line[n+1:]
+	if rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+		rest = rest[1:]
+	}
+	return rest, true
+}
+
+// quote собирает подряд идущие строки с ">". Строка без ">" цитату заканчивает:
+// в чате ответ обычно пишут сразу под цитатой.
+func quote(b *strings.Builder, lines []string, i, depth int) int {
+	var inner []string
+	for ; i < len(lines); i++ {
+		rest, ok := quoteLine(lines[i])
+		if !ok {
+			break
+		}
+		inner = append(inner, rest)
+	}
+	b.WriteString("<blockquote>\n")
+	renderBlocks(b, inner, depth+1, false)
+	b.WriteString("</blockquote>\n")
+	return i
+}
+
+// listMarker — маркер пункта списка
+type listMarker struct {
+	ordered bool
+	delim   byte // '-', '*', '+' или для нумерованных '.', ')'
+	start   int
+	content int // колонка, с которой начинается текст пункта
+}
+
+func (m listMarker) sameList(o listMarker) bool {
+	return m.ordered == o.ordered && m.delim == o.delim
+}
+
+// parseMarker распознаёт "- текст", "* текст", "+ текст", "1. текст" и "1) текст"
+func parseMarker(line string) (m listMarker, text string, ok bool) {
+	cols, n := indent(line)
+	if cols > 3 || n >= len(line) || isBreak(line[n:]) {
+		return m, "", false
+	}
+	rest := line[n:]
+	w := 0
+	switch c := rest[0]; {
+	case c == '-' || c == '*' || c == '+':
+		m.delim, w = c, 1
+	case c >= '0' && c <= '9':
+		for w < len(rest) && w < 9 && rest[w] >= '0' && rest[w] <= '9' {
+			w++
+		}
+		if w >= len(rest) || (rest[w] != '.' && rest[w] != ')') {
+			return m, "", false
+		}
+		m.ordered = true
+		m.start, _ = strconv.Atoi(rest[:w])
+		m.delim = rest[w]
+		w++
+	default:
+		return m, "", false
+	}
+
+	after := rest[w:]
+	if after != "" && after[0] != ' ' && after[0] != '\t' {
+		return m, "", false
+	}
+	sp, _ := indent(after)
+	switch {
+	case isBlank(after):
+		sp, after = 1, ""
+	case sp > 4:
+		sp = 1 // дальше код с отступом внутри пункта
+	}
+	m.content = cols + w + sp
+	return m, stripIndent(after, sp), true
+}
+
+// isBreak — строка вида "* * *" или "- - -": не список, а разделитель, выводится текстом
+func isBreak(s string) bool {
+	s = strings.TrimSpace(s)
+	if s == "" || !strings.ContainsAny(s[:1], "-*_") {
+		return false
+	}
+	count := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case s[0]:
+			count++
+		case ' ', '\t':
+		default:
+			return false
+		}
+	}
+	return count >= 3
+}
+
+// interrupts — строка начинает новый блок прямо посреди абзаца
+func interrupts(line string, depth int) bool {
+	if _, _, _, ok := fenceOpen(line); ok {
+		return true
+	}
+	if depth >= maxDepth {
+		return false
+	}
+	if _, ok := quoteLine(line); ok {
+		return true
+	}
+	m, text, ok := parseMarker(line)
+	return ok && !isBlank(text) && (!m.ordered || m.start == 1)
+}
+
+func list(b *strings.Builder, lines []string, i int, first listMarker, depth int) int {
+	var (
+		items [][]string
+		loose bool
+	)
+	for i < len(lines) {
+		m, text, ok := parseMarker(lines[i])
+		if !ok || !m.sameList(first) {
+			break
+		}
+		body := []string{text}
+		for i++; i < len(lines); i++ {
+			if isBlank(lines[i]) {
+				// пустые строки остаются в пункте, только если за ними идёт строка с его отступом
+				j := nextNonBlank(lines, i)
+				if j == len(lines) {
+					break
+				}
+				if cols, _ := indent(lines[j]); cols < m.content {
+					break
+				}
+				for ; i < j; i++ {
+					body = append(body, "")
+				}
+				loose = true
+			}
+			if cols, _ := indent(lines[i]); cols < m.content {
+				break
+			}
+			body = append(body, stripIndent(lines[i], m.content))
+		}
+		items = append(items, body)
+
+		// пункты через пустую строку — «свободный» список с абзацами
+		if j := nextNonBlank(lines, i); j > i && j < len(lines) {
+			if next, _, ok := parseMarker(lines[j]); ok && next.sameList(first) {
+				loose = true
+				i = j
+			}
+		}
+	}
+
+	tag := "ul"
+	if first.ordered {
+		tag = "ol"
+	}
+	b.WriteString("<" + tag)
+	if first.ordered && first.start != 1 {
+		b.WriteString(` start="` + strconv.Itoa(first.start) + `"`)
+	}
+	b.WriteString(">\n")
+	for _, body := range items {
+		b.WriteString("<li>")
+		renderBlocks(b, body, depth+1, !loose)
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</" + tag + ">\n")
+	return i
+}
+
+func nextNonBlank(lines []string, i int) int {
+	for i < len(lines) && isBlank(lines[i]) {
+		i++
+	}
+	return i
+}
+
+func paragraph(b *strings.Builder, lines []string, i, depth int, tight bool) int {
+	text := []string{strings.TrimSpace(lines[i])}
+	for i++; i < len(lines) && !isBlank(lines[i]) && !interrupts(lines[i], depth); i++ {
+		text = append(text, strings.TrimSpace(lines[i]))
+	}
+
+	if !tight {
+		b.WriteString("<p>")
+	}
+	renderInline(b, strings.Join(text, "\n"))
+	if !tight {
+		b.WriteString("</p>\n")
+	}
+	return i
+}
@@ -0,0 +1,99 @@
+package markdown
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxMentionLength — длиннее username не бывает (users.username VARCHAR(32))
+const maxMentionLength = 32
+
+// Mentions возвращает имена из упоминаний @имя в порядке появления, без повторов; регистр имени
+// не различается, остаётся написание первого упоминания.
+// Упоминания внутри кода и ссылок не считаются: разбирается текст, уже отрисованный Render.
+// Имя — буквы, цифры, '_', '.', '-'; точка и дефис в конце относятся к тексту ("спасибо, @bob.").
+// Адреса почты (bob@example.com) упоминаниями не считаются.
+func Mentions(src string) []string {
+	if !strings.Contains(src, "@") {
+		return nil
+	}
+
+	var (
+		names []string
+		seen  = make(map[string]bool)
+		skip  int // глубина <code> и <a>: их текст не разбирается
+	)
+	out := Render(src)
+	for out != "" {
+		if out[0] == '<' {
+			// весь текст экранирован, так что '<' в результате — всегда тег
+			end := strings.IndexByte(out, '>')
+			if end < 0 {
+				break
+			}
+			switch tag := out[1:end]; {
+			case strings.HasPrefix(tag, "code"), strings.HasPrefix(tag, "a "):
+				skip++
+			case tag == "/code", tag == "/a":
+				skip--
+			}
+			out = out[end+1:]
+			continue
+		}
+
+		end := strings.IndexByte(out, '<')
+		if end < 0 {
+			end = len(out)
+		}
+		if skip == 0 {
+			for _, name := range mentionsInText(html.UnescapeString(out[:end])) {
+				if key := strings.ToLower(name); !seen[key] {
+					seen[key] = true
+					names = append(names, name)
+				}
+			}
+		}
+		out = out[end:]
+	}
+	return names
+}
+
+func mentionsInText(s string) []string {
+	var names []string
+	for i := strings.IndexByte(s, '@'); i >= 0; {
+		if before, _ := utf8.DecodeLastRuneInString(s[:i]); i > 0 && (isWord(before) || strings.ContainsRune("@._-", before)) {
+			i = nextAt(s, i+1)
+			continue
+		}
+		j := i + 1
+		for j < len(s) {
+			r, n := utf8.DecodeRuneInString(s[j:])
+			if !isWord(r) && r != '_' && r != '.' && r != '-' {
+				break
+			}
+			j += n
+		}
+		name := strings.TrimRightFunc(s[i+1:j], func(r rune) bool { return r == '.' || r == '-' })
+		if name != "" && utf8.RuneCountInString(name) <= maxMentionLength && isWord(firstRune(name)) {
+			names = append(names, name)
+		}
+		i = nextAt(s, j)
+	}
+	return names
+}
+
+func nextAt(s string, from int) int {
+	if from >= len(s) {
+		return -1
+	}
+	if k := strings.IndexByte(s[from:], '@'); k >= 0 {
+		return from + k
+	}
+	return -1
+}
+
+func firstRune(s string) rune {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r
+}
@@ -0,0 +1,162 @@
+// Package media — распознавание типа загруженных файлов по содержимому и уменьшение картинок.
+// Тип, присланный клиентом, не учитывается: ему нельзя доверять.
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // регистрирует декодер GIF
+	"image/jpeg"
+	"image/png"
+	"mime"
+	"net/http"
+)
+
+// maxPixels — картинки больше этого не декодируются: маленький файл может развернуться в гигабайты памяти
+const maxPixels = 40_000_000
+
+var ErrTooManyPixels = errors.New("image dimensions are too large")
+
+// Sniff определяет MIME-тип по первым байтам содержимого, без параметров вроде charset
+func Sniff(data []byte) string {
+	mt, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil {
+		return "application/octet-stream"
+	}
+	return mt
+}
+
+// CanThumbnail сообщает, что для картинки этого типа можно сделать миниатюру
+func CanThumbnail(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	}
+	return false
+}
+
+// Image — картинка, закодированная после уменьшения
+type Image struct {
+	Data          []byte
+	ContentType   string
+	Width, Height int
+}
+
+// Dimensions читает размеры картинки из заголовка, не декодируя её целиком
+func Dimensions(data []byte) (width, height int, err error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, fmt.Errorf("media: %w", err)
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+// Thumbnail уменьшает картинку так, чтобы большая сторона была не больше maxSide (меньшие не увеличиваются).
+// Непрозрачные картинки кодируются в JPEG, с прозрачностью — в PNG. У GIF берётся первый кадр.
+func Thumbnail(data []byte, maxSide int) (*Image, error) {
+	src, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	w, h := fit(src.Bounds().Dx(), src.Bounds().Dy(), maxSide)
+	return encode(resize(src, w, h))
+}
+
+// Square вырезает из картинки центральный квадрат и уменьшает его до side×side (меньшие не увеличиваются) — для аватаров
+func Square(data []byte, side int) (*Image, error) {
+	src, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	b := src.Bounds()
+	d := min(b.Dx(), b.Dy())
+	x0, y0 := b.Min.X+(b.Dx()-d)/2, b.Min.Y+(b.Dy()-d)/2
+	crop := image.NewRGBA(image.Rect(0, 0, d, d))
+	draw.Draw(crop, crop.Bounds(), src, image.Pt(x0, y0), draw.Src)
+	side = min(side, d)
+	return encode(resize(crop, side, side))
+}
+
+func decode(data []byte) (image.Image, error) {
+	w, h, err := Dimensions(data)
+	if err != nil {
+		return nil, err
+	}
+	if w <= 0 || h <= 0 || w*h > maxPixels {
+		return nil, ErrTooManyPixels
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("media: %w", err)
+	}
+	return img, nil
+}
+
+// fit вписывает w×h в квадрат side×side с сохранением пропорций
+func fit(w, h, side int) (int, int) {
+	if w <= side && h <= side {
+		return w, h
+	}
+	if w >= h {
+		return side, max(h*side/w, 1)
+	}
+	return max(w*side/h, 1), side
+}
+
+// resize уменьшает картинку усреднением: каждый пиксель результата — среднее накрытого им прямоугольника исходника
+func resize(src image.Image, w, h int) *image.RGBA {
+	b := src.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+	sw, sh := b.Dx(), b.Dy()
+	if sw == w && sh == h {
+		return rgba
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0 := y * sh / h
+		y1 := max((y+1)*sh/h, y0+1)
+		for x := 0; x < w; x++ {
+			x0 := x * sw / w
+			x1 := max((x+1)*sw/w, x0+1)
+			var sum [4]uint64
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[rgba.PixOffset(x0, sy):rgba.PixOffset(x1, sy)]
+				for i := 0; i < len(row); i += 4 {
+					sum[0] += uint64(row[i])
+					sum[1] += uint64(row[i+1])
+					sum[2] += uint64(row[i+2])
+					sum[3] += uint64(row[i+3])
+				}
+			}
+			n := uint64((y1 - y0) * (x1 - x0))
+			d := dst.PixOffset(x, y)
+			for i := range sum {
+				dst.Pix[d+i] = uint8(sum[i] / n)
+			}
+		}
+	}
+	return dst
+}
+
+func encode(img *image.RGBA) (*Image, error) {
+	var buf bytes.Buffer
+	out := &Image{Width: img.Rect.Dx(), Height: img.Rect.Dy()}
+	if img.Opaque() {
+		out.ContentType = "image/jpeg"
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+			return nil, fmt.Errorf("media: %w", err)
+		}
+	} else {
+		out.ContentType = "image/png"
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, fmt.Errorf("media: %w", err)
+		}
+	}
+	out.Data = buf.Bytes()
+	return out, nil
+}
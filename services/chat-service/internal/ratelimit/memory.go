@@ -0,0 +1,45 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore держит вёдра в памяти процесса. Подходит для одного экземпляра сервиса:
+// у нескольких экземпляров лимиты будут раздельными.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]State
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]State)}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, l Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.buckets[key]
+	if !ok {
+		st = Full(l, now)
+	}
+	st, res := Take(st, l, now)
+	s.buckets[key] = st
+	return res, nil
+}
+
+func (s *MemoryStore) Sweep(_ context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int64
+	for key, st := range s.buckets {
+		if st.Updated.Before(before) {
+			delete(s.buckets, key)
+			n++
+		}
+	}
+	return n, nil
+}
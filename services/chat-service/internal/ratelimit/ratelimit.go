@@ -0,0 +1,109 @@
+// Package ratelimit — token bucket для ограничения частоты действий.
+// Ведро вмещает Burst токенов и полностью наполняется за Period; каждое действие забирает один токен.
+// Состояние вёдер хранит Store: в памяти процесса или в общей базе.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit — Burst действий за Period. Нулевой Limit ничего не ограничивает.
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// Enabled — лимит задан
+func (l Limit) Enabled() bool {
+	return l.Burst > 0 && l.Period > 0
+}
+
+// rate — сколько токенов возвращается в секунду
+func (l Limit) rate() float64 {
+	return float64(l.Burst) / l.Period.Seconds()
+}
+
+func (l Limit) String() string {
+	return strconv.Itoa(l.Burst) + "/" + l.Period.String()
+}
+
+// UnmarshalText разбирает лимит вида "10/1m"; "0" или пустая строка — без лимита
+func (l *Limit) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if s == "" || s == "0" {
+		*l = Limit{}
+		return nil
+	}
+	burst, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return fmt.Errorf("rate limit %q: want burst/period, e.g. 10/1m", s)
+	}
+	b, err := strconv.Atoi(burst)
+	if err != nil || b < 0 {
+		return fmt.Errorf("rate limit %q: invalid burst", s)
+	}
+	p, err := time.ParseDuration(period)
+	if err != nil || p <= 0 {
+		return fmt.Errorf("rate limit %q: invalid period", s)
+	}
+	*l = Limit{Burst: b, Period: p}
+	return nil
+}
+
+// State — состояние ведра на момент Updated
+type State struct {
+	Tokens  float64
+	Updated time.Time
+}
+
+// Full — новое ведро
+func Full(l Limit, now time.Time) State {
+	return State{Tokens: float64(l.Burst), Updated: now}
+}
+
+// Result — итог попытки забрать токен
+type Result struct {
+	Allowed    bool
+	Limit      Limit
+	Remaining  int           // сколько действий осталось прямо сейчас
+	Reset      time.Duration // через сколько ведро наполнится полностью
+	RetryAfter time.Duration // через сколько появится токен; 0, если действие разрешено
+}
+
+// Take пополняет ведро за прошедшее время и забирает токен, если он есть
+func Take(s State, l Limit, now time.Time) (State, Result) {
+	rate := l.rate()
+	if elapsed := now.Sub(s.Updated).Seconds(); elapsed > 0 {
+		s.Tokens = math.Min(float64(l.Burst), s.Tokens+elapsed*rate)
+	}
+	s.Tokens = math.Min(s.Tokens, float64(l.Burst)) // лимит могли уменьшить
+	s.Updated = now
+
+	res := Result{Limit: l}
+	if s.Tokens >= 1 {
+		s.Tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - s.Tokens) / rate)
+	}
+	res.Remaining = int(math.Floor(s.Tokens))
+	res.Reset = seconds((float64(l.Burst) - s.Tokens) / rate)
+	return s, res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// Store хранит вёдра по ключу
+type Store interface {
+	// Take атомарно применяет ratelimit.Take к ведру key; отсутствующее ведро считается полным
+	Take(ctx context.Context, key string, l Limit, now time.Time) (Result, error)
+	// Sweep удаляет вёдра, которые не трогали с before: к этому времени они уже полные
+	Sweep(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,143 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"chat-service/internal/entity"
+	"chat-service/internal/errors"
+	"github.com/ZoyaDenisova/go-common/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type AttachmentRepoPostgres struct {
+	*postgres.Postgres
+}
+
+func NewAttachmentRepo(pg *postgres.Postgres) AttachmentRepository {
+	return &AttachmentRepoPostgres{pg}
+}
+
+// attachmentColumns — колонки вложения (a — attachments, m — messages через LEFT JOIN)
+const attachmentColumns = `
+        a.id, COALESCE(a.uploader_id, 0), a.message_id, a.file_name, a.content_type, a.size,
+        COALESCE(a.width, 0), COALESCE(a.height, 0), a.storage_key, COALESCE(a.thumbnail_key, ''), a.created_at,
+        COALESCE(m.deleted_at IS NOT NULL OR m.hidden_at IS NOT NULL, false)`
+
+func scanAttachment(row pgx.Row, a *entity.Attachment) error {
+	return row.Scan(&a.ID, &a.UploaderID, &a.MessageID, &a.FileName, &a.ContentType, &a.Size,
+		&a.Width, &a.Height, &a.StorageKey, &a.ThumbnailKey, &a.CreatedAt, &a.Hidden)
+}
+
+func (r *AttachmentRepoPostgres) list(ctx context.Context, op, query string, args ...any) ([]*entity.Attachment, error) {
+	rows, err := r.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	list := make([]*entity.Attachment, 0)
+	for rows.Next() {
+		a := &entity.Attachment{}
+		if err := scanAttachment(rows, a); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		list = append(list, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return list, nil
+}
+
+func (r *AttachmentRepoPostgres) Create(ctx context.Context, a *entity.Attachment) error {
+	const op = "AttachmentRepo.Create"
+	const query = `
+        INSERT INTO attachments (uploader_id, file_name, content_type, size, width, height, storage_key, thumbnail_key)
+        VALUES ($1, $2, $3, $4, NULLIF($5, 0), NULLIF($6, 0), $7, NULLIF($8, ''))
+        RETURNING id, created_at;
+    `
+	err := r.Pool.QueryRow(ctx, query, a.UploaderID, a.FileName, a.ContentType, a.Size,
+		a.Width, a.Height, a.StorageKey, a.ThumbnailKey).Scan(&a.ID, &a.CreatedAt)
+	if isFKViolation(err) {
+		return fmt.Errorf("%s: %w", op, errors.ErrInvalidReference)
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *AttachmentRepoPostgres) GetByID(ctx context.Context, id int64) (*entity.Attachment, error) {
+	const op = "AttachmentRepo.GetByID"
+	const query = `
+        SELECT ` + attachmentColumns + `
+        FROM attachments a
+        LEFT JOIN messages m ON m.id = a.message_id
+        WHERE a.id = $1;
+    `
+	a := &entity.Attachment{}
+	if err := scanAttachment(r.Pool.QueryRow(ctx, query, id), a); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return a, nil
+}
+
+func (r *AttachmentRepoPostgres) ListByIDs(ctx context.Context, ids []int64) ([]*entity.Attachment, error) {
+	const query = `
+        SELECT ` + attachmentColumns + `
+        FROM attachments a
+        LEFT JOIN messages m ON m.id = a.message_id
+        WHERE a.id = ANY($1);
+    `
+	return r.list(ctx, "AttachmentRepo.ListByIDs", query, ids)
+}
+
+func (r *AttachmentRepoPostgres) ListByMessages(ctx context.Context, messageIDs []int64) ([]*entity.Attachment, error) {
+	const query = `
+        SELECT ` + attachmentColumns + `
+        FROM attachments a
+        JOIN messages m ON m.id = a.message_id
+        WHERE a.message_id = ANY($1::integer[])
+        ORDER BY a.message_id, a.position, a.id;
+    `
+	return r.list(ctx, "AttachmentRepo.ListByMessages", query, messageIDs)
+}
+
+func (r *AttachmentRepoPostgres) CountPending(ctx context.Context, uploaderID int64) (int, error) {
+	const op = "AttachmentRepo.CountPending"
+	const query = `SELECT count(*) FROM attachments WHERE uploader_id = $1 AND message_id IS NULL;`
+
+	var n int
+	if err := r.Pool.QueryRow(ctx, query, uploaderID).Scan(&n); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return n, nil
+}
+
+func (r *AttachmentRepoPostgres) ListOrphans(ctx context.Context, before time.Time, limit int) ([]*entity.Attachment, error) {
+	const query = `
+        SELECT ` + attachmentColumns + `
+        FROM attachments a
+        LEFT JOIN messages m ON m.id = a.message_id
+        WHERE a.message_id IS NULL AND a.created_at < $1
+        ORDER BY a.created_at
+        LIMIT $2;
+    `
+	return r.list(ctx, "AttachmentRepo.ListOrphans", query, before, limit)
+}
+
+// Delete удаляет строки вложений, которые так и не привязались к сообщению
+func (r *AttachmentRepoPostgres) Delete(ctx context.Context, ids []int64) (int64, error) {
+	const op = "AttachmentRepo.Delete"
+	const query = `DELETE FROM attachments WHERE id = ANY($1) AND message_id IS NULL;`
+
+	tag, err := r.Pool.Exec(ctx, query, ids)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -2,14 +2,22 @@ package repo
 
 import (
 	"context"
+	stdErrors "errors"
 	"fmt"
 
 	"chat-service/internal/entity"
 	"chat-service/internal/errors"
 	"github.com/ZoyaDenisova/go-common/postgres"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// isFKViolation сообщает, что запрос нарушил внешний ключ (SQLSTATE 23503)
+func isFKViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return stdErrors.As(err, &pgErr) && pgErr.Code == "23503"
+}
+
 type CategoryRepoPostgres struct {
 	*postgres.Postgres
 }
@@ -20,14 +28,21 @@ func NewCategoryRepo(pg *postgres.Postgres) CategoryRepository {
 
 func (r *CategoryRepoPostgres) Create(ctx context.Context, c *entity.Category) error {
 	const op = "CategoryRepo.Create"
+	// новая категория встаёт последней среди соседей
 	const query = `
-        INSERT INTO categories (title, description)
-        VALUES ($1, $2)
-        RETURNING id;
+        INSERT INTO categories (title, description, parent_id, position)
+        VALUES ($1, $2, $3,
+                (SELECT COALESCE(MAX(position) + 1, 0)
+                   FROM categories
+                  WHERE parent_id IS NOT DISTINCT FROM $3::integer))
+        RETURNING id, position;
     `
 	if err := r.Pool.
-		QueryRow(ctx, query, c.Title, c.Description).
-		Scan(&c.ID); err != nil {
+		QueryRow(ctx, query, c.Title, c.Description, c.ParentID).
+		Scan(&c.ID, &c.Position); err != nil {
+		if isFKViolation(err) {
+			return fmt.Errorf("%s: parent: %w", op, errors.ErrInvalidReference)
+		}
 		return fmt.Errorf("%s: %w", op, err)
 	}
 	return nil
@@ -51,26 +66,59 @@ func (r *CategoryRepoPostgres) Update(ctx context.Context, c *entity.Category) e
 	return nil
 }
 
+// Delete удаляет пустую категорию. Категория с подкатегориями или живыми топиками
+// не удаляется — возвращается errors.ErrConflict, чтобы не снести поддерево каскадом.
 func (r *CategoryRepoPostgres) Delete(ctx context.Context, id int64) error {
 	const op = "CategoryRepo.Delete"
-	const query = `DELETE FROM categories WHERE id = $1;`
+	const query = `
+        DELETE FROM categories
+         WHERE id = $1
+           AND NOT EXISTS (SELECT 1 FROM categories WHERE parent_id = $1)
+           AND NOT EXISTS (SELECT 1 FROM topics
+                            WHERE category_id = $1 AND deleted_at IS NULL AND redirect_to IS NULL);
+    `
+	const existsQuery = `SELECT EXISTS (SELECT 1 FROM categories WHERE id = $1);`
 
 	tag, err := r.Pool.Exec(ctx, query, id)
 	if err != nil {
+		if isFKViolation(err) {
+			return fmt.Errorf("%s: %w", op, errors.ErrConflict)
+		}
 		return fmt.Errorf("%s: %w", op, err)
 	}
-	if tag.RowsAffected() == 0 {
-		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	if tag.RowsAffected() > 0 {
+		return nil
 	}
-	return nil
+
+	var exists bool
+	if err := r.Pool.QueryRow(ctx, existsQuery, id).Scan(&exists); err != nil {
+		return fmt.Errorf("%s: exists: %w", op, err)
+	}
+	if exists {
+		return fmt.Errorf("%s: %w", op, errors.ErrConflict)
+	}
+	return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
 }
 
+// GetAll возвращает все категории плоским списком в порядке position вместе со счётчиками
+// живых топиков и сообщений (без учёта подкатегорий).
 func (r *CategoryRepoPostgres) GetAll(ctx context.Context) ([]*entity.Category, error) {
 	const op = "CategoryRepo.GetAll"
 	const query = `
-        SELECT id, title, description
-        FROM categories
-        ORDER BY title
+        SELECT c.id, c.title, c.description, c.parent_id, c.position, c.qa,
+               COALESCE(c.retention_mode, 'default'), COALESCE(c.retention_value, 0),
+               COALESCE(s.topics, 0), COALESCE(s.messages, 0)
+        FROM categories c
+        LEFT JOIN (
+            SELECT t.category_id,
+                   COUNT(DISTINCT t.id) AS topics,
+                   COUNT(m.id)          AS messages
+              FROM topics t
+              LEFT JOIN messages m ON m.topic_id = t.id AND m.deleted_at IS NULL
+             WHERE t.deleted_at IS NULL AND t.redirect_to IS NULL
+             GROUP BY t.category_id
+        ) s ON s.category_id = c.id
+        ORDER BY c.position, c.title
     `
 
 	rows, err := r.Pool.Query(ctx, query)
@@ -82,7 +130,9 @@ func (r *CategoryRepoPostgres) GetAll(ctx context.Context) ([]*entity.Category,
 	var list []*entity.Category
 	for rows.Next() {
 		c := &entity.Category{}
-		if err := rows.Scan(&c.ID, &c.Title, &c.Description); err != nil {
+		if err := rows.Scan(&c.ID, &c.Title, &c.Description, &c.ParentID, &c.Position, &c.QA,
+			&c.Retention.Mode, &c.Retention.Value,
+			&c.TopicCount, &c.MessageCount); err != nil {
 			return nil, fmt.Errorf("%s: scan: %w", op, err)
 		}
 		list = append(list, c)
@@ -96,14 +146,16 @@ func (r *CategoryRepoPostgres) GetAll(ctx context.Context) ([]*entity.Category,
 func (r *CategoryRepoPostgres) GetByID(ctx context.Context, id int64) (*entity.Category, error) {
 	const op = "CategoryRepo.GetByID"
 	const query = `
-        SELECT id, title, description
+        SELECT id, title, description, parent_id, position, qa,
+               COALESCE(retention_mode, 'default'), COALESCE(retention_value, 0)
         FROM categories
         WHERE id = $1
     `
 
 	c := &entity.Category{}
 	err := r.Pool.QueryRow(ctx, query, id).
-		Scan(&c.ID, &c.Title, &c.Description)
+		Scan(&c.ID, &c.Title, &c.Description, &c.ParentID, &c.Position, &c.QA,
+			&c.Retention.Mode, &c.Retention.Value)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
@@ -112,3 +164,65 @@ func (r *CategoryRepoPostgres) GetByID(ctx context.Context, id int64) (*entity.C
 	}
 	return c, nil
 }
+
+// Reorder меняет родителя и позицию нескольких категорий в одной транзакции
+func (r *CategoryRepoPostgres) Reorder(ctx context.Context, items []entity.CategoryPosition) error {
+	const op = "CategoryRepo.Reorder"
+	const query = `UPDATE categories SET parent_id = $2, position = $3 WHERE id = $1;`
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: begin: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }() // после Commit откат ничего не делает
+
+	for _, it := range items {
+		tag, err := tx.Exec(ctx, query, it.ID, it.ParentID, it.Position)
+		if err != nil {
+			if isFKViolation(err) {
+				return fmt.Errorf("%s: parent of %d: %w", op, it.ID, errors.ErrInvalidReference)
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("%s: category %d: %w", op, it.ID, errors.ErrNotFound)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return nil
+}
+
+func (r *CategoryRepoPostgres) SetRetention(ctx context.Context, id int64, p entity.RetentionPolicy) error {
+	const op = "CategoryRepo.SetRetention"
+	const query = `
+        UPDATE categories
+           SET retention_mode  = NULLIF($2, 'default'),
+               retention_value = NULLIF($3, 0)
+         WHERE id = $1;
+    `
+	tag, err := r.Pool.Exec(ctx, query, id, string(p.Mode), p.Value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *CategoryRepoPostgres) SetQA(ctx context.Context, id int64, qa bool) error {
+	const op = "CategoryRepo.SetQA"
+	const query = `UPDATE categories SET qa = $2 WHERE id = $1;`
+
+	tag, err := r.Pool.Exec(ctx, query, id, qa)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+	return nil
+}
@@ -8,26 +8,301 @@ import (
 )
 
 type CategoryRepository interface {
+	// GetAll возвращает все категории плоским списком (по position) со счётчиками топиков и сообщений.
 	GetAll(ctx context.Context) ([]*entity.Category, error)
 	GetByID(ctx context.Context, id int64) (*entity.Category, error)
+	// Create добавляет категорию последней среди соседей; несуществующий родитель — errors.ErrInvalidReference.
 	Create(ctx context.Context, c *entity.Category) error
 	Update(ctx context.Context, c *entity.Category) error
+	// Delete удаляет только пустую категорию; с подкатегориями или топиками — errors.ErrConflict.
 	Delete(ctx context.Context, id int64) error
+	// Reorder атомарно меняет parent_id и position у переданных категорий.
+	Reorder(ctx context.Context, items []entity.CategoryPosition) error
+	// SetRetention задаёт политику хранения сообщений категории; Mode=default снимает её.
+	SetRetention(ctx context.Context, id int64, p entity.RetentionPolicy) error
+	// SetQA включает или выключает режим вопросов и ответов.
+	SetQA(ctx context.Context, id int64, qa bool) error
 }
 
 type TopicRepository interface {
+	// GetByCategory возвращает топики категории без скрытых по жалобам: сначала закреплённые, затем по дате создания.
 	GetByCategory(ctx context.Context, categoryID int64) ([]*entity.Topic, error)
+	// GetByID возвращает топик, в том числе заглушку слитого топика (RedirectTo != nil).
 	GetByID(ctx context.Context, id int64) (*entity.Topic, error)
 	Create(ctx context.Context, t *entity.Topic) (int64, error)
 	Update(ctx context.Context, t *entity.Topic) (int64, error)
-	Delete(ctx context.Context, id int64) error
+	// Delete мягко удаляет топик: строка остаётся, проставляются deleted_at/deleted_by/delete_reason.
+	Delete(ctx context.Context, id, deletedBy int64, reason string) error
+	// Restore снимает отметку удаления, если топик удалён не раньше since.
+	Restore(ctx context.Context, id int64, since time.Time) error
+	// GetDeleted возвращает топики, удалённые не раньше since.
+	GetDeleted(ctx context.Context, since time.Time) ([]*entity.Topic, error)
+	// PurgeDeleted физически удаляет топики, удалённые раньше threshold.
+	PurgeDeleted(ctx context.Context, threshold time.Time) (int64, error)
+	// SetRetention задаёт политику хранения сообщений топика; Mode=default снимает её.
+	SetRetention(ctx context.Context, id int64, p entity.RetentionPolicy) error
+	SetPinned(ctx context.Context, id int64, pinned bool) error
+	SetLocked(ctx context.Context, id int64, locked bool) error
+	// SetAccepted отмечает принятый ответ (nil — снимает отметку); удалённый топик — errors.ErrNotFound.
+	SetAccepted(ctx context.Context, id int64, messageID *int64) error
+	// Move переносит топик в другую категорию с записью в журнал модерации; возвращает прежнюю категорию.
+	// Несуществующая категория — errors.ErrInvalidReference.
+	Move(ctx context.Context, id, categoryID, actorID int64) (int64, error)
+	// Merge сливает sourceID в targetID, оставляя на месте sourceID заглушку; возвращает число перенесённых сообщений.
+	Merge(ctx context.Context, sourceID, targetID, actorID int64) (int64, error)
 }
 
 type MessageRepository interface {
+	// Create сохраняет сообщение и привязывает к нему m.Attachments; вложение занято или чужое — errors.ErrConflict.
 	Create(ctx context.Context, m *entity.Message) error
 	Update(ctx context.Context, id int64, newContent string) error
-	Delete(ctx context.Context, id int64) error
+	// Delete мягко удаляет сообщение: строка остаётся как tombstone.
+	Delete(ctx context.Context, id, deletedBy int64, reason string) error
+	// Restore снимает отметку удаления, если сообщение удалено не раньше since.
+	Restore(ctx context.Context, id int64, since time.Time) error
 	GetByTopic(ctx context.Context, topicID int64) ([]*entity.Message, error)
 	GetByID(ctx context.Context, id int64) (*entity.Message, error)
-	DeleteOlderThan(ctx context.Context, threshold time.Time) error
+	// GetDeleted возвращает сообщения, удалённые не раньше since.
+	GetDeleted(ctx context.Context, since time.Time) ([]*entity.Message, error)
+	// CountExpired считает по топикам сообщения, вышедшие за политику хранения (dry-run).
+	// threshold — глобальный порог для топиков без собственной политики.
+	CountExpired(ctx context.Context, threshold, now time.Time) ([]*entity.RetentionStat, error)
+	// DeleteExpired удаляет не больше limit сообщений, вышедших за политику хранения.
+	DeleteExpired(ctx context.Context, threshold, now time.Time, limit int) (int64, error)
+	// PurgeDeleted физически удаляет tombstone-сообщения, удалённые раньше threshold.
+	PurgeDeleted(ctx context.Context, threshold time.Time) (int64, error)
+	// RecentByAuthor возвращает тексты последних (не больше limit) сообщений автора, отправленных не раньше since.
+	RecentByAuthor(ctx context.Context, authorID int64, since time.Time, limit int) ([]string, error)
+}
+
+type ModeratorRepository interface {
+	// IsModerator сообщает, что userID модерирует категорию — напрямую или через родительскую.
+	IsModerator(ctx context.Context, userID, categoryID int64) (bool, error)
+}
+
+type ModerationRepository interface {
+	Save(ctx context.Context, e *entity.ModerationEntry) error
+	// List возвращает страницу журнала по фильтру (новые сверху) и общее число подходящих записей.
+	List(ctx context.Context, f entity.ModerationFilter) ([]*entity.ModerationEntry, int64, error)
+}
+
+type ReportRepository interface {
+	// Create сохраняет жалобу и скрывает объект, если открытых жалоб стало не меньше hideAfter (0 — не скрывать).
+	// Заполняет ID, TopicID и TargetAuthorID; возвращает true, если объект скрыт этой жалобой.
+	// Объекта нет или он удалён — errors.ErrNotFound, повторная жалоба того же пользователя — errors.ErrConflict.
+	Create(ctx context.Context, r *entity.Report, hideAfter int) (bool, error)
+	GetByID(ctx context.Context, id int64) (*entity.Report, error)
+	// List возвращает страницу очереди (старые сверху) и общее число подходящих жалоб.
+	List(ctx context.Context, f entity.ReportFilter) ([]*entity.Report, int64, error)
+	// Resolve закрывает все открытые жалобы на объект; при state=dismissed снимает скрытие.
+	// Открытых жалоб нет — errors.ErrNotFound.
+	Resolve(ctx context.Context, targetType string, targetID int64, state string, resolvedBy int64, resolution string) (int64, error)
+}
+
+type MuteRepository interface {
+	// Create сохраняет мьют; несуществующие пользователь, топик или категория — errors.ErrInvalidReference.
+	Create(ctx context.Context, m *entity.Mute) error
+	GetByID(ctx context.Context, id int64) (*entity.Mute, error)
+	Delete(ctx context.Context, id int64) error
+	// ListActive возвращает действующие мьюты пользователя (userID = 0 — всех), новые сверху.
+	ListActive(ctx context.Context, userID int64) ([]*entity.Mute, error)
+	// FindActive ищет действующий мьют, запрещающий писать в топике topicID (0 — проверяется только категория)
+	// или в категории categoryID с учётом родительских. Ничего нет — errors.ErrNotFound.
+	FindActive(ctx context.Context, userID, topicID, categoryID int64) (*entity.Mute, error)
+	// DeleteExpired удаляет мьюты, истёкшие к before.
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+type FilterRepository interface {
+	ListRules(ctx context.Context) ([]*entity.FilterRule, error)
+	GetRule(ctx context.Context, id int64) (*entity.FilterRule, error)
+	// CreateRule сохраняет правило; такое же правило того же вида уже есть — errors.ErrConflict.
+	CreateRule(ctx context.Context, f *entity.FilterRule) error
+	// UpdateRule меняет шаблон и действие правила и дозаполняет остальные поля.
+	UpdateRule(ctx context.Context, f *entity.FilterRule) error
+	DeleteRule(ctx context.Context, id int64) error
+	GetSettings(ctx context.Context) (*entity.FilterSettings, error)
+	SaveSettings(ctx context.Context, s *entity.FilterSettings) error
+}
+
+type UserRepository interface {
+	// GetStanding возвращает возраст аккаунта, число сообщений и репутацию; нет пользователя — errors.ErrNotFound.
+	GetStanding(ctx context.Context, userID int64) (*entity.Standing, error)
+}
+
+type MentionRepository interface {
+	// Replace оставляет у сообщения ровно упоминания userIDs и возвращает тех, кто упомянут впервые.
+	Replace(ctx context.Context, messageID int64, userIDs []int64) ([]int64, error)
+	// ListForUser возвращает страницу упоминаний пользователя (новые сверху) без удалённых и скрытых сообщений
+	// и общее число подходящих упоминаний.
+	ListForUser(ctx context.Context, userID int64, limit, offset int) ([]*entity.Mention, int64, error)
+}
+
+type NotificationRepository interface {
+	// Create сохраняет уведомление и заполняет ID и CreatedAt; несуществующие ссылки — errors.ErrInvalidReference.
+	Create(ctx context.Context, n *entity.Notification) error
+	// List возвращает страницу уведомлений пользователя (новые сверху) и общее число подходящих.
+	List(ctx context.Context, f entity.NotificationFilter) ([]*entity.Notification, int64, error)
+	CountUnread(ctx context.Context, userID int64) (int64, error)
+	// MarkRead отмечает уведомление прочитанным; чужое или несуществующее — errors.ErrNotFound.
+	MarkRead(ctx context.Context, userID, id int64) error
+	// MarkAllRead отмечает прочитанными все уведомления пользователя и возвращает, сколько их было.
+	MarkAllRead(ctx context.Context, userID int64) (int64, error)
+}
+
+type SubscriptionRepository interface {
+	// Follow сохраняет подписку и заводит пользователю настройки дайджеста, если их ещё нет; повторная ничего не меняет.
+	// Несуществующие топик или категория — errors.ErrInvalidReference.
+	Follow(ctx context.Context, s *entity.Subscription) error
+	// Unfollow удаляет подписку; её не было — errors.ErrNotFound.
+	Unfollow(ctx context.Context, userID int64, topicID, categoryID *int64) error
+	// ListByUser возвращает подписки пользователя с названиями, новые сверху.
+	ListByUser(ctx context.Context, userID int64) ([]*entity.Subscription, error)
+	// GetSettings возвращает настройки дайджеста; их ещё нет — errors.ErrNotFound.
+	GetSettings(ctx context.Context, userID int64) (*entity.DigestSettings, error)
+	// SaveSettings меняет частоту дайджеста (создавая настройки при необходимости) и дозаполняет остальные поля.
+	SaveSettings(ctx context.Context, s *entity.DigestSettings) error
+	// Unsubscribe выключает дайджест по токену из письма; неизвестный токен — errors.ErrNotFound.
+	Unsubscribe(ctx context.Context, token string) error
+	// DueRecipients возвращает не больше limit подписчиков с id больше afterID, которым к now пора отправить дайджест.
+	// Заблокированные и не подписанные ни на что пропускаются.
+	DueRecipients(ctx context.Context, now time.Time, afterID int64, limit int) ([]*entity.DigestRecipient, error)
+	// DigestTopics возвращает не больше limit топиков из подписок пользователя, где с since по until появились
+	// чужие сообщения или которые за это время созданы, вместе с perTopic последними новыми сообщениями.
+	DigestTopics(ctx context.Context, userID int64, since, until time.Time, limit, perTopic int) ([]*entity.DigestTopic, error)
+	// MarkSent запоминает, что дайджест отправлен в at.
+	MarkSent(ctx context.Context, userID int64, at time.Time) error
+}
+
+type ReadRepository interface {
+	// MarkRead сдвигает позицию чтения вперёд до messageID, а при nil — до последнего сообщения топика;
+	// назад позиция не двигается. Сообщения нет в топике — errors.ErrNotFound, нет топика — errors.ErrInvalidReference.
+	MarkRead(ctx context.Context, userID, topicID int64, messageID *int64) (*entity.TopicRead, error)
+	// GetByTopics возвращает позиции пользователя в перечисленных топиках с числом непрочитанных;
+	// топиков, которые он не открывал, в ответе нет.
+	GetByTopics(ctx context.Context, userID int64, topicIDs []int64) (map[int64]*entity.TopicRead, error)
+	// UnreadByCategory возвращает категории, где у пользователя есть непрочитанное, в порядке position.
+	UnreadByCategory(ctx context.Context, userID int64) ([]*entity.CategoryUnread, error)
+	// UnreadTopics возвращает не больше limit топиков с непрочитанным, свежие сверху.
+	UnreadTopics(ctx context.Context, userID int64, limit int) ([]*entity.UnreadTopic, error)
+}
+
+type ConversationRepository interface {
+	// IsBanned сообщает, заблокирован ли пользователь на форуме сейчас; нет пользователя — errors.ErrNotFound.
+	IsBanned(ctx context.Context, userID int64) (bool, error)
+	// Correspondents возвращает существующих пользователей из userIDs с запретами писать между ними и senderID.
+	Correspondents(ctx context.Context, senderID int64, userIDs []int64) ([]*entity.Correspondent, error)
+	// Create заводит переписку; переписка один на один между теми же людьми уже есть — в c её данные, created = false.
+	Create(ctx context.Context, c *entity.Conversation, memberIDs []int64) (created bool, err error)
+	// GetByID возвращает переписку с участниками; нет — errors.ErrNotFound.
+	GetByID(ctx context.Context, id int64) (*entity.Conversation, error)
+	// ListByUser возвращает страницу переписок пользователя (свежие сверху) с последним сообщением и непрочитанным
+	// и общее их число.
+	ListByUser(ctx context.Context, userID int64, limit, offset int) ([]*entity.Conversation, int64, error)
+	// AddMember добавляет участника; уже состоит — errors.ErrConflict.
+	AddMember(ctx context.Context, conversationID, userID int64) error
+	// RemoveMember убирает участника; не состоял — errors.ErrNotFound.
+	RemoveMember(ctx context.Context, conversationID, userID int64) error
+	// CreateMessage сохраняет сообщение и отмечает его прочитанным для автора.
+	CreateMessage(ctx context.Context, m *entity.PrivateMessage) error
+	// GetMessage возвращает сообщение; нет — errors.ErrNotFound.
+	GetMessage(ctx context.Context, id int64) (*entity.PrivateMessage, error)
+	// ListMessages возвращает не больше limit сообщений переписки с id меньше beforeID (0 — с конца), новые сверху.
+	ListMessages(ctx context.Context, conversationID, beforeID int64, limit int) ([]*entity.PrivateMessage, error)
+	// DeleteMessage мягко удаляет сообщение; нет или уже удалено — errors.ErrNotFound.
+	DeleteMessage(ctx context.Context, id int64) error
+	// MarkRead сдвигает позицию чтения участника вперёд до messageID (nil — до конца) и возвращает её;
+	// сообщения нет в переписке или пользователь в ней не состоит — errors.ErrNotFound.
+	MarkRead(ctx context.Context, conversationID, userID int64, messageID *int64) (int64, error)
+	// Block запрещает blockedID писать userID; нет пользователя — errors.ErrInvalidReference.
+	Block(ctx context.Context, userID, blockedID int64) error
+	// Unblock снимает запрет; его не было — errors.ErrNotFound.
+	Unblock(ctx context.Context, userID, blockedID int64) error
+	// ListBlocks возвращает, кому пользователь запретил писать себе, новые сверху.
+	ListBlocks(ctx context.Context, userID int64) ([]*entity.UserBlock, error)
+}
+
+type RoomRepository interface {
+	// Create заводит комнату и проставляет ID, Position и CreatedAt; slug занят — errors.ErrConflict.
+	Create(ctx context.Context, rm *entity.Room) error
+	// GetByID возвращает комнату; нет — errors.ErrNotFound.
+	GetByID(ctx context.Context, id int64) (*entity.Room, error)
+	// GetBySlug возвращает комнату по постоянному имени; нет — errors.ErrNotFound.
+	GetBySlug(ctx context.Context, slug string) (*entity.Room, error)
+	// List возвращает все комнаты по порядку.
+	List(ctx context.Context) ([]*entity.Room, error)
+	// Update сохраняет настройки комнаты; нет — errors.ErrNotFound, slug занят — errors.ErrConflict.
+	Update(ctx context.Context, rm *entity.Room) error
+	// Delete удаляет комнату вместе с сообщениями; нет — errors.ErrNotFound.
+	Delete(ctx context.Context, id int64) error
+	// IsBanned сообщает, заблокирован ли пользователь на форуме сейчас; нет пользователя — errors.ErrNotFound.
+	IsBanned(ctx context.Context, userID int64) (bool, error)
+	// UserNames возвращает имена пользователей по id; неизвестных id в ответе нет.
+	UserNames(ctx context.Context, ids []int64) (map[int64]string, error)
+	// CreateMessage сохраняет сообщение и проставляет ID и AuthorName.
+	CreateMessage(ctx context.Context, m *entity.RoomMessage) error
+	// GetMessage возвращает сообщение комнаты; нет — errors.ErrNotFound.
+	GetMessage(ctx context.Context, id int64) (*entity.RoomMessage, error)
+	// ListMessages возвращает не больше limit неудалённых сообщений комнаты с id меньше beforeID (0 — с конца), новые сверху.
+	ListMessages(ctx context.Context, roomID, beforeID int64, limit int) ([]*entity.RoomMessage, error)
+	// UpdateMessage меняет текст; нет или удалено — errors.ErrNotFound.
+	UpdateMessage(ctx context.Context, id int64, content string, updatedAt time.Time) error
+	// DeleteMessage мягко удаляет сообщение; нет или уже удалено — errors.ErrNotFound.
+	DeleteMessage(ctx context.Context, id int64) error
+	// DeleteExpired удаляет не больше limit сообщений, вышедших за политику хранения комнаты.
+	DeleteExpired(ctx context.Context, threshold, now time.Time, limit int) (int64, error)
+	// PurgeDeleted физически удаляет сообщения комнат, удалённые раньше threshold.
+	PurgeDeleted(ctx context.Context, threshold time.Time) (int64, error)
+}
+
+type AttachmentRepository interface {
+	// Create сохраняет загруженное вложение и проставляет ID и CreatedAt.
+	Create(ctx context.Context, a *entity.Attachment) error
+	// GetByID возвращает вложение; Hidden — его сообщение удалено или скрыто. Нет — errors.ErrNotFound.
+	GetByID(ctx context.Context, id int64) (*entity.Attachment, error)
+	// ListByIDs возвращает вложения с указанными id в любом порядке; неизвестных id в ответе нет.
+	ListByIDs(ctx context.Context, ids []int64) ([]*entity.Attachment, error)
+	// ListByMessages возвращает вложения сообщений по порядку внутри каждого сообщения.
+	ListByMessages(ctx context.Context, messageIDs []int64) ([]*entity.Attachment, error)
+	// CountPending считает вложения пользователя, ещё не привязанные к сообщению.
+	CountPending(ctx context.Context, uploaderID int64) (int, error)
+	// ListOrphans возвращает не больше limit вложений без сообщения, загруженных раньше before, старые первыми.
+	ListOrphans(ctx context.Context, before time.Time, limit int) ([]*entity.Attachment, error)
+	// Delete удаляет вложения без сообщения; привязанные за это время не трогает.
+	Delete(ctx context.Context, ids []int64) (int64, error)
+}
+
+type ProfileRepository interface {
+	// Get возвращает профиль с именем и датой регистрации; профиль ещё не заполняли — поля пустые.
+	// Нет пользователя — errors.ErrNotFound.
+	Get(ctx context.Context, userID int64) (*entity.Profile, error)
+	// UserIDByUsername находит пользователя по username без учёта регистра. Нет такого — errors.ErrNotFound.
+	UserIDByUsername(ctx context.Context, username string) (int64, error)
+	// Save сохраняет текстовые поля профиля. Нет пользователя — errors.ErrNotFound.
+	Save(ctx context.Context, p *entity.Profile) error
+	// SetAvatar ставит новый ключ аватара (пустой — убрать аватар) и возвращает прежний, чтобы удалить файл.
+	SetAvatar(ctx context.Context, userID int64, key string) (string, error)
+	// Stats считает видимые всем топики и сообщения пользователя.
+	Stats(ctx context.Context, userID int64) (*entity.ProfileStats, error)
+	// RecentActivity возвращает последние видимые всем сообщения пользователя, новые сверху.
+	RecentActivity(ctx context.Context, userID int64, limit int) ([]*entity.ProfileActivity, error)
+}
+
+type VoteRepository interface {
+	// Vote ставит голос value (1 или -1) или снимает его (0), меняя счёт сообщения и репутацию автора
+	// на разницу с прежним голосом. Сообщение удалено, скрыто или не существует — errors.ErrNotFound.
+	Vote(ctx context.Context, messageID, userID int64, value int) (*entity.MessageVote, error)
+	// ByUser возвращает голоса пользователя за перечисленные сообщения; за какие не голосовал — в ответе нет.
+	ByUser(ctx context.Context, userID int64, messageIDs []int64) (map[int64]int, error)
+	// Reputation возвращает репутацию пользователя; за кого ещё не голосовали — 0.
+	Reputation(ctx context.Context, userID int64) (int64, error)
+}
+
+// AuthWebAPI — вызовы auth-service от имени текущего пользователя (токен берётся из контекста)
+type AuthWebAPI interface {
+	// BlockUser блокирует пользователя; нет прав — errors.ErrPermissionDenied, нет пользователя — errors.ErrNotFound.
+	BlockUser(ctx context.Context, userID int64, reason string) error
+	// ResolveUsernames возвращает id пользователей по username без учёта регистра; ключи — в нижнем регистре,
+	// неизвестных имён в ответе нет.
+	ResolveUsernames(ctx context.Context, names []string) (map[string]int64, error)
 }
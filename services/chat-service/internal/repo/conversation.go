@@ -0,0 +1,479 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"chat-service/internal/entity"
+	"chat-service/internal/errors"
+	"github.com/ZoyaDenisova/go-common/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type ConversationRepoPostgres struct {
+	*postgres.Postgres
+}
+
+func NewConversationRepo(pg *postgres.Postgres) ConversationRepository {
+	return &ConversationRepoPostgres{pg}
+}
+
+// текст удалённого сообщения не отдаётся никому
+const privateMessageColumns = `
+        pm.id, pm.conversation_id, pm.author_id, COALESCE(u.name, ''),
+        CASE WHEN pm.deleted_at IS NULL THEN pm.content ELSE '' END, pm.created_at, pm.deleted_at`
+
+func scanPrivateMessage(row pgx.Row, m *entity.PrivateMessage, extra ...any) error {
+	return row.Scan(append([]any{&m.ID, &m.ConversationID, &m.AuthorID, &m.AuthorName,
+		&m.Content, &m.CreatedAt, &m.DeletedAt}, extra...)...)
+}
+
+// directKey — ключ переписки один на один, одинаковый для обоих собеседников
+func directKey(a, b int64) string {
+	if a > b {
+		a, b = b, a
+	}
+	return fmt.Sprintf("%d:%d", a, b)
+}
+
+func (r *ConversationRepoPostgres) IsBanned(ctx context.Context, userID int64) (bool, error) {
+	const op = "ConversationRepo.IsBanned"
+	const query = `
+        SELECT is_blocked AND (blocked_until IS NULL OR blocked_until > now())
+        FROM users
+        WHERE id = $1;
+    `
+	var banned bool
+	if err := r.Pool.QueryRow(ctx, query, userID).Scan(&banned); err != nil {
+		if err == pgx.ErrNoRows {
+			return false, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return banned, nil
+}
+
+func (r *ConversationRepoPostgres) Correspondents(ctx context.Context, senderID int64, userIDs []int64) ([]*entity.Correspondent, error) {
+	const op = "ConversationRepo.Correspondents"
+	const query = `
+        SELECT u.id, u.name,
+               u.is_blocked AND (u.blocked_until IS NULL OR u.blocked_until > now()),
+               EXISTS (SELECT 1 FROM user_blocks b WHERE b.user_id = u.id AND b.blocked_user_id = $1),
+               EXISTS (SELECT 1 FROM user_blocks b WHERE b.user_id = $1 AND b.blocked_user_id = u.id)
+        FROM users u
+        WHERE u.id = ANY($2)
+        ORDER BY u.id;
+    `
+	rows, err := r.Pool.Query(ctx, query, senderID, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	list := make([]*entity.Correspondent, 0, len(userIDs))
+	for rows.Next() {
+		var c entity.Correspondent
+		if err := rows.Scan(&c.UserID, &c.Name, &c.Banned, &c.BlocksSender, &c.BlockedBySender); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		list = append(list, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return list, nil
+}
+
+// Create заводит переписку с участниками memberIDs. Переписка один на один между теми же
+// двумя людьми может быть только одна: если она уже есть, в c попадают её ID и дата, а created = false.
+func (r *ConversationRepoPostgres) Create(ctx context.Context, c *entity.Conversation, memberIDs []int64) (bool, error) {
+	const op = "ConversationRepo.Create"
+	const insertQuery = `
+        INSERT INTO conversations (title, is_group, direct_key, created_by)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (direct_key) DO UPDATE SET direct_key = EXCLUDED.direct_key
+        RETURNING id, created_at, (xmax = 0) AS created;
+    `
+	const membersQuery = `
+        INSERT INTO conversation_members (conversation_id, user_id)
+        SELECT $1, unnest($2::integer[])
+        ON CONFLICT DO NOTHING;
+    `
+	var key *string
+	if !c.IsGroup {
+		if len(memberIDs) != 2 {
+			return false, fmt.Errorf("%s: direct conversation needs 2 members, got %d", op, len(memberIDs))
+		}
+		k := directKey(memberIDs[0], memberIDs[1])
+		key = &k
+	}
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%s: begin: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }() // после Commit откат ничего не делает
+
+	var created bool
+	if err := tx.QueryRow(ctx, insertQuery, c.Title, c.IsGroup, key, c.CreatedBy).
+		Scan(&c.ID, &c.CreatedAt, &created); err != nil {
+		return false, fmt.Errorf("%s: insert: %w", op, err)
+	}
+	if created {
+		if _, err := tx.Exec(ctx, membersQuery, c.ID, memberIDs); err != nil {
+			if isFKViolation(err) {
+				return false, fmt.Errorf("%s: members: %w", op, errors.ErrInvalidReference)
+			}
+			return false, fmt.Errorf("%s: members: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return created, nil
+}
+
+func (r *ConversationRepoPostgres) GetByID(ctx context.Context, id int64) (*entity.Conversation, error) {
+	const op = "ConversationRepo.GetByID"
+	const query = `
+        SELECT id, title, is_group, created_by, created_at, last_message_at
+        FROM conversations
+        WHERE id = $1;
+    `
+	var c entity.Conversation
+	err := r.Pool.QueryRow(ctx, query, id).
+		Scan(&c.ID, &c.Title, &c.IsGroup, &c.CreatedBy, &c.CreatedAt, &c.LastMessageAt)
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	members, err := r.members(ctx, []int64{c.ID})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	c.Members = members[c.ID]
+	return &c, nil
+}
+
+// members возвращает участников переписок ids в порядке вступления
+func (r *ConversationRepoPostgres) members(ctx context.Context, ids []int64) (map[int64][]*entity.ConversationMember, error) {
+	const query = `
+        SELECT cm.conversation_id, cm.user_id, u.name, cm.last_read_message_id, cm.joined_at
+        FROM conversation_members cm
+        JOIN users u ON u.id = cm.user_id
+        WHERE cm.conversation_id = ANY($1)
+        ORDER BY cm.joined_at, cm.user_id;
+    `
+	rows, err := r.Pool.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("members: %w", err)
+	}
+	defer rows.Close()
+
+	res := make(map[int64][]*entity.ConversationMember, len(ids))
+	for rows.Next() {
+		var (
+			convID int64
+			m      entity.ConversationMember
+		)
+		if err := rows.Scan(&convID, &m.UserID, &m.Name, &m.LastReadMessageID, &m.JoinedAt); err != nil {
+			return nil, fmt.Errorf("members: scan: %w", err)
+		}
+		res[convID] = append(res[convID], &m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("members: rows: %w", err)
+	}
+	return res, nil
+}
+
+func (r *ConversationRepoPostgres) ListByUser(ctx context.Context, userID int64, limit, offset int) ([]*entity.Conversation, int64, error) {
+	const op = "ConversationRepo.ListByUser"
+	const query = `
+        SELECT c.id, c.title, c.is_group, c.created_by, c.created_at, c.last_message_at,
+               (SELECT count(*) FROM private_messages pm
+                 WHERE pm.conversation_id = c.id AND pm.id > me.last_read_message_id
+                   AND pm.deleted_at IS NULL AND pm.author_id <> $1),
+               lm.id, lm.author_id, COALESCE(lu.name, ''),
+               CASE WHEN lm.deleted_at IS NULL THEN lm.content ELSE '' END, lm.created_at, lm.deleted_at,
+               count(*) OVER () AS total
+        FROM conversation_members me
+        JOIN conversations c ON c.id = me.conversation_id
+        LEFT JOIN LATERAL (
+            SELECT pm.id, pm.author_id, pm.content, pm.created_at, pm.deleted_at
+            FROM private_messages pm
+            WHERE pm.conversation_id = c.id
+            ORDER BY pm.id DESC
+            LIMIT 1
+        ) lm ON TRUE
+        LEFT JOIN users lu ON lu.id = lm.author_id
+        WHERE me.user_id = $1
+        ORDER BY COALESCE(c.last_message_at, c.created_at) DESC, c.id DESC
+        LIMIT $2 OFFSET $3;
+    `
+	rows, err := r.Pool.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	var (
+		list  = make([]*entity.Conversation, 0)
+		ids   []int64
+		total int64
+	)
+	for rows.Next() {
+		var (
+			c         entity.Conversation
+			lmID      *int64
+			lmAuthor  *int64
+			lmName    string
+			lmContent *string
+			lmAt      *time.Time
+			lmDeleted *time.Time
+		)
+		if err := rows.Scan(&c.ID, &c.Title, &c.IsGroup, &c.CreatedBy, &c.CreatedAt, &c.LastMessageAt,
+			&c.Unread, &lmID, &lmAuthor, &lmName, &lmContent, &lmAt, &lmDeleted, &total); err != nil {
+			return nil, 0, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		if lmID != nil {
+			c.LastMessage = &entity.PrivateMessage{
+				ID:             *lmID,
+				ConversationID: c.ID,
+				AuthorID:       *lmAuthor,
+				AuthorName:     lmName,
+				Content:        *lmContent,
+				CreatedAt:      *lmAt,
+				DeletedAt:      lmDeleted,
+			}
+		}
+		list = append(list, &c)
+		ids = append(ids, c.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	if len(ids) == 0 {
+		return list, total, nil
+	}
+
+	members, err := r.members(ctx, ids)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	for _, c := range list {
+		c.Members = members[c.ID]
+	}
+	return list, total, nil
+}
+
+func (r *ConversationRepoPostgres) AddMember(ctx context.Context, conversationID, userID int64) error {
+	const op = "ConversationRepo.AddMember"
+	const query = `
+        INSERT INTO conversation_members (conversation_id, user_id)
+        VALUES ($1, $2);
+    `
+	_, err := r.Pool.Exec(ctx, query, conversationID, userID)
+	switch {
+	case isUniqueViolation(err):
+		return fmt.Errorf("%s: %w", op, errors.ErrConflict)
+	case isFKViolation(err):
+		return fmt.Errorf("%s: %w", op, errors.ErrInvalidReference)
+	case err != nil:
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *ConversationRepoPostgres) RemoveMember(ctx context.Context, conversationID, userID int64) error {
+	const op = "ConversationRepo.RemoveMember"
+	const query = `DELETE FROM conversation_members WHERE conversation_id = $1 AND user_id = $2;`
+
+	tag, err := r.Pool.Exec(ctx, query, conversationID, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+	return nil
+}
+
+// CreateMessage сохраняет сообщение, сдвигает время последнего сообщения переписки
+// и позицию чтения автора — своё сообщение непрочитанным не бывает
+func (r *ConversationRepoPostgres) CreateMessage(ctx context.Context, m *entity.PrivateMessage) error {
+	const op = "ConversationRepo.CreateMessage"
+	const query = `
+        WITH msg AS (
+            INSERT INTO private_messages (conversation_id, author_id, content, created_at)
+            VALUES ($1, $2, $3, $4)
+            RETURNING id, created_at
+        ), conv AS (
+            UPDATE conversations SET last_message_at = (SELECT created_at FROM msg)
+            WHERE id = $1
+        ), pos AS (
+            UPDATE conversation_members SET last_read_message_id = (SELECT id FROM msg)
+            WHERE conversation_id = $1 AND user_id = $2
+        )
+        SELECT id FROM msg;
+    `
+	err := r.Pool.QueryRow(ctx, query, m.ConversationID, m.AuthorID, m.Content, m.CreatedAt).Scan(&m.ID)
+	if isFKViolation(err) {
+		return fmt.Errorf("%s: %w", op, errors.ErrInvalidReference)
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *ConversationRepoPostgres) GetMessage(ctx context.Context, id int64) (*entity.PrivateMessage, error) {
+	const op = "ConversationRepo.GetMessage"
+	const query = `
+        SELECT ` + privateMessageColumns + `
+        FROM private_messages pm
+        LEFT JOIN users u ON u.id = pm.author_id
+        WHERE pm.id = $1;
+    `
+	var m entity.PrivateMessage
+	if err := scanPrivateMessage(r.Pool.QueryRow(ctx, query, id), &m); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &m, nil
+}
+
+func (r *ConversationRepoPostgres) ListMessages(ctx context.Context, conversationID, beforeID int64, limit int) ([]*entity.PrivateMessage, error) {
+	const op = "ConversationRepo.ListMessages"
+	const query = `
+        SELECT ` + privateMessageColumns + `
+        FROM private_messages pm
+        LEFT JOIN users u ON u.id = pm.author_id
+        WHERE pm.conversation_id = $1 AND ($2 = 0 OR pm.id < $2)
+        ORDER BY pm.id DESC
+        LIMIT $3;
+    `
+	rows, err := r.Pool.Query(ctx, query, conversationID, beforeID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	list := make([]*entity.PrivateMessage, 0)
+	for rows.Next() {
+		var m entity.PrivateMessage
+		if err := scanPrivateMessage(rows, &m); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		list = append(list, &m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return list, nil
+}
+
+func (r *ConversationRepoPostgres) DeleteMessage(ctx context.Context, id int64) error {
+	const op = "ConversationRepo.DeleteMessage"
+	const query = `UPDATE private_messages SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL;`
+
+	tag, err := r.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+	return nil
+}
+
+// MarkRead сдвигает позицию участника вперёд до messageID (nil — до последнего сообщения)
+// и возвращает получившуюся позицию
+func (r *ConversationRepoPostgres) MarkRead(ctx context.Context, conversationID, userID int64, messageID *int64) (int64, error) {
+	const op = "ConversationRepo.MarkRead"
+	const query = `
+        UPDATE conversation_members cm
+        SET last_read_message_id = GREATEST(cm.last_read_message_id, p.id)
+        FROM (
+            SELECT COALESCE(MAX(pm.id), 0) AS id, COUNT(pm.id) AS found
+            FROM private_messages pm
+            WHERE pm.conversation_id = $1 AND ($3::bigint IS NULL OR pm.id = $3::bigint)
+        ) p
+        WHERE cm.conversation_id = $1 AND cm.user_id = $2
+          AND ($3::bigint IS NULL OR p.found > 0)
+        RETURNING cm.last_read_message_id;
+    `
+	var pos int64
+	if err := r.Pool.QueryRow(ctx, query, conversationID, userID, messageID).Scan(&pos); err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return pos, nil
+}
+
+// Block запрещает blockedID писать userID; повторный запрет ничего не меняет
+func (r *ConversationRepoPostgres) Block(ctx context.Context, userID, blockedID int64) error {
+	const op = "ConversationRepo.Block"
+	const query = `
+        INSERT INTO user_blocks (user_id, blocked_user_id)
+        VALUES ($1, $2)
+        ON CONFLICT DO NOTHING;
+    `
+	_, err := r.Pool.Exec(ctx, query, userID, blockedID)
+	if isFKViolation(err) {
+		return fmt.Errorf("%s: %w", op, errors.ErrInvalidReference)
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *ConversationRepoPostgres) Unblock(ctx context.Context, userID, blockedID int64) error {
+	const op = "ConversationRepo.Unblock"
+	const query = `DELETE FROM user_blocks WHERE user_id = $1 AND blocked_user_id = $2;`
+
+	tag, err := r.Pool.Exec(ctx, query, userID, blockedID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *ConversationRepoPostgres) ListBlocks(ctx context.Context, userID int64) ([]*entity.UserBlock, error) {
+	const op = "ConversationRepo.ListBlocks"
+	const query = `
+        SELECT b.user_id, b.blocked_user_id, u.name, b.created_at
+        FROM user_blocks b
+        JOIN users u ON u.id = b.blocked_user_id
+        WHERE b.user_id = $1
+        ORDER BY b.created_at DESC;
+    `
+	rows, err := r.Pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	list := make([]*entity.UserBlock, 0)
+	for rows.Next() {
+		var b entity.UserBlock
+		if err := rows.Scan(&b.UserID, &b.BlockedUserID, &b.BlockedUserName, &b.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		list = append(list, &b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return list, nil
+}
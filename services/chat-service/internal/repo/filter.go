@@ -0,0 +1,167 @@
+package repo
+
+import (
+	"chat-service/internal/entity"
+	"chat-service/internal/errors"
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/postgres"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"time"
+)
+
+// isUniqueViolation сообщает, что запрос нарушил уникальный индекс (SQLSTATE 23505)
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return stdErrors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+type FilterRepoPostgres struct {
+	*postgres.Postgres
+}
+
+func NewFilterRepo(pg *postgres.Postgres) FilterRepository {
+	return &FilterRepoPostgres{pg}
+}
+
+func (r *FilterRepoPostgres) ListRules(ctx context.Context) ([]*entity.FilterRule, error) {
+	const op = "FilterRepo.ListRules"
+	const query = `
+        SELECT id, kind, pattern, action, created_by, created_at
+        FROM filter_rules
+        ORDER BY kind, id;
+    `
+	rows, err := r.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	list := make([]*entity.FilterRule, 0)
+	for rows.Next() {
+		var f entity.FilterRule
+		if err := rows.Scan(&f.ID, &f.Kind, &f.Pattern, &f.Action, &f.CreatedBy, &f.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		list = append(list, &f)
+	}
+	return list, rows.Err()
+}
+
+func (r *FilterRepoPostgres) CreateRule(ctx context.Context, f *entity.FilterRule) error {
+	const op = "FilterRepo.CreateRule"
+	const query = `
+        INSERT INTO filter_rules (kind, pattern, action, created_by)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at;
+    `
+	err := r.Pool.QueryRow(ctx, query, f.Kind, f.Pattern, f.Action, f.CreatedBy).Scan(&f.ID, &f.CreatedAt)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("%s: %w", op, errors.ErrConflict)
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *FilterRepoPostgres) UpdateRule(ctx context.Context, f *entity.FilterRule) error {
+	const op = "FilterRepo.UpdateRule"
+	const query = `
+        UPDATE filter_rules SET pattern = $1, action = $2
+        WHERE id = $3
+        RETURNING kind, created_by, created_at;
+    `
+	err := r.Pool.QueryRow(ctx, query, f.Pattern, f.Action, f.ID).Scan(&f.Kind, &f.CreatedBy, &f.CreatedAt)
+	switch {
+	case err == pgx.ErrNoRows:
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	case isUniqueViolation(err):
+		return fmt.Errorf("%s: %w", op, errors.ErrConflict)
+	case err != nil:
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *FilterRepoPostgres) GetRule(ctx context.Context, id int64) (*entity.FilterRule, error) {
+	const op = "FilterRepo.GetRule"
+	const query = `SELECT id, kind, pattern, action, created_by, created_at FROM filter_rules WHERE id = $1;`
+
+	var f entity.FilterRule
+	err := r.Pool.QueryRow(ctx, query, id).Scan(&f.ID, &f.Kind, &f.Pattern, &f.Action, &f.CreatedBy, &f.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &f, nil
+}
+
+func (r *FilterRepoPostgres) DeleteRule(ctx context.Context, id int64) error {
+	const op = "FilterRepo.DeleteRule"
+	const query = `DELETE FROM filter_rules WHERE id = $1;`
+
+	tag, err := r.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *FilterRepoPostgres) GetSettings(ctx context.Context) (*entity.FilterSettings, error) {
+	const op = "FilterRepo.GetSettings"
+	const query = `
+        SELECT max_length, length_action, max_links, link_flood_action, unlisted_link_action,
+               duplicate_window_sec, duplicate_action, updated_by, updated_at
+        FROM filter_settings
+        WHERE id = 1;
+    `
+	var (
+		s      entity.FilterSettings
+		window int
+	)
+	err := r.Pool.QueryRow(ctx, query).Scan(
+		&s.MaxLength, &s.LengthAction, &s.MaxLinks, &s.LinkFloodAction, &s.UnlistedLinkAction,
+		&window, &s.DuplicateAction, &s.UpdatedBy, &s.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	s.DuplicateWindow = time.Duration(window) * time.Second
+	return &s, nil
+}
+
+func (r *FilterRepoPostgres) SaveSettings(ctx context.Context, s *entity.FilterSettings) error {
+	const op = "FilterRepo.SaveSettings"
+	const query = `
+        INSERT INTO filter_settings (id, max_length, length_action, max_links, link_flood_action,
+                                     unlisted_link_action, duplicate_window_sec, duplicate_action, updated_by, updated_at)
+        VALUES (1, $1, $2, $3, $4, $5, $6, $7, $8, now())
+        ON CONFLICT (id) DO UPDATE SET
+            max_length = EXCLUDED.max_length,
+            length_action = EXCLUDED.length_action,
+            max_links = EXCLUDED.max_links,
+            link_flood_action = EXCLUDED.link_flood_action,
+            unlisted_link_action = EXCLUDED.unlisted_link_action,
+            duplicate_window_sec = EXCLUDED.duplicate_window_sec,
+            duplicate_action = EXCLUDED.duplicate_action,
+            updated_by = EXCLUDED.updated_by,
+            updated_at = EXCLUDED.updated_at
+        RETURNING updated_at;
+    `
+	err := r.Pool.QueryRow(ctx, query,
+		s.MaxLength, s.LengthAction, s.MaxLinks, s.LinkFloodAction, s.UnlistedLinkAction,
+		int(s.DuplicateWindow/time.Second), s.DuplicateAction, s.UpdatedBy,
+	).Scan(&s.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"chat-service/internal/entity"
+	"chat-service/internal/errors"
+	"github.com/ZoyaDenisova/go-common/postgres"
+)
+
+type MentionRepoPostgres struct {
+	*postgres.Postgres
+}
+
+func NewMentionRepo(pg *postgres.Postgres) MentionRepository {
+	return &MentionRepoPostgres{pg}
+}
+
+// Replace оставляет у сообщения ровно упоминания userIDs и возвращает тех, кто упомянут впервые.
+// Несуществующий пользователь — errors.ErrInvalidReference.
+func (r *MentionRepoPostgres) Replace(ctx context.Context, messageID int64, userIDs []int64) ([]int64, error) {
+	const op = "MentionRepo.Replace"
+	const deleteQuery = `
+        DELETE FROM message_mentions
+        WHERE message_id = $1 AND user_id <> ALL ($2::bigint[]);
+    `
+	const insertQuery = `
+        INSERT INTO message_mentions (message_id, user_id)
+        SELECT $1, unnest($2::bigint[])
+        ON CONFLICT DO NOTHING
+        RETURNING user_id;
+    `
+	if userIDs == nil {
+		userIDs = []int64{}
+	}
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: begin: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }() // после Commit откат ничего не делает
+
+	if _, err := tx.Exec(ctx, deleteQuery, messageID, userIDs); err != nil {
+		return nil, fmt.Errorf("%s: delete: %w", op, err)
+	}
+
+	rows, err := tx.Query(ctx, insertQuery, messageID, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: insert: %w", op, err)
+	}
+	var added []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		added = append(added, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		if isFKViolation(err) {
+			return nil, fmt.Errorf("%s: %w", op, errors.ErrInvalidReference)
+		}
+		return nil, fmt.Errorf("%s: rows: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return added, nil
+}
+
+// ListForUser возвращает упоминания пользователя (новые сверху) и их общее число.
+// Удалённые и скрытые сообщения, как и сообщения удалённых топиков, не показываются.
+func (r *MentionRepoPostgres) ListForUser(ctx context.Context, userID int64, limit, offset int) ([]*entity.Mention, int64, error) {
+	const op = "MentionRepo.ListForUser"
+	const query = `
+        SELECT ` + messageColumns + `, t.title, mm.created_at, count(*) OVER () AS total
+        FROM message_mentions mm
+        JOIN messages m ON m.id = mm.message_id
+        JOIN users u ON u.id = m.author_id
+        JOIN topics t ON t.id = m.topic_id
+        WHERE mm.user_id = $1
+          AND m.deleted_at IS NULL AND m.hidden_at IS NULL
+          AND t.deleted_at IS NULL AND t.hidden_at IS NULL
+        ORDER BY mm.created_at DESC, mm.message_id DESC
+        LIMIT $2 OFFSET $3;
+    `
+	rows, err := r.Pool.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	var (
+		list  = make([]*entity.Mention, 0)
+		total int64
+	)
+	for rows.Next() {
+		m := &entity.Mention{Message: &entity.Message{}}
+		if err := scanMessage(rows, m.Message, &m.TopicTitle, &m.CreatedAt, &total); err != nil {
+			return nil, 0, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		list = append(list, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return list, total, nil
+}
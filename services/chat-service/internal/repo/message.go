@@ -19,20 +19,88 @@ func NewMessageRepo(pg *postgres.Postgres) MessageRepository {
 	return &MessageRepoPostgres{pg}
 }
 
+// messageColumns — общий список колонок для выборок сообщений (m — messages, u — users)
+const messageColumns = `
+        m.id, m.topic_id, m.author_id, u.name AS author_name, m.content, m.created_at,
+        m.deleted_at, m.deleted_by, COALESCE(m.delete_reason, ''), m.hidden_at IS NOT NULL, m.quote_id, m.score,
+        EXISTS (SELECT 1 FROM topics ta WHERE ta.id = m.topic_id AND ta.accepted_message_id = m.id)
+`
+
+// scanMessage – единое место, чтобы не дублировать Scan в выборках.
+// extra — колонки, которые выборка добавляет после messageColumns.
+func scanMessage(row pgx.Row, m *entity.Message, extra ...any) error {
+	return row.Scan(append([]any{
+		&m.ID,
+		&m.TopicID,
+		&m.AuthorID,
+		&m.AuthorName,
+		&m.Content,
+		&m.CreatedAt,
+		&m.DeletedAt,
+		&m.DeletedBy,
+		&m.DeleteReason,
+		&m.Hidden,
+		&m.QuoteID,
+		&m.Score,
+		&m.Accepted,
+	}, extra...)...)
+}
+
+// Create сохраняет сообщение и в той же транзакции привязывает к нему m.Attachments в их порядке.
+// Вложение уже привязано к другому сообщению или загружено не автором — errors.ErrConflict, сообщение не сохраняется.
 func (r *MessageRepoPostgres) Create(ctx context.Context, m *entity.Message) error {
 	const op = "MessageRepo.Create"
 	const query = `
-        INSERT INTO messages (topic_id, author_id, content, created_at)
-	    VALUES ($1, $2, $3, $4)
+        INSERT INTO messages (topic_id, author_id, content, created_at, quote_id)
+	    VALUES ($1, $2, $3, $4, $5)
 	    RETURNING id,
 	              (SELECT name FROM users WHERE id = $2) AS author_name;
     `
+	const attachQuery = `
+        UPDATE attachments
+        SET message_id = $1,
+            position   = array_position($3::bigint[], id)
+        WHERE id = ANY($3) AND uploader_id = $2 AND message_id IS NULL
+    `
+
+	if len(m.Attachments) == 0 {
+		if err := r.Pool.QueryRow(ctx, query,
+			m.TopicID, m.AuthorID, m.Content, m.CreatedAt, m.QuoteID,
+		).Scan(&m.ID, &m.AuthorName); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		return nil
+	}
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: begin: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }() // после Commit откат ничего не делает
 
-	if err := r.Pool.QueryRow(ctx, query,
-		m.TopicID, m.AuthorID, m.Content, m.CreatedAt,
+	if err := tx.QueryRow(ctx, query,
+		m.TopicID, m.AuthorID, m.Content, m.CreatedAt, m.QuoteID,
 	).Scan(&m.ID, &m.AuthorName); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	ids := make([]int64, 0, len(m.Attachments))
+	for _, a := range m.Attachments {
+		ids = append(ids, a.ID)
+	}
+	tag, err := tx.Exec(ctx, attachQuery, m.ID, m.AuthorID, ids)
+	if err != nil {
+		return fmt.Errorf("%s: attachments: %w", op, err)
+	}
+	if tag.RowsAffected() != int64(len(ids)) {
+		return fmt.Errorf("%s: attachments: %w", op, errors.ErrConflict)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	for _, a := range m.Attachments {
+		a.MessageID = &m.ID
+	}
 	return nil
 }
 
@@ -41,7 +109,7 @@ func (r *MessageRepoPostgres) Update(ctx context.Context, id int64, newContent s
 	const query = `
         UPDATE messages
         SET content = $2
-        WHERE id = $1
+        WHERE id = $1 AND deleted_at IS NULL
     `
 
 	tag, err := r.Pool.Exec(ctx, query, id, newContent)
@@ -54,11 +122,17 @@ func (r *MessageRepoPostgres) Update(ctx context.Context, id int64, newContent s
 	return nil
 }
 
-func (r *MessageRepoPostgres) Delete(ctx context.Context, id int64) error {
+func (r *MessageRepoPostgres) Delete(ctx context.Context, id, deletedBy int64, reason string) error {
 	const op = "MessageRepo.Delete"
-	const query = `DELETE FROM messages WHERE id = $1`
+	const query = `
+        UPDATE messages
+        SET deleted_at    = now(),
+            deleted_by    = $2,
+            delete_reason = NULLIF($3, '')
+        WHERE id = $1 AND deleted_at IS NULL
+    `
 
-	tag, err := r.Pool.Exec(ctx, query, id)
+	tag, err := r.Pool.Exec(ctx, query, id, deletedBy, reason)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -68,13 +142,37 @@ func (r *MessageRepoPostgres) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
+func (r *MessageRepoPostgres) Restore(ctx context.Context, id int64, since time.Time) error {
+	const op = "MessageRepo.Restore"
+	const query = `
+        UPDATE messages
+        SET deleted_at    = NULL,
+            deleted_by    = NULL,
+            delete_reason = NULL
+        WHERE id = $1 AND deleted_at >= $2
+    `
+
+	tag, err := r.Pool.Exec(ctx, query, id, since)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+	return nil
+}
+
+// GetByTopic возвращает сообщения топика вместе с tombstone-записями;
+// сообщения мягко удалённого топика не возвращаются. Для заглушки слитого топика
+// возвращаются сообщения топика, в который он слит.
 func (r *MessageRepoPostgres) GetByTopic(ctx context.Context, topicID int64) ([]*entity.Message, error) {
 	const op = "MessageRepo.GetByTopic"
 	const query = `
-        SELECT m.id, m.topic_id, m.author_id, u.name AS author_name, m.content, m.created_at
+        SELECT ` + messageColumns + `
         FROM messages m
         JOIN users u ON u.id = m.author_id
-        WHERE m.topic_id = $1
+        WHERE m.topic_id = COALESCE((SELECT redirect_to FROM topics WHERE id = $1), $1)
+          AND NOT EXISTS (SELECT 1 FROM topics t WHERE t.id = m.topic_id AND t.deleted_at IS NOT NULL)
         ORDER BY m.created_at
     `
 
@@ -87,7 +185,7 @@ func (r *MessageRepoPostgres) GetByTopic(ctx context.Context, topicID int64) ([]
 	var list []*entity.Message
 	for rows.Next() {
 		m := &entity.Message{}
-		if err := rows.Scan(&m.ID, &m.TopicID, &m.AuthorID, &m.AuthorName, &m.Content, &m.CreatedAt); err != nil {
+		if err := scanMessage(rows, m); err != nil {
 			return nil, fmt.Errorf("%s: scan: %w", op, err)
 		}
 		list = append(list, m)
@@ -101,15 +199,14 @@ func (r *MessageRepoPostgres) GetByTopic(ctx context.Context, topicID int64) ([]
 func (r *MessageRepoPostgres) GetByID(ctx context.Context, id int64) (*entity.Message, error) {
 	const op = "MessageRepo.GetByID"
 	const query = `
-        SELECT m.id, m.topic_id, m.author_id, u.name AS author_name, m.content, m.created_at
+        SELECT ` + messageColumns + `
         FROM messages m
         JOIN users u ON u.id = m.author_id
         WHERE m.id = $1
     `
 
 	m := &entity.Message{}
-	err := r.Pool.QueryRow(ctx, query, id).
-		Scan(&m.ID, &m.TopicID, &m.AuthorID, &m.AuthorName, &m.Content, &m.CreatedAt)
+	err := scanMessage(r.Pool.QueryRow(ctx, query, id), m)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
@@ -119,13 +216,145 @@ func (r *MessageRepoPostgres) GetByID(ctx context.Context, id int64) (*entity.Me
 	return m, nil
 }
 
-func (r *MessageRepoPostgres) DeleteOlderThan(ctx context.Context, threshold time.Time) error {
-	const op = "MessageRepo.DeleteOlderThan"
-	const query = `DELETE FROM messages WHERE created_at < $1`
+func (r *MessageRepoPostgres) GetDeleted(ctx context.Context, since time.Time) ([]*entity.Message, error) {
+	const op = "MessageRepo.GetDeleted"
+	const query = `
+        SELECT ` + messageColumns + `
+        FROM messages m
+        JOIN users u ON u.id = m.author_id
+        WHERE m.deleted_at >= $1
+        ORDER BY m.deleted_at DESC
+    `
 
-	_, err := r.Pool.Exec(ctx, query, threshold)
+	rows, err := r.Pool.Query(ctx, query, since)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: query: %w", op, err)
 	}
-	return nil
+	defer rows.Close()
+
+	var list []*entity.Message
+	for rows.Next() {
+		m := &entity.Message{}
+		if err := scanMessage(rows, m); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		list = append(list, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return list, nil
+}
+
+// expiredMessagesCTE выбирает сообщения, вышедшие за политику хранения.
+// Политика топика важнее политики категории; без политики действует глобальный порог $1.
+// $2 — текущее время, от которого считаются дни в режиме days.
+// В режиме last_n места занимают только живые сообщения: tombstone не вытесняет их
+// и сам удаляется по окну восстановления (PurgeDeleted), а не по этой политике.
+const expiredMessagesCTE = `
+        WITH policy AS (
+            SELECT t.id AS topic_id,
+                   t.category_id,
+                   COALESCE(t.retention_mode, c.retention_mode) AS mode,
+                   CASE WHEN t.retention_mode IS NOT NULL
+                        THEN t.retention_value
+                        ELSE c.retention_value
+                   END AS value
+              FROM topics t
+              LEFT JOIN categories c ON c.id = t.category_id
+        ),
+        ranked AS (
+            SELECT m.id, m.topic_id, p.category_id, p.mode, p.value, m.created_at, m.deleted_at,
+                   ROW_NUMBER() OVER (PARTITION BY m.topic_id, m.deleted_at IS NULL
+                                          ORDER BY m.created_at DESC, m.id DESC) AS rn
+              FROM messages m
+              JOIN policy p ON p.topic_id = m.topic_id
+             WHERE p.mode IS DISTINCT FROM 'forever'
+        ),
+        expired AS (
+            SELECT id, topic_id, category_id, COALESCE(mode, 'default') AS mode
+              FROM ranked
+             WHERE (mode IS NULL AND created_at < $1)
+                OR (mode = 'days' AND created_at < $2::timestamp - make_interval(days => value))
+                OR (mode = 'last_n' AND deleted_at IS NULL AND rn > value)
+        )
+`
+
+func (r *MessageRepoPostgres) CountExpired(ctx context.Context, threshold, now time.Time) ([]*entity.RetentionStat, error) {
+	const op = "MessageRepo.CountExpired"
+	const query = expiredMessagesCTE + `
+        SELECT topic_id, COALESCE(category_id, 0), mode, COUNT(*)
+          FROM expired
+         GROUP BY topic_id, category_id, mode
+         ORDER BY topic_id;
+    `
+
+	rows, err := r.Pool.Query(ctx, query, threshold, now)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	var list []*entity.RetentionStat
+	for rows.Next() {
+		st := &entity.RetentionStat{}
+		if err := rows.Scan(&st.TopicID, &st.CategoryID, &st.Mode, &st.Count); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		list = append(list, st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return list, nil
+}
+
+func (r *MessageRepoPostgres) DeleteExpired(ctx context.Context, threshold, now time.Time, limit int) (int64, error) {
+	const op = "MessageRepo.DeleteExpired"
+	const query = expiredMessagesCTE + `
+        DELETE FROM messages
+         WHERE id IN (SELECT id FROM expired ORDER BY id LIMIT $3);
+    `
+
+	tag, err := r.Pool.Exec(ctx, query, threshold, now, limit)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *MessageRepoPostgres) PurgeDeleted(ctx context.Context, threshold time.Time) (int64, error) {
+	const op = "MessageRepo.PurgeDeleted"
+	const query = `DELETE FROM messages WHERE deleted_at < $1`
+
+	tag, err := r.Pool.Exec(ctx, query, threshold)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *MessageRepoPostgres) RecentByAuthor(ctx context.Context, authorID int64, since time.Time, limit int) ([]string, error) {
+	const op = "MessageRepo.RecentByAuthor"
+	const query = `
+        SELECT content FROM messages
+        WHERE author_id = $1 AND created_at >= $2 AND deleted_at IS NULL
+        ORDER BY created_at DESC
+        LIMIT $3;
+    `
+	rows, err := r.Pool.Query(ctx, query, authorID, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var list []string
+	for rows.Next() {
+		var content string
+		if err := rows.Scan(&content); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		list = append(list, content)
+	}
+	return list, rows.Err()
 }
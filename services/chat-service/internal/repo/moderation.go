@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"chat-service/internal/entity"
+	"github.com/ZoyaDenisova/go-common/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type ModerationRepoPostgres struct {
+	*postgres.Postgres
+}
+
+func NewModerationRepo(pg *postgres.Postgres) ModerationRepository {
+	return &ModerationRepoPostgres{pg}
+}
+
+const insertModerationQuery = `
+        INSERT INTO moderation_log (actor_id, action, target_type, target_id, target_author_id,
+                                    reason, snapshot, details)
+        VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, '{}'::jsonb), COALESCE($8, '{}'::jsonb))
+        RETURNING id, created_at;
+`
+
+// saveModeration пишет запись журнала через pool или внутри транзакции другого репозитория
+func saveModeration(ctx context.Context, q interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}, e *entity.ModerationEntry) error {
+	return q.QueryRow(ctx, insertModerationQuery,
+		e.ActorID, e.Action, e.TargetType, e.TargetID, e.TargetAuthorID, e.Reason, e.Snapshot, e.Details,
+	).Scan(&e.ID, &e.CreatedAt)
+}
+
+func (r *ModerationRepoPostgres) Save(ctx context.Context, e *entity.ModerationEntry) error {
+	const op = "ModerationRepo.Save"
+	if err := saveModeration(ctx, r.Pool, e); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// List возвращает записи по фильтру (новые сверху) и общее число подходящих записей
+func (r *ModerationRepoPostgres) List(ctx context.Context, f entity.ModerationFilter) ([]*entity.ModerationEntry, int64, error) {
+	const op = "ModerationRepo.List"
+	const query = `
+        SELECT id, actor_id, action, target_type, target_id, target_author_id, reason,
+               snapshot, details, created_at, count(*) OVER () AS total
+        FROM moderation_log
+        WHERE ($1 = 0 OR actor_id = $1)
+          AND ($2 = 0 OR target_author_id = $2)
+          AND ($3 = '' OR target_type = $3)
+          AND ($4 = 0 OR target_id = $4)
+          AND ($5 = '' OR action = $5)
+        ORDER BY created_at DESC, id DESC
+        LIMIT $6 OFFSET $7;
+    `
+	rows, err := r.Pool.Query(ctx, query,
+		f.ActorID, f.TargetAuthorID, f.TargetType, f.TargetID, f.Action, f.Limit, f.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	var (
+		list  = make([]*entity.ModerationEntry, 0)
+		total int64
+	)
+	for rows.Next() {
+		var e entity.ModerationEntry
+		if err := rows.Scan(&e.ID, &e.ActorID, &e.Action, &e.TargetType, &e.TargetID, &e.TargetAuthorID,
+			&e.Reason, &e.Snapshot, &e.Details, &e.CreatedAt, &total); err != nil {
+			return nil, 0, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		list = append(list, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return list, total, nil
+}
@@ -0,0 +1,41 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ZoyaDenisova/go-common/postgres"
+)
+
+type ModeratorRepoPostgres struct {
+	*postgres.Postgres
+}
+
+func NewModeratorRepo(pg *postgres.Postgres) ModeratorRepository {
+	return &ModeratorRepoPostgres{pg}
+}
+
+// IsModerator проверяет назначение на саму категорию или на любого её предка
+func (r *ModeratorRepoPostgres) IsModerator(ctx context.Context, userID, categoryID int64) (bool, error) {
+	const op = "ModeratorRepo.IsModerator"
+	const query = `
+        WITH RECURSIVE ancestors AS (
+            SELECT id, parent_id FROM categories WHERE id = $2
+            UNION ALL
+            SELECT c.id, c.parent_id
+              FROM categories c
+              JOIN ancestors a ON c.id = a.parent_id
+        )
+        SELECT EXISTS (
+            SELECT 1
+              FROM category_moderators cm
+              JOIN ancestors a ON a.id = cm.category_id
+             WHERE cm.user_id = $1
+        );
+    `
+	var ok bool
+	if err := r.Pool.QueryRow(ctx, query, userID, categoryID).Scan(&ok); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return ok, nil
+}
@@ -0,0 +1,133 @@
+package repo
+
+import (
+	"chat-service/internal/entity"
+	"chat-service/internal/errors"
+	"context"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/postgres"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+type MuteRepoPostgres struct {
+	*postgres.Postgres
+}
+
+func NewMuteRepo(pg *postgres.Postgres) MuteRepository {
+	return &MuteRepoPostgres{pg}
+}
+
+const muteColumns = `id, user_id, topic_id, category_id, reason, muted_by, expires_at, created_at`
+
+func scanMute(row pgx.Row, m *entity.Mute) error {
+	return row.Scan(&m.ID, &m.UserID, &m.TopicID, &m.CategoryID, &m.Reason, &m.MutedBy, &m.ExpiresAt, &m.CreatedAt)
+}
+
+func (r *MuteRepoPostgres) Create(ctx context.Context, m *entity.Mute) error {
+	const op = "MuteRepo.Create"
+	const query = `
+        INSERT INTO mutes (user_id, topic_id, category_id, reason, muted_by, expires_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, created_at;
+    `
+	err := r.Pool.QueryRow(ctx, query, m.UserID, m.TopicID, m.CategoryID, m.Reason, m.MutedBy, m.ExpiresAt).
+		Scan(&m.ID, &m.CreatedAt)
+	if isFKViolation(err) {
+		return fmt.Errorf("%s: %w", op, errors.ErrInvalidReference)
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *MuteRepoPostgres) GetByID(ctx context.Context, id int64) (*entity.Mute, error) {
+	const op = "MuteRepo.GetByID"
+	query := `SELECT ` + muteColumns + ` FROM mutes WHERE id = $1;`
+
+	var m entity.Mute
+	if err := scanMute(r.Pool.QueryRow(ctx, query, id), &m); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &m, nil
+}
+
+func (r *MuteRepoPostgres) Delete(ctx context.Context, id int64) error {
+	const op = "MuteRepo.Delete"
+	const query = `DELETE FROM mutes WHERE id = $1;`
+
+	tag, err := r.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *MuteRepoPostgres) ListActive(ctx context.Context, userID int64) ([]*entity.Mute, error) {
+	const op = "MuteRepo.ListActive"
+	query := `
+        SELECT ` + muteColumns + `
+        FROM mutes
+        WHERE ($1 = 0 OR user_id = $1) AND (expires_at IS NULL OR expires_at > now())
+        ORDER BY created_at DESC;
+    `
+	rows, err := r.Pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var list []*entity.Mute
+	for rows.Next() {
+		var m entity.Mute
+		if err := scanMute(rows, &m); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		list = append(list, &m)
+	}
+	return list, rows.Err()
+}
+
+func (r *MuteRepoPostgres) FindActive(ctx context.Context, userID, topicID, categoryID int64) (*entity.Mute, error) {
+	const op = "MuteRepo.FindActive"
+	// мьют категории действует и во всех её подкатегориях; из нескольких берём самый долгий
+	query := `
+        WITH RECURSIVE ancestors AS (
+            SELECT id, parent_id FROM categories WHERE id = $3
+            UNION ALL
+            SELECT c.id, c.parent_id FROM categories c JOIN ancestors a ON c.id = a.parent_id
+        )
+        SELECT ` + muteColumns + `
+        FROM mutes
+        WHERE user_id = $1
+          AND (expires_at IS NULL OR expires_at > now())
+          AND (topic_id = $2 OR category_id IN (SELECT id FROM ancestors))
+        ORDER BY expires_at DESC NULLS FIRST
+        LIMIT 1;
+    `
+	var m entity.Mute
+	if err := scanMute(r.Pool.QueryRow(ctx, query, userID, topicID, categoryID), &m); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &m, nil
+}
+
+func (r *MuteRepoPostgres) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	const op = "MuteRepo.DeleteExpired"
+	const query = `DELETE FROM mutes WHERE expires_at IS NOT NULL AND expires_at <= $1;`
+
+	tag, err := r.Pool.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return tag.RowsAffected(), nil
+}
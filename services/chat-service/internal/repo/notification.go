@@ -0,0 +1,112 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"chat-service/internal/entity"
+	"chat-service/internal/errors"
+	"github.com/ZoyaDenisova/go-common/postgres"
+)
+
+type NotificationRepoPostgres struct {
+	*postgres.Postgres
+}
+
+func NewNotificationRepo(pg *postgres.Postgres) NotificationRepository {
+	return &NotificationRepoPostgres{pg}
+}
+
+func (r *NotificationRepoPostgres) Create(ctx context.Context, n *entity.Notification) error {
+	const op = "NotificationRepo.Create"
+	const query = `
+        INSERT INTO notifications (user_id, type, actor_id, topic_id, message_id, data)
+        VALUES ($1, $2, $3, $4, $5, COALESCE($6, '{}'::jsonb))
+        RETURNING id, created_at;
+    `
+	err := r.Pool.QueryRow(ctx, query, n.UserID, n.Type, n.ActorID, n.TopicID, n.MessageID, n.Data).
+		Scan(&n.ID, &n.CreatedAt)
+	if isFKViolation(err) {
+		return fmt.Errorf("%s: %w", op, errors.ErrInvalidReference)
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// List возвращает уведомления пользователя (новые сверху) и общее число подходящих
+func (r *NotificationRepoPostgres) List(ctx context.Context, f entity.NotificationFilter) ([]*entity.Notification, int64, error) {
+	const op = "NotificationRepo.List"
+	const query = `
+        SELECT n.id, n.user_id, n.type, n.actor_id, COALESCE(u.name, ''), n.topic_id, n.message_id,
+               n.data, n.read_at, n.created_at, count(*) OVER () AS total
+        FROM notifications n
+        LEFT JOIN users u ON u.id = n.actor_id
+        WHERE n.user_id = $1
+          AND (NOT $2 OR n.read_at IS NULL)
+        ORDER BY n.created_at DESC, n.id DESC
+        LIMIT $3 OFFSET $4;
+    `
+	rows, err := r.Pool.Query(ctx, query, f.UserID, f.UnreadOnly, f.Limit, f.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	var (
+		list  = make([]*entity.Notification, 0)
+		total int64
+	)
+	for rows.Next() {
+		var n entity.Notification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Type, &n.ActorID, &n.ActorName, &n.TopicID, &n.MessageID,
+			&n.Data, &n.ReadAt, &n.CreatedAt, &total); err != nil {
+			return nil, 0, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		list = append(list, &n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return list, total, nil
+}
+
+func (r *NotificationRepoPostgres) CountUnread(ctx context.Context, userID int64) (int64, error) {
+	const op = "NotificationRepo.CountUnread"
+	const query = `SELECT count(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL;`
+
+	var n int64
+	if err := r.Pool.QueryRow(ctx, query, userID).Scan(&n); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return n, nil
+}
+
+// MarkRead отмечает уведомление прочитанным; повторная отметка ничего не меняет
+func (r *NotificationRepoPostgres) MarkRead(ctx context.Context, userID, id int64) error {
+	const op = "NotificationRepo.MarkRead"
+	const query = `
+        UPDATE notifications
+        SET read_at = COALESCE(read_at, now())
+        WHERE id = $1 AND user_id = $2
+    `
+	tag, err := r.Pool.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *NotificationRepoPostgres) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	const op = "NotificationRepo.MarkAllRead"
+	const query = `UPDATE notifications SET read_at = now() WHERE user_id = $1 AND read_at IS NULL;`
+
+	tag, err := r.Pool.Exec(ctx, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return tag.RowsAffected(), nil
+}
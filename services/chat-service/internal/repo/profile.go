@@ -0,0 +1,160 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"chat-service/internal/entity"
+	"chat-service/internal/errors"
+	"github.com/ZoyaDenisova/go-common/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type ProfileRepoPostgres struct {
+	*postgres.Postgres
+}
+
+func NewProfileRepo(pg *postgres.Postgres) ProfileRepository {
+	return &ProfileRepoPostgres{pg}
+}
+
+func (r *ProfileRepoPostgres) Get(ctx context.Context, userID int64) (*entity.Profile, error) {
+	const op = "ProfileRepo.Get"
+	const query = `
+        SELECT u.id, u.username, u.name, u.created_at,
+               COALESCE(p.bio, ''), COALESCE(p.location, ''), COALESCE(p.website, ''), COALESCE(p.signature, ''),
+               COALESCE(p.avatar_key, ''), p.avatar_updated_at
+        FROM users u
+        LEFT JOIN user_profiles p ON p.user_id = u.id
+        WHERE u.id = $1;
+    `
+	p := &entity.Profile{}
+	err := r.Pool.QueryRow(ctx, query, userID).Scan(&p.UserID, &p.Username, &p.Name, &p.JoinedAt,
+		&p.Bio, &p.Location, &p.Website, &p.Signature, &p.AvatarKey, &p.AvatarUpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return p, nil
+}
+
+func (r *ProfileRepoPostgres) UserIDByUsername(ctx context.Context, username string) (int64, error) {
+	const op = "ProfileRepo.UserIDByUsername"
+	const query = `SELECT id FROM users WHERE lower(username) = lower($1);`
+
+	var id int64
+	if err := r.Pool.QueryRow(ctx, query, username).Scan(&id); err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return id, nil
+}
+
+// Save сохраняет текстовые поля профиля; аватар меняется только через SetAvatar
+func (r *ProfileRepoPostgres) Save(ctx context.Context, p *entity.Profile) error {
+	const op = "ProfileRepo.Save"
+	const query = `
+        INSERT INTO user_profiles (user_id, bio, location, website, signature)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (user_id) DO UPDATE
+            SET bio = EXCLUDED.bio, location = EXCLUDED.location, website = EXCLUDED.website,
+                signature = EXCLUDED.signature, updated_at = now();
+    `
+	_, err := r.Pool.Exec(ctx, query, p.UserID, p.Bio, p.Location, p.Website, p.Signature)
+	if isFKViolation(err) {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *ProfileRepoPostgres) SetAvatar(ctx context.Context, userID int64, key string) (string, error) {
+	const op = "ProfileRepo.SetAvatar"
+	const selectQuery = `SELECT COALESCE(avatar_key, '') FROM user_profiles WHERE user_id = $1 FOR UPDATE;`
+	const upsertQuery = `
+        INSERT INTO user_profiles (user_id, avatar_key, avatar_updated_at)
+        VALUES ($1, NULLIF($2, ''), CASE WHEN $2 = '' THEN NULL ELSE now() END)
+        ON CONFLICT (user_id) DO UPDATE
+            SET avatar_key = EXCLUDED.avatar_key, avatar_updated_at = EXCLUDED.avatar_updated_at, updated_at = now();
+    `
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%s: begin: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }() // после Commit откат ничего не делает
+
+	var previous string
+	if err := tx.QueryRow(ctx, selectQuery, userID).Scan(&previous); err != nil && err != pgx.ErrNoRows {
+		return "", fmt.Errorf("%s: select: %w", op, err)
+	}
+	if _, err := tx.Exec(ctx, upsertQuery, userID, key); isFKViolation(err) {
+		return "", fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	} else if err != nil {
+		return "", fmt.Errorf("%s: upsert: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return previous, nil
+}
+
+func (r *ProfileRepoPostgres) Stats(ctx context.Context, userID int64) (*entity.ProfileStats, error) {
+	const op = "ProfileRepo.Stats"
+	const query = `
+        SELECT (SELECT count(*)
+                FROM topics t
+                WHERE t.author_id = $1 AND t.deleted_at IS NULL AND t.hidden_at IS NULL AND t.redirect_to IS NULL),
+               count(m.id), max(m.created_at),
+               COALESCE((SELECT reputation FROM user_reputation WHERE user_id = $1), 0)
+        FROM messages m
+        JOIN topics t ON t.id = m.topic_id
+        WHERE m.author_id = $1
+          AND m.deleted_at IS NULL AND m.hidden_at IS NULL
+          AND t.deleted_at IS NULL AND t.hidden_at IS NULL;
+    `
+	s := &entity.ProfileStats{}
+	if err := r.Pool.QueryRow(ctx, query, userID).Scan(&s.Topics, &s.Messages, &s.LastActiveAt, &s.Reputation); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return s, nil
+}
+
+// RecentActivity кладёт в Excerpt текст сообщения целиком; обрезает его usecase
+func (r *ProfileRepoPostgres) RecentActivity(ctx context.Context, userID int64, limit int) ([]*entity.ProfileActivity, error) {
+	const op = "ProfileRepo.RecentActivity"
+	const query = `
+        SELECT m.id, m.topic_id, t.title, m.content, m.created_at
+        FROM messages m
+        JOIN topics t ON t.id = m.topic_id
+        WHERE m.author_id = $1
+          AND m.deleted_at IS NULL AND m.hidden_at IS NULL
+          AND t.deleted_at IS NULL AND t.hidden_at IS NULL
+        ORDER BY m.created_at DESC, m.id DESC
+        LIMIT $2;
+    `
+	rows, err := r.Pool.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	list := make([]*entity.ProfileActivity, 0)
+	for rows.Next() {
+		a := &entity.ProfileActivity{}
+		if err := rows.Scan(&a.MessageID, &a.TopicID, &a.TopicTitle, &a.Excerpt, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		list = append(list, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return list, nil
+}
@@ -0,0 +1,67 @@
+package repo
+
+import (
+	"chat-service/internal/ratelimit"
+	"context"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/postgres"
+	"time"
+)
+
+// RateLimitRepoPostgres — ratelimit.Store в общей базе: лимиты едины для всех экземпляров сервиса
+type RateLimitRepoPostgres struct {
+	*postgres.Postgres
+}
+
+func NewRateLimitRepo(pg *postgres.Postgres) ratelimit.Store {
+	return &RateLimitRepoPostgres{pg}
+}
+
+func (r *RateLimitRepoPostgres) Take(ctx context.Context, key string, l ratelimit.Limit, now time.Time) (ratelimit.Result, error) {
+	const op = "RateLimitRepo.Take"
+	// новое ведро создаётся полным; затем строка блокируется, чтобы параллельные запросы не взяли один токен дважды
+	const insertQuery = `
+        INSERT INTO rate_limits (key, tokens, updated_at)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (key) DO NOTHING;
+    `
+	const selectQuery = `SELECT tokens, updated_at FROM rate_limits WHERE key = $1 FOR UPDATE;`
+	const updateQuery = `UPDATE rate_limits SET tokens = $2, updated_at = $3 WHERE key = $1;`
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return ratelimit.Result{}, fmt.Errorf("%s: begin: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }() // после Commit откат ничего не делает
+
+	full := ratelimit.Full(l, now)
+	if _, err := tx.Exec(ctx, insertQuery, key, full.Tokens, full.Updated); err != nil {
+		return ratelimit.Result{}, fmt.Errorf("%s: insert: %w", op, err)
+	}
+
+	var st ratelimit.State
+	if err := tx.QueryRow(ctx, selectQuery, key).Scan(&st.Tokens, &st.Updated); err != nil {
+		return ratelimit.Result{}, fmt.Errorf("%s: select: %w", op, err)
+	}
+
+	st, res := ratelimit.Take(st, l, now)
+	if _, err := tx.Exec(ctx, updateQuery, key, st.Tokens, st.Updated); err != nil {
+		return ratelimit.Result{}, fmt.Errorf("%s: update: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ratelimit.Result{}, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return res, nil
+}
+
+func (r *RateLimitRepoPostgres) Sweep(ctx context.Context, before time.Time) (int64, error) {
+	const op = "RateLimitRepo.Sweep"
+	const query = `DELETE FROM rate_limits WHERE updated_at < $1;`
+
+	tag, err := r.Pool.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return tag.RowsAffected(), nil
+}
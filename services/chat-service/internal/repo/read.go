@@ -0,0 +1,158 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"chat-service/internal/entity"
+	"chat-service/internal/errors"
+	"github.com/ZoyaDenisova/go-common/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type ReadRepoPostgres struct {
+	*postgres.Postgres
+}
+
+func NewReadRepo(pg *postgres.Postgres) ReadRepository {
+	return &ReadRepoPostgres{pg}
+}
+
+// непрочитанными считаются видимые чужие сообщения после позиции; $1 — читатель
+const unreadMessageJoin = `
+        JOIN messages m ON m.topic_id = r.topic_id AND m.id > r.last_read_message_id
+                       AND m.deleted_at IS NULL AND m.hidden_at IS NULL AND m.author_id <> $1`
+
+const visibleTopicJoin = `
+        JOIN topics t ON t.id = r.topic_id
+                     AND t.deleted_at IS NULL AND t.redirect_to IS NULL AND t.hidden_at IS NULL`
+
+// MarkRead сдвигает позицию вперёд до messageID (nil — до последнего сообщения топика) и возвращает,
+// что получилось, вместе с числом оставшихся непрочитанных
+func (r *ReadRepoPostgres) MarkRead(ctx context.Context, userID, topicID int64, messageID *int64) (*entity.TopicRead, error) {
+	const op = "ReadRepo.MarkRead"
+	const query = `
+        WITH pos AS (
+            INSERT INTO topic_reads (user_id, topic_id, last_read_message_id)
+            SELECT $1, $2, COALESCE(p.id, 0)
+            FROM (
+                SELECT MAX(m.id) AS id
+                FROM messages m
+                WHERE m.topic_id = $2 AND m.deleted_at IS NULL
+                  AND ($3::integer IS NULL OR m.id = $3::integer)
+            ) p
+            WHERE $3::integer IS NULL OR p.id IS NOT NULL
+            ON CONFLICT (user_id, topic_id) DO UPDATE
+                SET last_read_message_id = GREATEST(topic_reads.last_read_message_id, EXCLUDED.last_read_message_id),
+                    updated_at           = now()
+            RETURNING last_read_message_id, updated_at
+        )
+        SELECT pos.last_read_message_id, pos.updated_at,
+               (SELECT COUNT(*)
+                FROM messages m
+                WHERE m.topic_id = $2 AND m.id > pos.last_read_message_id
+                  AND m.deleted_at IS NULL AND m.hidden_at IS NULL AND m.author_id <> $1)
+        FROM pos;
+    `
+	rd := &entity.TopicRead{UserID: userID, TopicID: topicID}
+	err := r.Pool.QueryRow(ctx, query, userID, topicID, messageID).
+		Scan(&rd.LastReadMessageID, &rd.UpdatedAt, &rd.Unread)
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	} else if isFKViolation(err) {
+		return nil, fmt.Errorf("%s: %w", op, errors.ErrInvalidReference)
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return rd, nil
+}
+
+func (r *ReadRepoPostgres) GetByTopics(ctx context.Context, userID int64, topicIDs []int64) (map[int64]*entity.TopicRead, error) {
+	const op = "ReadRepo.GetByTopics"
+	const query = `
+        SELECT r.topic_id, r.last_read_message_id, r.updated_at, COUNT(m.id)
+        FROM topic_reads r
+        LEFT JOIN messages m ON m.topic_id = r.topic_id AND m.id > r.last_read_message_id
+                            AND m.deleted_at IS NULL AND m.hidden_at IS NULL AND m.author_id <> $1
+        WHERE r.user_id = $1 AND r.topic_id = ANY($2)
+        GROUP BY r.topic_id, r.last_read_message_id, r.updated_at;
+    `
+	rows, err := r.Pool.Query(ctx, query, userID, topicIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	res := make(map[int64]*entity.TopicRead, len(topicIDs))
+	for rows.Next() {
+		rd := &entity.TopicRead{UserID: userID}
+		if err := rows.Scan(&rd.TopicID, &rd.LastReadMessageID, &rd.UpdatedAt, &rd.Unread); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		res[rd.TopicID] = rd
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return res, nil
+}
+
+func (r *ReadRepoPostgres) UnreadByCategory(ctx context.Context, userID int64) ([]*entity.CategoryUnread, error) {
+	const op = "ReadRepo.UnreadByCategory"
+	const query = `
+        SELECT c.id, c.title, COUNT(DISTINCT t.id), COUNT(m.id)
+        FROM topic_reads r` + visibleTopicJoin + `
+        JOIN categories c ON c.id = t.category_id` + unreadMessageJoin + `
+        WHERE r.user_id = $1
+        GROUP BY c.id, c.title, c.position
+        ORDER BY c.position, c.title;
+    `
+	rows, err := r.Pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	list := make([]*entity.CategoryUnread, 0)
+	for rows.Next() {
+		var cu entity.CategoryUnread
+		if err := rows.Scan(&cu.CategoryID, &cu.Title, &cu.Topics, &cu.Messages); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		list = append(list, &cu)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return list, nil
+}
+
+func (r *ReadRepoPostgres) UnreadTopics(ctx context.Context, userID int64, limit int) ([]*entity.UnreadTopic, error) {
+	const op = "ReadRepo.UnreadTopics"
+	const query = `
+        SELECT t.id, t.category_id, t.title, r.last_read_message_id, COUNT(m.id), MAX(m.created_at)
+        FROM topic_reads r` + visibleTopicJoin + unreadMessageJoin + `
+        WHERE r.user_id = $1
+        GROUP BY t.id, t.category_id, t.title, r.last_read_message_id
+        ORDER BY MAX(m.created_at) DESC, t.id DESC
+        LIMIT $2;
+    `
+	rows, err := r.Pool.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	list := make([]*entity.UnreadTopic, 0)
+	for rows.Next() {
+		var ut entity.UnreadTopic
+		if err := rows.Scan(&ut.TopicID, &ut.CategoryID, &ut.Title, &ut.LastReadMessageID, &ut.Unread, &ut.LastMessageAt); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		list = append(list, &ut)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return list, nil
+}
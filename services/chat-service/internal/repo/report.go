@@ -0,0 +1,231 @@
+package repo
+
+import (
+	"chat-service/internal/entity"
+	"chat-service/internal/errors"
+	"context"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type ReportRepoPostgres struct {
+	*postgres.Postgres
+}
+
+func NewReportRepo(pg *postgres.Postgres) ReportRepository {
+	return &ReportRepoPostgres{pg}
+}
+
+// reportColumns — общий список колонок для выборок жалоб (r — reports, m — messages, t — топик объекта)
+const reportColumns = `
+        r.id,
+        CASE WHEN r.message_id IS NOT NULL THEN 'message' ELSE 'topic' END,
+        COALESCE(r.message_id, r.topic_id),
+        COALESCE(r.reporter_id, 0), r.reason, r.comment, r.state, r.resolved_by, r.resolved_at, r.resolution, r.created_at,
+        COALESCE(m.author_id, t.author_id), t.id, t.category_id,
+        (SELECT count(*) FROM reports o
+          WHERE o.state = 'open' AND (o.message_id = r.message_id OR o.topic_id = r.topic_id)),
+        CASE WHEN r.message_id IS NOT NULL THEN m.hidden_at IS NOT NULL ELSE t.hidden_at IS NOT NULL END,
+        COALESCE(m.content, t.title)
+`
+
+const reportFrom = `
+        FROM reports r
+        LEFT JOIN messages m ON m.id = r.message_id
+        JOIN topics t ON t.id = COALESCE(m.topic_id, r.topic_id)
+`
+
+func scanReport(row pgx.Row, r *entity.Report) error {
+	return row.Scan(
+		&r.ID, &r.TargetType, &r.TargetID,
+		&r.ReporterID, &r.Reason, &r.Comment, &r.State, &r.ResolvedBy, &r.ResolvedAt, &r.Resolution, &r.CreatedAt,
+		&r.TargetAuthorID, &r.TopicID, &r.CategoryID, &r.OpenReports, &r.TargetHidden, &r.Excerpt,
+	)
+}
+
+// reportTarget раскладывает объект жалобы по колонкам message_id / topic_id
+func reportTarget(targetType string, id int64) (messageID, topicID *int64) {
+	if targetType == entity.TargetMessage {
+		return &id, nil
+	}
+	return nil, &id
+}
+
+func (r *ReportRepoPostgres) Create(ctx context.Context, rep *entity.Report, hideAfter int) (bool, error) {
+	const op = "ReportRepo.Create"
+	// объект должен существовать и не быть удалённым; заодно узнаём автора и топик
+	const messageTargetQuery = `
+        SELECT m.author_id, m.topic_id
+        FROM messages m
+        JOIN topics t ON t.id = m.topic_id
+        WHERE m.id = $1 AND m.deleted_at IS NULL AND t.deleted_at IS NULL;
+    `
+	const topicTargetQuery = `
+        SELECT author_id, id
+        FROM topics
+        WHERE id = $1 AND deleted_at IS NULL AND redirect_to IS NULL;
+    `
+	const insertQuery = `
+        INSERT INTO reports (message_id, topic_id, reporter_id, reason, comment)
+        VALUES ($1, $2, NULLIF($3, 0), $4, $5)
+        ON CONFLICT DO NOTHING
+        RETURNING id, state, created_at;
+    `
+	const countQuery = `
+        SELECT count(*) FROM reports
+        WHERE state = 'open' AND (message_id = $1 OR topic_id = $2);
+    `
+	const hideMessageQuery = `UPDATE messages SET hidden_at = now() WHERE id = $1 AND hidden_at IS NULL;`
+	const hideTopicQuery = `UPDATE topics SET hidden_at = now() WHERE id = $1 AND hidden_at IS NULL;`
+
+	targetQuery, hideQuery := topicTargetQuery, hideTopicQuery
+	if rep.TargetType == entity.TargetMessage {
+		targetQuery, hideQuery = messageTargetQuery, hideMessageQuery
+	}
+	messageID, topicID := reportTarget(rep.TargetType, rep.TargetID)
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%s: begin: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }() // после Commit откат ничего не делает
+
+	if err := tx.QueryRow(ctx, targetQuery, rep.TargetID).Scan(&rep.TargetAuthorID, &rep.TopicID); err != nil {
+		if err == pgx.ErrNoRows {
+			return false, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+		}
+		return false, fmt.Errorf("%s: target: %w", op, err)
+	}
+
+	err = tx.QueryRow(ctx, insertQuery, messageID, topicID, rep.ReporterID, rep.Reason, rep.Comment).
+		Scan(&rep.ID, &rep.State, &rep.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return false, fmt.Errorf("%s: already reported: %w", op, errors.ErrConflict)
+	} else if err != nil {
+		return false, fmt.Errorf("%s: insert: %w", op, err)
+	}
+
+	if err := tx.QueryRow(ctx, countQuery, messageID, topicID).Scan(&rep.OpenReports); err != nil {
+		return false, fmt.Errorf("%s: count: %w", op, err)
+	}
+
+	hidden := false
+	if hideAfter > 0 && rep.OpenReports >= int64(hideAfter) {
+		tag, err := tx.Exec(ctx, hideQuery, rep.TargetID)
+		if err != nil {
+			return false, fmt.Errorf("%s: hide: %w", op, err)
+		}
+		hidden = tag.RowsAffected() > 0
+		rep.TargetHidden = true
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return hidden, nil
+}
+
+func (r *ReportRepoPostgres) GetByID(ctx context.Context, id int64) (*entity.Report, error) {
+	const op = "ReportRepo.GetByID"
+	const query = `SELECT ` + reportColumns + reportFrom + ` WHERE r.id = $1;`
+
+	rep := &entity.Report{}
+	if err := scanReport(r.Pool.QueryRow(ctx, query, id), rep); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: scan: %w", op, err)
+	}
+	return rep, nil
+}
+
+// List для модератора оставляет только жалобы из назначенных ему категорий и их подкатегорий
+func (r *ReportRepoPostgres) List(ctx context.Context, f entity.ReportFilter) ([]*entity.Report, int64, error) {
+	const op = "ReportRepo.List"
+	const query = `
+        WITH RECURSIVE moderated AS (
+            SELECT category_id AS id FROM category_moderators WHERE user_id = $1
+            UNION
+            SELECT c.id FROM categories c JOIN moderated md ON c.parent_id = md.id
+        )
+        SELECT ` + reportColumns + `, count(*) OVER () AS total` + reportFrom + `
+        WHERE ($1 = 0 OR t.category_id IN (SELECT id FROM moderated))
+          AND ($2 = '' OR r.state = $2)
+          AND ($3 = '' OR ($3 = 'message') = (r.message_id IS NOT NULL))
+        ORDER BY r.created_at, r.id
+        LIMIT $4 OFFSET $5;
+    `
+
+	rows, err := r.Pool.Query(ctx, query, f.ModeratorID, f.State, f.TargetType, f.Limit, f.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	var (
+		list  = make([]*entity.Report, 0)
+		total int64
+	)
+	for rows.Next() {
+		rep := &entity.Report{}
+		if err := rows.Scan(
+			&rep.ID, &rep.TargetType, &rep.TargetID,
+			&rep.ReporterID, &rep.Reason, &rep.Comment, &rep.State, &rep.ResolvedBy, &rep.ResolvedAt, &rep.Resolution, &rep.CreatedAt,
+			&rep.TargetAuthorID, &rep.TopicID, &rep.CategoryID, &rep.OpenReports, &rep.TargetHidden, &rep.Excerpt,
+			&total,
+		); err != nil {
+			return nil, 0, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		list = append(list, rep)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return list, total, nil
+}
+
+func (r *ReportRepoPostgres) Resolve(ctx context.Context, targetType string, targetID int64, state string, resolvedBy int64, resolution string) (int64, error) {
+	const op = "ReportRepo.Resolve"
+	const resolveQuery = `
+        UPDATE reports
+        SET state       = $3,
+            resolved_by = $4,
+            resolved_at = now(),
+            resolution  = $5
+        WHERE state = 'open' AND (message_id = $1 OR topic_id = $2);
+    `
+	const unhideMessageQuery = `UPDATE messages SET hidden_at = NULL WHERE id = $1;`
+	const unhideTopicQuery = `UPDATE topics SET hidden_at = NULL WHERE id = $1;`
+
+	messageID, topicID := reportTarget(targetType, targetID)
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: begin: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }() // после Commit откат ничего не делает
+
+	tag, err := tx.Exec(ctx, resolveQuery, messageID, topicID, state, resolvedBy, resolution)
+	if err != nil {
+		return 0, fmt.Errorf("%s: resolve: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return 0, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+
+	if state == entity.ReportDismissed {
+		unhide := unhideTopicQuery
+		if targetType == entity.TargetMessage {
+			unhide = unhideMessageQuery
+		}
+		if _, err := tx.Exec(ctx, unhide, targetID); err != nil {
+			return 0, fmt.Errorf("%s: unhide: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return tag.RowsAffected(), nil
+}
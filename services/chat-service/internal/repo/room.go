@@ -0,0 +1,341 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"chat-service/internal/entity"
+	"chat-service/internal/errors"
+	"github.com/ZoyaDenisova/go-common/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type RoomRepoPostgres struct {
+	*postgres.Postgres
+}
+
+func NewRoomRepo(pg *postgres.Postgres) RoomRepository {
+	return &RoomRepoPostgres{pg}
+}
+
+const roomColumns = `
+        id, slug, title, description, position,
+        COALESCE(retention_mode, 'default'), COALESCE(retention_value, 0),
+        rate_burst, rate_period_seconds, created_by, created_at`
+
+func scanRoom(row pgx.Row, rm *entity.Room) error {
+	var mode string
+	var period int
+	if err := row.Scan(&rm.ID, &rm.Slug, &rm.Title, &rm.Description, &rm.Position,
+		&mode, &rm.Retention.Value, &rm.RateBurst, &period, &rm.CreatedBy, &rm.CreatedAt); err != nil {
+		return err
+	}
+	rm.Retention.Mode = entity.RetentionMode(mode)
+	rm.RatePeriod = time.Duration(period) * time.Second
+	return nil
+}
+
+// текст удалённого сообщения не отдаётся никому
+const roomMessageColumns = `
+        m.id, m.room_id, m.author_id, COALESCE(u.name, ''),
+        CASE WHEN m.deleted_at IS NULL THEN m.content ELSE '' END, m.created_at, m.updated_at, m.deleted_at`
+
+func scanRoomMessage(row pgx.Row, m *entity.RoomMessage) error {
+	return row.Scan(&m.ID, &m.RoomID, &m.AuthorID, &m.AuthorName,
+		&m.Content, &m.CreatedAt, &m.UpdatedAt, &m.DeletedAt)
+}
+
+func (r *RoomRepoPostgres) Create(ctx context.Context, rm *entity.Room) error {
+	const op = "RoomRepo.Create"
+	// новая комната встаёт последней
+	const query = `
+        INSERT INTO rooms (slug, title, description, position, retention_mode, retention_value,
+                           rate_burst, rate_period_seconds, created_by)
+        VALUES ($1, $2, $3, (SELECT COALESCE(MAX(position) + 1, 0) FROM rooms),
+                NULLIF($4, 'default'), NULLIF($5, 0), $6, $7, $8)
+        RETURNING id, position, created_at;
+    `
+	err := r.Pool.QueryRow(ctx, query, rm.Slug, rm.Title, rm.Description,
+		string(rm.Retention.Mode), rm.Retention.Value, rm.RateBurst, int(rm.RatePeriod/time.Second), rm.CreatedBy).
+		Scan(&rm.ID, &rm.Position, &rm.CreatedAt)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("%s: %w", op, errors.ErrConflict)
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *RoomRepoPostgres) GetByID(ctx context.Context, id int64) (*entity.Room, error) {
+	const op = "RoomRepo.GetByID"
+	const query = `SELECT ` + roomColumns + ` FROM rooms WHERE id = $1;`
+
+	var rm entity.Room
+	if err := scanRoom(r.Pool.QueryRow(ctx, query, id), &rm); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &rm, nil
+}
+
+func (r *RoomRepoPostgres) GetBySlug(ctx context.Context, slug string) (*entity.Room, error) {
+	const op = "RoomRepo.GetBySlug"
+	const query = `SELECT ` + roomColumns + ` FROM rooms WHERE slug = $1;`
+
+	var rm entity.Room
+	if err := scanRoom(r.Pool.QueryRow(ctx, query, slug), &rm); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &rm, nil
+}
+
+func (r *RoomRepoPostgres) List(ctx context.Context) ([]*entity.Room, error) {
+	const op = "RoomRepo.List"
+	const query = `SELECT ` + roomColumns + ` FROM rooms ORDER BY position, id;`
+
+	rows, err := r.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	list := make([]*entity.Room, 0)
+	for rows.Next() {
+		var rm entity.Room
+		if err := scanRoom(rows, &rm); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		list = append(list, &rm)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return list, nil
+}
+
+func (r *RoomRepoPostgres) Update(ctx context.Context, rm *entity.Room) error {
+	const op = "RoomRepo.Update"
+	const query = `
+        UPDATE rooms
+           SET slug                = $2,
+               title               = $3,
+               description         = $4,
+               position            = $5,
+               retention_mode      = NULLIF($6, 'default'),
+               retention_value     = NULLIF($7, 0),
+               rate_burst          = $8,
+               rate_period_seconds = $9
+         WHERE id = $1;
+    `
+	tag, err := r.Pool.Exec(ctx, query, rm.ID, rm.Slug, rm.Title, rm.Description, rm.Position,
+		string(rm.Retention.Mode), rm.Retention.Value, rm.RateBurst, int(rm.RatePeriod/time.Second))
+	if isUniqueViolation(err) {
+		return fmt.Errorf("%s: %w", op, errors.ErrConflict)
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *RoomRepoPostgres) Delete(ctx context.Context, id int64) error {
+	const op = "RoomRepo.Delete"
+	const query = `DELETE FROM rooms WHERE id = $1;`
+
+	tag, err := r.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *RoomRepoPostgres) IsBanned(ctx context.Context, userID int64) (bool, error) {
+	const op = "RoomRepo.IsBanned"
+	const query = `
+        SELECT is_blocked AND (blocked_until IS NULL OR blocked_until > now())
+        FROM users
+        WHERE id = $1;
+    `
+	var banned bool
+	if err := r.Pool.QueryRow(ctx, query, userID).Scan(&banned); err != nil {
+		if err == pgx.ErrNoRows {
+			return false, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return banned, nil
+}
+
+func (r *RoomRepoPostgres) UserNames(ctx context.Context, ids []int64) (map[int64]string, error) {
+	const op = "RoomRepo.UserNames"
+	const query = `SELECT id, name FROM users WHERE id = ANY($1);`
+
+	rows, err := r.Pool.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	names := make(map[int64]string, len(ids))
+	for rows.Next() {
+		var id int64
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		names[id] = name
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return names, nil
+}
+
+func (r *RoomRepoPostgres) CreateMessage(ctx context.Context, m *entity.RoomMessage) error {
+	const op = "RoomRepo.CreateMessage"
+	const query = `
+        WITH msg AS (
+            INSERT INTO room_messages (room_id, author_id, content, created_at)
+            VALUES ($1, $2, $3, $4)
+            RETURNING id, author_id
+        )
+        SELECT msg.id, COALESCE(u.name, '')
+        FROM msg
+        LEFT JOIN users u ON u.id = msg.author_id;
+    `
+	err := r.Pool.QueryRow(ctx, query, m.RoomID, m.AuthorID, m.Content, m.CreatedAt).Scan(&m.ID, &m.AuthorName)
+	if isFKViolation(err) {
+		return fmt.Errorf("%s: %w", op, errors.ErrInvalidReference)
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *RoomRepoPostgres) GetMessage(ctx context.Context, id int64) (*entity.RoomMessage, error) {
+	const op = "RoomRepo.GetMessage"
+	const query = `
+        SELECT ` + roomMessageColumns + `
+        FROM room_messages m
+        LEFT JOIN users u ON u.id = m.author_id
+        WHERE m.id = $1;
+    `
+	var m entity.RoomMessage
+	if err := scanRoomMessage(r.Pool.QueryRow(ctx, query, id), &m); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &m, nil
+}
+
+// ListMessages не отдаёт удалённые сообщения: в комнате нет ветки обсуждения, которую они бы разрывали
+func (r *RoomRepoPostgres) ListMessages(ctx context.Context, roomID, beforeID int64, limit int) ([]*entity.RoomMessage, error) {
+	const op = "RoomRepo.ListMessages"
+	const query = `
+        SELECT ` + roomMessageColumns + `
+        FROM room_messages m
+        LEFT JOIN users u ON u.id = m.author_id
+        WHERE m.room_id = $1 AND m.deleted_at IS NULL AND ($2 = 0 OR m.id < $2)
+        ORDER BY m.id DESC
+        LIMIT $3;
+    `
+	rows, err := r.Pool.Query(ctx, query, roomID, beforeID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	list := make([]*entity.RoomMessage, 0)
+	for rows.Next() {
+		var m entity.RoomMessage
+		if err := scanRoomMessage(rows, &m); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		list = append(list, &m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return list, nil
+}
+
+func (r *RoomRepoPostgres) UpdateMessage(ctx context.Context, id int64, content string, updatedAt time.Time) error {
+	const op = "RoomRepo.UpdateMessage"
+	const query = `UPDATE room_messages SET content = $2, updated_at = $3 WHERE id = $1 AND deleted_at IS NULL;`
+
+	tag, err := r.Pool.Exec(ctx, query, id, content, updatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *RoomRepoPostgres) DeleteMessage(ctx context.Context, id int64) error {
+	const op = "RoomRepo.DeleteMessage"
+	const query = `UPDATE room_messages SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL;`
+
+	tag, err := r.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+	return nil
+}
+
+// DeleteExpired удаляет не больше limit сообщений, вышедших за политику хранения своей комнаты.
+// Без политики действует глобальный порог threshold; дни в режиме days считаются от now.
+func (r *RoomRepoPostgres) DeleteExpired(ctx context.Context, threshold, now time.Time, limit int) (int64, error) {
+	const op = "RoomRepo.DeleteExpired"
+	const query = `
+        WITH ranked AS (
+            SELECT m.id, rm.retention_mode AS mode, rm.retention_value AS value, m.created_at,
+                   ROW_NUMBER() OVER (PARTITION BY m.room_id ORDER BY m.created_at DESC, m.id DESC) AS rn
+              FROM room_messages m
+              JOIN rooms rm ON rm.id = m.room_id
+             WHERE rm.retention_mode IS DISTINCT FROM 'forever'
+        ),
+        expired AS (
+            SELECT id
+              FROM ranked
+             WHERE (mode IS NULL AND created_at < $1)
+                OR (mode = 'days' AND created_at < $2::timestamp - make_interval(days => value))
+                OR (mode = 'last_n' AND rn > value)
+        )
+        DELETE FROM room_messages
+         WHERE id IN (SELECT id FROM expired ORDER BY id LIMIT $3);
+    `
+	tag, err := r.Pool.Exec(ctx, query, threshold, now, limit)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *RoomRepoPostgres) PurgeDeleted(ctx context.Context, threshold time.Time) (int64, error) {
+	const op = "RoomRepo.PurgeDeleted"
+	const query = `DELETE FROM room_messages WHERE deleted_at < $1`
+
+	tag, err := r.Pool.Exec(ctx, query, threshold)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,284 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"chat-service/internal/entity"
+	"chat-service/internal/errors"
+	"github.com/ZoyaDenisova/go-common/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type SubscriptionRepoPostgres struct {
+	*postgres.Postgres
+}
+
+func NewSubscriptionRepo(pg *postgres.Postgres) SubscriptionRepository {
+	return &SubscriptionRepoPostgres{pg}
+}
+
+// Follow сохраняет подписку; настройки дайджеста по умолчанию (daily) создаются вместе с первой подпиской
+func (r *SubscriptionRepoPostgres) Follow(ctx context.Context, s *entity.Subscription) error {
+	const op = "SubscriptionRepo.Follow"
+	const query = `
+        WITH settings AS (
+            INSERT INTO digest_settings (user_id) VALUES ($1)
+            ON CONFLICT DO NOTHING
+        )
+        INSERT INTO subscriptions (user_id, topic_id, category_id)
+        VALUES ($1, $2, $3)
+        ON CONFLICT DO NOTHING;
+    `
+	_, err := r.Pool.Exec(ctx, query, s.UserID, s.TopicID, s.CategoryID)
+	if isFKViolation(err) {
+		return fmt.Errorf("%s: %w", op, errors.ErrInvalidReference)
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *SubscriptionRepoPostgres) Unfollow(ctx context.Context, userID int64, topicID, categoryID *int64) error {
+	const op = "SubscriptionRepo.Unfollow"
+	const query = `
+        DELETE FROM subscriptions
+        WHERE user_id = $1
+          AND topic_id IS NOT DISTINCT FROM $2::integer
+          AND category_id IS NOT DISTINCT FROM $3::integer;
+    `
+	tag, err := r.Pool.Exec(ctx, query, userID, topicID, categoryID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *SubscriptionRepoPostgres) ListByUser(ctx context.Context, userID int64) ([]*entity.Subscription, error) {
+	const op = "SubscriptionRepo.ListByUser"
+	const query = `
+        SELECT s.id, s.user_id, s.topic_id, s.category_id, COALESCE(t.title, c.title, ''), s.created_at
+        FROM subscriptions s
+        LEFT JOIN topics t ON t.id = s.topic_id
+        LEFT JOIN categories c ON c.id = s.category_id
+        WHERE s.user_id = $1
+        ORDER BY s.created_at DESC, s.id DESC;
+    `
+	rows, err := r.Pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	list := make([]*entity.Subscription, 0)
+	for rows.Next() {
+		var s entity.Subscription
+		if err := rows.Scan(&s.ID, &s.UserID, &s.TopicID, &s.CategoryID, &s.Title, &s.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		list = append(list, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return list, nil
+}
+
+func (r *SubscriptionRepoPostgres) GetSettings(ctx context.Context, userID int64) (*entity.DigestSettings, error) {
+	const op = "SubscriptionRepo.GetSettings"
+	const query = `
+        SELECT user_id, frequency, last_sent_at, unsubscribe_token, updated_at
+        FROM digest_settings
+        WHERE user_id = $1;
+    `
+	var s entity.DigestSettings
+	err := r.Pool.QueryRow(ctx, query, userID).
+		Scan(&s.UserID, &s.Frequency, &s.LastSentAt, &s.UnsubscribeToken, &s.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &s, nil
+}
+
+func (r *SubscriptionRepoPostgres) SaveSettings(ctx context.Context, s *entity.DigestSettings) error {
+	const op = "SubscriptionRepo.SaveSettings"
+	const query = `
+        INSERT INTO digest_settings (user_id, frequency)
+        VALUES ($1, $2)
+        ON CONFLICT (user_id) DO UPDATE
+            SET frequency  = EXCLUDED.frequency,
+                updated_at = now()
+        RETURNING last_sent_at, unsubscribe_token, updated_at;
+    `
+	if err := r.Pool.QueryRow(ctx, query, s.UserID, s.Frequency).
+		Scan(&s.LastSentAt, &s.UnsubscribeToken, &s.UpdatedAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *SubscriptionRepoPostgres) Unsubscribe(ctx context.Context, token string) error {
+	const op = "SubscriptionRepo.Unsubscribe"
+	const query = `
+        UPDATE digest_settings
+        SET frequency  = 'off',
+            updated_at = now()
+        WHERE unsubscribe_token = $1;
+    `
+	tag, err := r.Pool.Exec(ctx, query, token)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+	return nil
+}
+
+// DueRecipients возвращает подписчиков, чей последний дайджест старше выбранной частоты (или ещё не отправлялся),
+// по возрастанию id — afterID позволяет пройти их страницами
+func (r *SubscriptionRepoPostgres) DueRecipients(ctx context.Context, now time.Time, afterID int64, limit int) ([]*entity.DigestRecipient, error) {
+	const op = "SubscriptionRepo.DueRecipients"
+	const query = `
+        SELECT d.user_id, d.frequency, d.last_sent_at, d.unsubscribe_token, d.updated_at, u.name, u.email
+        FROM digest_settings d
+        JOIN users u ON u.id = d.user_id
+        WHERE d.frequency <> 'off'
+          AND d.user_id > $2
+          AND NOT u.is_blocked
+          AND (d.last_sent_at IS NULL
+               OR d.last_sent_at <= $1::timestamptz - CASE d.frequency
+                                                         WHEN 'weekly' THEN interval '7 days'
+                                                         ELSE interval '1 day' END)
+          AND EXISTS (SELECT 1 FROM subscriptions s WHERE s.user_id = d.user_id)
+        ORDER BY d.user_id
+        LIMIT $3;
+    `
+	rows, err := r.Pool.Query(ctx, query, now, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	list := make([]*entity.DigestRecipient, 0)
+	for rows.Next() {
+		var rc entity.DigestRecipient
+		s := &rc.Settings
+		if err := rows.Scan(&s.UserID, &s.Frequency, &s.LastSentAt, &s.UnsubscribeToken, &s.UpdatedAt,
+			&rc.Name, &rc.Email); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		list = append(list, &rc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return list, nil
+}
+
+// DigestTopics собирает топики из подписок пользователя — напрямую и через категории с подкатегориями.
+// Свои сообщения пользователя, удалённое и скрытое в дайджест не попадают; первыми идут топики с самой свежей активностью.
+func (r *SubscriptionRepoPostgres) DigestTopics(ctx context.Context, userID int64, since, until time.Time, limit, perTopic int) ([]*entity.DigestTopic, error) {
+	const op = "SubscriptionRepo.DigestTopics"
+	const topicsQuery = `
+        WITH RECURSIVE followed_categories AS (
+            SELECT category_id AS id FROM subscriptions WHERE user_id = $1 AND category_id IS NOT NULL
+            UNION
+            SELECT c.id FROM categories c JOIN followed_categories f ON c.parent_id = f.id
+        ), followed AS (
+            SELECT topic_id AS id FROM subscriptions WHERE user_id = $1 AND topic_id IS NOT NULL
+            UNION
+            SELECT t.id FROM topics t JOIN followed_categories f ON t.category_id = f.id
+        )
+        SELECT t.id, t.title, t.created_at > $2, count(m.id), COALESCE(max(m.created_at), t.created_at) AS last_at
+        FROM topics t
+        JOIN followed f ON f.id = t.id
+        LEFT JOIN messages m ON m.topic_id = t.id
+                            AND m.created_at > $2 AND m.created_at <= $3
+                            AND m.author_id <> $1
+                            AND m.deleted_at IS NULL AND m.hidden_at IS NULL
+        WHERE t.deleted_at IS NULL AND t.hidden_at IS NULL AND t.redirect_to IS NULL
+        GROUP BY t.id
+        HAVING count(m.id) > 0 OR (t.created_at > $2 AND t.created_at <= $3 AND t.author_id <> $1)
+        ORDER BY last_at DESC, t.id DESC
+        LIMIT $4;
+    `
+	const messagesQuery = `
+        SELECT ` + messageColumns + `
+        FROM (
+            SELECT *, row_number() OVER (PARTITION BY topic_id ORDER BY created_at DESC, id DESC) AS rn
+            FROM messages
+            WHERE topic_id = ANY ($1::bigint[])
+              AND created_at > $2 AND created_at <= $3
+              AND author_id <> $4
+              AND deleted_at IS NULL AND hidden_at IS NULL
+        ) m
+        JOIN users u ON u.id = m.author_id
+        WHERE m.rn <= $5
+        ORDER BY m.topic_id, m.created_at DESC, m.id DESC;
+    `
+	rows, err := r.Pool.Query(ctx, topicsQuery, userID, since, until, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query topics: %w", op, err)
+	}
+	defer rows.Close()
+
+	var (
+		list  = make([]*entity.DigestTopic, 0)
+		byID  = make(map[int64]*entity.DigestTopic)
+		ids   []int64
+		total int64
+	)
+	for rows.Next() {
+		var t entity.DigestTopic
+		if err := rows.Scan(&t.TopicID, &t.Title, &t.IsNew, &t.NewMessages, &t.LastAt); err != nil {
+			return nil, fmt.Errorf("%s: scan topic: %w", op, err)
+		}
+		list = append(list, &t)
+		byID[t.TopicID] = &t
+		ids = append(ids, t.TopicID)
+		total += t.NewMessages
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: topic rows: %w", op, err)
+	}
+	if total == 0 || perTopic <= 0 {
+		return list, nil
+	}
+
+	mrows, err := r.Pool.Query(ctx, messagesQuery, ids, since, until, userID, perTopic)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query messages: %w", op, err)
+	}
+	defer mrows.Close()
+
+	for mrows.Next() {
+		var m entity.Message
+		if err := scanMessage(mrows, &m); err != nil {
+			return nil, fmt.Errorf("%s: scan message: %w", op, err)
+		}
+		if t, ok := byID[m.TopicID]; ok {
+			t.Messages = append(t.Messages, &m)
+		}
+	}
+	if err := mrows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: message rows: %w", op, err)
+	}
+	return list, nil
+}
+
+func (r *SubscriptionRepoPostgres) MarkSent(ctx context.Context, userID int64, at time.Time) error {
+	const op = "SubscriptionRepo.MarkSent"
+	const query = `UPDATE digest_settings SET last_sent_at = $2 WHERE user_id = $1;`
+
+	if _, err := r.Pool.Exec(ctx, query, userID, at); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"github.com/ZoyaDenisova/go-common/postgres"
 	"github.com/jackc/pgx/v5"
+	"time"
 )
 
 type TopicRepoPostgres struct {
@@ -41,7 +42,7 @@ func (r *TopicRepoPostgres) Update(ctx context.Context, t *entity.Topic) (int64,
            SET category_id = $2,
                title       = $3,
                description = $4
-         WHERE id = $1
+         WHERE id = $1 AND deleted_at IS NULL
          RETURNING id;
     `
 
@@ -61,11 +62,67 @@ func (r *TopicRepoPostgres) Update(ctx context.Context, t *entity.Topic) (int64,
 	return id, nil
 }
 
-func (r *TopicRepoPostgres) Delete(ctx context.Context, id int64) error {
+func (r *TopicRepoPostgres) Delete(ctx context.Context, id, deletedBy int64, reason string) error {
 	const op = "TopicRepo.Delete"
-	const query = `DELETE FROM topics WHERE id = $1;`
+	const query = `
+        UPDATE topics
+           SET deleted_at    = now(),
+               deleted_by    = $2,
+               delete_reason = NULLIF($3, '')
+         WHERE id = $1 AND deleted_at IS NULL;
+    `
+
+	tag, err := r.Pool.Exec(ctx, query, id, deletedBy, reason)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *TopicRepoPostgres) Restore(ctx context.Context, id int64, since time.Time) error {
+	const op = "TopicRepo.Restore"
+	const query = `
+        UPDATE topics
+           SET deleted_at    = NULL,
+               deleted_by    = NULL,
+               delete_reason = NULL
+         WHERE id = $1 AND deleted_at >= $2;
+    `
+
+	tag, err := r.Pool.Exec(ctx, query, id, since)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *TopicRepoPostgres) PurgeDeleted(ctx context.Context, threshold time.Time) (int64, error) {
+	const op = "TopicRepo.PurgeDeleted"
+	const query = `DELETE FROM topics WHERE deleted_at < $1;`
+
+	tag, err := r.Pool.Exec(ctx, query, threshold)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *TopicRepoPostgres) SetRetention(ctx context.Context, id int64, p entity.RetentionPolicy) error {
+	const op = "TopicRepo.SetRetention"
+	const query = `
+        UPDATE topics
+           SET retention_mode  = NULLIF($2, 'default'),
+               retention_value = NULLIF($3, 0)
+         WHERE id = $1 AND deleted_at IS NULL;
+    `
 
-	tag, err := r.Pool.Exec(ctx, query, id)
+	tag, err := r.Pool.Exec(ctx, query, id, string(p.Mode), p.Value)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -75,16 +132,185 @@ func (r *TopicRepoPostgres) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
+func (r *TopicRepoPostgres) SetPinned(ctx context.Context, id int64, pinned bool) error {
+	const op = "TopicRepo.SetPinned"
+	const query = `UPDATE topics SET pinned = $2 WHERE id = $1 AND deleted_at IS NULL;`
+
+	tag, err := r.Pool.Exec(ctx, query, id, pinned)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *TopicRepoPostgres) SetLocked(ctx context.Context, id int64, locked bool) error {
+	const op = "TopicRepo.SetLocked"
+	const query = `UPDATE topics SET locked = $2 WHERE id = $1 AND deleted_at IS NULL;`
+
+	tag, err := r.Pool.Exec(ctx, query, id, locked)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+	return nil
+}
+
+// SetAccepted отмечает принятый ответ топика; nil снимает отметку
+func (r *TopicRepoPostgres) SetAccepted(ctx context.Context, id int64, messageID *int64) error {
+	const op = "TopicRepo.SetAccepted"
+	const query = `UPDATE topics SET accepted_message_id = $2 WHERE id = $1 AND deleted_at IS NULL;`
+
+	tag, err := r.Pool.Exec(ctx, query, id, messageID)
+	if err != nil {
+		if isFKViolation(err) {
+			return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	}
+	return nil
+}
+
+// Move переносит топик в другую категорию и пишет запись в moderation_log в одной транзакции.
+// Возвращает прежнюю категорию топика.
+func (r *TopicRepoPostgres) Move(ctx context.Context, id, categoryID, actorID int64) (int64, error) {
+	const op = "TopicRepo.Move"
+	const moveQuery = `
+        UPDATE topics t
+           SET category_id = $2
+          FROM (SELECT id, category_id FROM topics WHERE id = $1 FOR UPDATE) old
+         WHERE t.id = old.id
+           AND t.deleted_at IS NULL
+           AND t.redirect_to IS NULL
+        RETURNING old.category_id, t.author_id, t.title;
+    `
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: begin: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }() // после Commit откат ничего не делает
+
+	var (
+		from     int64
+		authorID int64
+		title    string
+	)
+	if err := tx.QueryRow(ctx, moveQuery, id, categoryID).Scan(&from, &authorID, &title); err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+		}
+		if isFKViolation(err) {
+			return 0, fmt.Errorf("%s: category %d: %w", op, categoryID, errors.ErrInvalidReference)
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	entry := &entity.ModerationEntry{
+		ActorID:        &actorID,
+		Action:         entity.ModMoveTopic,
+		TargetType:     entity.TargetTopic,
+		TargetID:       id,
+		TargetAuthorID: &authorID,
+		Snapshot:       map[string]any{"title": title},
+		Details:        map[string]any{"from_category_id": from, "to_category_id": categoryID},
+	}
+	if err := saveModeration(ctx, tx, entry); err != nil {
+		return 0, fmt.Errorf("%s: moderation log: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return from, nil
+}
+
+// Merge переносит все сообщения sourceID в targetID (created_at не меняется),
+// превращает sourceID в заглушку с redirect_to = targetID и пишет запись в moderation_log.
+// Заглушки, указывавшие на sourceID, перенаправляются сразу на targetID.
+func (r *TopicRepoPostgres) Merge(ctx context.Context, sourceID, targetID, actorID int64) (int64, error) {
+	const op = "TopicRepo.Merge"
+	const stubQuery = `
+        UPDATE topics
+           SET redirect_to = $2
+         WHERE id = $1 AND deleted_at IS NULL AND redirect_to IS NULL
+           AND EXISTS (SELECT 1 FROM topics
+                        WHERE id = $2 AND deleted_at IS NULL AND redirect_to IS NULL)
+        RETURNING author_id, title;
+    `
+	const moveMessagesQuery = `UPDATE messages SET topic_id = $2 WHERE topic_id = $1;`
+	const flattenQuery = `UPDATE topics SET redirect_to = $2 WHERE redirect_to = $1;`
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: begin: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }() // после Commit откат ничего не делает
+
+	var (
+		authorID int64
+		title    string
+	)
+	if err := tx.QueryRow(ctx, stubQuery, sourceID, targetID).Scan(&authorID, &title); err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+		}
+		return 0, fmt.Errorf("%s: stub: %w", op, err)
+	}
+
+	tag, err := tx.Exec(ctx, moveMessagesQuery, sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: messages: %w", op, err)
+	}
+	moved := tag.RowsAffected()
+
+	if _, err := tx.Exec(ctx, flattenQuery, sourceID, targetID); err != nil {
+		return 0, fmt.Errorf("%s: redirects: %w", op, err)
+	}
+
+	entry := &entity.ModerationEntry{
+		ActorID:        &actorID,
+		Action:         entity.ModMergeTopic,
+		TargetType:     entity.TargetTopic,
+		TargetID:       sourceID,
+		TargetAuthorID: &authorID,
+		Snapshot:       map[string]any{"title": title},
+		Details:        map[string]any{"merged_into_id": targetID, "messages_moved": moved},
+	}
+	if err := saveModeration(ctx, tx, entry); err != nil {
+		return 0, fmt.Errorf("%s: moderation log: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return moved, nil
+}
+
 func (r *TopicRepoPostgres) GetByCategory(ctx context.Context, categoryID int64) ([]*entity.Topic, error) {
 	const op = "TopicRepo.GetByCategory"
 	const query = `
         SELECT t.id, t.category_id, t.title, t.description,
        	t.author_id, u.name AS author_name,   
-       	t.created_at
+       	t.created_at, t.pinned, t.locked, c.qa, a.id
 		FROM   topics t
 		JOIN   users u ON u.id = t.author_id           
+		JOIN   categories c ON c.id = t.category_id
+		LEFT   JOIN messages a ON a.id = t.accepted_message_id
+		                      AND a.deleted_at IS NULL
+		                      AND a.hidden_at IS NULL
 		WHERE  t.category_id = $1
-		ORDER  BY t.created_at;
+		  AND  t.deleted_at IS NULL
+		  AND  t.redirect_to IS NULL
+		  AND  t.hidden_at IS NULL
+		ORDER  BY t.pinned DESC, t.created_at;
 
     `
 
@@ -99,7 +325,7 @@ func (r *TopicRepoPostgres) GetByCategory(ctx context.Context, categoryID int64)
 		t := &entity.Topic{}
 		if err := rows.Scan(&t.ID, &t.CategoryID, &t.Title, &t.Description,
 			&t.AuthorID, &t.AuthorName, // +1
-			&t.CreatedAt); err != nil {
+			&t.CreatedAt, &t.Pinned, &t.Locked, &t.QA, &t.AcceptedID); err != nil {
 			return nil, fmt.Errorf("%s: scan: %w", op, err)
 		}
 		list = append(list, t)
@@ -115,17 +341,25 @@ func (r *TopicRepoPostgres) GetByID(ctx context.Context, id int64) (*entity.Topi
 	const query = `
     	SELECT t.id, t.category_id, t.title, t.description,
        	t.author_id, u.name AS author_name,
-       	t.created_at
+       	t.created_at, t.pinned, t.locked, t.redirect_to, t.hidden_at IS NOT NULL,
+       	COALESCE(t.retention_mode, 'default'), COALESCE(t.retention_value, 0),
+       	c.qa, a.id
 		FROM   topics t
 		JOIN   users u ON u.id = t.author_id
-		WHERE  t.id = $1;
+		JOIN   categories c ON c.id = t.category_id
+		LEFT   JOIN messages a ON a.id = t.accepted_message_id
+		                      AND a.deleted_at IS NULL
+		                      AND a.hidden_at IS NULL
+		WHERE  t.id = $1
+		  AND  t.deleted_at IS NULL;
     `
 
 	t := &entity.Topic{}
 	err := r.Pool.QueryRow(ctx, query, id).
 		Scan(&t.ID, &t.CategoryID, &t.Title, &t.Description,
 			&t.AuthorID, &t.AuthorName, // +1
-			&t.CreatedAt)
+			&t.CreatedAt, &t.Pinned, &t.Locked, &t.RedirectTo, &t.Hidden, &t.Retention.Mode, &t.Retention.Value,
+			&t.QA, &t.AcceptedID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
@@ -134,3 +368,37 @@ func (r *TopicRepoPostgres) GetByID(ctx context.Context, id int64) (*entity.Topi
 	}
 	return t, nil
 }
+
+func (r *TopicRepoPostgres) GetDeleted(ctx context.Context, since time.Time) ([]*entity.Topic, error) {
+	const op = "TopicRepo.GetDeleted"
+	const query = `
+    	SELECT t.id, t.category_id, t.title, t.description,
+       	t.author_id, u.name AS author_name,
+       	t.created_at, t.deleted_at, t.deleted_by, COALESCE(t.delete_reason, '')
+		FROM   topics t
+		JOIN   users u ON u.id = t.author_id
+		WHERE  t.deleted_at >= $1
+		ORDER  BY t.deleted_at DESC;
+    `
+
+	rows, err := r.Pool.Query(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	var list []*entity.Topic
+	for rows.Next() {
+		t := &entity.Topic{}
+		if err := rows.Scan(&t.ID, &t.CategoryID, &t.Title, &t.Description,
+			&t.AuthorID, &t.AuthorName,
+			&t.CreatedAt, &t.DeletedAt, &t.DeletedBy, &t.DeleteReason); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		list = append(list, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return list, nil
+}
@@ -0,0 +1,40 @@
+package repo
+
+import (
+	"chat-service/internal/entity"
+	"chat-service/internal/errors"
+	"context"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+// UserRepoPostgres читает пользователей из общей с auth-service базы; сами пользователи там и меняются
+type UserRepoPostgres struct {
+	*postgres.Postgres
+}
+
+func NewUserRepo(pg *postgres.Postgres) UserRepository {
+	return &UserRepoPostgres{pg}
+}
+
+func (r *UserRepoPostgres) GetStanding(ctx context.Context, userID int64) (*entity.Standing, error) {
+	const op = "UserRepo.GetStanding"
+	const query = `
+        SELECT u.id, u.created_at,
+               (SELECT count(*) FROM messages m WHERE m.author_id = u.id AND m.deleted_at IS NULL),
+               COALESCE(r.reputation, 0)
+        FROM users u
+        LEFT JOIN user_reputation r ON r.user_id = u.id
+        WHERE u.id = $1;
+    `
+
+	var s entity.Standing
+	if err := r.Pool.QueryRow(ctx, query, userID).Scan(&s.UserID, &s.CreatedAt, &s.Posts, &s.Reputation); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &s, nil
+}
@@ -0,0 +1,129 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"chat-service/internal/entity"
+	"chat-service/internal/errors"
+	"github.com/ZoyaDenisova/go-common/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type VoteRepoPostgres struct {
+	*postgres.Postgres
+}
+
+func NewVoteRepo(pg *postgres.Postgres) VoteRepository {
+	return &VoteRepoPostgres{pg}
+}
+
+// Vote ставит, меняет или снимает (value = 0) голос. Счёт сообщения и репутация автора меняются
+// на разницу с прежним голосом в той же транзакции; строка сообщения блокируется, так что
+// одновременные голоса за одно сообщение не теряются.
+func (r *VoteRepoPostgres) Vote(ctx context.Context, messageID, userID int64, value int) (*entity.MessageVote, error) {
+	const op = "VoteRepo.Vote"
+	const lockQuery = `
+        SELECT author_id, score
+        FROM messages
+        WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL
+        FOR UPDATE;
+    `
+	const previousQuery = `SELECT value FROM message_votes WHERE message_id = $1 AND user_id = $2;`
+	const upsertQuery = `
+        INSERT INTO message_votes (message_id, user_id, value)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (message_id, user_id) DO UPDATE
+            SET value = EXCLUDED.value, created_at = now();
+    `
+	const deleteQuery = `DELETE FROM message_votes WHERE message_id = $1 AND user_id = $2;`
+	const scoreQuery = `UPDATE messages SET score = score + $2 WHERE id = $1 RETURNING score;`
+	const reputationQuery = `
+        INSERT INTO user_reputation (user_id, reputation)
+        VALUES ($1, $2)
+        ON CONFLICT (user_id) DO UPDATE
+            SET reputation = user_reputation.reputation + EXCLUDED.reputation, updated_at = now();
+    `
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: begin: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }() // после Commit откат ничего не делает
+
+	res := &entity.MessageVote{MessageID: messageID, Value: value}
+	var authorID int64
+	if err := tx.QueryRow(ctx, lockQuery, messageID).Scan(&authorID, &res.Score); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: lock: %w", op, err)
+	}
+
+	var previous int
+	if err := tx.QueryRow(ctx, previousQuery, messageID, userID).Scan(&previous); err != nil && err != pgx.ErrNoRows {
+		return nil, fmt.Errorf("%s: previous: %w", op, err)
+	}
+	delta := value - previous
+	if delta == 0 {
+		return res, nil
+	}
+
+	if value == 0 {
+		_, err = tx.Exec(ctx, deleteQuery, messageID, userID)
+	} else {
+		_, err = tx.Exec(ctx, upsertQuery, messageID, userID, value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: vote: %w", op, err)
+	}
+	if err := tx.QueryRow(ctx, scoreQuery, messageID, delta).Scan(&res.Score); err != nil {
+		return nil, fmt.Errorf("%s: score: %w", op, err)
+	}
+	if _, err := tx.Exec(ctx, reputationQuery, authorID, delta); err != nil {
+		return nil, fmt.Errorf("%s: reputation: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+	return res, nil
+}
+
+func (r *VoteRepoPostgres) ByUser(ctx context.Context, userID int64, messageIDs []int64) (map[int64]int, error) {
+	const op = "VoteRepo.ByUser"
+	const query = `SELECT message_id, value FROM message_votes WHERE user_id = $1 AND message_id = ANY($2);`
+
+	rows, err := r.Pool.Query(ctx, query, userID, messageIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	votes := make(map[int64]int)
+	for rows.Next() {
+		var (
+			id    int64
+			value int
+		)
+		if err := rows.Scan(&id, &value); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		votes[id] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: rows: %w", op, err)
+	}
+	return votes, nil
+}
+
+func (r *VoteRepoPostgres) Reputation(ctx context.Context, userID int64) (int64, error) {
+	const op = "VoteRepo.Reputation"
+	const query = `SELECT COALESCE((SELECT reputation FROM user_reputation WHERE user_id = $1), 0);`
+
+	var rep int64
+	if err := r.Pool.QueryRow(ctx, query, userID).Scan(&rep); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return rep, nil
+}
@@ -0,0 +1,61 @@
+package webapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	authpb "chat-service/cmd/app/docs/proto"
+	"chat-service/internal/auth"
+	"chat-service/internal/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AuthGRPC вызывает auth-service по gRPC, передавая токен пользователя из контекста:
+// права проверяет сам auth-service.
+type AuthGRPC struct {
+	client authpb.AuthServiceClient
+}
+
+func NewAuthGRPC(client authpb.AuthServiceClient) *AuthGRPC {
+	return &AuthGRPC{client: client}
+}
+
+func (a *AuthGRPC) BlockUser(ctx context.Context, userID int64, reason string) error {
+	const op = "AuthGRPC.BlockUser"
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+auth.TokenFromContext(ctx))
+	_, err := a.client.BlockUser(ctx, &authpb.BlockUserRequest{UserId: userID, Reason: reason})
+	switch status.Code(err) {
+	case codes.OK:
+		return nil
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return fmt.Errorf("%s: %w", op, errors.ErrPermissionDenied)
+	case codes.NotFound:
+		return fmt.Errorf("%s: %w", op, errors.ErrNotFound)
+	default:
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
+
+func (a *AuthGRPC) ResolveUsernames(ctx context.Context, names []string) (map[string]int64, error) {
+	const op = "AuthGRPC.ResolveUsernames"
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+auth.TokenFromContext(ctx))
+	resp, err := a.client.ResolveUsernames(ctx, &authpb.ResolveUsernamesRequest{Usernames: names})
+	switch status.Code(err) {
+	case codes.OK:
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return nil, fmt.Errorf("%s: %w", op, errors.ErrPermissionDenied)
+	default:
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ids := make(map[string]int64, len(resp.GetUsers()))
+	for _, u := range resp.GetUsers() {
+		ids[strings.ToLower(u.GetUsername())] = u.GetId()
+	}
+	return ids, nil
+}
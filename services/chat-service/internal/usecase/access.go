@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"chat-service/internal/auth"
+	repoErr "chat-service/internal/errors"
+	"chat-service/internal/repo"
+)
+
+// categoryFunc лениво возвращает категорию, к которой относится действие.
+// Вызывается только для прав с областью категории, чтобы не ходить в базу за admin и user.
+type categoryFunc func(ctx context.Context) (int64, error)
+
+// inCategory — категория уже известна
+func inCategory(id int64) categoryFunc {
+	return func(context.Context) (int64, error) { return id, nil }
+}
+
+// topicCategory — категория топика; заглушка слитого топика считается несуществующей
+func topicCategory(topics repo.TopicRepository, topicID int64) categoryFunc {
+	return func(ctx context.Context) (int64, error) {
+		t, err := topics.GetByID(ctx, topicID)
+		if errors.Is(err, repoErr.ErrNotFound) || (err == nil && t.IsRedirect()) {
+			return 0, ErrTopicNotFound
+		} else if err != nil {
+			return 0, fmt.Errorf("topicCategory: %w", err)
+		}
+		return t.CategoryID, nil
+	}
+}
+
+// access проверяет права текущего пользователя по таблице auth-политики.
+// Права модератора с областью категории сверяются с category_moderators.
+type access struct {
+	mods repo.ModeratorRepository
+}
+
+// check возвращает userID, если у пользователя из ctx есть право perm; category нужна только
+// для прав с областью категории (nil — действие вне категорий, такие права не выдаются).
+func (a access) check(ctx context.Context, perm auth.Permission, category categoryFunc) (int64, error) {
+	userID, role := auth.FromContext(ctx)
+	if userID == 0 {
+		return 0, ErrUnauthenticated
+	}
+
+	switch auth.ScopeOf(role, perm) {
+	case auth.ScopeGlobal:
+		return userID, nil
+	case auth.ScopeCategory:
+		if category == nil || a.mods == nil {
+			return 0, ErrForbidden
+		}
+		categoryID, err := category(ctx)
+		if err != nil {
+			return 0, err
+		}
+		ok, err := a.mods.IsModerator(ctx, userID, categoryID)
+		if err != nil {
+			return 0, fmt.Errorf("access.check: %w", err)
+		}
+		if !ok {
+			return 0, ErrForbidden
+		}
+		return userID, nil
+	default:
+		return 0, ErrForbidden
+	}
+}
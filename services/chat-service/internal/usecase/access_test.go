@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/usecase/mocks"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccess_Check(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mods := mocks.NewMockModeratorRepository(ctrl)
+	a := access{mods: mods}
+
+	const (
+		userID     = int64(5)
+		categoryID = int64(10)
+	)
+
+	global := []auth.Permission{
+		auth.PermTopicMove, auth.PermContentRestore, auth.PermCategoryManage, auth.PermRetentionManage, auth.PermRoomManage,
+		auth.PermUserBlock,
+	}
+	write := []auth.Permission{auth.PermTopicWrite, auth.PermMessageWrite, auth.PermConversationWrite, auth.PermRoomWrite}
+	moderate := []auth.Permission{auth.PermTopicModerate, auth.PermMessageModerate}
+
+	type tc struct {
+		name     string
+		role     string
+		perms    []auth.Permission
+		isMod    *bool // nil — в category_moderators не ходим
+		expected error
+	}
+	yes, no := true, false
+
+	cases := []tc{
+		{name: "user writes", role: auth.RoleUser, perms: write},
+		{name: "user cannot moderate", role: auth.RoleUser, perms: moderate, expected: ErrForbidden},
+		{name: "user has no admin rights", role: auth.RoleUser, perms: global, expected: ErrForbidden},
+		{name: "moderator writes", role: auth.RoleModerator, perms: write},
+		{name: "moderator moderates own category", role: auth.RoleModerator, perms: moderate, isMod: &yes},
+		{name: "moderator cannot moderate foreign category", role: auth.RoleModerator, perms: moderate, isMod: &no, expected: ErrForbidden},
+		{name: "moderator moderates every room", role: auth.RoleModerator, perms: []auth.Permission{auth.PermRoomModerate}},
+		{name: "user cannot moderate rooms", role: auth.RoleUser, perms: []auth.Permission{auth.PermRoomModerate}, expected: ErrForbidden},
+		{name: "moderator has no admin rights", role: auth.RoleModerator, perms: global, expected: ErrForbidden},
+		{name: "admin writes", role: auth.RoleAdmin, perms: write},
+		{name: "admin moderates everywhere", role: auth.RoleAdmin, perms: moderate},
+		{name: "admin has admin rights", role: auth.RoleAdmin, perms: global},
+		{name: "unknown role has nothing", role: "guest", perms: append(append(write, moderate...), global...), expected: ErrForbidden},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := auth.WithUser(context.Background(), userID, c.role)
+			for _, perm := range c.perms {
+				if c.isMod != nil {
+					mods.EXPECT().IsModerator(ctx, userID, categoryID).Return(*c.isMod, nil)
+				}
+				id, err := a.check(ctx, perm, inCategory(categoryID))
+				if c.expected != nil {
+					require.ErrorIs(t, err, c.expected, perm)
+					require.Zero(t, id)
+					continue
+				}
+				require.NoError(t, err, perm)
+				require.Equal(t, userID, id)
+			}
+		})
+	}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := a.check(context.Background(), auth.PermMessageWrite, nil)
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	t.Run("category-scoped right outside category", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), userID, auth.RoleModerator)
+		_, err := a.check(ctx, auth.PermTopicModerate, nil)
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("category lookup error", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), userID, auth.RoleModerator)
+		_, err := a.check(ctx, auth.PermTopicModerate, func(context.Context) (int64, error) {
+			return 0, ErrTopicNotFound
+		})
+		require.ErrorIs(t, err, ErrTopicNotFound)
+	})
+
+	t.Run("moderator repo error", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), userID, auth.RoleModerator)
+		mods.EXPECT().IsModerator(ctx, userID, categoryID).Return(false, errors.New("db down"))
+		_, err := a.check(ctx, auth.PermMessageModerate, inCategory(categoryID))
+		require.ErrorContains(t, err, "access.check")
+	})
+
+	t.Run("no moderator repo configured", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), userID, auth.RoleModerator)
+		_, err := access{}.check(ctx, auth.PermMessageModerate, inCategory(categoryID))
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+}
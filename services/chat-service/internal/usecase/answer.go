@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	repoErr "chat-service/internal/errors"
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrNotQACategory = errors.New("accepted answers are available only in Q&A categories")
+
+// SetAcceptedAnswer отмечает сообщение принятым ответом топика (accepted=false — снимает отметку).
+// Отмечают автор топика и модераторы категории, и только в категориях вопросов и ответов.
+// Новая отметка заменяет прежнюю: принятый ответ в топике один.
+func (uc *MessageUC) SetAcceptedAnswer(ctx context.Context, messageID int64, accepted bool) error {
+	uc.log.Debug("SetAcceptedAnswer called", "message_id", messageID, "accepted", accepted)
+
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 {
+		uc.log.Warn("unauthenticated user tried to accept answer")
+		return ErrUnauthenticated
+	}
+
+	m, err := uc.repo.GetByID(ctx, messageID)
+	if errors.Is(err, repoErr.ErrNotFound) || (err == nil && (m.IsDeleted() || m.Hidden)) {
+		uc.log.Info("message not found for accept", "message_id", messageID)
+		return ErrMessageNotFound
+	} else if err != nil {
+		uc.log.Error("repo.GetByID failed", "err", err)
+		return fmt.Errorf("MessageUC.Accept#message: %w", err)
+	}
+	t, err := uc.topics.GetByID(ctx, m.TopicID)
+	if errors.Is(err, repoErr.ErrNotFound) || (err == nil && t.IsRedirect()) {
+		uc.log.Info("topic not found for accept", "topic_id", m.TopicID)
+		return ErrTopicNotFound
+	} else if err != nil {
+		uc.log.Error("topics.GetByID failed", "err", err)
+		return fmt.Errorf("MessageUC.Accept#topic: %w", err)
+	}
+
+	// в своём топике отмечает автор, в чужом — модератор этой категории
+	perm := auth.PermTopicWrite
+	if t.AuthorID != userID {
+		perm = auth.PermTopicModerate
+	}
+	if _, err := uc.access.check(ctx, perm, inCategory(t.CategoryID)); err != nil {
+		uc.log.Warn("accept answer denied", "topic_id", t.ID, "user_id", userID, "err", err)
+		return err
+	}
+	if !t.QA {
+		return ErrNotQACategory
+	}
+
+	isAccepted := t.AcceptedID != nil && *t.AcceptedID == messageID
+	if accepted == isAccepted {
+		return nil
+	}
+	var target *int64
+	action := entity.ActionAnswerUnaccepted
+	if accepted {
+		target = &messageID
+		action = entity.ActionAnswerAccepted
+	}
+
+	err = uc.topics.SetAccepted(ctx, t.ID, target)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		uc.log.Info("topic or message deleted concurrently", "topic_id", t.ID, "message_id", messageID)
+		return ErrMessageNotFound
+	} else if err != nil {
+		uc.log.Error("topics.SetAccepted failed", "err", err)
+		return fmt.Errorf("MessageUC.Accept: %w", err)
+	}
+
+	uc.publish(t.ID, &entity.WSEvent{Action: action, TopicID: t.ID, MessageID: messageID})
+	uc.log.Info("accepted answer changed", "topic_id", t.ID, "message_id", messageID, "accepted", accepted, "by", userID)
+	return nil
+}
+
+// acceptedFirst поднимает принятый ответ в начало списка, не меняя порядок остальных.
+// Удалённый или скрытый ответ остаётся на своём месте.
+func acceptedFirst(list []*entity.Message) {
+	for i, m := range list {
+		if !m.Accepted || m.IsDeleted() || m.Hidden {
+			continue
+		}
+		copy(list[1:i+1], list[:i])
+		list[0] = m
+		return
+	}
+}
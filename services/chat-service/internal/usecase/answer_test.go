@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	customErr "chat-service/internal/errors"
+	"chat-service/internal/usecase/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestMessageUC_SetAcceptedAnswer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockMessageRepository(ctrl)
+	topics := mocks.NewMockTopicRepository(ctrl)
+	mods := mocks.NewMockModeratorRepository(ctrl)
+	publisher := mocks.NewMockMessagePublisher(ctrl)
+	uc := NewMessageUsecase(repo, topics, mods, nil, nil, MessageDeps{Publisher: publisher}, mocks.FakeLogger{}, retention)
+
+	author := auth.WithUser(context.Background(), 1, "user")
+	msg := &entity.Message{ID: 20, TopicID: 5, AuthorID: 2}
+	qaTopic := func() *entity.Topic { return &entity.Topic{ID: 5, CategoryID: 3, AuthorID: 1, QA: true} }
+
+	t.Run("author accepts", func(t *testing.T) {
+		repo.EXPECT().GetByID(author, int64(20)).Return(msg, nil)
+		topics.EXPECT().GetByID(author, int64(5)).Return(qaTopic(), nil)
+		topics.EXPECT().SetAccepted(author, int64(5), gomock.Eq(&msg.ID)).Return(nil)
+		publisher.EXPECT().Publish(int64(5), &entity.WSEvent{Action: entity.ActionAnswerAccepted, TopicID: 5, MessageID: 20})
+
+		require.NoError(t, uc.SetAcceptedAnswer(author, 20, true))
+	})
+
+	t.Run("already accepted", func(t *testing.T) {
+		tp := qaTopic()
+		tp.AcceptedID = &msg.ID
+		repo.EXPECT().GetByID(author, int64(20)).Return(msg, nil)
+		topics.EXPECT().GetByID(author, int64(5)).Return(tp, nil)
+
+		require.NoError(t, uc.SetAcceptedAnswer(author, 20, true))
+	})
+
+	t.Run("another answer replaces a deleted accepted one", func(t *testing.T) {
+		// удалённый принятый ответ репозиторий не отдаёт: топик открыт, можно принять другой
+		repo.EXPECT().GetByID(author, int64(20)).Return(msg, nil)
+		topics.EXPECT().GetByID(author, int64(5)).Return(qaTopic(), nil)
+		topics.EXPECT().SetAccepted(author, int64(5), gomock.Eq(&msg.ID)).Return(nil)
+		publisher.EXPECT().Publish(int64(5), &entity.WSEvent{Action: entity.ActionAnswerAccepted, TopicID: 5, MessageID: 20})
+
+		require.NoError(t, uc.SetAcceptedAnswer(author, 20, true))
+	})
+
+	t.Run("author unaccepts", func(t *testing.T) {
+		tp := qaTopic()
+		tp.AcceptedID = &msg.ID
+		repo.EXPECT().GetByID(author, int64(20)).Return(msg, nil)
+		topics.EXPECT().GetByID(author, int64(5)).Return(tp, nil)
+		topics.EXPECT().SetAccepted(author, int64(5), nil).Return(nil)
+		publisher.EXPECT().Publish(int64(5), &entity.WSEvent{Action: entity.ActionAnswerUnaccepted, TopicID: 5, MessageID: 20})
+
+		require.NoError(t, uc.SetAcceptedAnswer(author, 20, false))
+	})
+
+	t.Run("category moderator accepts", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 7, "moderator")
+		repo.EXPECT().GetByID(ctx, int64(20)).Return(msg, nil)
+		topics.EXPECT().GetByID(ctx, int64(5)).Return(qaTopic(), nil)
+		mods.EXPECT().IsModerator(ctx, int64(7), int64(3)).Return(true, nil)
+		topics.EXPECT().SetAccepted(ctx, int64(5), gomock.Any()).Return(nil)
+		publisher.EXPECT().Publish(int64(5), gomock.Any())
+
+		require.NoError(t, uc.SetAcceptedAnswer(ctx, 20, true))
+	})
+
+	t.Run("other user forbidden", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 2, "user")
+		repo.EXPECT().GetByID(ctx, int64(20)).Return(msg, nil)
+		topics.EXPECT().GetByID(ctx, int64(5)).Return(qaTopic(), nil)
+
+		require.ErrorIs(t, uc.SetAcceptedAnswer(ctx, 20, true), ErrForbidden)
+	})
+
+	t.Run("not a Q&A category", func(t *testing.T) {
+		tp := qaTopic()
+		tp.QA = false
+		repo.EXPECT().GetByID(author, int64(20)).Return(msg, nil)
+		topics.EXPECT().GetByID(author, int64(5)).Return(tp, nil)
+
+		require.ErrorIs(t, uc.SetAcceptedAnswer(author, 20, true), ErrNotQACategory)
+	})
+
+	t.Run("hidden message", func(t *testing.T) {
+		repo.EXPECT().GetByID(author, int64(21)).Return(&entity.Message{ID: 21, TopicID: 5, Hidden: true}, nil)
+
+		require.ErrorIs(t, uc.SetAcceptedAnswer(author, 21, true), ErrMessageNotFound)
+	})
+
+	t.Run("deleted message", func(t *testing.T) {
+		deleted := time.Now()
+		repo.EXPECT().GetByID(author, int64(21)).Return(&entity.Message{ID: 21, TopicID: 5, DeletedAt: &deleted}, nil)
+
+		require.ErrorIs(t, uc.SetAcceptedAnswer(author, 21, true), ErrMessageNotFound)
+	})
+
+	t.Run("message not found", func(t *testing.T) {
+		repo.EXPECT().GetByID(author, int64(22)).Return(nil, customErr.ErrNotFound)
+
+		require.ErrorIs(t, uc.SetAcceptedAnswer(author, 22, true), ErrMessageNotFound)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		repo.EXPECT().GetByID(author, int64(20)).Return(msg, nil)
+		topics.EXPECT().GetByID(author, int64(5)).Return(qaTopic(), nil)
+		topics.EXPECT().SetAccepted(author, int64(5), gomock.Any()).Return(errors.New("db down"))
+
+		require.ErrorContains(t, uc.SetAcceptedAnswer(author, 20, true), "MessageUC.Accept")
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		require.ErrorIs(t, uc.SetAcceptedAnswer(context.Background(), 20, true), ErrUnauthenticated)
+	})
+}
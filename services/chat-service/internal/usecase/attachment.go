@@ -0,0 +1,360 @@
+package usecase
+
+import (
+	"bytes"
+	"chat-service/internal/auth"
+	"chat-service/internal/blob"
+	"chat-service/internal/entity"
+	repoErr "chat-service/internal/errors"
+	"chat-service/internal/media"
+	"chat-service/internal/repo"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/logger"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const maxFileNameLength = 255
+
+var (
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrAttachmentTooLarge = errors.New("file is too large")
+	ErrAttachmentEmpty    = errors.New("file is empty")
+	ErrAttachmentType     = errors.New("file type is not allowed")
+	ErrTooManyAttachments = errors.New("too many attachments in one message")
+	ErrTooManyPending     = errors.New("too many unsent attachments: send or delete some first")
+	ErrInvalidAttachment  = errors.New("attachment not found or already attached to a message")
+	ErrAttachmentInUse    = errors.New("attachment is already attached to a message")
+)
+
+// AttachmentPolicy — ограничения на вложения
+type AttachmentPolicy struct {
+	MaxSize       int64    // байт на файл
+	MaxPerMessage int      // вложений в одном сообщении
+	MaxPending    int      // загруженных, но ещё не отправленных вложений одного пользователя
+	Types         []string // разрешённые MIME-типы, определённые по содержимому
+	ThumbnailSize int      // большая сторона миниатюры в пикселях
+}
+
+// AttachmentBinder — то, что нужно MessageUC от вложений; реализуется AttachmentUC
+type AttachmentBinder interface {
+	// Pending возвращает в порядке ids вложения, которые uploaderID загрузил и ещё не отправил
+	Pending(ctx context.Context, uploaderID int64, ids []int64) ([]*entity.Attachment, error)
+	// Load заполняет Attachments у сообщений
+	Load(ctx context.Context, list ...*entity.Message) error
+}
+
+// AttachmentUC — файлы к сообщениям. Файл загружается заранее и ждёт отправки сообщения;
+// содержимое лежит в blob-хранилище, в базе — только описание. Тип определяется по содержимому,
+// для картинок сразу делается миниатюра.
+type AttachmentUC struct {
+	repo   repo.AttachmentRepository
+	store  blob.Store
+	policy AttachmentPolicy
+	access access
+	log    logger.Interface
+}
+
+func NewAttachmentUsecase(r repo.AttachmentRepository, s blob.Store, p AttachmentPolicy, l logger.Interface) *AttachmentUC {
+	return &AttachmentUC{repo: r, store: s, policy: p, log: l}
+}
+
+// Upload сохраняет файл текущего пользователя; привязать его к сообщению можно при отправке
+func (uc *AttachmentUC) Upload(ctx context.Context, p UploadParams) (*entity.Attachment, error) {
+	uc.log.Debug("Upload called", "file_name", p.FileName)
+
+	userID, err := uc.access.check(ctx, auth.PermMessageWrite, nil)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := uc.repo.CountPending(ctx, userID)
+	if err != nil {
+		uc.log.Error("repo.CountPending failed", "err", err)
+		return nil, fmt.Errorf("AttachmentUC.Upload#pending: %w", err)
+	}
+	if uc.policy.MaxPending > 0 && pending >= uc.policy.MaxPending {
+		uc.log.Info("upload rejected: too many pending attachments", "user_id", userID, "pending", pending)
+		return nil, ErrTooManyPending
+	}
+
+	// читаем на байт больше лимита, чтобы отличить файл ровно в лимит от большего
+	data, err := io.ReadAll(io.LimitReader(p.Body, uc.policy.MaxSize+1))
+	if err != nil {
+		uc.log.Warn("upload read failed", "err", err)
+		return nil, fmt.Errorf("AttachmentUC.Upload#read: %w", err)
+	}
+	if int64(len(data)) > uc.policy.MaxSize {
+		return nil, ErrAttachmentTooLarge
+	}
+	if len(data) == 0 {
+		return nil, ErrAttachmentEmpty
+	}
+	contentType := media.Sniff(data)
+	if !slices.Contains(uc.policy.Types, contentType) {
+		uc.log.Info("upload rejected: file type is not allowed", "user_id", userID, "content_type", contentType)
+		return nil, ErrAttachmentType
+	}
+
+	a := &entity.Attachment{
+		UploaderID:  userID,
+		FileName:    cleanFileName(p.FileName, contentType),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		StorageKey:  newStorageKey("attachments"),
+	}
+	var thumb *media.Image
+	if media.CanThumbnail(contentType) && uc.policy.ThumbnailSize > 0 {
+		if a.Width, a.Height, err = media.Dimensions(data); err == nil {
+			thumb, err = media.Thumbnail(data, uc.policy.ThumbnailSize)
+		}
+		if errors.Is(err, media.ErrTooManyPixels) {
+			uc.log.Info("upload rejected: image dimensions are too large", "user_id", userID, "width", a.Width, "height", a.Height)
+			return nil, ErrAttachmentTooLarge
+		} else if err != nil {
+			// заголовок похож на картинку, а декодировать не вышло — битый или подделанный файл
+			uc.log.Info("upload rejected: unreadable image", "user_id", userID, "err", err)
+			return nil, ErrAttachmentType
+		}
+		a.ThumbnailKey = a.StorageKey + "_thumb"
+	}
+
+	if err := uc.store.Put(ctx, a.StorageKey, bytes.NewReader(data), a.Size, a.ContentType); err != nil {
+		uc.log.Error("store.Put failed", "err", err)
+		return nil, fmt.Errorf("AttachmentUC.Upload#store: %w", err)
+	}
+	if thumb != nil {
+		if err := uc.store.Put(ctx, a.ThumbnailKey, bytes.NewReader(thumb.Data), int64(len(thumb.Data)), thumb.ContentType); err != nil {
+			uc.log.Error("store.Put thumbnail failed", "err", err)
+			uc.removeFiles(ctx, a)
+			return nil, fmt.Errorf("AttachmentUC.Upload#thumbnail: %w", err)
+		}
+	}
+	if err := uc.repo.Create(ctx, a); err != nil {
+		uc.log.Error("repo.Create failed", "err", err)
+		uc.removeFiles(ctx, a)
+		return nil, fmt.Errorf("AttachmentUC.Upload: %w", err)
+	}
+
+	uc.log.Info("attachment uploaded", "id", a.ID, "user_id", userID, "content_type", a.ContentType, "size", a.Size)
+	return a, nil
+}
+
+// Open отдаёт вложение или его миниатюру. Неотправленное вложение видит только тот, кто его загрузил;
+// вложения удалённых и скрытых сообщений не отдаются.
+func (uc *AttachmentUC) Open(ctx context.Context, id int64, thumbnail bool) (*entity.Attachment, io.ReadCloser, error) {
+	a, err := uc.repo.GetByID(ctx, id)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		return nil, nil, ErrAttachmentNotFound
+	} else if err != nil {
+		uc.log.Error("repo.GetByID failed", "err", err)
+		return nil, nil, fmt.Errorf("AttachmentUC.Open: %w", err)
+	}
+	userID, _ := auth.FromContext(ctx)
+	if a.Hidden || (a.IsPending() && a.UploaderID != userID) || (thumbnail && !a.HasThumbnail()) {
+		return nil, nil, ErrAttachmentNotFound
+	}
+
+	key := a.StorageKey
+	if thumbnail {
+		key = a.ThumbnailKey
+	}
+	rc, err := uc.store.Open(ctx, key)
+	if errors.Is(err, blob.ErrNotFound) {
+		uc.log.Warn("attachment file is missing", "id", id, "key", key)
+		return nil, nil, ErrAttachmentNotFound
+	} else if err != nil {
+		uc.log.Error("store.Open failed", "err", err)
+		return nil, nil, fmt.Errorf("AttachmentUC.Open#store: %w", err)
+	}
+	return a, rc, nil
+}
+
+// Delete удаляет своё ещё не отправленное вложение
+func (uc *AttachmentUC) Delete(ctx context.Context, id int64) error {
+	uc.log.Debug("AttachmentUC.Delete called", "id", id)
+
+	userID, err := uc.access.check(ctx, auth.PermMessageWrite, nil)
+	if err != nil {
+		return err
+	}
+	a, err := uc.repo.GetByID(ctx, id)
+	if errors.Is(err, repoErr.ErrNotFound) || (err == nil && a.UploaderID != userID) {
+		return ErrAttachmentNotFound
+	} else if err != nil {
+		uc.log.Error("repo.GetByID failed", "err", err)
+		return fmt.Errorf("AttachmentUC.Delete: %w", err)
+	}
+	if !a.IsPending() {
+		return ErrAttachmentInUse
+	}
+
+	if n, err := uc.repo.Delete(ctx, []int64{id}); err != nil {
+		uc.log.Error("repo.Delete failed", "err", err)
+		return fmt.Errorf("AttachmentUC.Delete: %w", err)
+	} else if n == 0 {
+		// успели отправить с сообщением
+		return ErrAttachmentInUse
+	}
+	uc.removeFiles(ctx, a)
+
+	uc.log.Info("attachment deleted", "id", id, "user_id", userID)
+	return nil
+}
+
+func (uc *AttachmentUC) Pending(ctx context.Context, uploaderID int64, ids []int64) ([]*entity.Attachment, error) {
+	ids = uniqueIDs(ids)
+	if len(ids) > uc.policy.MaxPerMessage {
+		return nil, ErrTooManyAttachments
+	}
+	list, err := uc.repo.ListByIDs(ctx, ids)
+	if err != nil {
+		uc.log.Error("repo.ListByIDs failed", "err", err)
+		return nil, fmt.Errorf("AttachmentUC.Pending: %w", err)
+	}
+	byID := make(map[int64]*entity.Attachment, len(list))
+	for _, a := range list {
+		byID[a.ID] = a
+	}
+
+	out := make([]*entity.Attachment, 0, len(ids))
+	for _, id := range ids {
+		a, ok := byID[id]
+		if !ok || a.UploaderID != uploaderID || !a.IsPending() {
+			uc.log.Info("attachment is not available", "id", id, "user_id", uploaderID)
+			return nil, ErrInvalidAttachment
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+func (uc *AttachmentUC) Load(ctx context.Context, list ...*entity.Message) error {
+	if len(list) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(list))
+	for _, m := range list {
+		ids = append(ids, m.ID)
+	}
+	attachments, err := uc.repo.ListByMessages(ctx, ids)
+	if err != nil {
+		uc.log.Error("repo.ListByMessages failed", "err", err)
+		return fmt.Errorf("AttachmentUC.Load: %w", err)
+	}
+	byMessage := make(map[int64][]*entity.Attachment)
+	for _, a := range attachments {
+		byMessage[*a.MessageID] = append(byMessage[*a.MessageID], a)
+	}
+	for _, m := range list {
+		m.Attachments = byMessage[m.ID]
+	}
+	return nil
+}
+
+// CleanupOrphans удаляет вложения, которые не привязались к сообщению до threshold, — неотправленные
+// и оставшиеся от окончательно удалённых сообщений, — вместе с файлами, пачками по batch (для cron).
+// Если файл удалить не вышло, строка остаётся до следующего запуска.
+func (uc *AttachmentUC) CleanupOrphans(ctx context.Context, threshold time.Time, batch int) error {
+	uc.log.Debug("CleanupOrphans called", "threshold", threshold)
+
+	if batch <= 0 {
+		batch = defaultCleanupBatch
+	}
+	var total int64
+	for {
+		list, err := uc.repo.ListOrphans(ctx, threshold, batch)
+		if err != nil {
+			uc.log.Error("repo.ListOrphans failed", "err", err, "deleted_so_far", total)
+			return fmt.Errorf("AttachmentUC.CleanupOrphans: %w", err)
+		}
+		ids := make([]int64, 0, len(list))
+		for _, a := range list {
+			if uc.removeFiles(ctx, a) {
+				ids = append(ids, a.ID)
+			}
+		}
+		if len(ids) > 0 {
+			n, err := uc.repo.Delete(ctx, ids)
+			if err != nil {
+				uc.log.Error("repo.Delete failed", "err", err, "deleted_so_far", total)
+				return fmt.Errorf("AttachmentUC.CleanupOrphans: %w", err)
+			}
+			total += n
+		}
+		// пачка неполная — больше нечего; ни один файл не удалился — хранилище недоступно, повторим в другой раз
+		if len(list) < batch || len(ids) == 0 {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			uc.log.Warn("attachment cleanup interrupted", "err", err, "deleted_so_far", total)
+			return fmt.Errorf("AttachmentUC.CleanupOrphans: %w", err)
+		}
+	}
+
+	uc.log.Info("orphan attachments deleted", "threshold", threshold, "count", total)
+	return nil
+}
+
+// removeFiles удаляет файл вложения и миниатюру; false — что-то удалить не вышло
+func (uc *AttachmentUC) removeFiles(ctx context.Context, a *entity.Attachment) bool {
+	ok := true
+	for _, key := range []string{a.StorageKey, a.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		if err := uc.store.Delete(ctx, key); err != nil {
+			uc.log.Warn("store.Delete failed", "key", key, "err", err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// newStorageKey — случайный ключ: по нему нельзя перебрать чужие файлы, и имя от клиента в него не попадает
+func newStorageKey(prefix string) string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return prefix + "/" + hex.EncodeToString(b)
+}
+
+// cleanFileName оставляет от присланного имени только базовое имя без управляющих символов;
+// пустое имя заменяется на "file" с расширением по типу
+func cleanFileName(name, contentType string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" || name == "." || name == "/" {
+		_, sub, _ := strings.Cut(contentType, "/")
+		name = "file." + sub
+	}
+	for len(name) > maxFileNameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
+
+func uniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	out := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
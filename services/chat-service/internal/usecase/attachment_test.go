@@ -0,0 +1,322 @@
+package usecase
+
+import (
+	"bytes"
+	"chat-service/internal/auth"
+	"chat-service/internal/blob"
+	"chat-service/internal/entity"
+	customErr "chat-service/internal/errors"
+	"chat-service/internal/usecase/mocks"
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testAttachmentPolicy = AttachmentPolicy{
+	MaxSize:       1 << 20,
+	MaxPerMessage: 2,
+	MaxPending:    5,
+	Types:         []string{"image/png", "text/plain", "application/pdf"},
+	ThumbnailSize: 32,
+}
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 200, A: 128})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func newTestStore(t *testing.T) *blob.LocalStore {
+	t.Helper()
+	s, err := blob.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	return s
+}
+
+func TestAttachmentUC_Upload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAttachmentRepository(ctrl)
+	store := newTestStore(t)
+	uc := NewAttachmentUsecase(repo, store, testAttachmentPolicy, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 1, "user")
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := uc.Upload(context.Background(), UploadParams{FileName: "a.txt", Body: strings.NewReader("hi")})
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	t.Run("too many pending", func(t *testing.T) {
+		repo.EXPECT().CountPending(ctx, int64(1)).Return(5, nil)
+		_, err := uc.Upload(ctx, UploadParams{FileName: "a.txt", Body: strings.NewReader("hi")})
+		require.ErrorIs(t, err, ErrTooManyPending)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		repo.EXPECT().CountPending(ctx, int64(1)).Return(0, nil)
+		body := bytes.NewReader(make([]byte, testAttachmentPolicy.MaxSize+1))
+		_, err := uc.Upload(ctx, UploadParams{FileName: "big.bin", Body: body})
+		require.ErrorIs(t, err, ErrAttachmentTooLarge)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		repo.EXPECT().CountPending(ctx, int64(1)).Return(0, nil)
+		_, err := uc.Upload(ctx, UploadParams{FileName: "empty.txt", Body: strings.NewReader("")})
+		require.ErrorIs(t, err, ErrAttachmentEmpty)
+	})
+
+	t.Run("type is detected from content, not name", func(t *testing.T) {
+		repo.EXPECT().CountPending(ctx, int64(1)).Return(0, nil)
+		exe := append([]byte("MZ"), make([]byte, 64)...)
+		_, err := uc.Upload(ctx, UploadParams{FileName: "photo.png", Body: bytes.NewReader(exe)})
+		require.ErrorIs(t, err, ErrAttachmentType)
+	})
+
+	t.Run("broken image", func(t *testing.T) {
+		repo.EXPECT().CountPending(ctx, int64(1)).Return(0, nil)
+		data := testPNG(t, 8, 8)[:40]
+		_, err := uc.Upload(ctx, UploadParams{FileName: "broken.png", Body: bytes.NewReader(data)})
+		require.ErrorIs(t, err, ErrAttachmentType)
+	})
+
+	t.Run("image gets thumbnail", func(t *testing.T) {
+		data := testPNG(t, 128, 64)
+		repo.EXPECT().CountPending(ctx, int64(1)).Return(0, nil)
+		repo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, a *entity.Attachment) error {
+			require.Equal(t, "image/png", a.ContentType)
+			require.Equal(t, "photo.png", a.FileName)
+			require.Equal(t, 128, a.Width)
+			require.Equal(t, 64, a.Height)
+			require.True(t, a.HasThumbnail())
+			a.ID = 3
+			return nil
+		})
+		a, err := uc.Upload(ctx, UploadParams{FileName: `C:\Users\me\photo.png`, Body: bytes.NewReader(data)})
+		require.NoError(t, err)
+
+		rc, err := store.Open(ctx, a.ThumbnailKey)
+		require.NoError(t, err)
+		thumb, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		cfg, err := png.DecodeConfig(bytes.NewReader(thumb))
+		require.NoError(t, err, "translucent image is thumbnailed as PNG")
+		require.Equal(t, 32, cfg.Width)
+		require.Equal(t, 16, cfg.Height)
+	})
+
+	t.Run("repo failure removes files", func(t *testing.T) {
+		var key string
+		repo.EXPECT().CountPending(ctx, int64(1)).Return(0, nil)
+		repo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, a *entity.Attachment) error {
+			key = a.StorageKey
+			return customErr.ErrInvalidReference
+		})
+		_, err := uc.Upload(ctx, UploadParams{FileName: "a.txt", Body: strings.NewReader("hello")})
+		require.Error(t, err)
+		_, err = store.Open(ctx, key)
+		require.ErrorIs(t, err, blob.ErrNotFound)
+	})
+}
+
+func TestAttachmentUC_Open(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAttachmentRepository(ctrl)
+	store := newTestStore(t)
+	uc := NewAttachmentUsecase(repo, store, testAttachmentPolicy, mocks.FakeLogger{})
+	owner := auth.WithUser(context.Background(), 1, "user")
+	other := auth.WithUser(context.Background(), 2, "user")
+	require.NoError(t, store.Put(owner, "attachments/k", strings.NewReader("hello"), 5, "text/plain"))
+
+	msgID := int64(10)
+	pending := &entity.Attachment{ID: 1, UploaderID: 1, StorageKey: "attachments/k"}
+	sent := &entity.Attachment{ID: 1, UploaderID: 1, MessageID: &msgID, StorageKey: "attachments/k"}
+
+	t.Run("pending is visible to uploader only", func(t *testing.T) {
+		repo.EXPECT().GetByID(other, int64(1)).Return(pending, nil)
+		_, _, err := uc.Open(other, 1, false)
+		require.ErrorIs(t, err, ErrAttachmentNotFound)
+
+		repo.EXPECT().GetByID(owner, int64(1)).Return(pending, nil)
+		_, rc, err := uc.Open(owner, 1, false)
+		require.NoError(t, err)
+		_ = rc.Close()
+	})
+
+	t.Run("sent is public", func(t *testing.T) {
+		repo.EXPECT().GetByID(context.Background(), int64(1)).Return(sent, nil)
+		_, rc, err := uc.Open(context.Background(), 1, false)
+		require.NoError(t, err)
+		data, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		require.Equal(t, "hello", string(data))
+	})
+
+	t.Run("hidden message", func(t *testing.T) {
+		hidden := *sent
+		hidden.Hidden = true
+		repo.EXPECT().GetByID(other, int64(1)).Return(&hidden, nil)
+		_, _, err := uc.Open(other, 1, false)
+		require.ErrorIs(t, err, ErrAttachmentNotFound)
+	})
+
+	t.Run("no thumbnail", func(t *testing.T) {
+		repo.EXPECT().GetByID(other, int64(1)).Return(sent, nil)
+		_, _, err := uc.Open(other, 1, true)
+		require.ErrorIs(t, err, ErrAttachmentNotFound)
+	})
+}
+
+func TestAttachmentUC_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAttachmentRepository(ctrl)
+	uc := NewAttachmentUsecase(repo, newTestStore(t), testAttachmentPolicy, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 1, "user")
+	msgID := int64(10)
+
+	t.Run("someone else's", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(1)).Return(&entity.Attachment{ID: 1, UploaderID: 2}, nil)
+		require.ErrorIs(t, uc.Delete(ctx, 1), ErrAttachmentNotFound)
+	})
+
+	t.Run("already sent", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(1)).Return(&entity.Attachment{ID: 1, UploaderID: 1, MessageID: &msgID}, nil)
+		require.ErrorIs(t, uc.Delete(ctx, 1), ErrAttachmentInUse)
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(1)).Return(&entity.Attachment{ID: 1, UploaderID: 1, StorageKey: "attachments/k"}, nil)
+		repo.EXPECT().Delete(ctx, []int64{1}).Return(int64(1), nil)
+		require.NoError(t, uc.Delete(ctx, 1))
+	})
+}
+
+func TestAttachmentUC_Pending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAttachmentRepository(ctrl)
+	uc := NewAttachmentUsecase(repo, nil, testAttachmentPolicy, mocks.FakeLogger{})
+	ctx := context.Background()
+	msgID := int64(10)
+
+	t.Run("too many", func(t *testing.T) {
+		_, err := uc.Pending(ctx, 1, []int64{1, 2, 3})
+		require.ErrorIs(t, err, ErrTooManyAttachments)
+	})
+
+	t.Run("someone else's or already sent", func(t *testing.T) {
+		repo.EXPECT().ListByIDs(ctx, []int64{1, 2}).Return([]*entity.Attachment{
+			{ID: 1, UploaderID: 1},
+			{ID: 2, UploaderID: 2},
+		}, nil)
+		_, err := uc.Pending(ctx, 1, []int64{1, 2})
+		require.ErrorIs(t, err, ErrInvalidAttachment)
+
+		repo.EXPECT().ListByIDs(ctx, []int64{1}).Return([]*entity.Attachment{{ID: 1, UploaderID: 1, MessageID: &msgID}}, nil)
+		_, err = uc.Pending(ctx, 1, []int64{1})
+		require.ErrorIs(t, err, ErrInvalidAttachment)
+	})
+
+	t.Run("keeps requested order, drops duplicates", func(t *testing.T) {
+		repo.EXPECT().ListByIDs(ctx, []int64{2, 1}).Return([]*entity.Attachment{
+			{ID: 1, UploaderID: 1},
+			{ID: 2, UploaderID: 1},
+		}, nil)
+		list, err := uc.Pending(ctx, 1, []int64{2, 1, 2})
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		require.Equal(t, int64(2), list[0].ID)
+		require.Equal(t, int64(1), list[1].ID)
+	})
+}
+
+func TestAttachmentUC_CleanupOrphans(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAttachmentRepository(ctrl)
+	store := newTestStore(t)
+	uc := NewAttachmentUsecase(repo, store, testAttachmentPolicy, mocks.FakeLogger{})
+	ctx := context.Background()
+	threshold := time.Now().Add(-24 * time.Hour)
+
+	require.NoError(t, store.Put(ctx, "attachments/a", strings.NewReader("a"), 1, "text/plain"))
+	require.NoError(t, store.Put(ctx, "attachments/a_thumb", strings.NewReader("t"), 1, "image/png"))
+
+	gomock.InOrder(
+		repo.EXPECT().ListOrphans(ctx, threshold, 2).Return([]*entity.Attachment{
+			{ID: 1, StorageKey: "attachments/a", ThumbnailKey: "attachments/a_thumb"},
+			{ID: 2, StorageKey: "attachments/missing"}, // файла уже нет — строку всё равно удаляем
+		}, nil),
+		repo.EXPECT().Delete(ctx, []int64{1, 2}).Return(int64(2), nil),
+		repo.EXPECT().ListOrphans(ctx, threshold, 2).Return([]*entity.Attachment{}, nil),
+	)
+	require.NoError(t, uc.CleanupOrphans(ctx, threshold, 2))
+
+	_, err := store.Open(ctx, "attachments/a")
+	require.ErrorIs(t, err, blob.ErrNotFound)
+	_, err = store.Open(ctx, "attachments/a_thumb")
+	require.ErrorIs(t, err, blob.ErrNotFound)
+}
+
+func TestMessageUC_SendMessageWithAttachments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockMessageRepository(ctrl)
+	topics := mocks.NewMockTopicRepository(ctrl)
+	files := mocks.NewMockAttachmentBinder(ctrl)
+	publisher := mocks.NewMockMessagePublisher(ctrl)
+	uc := NewMessageUsecase(repo, topics, nil, nil, nil, MessageDeps{Files: files, Publisher: publisher}, mocks.FakeLogger{}, retention)
+	ctx := auth.WithUser(context.Background(), 1, "user")
+
+	t.Run("empty without attachments", func(t *testing.T) {
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(&entity.Topic{ID: 10}, nil).AnyTimes()
+		_, err := uc.SendMessage(ctx, SendMessageParams{TopicID: 10, AuthorID: 1, Content: "  "})
+		require.ErrorIs(t, err, ErrEmptyMessage)
+	})
+
+	t.Run("attachment not available", func(t *testing.T) {
+		files.EXPECT().Pending(ctx, int64(1), []int64{5}).Return(nil, ErrInvalidAttachment)
+		_, err := uc.SendMessage(ctx, SendMessageParams{TopicID: 10, AuthorID: 1, AttachmentIDs: []int64{5}})
+		require.ErrorIs(t, err, ErrInvalidAttachment)
+	})
+
+	t.Run("attached concurrently elsewhere", func(t *testing.T) {
+		files.EXPECT().Pending(ctx, int64(1), []int64{5}).Return([]*entity.Attachment{{ID: 5, UploaderID: 1}}, nil)
+		repo.EXPECT().Create(ctx, gomock.Any()).Return(customErr.ErrConflict)
+		_, err := uc.SendMessage(ctx, SendMessageParams{TopicID: 10, AuthorID: 1, AttachmentIDs: []int64{5}})
+		require.ErrorIs(t, err, ErrInvalidAttachment)
+	})
+
+	t.Run("files only", func(t *testing.T) {
+		files.EXPECT().Pending(ctx, int64(1), []int64{5}).Return([]*entity.Attachment{{ID: 5, UploaderID: 1}}, nil)
+		repo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, m *entity.Message) error {
+			require.Len(t, m.Attachments, 1)
+			return nil
+		})
+		publisher.EXPECT().Publish(int64(10), gomock.Any())
+		m, err := uc.SendMessage(ctx, SendMessageParams{TopicID: 10, AuthorID: 1, AttachmentIDs: []int64{5}})
+		require.NoError(t, err)
+		require.Equal(t, int64(5), m.Attachments[0].ID)
+	})
+}
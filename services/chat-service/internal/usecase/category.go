@@ -12,12 +12,16 @@ import (
 )
 
 var (
-	ErrCategoryNotFound = errors.New("category not found")
+	ErrCategoryNotFound       = errors.New("category not found")
+	ErrParentCategoryNotFound = errors.New("parent category not found")
+	ErrCategoryNotEmpty       = errors.New("category has subcategories or topics: move or delete them first")
+	ErrCategoryCycle          = errors.New("category cannot be nested inside itself or its own subcategory")
 )
 
 type CategoryUC struct {
-	repo repo.CategoryRepository
-	log  logger.Interface
+	repo   repo.CategoryRepository
+	access access // управление категориями — только глобальные права, модераторы не нужны
+	log    logger.Interface
 }
 
 func NewCategoryUsecase(r repo.CategoryRepository, l logger.Interface) *CategoryUC {
@@ -43,6 +47,41 @@ func (uc *CategoryUC) ListCategories(ctx context.Context) ([]*entity.Category, e
 	return cats, nil
 }
 
+// ListCategoryTree возвращает категории деревом: корни и их Children, отсортированные по Position
+func (uc *CategoryUC) ListCategoryTree(ctx context.Context) ([]*entity.Category, error) {
+	uc.log.Debug("CategoryUC.ListCategoryTree called")
+
+	cats, err := uc.repo.GetAll(ctx)
+	if err != nil {
+		uc.log.Error("repo.GetAll failed", "err", err)
+		return nil, fmt.Errorf("CategoryUC.Tree: %w", err)
+	}
+
+	return buildCategoryTree(cats), nil
+}
+
+// buildCategoryTree раскладывает плоский список по родителям, сохраняя порядок списка.
+// Категория с потерянным родителем поднимается в корень, чтобы не пропасть из выдачи.
+func buildCategoryTree(cats []*entity.Category) []*entity.Category {
+	byID := make(map[int64]*entity.Category, len(cats))
+	for _, c := range cats {
+		c.Children = nil
+		byID[c.ID] = c
+	}
+
+	roots := make([]*entity.Category, 0)
+	for _, c := range cats {
+		if c.ParentID != nil {
+			if parent, ok := byID[*c.ParentID]; ok {
+				parent.Children = append(parent.Children, c)
+				continue
+			}
+		}
+		roots = append(roots, c)
+	}
+	return roots
+}
+
 // GetCategory возвращает категорию по ID
 func (uc *CategoryUC) GetCategory(ctx context.Context, id int64) (*entity.Category, error) {
 	uc.log.Debug("CategoryUC.GetCategory called", "id", id)
@@ -64,22 +103,23 @@ func (uc *CategoryUC) GetCategory(ctx context.Context, id int64) (*entity.Catego
 func (uc *CategoryUC) CreateCategory(ctx context.Context, p CreateCategoryParams) (int64, error) {
 	uc.log.Debug("CreateCategory called", "title", p.Title)
 
-	userID, role := auth.FromContext(ctx)
-	if userID == 0 {
-		uc.log.Warn("unauthenticated user tried to create category")
-		return 0, ErrUnauthenticated
-	}
-	if role != "admin" {
-		uc.log.Warn("unauthorized role tried to create category", "role", role)
-		return 0, ErrForbidden
+	_, err := uc.access.check(ctx, auth.PermCategoryManage, nil)
+	if err != nil {
+		uc.log.Warn("create category denied", "err", err)
+		return 0, err
 	}
 
 	c := &entity.Category{
 		Title:       p.Title,
 		Description: p.Description,
+		ParentID:    p.ParentID,
 	}
 
 	if err := uc.repo.Create(ctx, c); err != nil {
+		if errors.Is(err, repoErr.ErrInvalidReference) {
+			uc.log.Info("parent category not found", "parent_id", *p.ParentID)
+			return 0, ErrParentCategoryNotFound
+		}
 		uc.log.Error("repo.Create failed", "err", err)
 		return 0, fmt.Errorf("CategoryUC.Create: %w", err)
 	}
@@ -91,17 +131,13 @@ func (uc *CategoryUC) CreateCategory(ctx context.Context, p CreateCategoryParams
 func (uc *CategoryUC) UpdateCategory(ctx context.Context, c *entity.Category) error {
 	uc.log.Debug("UpdateCategory called", "id", c.ID, "title", c.Title)
 
-	userID, role := auth.FromContext(ctx)
-	if userID == 0 {
-		uc.log.Warn("unauthenticated user tried to update category")
-		return ErrUnauthenticated
-	}
-	if role != "admin" {
-		uc.log.Warn("unauthorized role tried to update category", "role", role)
-		return ErrForbidden
+	_, err := uc.access.check(ctx, auth.PermCategoryManage, nil)
+	if err != nil {
+		uc.log.Warn("update category denied", "err", err)
+		return err
 	}
 
-	err := uc.repo.Update(ctx, c)
+	err = uc.repo.Update(ctx, c)
 	switch {
 	case errors.Is(err, repoErr.ErrNotFound):
 		uc.log.Info("category not found during update", "id", c.ID)
@@ -118,21 +154,20 @@ func (uc *CategoryUC) UpdateCategory(ctx context.Context, c *entity.Category) er
 func (uc *CategoryUC) DeleteCategory(ctx context.Context, id int64) error {
 	uc.log.Debug("DeleteCategory called", "id", id)
 
-	userID, role := auth.FromContext(ctx)
-	if userID == 0 {
-		uc.log.Warn("unauthenticated user tried to delete category")
-		return ErrUnauthenticated
-	}
-	if role != "admin" {
-		uc.log.Warn("unauthorized role tried to delete category", "role", role)
-		return ErrForbidden
+	_, err := uc.access.check(ctx, auth.PermCategoryManage, nil)
+	if err != nil {
+		uc.log.Warn("delete category denied", "err", err)
+		return err
 	}
 
-	err := uc.repo.Delete(ctx, id)
+	err = uc.repo.Delete(ctx, id)
 	switch {
 	case errors.Is(err, repoErr.ErrNotFound):
 		uc.log.Info("category not found during delete", "id", id)
 		return ErrCategoryNotFound
+	case errors.Is(err, repoErr.ErrConflict):
+		uc.log.Info("category is not empty", "id", id)
+		return ErrCategoryNotEmpty
 	case err != nil:
 		uc.log.Error("repo.Delete failed", "err", err)
 		return fmt.Errorf("CategoryUC.Delete: %w", err)
@@ -141,3 +176,129 @@ func (uc *CategoryUC) DeleteCategory(ctx context.Context, id int64) error {
 		return nil
 	}
 }
+
+// ReorderCategories переставляет категории: меняет родителя и позицию (только admin).
+// Перестановка, после которой категория оказалась бы внутри самой себя, отклоняется целиком.
+func (uc *CategoryUC) ReorderCategories(ctx context.Context, items []entity.CategoryPosition) error {
+	uc.log.Debug("ReorderCategories called", "count", len(items))
+
+	userID, err := uc.access.check(ctx, auth.PermCategoryManage, nil)
+	if err != nil {
+		uc.log.Warn("reorder categories denied", "err", err)
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	cats, err := uc.repo.GetAll(ctx)
+	if err != nil {
+		uc.log.Error("repo.GetAll failed", "err", err)
+		return fmt.Errorf("CategoryUC.Reorder#get: %w", err)
+	}
+
+	parents := make(map[int64]*int64, len(cats))
+	for _, c := range cats {
+		parents[c.ID] = c.ParentID
+	}
+	for _, it := range items {
+		if _, ok := parents[it.ID]; !ok {
+			uc.log.Info("category not found during reorder", "id", it.ID)
+			return ErrCategoryNotFound
+		}
+		if it.ParentID != nil {
+			if _, ok := parents[*it.ParentID]; !ok {
+				uc.log.Info("parent category not found during reorder", "parent_id", *it.ParentID)
+				return ErrParentCategoryNotFound
+			}
+		}
+		parents[it.ID] = it.ParentID
+	}
+	if hasCategoryCycle(parents) {
+		uc.log.Info("reorder would create a cycle")
+		return ErrCategoryCycle
+	}
+
+	err = uc.repo.Reorder(ctx, items)
+	switch {
+	case errors.Is(err, repoErr.ErrNotFound):
+		uc.log.Info("category disappeared during reorder", "err", err)
+		return ErrCategoryNotFound
+	case errors.Is(err, repoErr.ErrInvalidReference):
+		uc.log.Info("parent disappeared during reorder", "err", err)
+		return ErrParentCategoryNotFound
+	case err != nil:
+		uc.log.Error("repo.Reorder failed", "err", err)
+		return fmt.Errorf("CategoryUC.Reorder: %w", err)
+	}
+
+	uc.log.Info("categories reordered", "count", len(items), "by", userID)
+	return nil
+}
+
+// hasCategoryCycle проверяет, что от каждой категории можно дойти до корня
+func hasCategoryCycle(parents map[int64]*int64) bool {
+	for id := range parents {
+		seen := map[int64]bool{id: true}
+		for p := parents[id]; p != nil; p = parents[*p] {
+			if seen[*p] {
+				return true
+			}
+			seen[*p] = true
+		}
+	}
+	return false
+}
+
+// SetCategoryRetention задаёт политику хранения сообщений для всех топиков категории (только admin)
+func (uc *CategoryUC) SetCategoryRetention(ctx context.Context, id int64, p entity.RetentionPolicy) error {
+	uc.log.Debug("SetCategoryRetention called", "id", id, "mode", p.Mode, "value", p.Value)
+
+	_, err := uc.access.check(ctx, auth.PermRetentionManage, nil)
+	if err != nil {
+		uc.log.Warn("set category retention denied", "err", err)
+		return err
+	}
+	if !p.Valid() {
+		uc.log.Info("invalid retention policy", "mode", p.Mode, "value", p.Value)
+		return ErrInvalidRetention
+	}
+
+	err = uc.repo.SetRetention(ctx, id, p)
+	switch {
+	case errors.Is(err, repoErr.ErrNotFound):
+		uc.log.Info("category not found during retention update", "id", id)
+		return ErrCategoryNotFound
+	case err != nil:
+		uc.log.Error("repo.SetRetention failed", "err", err)
+		return fmt.Errorf("CategoryUC.SetRetention: %w", err)
+	default:
+		uc.log.Info("category retention updated", "id", id, "mode", p.Mode, "value", p.Value)
+		return nil
+	}
+}
+
+// SetCategoryQA включает или выключает режим вопросов и ответов (только admin).
+// Отмеченные ранее принятые ответы при выключении остаются, но отмечать новые нельзя.
+func (uc *CategoryUC) SetCategoryQA(ctx context.Context, id int64, qa bool) error {
+	uc.log.Debug("SetCategoryQA called", "id", id, "qa", qa)
+
+	_, err := uc.access.check(ctx, auth.PermCategoryManage, nil)
+	if err != nil {
+		uc.log.Warn("set category qa denied", "err", err)
+		return err
+	}
+
+	err = uc.repo.SetQA(ctx, id, qa)
+	switch {
+	case errors.Is(err, repoErr.ErrNotFound):
+		uc.log.Info("category not found during qa update", "id", id)
+		return ErrCategoryNotFound
+	case err != nil:
+		uc.log.Error("repo.SetQA failed", "err", err)
+		return fmt.Errorf("CategoryUC.SetQA: %w", err)
+	default:
+		uc.log.Info("category qa updated", "id", id, "qa", qa)
+		return nil
+	}
+}
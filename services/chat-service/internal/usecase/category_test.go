@@ -166,6 +166,26 @@ func TestCategoryUC_CreateCategory(t *testing.T) {
 		require.Zero(t, id)
 		require.ErrorContains(t, err, "CategoryUC.Create")
 	})
+
+	t.Run("parent not found", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "admin")
+		parentID := int64(99)
+		mockRepo.EXPECT().
+			Create(ctx, gomock.AssignableToTypeOf(&entity.Category{})).
+			DoAndReturn(func(_ context.Context, c *entity.Category) error {
+				require.Equal(t, &parentID, c.ParentID)
+				return customErr.ErrInvalidReference
+			})
+
+		id, err := uc.CreateCategory(ctx, CreateCategoryParams{
+			Title:       "Sci-fi",
+			Description: "sub",
+			ParentID:    &parentID,
+		})
+
+		require.Zero(t, id)
+		require.ErrorIs(t, err, ErrParentCategoryNotFound)
+	})
 }
 
 func TestCategoryUC_UpdateCategory(t *testing.T) {
@@ -280,6 +300,17 @@ func TestCategoryUC_DeleteCategory(t *testing.T) {
 		require.ErrorIs(t, err, ErrCategoryNotFound)
 	})
 
+	t.Run("category has subcategories or topics", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "admin")
+
+		mockRepo.EXPECT().
+			Delete(ctx, testID).
+			Return(customErr.ErrConflict)
+
+		err := uc.DeleteCategory(ctx, testID)
+		require.ErrorIs(t, err, ErrCategoryNotEmpty)
+	})
+
 	t.Run("repo returns general error", func(t *testing.T) {
 		ctx := auth.WithUser(context.Background(), 1, "admin")
 
@@ -291,3 +322,234 @@ func TestCategoryUC_DeleteCategory(t *testing.T) {
 		require.ErrorContains(t, err, "CategoryUC.Delete")
 	})
 }
+
+func TestCategoryUC_SetCategoryRetention(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCategoryRepository(ctrl)
+	log := mocks.FakeLogger{}
+	uc := NewCategoryUsecase(mockRepo, log)
+
+	testID := int64(7)
+	policy := entity.RetentionPolicy{Mode: entity.RetentionDays, Value: 90}
+
+	t.Run("success - admin sets policy", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "admin")
+
+		mockRepo.EXPECT().
+			SetRetention(ctx, testID, policy).
+			Return(nil)
+
+		err := uc.SetCategoryRetention(ctx, testID, policy)
+		require.NoError(t, err)
+	})
+
+	t.Run("success - keep forever", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "admin")
+		forever := entity.RetentionPolicy{Mode: entity.RetentionForever}
+
+		mockRepo.EXPECT().
+			SetRetention(ctx, testID, forever).
+			Return(nil)
+
+		err := uc.SetCategoryRetention(ctx, testID, forever)
+		require.NoError(t, err)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		err := uc.SetCategoryRetention(context.Background(), testID, policy)
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	t.Run("forbidden - not admin", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 2, "user")
+
+		err := uc.SetCategoryRetention(ctx, testID, policy)
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "admin")
+
+		err := uc.SetCategoryRetention(ctx, testID, entity.RetentionPolicy{Mode: "weekly", Value: 1})
+		require.ErrorIs(t, err, ErrInvalidRetention)
+
+		err = uc.SetCategoryRetention(ctx, testID, entity.RetentionPolicy{Mode: entity.RetentionLastN, Value: 0})
+		require.ErrorIs(t, err, ErrInvalidRetention)
+	})
+
+	t.Run("repo returns not found", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "admin")
+
+		mockRepo.EXPECT().
+			SetRetention(ctx, testID, policy).
+			Return(customErr.ErrNotFound)
+
+		err := uc.SetCategoryRetention(ctx, testID, policy)
+		require.ErrorIs(t, err, ErrCategoryNotFound)
+	})
+
+	t.Run("repo returns general error", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "admin")
+
+		mockRepo.EXPECT().
+			SetRetention(ctx, testID, policy).
+			Return(errors.New("unexpected"))
+
+		err := uc.SetCategoryRetention(ctx, testID, policy)
+		require.ErrorContains(t, err, "CategoryUC.SetRetention")
+	})
+}
+
+func TestCategoryUC_SetCategoryQA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCategoryRepository(ctrl)
+	uc := NewCategoryUsecase(mockRepo, mocks.FakeLogger{})
+	admin := auth.WithUser(context.Background(), 1, "admin")
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo.EXPECT().SetQA(admin, int64(7), true).Return(nil)
+		require.NoError(t, uc.SetCategoryQA(admin, 7, true))
+	})
+
+	t.Run("forbidden - not admin", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 2, "moderator")
+		require.ErrorIs(t, uc.SetCategoryQA(ctx, 7, true), ErrForbidden)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockRepo.EXPECT().SetQA(admin, int64(8), false).Return(customErr.ErrNotFound)
+		require.ErrorIs(t, uc.SetCategoryQA(admin, 8, false), ErrCategoryNotFound)
+	})
+}
+
+func TestCategoryUC_ListCategoryTree(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCategoryRepository(ctrl)
+	uc := NewCategoryUsecase(mockRepo, mocks.FakeLogger{})
+
+	ctx := context.Background()
+	root, missing := int64(1), int64(404)
+
+	t.Run("builds tree preserving order", func(t *testing.T) {
+		mockRepo.EXPECT().
+			GetAll(ctx).
+			Return([]*entity.Category{
+				{ID: 1, Title: "Root", Position: 0},
+				{ID: 2, Title: "Other root", Position: 1},
+				{ID: 3, Title: "Child B", ParentID: &root, Position: 0},
+				{ID: 4, Title: "Child A", ParentID: &root, Position: 1},
+				{ID: 5, Title: "Orphan", ParentID: &missing},
+			}, nil)
+
+		tree, err := uc.ListCategoryTree(ctx)
+
+		require.NoError(t, err)
+		require.Len(t, tree, 3)
+		require.Equal(t, []int64{1, 2, 5}, []int64{tree[0].ID, tree[1].ID, tree[2].ID})
+		require.Len(t, tree[0].Children, 2)
+		require.Equal(t, int64(3), tree[0].Children[0].ID)
+		require.Equal(t, int64(4), tree[0].Children[1].ID)
+		require.Empty(t, tree[1].Children)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		mockRepo.EXPECT().
+			GetAll(ctx).
+			Return(nil, errors.New("db down"))
+
+		tree, err := uc.ListCategoryTree(ctx)
+
+		require.Nil(t, tree)
+		require.ErrorContains(t, err, "CategoryUC.Tree")
+	})
+}
+
+func TestCategoryUC_ReorderCategories(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCategoryRepository(ctrl)
+	uc := NewCategoryUsecase(mockRepo, mocks.FakeLogger{})
+
+	admin := auth.WithUser(context.Background(), 1, "admin")
+	one, two, three := int64(1), int64(2), int64(3)
+
+	// 1 → 2 → 3 (3 вложена в 2, 2 вложена в 1)
+	existing := func() []*entity.Category {
+		return []*entity.Category{
+			{ID: 1},
+			{ID: 2, ParentID: &one},
+			{ID: 3, ParentID: &two},
+		}
+	}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		err := uc.ReorderCategories(context.Background(), []entity.CategoryPosition{{ID: 1}})
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	t.Run("forbidden - not admin", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 2, "user")
+		err := uc.ReorderCategories(ctx, []entity.CategoryPosition{{ID: 1}})
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("empty list is a no-op", func(t *testing.T) {
+		require.NoError(t, uc.ReorderCategories(admin, nil))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		items := []entity.CategoryPosition{
+			{ID: 3, ParentID: &one, Position: 0},
+			{ID: 2, ParentID: &one, Position: 1},
+		}
+		mockRepo.EXPECT().GetAll(admin).Return(existing(), nil)
+		mockRepo.EXPECT().Reorder(admin, items).Return(nil)
+
+		require.NoError(t, uc.ReorderCategories(admin, items))
+	})
+
+	t.Run("cycle rejected", func(t *testing.T) {
+		mockRepo.EXPECT().GetAll(admin).Return(existing(), nil)
+
+		err := uc.ReorderCategories(admin, []entity.CategoryPosition{{ID: 1, ParentID: &three}})
+		require.ErrorIs(t, err, ErrCategoryCycle)
+	})
+
+	t.Run("self parent rejected", func(t *testing.T) {
+		mockRepo.EXPECT().GetAll(admin).Return(existing(), nil)
+
+		err := uc.ReorderCategories(admin, []entity.CategoryPosition{{ID: 2, ParentID: &two}})
+		require.ErrorIs(t, err, ErrCategoryCycle)
+	})
+
+	t.Run("unknown category", func(t *testing.T) {
+		mockRepo.EXPECT().GetAll(admin).Return(existing(), nil)
+
+		err := uc.ReorderCategories(admin, []entity.CategoryPosition{{ID: 42}})
+		require.ErrorIs(t, err, ErrCategoryNotFound)
+	})
+
+	t.Run("unknown parent", func(t *testing.T) {
+		missing := int64(42)
+		mockRepo.EXPECT().GetAll(admin).Return(existing(), nil)
+
+		err := uc.ReorderCategories(admin, []entity.CategoryPosition{{ID: 3, ParentID: &missing}})
+		require.ErrorIs(t, err, ErrParentCategoryNotFound)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		items := []entity.CategoryPosition{{ID: 3, Position: 5}}
+		mockRepo.EXPECT().GetAll(admin).Return(existing(), nil)
+		mockRepo.EXPECT().Reorder(admin, items).Return(errors.New("db down"))
+
+		err := uc.ReorderCategories(admin, items)
+		require.ErrorContains(t, err, "CategoryUC.Reorder")
+	})
+}
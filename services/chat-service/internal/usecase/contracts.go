@@ -2,31 +2,183 @@ package usecase
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"chat-service/internal/entity"
+	"chat-service/internal/filter"
+	"chat-service/internal/ratelimit"
 )
 
 type CategoryUsecase interface {
 	ListCategories(ctx context.Context) ([]*entity.Category, error)
+	ListCategoryTree(ctx context.Context) ([]*entity.Category, error)
 	GetCategory(ctx context.Context, id int64) (*entity.Category, error)
 	CreateCategory(ctx context.Context, p CreateCategoryParams) (int64, error)
 	UpdateCategory(ctx context.Context, c *entity.Category) error
 	DeleteCategory(ctx context.Context, id int64) error
+	ReorderCategories(ctx context.Context, items []entity.CategoryPosition) error
+	SetCategoryRetention(ctx context.Context, id int64, p entity.RetentionPolicy) error
+	SetCategoryQA(ctx context.Context, id int64, qa bool) error
 }
 
 type TopicUsecase interface {
-	ListTopics(ctx context.Context, categoryID int64) ([]*entity.Topic, error)
+	ListTopics(ctx context.Context, categoryID int64, f TopicFilter) ([]*entity.Topic, error)
 	GetTopic(ctx context.Context, id int64) (*entity.Topic, error)
 	CreateTopic(ctx context.Context, p TopicParams) (int64, error)
 	UpdateTopic(ctx context.Context, id int64, p TopicParams) (int64, error)
-	DeleteTopic(ctx context.Context, id int64) error
+	DeleteTopic(ctx context.Context, id int64, reason string) error
+	RestoreTopic(ctx context.Context, id int64) error
+	ListDeletedTopics(ctx context.Context) ([]*entity.Topic, error)
+	PurgeDeletedTopics(ctx context.Context, threshold time.Time) error
+	SetTopicRetention(ctx context.Context, id int64, p entity.RetentionPolicy) error
+	PinTopic(ctx context.Context, id int64, pinned bool) error
+	LockTopic(ctx context.Context, id int64, locked bool) error
+	MoveTopic(ctx context.Context, id, categoryID int64) error
+	MergeTopics(ctx context.Context, sourceID, targetID int64) error
 }
 
 type MessageUsecase interface {
 	SendMessage(ctx context.Context, p SendMessageParams) (*entity.Message, error)
 	UpdateMessage(ctx context.Context, id int64, newContent string) error
-	DeleteMessage(ctx context.Context, id int64) error
-	GetMessages(ctx context.Context, topicID int64) ([]*entity.Message, error)
-	CleanupOldMessages(ctx context.Context, threshold time.Time) error
+	DeleteMessage(ctx context.Context, id int64, reason string) error
+	RestoreMessage(ctx context.Context, id int64) error
+	ListDeletedMessages(ctx context.Context) ([]*entity.Message, error)
+	// GetMessages возвращает сообщения топика в порядке order (OrderOldest или OrderScore)
+	GetMessages(ctx context.Context, topicID int64, order string) ([]*entity.Message, error)
+	// SetAcceptedAnswer отмечает сообщение принятым ответом топика или снимает отметку
+	SetAcceptedAnswer(ctx context.Context, messageID int64, accepted bool) error
+	CleanupOldMessages(ctx context.Context, p CleanupParams) (*entity.CleanupReport, error)
+	PreviewCleanup(ctx context.Context, threshold time.Time) (*entity.CleanupReport, error)
+	PurgeDeletedMessages(ctx context.Context, threshold time.Time) error
+}
+
+type VoteUsecase interface {
+	// Vote ставит голос текущего пользователя: 1 — за, -1 — против, 0 — снять голос
+	Vote(ctx context.Context, messageID int64, value int) (*entity.MessageVote, error)
+	// AnnotateMessages заполняет MyVote у сообщений для текущего пользователя, если он вошёл
+	AnnotateMessages(ctx context.Context, list ...*entity.Message)
+}
+
+type ModerationUsecase interface {
+	ListModerationLog(ctx context.Context, f entity.ModerationFilter) ([]*entity.ModerationEntry, int64, error)
+	ListMyModerationLog(ctx context.Context, limit, offset int) ([]*entity.ModerationEntry, int64, error)
+}
+
+type ReportUsecase interface {
+	CreateReport(ctx context.Context, p ReportParams) error
+	ListReports(ctx context.Context, f entity.ReportFilter) ([]*entity.Report, int64, error)
+	ResolveReport(ctx context.Context, id int64, p ResolveReportParams) error
+}
+
+type MuteUsecase interface {
+	Mute(ctx context.Context, p MuteParams) (*entity.Mute, error)
+	Unmute(ctx context.Context, id int64) error
+	ListMutes(ctx context.Context, userID int64) ([]*entity.Mute, error)
+	ListMyMutes(ctx context.Context) ([]*entity.Mute, error)
+	PurgeExpiredMutes(ctx context.Context, before time.Time) error
+}
+
+type FilterUsecase interface {
+	ListFilterRules(ctx context.Context) ([]*entity.FilterRule, error)
+	CreateFilterRule(ctx context.Context, r *entity.FilterRule) error
+	UpdateFilterRule(ctx context.Context, r *entity.FilterRule) error
+	DeleteFilterRule(ctx context.Context, id int64) error
+	GetFilterSettings(ctx context.Context) (*entity.FilterSettings, error)
+	UpdateFilterSettings(ctx context.Context, s *entity.FilterSettings) error
+	TestContent(ctx context.Context, text string) (*filter.Verdict, error)
+}
+
+type MentionUsecase interface {
+	ListMyMentions(ctx context.Context, limit, offset int) ([]*entity.Mention, int64, error)
+}
+
+type NotificationUsecase interface {
+	// ListNotifications возвращает страницу уведомлений, общее число подходящих и число непрочитанных
+	ListNotifications(ctx context.Context, unreadOnly bool, limit, offset int) ([]*entity.Notification, int64, int64, error)
+	MarkNotificationRead(ctx context.Context, id int64) error
+	MarkAllNotificationsRead(ctx context.Context) (int64, error)
+}
+
+type SubscriptionUsecase interface {
+	FollowTopic(ctx context.Context, topicID int64) error
+	UnfollowTopic(ctx context.Context, topicID int64) error
+	FollowCategory(ctx context.Context, categoryID int64) error
+	UnfollowCategory(ctx context.Context, categoryID int64) error
+	ListFollows(ctx context.Context) ([]*entity.Subscription, error)
+	GetDigestSettings(ctx context.Context) (*entity.DigestSettings, error)
+	UpdateDigestSettings(ctx context.Context, frequency string) (*entity.DigestSettings, error)
+	// Unsubscribe выключает дайджест по токену из письма, без входа
+	Unsubscribe(ctx context.Context, token string) error
+	// SendDigests отправляет дайджесты всем, кому к now пора; возвращает число писем
+	SendDigests(ctx context.Context, now time.Time) (int, error)
+}
+
+type ReadUsecase interface {
+	// MarkTopicRead сдвигает позицию чтения до messageID; nil — до конца топика
+	MarkTopicRead(ctx context.Context, topicID int64, messageID *int64) (*entity.TopicRead, error)
+	Unread(ctx context.Context, limit int) (*entity.UnreadSummary, error)
+	// AnnotateTopics и AnnotateCategories добавляют к спискам непрочитанное текущего пользователя, если он вошёл
+	AnnotateTopics(ctx context.Context, list ...*entity.Topic)
+	AnnotateCategories(ctx context.Context, list []*entity.Category)
+}
+
+type ConversationUsecase interface {
+	StartConversation(ctx context.Context, p StartConversationParams) (*entity.Conversation, error)
+	GetConversation(ctx context.Context, id int64) (*entity.Conversation, error)
+	ListConversations(ctx context.Context, limit, offset int) ([]*entity.Conversation, int64, error)
+	SendMessage(ctx context.Context, conversationID int64, content string) (*entity.PrivateMessage, error)
+	// GetMessages возвращает сообщения старше beforeID (0 — последние), новые сверху
+	GetMessages(ctx context.Context, conversationID, beforeID int64, limit int) ([]*entity.PrivateMessage, error)
+	DeleteMessage(ctx context.Context, conversationID, id int64) error
+	MarkRead(ctx context.Context, conversationID int64, messageID *int64) (int64, error)
+	AddMember(ctx context.Context, conversationID, userID int64) error
+	LeaveConversation(ctx context.Context, conversationID int64) error
+	BlockUser(ctx context.Context, userID int64) error
+	UnblockUser(ctx context.Context, userID int64) error
+	ListBlockedUsers(ctx context.Context) ([]*entity.UserBlock, error)
+}
+
+type RoomUsecase interface {
+	ListRooms(ctx context.Context) ([]*entity.Room, error)
+	GetRoom(ctx context.Context, id int64) (*entity.Room, error)
+	GetRoomBySlug(ctx context.Context, slug string) (*entity.Room, error)
+	CreateRoom(ctx context.Context, p RoomParams) (*entity.Room, error)
+	UpdateRoom(ctx context.Context, id int64, p RoomParams) (*entity.Room, error)
+	DeleteRoom(ctx context.Context, id int64) error
+	// GetMessages возвращает сообщения старше beforeID (0 — последние), новые сверху
+	GetMessages(ctx context.Context, roomID, beforeID int64, limit int) ([]*entity.RoomMessage, error)
+	SendMessage(ctx context.Context, roomID int64, content string) (*entity.RoomMessage, error)
+	UpdateMessage(ctx context.Context, roomID, id int64, content string) (*entity.RoomMessage, error)
+	DeleteMessage(ctx context.Context, roomID, id int64) error
+	Presence(ctx context.Context, roomID int64) (*entity.RoomPresence, error)
+	BroadcastPresence(ctx context.Context, roomID int64)
+	CleanupRoomMessages(ctx context.Context, threshold time.Time, batch int) error
+	PurgeDeletedRoomMessages(ctx context.Context, threshold time.Time) error
+}
+
+type AttachmentUsecase interface {
+	Upload(ctx context.Context, p UploadParams) (*entity.Attachment, error)
+	// Open отдаёт содержимое вложения или его миниатюры; закрыть поток — на вызывающем
+	Open(ctx context.Context, id int64, thumbnail bool) (*entity.Attachment, io.ReadCloser, error)
+	Delete(ctx context.Context, id int64) error
+	CleanupOrphans(ctx context.Context, threshold time.Time, batch int) error
+}
+
+type ProfileUsecase interface {
+	GetProfile(ctx context.Context, userID int64) (*entity.Profile, error)
+	GetProfileByUsername(ctx context.Context, username string) (*entity.Profile, error)
+	UpdateProfile(ctx context.Context, p ProfileParams) (*entity.Profile, error)
+	SetAvatar(ctx context.Context, body io.Reader) (*entity.Profile, error)
+	DeleteAvatar(ctx context.Context) error
+	// OpenAvatar отдаёт картинку аватара; закрыть поток — на вызывающем
+	OpenAvatar(ctx context.Context, userID int64) (io.ReadCloser, error)
+}
+
+type RateLimitUsecase interface {
+	// Allow учитывает действие текущего пользователя с адреса ip; лимит исчерпан — *RateLimitedError
+	Allow(ctx context.Context, action, ip string) (*ratelimit.Result, error)
+	// AllowLimit учитывает действие текущего пользователя в ведре key по лимиту l; лимит исчерпан — *RateLimitedError
+	AllowLimit(ctx context.Context, key string, l ratelimit.Limit) (*ratelimit.Result, error)
+	PurgeRateLimits(ctx context.Context) error
 }
@@ -0,0 +1,455 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	repoErr "chat-service/internal/errors"
+	"chat-service/internal/markdown"
+	"chat-service/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/logger"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	maxConversationMembers = 10  // сколько человек, считая автора, может быть в групповой переписке
+	maxConversationPage    = 200 // больше переписок или сообщений за раз не отдаётся
+)
+
+var (
+	ErrConversationNotFound = errors.New("conversation not found")
+	ErrNoRecipients         = errors.New("a conversation needs at least one other user")
+	ErrConversationTooLarge = fmt.Errorf("a group conversation can have at most %d members", maxConversationMembers)
+	ErrRecipientNotFound    = errors.New("user not found")
+	ErrMessagingBlocked     = errors.New("this user does not accept private messages from you")
+	ErrYouBlockedUser       = errors.New("you have blocked this user; unblock them first")
+	ErrBannedFromMessaging  = errors.New("blocked users cannot start conversations")
+	ErrNotGroupConversation = errors.New("only group conversations can change members")
+	ErrAlreadyMember        = errors.New("user is already in the conversation")
+	ErrEmptyMessage         = errors.New("message is empty")
+	ErrCannotBlockSelf      = errors.New("you cannot block yourself")
+	ErrNotBlocked           = errors.New("this user is not blocked")
+)
+
+// ConversationPublisher доставляет события переписки: в её канал и в личные каналы участников
+type ConversationPublisher interface {
+	PublishToUser(userID int64, ev *entity.WSEvent)
+	PublishToConversation(conversationID int64, ev *entity.WSEvent)
+}
+
+type ConversationUC struct {
+	repo      repo.ConversationRepository
+	access    access
+	content   ContentChecker
+	privilege PrivilegeChecker
+	publisher ConversationPublisher
+	log       logger.Interface
+}
+
+func NewConversationUsecase(r repo.ConversationRepository, cc ContentChecker, pc PrivilegeChecker, p ConversationPublisher, l logger.Interface) *ConversationUC {
+	return &ConversationUC{repo: r, content: cc, privilege: pc, publisher: p, log: l}
+}
+
+// StartConversation начинает переписку текущего пользователя с p.UserIDs: с одним собеседником —
+// один на один (если такая уже есть, возвращается она), с несколькими — группу.
+// Заблокированный на форуме не может начинать переписки; писать тому, кто запретил ему это, тоже нельзя.
+func (uc *ConversationUC) StartConversation(ctx context.Context, p StartConversationParams) (*entity.Conversation, error) {
+	uc.log.Debug("StartConversation called", "user_ids", p.UserIDs)
+
+	userID, err := uc.access.check(ctx, auth.PermConversationWrite, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(p.UserIDs))
+	for _, id := range p.UserIDs {
+		if id != userID && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, ErrNoRecipients
+	}
+	if len(ids)+1 > maxConversationMembers {
+		return nil, ErrConversationTooLarge
+	}
+
+	banned, err := uc.repo.IsBanned(ctx, userID)
+	if err != nil {
+		uc.log.Error("repo.IsBanned failed", "err", err)
+		return nil, fmt.Errorf("ConversationUC.Start#banned: %w", err)
+	}
+	if banned {
+		uc.log.Info("conversation rejected: author is banned", "user_id", userID)
+		return nil, ErrBannedFromMessaging
+	}
+	if err := uc.checkCorrespondents(ctx, userID, ids); err != nil {
+		return nil, err
+	}
+	// первое сообщение проверяется до создания переписки: отклонённое не должно оставлять пустую
+	first := strings.TrimSpace(p.Content) != ""
+	content := p.Content
+	if first {
+		if err := requireLinks(ctx, uc.privilege, content); err != nil {
+			return nil, err
+		}
+		if content, _, err = screen(ctx, uc.content, uc.log, userID, content, false); err != nil {
+			return nil, err
+		}
+	}
+
+	c := &entity.Conversation{IsGroup: len(ids) > 1, CreatedBy: &userID}
+	if c.IsGroup {
+		c.Title = strings.TrimSpace(p.Title)
+	}
+	created, err := uc.repo.Create(ctx, c, append([]int64{userID}, ids...))
+	if errors.Is(err, repoErr.ErrInvalidReference) {
+		return nil, ErrRecipientNotFound
+	} else if err != nil {
+		uc.log.Error("repo.Create failed", "err", err)
+		return nil, fmt.Errorf("ConversationUC.Start: %w", err)
+	}
+	if created {
+		uc.log.Info("conversation started", "id", c.ID, "user_id", userID, "members", len(ids)+1)
+	}
+
+	if first {
+		full, _, err := uc.membership(ctx, c.ID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := uc.deliver(ctx, full, userID, content); err != nil {
+			return nil, err
+		}
+	}
+	return uc.GetConversation(ctx, c.ID)
+}
+
+// checkCorrespondents проверяет, что все ids существуют и ни один запрет не мешает userID им писать
+func (uc *ConversationUC) checkCorrespondents(ctx context.Context, userID int64, ids []int64) error {
+	list, err := uc.repo.Correspondents(ctx, userID, ids)
+	if err != nil {
+		uc.log.Error("repo.Correspondents failed", "err", err)
+		return fmt.Errorf("ConversationUC.checkCorrespondents: %w", err)
+	}
+	if len(list) != len(ids) {
+		return ErrRecipientNotFound
+	}
+	for _, c := range list {
+		if c.BlocksSender {
+			uc.log.Info("private message rejected: blocked by recipient", "user_id", userID, "recipient_id", c.UserID)
+			return ErrMessagingBlocked
+		}
+		if c.BlockedBySender {
+			return ErrYouBlockedUser
+		}
+	}
+	return nil
+}
+
+// membership возвращает переписку, если текущий пользователь в ней состоит.
+// Для остальных переписка выглядит несуществующей.
+func (uc *ConversationUC) membership(ctx context.Context, conversationID int64) (*entity.Conversation, int64, error) {
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 {
+		return nil, 0, ErrUnauthenticated
+	}
+
+	c, err := uc.repo.GetByID(ctx, conversationID)
+	if errors.Is(err, repoErr.ErrNotFound) || (err == nil && !c.HasMember(userID)) {
+		uc.log.Info("conversation not found for user", "id", conversationID, "user_id", userID)
+		return nil, 0, ErrConversationNotFound
+	} else if err != nil {
+		uc.log.Error("repo.GetByID failed", "err", err)
+		return nil, 0, fmt.Errorf("ConversationUC.membership: %w", err)
+	}
+	return c, userID, nil
+}
+
+// GetConversation возвращает переписку с участниками, если текущий пользователь в ней состоит
+func (uc *ConversationUC) GetConversation(ctx context.Context, id int64) (*entity.Conversation, error) {
+	c, _, err := uc.membership(ctx, id)
+	return c, err
+}
+
+// ListConversations возвращает страницу переписок текущего пользователя и общее их число
+func (uc *ConversationUC) ListConversations(ctx context.Context, limit, offset int) ([]*entity.Conversation, int64, error) {
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 {
+		return nil, 0, ErrUnauthenticated
+	}
+	limit, offset = min(max(limit, 1), maxConversationPage), max(offset, 0)
+
+	list, total, err := uc.repo.ListByUser(ctx, userID, limit, offset)
+	if err != nil {
+		uc.log.Error("repo.ListByUser failed", "err", err)
+		return nil, 0, fmt.Errorf("ConversationUC.List: %w", err)
+	}
+	for _, c := range list {
+		if c.LastMessage != nil {
+			renderPrivate(c.LastMessage)
+		}
+	}
+	return list, total, nil
+}
+
+// SendMessage пишет в переписку от имени текущего пользователя и рассылает сообщение участникам.
+// В переписке один на один запрет писать действует и на уже начатую переписку.
+func (uc *ConversationUC) SendMessage(ctx context.Context, conversationID int64, content string) (*entity.PrivateMessage, error) {
+	uc.log.Debug("ConversationUC.SendMessage called", "conversation_id", conversationID)
+
+	if _, err := uc.access.check(ctx, auth.PermConversationWrite, nil); err != nil {
+		return nil, err
+	}
+	c, userID, err := uc.membership(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, ErrEmptyMessage
+	}
+	if !c.IsGroup {
+		for _, m := range c.Members {
+			if m.UserID == userID {
+				continue
+			}
+			if err := uc.checkCorrespondents(ctx, userID, []int64{m.UserID}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := requireLinks(ctx, uc.privilege, content); err != nil {
+		return nil, err
+	}
+	// личные сообщения проверяются фильтром, но в очередь жалоб не попадают
+	content, _, err = screen(ctx, uc.content, uc.log, userID, content, false)
+	if err != nil {
+		return nil, err
+	}
+	return uc.deliver(ctx, c, userID, content)
+}
+
+// deliver сохраняет уже проверенное сообщение и рассылает его участникам переписки
+func (uc *ConversationUC) deliver(ctx context.Context, c *entity.Conversation, userID int64, content string) (*entity.PrivateMessage, error) {
+	m := &entity.PrivateMessage{
+		ConversationID: c.ID,
+		AuthorID:       userID,
+		Content:        content,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err := uc.repo.CreateMessage(ctx, m); err != nil {
+		uc.log.Error("repo.CreateMessage failed", "err", err)
+		return nil, fmt.Errorf("ConversationUC.deliver: %w", err)
+	}
+	if me := c.Member(userID); me != nil {
+		m.AuthorName = me.Name
+	}
+	renderPrivate(m)
+
+	if uc.publisher != nil {
+		uc.publisher.PublishToConversation(c.ID, &entity.WSEvent{Action: entity.ActionCreated, ConversationID: c.ID, PrivateMessage: m})
+		for _, member := range c.Members {
+			uc.publisher.PublishToUser(member.UserID, &entity.WSEvent{Action: entity.ActionConversationMessage, ConversationID: c.ID, PrivateMessage: m})
+		}
+	}
+
+	uc.log.Info("private message sent", "id", m.ID, "conversation_id", c.ID, "author_id", userID)
+	return m, nil
+}
+
+// GetMessages возвращает не больше limit сообщений переписки старше beforeID (0 — последние), новые сверху
+func (uc *ConversationUC) GetMessages(ctx context.Context, conversationID, beforeID int64, limit int) ([]*entity.PrivateMessage, error) {
+	if _, _, err := uc.membership(ctx, conversationID); err != nil {
+		return nil, err
+	}
+	limit = min(max(limit, 1), maxConversationPage)
+
+	list, err := uc.repo.ListMessages(ctx, conversationID, max(beforeID, 0), limit)
+	if err != nil {
+		uc.log.Error("repo.ListMessages failed", "err", err)
+		return nil, fmt.Errorf("ConversationUC.GetMessages: %w", err)
+	}
+	for _, m := range list {
+		renderPrivate(m)
+	}
+	return list, nil
+}
+
+// DeleteMessage удаляет своё сообщение переписки; текст перестают видеть все участники
+func (uc *ConversationUC) DeleteMessage(ctx context.Context, conversationID, id int64) error {
+	uc.log.Debug("ConversationUC.DeleteMessage called", "conversation_id", conversationID, "id", id)
+
+	_, userID, err := uc.membership(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	m, err := uc.repo.GetMessage(ctx, id)
+	if errors.Is(err, repoErr.ErrNotFound) || (err == nil && (m.IsDeleted() || m.ConversationID != conversationID)) {
+		return ErrMessageNotFound
+	} else if err != nil {
+		uc.log.Error("repo.GetMessage failed", "err", err)
+		return fmt.Errorf("ConversationUC.DeleteMessage#get: %w", err)
+	}
+	if m.AuthorID != userID {
+		return ErrForbidden
+	}
+
+	if err := uc.repo.DeleteMessage(ctx, id); errors.Is(err, repoErr.ErrNotFound) {
+		return ErrMessageNotFound
+	} else if err != nil {
+		uc.log.Error("repo.DeleteMessage failed", "err", err)
+		return fmt.Errorf("ConversationUC.DeleteMessage: %w", err)
+	}
+	if uc.publisher != nil {
+		uc.publisher.PublishToConversation(conversationID, &entity.WSEvent{Action: entity.ActionDeleted, ConversationID: conversationID, MessageID: id})
+	}
+
+	uc.log.Info("private message deleted", "id", id, "user_id", userID)
+	return nil
+}
+
+// MarkRead сдвигает позицию чтения текущего пользователя до messageID (nil — до конца) и возвращает её
+func (uc *ConversationUC) MarkRead(ctx context.Context, conversationID int64, messageID *int64) (int64, error) {
+	_, userID, err := uc.membership(ctx, conversationID)
+	if err != nil {
+		return 0, err
+	}
+
+	pos, err := uc.repo.MarkRead(ctx, conversationID, userID, messageID)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		return 0, ErrMessageNotFound
+	} else if err != nil {
+		uc.log.Error("repo.MarkRead failed", "err", err)
+		return 0, fmt.Errorf("ConversationUC.MarkRead: %w", err)
+	}
+	if uc.publisher != nil {
+		uc.publisher.PublishToUser(userID, &entity.WSEvent{Action: entity.ActionConversationRead, ConversationID: conversationID, MessageID: pos})
+	}
+	return pos, nil
+}
+
+// AddMember добавляет пользователя в групповую переписку; добавить может любой участник,
+// если между ним и новым участником нет запретов
+func (uc *ConversationUC) AddMember(ctx context.Context, conversationID, userID int64) error {
+	uc.log.Debug("AddMember called", "conversation_id", conversationID, "user_id", userID)
+
+	if _, err := uc.access.check(ctx, auth.PermConversationWrite, nil); err != nil {
+		return err
+	}
+	c, actorID, err := uc.membership(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	if !c.IsGroup {
+		return ErrNotGroupConversation
+	}
+	if c.HasMember(userID) {
+		return ErrAlreadyMember
+	}
+	if len(c.Members) >= maxConversationMembers {
+		return ErrConversationTooLarge
+	}
+	if err := uc.checkCorrespondents(ctx, actorID, []int64{userID}); err != nil {
+		return err
+	}
+
+	err = uc.repo.AddMember(ctx, conversationID, userID)
+	switch {
+	case errors.Is(err, repoErr.ErrConflict):
+		return ErrAlreadyMember
+	case errors.Is(err, repoErr.ErrInvalidReference):
+		return ErrRecipientNotFound
+	case err != nil:
+		uc.log.Error("repo.AddMember failed", "err", err)
+		return fmt.Errorf("ConversationUC.AddMember: %w", err)
+	}
+
+	uc.log.Info("conversation member added", "conversation_id", conversationID, "user_id", userID, "by", actorID)
+	return nil
+}
+
+// LeaveConversation выводит текущего пользователя из групповой переписки
+func (uc *ConversationUC) LeaveConversation(ctx context.Context, conversationID int64) error {
+	c, userID, err := uc.membership(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	if !c.IsGroup {
+		return ErrNotGroupConversation
+	}
+
+	if err := uc.repo.RemoveMember(ctx, conversationID, userID); errors.Is(err, repoErr.ErrNotFound) {
+		return ErrConversationNotFound
+	} else if err != nil {
+		uc.log.Error("repo.RemoveMember failed", "err", err)
+		return fmt.Errorf("ConversationUC.Leave: %w", err)
+	}
+
+	uc.log.Info("conversation left", "conversation_id", conversationID, "user_id", userID)
+	return nil
+}
+
+// BlockUser запрещает пользователю писать текущему: новые переписки с ним и сообщения
+// в переписке один на один отклоняются; общие группы остаются
+func (uc *ConversationUC) BlockUser(ctx context.Context, blockedID int64) error {
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 {
+		return ErrUnauthenticated
+	}
+	if blockedID == userID {
+		return ErrCannotBlockSelf
+	}
+
+	if err := uc.repo.Block(ctx, userID, blockedID); errors.Is(err, repoErr.ErrInvalidReference) {
+		return ErrRecipientNotFound
+	} else if err != nil {
+		uc.log.Error("repo.Block failed", "err", err)
+		return fmt.Errorf("ConversationUC.BlockUser: %w", err)
+	}
+
+	uc.log.Info("user blocked from messaging", "user_id", userID, "blocked_id", blockedID)
+	return nil
+}
+
+// UnblockUser снимает запрет писать текущему пользователю
+func (uc *ConversationUC) UnblockUser(ctx context.Context, blockedID int64) error {
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 {
+		return ErrUnauthenticated
+	}
+
+	if err := uc.repo.Unblock(ctx, userID, blockedID); errors.Is(err, repoErr.ErrNotFound) {
+		return ErrNotBlocked
+	} else if err != nil {
+		uc.log.Error("repo.Unblock failed", "err", err)
+		return fmt.Errorf("ConversationUC.UnblockUser: %w", err)
+	}
+
+	uc.log.Info("user unblocked from messaging", "user_id", userID, "blocked_id", blockedID)
+	return nil
+}
+
+// ListBlockedUsers возвращает, кому текущий пользователь запретил писать себе
+func (uc *ConversationUC) ListBlockedUsers(ctx context.Context) ([]*entity.UserBlock, error) {
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 {
+		return nil, ErrUnauthenticated
+	}
+
+	list, err := uc.repo.ListBlocks(ctx, userID)
+	if err != nil {
+		uc.log.Error("repo.ListBlocks failed", "err", err)
+		return nil, fmt.Errorf("ConversationUC.ListBlockedUsers: %w", err)
+	}
+	return list, nil
+}
+
+func renderPrivate(m *entity.PrivateMessage) {
+	if !m.IsDeleted() {
+		m.ContentHTML = markdown.Render(m.Content)
+	}
+}
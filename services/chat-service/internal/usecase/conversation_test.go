@@ -0,0 +1,387 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	customErr "chat-service/internal/errors"
+	"chat-service/internal/usecase/mocks"
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func direct(id int64, members ...int64) *entity.Conversation {
+	c := &entity.Conversation{ID: id}
+	for _, m := range members {
+		c.Members = append(c.Members, &entity.ConversationMember{UserID: m})
+	}
+	return c
+}
+
+func TestConversationUC_StartConversation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockConversationRepository(ctrl)
+	pub := mocks.NewMockConversationPublisher(ctrl)
+	uc := NewConversationUsecase(repo, nil, nil, pub, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 1, "user")
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := uc.StartConversation(context.Background(), StartConversationParams{UserIDs: []int64{2}})
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	t.Run("only myself", func(t *testing.T) {
+		_, err := uc.StartConversation(ctx, StartConversationParams{UserIDs: []int64{1, 1}})
+		require.ErrorIs(t, err, ErrNoRecipients)
+	})
+
+	t.Run("too many members", func(t *testing.T) {
+		_, err := uc.StartConversation(ctx, StartConversationParams{UserIDs: []int64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11}})
+		require.ErrorIs(t, err, ErrConversationTooLarge)
+	})
+
+	t.Run("banned author", func(t *testing.T) {
+		repo.EXPECT().IsBanned(ctx, int64(1)).Return(true, nil)
+		_, err := uc.StartConversation(ctx, StartConversationParams{UserIDs: []int64{2}})
+		require.ErrorIs(t, err, ErrBannedFromMessaging)
+	})
+
+	t.Run("recipient blocked me", func(t *testing.T) {
+		repo.EXPECT().IsBanned(ctx, int64(1)).Return(false, nil)
+		repo.EXPECT().Correspondents(ctx, int64(1), []int64{2}).
+			Return([]*entity.Correspondent{{UserID: 2, BlocksSender: true}}, nil)
+		_, err := uc.StartConversation(ctx, StartConversationParams{UserIDs: []int64{2}})
+		require.ErrorIs(t, err, ErrMessagingBlocked)
+	})
+
+	t.Run("I blocked the recipient", func(t *testing.T) {
+		repo.EXPECT().IsBanned(ctx, int64(1)).Return(false, nil)
+		repo.EXPECT().Correspondents(ctx, int64(1), []int64{2}).
+			Return([]*entity.Correspondent{{UserID: 2, BlockedBySender: true}}, nil)
+		_, err := uc.StartConversation(ctx, StartConversationParams{UserIDs: []int64{2}})
+		require.ErrorIs(t, err, ErrYouBlockedUser)
+	})
+
+	t.Run("unknown recipient", func(t *testing.T) {
+		repo.EXPECT().IsBanned(ctx, int64(1)).Return(false, nil)
+		repo.EXPECT().Correspondents(ctx, int64(1), []int64{2, 99}).
+			Return([]*entity.Correspondent{{UserID: 2}}, nil)
+		_, err := uc.StartConversation(ctx, StartConversationParams{UserIDs: []int64{2, 99}})
+		require.ErrorIs(t, err, ErrRecipientNotFound)
+	})
+
+	t.Run("direct conversation is reused", func(t *testing.T) {
+		repo.EXPECT().IsBanned(ctx, int64(1)).Return(false, nil)
+		repo.EXPECT().Correspondents(ctx, int64(1), []int64{2}).Return([]*entity.Correspondent{{UserID: 2}}, nil)
+		repo.EXPECT().Create(ctx, gomock.Any(), []int64{1, 2}).
+			DoAndReturn(func(_ context.Context, c *entity.Conversation, _ []int64) (bool, error) {
+				require.False(t, c.IsGroup)
+				require.Empty(t, c.Title)
+				c.ID = 5
+				return false, nil
+			})
+		repo.EXPECT().GetByID(ctx, int64(5)).Return(direct(5, 1, 2), nil)
+
+		c, err := uc.StartConversation(ctx, StartConversationParams{UserIDs: []int64{2, 1}, Title: "ignored"})
+		require.NoError(t, err)
+		require.Equal(t, int64(5), c.ID)
+	})
+
+	t.Run("group with a first message", func(t *testing.T) {
+		group := direct(6, 1, 2, 3)
+		group.IsGroup = true
+		repo.EXPECT().IsBanned(ctx, int64(1)).Return(false, nil)
+		repo.EXPECT().Correspondents(ctx, int64(1), []int64{2, 3}).
+			Return([]*entity.Correspondent{{UserID: 2}, {UserID: 3}}, nil)
+		repo.EXPECT().Create(ctx, gomock.Any(), []int64{1, 2, 3}).
+			DoAndReturn(func(_ context.Context, c *entity.Conversation, _ []int64) (bool, error) {
+				require.True(t, c.IsGroup)
+				require.Equal(t, "Team", c.Title)
+				c.ID = 6
+				return true, nil
+			})
+		repo.EXPECT().GetByID(ctx, int64(6)).Return(group, nil).Times(2)
+		repo.EXPECT().CreateMessage(ctx, gomock.Any()).Return(nil)
+		pub.EXPECT().PublishToConversation(int64(6), gomock.Any())
+		pub.EXPECT().PublishToUser(gomock.Any(), gomock.Any()).Times(3)
+
+		c, err := uc.StartConversation(ctx, StartConversationParams{UserIDs: []int64{2, 3}, Title: " Team ", Content: "hi"})
+		require.NoError(t, err)
+		require.Equal(t, int64(6), c.ID)
+	})
+}
+
+func TestConversationUC_SendMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockConversationRepository(ctrl)
+	pub := mocks.NewMockConversationPublisher(ctrl)
+	uc := NewConversationUsecase(repo, nil, nil, pub, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 1, "user")
+
+	t.Run("not a member", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(5)).Return(direct(5, 2, 3), nil)
+		_, err := uc.SendMessage(ctx, 5, "hi")
+		require.ErrorIs(t, err, ErrConversationNotFound)
+	})
+
+	t.Run("missing conversation", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(5)).Return(nil, customErr.ErrNotFound)
+		_, err := uc.SendMessage(ctx, 5, "hi")
+		require.ErrorIs(t, err, ErrConversationNotFound)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(5)).Return(direct(5, 1, 2), nil)
+		_, err := uc.SendMessage(ctx, 5, "  ")
+		require.ErrorIs(t, err, ErrEmptyMessage)
+	})
+
+	t.Run("blocked in a direct conversation", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(5)).Return(direct(5, 1, 2), nil)
+		repo.EXPECT().Correspondents(ctx, int64(1), []int64{2}).
+			Return([]*entity.Correspondent{{UserID: 2, BlocksSender: true}}, nil)
+		_, err := uc.SendMessage(ctx, 5, "hi")
+		require.ErrorIs(t, err, ErrMessagingBlocked)
+	})
+
+	t.Run("delivered to the conversation and every member", func(t *testing.T) {
+		c := direct(5, 1, 2)
+		c.Members[0].Name = "alice"
+		repo.EXPECT().GetByID(ctx, int64(5)).Return(c, nil)
+		repo.EXPECT().Correspondents(ctx, int64(1), []int64{2}).Return([]*entity.Correspondent{{UserID: 2}}, nil)
+		repo.EXPECT().CreateMessage(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, m *entity.PrivateMessage) error {
+			m.ID = 40
+			return nil
+		})
+		pub.EXPECT().PublishToConversation(int64(5), gomock.Any())
+		pub.EXPECT().PublishToUser(int64(1), gomock.Any())
+		pub.EXPECT().PublishToUser(int64(2), gomock.Any())
+
+		m, err := uc.SendMessage(ctx, 5, "**hi**")
+		require.NoError(t, err)
+		require.Equal(t, int64(40), m.ID)
+		require.Equal(t, "alice", m.AuthorName)
+		require.Contains(t, m.ContentHTML, "<strong>hi</strong>")
+	})
+}
+
+func TestConversationUC_DeleteMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockConversationRepository(ctrl)
+	pub := mocks.NewMockConversationPublisher(ctrl)
+	uc := NewConversationUsecase(repo, nil, nil, pub, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 1, "user")
+
+	t.Run("someone else's message", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(5)).Return(direct(5, 1, 2), nil)
+		repo.EXPECT().GetMessage(ctx, int64(40)).Return(&entity.PrivateMessage{ID: 40, ConversationID: 5, AuthorID: 2}, nil)
+		require.ErrorIs(t, uc.DeleteMessage(ctx, 5, 40), ErrForbidden)
+	})
+
+	t.Run("message from another conversation", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(5)).Return(direct(5, 1, 2), nil)
+		repo.EXPECT().GetMessage(ctx, int64(40)).Return(&entity.PrivateMessage{ID: 40, ConversationID: 7, AuthorID: 1}, nil)
+		require.ErrorIs(t, uc.DeleteMessage(ctx, 5, 40), ErrMessageNotFound)
+	})
+
+	t.Run("already deleted", func(t *testing.T) {
+		deleted := time.Now()
+		repo.EXPECT().GetByID(ctx, int64(5)).Return(direct(5, 1, 2), nil)
+		repo.EXPECT().GetMessage(ctx, int64(40)).
+			Return(&entity.PrivateMessage{ID: 40, ConversationID: 5, AuthorID: 1, DeletedAt: &deleted}, nil)
+		require.ErrorIs(t, uc.DeleteMessage(ctx, 5, 40), ErrMessageNotFound)
+	})
+
+	t.Run("own message", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(5)).Return(direct(5, 1, 2), nil)
+		repo.EXPECT().GetMessage(ctx, int64(40)).Return(&entity.PrivateMessage{ID: 40, ConversationID: 5, AuthorID: 1}, nil)
+		repo.EXPECT().DeleteMessage(ctx, int64(40)).Return(nil)
+		pub.EXPECT().PublishToConversation(int64(5), &entity.WSEvent{Action: entity.ActionDeleted, ConversationID: 5, MessageID: 40})
+		require.NoError(t, uc.DeleteMessage(ctx, 5, 40))
+	})
+}
+
+func TestConversationUC_MarkRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockConversationRepository(ctrl)
+	pub := mocks.NewMockConversationPublisher(ctrl)
+	uc := NewConversationUsecase(repo, nil, nil, pub, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 1, "user")
+
+	t.Run("to the end", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(5)).Return(direct(5, 1, 2), nil)
+		repo.EXPECT().MarkRead(ctx, int64(5), int64(1), nil).Return(int64(44), nil)
+		pub.EXPECT().PublishToUser(int64(1), &entity.WSEvent{Action: entity.ActionConversationRead, ConversationID: 5, MessageID: 44})
+
+		pos, err := uc.MarkRead(ctx, 5, nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(44), pos)
+	})
+
+	t.Run("message from another conversation", func(t *testing.T) {
+		msgID := int64(90)
+		repo.EXPECT().GetByID(ctx, int64(5)).Return(direct(5, 1, 2), nil)
+		repo.EXPECT().MarkRead(ctx, int64(5), int64(1), &msgID).Return(int64(0), customErr.ErrNotFound)
+		_, err := uc.MarkRead(ctx, 5, &msgID)
+		require.ErrorIs(t, err, ErrMessageNotFound)
+	})
+}
+
+func TestConversationUC_Members(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockConversationRepository(ctrl)
+	uc := NewConversationUsecase(repo, nil, nil, nil, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 1, "user")
+	group := func() *entity.Conversation {
+		c := direct(6, 1, 2)
+		c.IsGroup = true
+		return c
+	}
+
+	t.Run("direct conversation cannot grow", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(5)).Return(direct(5, 1, 2), nil)
+		require.ErrorIs(t, uc.AddMember(ctx, 5, 3), ErrNotGroupConversation)
+	})
+
+	t.Run("already a member", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(6)).Return(group(), nil)
+		require.ErrorIs(t, uc.AddMember(ctx, 6, 2), ErrAlreadyMember)
+	})
+
+	t.Run("new member blocked me", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(6)).Return(group(), nil)
+		repo.EXPECT().Correspondents(ctx, int64(1), []int64{3}).
+			Return([]*entity.Correspondent{{UserID: 3, BlocksSender: true}}, nil)
+		require.ErrorIs(t, uc.AddMember(ctx, 6, 3), ErrMessagingBlocked)
+	})
+
+	t.Run("add", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(6)).Return(group(), nil)
+		repo.EXPECT().Correspondents(ctx, int64(1), []int64{3}).Return([]*entity.Correspondent{{UserID: 3}}, nil)
+		repo.EXPECT().AddMember(ctx, int64(6), int64(3)).Return(nil)
+		require.NoError(t, uc.AddMember(ctx, 6, 3))
+	})
+
+	t.Run("direct conversation cannot be left", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(5)).Return(direct(5, 1, 2), nil)
+		require.ErrorIs(t, uc.LeaveConversation(ctx, 5), ErrNotGroupConversation)
+	})
+
+	t.Run("leave", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(6)).Return(group(), nil)
+		repo.EXPECT().RemoveMember(ctx, int64(6), int64(1)).Return(nil)
+		require.NoError(t, uc.LeaveConversation(ctx, 6))
+	})
+}
+
+func TestConversationUC_Blocks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockConversationRepository(ctrl)
+	uc := NewConversationUsecase(repo, nil, nil, nil, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 1, "user")
+
+	t.Run("self", func(t *testing.T) {
+		require.ErrorIs(t, uc.BlockUser(ctx, 1), ErrCannotBlockSelf)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		repo.EXPECT().Block(ctx, int64(1), int64(99)).Return(customErr.ErrInvalidReference)
+		require.ErrorIs(t, uc.BlockUser(ctx, 99), ErrRecipientNotFound)
+	})
+
+	t.Run("block", func(t *testing.T) {
+		repo.EXPECT().Block(ctx, int64(1), int64(2)).Return(nil)
+		require.NoError(t, uc.BlockUser(ctx, 2))
+	})
+
+	t.Run("unblock someone not blocked", func(t *testing.T) {
+		repo.EXPECT().Unblock(ctx, int64(1), int64(2)).Return(customErr.ErrNotFound)
+		require.ErrorIs(t, uc.UnblockUser(ctx, 2), ErrNotBlocked)
+	})
+}
+
+func TestConversationUC_Links(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockConversationRepository(ctrl)
+	privileges := mocks.NewMockPrivilegeChecker(ctrl)
+	uc := NewConversationUsecase(repo, nil, privileges, nil, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 1, "user")
+	denied := &InsufficientReputationError{Privilege: entity.PrivilegePostLinks, Required: 3}
+
+	t.Run("message with a link needs reputation", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(5)).Return(direct(5, 1, 2), nil)
+		repo.EXPECT().Correspondents(ctx, int64(1), []int64{2}).Return([]*entity.Correspondent{{UserID: 2}}, nil)
+		privileges.EXPECT().Require(ctx, entity.PrivilegePostLinks).Return(denied)
+
+		_, err := uc.SendMessage(ctx, 5, "see https://example.com")
+		require.ErrorIs(t, err, ErrInsufficientReputation)
+	})
+
+	t.Run("first message with a link is refused before the conversation is created", func(t *testing.T) {
+		repo.EXPECT().IsBanned(ctx, int64(1)).Return(false, nil)
+		repo.EXPECT().Correspondents(ctx, int64(1), []int64{2}).Return([]*entity.Correspondent{{UserID: 2}}, nil)
+		privileges.EXPECT().Require(ctx, entity.PrivilegePostLinks).Return(denied)
+
+		_, err := uc.StartConversation(ctx, StartConversationParams{UserIDs: []int64{2}, Content: "<https://example.com>"})
+		require.ErrorIs(t, err, ErrInsufficientReputation)
+	})
+}
+
+func TestConversationUC_StartConversation_Filter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rules := []*entity.FilterRule{
+		{ID: 1, Kind: entity.FilterWord, Pattern: "сука", Action: entity.FilterReject},
+		{ID: 2, Kind: entity.FilterWord, Pattern: "дурак", Action: entity.FilterMask},
+	}
+	s := filterSettings()
+	s.DuplicateWindow = 0
+	filterUC, _, _, _ := newTestFilterUC(ctrl, rules, s)
+
+	repo := mocks.NewMockConversationRepository(ctrl)
+	uc := NewConversationUsecase(repo, filterUC, nil, nil, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 1, "user")
+
+	t.Run("rejected first message creates nothing", func(t *testing.T) {
+		repo.EXPECT().IsBanned(ctx, int64(1)).Return(false, nil)
+		repo.EXPECT().Correspondents(ctx, int64(1), []int64{2}).Return([]*entity.Correspondent{{UserID: 2}}, nil)
+
+		_, err := uc.StartConversation(ctx, StartConversationParams{UserIDs: []int64{2}, Content: "сука"})
+		require.ErrorIs(t, err, ErrContentRejected)
+	})
+
+	t.Run("first message is saved as screened", func(t *testing.T) {
+		repo.EXPECT().IsBanned(ctx, int64(1)).Return(false, nil)
+		repo.EXPECT().Correspondents(ctx, int64(1), []int64{2}).Return([]*entity.Correspondent{{UserID: 2}}, nil)
+		repo.EXPECT().Create(ctx, gomock.Any(), []int64{1, 2}).
+			DoAndReturn(func(_ context.Context, c *entity.Conversation, _ []int64) (bool, error) {
+				c.ID = 5
+				return true, nil
+			})
+		repo.EXPECT().GetByID(ctx, int64(5)).Return(direct(5, 1, 2), nil).Times(2)
+		repo.EXPECT().CreateMessage(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, m *entity.PrivateMessage) error {
+			require.Equal(t, "сам *****", m.Content)
+			return nil
+		})
+
+		_, err := uc.StartConversation(ctx, StartConversationParams{UserIDs: []int64{2}, Content: "сам дурак"})
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"bytes"
+	"chat-service/internal/entity"
+	"chat-service/internal/mailer"
+	"fmt"
+	htmltemplate "html/template"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"text/template"
+	"time"
+)
+
+// digestView — данные для шаблонов письма
+type digestView struct {
+	Name           string
+	Period         string
+	Topics         []digestTopicView
+	UnsubscribeURL string
+}
+
+type digestTopicView struct {
+	Title       string
+	URL         string
+	IsNew       bool
+	NewMessages int64
+	Messages    []digestMessageView
+}
+
+type digestMessageView struct {
+	Author    string
+	Excerpt   string
+	CreatedAt string
+}
+
+var digestFuncs = map[string]any{"messages": messagesWord}
+
+var digestText = template.Must(template.New("digest").Funcs(digestFuncs).Parse(
+	`Здравствуйте, {{.Name}}!
+
+Что нового {{.Period}} в темах, за которыми вы следите.
+{{range .Topics}}
+{{.Title}}{{if .IsNew}} (новая тема){{end}}
+{{if .NewMessages}}{{.NewMessages}} {{messages .NewMessages}}: {{end}}{{.URL}}
+{{range .Messages}}  {{.Author}}, {{.CreatedAt}}: {{.Excerpt}}
+{{end}}{{end}}
+Отписаться от дайджеста: {{.UnsubscribeURL}}
+`))
+
+var digestHTML = htmltemplate.Must(htmltemplate.New("digest").Funcs(digestFuncs).Parse(
+	`<!DOCTYPE html>
+<html lang="ru">
+<body style="font-family: sans-serif; line-height: 1.4">
+<p>Здравствуйте, {{.Name}}!</p>
+<p>Что нового {{.Period}} в темах, за которыми вы следите.</p>
+{{range .Topics}}
+<h3 style="margin-bottom: 4px"><a href="{{.URL}}">{{.Title}}</a>{{if .IsNew}} <small>новая тема</small>{{end}}</h3>
+{{if .NewMessages}}<p style="margin-top: 0; color: #666">{{.NewMessages}} {{messages .NewMessages}}</p>{{end}}
+{{range .Messages}}<blockquote style="margin: 0 0 8px 0; padding-left: 8px; border-left: 3px solid #ddd">
+<b>{{.Author}}</b> <span style="color: #666">{{.CreatedAt}}</span><br>{{.Excerpt}}
+</blockquote>
+{{end}}{{end}}
+<p style="color: #666; font-size: small">Вы получили это письмо, потому что подписаны на темы или разделы форума.
+<a href="{{.UnsubscribeURL}}">Отписаться от дайджеста</a></p>
+</body>
+</html>
+`))
+
+// composeDigest собирает письмо-дайджест: текст, HTML и заголовки для отписки в один клик (RFC 8058)
+func (uc *SubscriptionUC) composeDigest(rc *entity.DigestRecipient, topics []*entity.DigestTopic) (*mailer.Message, error) {
+	period := "за день"
+	if rc.Settings.Frequency == entity.DigestWeekly {
+		period = "за неделю"
+	}
+	unsubscribe := uc.opts.PublicURL + "/digest/unsubscribe?token=" + url.QueryEscape(rc.Settings.UnsubscribeToken)
+
+	v := digestView{Name: rc.Name, Period: period, UnsubscribeURL: unsubscribe}
+	for _, t := range topics {
+		tv := digestTopicView{
+			Title:       t.Title,
+			URL:         uc.opts.SiteURL + "/topics/" + strconv.FormatInt(t.TopicID, 10),
+			IsNew:       t.IsNew,
+			NewMessages: t.NewMessages,
+		}
+		for _, m := range t.Messages {
+			tv.Messages = append(tv.Messages, digestMessageView{
+				Author:    m.AuthorName,
+				Excerpt:   excerpt(m.Content),
+				CreatedAt: m.CreatedAt.UTC().Format(time.DateTime + " MST"),
+			})
+		}
+		v.Topics = append(v.Topics, tv)
+	}
+
+	var text, html bytes.Buffer
+	if err := digestText.Execute(&text, v); err != nil {
+		return nil, fmt.Errorf("text: %w", err)
+	}
+	if err := digestHTML.Execute(&html, v); err != nil {
+		return nil, fmt.Errorf("html: %w", err)
+	}
+	return &mailer.Message{
+		To:      (&mail.Address{Name: rc.Name, Address: rc.Email}).String(),
+		Subject: "Дайджест форума " + period,
+		Text:    text.String(),
+		HTML:    html.String(),
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + unsubscribe + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}, nil
+}
+
+// messagesWord — «сообщение» в нужной форме после числа n
+func messagesWord(n int64) string {
+	switch {
+	case n%100 >= 11 && n%100 <= 14:
+		return "сообщений"
+	case n%10 == 1:
+		return "сообщение"
+	case n%10 >= 2 && n%10 <= 4:
+		return "сообщения"
+	}
+	return "сообщений"
+}
@@ -1,14 +1,30 @@
 package usecase
 
+import (
+	"chat-service/internal/entity"
+	"chat-service/internal/ratelimit"
+	"io"
+	"time"
+)
+
 type CreateCategoryParams struct {
 	Title       string
 	Description string
+	ParentID    *int64 // nil — корневая категория
+}
+
+// TopicFilter — отбор топиков в ListTopics
+type TopicFilter struct {
+	Unsolved bool // только топики без принятого ответа
 }
 
 type SendMessageParams struct {
 	TopicID  int64
 	AuthorID int64 // берётся из контекста (middleware)
 	Content  string
+	QuoteID  *int64 // цитируемое сообщение того же топика
+	// AttachmentIDs — заранее загруженные автором вложения, в порядке показа
+	AttachmentIDs []int64
 }
 type TopicParams struct {
 	CategoryID  int64
@@ -16,3 +32,92 @@ type TopicParams struct {
 	Description string
 	AuthorID    int64 // берётся из контекста авторизации
 }
+
+// CleanupParams — параметры прогона очистки сообщений по политикам хранения
+type CleanupParams struct {
+	Threshold time.Time // глобальный порог для топиков и категорий без своей политики
+	DryRun    bool      // только посчитать, ничего не удалять
+	BatchSize int       // сколько сообщений удалять за один запрос
+}
+
+// ReportParams — жалоба на сообщение или топик
+type ReportParams struct {
+	TargetType string // entity.TargetMessage или entity.TargetTopic
+	TargetID   int64
+	Reason     entity.ReportReason
+	Comment    string
+}
+
+// Способы разбора жалобы
+const (
+	ResolveDismiss = "dismiss" // отклонить, снять скрытие
+	ResolveDelete  = "delete"  // удалить объект жалобы
+	ResolveBlock   = "block"   // заблокировать автора через auth-service
+)
+
+// ResolveReportParams — решение модератора по жалобе; применяется ко всем открытым жалобам на тот же объект
+type ResolveReportParams struct {
+	Action string
+	Note   string // пояснение модератора; для delete — причина удаления
+}
+
+// MuteParams — запрет писать в топике или категории; указывается ровно одно из TopicID и CategoryID
+type MuteParams struct {
+	UserID     int64
+	TopicID    int64
+	CategoryID int64
+	Duration   time.Duration // 0 — бессрочно
+	Reason     string
+}
+
+// ActionLimits — лимиты одного действия. Нулевой лимит не ограничивает.
+type ActionLimits struct {
+	User       ratelimit.Limit // обычный пользователь
+	Restricted ratelimit.Limit // новый аккаунт или почти без сообщений
+	IP         ratelimit.Limit // все запросы с одного адреса
+}
+
+// RateLimitPolicy — лимиты по действиям (ActionMessage, ActionTopic, ActionEdit) и кого считать новичком
+type RateLimitPolicy struct {
+	Actions       map[string]ActionLimits
+	NewAccountAge time.Duration
+	MinPosts      int64
+	MinReputation int64
+}
+
+// ReputationPolicy — сколько репутации нужно для привилегии (entity.Privilege*).
+// Привилегии без порога доступны всем.
+type ReputationPolicy struct {
+	Thresholds map[string]int64
+}
+
+// StartConversationParams — новая личная переписка
+type StartConversationParams struct {
+	UserIDs []int64 // собеседники без автора; больше одного — группа
+	Title   string  // только для группы
+	Content string  // первое сообщение; пусто — переписка без сообщений
+}
+
+// RoomParams — настройки комнаты при создании и изменении
+type RoomParams struct {
+	Slug        string
+	Title       string
+	Description string
+	Position    int // при создании не учитывается: новая комната встаёт последней
+	Retention   entity.RetentionPolicy
+	RateLimit   ratelimit.Limit // лимит сообщений одного пользователя в комнате; нулевой — только общий
+}
+
+// UploadParams — загружаемый файл; имя — как прислал клиент, тип определяется по содержимому
+type UploadParams struct {
+	FileName string
+	Body     io.Reader
+}
+
+// ProfileParams — публичные поля профиля; заменяют прежние целиком
+type ProfileParams struct {
+	Bio       string
+	Location  string
+	Website   string // http(s)-ссылка или пусто
+	Signature string
+}
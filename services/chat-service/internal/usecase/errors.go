@@ -3,6 +3,7 @@ package usecase
 import "errors"
 
 var (
-	ErrForbidden       = errors.New("forbidden: insufficient privileges")
-	ErrUnauthenticated = errors.New("unauthenticated: please log in first")
+	ErrForbidden        = errors.New("forbidden: insufficient privileges")
+	ErrUnauthenticated  = errors.New("unauthenticated: please log in first")
+	ErrInvalidRetention = errors.New("invalid retention policy: mode must be default, forever, days or last_n; days and last_n need a positive value")
 )
@@ -0,0 +1,335 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	repoErr "chat-service/internal/errors"
+	"chat-service/internal/filter"
+	"chat-service/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/logger"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrContentRejected       = errors.New("content rejected by filter")
+	ErrInvalidFilterRule     = errors.New("invalid filter rule")
+	ErrInvalidFilterSettings = errors.New("invalid filter settings")
+	ErrFilterRuleNotFound    = errors.New("filter rule not found")
+	ErrFilterRuleExists      = errors.New("filter rule already exists")
+)
+
+// recentLimit — сколько последних сообщений автора сравнивается при проверке повторов
+const recentLimit = 20
+
+// ContentRejectedError — текст отклонён фильтром; errors.Is(err, ErrContentRejected) == true.
+// Текст ошибки объясняет причину и показывается автору как есть.
+type ContentRejectedError struct {
+	Hit filter.Hit
+}
+
+func (e *ContentRejectedError) Error() string {
+	return "content rejected: " + e.Hit.Reason
+}
+
+func (e *ContentRejectedError) Unwrap() error { return ErrContentRejected }
+
+// ContentChecker — фильтр контента для message- и topic-usecase; реализуется FilterUC
+type ContentChecker interface {
+	// CheckContent проверяет текст автора; history — сравнивать с его недавними сообщениями.
+	// Отклонённый текст — *ContentRejectedError.
+	CheckContent(ctx context.Context, authorID int64, text string, history bool) (*filter.Verdict, error)
+	// FlagContent отправляет сохранённый объект в очередь жалоб
+	FlagContent(ctx context.Context, targetType string, targetID int64, flags []filter.Hit)
+}
+
+// screen прогоняет текст через фильтр контента и возвращает текст для сохранения и флаги.
+// Без фильтра (в тестах, где он не важен) текст не меняется.
+func screen(ctx context.Context, cc ContentChecker, log logger.Interface, authorID int64, text string, history bool) (string, []filter.Hit, error) {
+	if cc == nil {
+		return text, nil, nil
+	}
+	v, err := cc.CheckContent(ctx, authorID, text, history)
+	if errors.Is(err, ErrContentRejected) {
+		log.Info("content rejected by filter", "user_id", authorID, "err", err)
+		return "", nil, err
+	} else if err != nil {
+		log.Error("content filter failed", "err", err)
+		return "", nil, err
+	}
+	return v.Text, v.Flags, nil
+}
+
+// FilterUC хранит цепочку фильтров, собранную из правил в базе, и управляет правилами.
+// Изменения через этот usecase применяются сразу; правки с других экземпляров сервиса
+// подхватываются не позже чем через reloadEvery.
+type FilterUC struct {
+	repo        repo.FilterRepository
+	messages    repo.MessageRepository
+	reports     repo.ReportRepository
+	access      access // управление фильтрами — только глобальное право
+	log         logger.Interface
+	reloadEvery time.Duration
+
+	mu       sync.RWMutex
+	chain    *filter.Chain
+	settings entity.FilterSettings
+	loadedAt time.Time
+}
+
+func NewFilterUsecase(r repo.FilterRepository, mr repo.MessageRepository, rr repo.ReportRepository, l logger.Interface, reloadEvery time.Duration) *FilterUC {
+	return &FilterUC{repo: r, messages: mr, reports: rr, log: l, reloadEvery: reloadEvery}
+}
+
+// current возвращает цепочку, при необходимости перечитав правила. Если перечитать не удалось,
+// работает прежняя цепочка: без фильтра остаться хуже, чем с чуть устаревшим.
+func (uc *FilterUC) current(ctx context.Context) (*filter.Chain, entity.FilterSettings, error) {
+	uc.mu.RLock()
+	chain, s, fresh := uc.chain, uc.settings, time.Since(uc.loadedAt) < uc.reloadEvery
+	uc.mu.RUnlock()
+	if chain != nil && fresh {
+		return chain, s, nil
+	}
+
+	if err := uc.reload(ctx); err != nil {
+		if chain != nil {
+			uc.log.Warn("filter reload failed, using previous rules", "err", err)
+			return chain, s, nil
+		}
+		return nil, entity.FilterSettings{}, err
+	}
+
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	return uc.chain, uc.settings, nil
+}
+
+// reload перечитывает правила и настройки и пересобирает цепочку
+func (uc *FilterUC) reload(ctx context.Context) error {
+	rules, err := uc.repo.ListRules(ctx)
+	if err != nil {
+		return fmt.Errorf("FilterUC.reload#rules: %w", err)
+	}
+	s, err := uc.repo.GetSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("FilterUC.reload#settings: %w", err)
+	}
+
+	chain := filter.Build(rules, *s)
+
+	uc.mu.Lock()
+	uc.chain, uc.settings, uc.loadedAt = chain, *s, time.Now()
+	uc.mu.Unlock()
+
+	uc.log.Debug("content filters reloaded", "rules", len(rules))
+	return nil
+}
+
+// applied — правка правил сохранена; цепочка пересобирается сразу, а при ошибке — при следующей проверке
+func (uc *FilterUC) applied(ctx context.Context) {
+	if err := uc.reload(ctx); err != nil {
+		uc.log.Error("filter reload after change failed", "err", err)
+		uc.mu.Lock()
+		uc.loadedAt = time.Time{}
+		uc.mu.Unlock()
+	}
+}
+
+func (uc *FilterUC) CheckContent(ctx context.Context, authorID int64, text string, history bool) (*filter.Verdict, error) {
+	chain, s, err := uc.current(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("FilterUC.Check: %w", err)
+	}
+
+	in := filter.Input{Text: text}
+	if history && s.DuplicateWindow > 0 {
+		since := time.Now().UTC().Add(-s.DuplicateWindow)
+		recent, err := uc.messages.RecentByAuthor(ctx, authorID, since, recentLimit)
+		if err != nil {
+			// без истории проверяется всё остальное
+			uc.log.Error("messages.RecentByAuthor failed", "err", err)
+		}
+		in.Recent = recent
+	}
+
+	v := chain.Check(in)
+	if v.Rejected != nil {
+		uc.log.Info("content filter hit", "filter", v.Rejected.Filter, "action", v.Rejected.Action, "detail", v.Rejected.Detail)
+		return nil, &ContentRejectedError{Hit: *v.Rejected}
+	}
+	if v.Masked > 0 || len(v.Flags) > 0 {
+		uc.log.Info("content filtered", "user_id", authorID, "masked", v.Masked, "flags", len(v.Flags))
+	}
+	return &v, nil
+}
+
+func (uc *FilterUC) FlagContent(ctx context.Context, targetType string, targetID int64, flags []filter.Hit) {
+	if len(flags) == 0 || uc.reports == nil {
+		return
+	}
+
+	lines := make([]string, 0, len(flags))
+	for _, h := range flags {
+		line := h.Filter + ": " + h.Reason
+		if h.Detail != "" {
+			line += " (" + h.Detail + ")"
+		}
+		lines = append(lines, line)
+	}
+
+	rep := &entity.Report{
+		TargetType: targetType,
+		TargetID:   targetID,
+		Reason:     entity.ReasonAutoFilter,
+		Comment:    strings.Join(lines, "\n"),
+	}
+	if _, err := uc.reports.Create(ctx, rep, 0); err != nil {
+		uc.log.Error("filter flag not saved", "target_type", targetType, "target_id", targetID, "err", err)
+		return
+	}
+	uc.log.Info("content flagged for moderation", "target_type", targetType, "target_id", targetID, "report_id", rep.ID)
+}
+
+// TestContent показывает, что цепочка сделает с текстом; повторы не проверяются (только admin)
+func (uc *FilterUC) TestContent(ctx context.Context, text string) (*filter.Verdict, error) {
+	if _, err := uc.access.check(ctx, auth.PermFilterManage, nil); err != nil {
+		return nil, err
+	}
+	chain, _, err := uc.current(ctx)
+	if err != nil {
+		uc.log.Error("filter load failed", "err", err)
+		return nil, fmt.Errorf("FilterUC.Test: %w", err)
+	}
+	v := chain.Check(filter.Input{Text: text})
+	return &v, nil
+}
+
+func (uc *FilterUC) ListFilterRules(ctx context.Context) ([]*entity.FilterRule, error) {
+	if _, err := uc.access.check(ctx, auth.PermFilterManage, nil); err != nil {
+		uc.log.Warn("list filter rules denied", "err", err)
+		return nil, err
+	}
+	list, err := uc.repo.ListRules(ctx)
+	if err != nil {
+		uc.log.Error("repo.ListRules failed", "err", err)
+		return nil, fmt.Errorf("FilterUC.ListRules: %w", err)
+	}
+	return list, nil
+}
+
+func (uc *FilterUC) CreateFilterRule(ctx context.Context, r *entity.FilterRule) error {
+	userID, err := uc.access.check(ctx, auth.PermFilterManage, nil)
+	if err != nil {
+		uc.log.Warn("create filter rule denied", "err", err)
+		return err
+	}
+	if err := filter.NormalizeRule(r); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidFilterRule, err)
+	}
+	r.CreatedBy = &userID
+
+	err = uc.repo.CreateRule(ctx, r)
+	if errors.Is(err, repoErr.ErrConflict) {
+		return ErrFilterRuleExists
+	} else if err != nil {
+		uc.log.Error("repo.CreateRule failed", "err", err)
+		return fmt.Errorf("FilterUC.CreateRule: %w", err)
+	}
+
+	uc.applied(ctx)
+	uc.log.Info("filter rule created", "id", r.ID, "kind", r.Kind, "by", userID)
+	return nil
+}
+
+// UpdateFilterRule меняет шаблон и действие; вид правила не меняется
+func (uc *FilterUC) UpdateFilterRule(ctx context.Context, r *entity.FilterRule) error {
+	userID, err := uc.access.check(ctx, auth.PermFilterManage, nil)
+	if err != nil {
+		uc.log.Warn("update filter rule denied", "err", err)
+		return err
+	}
+
+	old, err := uc.repo.GetRule(ctx, r.ID)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		return ErrFilterRuleNotFound
+	} else if err != nil {
+		uc.log.Error("repo.GetRule failed", "err", err)
+		return fmt.Errorf("FilterUC.UpdateRule#get: %w", err)
+	}
+	r.Kind = old.Kind
+	if err := filter.NormalizeRule(r); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidFilterRule, err)
+	}
+
+	err = uc.repo.UpdateRule(ctx, r)
+	switch {
+	case errors.Is(err, repoErr.ErrNotFound):
+		return ErrFilterRuleNotFound
+	case errors.Is(err, repoErr.ErrConflict):
+		return ErrFilterRuleExists
+	case err != nil:
+		uc.log.Error("repo.UpdateRule failed", "err", err)
+		return fmt.Errorf("FilterUC.UpdateRule: %w", err)
+	}
+
+	uc.applied(ctx)
+	uc.log.Info("filter rule updated", "id", r.ID, "by", userID)
+	return nil
+}
+
+func (uc *FilterUC) DeleteFilterRule(ctx context.Context, id int64) error {
+	userID, err := uc.access.check(ctx, auth.PermFilterManage, nil)
+	if err != nil {
+		uc.log.Warn("delete filter rule denied", "err", err)
+		return err
+	}
+
+	err = uc.repo.DeleteRule(ctx, id)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		return ErrFilterRuleNotFound
+	} else if err != nil {
+		uc.log.Error("repo.DeleteRule failed", "err", err)
+		return fmt.Errorf("FilterUC.DeleteRule: %w", err)
+	}
+
+	uc.applied(ctx)
+	uc.log.Info("filter rule deleted", "id", id, "by", userID)
+	return nil
+}
+
+func (uc *FilterUC) GetFilterSettings(ctx context.Context) (*entity.FilterSettings, error) {
+	if _, err := uc.access.check(ctx, auth.PermFilterManage, nil); err != nil {
+		return nil, err
+	}
+	s, err := uc.repo.GetSettings(ctx)
+	if err != nil {
+		uc.log.Error("repo.GetSettings failed", "err", err)
+		return nil, fmt.Errorf("FilterUC.GetSettings: %w", err)
+	}
+	return s, nil
+}
+
+func (uc *FilterUC) UpdateFilterSettings(ctx context.Context, s *entity.FilterSettings) error {
+	userID, err := uc.access.check(ctx, auth.PermFilterManage, nil)
+	if err != nil {
+		uc.log.Warn("update filter settings denied", "err", err)
+		return err
+	}
+	if err := filter.ValidateSettings(*s); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidFilterSettings, err)
+	}
+	s.UpdatedBy = &userID
+
+	if err := uc.repo.SaveSettings(ctx, s); err != nil {
+		uc.log.Error("repo.SaveSettings failed", "err", err)
+		return fmt.Errorf("FilterUC.UpdateSettings: %w", err)
+	}
+
+	uc.applied(ctx)
+	uc.log.Info("filter settings updated", "by", userID)
+	return nil
+}
@@ -0,0 +1,293 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	customErr "chat-service/internal/errors"
+	"chat-service/internal/usecase/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// filterSettings — настройки по умолчанию из миграции
+func filterSettings() *entity.FilterSettings {
+	return &entity.FilterSettings{
+		MaxLength:          10000,
+		LengthAction:       entity.FilterReject,
+		MaxLinks:           5,
+		LinkFloodAction:    entity.FilterFlag,
+		UnlistedLinkAction: entity.FilterFlag,
+		DuplicateWindow:    time.Minute,
+		DuplicateAction:    entity.FilterReject,
+	}
+}
+
+// newTestFilterUC собирает FilterUC, который при каждой загрузке читает переданные правила
+func newTestFilterUC(ctrl *gomock.Controller, rules []*entity.FilterRule, s *entity.FilterSettings) (*FilterUC, *mocks.MockFilterRepository, *mocks.MockMessageRepository, *mocks.MockReportRepository) {
+	repo := mocks.NewMockFilterRepository(ctrl)
+	messages := mocks.NewMockMessageRepository(ctrl)
+	reports := mocks.NewMockReportRepository(ctrl)
+	repo.EXPECT().ListRules(gomock.Any()).Return(rules, nil).AnyTimes()
+	repo.EXPECT().GetSettings(gomock.Any()).Return(s, nil).AnyTimes()
+	return NewFilterUsecase(repo, messages, reports, mocks.FakeLogger{}, time.Hour), repo, messages, reports
+}
+
+func TestFilterUC_CheckContent_Words(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rules := []*entity.FilterRule{
+		{ID: 1, Kind: entity.FilterWord, Pattern: "дурак", Action: entity.FilterMask},
+		{ID: 2, Kind: entity.FilterWord, Pattern: "сука", Action: entity.FilterReject},
+		{ID: 3, Kind: entity.FilterWord, Pattern: "casino", Action: entity.FilterFlag},
+	}
+	uc, _, _, _ := newTestFilterUC(ctrl, rules, filterSettings())
+	ctx := context.Background()
+
+	t.Run("word forms are masked", func(t *testing.T) {
+		v, err := uc.CheckContent(ctx, 1, "Не спорь с дураками", false)
+		require.NoError(t, err)
+		require.Equal(t, "Не спорь с ********", v.Text)
+		require.Equal(t, 1, v.Masked)
+	})
+
+	t.Run("look-alike letters and leet", func(t *testing.T) {
+		// латинские a, y и цифра 4 вместо кириллицы
+		_, err := uc.CheckContent(ctx, 1, "ну ты и cyкa", false)
+		require.ErrorIs(t, err, ErrContentRejected)
+
+		_, err = uc.CheckContent(ctx, 1, "CY4ARA", false)
+		require.NoError(t, err)
+
+		_, err = uc.CheckContent(ctx, 1, "сууука", false)
+		require.ErrorIs(t, err, ErrContentRejected)
+	})
+
+	t.Run("short stem does not match other words", func(t *testing.T) {
+		v, err := uc.CheckContent(ctx, 1, "пальто из сукна", false)
+		require.NoError(t, err)
+		require.Equal(t, "пальто из сукна", v.Text)
+		require.Empty(t, v.Flags)
+	})
+
+	t.Run("rejected error explains the reason", func(t *testing.T) {
+		_, err := uc.CheckContent(ctx, 1, "сука", false)
+		var rejected *ContentRejectedError
+		require.True(t, errors.As(err, &rejected))
+		require.Equal(t, entity.FilterReject, rejected.Hit.Action)
+		require.Contains(t, err.Error(), "content rejected")
+	})
+
+	t.Run("flag keeps the text", func(t *testing.T) {
+		v, err := uc.CheckContent(ctx, 1, "лучшее Casino тут", false)
+		require.NoError(t, err)
+		require.Equal(t, "лучшее Casino тут", v.Text)
+		require.Len(t, v.Flags, 1)
+	})
+}
+
+func TestFilterUC_CheckContent_Links(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rules := []*entity.FilterRule{
+		{ID: 1, Kind: entity.FilterLinkDeny, Pattern: "spam.ru", Action: entity.FilterMask},
+		{ID: 2, Kind: entity.FilterLinkAllow, Pattern: "github.com"},
+	}
+	s := filterSettings()
+	s.MaxLinks = 2
+	uc, _, _, _ := newTestFilterUC(ctrl, rules, s)
+	ctx := context.Background()
+
+	t.Run("denied domain with subdomain is masked", func(t *testing.T) {
+		v, err := uc.CheckContent(ctx, 1, "смотри https://www.spam.ru/x", false)
+		require.NoError(t, err)
+		require.Equal(t, "смотри [link removed]", v.Text)
+	})
+
+	t.Run("allowed domain passes", func(t *testing.T) {
+		v, err := uc.CheckContent(ctx, 1, "код на https://github.com/a/b", false)
+		require.NoError(t, err)
+		require.Empty(t, v.Flags)
+	})
+
+	t.Run("unlisted domain is flagged", func(t *testing.T) {
+		v, err := uc.CheckContent(ctx, 1, "https://example.org", false)
+		require.NoError(t, err)
+		require.Len(t, v.Flags, 1)
+	})
+
+	t.Run("link flood", func(t *testing.T) {
+		v, err := uc.CheckContent(ctx, 1, "https://github.com/a https://github.com/b https://github.com/c", false)
+		require.NoError(t, err)
+		require.Len(t, v.Flags, 1)
+	})
+}
+
+func TestFilterUC_CheckContent_LengthAndDuplicates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := filterSettings()
+	s.MaxLength = 10
+	uc, _, messages, _ := newTestFilterUC(ctrl, nil, s)
+	ctx := context.Background()
+
+	t.Run("too long", func(t *testing.T) {
+		_, err := uc.CheckContent(ctx, 1, "очень длинный текст", false)
+		require.ErrorIs(t, err, ErrContentRejected)
+	})
+
+	t.Run("duplicate of recent message", func(t *testing.T) {
+		messages.EXPECT().RecentByAuthor(ctx, int64(1), gomock.Any(), recentLimit).Return([]string{"Привет!"}, nil)
+
+		_, err := uc.CheckContent(ctx, 1, "привет", true)
+		require.ErrorIs(t, err, ErrContentRejected)
+	})
+
+	t.Run("history is not checked on edit", func(t *testing.T) {
+		_, err := uc.CheckContent(ctx, 1, "привет", false)
+		require.NoError(t, err)
+	})
+
+	t.Run("history error does not block", func(t *testing.T) {
+		messages.EXPECT().RecentByAuthor(ctx, int64(1), gomock.Any(), recentLimit).Return(nil, errors.New("db down"))
+
+		_, err := uc.CheckContent(ctx, 1, "привет", true)
+		require.NoError(t, err)
+	})
+}
+
+func TestMessageUC_SendMessage_Filtered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rules := []*entity.FilterRule{
+		{ID: 1, Kind: entity.FilterWord, Pattern: "сука", Action: entity.FilterReject},
+		{ID: 2, Kind: entity.FilterWord, Pattern: "дурак", Action: entity.FilterMask},
+		{ID: 3, Kind: entity.FilterWord, Pattern: "casino", Action: entity.FilterFlag},
+	}
+	s := filterSettings()
+	s.DuplicateWindow = 0
+	filterUC, _, _, reports := newTestFilterUC(ctrl, rules, s)
+
+	repo := mocks.NewMockMessageRepository(ctrl)
+	topics := mocks.NewMockTopicRepository(ctrl)
+	publisher := mocks.NewMockMessagePublisher(ctrl)
+	uc := NewMessageUsecase(repo, topics, nil, nil, nil, MessageDeps{Content: filterUC, Publisher: publisher}, mocks.FakeLogger{}, retention)
+
+	ctx := auth.WithUser(context.Background(), 1, "user")
+
+	t.Run("rejected", func(t *testing.T) {
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(&entity.Topic{ID: 10, CategoryID: 4}, nil)
+
+		_, err := uc.SendMessage(ctx, SendMessageParams{TopicID: 10, AuthorID: 1, Content: "сука"})
+		require.ErrorIs(t, err, ErrContentRejected)
+	})
+
+	t.Run("masked", func(t *testing.T) {
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(&entity.Topic{ID: 10, CategoryID: 4}, nil)
+		repo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, m *entity.Message) error {
+			require.Equal(t, "сам *****", m.Content)
+			return nil
+		})
+		publisher.EXPECT().Publish(int64(10), gomock.Any())
+
+		m, err := uc.SendMessage(ctx, SendMessageParams{TopicID: 10, AuthorID: 1, Content: "сам дурак"})
+		require.NoError(t, err)
+		require.Equal(t, "сам *****", m.Content)
+	})
+
+	t.Run("flagged goes to report queue", func(t *testing.T) {
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(&entity.Topic{ID: 10, CategoryID: 4}, nil)
+		repo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, m *entity.Message) error {
+			m.ID = 55
+			return nil
+		})
+		publisher.EXPECT().Publish(int64(10), gomock.Any())
+		reports.EXPECT().Create(ctx, gomock.Any(), 0).DoAndReturn(func(_ context.Context, r *entity.Report, _ int) (bool, error) {
+			require.Equal(t, entity.TargetMessage, r.TargetType)
+			require.Equal(t, int64(55), r.TargetID)
+			require.Equal(t, entity.ReasonAutoFilter, r.Reason)
+			require.Zero(t, r.ReporterID)
+			return false, nil
+		})
+
+		_, err := uc.SendMessage(ctx, SendMessageParams{TopicID: 10, AuthorID: 1, Content: "играй в casino"})
+		require.NoError(t, err)
+	})
+}
+
+func TestFilterUC_ManageRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockFilterRepository(ctrl)
+	uc := NewFilterUsecase(repo, nil, nil, mocks.FakeLogger{}, time.Hour)
+
+	admin := auth.WithUser(context.Background(), 1, "admin")
+	user := auth.WithUser(context.Background(), 2, "user")
+
+	t.Run("user forbidden", func(t *testing.T) {
+		err := uc.CreateFilterRule(user, &entity.FilterRule{Kind: entity.FilterWord, Pattern: "x", Action: entity.FilterMask})
+		require.ErrorIs(t, err, ErrForbidden)
+
+		_, err = uc.TestContent(user, "text")
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("invalid rules", func(t *testing.T) {
+		err := uc.CreateFilterRule(admin, &entity.FilterRule{Kind: entity.FilterWord, Pattern: "x", Action: "ban"})
+		require.ErrorIs(t, err, ErrInvalidFilterRule)
+
+		err = uc.CreateFilterRule(admin, &entity.FilterRule{Kind: entity.FilterWord, Pattern: "два слова", Action: entity.FilterMask})
+		require.ErrorIs(t, err, ErrInvalidFilterRule)
+
+		err = uc.CreateFilterRule(admin, &entity.FilterRule{Kind: entity.FilterLinkDeny, Pattern: "not a domain", Action: entity.FilterReject})
+		require.ErrorIs(t, err, ErrInvalidFilterRule)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		repo.EXPECT().CreateRule(admin, gomock.Any()).Return(customErr.ErrConflict)
+
+		err := uc.CreateFilterRule(admin, &entity.FilterRule{Kind: entity.FilterWord, Pattern: "spam", Action: entity.FilterMask})
+		require.ErrorIs(t, err, ErrFilterRuleExists)
+	})
+
+	t.Run("new rule applies at once", func(t *testing.T) {
+		rule := &entity.FilterRule{Kind: entity.FilterWord, Pattern: "Spam", Action: entity.FilterReject}
+		repo.EXPECT().CreateRule(admin, rule).DoAndReturn(func(_ context.Context, r *entity.FilterRule) error {
+			require.Equal(t, "spam", r.Pattern)
+			require.Equal(t, int64(1), *r.CreatedBy)
+			r.ID = 7
+			return nil
+		})
+		repo.EXPECT().ListRules(admin).Return([]*entity.FilterRule{rule}, nil)
+		repo.EXPECT().GetSettings(admin).Return(filterSettings(), nil)
+
+		require.NoError(t, uc.CreateFilterRule(admin, rule))
+
+		v, err := uc.TestContent(admin, "SPAM!!!")
+		require.NoError(t, err)
+		require.NotNil(t, v.Rejected)
+	})
+
+	t.Run("update of missing rule", func(t *testing.T) {
+		repo.EXPECT().GetRule(admin, int64(99)).Return(nil, customErr.ErrNotFound)
+
+		err := uc.UpdateFilterRule(admin, &entity.FilterRule{ID: 99, Pattern: "x", Action: entity.FilterMask})
+		require.ErrorIs(t, err, ErrFilterRuleNotFound)
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		s := filterSettings()
+		s.DuplicateAction = entity.FilterMask
+
+		err := uc.UpdateFilterSettings(admin, s)
+		require.ErrorIs(t, err, ErrInvalidFilterSettings)
+	})
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	"chat-service/internal/markdown"
+	"chat-service/internal/repo"
+	"context"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/logger"
+	"strings"
+)
+
+const (
+	maxMentions         = 20 // остальные упоминания в одном сообщении не учитываются
+	defaultMentionLimit = 50 // границы страницы упоминаний
+	maxMentionLimit     = 200
+)
+
+// MentionProcessor разбирает упоминания в сохранённом сообщении и возвращает тех, кто упомянут впервые;
+// реализуется MentionUC. Сбой разбора не отменяет отправку или правку сообщения — он логируется.
+type MentionProcessor interface {
+	ProcessMentions(ctx context.Context, m *entity.Message, edited bool) []int64
+}
+
+type MentionUC struct {
+	repo   repo.MentionRepository
+	users  repo.AuthWebAPI
+	notify Notifier
+	log    logger.Interface
+}
+
+func NewMentionUsecase(r repo.MentionRepository, users repo.AuthWebAPI, n Notifier, l logger.Interface) *MentionUC {
+	return &MentionUC{repo: r, users: users, notify: n, log: l}
+}
+
+// ProcessMentions находит в сообщении @имена, сохраняет упоминания и уведомляет тех, кто упомянут впервые:
+// правка сообщения не присылает уведомление повторно. Возвращает уведомлённых.
+// Имена разрешаются через auth-service; упоминание самого себя не учитывается.
+func (uc *MentionUC) ProcessMentions(ctx context.Context, m *entity.Message, edited bool) []int64 {
+	names := markdown.Mentions(m.Content)
+	if len(names) > maxMentions {
+		names = names[:maxMentions]
+	}
+	// у нового сообщения без упоминаний в базе ничего нет; у исправленного старые упоминания надо убрать
+	if len(names) == 0 && !edited {
+		return nil
+	}
+
+	var ids []int64
+	if len(names) > 0 {
+		resolved, err := uc.users.ResolveUsernames(ctx, names)
+		if err != nil {
+			uc.log.Error("users.ResolveUsernames failed", "message_id", m.ID, "err", err)
+			return nil
+		}
+		for _, name := range names {
+			if id, ok := resolved[strings.ToLower(name)]; ok && id != m.AuthorID {
+				ids = append(ids, id)
+			}
+		}
+	}
+	if len(ids) == 0 && !edited {
+		return nil
+	}
+
+	added, err := uc.repo.Replace(ctx, m.ID, ids)
+	if err != nil {
+		uc.log.Error("repo.Replace failed", "message_id", m.ID, "err", err)
+		return nil
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	list := make([]*entity.Notification, 0, len(added))
+	for _, id := range added {
+		list = append(list, messageNotification(entity.NotifyMention, id, m, ""))
+	}
+	uc.notify.Notify(ctx, list...)
+	uc.log.Info("users mentioned", "message_id", m.ID, "count", len(added))
+	return added
+}
+
+// ListMyMentions возвращает страницу упоминаний текущего пользователя, новые сверху
+func (uc *MentionUC) ListMyMentions(ctx context.Context, limit, offset int) ([]*entity.Mention, int64, error) {
+	uc.log.Debug("ListMyMentions called", "limit", limit, "offset", offset)
+
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 {
+		uc.log.Warn("unauthenticated user tried to read mentions")
+		return nil, 0, ErrUnauthenticated
+	}
+
+	if limit <= 0 {
+		limit = defaultMentionLimit
+	} else if limit > maxMentionLimit {
+		limit = maxMentionLimit
+	}
+	list, total, err := uc.repo.ListForUser(ctx, userID, limit, max(offset, 0))
+	if err != nil {
+		uc.log.Error("repo.ListForUser failed", "err", err)
+		return nil, 0, fmt.Errorf("MentionUC.ListMine: %w", err)
+	}
+	for _, m := range list {
+		renderContent(m.Message)
+	}
+
+	uc.log.Info("mentions retrieved", "user_id", userID, "count", len(list))
+	return list, total, nil
+}
@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	"chat-service/internal/usecase/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestMentionUC_ProcessMentions_Parsing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cases := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"single", "привет, @bob!", []string{"bob"}},
+		{"order and duplicates", "@bob @alice и снова @bob", []string{"bob", "alice"}},
+		{"duplicates in another case", "@Bob и @bob", []string{"Bob"}},
+		{"trailing punctuation", "спасибо, @bob. И @alice-", []string{"bob", "alice"}},
+		{"dots and underscores inside", "@john.smith и @snake_case", []string{"john.smith", "snake_case"}},
+		{"cyrillic", "@Маша, глянь", []string{"Маша"}},
+		{"email is not a mention", "пишите на bob@example.com", nil},
+		{"inline code", "`@bob` не зовём, а @alice зовём", []string{"alice"}},
+		{"code block", "```\n@bob\n```\n@alice", []string{"alice"}},
+		{"link", "[@bob](https://example.com/@bob) https://example.com/@alice", nil},
+		{"emphasis", "**@bob** и _@alice_", []string{"bob", "alice"}},
+		{"bare at", "@ и @@ и @.", nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			users := mocks.NewMockAuthWebAPI(ctrl)
+			uc := NewMentionUsecase(mocks.NewMockMentionRepository(ctrl), users, mocks.NewMockNotifier(ctrl), mocks.FakeLogger{})
+			if tc.want != nil {
+				// разрешение имён упирается в auth-service; здесь проверяем только, что спрашиваем
+				users.EXPECT().ResolveUsernames(gomock.Any(), tc.want).Return(map[string]int64{}, nil)
+			}
+			uc.ProcessMentions(context.Background(), &entity.Message{ID: 1, AuthorID: 1, Content: tc.content}, false)
+		})
+	}
+}
+
+func TestMentionUC_ProcessMentions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := auth.WithUser(context.Background(), 1, "user")
+
+	t.Run("notifies mentioned users except the author", func(t *testing.T) {
+		repo := mocks.NewMockMentionRepository(ctrl)
+		users := mocks.NewMockAuthWebAPI(ctrl)
+		notifier := mocks.NewMockNotifier(ctrl)
+		uc := NewMentionUsecase(repo, users, notifier, mocks.FakeLogger{})
+		m := &entity.Message{ID: 5, TopicID: 10, AuthorID: 1, AuthorName: "me", Content: "@me @bob @ghost @alice"}
+
+		users.EXPECT().ResolveUsernames(ctx, []string{"me", "bob", "ghost", "alice"}).
+			Return(map[string]int64{"me": 1, "bob": 2, "alice": 3}, nil)
+		repo.EXPECT().Replace(ctx, int64(5), []int64{2, 3}).Return([]int64{2, 3}, nil)
+		notifier.EXPECT().Notify(ctx, gomock.Any(), gomock.Any()).Do(func(_ context.Context, list ...*entity.Notification) {
+			for i, n := range list {
+				require.Equal(t, []int64{2, 3}[i], n.UserID)
+				require.Equal(t, entity.NotifyMention, n.Type)
+				require.Equal(t, int64(5), *n.MessageID)
+				require.Equal(t, int64(1), *n.ActorID)
+				require.Equal(t, m.Content, n.Data["excerpt"])
+			}
+		})
+
+		require.Equal(t, []int64{2, 3}, uc.ProcessMentions(ctx, m, false))
+	})
+
+	t.Run("edit notifies only new mentions", func(t *testing.T) {
+		repo := mocks.NewMockMentionRepository(ctrl)
+		users := mocks.NewMockAuthWebAPI(ctrl)
+		notifier := mocks.NewMockNotifier(ctrl)
+		uc := NewMentionUsecase(repo, users, notifier, mocks.FakeLogger{})
+
+		users.EXPECT().ResolveUsernames(ctx, []string{"bob", "alice"}).
+			Return(map[string]int64{"bob": 2, "alice": 3}, nil)
+		repo.EXPECT().Replace(ctx, int64(5), []int64{2, 3}).Return([]int64{3}, nil)
+		notifier.EXPECT().Notify(ctx, gomock.Any())
+
+		require.Equal(t, []int64{3}, uc.ProcessMentions(ctx, &entity.Message{ID: 5, AuthorID: 1, Content: "@bob @alice"}, true))
+	})
+
+	t.Run("edit without mentions clears them", func(t *testing.T) {
+		repo := mocks.NewMockMentionRepository(ctrl)
+		uc := NewMentionUsecase(repo, mocks.NewMockAuthWebAPI(ctrl), mocks.NewMockNotifier(ctrl), mocks.FakeLogger{})
+
+		repo.EXPECT().Replace(ctx, int64(5), nil).Return(nil, nil)
+		uc.ProcessMentions(ctx, &entity.Message{ID: 5, AuthorID: 1, Content: "уже никого"}, true)
+	})
+
+	t.Run("usernames are case-insensitive", func(t *testing.T) {
+		repo := mocks.NewMockMentionRepository(ctrl)
+		users := mocks.NewMockAuthWebAPI(ctrl)
+		notifier := mocks.NewMockNotifier(ctrl)
+		uc := NewMentionUsecase(repo, users, notifier, mocks.FakeLogger{})
+
+		users.EXPECT().ResolveUsernames(ctx, []string{"BOB"}).Return(map[string]int64{"bob": 2}, nil)
+		repo.EXPECT().Replace(ctx, int64(5), []int64{2}).Return([]int64{2}, nil)
+		notifier.EXPECT().Notify(ctx, gomock.Any())
+		require.Equal(t, []int64{2}, uc.ProcessMentions(ctx, &entity.Message{ID: 5, AuthorID: 1, Content: "@BOB"}, false))
+	})
+
+	t.Run("only self mention stores nothing", func(t *testing.T) {
+		users := mocks.NewMockAuthWebAPI(ctrl)
+		uc := NewMentionUsecase(mocks.NewMockMentionRepository(ctrl), users, mocks.NewMockNotifier(ctrl), mocks.FakeLogger{})
+
+		users.EXPECT().ResolveUsernames(ctx, []string{"me"}).Return(map[string]int64{"me": 1}, nil)
+		uc.ProcessMentions(ctx, &entity.Message{ID: 5, AuthorID: 1, Content: "@me"}, false)
+	})
+
+	t.Run("auth-service error is not fatal", func(t *testing.T) {
+		users := mocks.NewMockAuthWebAPI(ctrl)
+		uc := NewMentionUsecase(mocks.NewMockMentionRepository(ctrl), users, mocks.NewMockNotifier(ctrl), mocks.FakeLogger{})
+
+		users.EXPECT().ResolveUsernames(ctx, gomock.Any()).Return(nil, errors.New("unavailable"))
+		uc.ProcessMentions(ctx, &entity.Message{ID: 5, AuthorID: 1, Content: "@bob"}, false)
+	})
+}
+
+func TestMessageUC_SendMessage_Mentions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockMessageRepository(ctrl)
+	topics := mocks.NewMockTopicRepository(ctrl)
+	publisher := mocks.NewMockMessagePublisher(ctrl)
+	mentions := mocks.NewMockMentionProcessor(ctrl)
+	uc := NewMessageUsecase(repo, topics, nil, nil, nil, MessageDeps{Mentions: mentions, Publisher: publisher}, mocks.FakeLogger{}, retention)
+	ctx := auth.WithUser(context.Background(), 1, "user")
+
+	t.Run("send", func(t *testing.T) {
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(&entity.Topic{ID: 10}, nil)
+		repo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, m *entity.Message) error {
+			m.ID = 5
+			return nil
+		})
+		publisher.EXPECT().Publish(int64(10), gomock.Any())
+		mentions.EXPECT().ProcessMentions(ctx, gomock.Any(), false).DoAndReturn(func(_ context.Context, m *entity.Message, _ bool) []int64 {
+			require.Equal(t, int64(5), m.ID)
+			require.Equal(t, "hi @bob", m.Content)
+			return []int64{2}
+		})
+
+		_, err := uc.SendMessage(ctx, SendMessageParams{TopicID: 10, AuthorID: 1, Content: "hi @bob"})
+		require.NoError(t, err)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(5)).Return(&entity.Message{ID: 5, TopicID: 10, AuthorID: 1, Content: "hi"}, nil)
+		repo.EXPECT().Update(ctx, int64(5), "hi @alice").Return(nil)
+		publisher.EXPECT().Publish(int64(10), gomock.Any())
+		mentions.EXPECT().ProcessMentions(ctx, gomock.Any(), true).DoAndReturn(func(_ context.Context, m *entity.Message, _ bool) []int64 {
+			require.Equal(t, "hi @alice", m.Content)
+			return nil
+		})
+
+		require.NoError(t, uc.UpdateMessage(ctx, 5, "hi @alice"))
+	})
+}
+
+func TestMentionUC_ListMyMentions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockMentionRepository(ctrl)
+	uc := NewMentionUsecase(repo, nil, nil, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 2, "user")
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, _, err := uc.ListMyMentions(context.Background(), 10, 0)
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	t.Run("page is clamped and content rendered", func(t *testing.T) {
+		list := []*entity.Mention{{
+			Message:    &entity.Message{ID: 5, TopicID: 10, AuthorID: 1, Content: "**@bob**"},
+			TopicTitle: "Новости",
+			CreatedAt:  time.Now(),
+		}}
+		repo.EXPECT().ListForUser(ctx, int64(2), maxMentionLimit, 0).Return(list, int64(1), nil)
+
+		got, total, err := uc.ListMyMentions(ctx, 1000, -5)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), total)
+		require.Equal(t, "<p><strong>@bob</strong></p>\n", got[0].Message.ContentHTML)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		repo.EXPECT().ListForUser(ctx, int64(2), defaultMentionLimit, 0).Return(nil, int64(0), errors.New("db down"))
+		_, _, err := uc.ListMyMentions(ctx, 0, 0)
+		require.Error(t, err)
+	})
+}
@@ -6,15 +6,26 @@ import (
 	"errors"
 	"fmt"
 	"github.com/ZoyaDenisova/go-common/logger"
+	"sort"
+	"strings"
 	"time"
 
 	"chat-service/internal/entity"
 	repoErr "chat-service/internal/errors"
+	"chat-service/internal/markdown"
 	"chat-service/internal/repo"
 )
 
 var (
 	ErrMessageNotFound = errors.New("message not found")
+	ErrInvalidQuote    = errors.New("quoted message not found in this topic")
+	ErrInvalidOrder    = errors.New("invalid order: must be oldest or score")
+)
+
+// Порядок сообщений в GetMessages
+const (
+	OrderOldest = "oldest" // по времени создания
+	OrderScore  = "score"  // по счёту голосов, лучшие первыми
 )
 
 type MessagePublisher interface {
@@ -23,60 +34,195 @@ type MessagePublisher interface {
 
 type MessageUC struct {
 	repo      repo.MessageRepository
+	topics    repo.TopicRepository
+	access    access
+	mutes     muteGuard
+	modlog    modLog
+	content   ContentChecker
+	privilege PrivilegeChecker
+	mentions  MentionProcessor
+	files     AttachmentBinder
+	notify    Notifier
 	publisher MessagePublisher
 	log       logger.Interface
+	retention time.Duration // окно, в течение которого удалённое сообщение можно восстановить
+}
+
+// MessageDeps — необязательные зависимости MessageUC; незаданная отключает свою часть работы
+// (фильтр, пороги репутации, упоминания, вложения, уведомления, рассылку по WebSocket)
+type MessageDeps struct {
+	Content    ContentChecker
+	Privileges PrivilegeChecker
+	Mentions   MentionProcessor
+	Files      AttachmentBinder
+	Notifier   Notifier
+	Publisher  MessagePublisher
+}
+
+func NewMessageUsecase(r repo.MessageRepository, tr repo.TopicRepository, mods repo.ModeratorRepository, mutes repo.MuteRepository, ml repo.ModerationRepository, d MessageDeps, l logger.Interface, retention time.Duration) *MessageUC {
+	return &MessageUC{
+		repo:      r,
+		topics:    tr,
+		access:    access{mods: mods},
+		mutes:     muteGuard{repo: mutes},
+		modlog:    modLog{repo: ml, notify: d.Notifier, log: l},
+		content:   d.Content,
+		privilege: d.Privileges,
+		mentions:  d.Mentions,
+		files:     d.Files,
+		notify:    d.Notifier,
+		publisher: d.Publisher,
+		log:       l,
+		retention: retention,
+	}
 }
 
-func NewMessageUsecase(r repo.MessageRepository, p MessagePublisher, l logger.Interface) *MessageUC {
-	return &MessageUC{repo: r, publisher: p, log: l}
+// publish рассылает событие топика по WebSocket; без MessagePublisher рассылки нет
+func (uc *MessageUC) publish(topicID int64, ev *entity.WSEvent) {
+	if uc.publisher != nil {
+		uc.publisher.Publish(topicID, ev)
+	}
 }
 
-// SendMessage сохраняет сообщение и рассылает его по WebSocket
+// SendMessage сохраняет сообщение и рассылает его по WebSocket.
+// Упомянутые пользователи, автор цитаты и автор топика получают уведомления.
 func (uc *MessageUC) SendMessage(ctx context.Context, p SendMessageParams) (*entity.Message, error) {
 	uc.log.Debug("SendMessage called", "topic_id", p.TopicID, "author_id", p.AuthorID)
 
-	userID, role := auth.FromContext(ctx)
-	if userID == 0 {
-		uc.log.Warn("unauthenticated user tried to send message")
-		return nil, ErrUnauthenticated
+	userID, err := uc.access.check(ctx, auth.PermMessageWrite, nil)
+	if err != nil {
+		uc.log.Warn("send message denied", "err", err)
+		return nil, err
+	}
+
+	t, err := uc.topics.GetByID(ctx, p.TopicID)
+	if err == nil && t.IsRedirect() {
+		// топик слит — пишем в тот, куда переехали сообщения
+		t, err = uc.topics.GetByID(ctx, *t.RedirectTo)
+	}
+	if errors.Is(err, repoErr.ErrNotFound) || (err == nil && t.IsRedirect()) {
+		uc.log.Info("topic not found for message", "topic_id", p.TopicID)
+		return nil, ErrTopicNotFound
+	} else if err != nil {
+		uc.log.Error("topics.GetByID failed", "err", err)
+		return nil, fmt.Errorf("MessageUC.Send#topic: %w", err)
+	}
+	// в закрытый топик могут писать только те, кто модерирует категорию
+	if t.Locked {
+		if _, err := uc.access.check(ctx, auth.PermMessageModerate, inCategory(t.CategoryID)); errors.Is(err, ErrForbidden) {
+			uc.log.Info("message rejected: topic is locked", "topic_id", p.TopicID, "user_id", userID)
+			return nil, ErrTopicLocked
+		} else if err != nil {
+			uc.log.Error("access check failed", "err", err)
+			return nil, fmt.Errorf("MessageUC.Send#access: %w", err)
+		}
 	}
-	if role != "user" && role != "admin" {
-		uc.log.Warn("unauthorized role tried to send message", "role", role)
-		return nil, ErrForbidden
+	if err := uc.mutes.check(ctx, userID, t.ID, t.CategoryID); err != nil {
+		if errors.Is(err, ErrMuted) {
+			uc.log.Info("message rejected: user is muted", "topic_id", t.ID, "user_id", userID)
+		} else {
+			uc.log.Error("mute check failed", "err", err)
+		}
+		return nil, err
+	}
+	var quoted *entity.Message
+	if p.QuoteID != nil {
+		q, err := uc.repo.GetByID(ctx, *p.QuoteID)
+		if errors.Is(err, repoErr.ErrNotFound) || (err == nil && (q.IsDeleted() || q.Hidden || q.TopicID != t.ID)) {
+			uc.log.Info("quoted message not found", "quote_id", *p.QuoteID, "topic_id", t.ID)
+			return nil, ErrInvalidQuote
+		} else if err != nil {
+			uc.log.Error("repo.GetByID failed", "err", err)
+			return nil, fmt.Errorf("MessageUC.Send#quote: %w", err)
+		}
+		quoted = q
+	}
+	var files []*entity.Attachment
+	if len(p.AttachmentIDs) > 0 {
+		if uc.files == nil {
+			return nil, ErrInvalidAttachment
+		}
+		if files, err = uc.files.Pending(ctx, userID, p.AttachmentIDs); err != nil {
+			return nil, err
+		}
+	}
+	// сообщение из одних вложений допустимо
+	if strings.TrimSpace(p.Content) == "" && len(files) == 0 {
+		return nil, ErrEmptyMessage
+	}
+	if err := requireLinks(ctx, uc.privilege, p.Content); err != nil {
+		return nil, err
+	}
+	content, flags, err := screen(ctx, uc.content, uc.log, userID, p.Content, true)
+	if err != nil {
+		return nil, err
 	}
 
 	m := &entity.Message{
-		TopicID:   p.TopicID,
-		AuthorID:  p.AuthorID,
-		Content:   p.Content,
-		CreatedAt: time.Now().UTC(),
+		TopicID:     t.ID,
+		AuthorID:    p.AuthorID,
+		Content:     content,
+		CreatedAt:   time.Now().UTC(),
+		QuoteID:     p.QuoteID,
+		Attachments: files,
 	}
 
-	// repo.Create проставит m.ID (RETURNING id)
-	if err := uc.repo.Create(ctx, m); err != nil {
+	// repo.Create проставит m.ID (RETURNING id) и привяжет вложения
+	if err := uc.repo.Create(ctx, m); errors.Is(err, repoErr.ErrConflict) {
+		// вложение успели отправить с другим сообщением
+		return nil, ErrInvalidAttachment
+	} else if err != nil {
 		uc.log.Error("repo.Create failed", "err", err)
 		return nil, fmt.Errorf("MessageUC.Send: %w", err)
 	}
 
-	uc.publisher.Publish(p.TopicID, &entity.WSEvent{
+	renderContent(m)
+	uc.publish(m.TopicID, &entity.WSEvent{
 		Action:  entity.ActionCreated,
 		Message: m,
 	})
+	if len(flags) > 0 {
+		uc.content.FlagContent(ctx, entity.TargetMessage, m.ID, flags)
+	}
+	notified := map[int64]bool{m.AuthorID: true}
+	if uc.mentions != nil {
+		for _, id := range uc.mentions.ProcessMentions(ctx, m, false) {
+			notified[id] = true
+		}
+	}
+	uc.notifyReplies(ctx, t, m, quoted, notified)
 	uc.log.Info("message sent", "id", m.ID, "topic_id", m.TopicID)
 	return m, nil
 }
 
+// notifyReplies уведомляет автора процитированного сообщения и автора топика.
+// Кто уже получил уведомление об упоминании в этом сообщении, второго не получает.
+func (uc *MessageUC) notifyReplies(ctx context.Context, t *entity.Topic, m *entity.Message, quoted *entity.Message, notified map[int64]bool) {
+	if uc.notify == nil {
+		return
+	}
+	var list []*entity.Notification
+	if quoted != nil && !notified[quoted.AuthorID] {
+		notified[quoted.AuthorID] = true
+		n := messageNotification(entity.NotifyQuote, quoted.AuthorID, m, t.Title)
+		n.Data["quote_id"] = quoted.ID
+		list = append(list, n)
+	}
+	if !notified[t.AuthorID] {
+		list = append(list, messageNotification(entity.NotifyReply, t.AuthorID, m, t.Title))
+	}
+	if len(list) > 0 {
+		uc.notify.Notify(ctx, list...)
+	}
+}
+
 func (uc *MessageUC) UpdateMessage(ctx context.Context, id int64, newContent string) error {
 	uc.log.Debug("UpdateMessage called", "id", id)
 
-	userID, role := auth.FromContext(ctx)
-	if userID == 0 {
-		uc.log.Warn("unauthenticated user tried to update message", "id", id)
-		return ErrUnauthenticated
-	}
-	if role != "user" && role != "admin" {
-		uc.log.Warn("unauthorized role tried to update message", "role", role)
-		return ErrForbidden
+	userID, err := uc.access.check(ctx, auth.PermMessageWrite, nil)
+	if err != nil {
+		uc.log.Warn("update message denied", "id", id, "err", err)
+		return err
 	}
 
 	m, err := uc.repo.GetByID(ctx, id)
@@ -87,16 +233,31 @@ func (uc *MessageUC) UpdateMessage(ctx context.Context, id int64, newContent str
 		uc.log.Error("repo.GetByID failed", "err", err)
 		return fmt.Errorf("MessageUC.Update#get: %w", err)
 	}
+	if m.IsDeleted() {
+		uc.log.Info("tried to update deleted message", "id", id)
+		return ErrMessageNotFound
+	}
 
 	if m.AuthorID != userID {
 		uc.log.Warn("user tried to update message not belonging to them", "message_id", id, "user_id", userID)
 		return ErrForbidden
 	}
 
+	if err := requireLinks(ctx, uc.privilege, newContent); err != nil {
+		return err
+	}
+	newContent, flags, err := screen(ctx, uc.content, uc.log, userID, newContent, false)
+	if err != nil {
+		return err
+	}
+
 	if err := uc.repo.Update(ctx, id, newContent); err != nil {
 		uc.log.Error("repo.Update failed", "err", err)
 		return fmt.Errorf("MessageUC.Update: %w", err)
 	}
+	if len(flags) > 0 {
+		uc.content.FlagContent(ctx, entity.TargetMessage, id, flags)
+	}
 
 	updated := &entity.Message{
 		ID:         id,
@@ -105,28 +266,31 @@ func (uc *MessageUC) UpdateMessage(ctx context.Context, id int64, newContent str
 		AuthorName: m.AuthorName,
 		Content:    newContent,
 		CreatedAt:  m.CreatedAt,
+		QuoteID:    m.QuoteID,
 	}
+	renderContent(updated)
 
-	uc.publisher.Publish(m.TopicID, &entity.WSEvent{
+	uc.publish(m.TopicID, &entity.WSEvent{
 		Action:  entity.ActionUpdated,
 		Message: updated,
 	})
+	if uc.mentions != nil {
+		uc.mentions.ProcessMentions(ctx, updated, true)
+	}
 
 	uc.log.Info("message updated", "id", id)
 	return nil
 }
 
-func (uc *MessageUC) DeleteMessage(ctx context.Context, id int64) error {
+// DeleteMessage мягко удаляет сообщение: в топике остаётся tombstone,
+// а автор удаления, время и причина сохраняются для модерации.
+func (uc *MessageUC) DeleteMessage(ctx context.Context, id int64, reason string) error {
 	uc.log.Debug("DeleteMessage called", "id", id)
 
-	userID, role := auth.FromContext(ctx)
-	if userID == 0 {
-		uc.log.Warn("unauthenticated user tried to delete message", "id", id)
-		return ErrUnauthenticated
-	}
-	if role != "user" && role != "admin" {
-		uc.log.Warn("unauthorized role tried to delete message", "role", role)
-		return ErrForbidden
+	userID, err := uc.access.check(ctx, auth.PermMessageWrite, nil)
+	if err != nil {
+		uc.log.Warn("delete message denied", "id", id, "err", err)
+		return err
 	}
 
 	m, err := uc.repo.GetByID(ctx, id)
@@ -137,29 +301,124 @@ func (uc *MessageUC) DeleteMessage(ctx context.Context, id int64) error {
 		uc.log.Error("repo.GetByID failed", "err", err)
 		return fmt.Errorf("MessageUC.Delete#get: %w", err)
 	}
+	if m.IsDeleted() {
+		uc.log.Info("message already deleted", "id", id)
+		return ErrMessageNotFound
+	}
 
-	if m.AuthorID != userID && role != "admin" {
-		uc.log.Warn("user tried to delete message not belonging to them", "message_id", id, "user_id", userID)
-		return ErrForbidden
+	// чужое сообщение удаляет только модератор категории топика
+	if m.AuthorID != userID {
+		if _, err := uc.access.check(ctx, auth.PermMessageModerate, topicCategory(uc.topics, m.TopicID)); err != nil {
+			uc.log.Warn("user tried to delete message not belonging to them", "message_id", id, "user_id", userID, "err", err)
+			return err
+		}
 	}
 
-	if err := uc.repo.Delete(ctx, id); err != nil {
+	err = uc.repo.Delete(ctx, id, userID, reason)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		uc.log.Info("message deleted concurrently", "id", id)
+		return ErrMessageNotFound
+	} else if err != nil {
 		uc.log.Error("repo.Delete failed", "err", err)
 		return fmt.Errorf("MessageUC.Delete: %w", err)
 	}
 
-	uc.publisher.Publish(m.TopicID, &entity.WSEvent{
+	// удаление своего сообщения — не модерация
+	if m.AuthorID != userID {
+		uc.modlog.record(ctx, &entity.ModerationEntry{
+			Action:         entity.ModDeleteMessage,
+			TargetType:     entity.TargetMessage,
+			TargetID:       id,
+			TargetAuthorID: &m.AuthorID,
+			Reason:         reason,
+			Snapshot:       map[string]any{"topic_id": m.TopicID, "content": m.Content},
+		})
+	}
+
+	uc.publish(m.TopicID, &entity.WSEvent{
 		Action:    entity.ActionDeleted,
 		MessageID: id,
 	})
 
-	uc.log.Info("message deleted", "id", id)
+	uc.log.Info("message deleted", "id", id, "deleted_by", userID)
+	return nil
+}
+
+// RestoreMessage восстанавливает мягко удалённое сообщение (только admin, в пределах окна хранения)
+func (uc *MessageUC) RestoreMessage(ctx context.Context, id int64) error {
+	uc.log.Debug("RestoreMessage called", "id", id)
+
+	userID, err := uc.access.check(ctx, auth.PermContentRestore, nil)
+	if err != nil {
+		uc.log.Warn("restore message denied", "err", err)
+		return err
+	}
+
+	since := time.Now().UTC().Add(-uc.retention)
+	err = uc.repo.Restore(ctx, id, since)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		uc.log.Info("no restorable message found", "id", id)
+		return ErrMessageNotFound
+	} else if err != nil {
+		uc.log.Error("repo.Restore failed", "err", err)
+		return fmt.Errorf("MessageUC.Restore: %w", err)
+	}
+
+	m, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		uc.log.Error("repo.GetByID failed", "err", err)
+		return fmt.Errorf("MessageUC.Restore#get: %w", err)
+	}
+
+	renderContent(m)
+	uc.publish(m.TopicID, &entity.WSEvent{
+		Action:  entity.ActionRestored,
+		Message: m,
+	})
+
+	uc.modlog.record(ctx, &entity.ModerationEntry{
+		Action:         entity.ModRestoreMessage,
+		TargetType:     entity.TargetMessage,
+		TargetID:       id,
+		TargetAuthorID: &m.AuthorID,
+		Snapshot:       map[string]any{"topic_id": m.TopicID},
+	})
+
+	uc.log.Info("message restored", "id", id, "restored_by", userID)
 	return nil
 }
 
-// GetMessages возвращает историю сообщений в топике
-func (uc *MessageUC) GetMessages(ctx context.Context, topicID int64) ([]*entity.Message, error) {
-	uc.log.Debug("GetMessages called", "topic_id", topicID)
+// ListDeletedMessages возвращает сообщения, которые ещё можно восстановить (только admin)
+func (uc *MessageUC) ListDeletedMessages(ctx context.Context) ([]*entity.Message, error) {
+	uc.log.Debug("ListDeletedMessages called")
+
+	_, err := uc.access.check(ctx, auth.PermContentRestore, nil)
+	if err != nil {
+		uc.log.Warn("list deleted messages denied", "err", err)
+		return nil, err
+	}
+
+	since := time.Now().UTC().Add(-uc.retention)
+	list, err := uc.repo.GetDeleted(ctx, since)
+	if err != nil {
+		uc.log.Error("repo.GetDeleted failed", "err", err)
+		return nil, fmt.Errorf("MessageUC.ListDeleted: %w", err)
+	}
+	renderContent(list...)
+
+	uc.log.Info("deleted messages retrieved", "count", len(list))
+	return list, nil
+}
+
+// GetMessages возвращает историю сообщений в топике.
+// Удалённые сообщения остаются на своих местах как tombstone; содержимое и причину
+// удаления, как и текст скрытых по жалобам, видят admin и модераторы категории.
+func (uc *MessageUC) GetMessages(ctx context.Context, topicID int64, order string) ([]*entity.Message, error) {
+	uc.log.Debug("GetMessages called", "topic_id", topicID, "order", order)
+
+	if order != OrderOldest && order != OrderScore {
+		return nil, ErrInvalidOrder
+	}
 
 	list, err := uc.repo.GetByTopic(ctx, topicID)
 	if err != nil {
@@ -167,19 +426,124 @@ func (uc *MessageUC) GetMessages(ctx context.Context, topicID int64) ([]*entity.
 		return nil, fmt.Errorf("MessageUC.List: %w", err)
 	}
 
+	if uc.files != nil {
+		if err := uc.files.Load(ctx, list...); err != nil {
+			return nil, fmt.Errorf("MessageUC.List#attachments: %w", err)
+		}
+	}
+	// содержимое удалённых и скрытых по жалобам видят те, кто модерирует категорию топика;
+	// их вложения не отдаются никому
+	for _, m := range list {
+		if m.IsDeleted() || m.Hidden {
+			m.Attachments = nil
+		}
+	}
+	if _, err := uc.access.check(ctx, auth.PermMessageModerate, topicCategory(uc.topics, topicID)); err != nil {
+		for _, m := range list {
+			if m.IsDeleted() {
+				m.Content = ""
+				m.DeletedBy = nil
+				m.DeleteReason = ""
+			} else if m.Hidden {
+				m.Content = ""
+			}
+		}
+	}
+	renderContent(list...)
+	if order == OrderScore {
+		// репозиторий отдаёт по времени — при равном счёте этот порядок и остаётся
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Score > list[j].Score })
+	}
+	acceptedFirst(list)
+
 	uc.log.Info("messages retrieved", "topic_id", topicID, "count", len(list))
 	return list, nil
 }
 
-// CleanupOldMessages удаляет устаревшие сообщения (для cron)
-func (uc *MessageUC) CleanupOldMessages(ctx context.Context, threshold time.Time) error {
-	uc.log.Debug("CleanupOldMessages called", "threshold", threshold)
+// defaultCleanupBatch — размер пачки удаления, если в CleanupParams не задан свой
+const defaultCleanupBatch = 1000
+
+// CleanupOldMessages удаляет сообщения, вышедшие за политики хранения категорий и топиков (для cron).
+// Удаление идёт пачками по BatchSize, чтобы не держать долгую блокировку на messages.
+// При DryRun ничего не удаляется, в отчёте — сколько сообщений и в каких топиках попало бы под очистку.
+func (uc *MessageUC) CleanupOldMessages(ctx context.Context, p CleanupParams) (*entity.CleanupReport, error) {
+	uc.log.Debug("CleanupOldMessages called", "threshold", p.Threshold, "dry_run", p.DryRun)
+
+	now := time.Now().UTC()
+	report := &entity.CleanupReport{DryRun: p.DryRun}
+
+	if p.DryRun {
+		stats, err := uc.repo.CountExpired(ctx, p.Threshold, now)
+		if err != nil {
+			uc.log.Error("repo.CountExpired failed", "err", err)
+			return nil, fmt.Errorf("MessageUC.Cleanup#count: %w", err)
+		}
+		for _, st := range stats {
+			report.Total += st.Count
+			uc.log.Info("dry-run: messages would be purged",
+				"topic_id", st.TopicID, "category_id", st.CategoryID, "policy", st.Mode, "count", st.Count)
+		}
+		report.Topics = stats
+		uc.log.Info("dry-run: cleanup finished", "threshold", p.Threshold, "total", report.Total)
+		return report, nil
+	}
 
-	if err := uc.repo.DeleteOlderThan(ctx, threshold); err != nil {
-		uc.log.Error("repo.DeleteOlderThan failed", "err", err)
-		return fmt.Errorf("MessageUC.Cleanup: %w", err)
+	batch := p.BatchSize
+	if batch <= 0 {
+		batch = defaultCleanupBatch
 	}
 
-	uc.log.Info("old messages deleted", "threshold", threshold)
+	for {
+		n, err := uc.repo.DeleteExpired(ctx, p.Threshold, now, batch)
+		if err != nil {
+			uc.log.Error("repo.DeleteExpired failed", "err", err, "deleted_so_far", report.Total)
+			return report, fmt.Errorf("MessageUC.Cleanup: %w", err)
+		}
+		report.Total += n
+		report.Batches++
+		if n < int64(batch) {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			uc.log.Warn("cleanup interrupted", "err", err, "deleted_so_far", report.Total)
+			return report, fmt.Errorf("MessageUC.Cleanup: %w", err)
+		}
+	}
+
+	uc.log.Info("old messages deleted", "threshold", p.Threshold, "count", report.Total, "batches", report.Batches)
+	return report, nil
+}
+
+// PreviewCleanup — dry-run очистки по запросу администратора
+func (uc *MessageUC) PreviewCleanup(ctx context.Context, threshold time.Time) (*entity.CleanupReport, error) {
+	uc.log.Debug("PreviewCleanup called", "threshold", threshold)
+
+	_, err := uc.access.check(ctx, auth.PermRetentionManage, nil)
+	if err != nil {
+		uc.log.Warn("preview cleanup denied", "err", err)
+		return nil, err
+	}
+
+	return uc.CleanupOldMessages(ctx, CleanupParams{Threshold: threshold, DryRun: true})
+}
+
+// PurgeDeletedMessages окончательно удаляет tombstone-сообщения, удалённые раньше threshold (для cron)
+func (uc *MessageUC) PurgeDeletedMessages(ctx context.Context, threshold time.Time) error {
+	uc.log.Debug("PurgeDeletedMessages called", "threshold", threshold)
+
+	n, err := uc.repo.PurgeDeleted(ctx, threshold)
+	if err != nil {
+		uc.log.Error("repo.PurgeDeleted failed", "err", err)
+		return fmt.Errorf("MessageUC.PurgeDeleted: %w", err)
+	}
+
+	uc.log.Info("deleted messages purged", "threshold", threshold, "count", n)
 	return nil
 }
+
+// renderContent заполняет ContentHTML безопасным HTML из Markdown-текста; пустой текст — пустой HTML
+func renderContent(list ...*entity.Message) {
+	for _, m := range list {
+		m.ContentHTML = markdown.Render(m.Content)
+	}
+}
@@ -13,14 +13,17 @@ import (
 	"time"
 )
 
+const retention = 30 * 24 * time.Hour
+
 func TestMessageUC_SendMessage(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	repo := mocks.NewMockMessageRepository(ctrl)
+	topics := mocks.NewMockTopicRepository(ctrl)
 	publisher := mocks.NewMockMessagePublisher(ctrl)
 	log := mocks.FakeLogger{}
-	uc := NewMessageUsecase(repo, publisher, log)
+	uc := NewMessageUsecase(repo, topics, nil, nil, nil, MessageDeps{Publisher: publisher}, log, retention)
 
 	ctx := auth.WithUser(context.Background(), 1, "user")
 	params := SendMessageParams{
@@ -30,26 +33,78 @@ func TestMessageUC_SendMessage(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
+		topics.EXPECT().GetByID(ctx, params.TopicID).Return(&entity.Topic{ID: 10}, nil)
 		repo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
 		publisher.EXPECT().Publish(params.TopicID, gomock.Any())
-		err := uc.SendMessage(ctx, params)
+		m, err := uc.SendMessage(ctx, params)
+		require.NoError(t, err)
+		require.Equal(t, params.Content, m.Content)
+	})
+
+	t.Run("without a publisher nothing is broadcast", func(t *testing.T) {
+		bare := NewMessageUsecase(repo, topics, nil, nil, nil, MessageDeps{}, log, retention)
+		topics.EXPECT().GetByID(ctx, params.TopicID).Return(&entity.Topic{ID: 10}, nil)
+		repo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		_, err := bare.SendMessage(ctx, params)
 		require.NoError(t, err)
 	})
 
 	t.Run("unauthenticated", func(t *testing.T) {
-		err := uc.SendMessage(context.Background(), params)
+		_, err := uc.SendMessage(context.Background(), params)
 		require.ErrorIs(t, err, ErrUnauthenticated)
 	})
 
 	t.Run("forbidden", func(t *testing.T) {
 		ctx := auth.WithUser(context.Background(), 1, "guest")
-		err := uc.SendMessage(ctx, params)
+		_, err := uc.SendMessage(ctx, params)
 		require.ErrorIs(t, err, ErrForbidden)
 	})
 
+	t.Run("topic not found", func(t *testing.T) {
+		topics.EXPECT().GetByID(ctx, params.TopicID).Return(nil, customErr.ErrNotFound)
+		_, err := uc.SendMessage(ctx, params)
+		require.ErrorIs(t, err, ErrTopicNotFound)
+	})
+
+	t.Run("topic lookup error", func(t *testing.T) {
+		topics.EXPECT().GetByID(ctx, params.TopicID).Return(nil, errors.New("db error"))
+		_, err := uc.SendMessage(ctx, params)
+		require.ErrorContains(t, err, "MessageUC.Send#topic")
+	})
+
+	t.Run("merged topic posts into target", func(t *testing.T) {
+		targetID := int64(20)
+		topics.EXPECT().GetByID(ctx, params.TopicID).Return(&entity.Topic{ID: 10, RedirectTo: &targetID}, nil)
+		topics.EXPECT().GetByID(ctx, targetID).Return(&entity.Topic{ID: 20}, nil)
+		repo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		publisher.EXPECT().Publish(targetID, gomock.Any())
+		m, err := uc.SendMessage(ctx, params)
+		require.NoError(t, err)
+		require.Equal(t, targetID, m.TopicID)
+	})
+
+	t.Run("locked topic", func(t *testing.T) {
+		topics.EXPECT().GetByID(ctx, params.TopicID).Return(&entity.Topic{ID: 10, Locked: true}, nil)
+		repo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+		m, err := uc.SendMessage(ctx, params)
+		require.Nil(t, m)
+		require.ErrorIs(t, err, ErrTopicLocked)
+	})
+
+	t.Run("admin posts in locked topic", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "admin")
+		topics.EXPECT().GetByID(ctx, params.TopicID).Return(&entity.Topic{ID: 10, Locked: true}, nil)
+		repo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		publisher.EXPECT().Publish(params.TopicID, gomock.Any())
+		_, err := uc.SendMessage(ctx, params)
+		require.NoError(t, err)
+	})
+
 	t.Run("repo error", func(t *testing.T) {
+		topics.EXPECT().GetByID(ctx, params.TopicID).Return(&entity.Topic{ID: 10}, nil)
 		repo.EXPECT().Create(ctx, gomock.Any()).Return(errors.New("db error"))
-		err := uc.SendMessage(ctx, params)
+		_, err := uc.SendMessage(ctx, params)
 		require.ErrorContains(t, err, "MessageUC.Send")
 	})
 }
@@ -59,19 +114,29 @@ func TestMessageUC_UpdateMessage(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mocks.NewMockMessageRepository(ctrl)
+	publisher := mocks.NewMockMessagePublisher(ctrl)
 	log := mocks.FakeLogger{}
-	uc := NewMessageUsecase(repo, nil, log)
+	uc := NewMessageUsecase(repo, nil, nil, nil, nil, MessageDeps{Publisher: publisher}, log, retention)
 
 	ctx := auth.WithUser(context.Background(), 1, "user")
 
 	t.Run("success", func(t *testing.T) {
-		msg := &entity.Message{ID: 1, AuthorID: 1}
+		msg := &entity.Message{ID: 1, TopicID: 10, AuthorID: 1}
 		repo.EXPECT().GetByID(ctx, int64(1)).Return(msg, nil)
 		repo.EXPECT().Update(ctx, int64(1), "updated").Return(nil)
+		publisher.EXPECT().Publish(int64(10), gomock.Any())
 		err := uc.UpdateMessage(ctx, 1, "updated")
 		require.NoError(t, err)
 	})
 
+	t.Run("deleted message", func(t *testing.T) {
+		deletedAt := time.Now()
+		msg := &entity.Message{ID: 6, AuthorID: 1, DeletedAt: &deletedAt}
+		repo.EXPECT().GetByID(ctx, int64(6)).Return(msg, nil)
+		err := uc.UpdateMessage(ctx, 6, "x")
+		require.ErrorIs(t, err, ErrMessageNotFound)
+	})
+
 	t.Run("unauthenticated", func(t *testing.T) {
 		err := uc.UpdateMessage(context.Background(), 1, "x")
 		require.ErrorIs(t, err, ErrUnauthenticated)
@@ -120,45 +185,63 @@ func TestMessageUC_DeleteMessage(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mocks.NewMockMessageRepository(ctrl)
+	publisher := mocks.NewMockMessagePublisher(ctrl)
 	log := mocks.FakeLogger{}
-	uc := NewMessageUsecase(repo, nil, log)
+	uc := NewMessageUsecase(repo, nil, nil, nil, nil, MessageDeps{Publisher: publisher}, log, retention)
 
 	ctx := auth.WithUser(context.Background(), 1, "user")
 
 	t.Run("success", func(t *testing.T) {
-		repo.EXPECT().GetByID(ctx, int64(1)).Return(&entity.Message{ID: 1, AuthorID: 1}, nil)
-		repo.EXPECT().Delete(ctx, int64(1)).Return(nil)
-		err := uc.DeleteMessage(ctx, 1)
+		repo.EXPECT().GetByID(ctx, int64(1)).Return(&entity.Message{ID: 1, TopicID: 10, AuthorID: 1}, nil)
+		repo.EXPECT().Delete(ctx, int64(1), int64(1), "").Return(nil)
+		publisher.EXPECT().Publish(int64(10), &entity.WSEvent{Action: entity.ActionDeleted, MessageID: 1})
+		err := uc.DeleteMessage(ctx, 1, "")
+		require.NoError(t, err)
+	})
+
+	t.Run("admin deletes foreign message with reason", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 7, "admin")
+		repo.EXPECT().GetByID(ctx, int64(8)).Return(&entity.Message{ID: 8, TopicID: 10, AuthorID: 42}, nil)
+		repo.EXPECT().Delete(ctx, int64(8), int64(7), "spam").Return(nil)
+		publisher.EXPECT().Publish(int64(10), gomock.Any())
+		err := uc.DeleteMessage(ctx, 8, "spam")
 		require.NoError(t, err)
 	})
 
 	t.Run("unauthenticated", func(t *testing.T) {
-		err := uc.DeleteMessage(context.Background(), 1)
+		err := uc.DeleteMessage(context.Background(), 1, "")
 		require.ErrorIs(t, err, ErrUnauthenticated)
 	})
 
 	t.Run("forbidden role", func(t *testing.T) {
 		ctx := auth.WithUser(context.Background(), 1, "guest")
-		err := uc.DeleteMessage(ctx, 1)
+		err := uc.DeleteMessage(ctx, 1, "")
 		require.ErrorIs(t, err, ErrForbidden)
 	})
 
 	t.Run("not found", func(t *testing.T) {
 		repo.EXPECT().GetByID(ctx, int64(2)).Return(nil, customErr.ErrNotFound)
-		err := uc.DeleteMessage(ctx, 2)
+		err := uc.DeleteMessage(ctx, 2, "")
+		require.ErrorIs(t, err, ErrMessageNotFound)
+	})
+
+	t.Run("already deleted", func(t *testing.T) {
+		deletedAt := time.Now()
+		repo.EXPECT().GetByID(ctx, int64(6)).Return(&entity.Message{ID: 6, AuthorID: 1, DeletedAt: &deletedAt}, nil)
+		err := uc.DeleteMessage(ctx, 6, "")
 		require.ErrorIs(t, err, ErrMessageNotFound)
 	})
 
 	t.Run("foreign author - not admin", func(t *testing.T) {
 		repo.EXPECT().GetByID(ctx, int64(3)).Return(&entity.Message{ID: 3, AuthorID: 42}, nil)
-		err := uc.DeleteMessage(ctx, 3)
+		err := uc.DeleteMessage(ctx, 3, "")
 		require.ErrorIs(t, err, ErrForbidden)
 	})
 
 	t.Run("delete error", func(t *testing.T) {
 		repo.EXPECT().GetByID(ctx, int64(4)).Return(&entity.Message{ID: 4, AuthorID: 1}, nil)
-		repo.EXPECT().Delete(ctx, int64(4)).Return(errors.New("fail"))
-		err := uc.DeleteMessage(ctx, 4)
+		repo.EXPECT().Delete(ctx, int64(4), int64(1), "").Return(errors.New("fail"))
+		err := uc.DeleteMessage(ctx, 4, "")
 		require.ErrorContains(t, err, "MessageUC.Delete")
 	})
 
@@ -168,9 +251,107 @@ func TestMessageUC_DeleteMessage(t *testing.T) {
 			GetByID(ctx, int64(5)).
 			Return(nil, errors.New("unexpected timeout"))
 
-		err := uc.DeleteMessage(ctx, 5)
+		err := uc.DeleteMessage(ctx, 5, "")
 		require.ErrorContains(t, err, "MessageUC.Delete#get")
 	})
+	t.Run("moderator deletes foreign message in own category", func(t *testing.T) {
+		topics := mocks.NewMockTopicRepository(ctrl)
+		mods := mocks.NewMockModeratorRepository(ctrl)
+		uc := NewMessageUsecase(repo, topics, mods, nil, nil, MessageDeps{Publisher: publisher}, log, retention)
+		ctx := auth.WithUser(context.Background(), 3, "moderator")
+		repo.EXPECT().GetByID(ctx, int64(9)).Return(&entity.Message{ID: 9, TopicID: 10, AuthorID: 42}, nil)
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(&entity.Topic{ID: 10, CategoryID: 5}, nil)
+		mods.EXPECT().IsModerator(ctx, int64(3), int64(5)).Return(true, nil)
+		repo.EXPECT().Delete(ctx, int64(9), int64(3), "offtopic").Return(nil)
+		publisher.EXPECT().Publish(int64(10), gomock.Any())
+		require.NoError(t, uc.DeleteMessage(ctx, 9, "offtopic"))
+	})
+
+	t.Run("moderator cannot delete outside own categories", func(t *testing.T) {
+		topics := mocks.NewMockTopicRepository(ctrl)
+		mods := mocks.NewMockModeratorRepository(ctrl)
+		uc := NewMessageUsecase(repo, topics, mods, nil, nil, MessageDeps{Publisher: publisher}, log, retention)
+		ctx := auth.WithUser(context.Background(), 3, "moderator")
+		repo.EXPECT().GetByID(ctx, int64(9)).Return(&entity.Message{ID: 9, TopicID: 10, AuthorID: 42}, nil)
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(&entity.Topic{ID: 10, CategoryID: 6}, nil)
+		mods.EXPECT().IsModerator(ctx, int64(3), int64(6)).Return(false, nil)
+		require.ErrorIs(t, uc.DeleteMessage(ctx, 9, ""), ErrForbidden)
+	})
+}
+
+func TestMessageUC_RestoreMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockMessageRepository(ctrl)
+	publisher := mocks.NewMockMessagePublisher(ctrl)
+	uc := NewMessageUsecase(repo, nil, nil, nil, nil, MessageDeps{Publisher: publisher}, mocks.FakeLogger{}, retention)
+
+	ctx := auth.WithUser(context.Background(), 1, "admin")
+
+	t.Run("success", func(t *testing.T) {
+		restored := &entity.Message{ID: 1, TopicID: 10, AuthorID: 5, Content: "back"}
+		repo.EXPECT().Restore(ctx, int64(1), gomock.Any()).Return(nil)
+		repo.EXPECT().GetByID(ctx, int64(1)).Return(restored, nil)
+		publisher.EXPECT().Publish(int64(10), &entity.WSEvent{Action: entity.ActionRestored, Message: restored})
+		err := uc.RestoreMessage(ctx, 1)
+		require.NoError(t, err)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		err := uc.RestoreMessage(context.Background(), 1)
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	t.Run("forbidden for user", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "user")
+		err := uc.RestoreMessage(ctx, 1)
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("outside retention window", func(t *testing.T) {
+		repo.EXPECT().Restore(ctx, int64(2), gomock.Any()).Return(customErr.ErrNotFound)
+		err := uc.RestoreMessage(ctx, 2)
+		require.ErrorIs(t, err, ErrMessageNotFound)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		repo.EXPECT().Restore(ctx, int64(3), gomock.Any()).Return(errors.New("fail"))
+		err := uc.RestoreMessage(ctx, 3)
+		require.ErrorContains(t, err, "MessageUC.Restore")
+	})
+}
+
+func TestMessageUC_ListDeletedMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockMessageRepository(ctrl)
+	uc := NewMessageUsecase(repo, nil, nil, nil, nil, MessageDeps{}, mocks.FakeLogger{}, retention)
+
+	t.Run("success", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "admin")
+		expected := []*entity.Message{{ID: 1}}
+		repo.EXPECT().GetDeleted(ctx, gomock.Any()).Return(expected, nil)
+		list, err := uc.ListDeletedMessages(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expected, list)
+	})
+
+	t.Run("forbidden for user", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "user")
+		list, err := uc.ListDeletedMessages(ctx)
+		require.Nil(t, list)
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "admin")
+		repo.EXPECT().GetDeleted(ctx, gomock.Any()).Return(nil, errors.New("fail"))
+		list, err := uc.ListDeletedMessages(ctx)
+		require.Nil(t, list)
+		require.ErrorContains(t, err, "MessageUC.ListDeleted")
+	})
 }
 
 func TestMessageUC_GetMessages(t *testing.T) {
@@ -179,45 +360,278 @@ func TestMessageUC_GetMessages(t *testing.T) {
 
 	repo := mocks.NewMockMessageRepository(ctrl)
 	log := mocks.FakeLogger{}
-	uc := NewMessageUsecase(repo, nil, log)
+	uc := NewMessageUsecase(repo, nil, nil, nil, nil, MessageDeps{}, log, retention)
 
 	topicID := int64(100)
 
 	t.Run("success", func(t *testing.T) {
 		expected := []*entity.Message{{ID: 1}, {ID: 2}}
 		repo.EXPECT().GetByTopic(context.Background(), topicID).Return(expected, nil)
-		list, err := uc.GetMessages(context.Background(), topicID)
+		list, err := uc.GetMessages(context.Background(), topicID, OrderOldest)
 		require.NoError(t, err)
 		require.Equal(t, expected, list)
 	})
 
+	t.Run("tombstones hidden from regular users", func(t *testing.T) {
+		deletedAt := time.Now()
+		deletedBy := int64(7)
+		list := []*entity.Message{
+			{ID: 1, Content: "visible"},
+			{ID: 2, Content: "secret", DeletedAt: &deletedAt, DeletedBy: &deletedBy, DeleteReason: "spam"},
+		}
+		repo.EXPECT().GetByTopic(context.Background(), topicID).Return(list, nil)
+		res, err := uc.GetMessages(context.Background(), topicID, OrderOldest)
+		require.NoError(t, err)
+		require.Equal(t, "visible", res[0].Content)
+		require.True(t, res[1].IsDeleted())
+		require.Empty(t, res[1].Content)
+		require.Nil(t, res[1].DeletedBy)
+		require.Empty(t, res[1].DeleteReason)
+	})
+
+	t.Run("tombstones visible to admin", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "admin")
+		deletedAt := time.Now()
+		list := []*entity.Message{{ID: 2, Content: "secret", DeletedAt: &deletedAt, DeleteReason: "spam"}}
+		repo.EXPECT().GetByTopic(ctx, topicID).Return(list, nil)
+		res, err := uc.GetMessages(ctx, topicID, OrderOldest)
+		require.NoError(t, err)
+		require.Equal(t, "secret", res[0].Content)
+		require.Equal(t, "spam", res[0].DeleteReason)
+	})
+
+	t.Run("tombstones visible to category moderator", func(t *testing.T) {
+		topics := mocks.NewMockTopicRepository(ctrl)
+		mods := mocks.NewMockModeratorRepository(ctrl)
+		uc := NewMessageUsecase(repo, topics, mods, nil, nil, MessageDeps{}, log, retention)
+		ctx := auth.WithUser(context.Background(), 3, "moderator")
+		deletedAt := time.Now()
+		list := []*entity.Message{{ID: 2, Content: "secret", DeletedAt: &deletedAt}}
+		repo.EXPECT().GetByTopic(ctx, topicID).Return(list, nil)
+		topics.EXPECT().GetByID(ctx, topicID).Return(&entity.Topic{ID: topicID, CategoryID: 5}, nil)
+		mods.EXPECT().IsModerator(ctx, int64(3), int64(5)).Return(true, nil)
+		res, err := uc.GetMessages(ctx, topicID, OrderOldest)
+		require.NoError(t, err)
+		require.Equal(t, "secret", res[0].Content)
+	})
+
+	t.Run("by score keeps creation order on ties", func(t *testing.T) {
+		list := []*entity.Message{{ID: 1, Score: 2}, {ID: 2, Score: 5}, {ID: 3, Score: -1}, {ID: 4, Score: 2}}
+		repo.EXPECT().GetByTopic(context.Background(), topicID).Return(list, nil)
+		res, err := uc.GetMessages(context.Background(), topicID, OrderScore)
+		require.NoError(t, err)
+		var ids []int64
+		for _, m := range res {
+			ids = append(ids, m.ID)
+		}
+		require.Equal(t, []int64{2, 1, 4, 3}, ids)
+	})
+
+	t.Run("accepted answer comes first", func(t *testing.T) {
+		list := []*entity.Message{{ID: 1, Score: 1}, {ID: 2, Score: 4}, {ID: 3, Accepted: true}}
+		repo.EXPECT().GetByTopic(context.Background(), topicID).Return(list, nil)
+		res, err := uc.GetMessages(context.Background(), topicID, OrderScore)
+		require.NoError(t, err)
+		var ids []int64
+		for _, m := range res {
+			ids = append(ids, m.ID)
+		}
+		require.Equal(t, []int64{3, 2, 1}, ids)
+	})
+
+	t.Run("deleted accepted answer stays in place", func(t *testing.T) {
+		deletedAt := time.Now()
+		list := []*entity.Message{{ID: 1}, {ID: 2, Accepted: true, DeletedAt: &deletedAt}}
+		repo.EXPECT().GetByTopic(context.Background(), topicID).Return(list, nil)
+		res, err := uc.GetMessages(context.Background(), topicID, OrderOldest)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), res[0].ID)
+	})
+
+	t.Run("invalid order", func(t *testing.T) {
+		_, err := uc.GetMessages(context.Background(), topicID, "newest")
+		require.ErrorIs(t, err, ErrInvalidOrder)
+	})
+
 	t.Run("repo error", func(t *testing.T) {
 		repo.EXPECT().GetByTopic(context.Background(), topicID).Return(nil, errors.New("fail"))
-		list, err := uc.GetMessages(context.Background(), topicID)
+		list, err := uc.GetMessages(context.Background(), topicID, OrderOldest)
 		require.Nil(t, list)
 		require.ErrorContains(t, err, "MessageUC.List")
 	})
 }
 
+func TestMessageUC_GetMessages_Markdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockMessageRepository(ctrl)
+	uc := NewMessageUsecase(repo, nil, nil, nil, nil, MessageDeps{}, mocks.FakeLogger{}, retention)
+
+	const rel = `rel="nofollow ugc noopener noreferrer"`
+	cases := []struct {
+		name, content, html string
+	}{
+		{"plain text keeps line breaks", "hi\nthere", "<p>hi<br>\nthere</p>\n"},
+		{"emphasis", "**bold** *it* _it_ snake_case_name", "<p><strong>bold</strong> <em>it</em> <em>it</em> snake_case_name</p>\n"},
+		{"inline code is escaped", "use `<b>&</b>`", "<p>use <code>&lt;b&gt;&amp;&lt;/b&gt;</code></p>\n"},
+		{"code block with language", "```go\nif a < b {\n\treturn\n}\n```", "<pre><code class=\"language-go\">if a &lt; b {\n\treturn\n}\n</code></pre>\n"},
+		{"language hint is sanitized", "```\"><script>\nx\n```", "<pre><code class=\"language-script\">x\n</code></pre>\n"},
+		{"indented code", "    x := 1", "<pre><code>x := 1\n</code></pre>\n"},
+		{"quote ends at unquoted line", "> a\n> b\nreply", "<blockquote>\n<p>a<br>\nb</p>\n</blockquote>\n<p>reply</p>\n"},
+		{"lists", "- a\n  - b\n- c\n\n3. x\n4. y", "<ul>\n<li>a<ul>\n<li>b</li>\n</ul>\n</li>\n<li>c</li>\n</ul>\n<ol start=\"3\">\n<li>x</li>\n<li>y</li>\n</ol>\n"},
+		{"link", "[docs](https://go.dev/doc)", `<p><a href="https://go.dev/doc" ` + rel + `>docs</a></p>` + "\n"},
+		{"bare url without trailing dot", "see https://go.dev.", `<p>see <a href="https://go.dev" ` + rel + `>https://go.dev</a>.</p>` + "\n"},
+		{"raw html is escaped", `<img src=x onerror="alert(1)">`, "<p>&lt;img src=x onerror=&#34;alert(1)&#34;&gt;</p>\n"},
+		{"javascript link dropped", "[click](javascript:alert(1))", "<p>click</p>\n"},
+		{"scheme with tab dropped", "[click](java\tscript:alert(1))", "<p>[click](java\tscript:alert(1))</p>\n"},
+		{"quote in href is escaped", `[x](https://a.com/"onmouseover=alert(1))`, `<p><a href="https://a.com/&#34;onmouseover=alert(1)" ` + rel + `>x</a></p>` + "\n"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo.EXPECT().GetByTopic(context.Background(), int64(1)).Return([]*entity.Message{{ID: 1, Content: tc.content}}, nil)
+			list, err := uc.GetMessages(context.Background(), 1, OrderOldest)
+			require.NoError(t, err)
+			require.Equal(t, tc.content, list[0].Content)
+			require.Equal(t, tc.html, list[0].ContentHTML)
+		})
+	}
+
+	t.Run("tombstone has no html", func(t *testing.T) {
+		deletedAt := time.Now()
+		repo.EXPECT().GetByTopic(context.Background(), int64(1)).
+			Return([]*entity.Message{{ID: 1, Content: "**secret**", DeletedAt: &deletedAt}}, nil)
+		list, err := uc.GetMessages(context.Background(), 1, OrderOldest)
+		require.NoError(t, err)
+		require.Empty(t, list[0].ContentHTML)
+	})
+}
+
 func TestMessageUC_CleanupOldMessages(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	repo := mocks.NewMockMessageRepository(ctrl)
 	log := mocks.FakeLogger{}
-	uc := NewMessageUsecase(repo, nil, log)
+	uc := NewMessageUsecase(repo, nil, nil, nil, nil, MessageDeps{}, log, retention)
 
 	threshold := time.Now().Add(-24 * time.Hour)
 
 	t.Run("success", func(t *testing.T) {
-		repo.EXPECT().DeleteOlderThan(context.Background(), threshold).Return(nil)
-		err := uc.CleanupOldMessages(context.Background(), threshold)
+		repo.EXPECT().DeleteExpired(context.Background(), threshold, gomock.Any(), 100).Return(int64(40), nil)
+		report, err := uc.CleanupOldMessages(context.Background(), CleanupParams{Threshold: threshold, BatchSize: 100})
 		require.NoError(t, err)
+		require.Equal(t, int64(40), report.Total)
+		require.Equal(t, 1, report.Batches)
 	})
 
-	t.Run("repo error", func(t *testing.T) {
-		repo.EXPECT().DeleteOlderThan(context.Background(), threshold).Return(errors.New("db err"))
-		err := uc.CleanupOldMessages(context.Background(), threshold)
+	t.Run("runs in batches until a short batch", func(t *testing.T) {
+		gomock.InOrder(
+			repo.EXPECT().DeleteExpired(context.Background(), threshold, gomock.Any(), 10).Return(int64(10), nil),
+			repo.EXPECT().DeleteExpired(context.Background(), threshold, gomock.Any(), 10).Return(int64(10), nil),
+			repo.EXPECT().DeleteExpired(context.Background(), threshold, gomock.Any(), 10).Return(int64(3), nil),
+		)
+		report, err := uc.CleanupOldMessages(context.Background(), CleanupParams{Threshold: threshold, BatchSize: 10})
+		require.NoError(t, err)
+		require.Equal(t, int64(23), report.Total)
+		require.Equal(t, 3, report.Batches)
+	})
+
+	t.Run("default batch size", func(t *testing.T) {
+		repo.EXPECT().DeleteExpired(context.Background(), threshold, gomock.Any(), defaultCleanupBatch).Return(int64(0), nil)
+		_, err := uc.CleanupOldMessages(context.Background(), CleanupParams{Threshold: threshold})
+		require.NoError(t, err)
+	})
+
+	t.Run("dry run only counts", func(t *testing.T) {
+		stats := []*entity.RetentionStat{
+			{TopicID: 1, CategoryID: 1, Mode: entity.RetentionDays, Count: 5},
+			{TopicID: 2, CategoryID: 1, Mode: entity.RetentionLastN, Count: 7},
+		}
+		repo.EXPECT().CountExpired(context.Background(), threshold, gomock.Any()).Return(stats, nil)
+		repo.EXPECT().DeleteExpired(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		report, err := uc.CleanupOldMessages(context.Background(), CleanupParams{Threshold: threshold, DryRun: true})
+		require.NoError(t, err)
+		require.True(t, report.DryRun)
+		require.Equal(t, int64(12), report.Total)
+		require.Equal(t, stats, report.Topics)
+	})
+
+	t.Run("dry run repo error", func(t *testing.T) {
+		repo.EXPECT().CountExpired(context.Background(), threshold, gomock.Any()).Return(nil, errors.New("db err"))
+		report, err := uc.CleanupOldMessages(context.Background(), CleanupParams{Threshold: threshold, DryRun: true})
+		require.Nil(t, report)
+		require.ErrorContains(t, err, "MessageUC.Cleanup#count")
+	})
+
+	t.Run("repo error keeps progress", func(t *testing.T) {
+		gomock.InOrder(
+			repo.EXPECT().DeleteExpired(context.Background(), threshold, gomock.Any(), 10).Return(int64(10), nil),
+			repo.EXPECT().DeleteExpired(context.Background(), threshold, gomock.Any(), 10).Return(int64(0), errors.New("db err")),
+		)
+		report, err := uc.CleanupOldMessages(context.Background(), CleanupParams{Threshold: threshold, BatchSize: 10})
 		require.ErrorContains(t, err, "MessageUC.Cleanup")
+		require.Equal(t, int64(10), report.Total)
+	})
+
+	t.Run("stops between batches when cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		repo.EXPECT().DeleteExpired(ctx, threshold, gomock.Any(), 10).
+			DoAndReturn(func(context.Context, time.Time, time.Time, int) (int64, error) {
+				cancel()
+				return 10, nil
+			})
+		report, err := uc.CleanupOldMessages(ctx, CleanupParams{Threshold: threshold, BatchSize: 10})
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, int64(10), report.Total)
+		require.Equal(t, 1, report.Batches)
+	})
+}
+
+func TestMessageUC_PreviewCleanup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockMessageRepository(ctrl)
+	uc := NewMessageUsecase(repo, nil, nil, nil, nil, MessageDeps{}, mocks.FakeLogger{}, retention)
+
+	threshold := time.Now().Add(-24 * time.Hour)
+
+	t.Run("forbidden for user", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "user")
+		report, err := uc.PreviewCleanup(ctx, threshold)
+		require.Nil(t, report)
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("admin gets dry-run report", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "admin")
+		repo.EXPECT().CountExpired(ctx, threshold, gomock.Any()).Return(nil, nil)
+		report, err := uc.PreviewCleanup(ctx, threshold)
+		require.NoError(t, err)
+		require.True(t, report.DryRun)
+		require.Zero(t, report.Total)
+	})
+}
+
+func TestMessageUC_PurgeDeletedMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockMessageRepository(ctrl)
+	uc := NewMessageUsecase(repo, nil, nil, nil, nil, MessageDeps{}, mocks.FakeLogger{}, retention)
+
+	threshold := time.Now().Add(-retention)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().PurgeDeleted(context.Background(), threshold).Return(int64(3), nil)
+		err := uc.PurgeDeletedMessages(context.Background(), threshold)
+		require.NoError(t, err)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		repo.EXPECT().PurgeDeleted(context.Background(), threshold).Return(int64(0), errors.New("db err"))
+		err := uc.PurgeDeletedMessages(context.Background(), threshold)
+		require.ErrorContains(t, err, "MessageUC.PurgeDeleted")
 	})
 }
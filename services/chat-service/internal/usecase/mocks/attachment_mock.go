@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: C:/Users/user/GolandProjects/forum/services/chat-service/internal/usecase/attachment.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entity "chat-service/internal/entity"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAttachmentBinder is a mock of AttachmentBinder interface.
+type MockAttachmentBinder struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentBinderMockRecorder
+}
+
+// MockAttachmentBinderMockRecorder is the mock recorder for MockAttachmentBinder.
+type MockAttachmentBinderMockRecorder struct {
+	mock *MockAttachmentBinder
+}
+
+// NewMockAttachmentBinder creates a new mock instance.
+func NewMockAttachmentBinder(ctrl *gomock.Controller) *MockAttachmentBinder {
+	mock := &MockAttachmentBinder{ctrl: ctrl}
+	mock.recorder = &MockAttachmentBinderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachmentBinder) EXPECT() *MockAttachmentBinderMockRecorder {
+	return m.recorder
+}
+
+// Load mocks base method.
+func (m *MockAttachmentBinder) Load(ctx context.Context, list ...*entity.Message) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range list {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Load", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockAttachmentBinderMockRecorder) Load(ctx interface{}, list ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, list...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockAttachmentBinder)(nil).Load), varargs...)
+}
+
+// Pending mocks base method.
+func (m *MockAttachmentBinder) Pending(ctx context.Context, uploaderID int64, ids []int64) ([]*entity.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pending", ctx, uploaderID, ids)
+	ret0, _ := ret[0].([]*entity.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pending indicates an expected call of Pending.
+func (mr *MockAttachmentBinderMockRecorder) Pending(ctx, uploaderID, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pending", reflect.TypeOf((*MockAttachmentBinder)(nil).Pending), ctx, uploaderID, ids)
+}
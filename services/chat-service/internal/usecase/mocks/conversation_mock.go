@@ -0,0 +1,59 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: C:/Users/user/GolandProjects/forum/services/chat-service/internal/usecase/conversation.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entity "chat-service/internal/entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockConversationPublisher is a mock of ConversationPublisher interface.
+type MockConversationPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockConversationPublisherMockRecorder
+}
+
+// MockConversationPublisherMockRecorder is the mock recorder for MockConversationPublisher.
+type MockConversationPublisherMockRecorder struct {
+	mock *MockConversationPublisher
+}
+
+// NewMockConversationPublisher creates a new mock instance.
+func NewMockConversationPublisher(ctrl *gomock.Controller) *MockConversationPublisher {
+	mock := &MockConversationPublisher{ctrl: ctrl}
+	mock.recorder = &MockConversationPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConversationPublisher) EXPECT() *MockConversationPublisherMockRecorder {
+	return m.recorder
+}
+
+// PublishToConversation mocks base method.
+func (m *MockConversationPublisher) PublishToConversation(conversationID int64, ev *entity.WSEvent) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PublishToConversation", conversationID, ev)
+}
+
+// PublishToConversation indicates an expected call of PublishToConversation.
+func (mr *MockConversationPublisherMockRecorder) PublishToConversation(conversationID, ev interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishToConversation", reflect.TypeOf((*MockConversationPublisher)(nil).PublishToConversation), conversationID, ev)
+}
+
+// PublishToUser mocks base method.
+func (m *MockConversationPublisher) PublishToUser(userID int64, ev *entity.WSEvent) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PublishToUser", userID, ev)
+}
+
+// PublishToUser indicates an expected call of PublishToUser.
+func (mr *MockConversationPublisherMockRecorder) PublishToUser(userID, ev interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishToUser", reflect.TypeOf((*MockConversationPublisher)(nil).PublishToUser), userID, ev)
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: C:/Users/user/GolandProjects/forum/services/chat-service/internal/usecase/mention.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entity "chat-service/internal/entity"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockMentionProcessor is a mock of MentionProcessor interface.
+type MockMentionProcessor struct {
+	ctrl     *gomock.Controller
+	recorder *MockMentionProcessorMockRecorder
+}
+
+// MockMentionProcessorMockRecorder is the mock recorder for MockMentionProcessor.
+type MockMentionProcessorMockRecorder struct {
+	mock *MockMentionProcessor
+}
+
+// NewMockMentionProcessor creates a new mock instance.
+func NewMockMentionProcessor(ctrl *gomock.Controller) *MockMentionProcessor {
+	mock := &MockMentionProcessor{ctrl: ctrl}
+	mock.recorder = &MockMentionProcessorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMentionProcessor) EXPECT() *MockMentionProcessorMockRecorder {
+	return m.recorder
+}
+
+// ProcessMentions mocks base method.
+func (m_2 *MockMentionProcessor) ProcessMentions(ctx context.Context, m *entity.Message, edited bool) []int64 {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "ProcessMentions", ctx, m, edited)
+	ret0, _ := ret[0].([]int64)
+	return ret0
+}
+
+// ProcessMentions indicates an expected call of ProcessMentions.
+func (mr *MockMentionProcessorMockRecorder) ProcessMentions(ctx, m, edited interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessMentions", reflect.TypeOf((*MockMentionProcessor)(nil).ProcessMentions), ctx, m, edited)
+}
@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: C:/Users/user/GolandProjects/forum/services/chat-service/internal/usecase/notification.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entity "chat-service/internal/entity"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockUserPublisher is a mock of UserPublisher interface.
+type MockUserPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserPublisherMockRecorder
+}
+
+// MockUserPublisherMockRecorder is the mock recorder for MockUserPublisher.
+type MockUserPublisherMockRecorder struct {
+	mock *MockUserPublisher
+}
+
+// NewMockUserPublisher creates a new mock instance.
+func NewMockUserPublisher(ctrl *gomock.Controller) *MockUserPublisher {
+	mock := &MockUserPublisher{ctrl: ctrl}
+	mock.recorder = &MockUserPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserPublisher) EXPECT() *MockUserPublisherMockRecorder {
+	return m.recorder
+}
+
+// PublishToUser mocks base method.
+func (m *MockUserPublisher) PublishToUser(userID int64, ev *entity.WSEvent) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PublishToUser", userID, ev)
+}
+
+// PublishToUser indicates an expected call of PublishToUser.
+func (mr *MockUserPublisherMockRecorder) PublishToUser(userID, ev interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishToUser", reflect.TypeOf((*MockUserPublisher)(nil).PublishToUser), userID, ev)
+}
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockNotifier) Notify(ctx context.Context, list ...*entity.Notification) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range list {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Notify", varargs...)
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockNotifierMockRecorder) Notify(ctx interface{}, list ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, list...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockNotifier)(nil).Notify), varargs...)
+}
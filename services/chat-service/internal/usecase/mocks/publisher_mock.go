@@ -35,7 +35,7 @@ func (m *MockMessagePublisher) EXPECT() *MockMessagePublisherMockRecorder {
 }
 
 // Publish mocks base method.
-func (m_2 *MockMessagePublisher) Publish(topicID int64, m *entity.Message) {
+func (m_2 *MockMessagePublisher) Publish(topicID int64, m *entity.WSEvent) {
 	m_2.ctrl.T.Helper()
 	m_2.ctrl.Call(m_2, "Publish", topicID, m)
 }
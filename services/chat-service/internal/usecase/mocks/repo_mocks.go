@@ -94,6 +94,48 @@ func (mr *MockCategoryRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCategoryRepository)(nil).GetByID), ctx, id)
 }
 
+// Reorder mocks base method.
+func (m *MockCategoryRepository) Reorder(ctx context.Context, items []entity.CategoryPosition) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", ctx, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockCategoryRepositoryMockRecorder) Reorder(ctx, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockCategoryRepository)(nil).Reorder), ctx, items)
+}
+
+// SetQA mocks base method.
+func (m *MockCategoryRepository) SetQA(ctx context.Context, id int64, qa bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetQA", ctx, id, qa)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetQA indicates an expected call of SetQA.
+func (mr *MockCategoryRepositoryMockRecorder) SetQA(ctx, id, qa interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetQA", reflect.TypeOf((*MockCategoryRepository)(nil).SetQA), ctx, id, qa)
+}
+
+// SetRetention mocks base method.
+func (m *MockCategoryRepository) SetRetention(ctx context.Context, id int64, p entity.RetentionPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRetention", ctx, id, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRetention indicates an expected call of SetRetention.
+func (mr *MockCategoryRepositoryMockRecorder) SetRetention(ctx, id, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRetention", reflect.TypeOf((*MockCategoryRepository)(nil).SetRetention), ctx, id, p)
+}
+
 // Update mocks base method.
 func (m *MockCategoryRepository) Update(ctx context.Context, c *entity.Category) error {
 	m.ctrl.T.Helper()
@@ -147,17 +189,17 @@ func (mr *MockTopicRepositoryMockRecorder) Create(ctx, t interface{}) *gomock.Ca
 }
 
 // Delete mocks base method.
-func (m *MockTopicRepository) Delete(ctx context.Context, id int64) error {
+func (m *MockTopicRepository) Delete(ctx context.Context, id, deletedBy int64, reason string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, deletedBy, reason)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockTopicRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+func (mr *MockTopicRepositoryMockRecorder) Delete(ctx, id, deletedBy, reason interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTopicRepository)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTopicRepository)(nil).Delete), ctx, id, deletedBy, reason)
 }
 
 // GetByCategory mocks base method.
@@ -190,6 +232,136 @@ func (mr *MockTopicRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTopicRepository)(nil).GetByID), ctx, id)
 }
 
+// GetDeleted mocks base method.
+func (m *MockTopicRepository) GetDeleted(ctx context.Context, since time.Time) ([]*entity.Topic, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeleted", ctx, since)
+	ret0, _ := ret[0].([]*entity.Topic)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeleted indicates an expected call of GetDeleted.
+func (mr *MockTopicRepositoryMockRecorder) GetDeleted(ctx, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeleted", reflect.TypeOf((*MockTopicRepository)(nil).GetDeleted), ctx, since)
+}
+
+// Merge mocks base method.
+func (m *MockTopicRepository) Merge(ctx context.Context, sourceID, targetID, actorID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, sourceID, targetID, actorID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockTopicRepositoryMockRecorder) Merge(ctx, sourceID, targetID, actorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockTopicRepository)(nil).Merge), ctx, sourceID, targetID, actorID)
+}
+
+// Move mocks base method.
+func (m *MockTopicRepository) Move(ctx context.Context, id, categoryID, actorID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", ctx, id, categoryID, actorID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockTopicRepositoryMockRecorder) Move(ctx, id, categoryID, actorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockTopicRepository)(nil).Move), ctx, id, categoryID, actorID)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockTopicRepository) PurgeDeleted(ctx context.Context, threshold time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", ctx, threshold)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockTopicRepositoryMockRecorder) PurgeDeleted(ctx, threshold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockTopicRepository)(nil).PurgeDeleted), ctx, threshold)
+}
+
+// Restore mocks base method.
+func (m *MockTopicRepository) Restore(ctx context.Context, id int64, since time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id, since)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockTopicRepositoryMockRecorder) Restore(ctx, id, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockTopicRepository)(nil).Restore), ctx, id, since)
+}
+
+// SetAccepted mocks base method.
+func (m *MockTopicRepository) SetAccepted(ctx context.Context, id int64, messageID *int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAccepted", ctx, id, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAccepted indicates an expected call of SetAccepted.
+func (mr *MockTopicRepositoryMockRecorder) SetAccepted(ctx, id, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccepted", reflect.TypeOf((*MockTopicRepository)(nil).SetAccepted), ctx, id, messageID)
+}
+
+// SetLocked mocks base method.
+func (m *MockTopicRepository) SetLocked(ctx context.Context, id int64, locked bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLocked", ctx, id, locked)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLocked indicates an expected call of SetLocked.
+func (mr *MockTopicRepositoryMockRecorder) SetLocked(ctx, id, locked interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLocked", reflect.TypeOf((*MockTopicRepository)(nil).SetLocked), ctx, id, locked)
+}
+
+// SetPinned mocks base method.
+func (m *MockTopicRepository) SetPinned(ctx context.Context, id int64, pinned bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPinned", ctx, id, pinned)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPinned indicates an expected call of SetPinned.
+func (mr *MockTopicRepositoryMockRecorder) SetPinned(ctx, id, pinned interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPinned", reflect.TypeOf((*MockTopicRepository)(nil).SetPinned), ctx, id, pinned)
+}
+
+// SetRetention mocks base method.
+func (m *MockTopicRepository) SetRetention(ctx context.Context, id int64, p entity.RetentionPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRetention", ctx, id, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRetention indicates an expected call of SetRetention.
+func (mr *MockTopicRepositoryMockRecorder) SetRetention(ctx, id, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRetention", reflect.TypeOf((*MockTopicRepository)(nil).SetRetention), ctx, id, p)
+}
+
 // Update mocks base method.
 func (m *MockTopicRepository) Update(ctx context.Context, t *entity.Topic) (int64, error) {
 	m.ctrl.T.Helper()
@@ -228,6 +400,21 @@ func (m *MockMessageRepository) EXPECT() *MockMessageRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CountExpired mocks base method.
+func (m *MockMessageRepository) CountExpired(ctx context.Context, threshold, now time.Time) ([]*entity.RetentionStat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountExpired", ctx, threshold, now)
+	ret0, _ := ret[0].([]*entity.RetentionStat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountExpired indicates an expected call of CountExpired.
+func (mr *MockMessageRepositoryMockRecorder) CountExpired(ctx, threshold, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountExpired", reflect.TypeOf((*MockMessageRepository)(nil).CountExpired), ctx, threshold, now)
+}
+
 // Create mocks base method.
 func (m_2 *MockMessageRepository) Create(ctx context.Context, m *entity.Message) error {
 	m_2.ctrl.T.Helper()
@@ -243,31 +430,32 @@ func (mr *MockMessageRepositoryMockRecorder) Create(ctx, m interface{}) *gomock.
 }
 
 // Delete mocks base method.
-func (m *MockMessageRepository) Delete(ctx context.Context, id int64) error {
+func (m *MockMessageRepository) Delete(ctx context.Context, id, deletedBy int64, reason string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, deletedBy, reason)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockMessageRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+func (mr *MockMessageRepositoryMockRecorder) Delete(ctx, id, deletedBy, reason interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockMessageRepository)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockMessageRepository)(nil).Delete), ctx, id, deletedBy, reason)
 }
 
-// DeleteOlderThan mocks base method.
-func (m *MockMessageRepository) DeleteOlderThan(ctx context.Context, threshold time.Time) error {
+// DeleteExpired mocks base method.
+func (m *MockMessageRepository) DeleteExpired(ctx context.Context, threshold, now time.Time, limit int) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteOlderThan", ctx, threshold)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, threshold, now, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteOlderThan indicates an expected call of DeleteOlderThan.
-func (mr *MockMessageRepositoryMockRecorder) DeleteOlderThan(ctx, threshold interface{}) *gomock.Call {
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockMessageRepositoryMockRecorder) DeleteExpired(ctx, threshold, now, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOlderThan", reflect.TypeOf((*MockMessageRepository)(nil).DeleteOlderThan), ctx, threshold)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockMessageRepository)(nil).DeleteExpired), ctx, threshold, now, limit)
 }
 
 // GetByID mocks base method.
@@ -300,6 +488,65 @@ func (mr *MockMessageRepositoryMockRecorder) GetByTopic(ctx, topicID interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTopic", reflect.TypeOf((*MockMessageRepository)(nil).GetByTopic), ctx, topicID)
 }
 
+// GetDeleted mocks base method.
+func (m *MockMessageRepository) GetDeleted(ctx context.Context, since time.Time) ([]*entity.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeleted", ctx, since)
+	ret0, _ := ret[0].([]*entity.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeleted indicates an expected call of GetDeleted.
+func (mr *MockMessageRepositoryMockRecorder) GetDeleted(ctx, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeleted", reflect.TypeOf((*MockMessageRepository)(nil).GetDeleted), ctx, since)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockMessageRepository) PurgeDeleted(ctx context.Context, threshold time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", ctx, threshold)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockMessageRepositoryMockRecorder) PurgeDeleted(ctx, threshold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockMessageRepository)(nil).PurgeDeleted), ctx, threshold)
+}
+
+// RecentByAuthor mocks base method.
+func (m *MockMessageRepository) RecentByAuthor(ctx context.Context, authorID int64, since time.Time, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecentByAuthor", ctx, authorID, since, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecentByAuthor indicates an expected call of RecentByAuthor.
+func (mr *MockMessageRepositoryMockRecorder) RecentByAuthor(ctx, authorID, since, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecentByAuthor", reflect.TypeOf((*MockMessageRepository)(nil).RecentByAuthor), ctx, authorID, since, limit)
+}
+
+// Restore mocks base method.
+func (m *MockMessageRepository) Restore(ctx context.Context, id int64, since time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id, since)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockMessageRepositoryMockRecorder) Restore(ctx, id, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockMessageRepository)(nil).Restore), ctx, id, since)
+}
+
 // Update mocks base method.
 func (m *MockMessageRepository) Update(ctx context.Context, id int64, newContent string) error {
 	m.ctrl.T.Helper()
@@ -313,3 +560,1682 @@ func (mr *MockMessageRepositoryMockRecorder) Update(ctx, id, newContent interfac
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockMessageRepository)(nil).Update), ctx, id, newContent)
 }
+
+// MockModeratorRepository is a mock of ModeratorRepository interface.
+type MockModeratorRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockModeratorRepositoryMockRecorder
+}
+
+// MockModeratorRepositoryMockRecorder is the mock recorder for MockModeratorRepository.
+type MockModeratorRepositoryMockRecorder struct {
+	mock *MockModeratorRepository
+}
+
+// NewMockModeratorRepository creates a new mock instance.
+func NewMockModeratorRepository(ctrl *gomock.Controller) *MockModeratorRepository {
+	mock := &MockModeratorRepository{ctrl: ctrl}
+	mock.recorder = &MockModeratorRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModeratorRepository) EXPECT() *MockModeratorRepositoryMockRecorder {
+	return m.recorder
+}
+
+// IsModerator mocks base method.
+func (m *MockModeratorRepository) IsModerator(ctx context.Context, userID, categoryID int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsModerator", ctx, userID, categoryID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsModerator indicates an expected call of IsModerator.
+func (mr *MockModeratorRepositoryMockRecorder) IsModerator(ctx, userID, categoryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsModerator", reflect.TypeOf((*MockModeratorRepository)(nil).IsModerator), ctx, userID, categoryID)
+}
+
+// MockModerationRepository is a mock of ModerationRepository interface.
+type MockModerationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockModerationRepositoryMockRecorder
+}
+
+// MockModerationRepositoryMockRecorder is the mock recorder for MockModerationRepository.
+type MockModerationRepositoryMockRecorder struct {
+	mock *MockModerationRepository
+}
+
+// NewMockModerationRepository creates a new mock instance.
+func NewMockModerationRepository(ctrl *gomock.Controller) *MockModerationRepository {
+	mock := &MockModerationRepository{ctrl: ctrl}
+	mock.recorder = &MockModerationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModerationRepository) EXPECT() *MockModerationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockModerationRepository) List(ctx context.Context, f entity.ModerationFilter) ([]*entity.ModerationEntry, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, f)
+	ret0, _ := ret[0].([]*entity.ModerationEntry)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockModerationRepositoryMockRecorder) List(ctx, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockModerationRepository)(nil).List), ctx, f)
+}
+
+// Save mocks base method.
+func (m *MockModerationRepository) Save(ctx context.Context, e *entity.ModerationEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockModerationRepositoryMockRecorder) Save(ctx, e interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockModerationRepository)(nil).Save), ctx, e)
+}
+
+// MockReportRepository is a mock of ReportRepository interface.
+type MockReportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportRepositoryMockRecorder
+}
+
+// MockReportRepositoryMockRecorder is the mock recorder for MockReportRepository.
+type MockReportRepositoryMockRecorder struct {
+	mock *MockReportRepository
+}
+
+// NewMockReportRepository creates a new mock instance.
+func NewMockReportRepository(ctrl *gomock.Controller) *MockReportRepository {
+	mock := &MockReportRepository{ctrl: ctrl}
+	mock.recorder = &MockReportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportRepository) EXPECT() *MockReportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockReportRepository) Create(ctx context.Context, r *entity.Report, hideAfter int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, r, hideAfter)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockReportRepositoryMockRecorder) Create(ctx, r, hideAfter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReportRepository)(nil).Create), ctx, r, hideAfter)
+}
+
+// GetByID mocks base method.
+func (m *MockReportRepository) GetByID(ctx context.Context, id int64) (*entity.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockReportRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockReportRepository)(nil).GetByID), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockReportRepository) List(ctx context.Context, f entity.ReportFilter) ([]*entity.Report, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, f)
+	ret0, _ := ret[0].([]*entity.Report)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockReportRepositoryMockRecorder) List(ctx, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockReportRepository)(nil).List), ctx, f)
+}
+
+// Resolve mocks base method.
+func (m *MockReportRepository) Resolve(ctx context.Context, targetType string, targetID int64, state string, resolvedBy int64, resolution string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", ctx, targetType, targetID, state, resolvedBy, resolution)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockReportRepositoryMockRecorder) Resolve(ctx, targetType, targetID, state, resolvedBy, resolution interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockReportRepository)(nil).Resolve), ctx, targetType, targetID, state, resolvedBy, resolution)
+}
+
+// MockMuteRepository is a mock of MuteRepository interface.
+type MockMuteRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMuteRepositoryMockRecorder
+}
+
+// MockMuteRepositoryMockRecorder is the mock recorder for MockMuteRepository.
+type MockMuteRepositoryMockRecorder struct {
+	mock *MockMuteRepository
+}
+
+// NewMockMuteRepository creates a new mock instance.
+func NewMockMuteRepository(ctrl *gomock.Controller) *MockMuteRepository {
+	mock := &MockMuteRepository{ctrl: ctrl}
+	mock.recorder = &MockMuteRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMuteRepository) EXPECT() *MockMuteRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m_2 *MockMuteRepository) Create(ctx context.Context, m *entity.Mute) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "Create", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockMuteRepositoryMockRecorder) Create(ctx, m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockMuteRepository)(nil).Create), ctx, m)
+}
+
+// Delete mocks base method.
+func (m *MockMuteRepository) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockMuteRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockMuteRepository)(nil).Delete), ctx, id)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockMuteRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockMuteRepositoryMockRecorder) DeleteExpired(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockMuteRepository)(nil).DeleteExpired), ctx, before)
+}
+
+// FindActive mocks base method.
+func (m *MockMuteRepository) FindActive(ctx context.Context, userID, topicID, categoryID int64) (*entity.Mute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindActive", ctx, userID, topicID, categoryID)
+	ret0, _ := ret[0].(*entity.Mute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindActive indicates an expected call of FindActive.
+func (mr *MockMuteRepositoryMockRecorder) FindActive(ctx, userID, topicID, categoryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActive", reflect.TypeOf((*MockMuteRepository)(nil).FindActive), ctx, userID, topicID, categoryID)
+}
+
+// GetByID mocks base method.
+func (m *MockMuteRepository) GetByID(ctx context.Context, id int64) (*entity.Mute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Mute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockMuteRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockMuteRepository)(nil).GetByID), ctx, id)
+}
+
+// ListActive mocks base method.
+func (m *MockMuteRepository) ListActive(ctx context.Context, userID int64) ([]*entity.Mute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActive", ctx, userID)
+	ret0, _ := ret[0].([]*entity.Mute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActive indicates an expected call of ListActive.
+func (mr *MockMuteRepositoryMockRecorder) ListActive(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActive", reflect.TypeOf((*MockMuteRepository)(nil).ListActive), ctx, userID)
+}
+
+// MockFilterRepository is a mock of FilterRepository interface.
+type MockFilterRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFilterRepositoryMockRecorder
+}
+
+// MockFilterRepositoryMockRecorder is the mock recorder for MockFilterRepository.
+type MockFilterRepositoryMockRecorder struct {
+	mock *MockFilterRepository
+}
+
+// NewMockFilterRepository creates a new mock instance.
+func NewMockFilterRepository(ctrl *gomock.Controller) *MockFilterRepository {
+	mock := &MockFilterRepository{ctrl: ctrl}
+	mock.recorder = &MockFilterRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFilterRepository) EXPECT() *MockFilterRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateRule mocks base method.
+func (m *MockFilterRepository) CreateRule(ctx context.Context, f *entity.FilterRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRule", ctx, f)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRule indicates an expected call of CreateRule.
+func (mr *MockFilterRepositoryMockRecorder) CreateRule(ctx, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRule", reflect.TypeOf((*MockFilterRepository)(nil).CreateRule), ctx, f)
+}
+
+// DeleteRule mocks base method.
+func (m *MockFilterRepository) DeleteRule(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRule", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRule indicates an expected call of DeleteRule.
+func (mr *MockFilterRepositoryMockRecorder) DeleteRule(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRule", reflect.TypeOf((*MockFilterRepository)(nil).DeleteRule), ctx, id)
+}
+
+// GetRule mocks base method.
+func (m *MockFilterRepository) GetRule(ctx context.Context, id int64) (*entity.FilterRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRule", ctx, id)
+	ret0, _ := ret[0].(*entity.FilterRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRule indicates an expected call of GetRule.
+func (mr *MockFilterRepositoryMockRecorder) GetRule(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRule", reflect.TypeOf((*MockFilterRepository)(nil).GetRule), ctx, id)
+}
+
+// GetSettings mocks base method.
+func (m *MockFilterRepository) GetSettings(ctx context.Context) (*entity.FilterSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettings", ctx)
+	ret0, _ := ret[0].(*entity.FilterSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSettings indicates an expected call of GetSettings.
+func (mr *MockFilterRepositoryMockRecorder) GetSettings(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettings", reflect.TypeOf((*MockFilterRepository)(nil).GetSettings), ctx)
+}
+
+// ListRules mocks base method.
+func (m *MockFilterRepository) ListRules(ctx context.Context) ([]*entity.FilterRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRules", ctx)
+	ret0, _ := ret[0].([]*entity.FilterRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRules indicates an expected call of ListRules.
+func (mr *MockFilterRepositoryMockRecorder) ListRules(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRules", reflect.TypeOf((*MockFilterRepository)(nil).ListRules), ctx)
+}
+
+// SaveSettings mocks base method.
+func (m *MockFilterRepository) SaveSettings(ctx context.Context, s *entity.FilterSettings) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSettings", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSettings indicates an expected call of SaveSettings.
+func (mr *MockFilterRepositoryMockRecorder) SaveSettings(ctx, s interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSettings", reflect.TypeOf((*MockFilterRepository)(nil).SaveSettings), ctx, s)
+}
+
+// UpdateRule mocks base method.
+func (m *MockFilterRepository) UpdateRule(ctx context.Context, f *entity.FilterRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRule", ctx, f)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRule indicates an expected call of UpdateRule.
+func (mr *MockFilterRepositoryMockRecorder) UpdateRule(ctx, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRule", reflect.TypeOf((*MockFilterRepository)(nil).UpdateRule), ctx, f)
+}
+
+// MockUserRepository is a mock of UserRepository interface.
+type MockUserRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRepositoryMockRecorder
+}
+
+// MockUserRepositoryMockRecorder is the mock recorder for MockUserRepository.
+type MockUserRepositoryMockRecorder struct {
+	mock *MockUserRepository
+}
+
+// NewMockUserRepository creates a new mock instance.
+func NewMockUserRepository(ctrl *gomock.Controller) *MockUserRepository {
+	mock := &MockUserRepository{ctrl: ctrl}
+	mock.recorder = &MockUserRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetStanding mocks base method.
+func (m *MockUserRepository) GetStanding(ctx context.Context, userID int64) (*entity.Standing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStanding", ctx, userID)
+	ret0, _ := ret[0].(*entity.Standing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStanding indicates an expected call of GetStanding.
+func (mr *MockUserRepositoryMockRecorder) GetStanding(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStanding", reflect.TypeOf((*MockUserRepository)(nil).GetStanding), ctx, userID)
+}
+
+// MockMentionRepository is a mock of MentionRepository interface.
+type MockMentionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMentionRepositoryMockRecorder
+}
+
+// MockMentionRepositoryMockRecorder is the mock recorder for MockMentionRepository.
+type MockMentionRepositoryMockRecorder struct {
+	mock *MockMentionRepository
+}
+
+// NewMockMentionRepository creates a new mock instance.
+func NewMockMentionRepository(ctrl *gomock.Controller) *MockMentionRepository {
+	mock := &MockMentionRepository{ctrl: ctrl}
+	mock.recorder = &MockMentionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMentionRepository) EXPECT() *MockMentionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListForUser mocks base method.
+func (m *MockMentionRepository) ListForUser(ctx context.Context, userID int64, limit, offset int) ([]*entity.Mention, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListForUser", ctx, userID, limit, offset)
+	ret0, _ := ret[0].([]*entity.Mention)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListForUser indicates an expected call of ListForUser.
+func (mr *MockMentionRepositoryMockRecorder) ListForUser(ctx, userID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListForUser", reflect.TypeOf((*MockMentionRepository)(nil).ListForUser), ctx, userID, limit, offset)
+}
+
+// Replace mocks base method.
+func (m *MockMentionRepository) Replace(ctx context.Context, messageID int64, userIDs []int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", ctx, messageID, userIDs)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockMentionRepositoryMockRecorder) Replace(ctx, messageID, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockMentionRepository)(nil).Replace), ctx, messageID, userIDs)
+}
+
+// MockNotificationRepository is a mock of NotificationRepository interface.
+type MockNotificationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationRepositoryMockRecorder
+}
+
+// MockNotificationRepositoryMockRecorder is the mock recorder for MockNotificationRepository.
+type MockNotificationRepositoryMockRecorder struct {
+	mock *MockNotificationRepository
+}
+
+// NewMockNotificationRepository creates a new mock instance.
+func NewMockNotificationRepository(ctrl *gomock.Controller) *MockNotificationRepository {
+	mock := &MockNotificationRepository{ctrl: ctrl}
+	mock.recorder = &MockNotificationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationRepository) EXPECT() *MockNotificationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountUnread mocks base method.
+func (m *MockNotificationRepository) CountUnread(ctx context.Context, userID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnread", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnread indicates an expected call of CountUnread.
+func (mr *MockNotificationRepositoryMockRecorder) CountUnread(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnread", reflect.TypeOf((*MockNotificationRepository)(nil).CountUnread), ctx, userID)
+}
+
+// Create mocks base method.
+func (m *MockNotificationRepository) Create(ctx context.Context, n *entity.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, n)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockNotificationRepositoryMockRecorder) Create(ctx, n interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNotificationRepository)(nil).Create), ctx, n)
+}
+
+// List mocks base method.
+func (m *MockNotificationRepository) List(ctx context.Context, f entity.NotificationFilter) ([]*entity.Notification, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, f)
+	ret0, _ := ret[0].([]*entity.Notification)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockNotificationRepositoryMockRecorder) List(ctx, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockNotificationRepository)(nil).List), ctx, f)
+}
+
+// MarkAllRead mocks base method.
+func (m *MockNotificationRepository) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllRead", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAllRead indicates an expected call of MarkAllRead.
+func (mr *MockNotificationRepositoryMockRecorder) MarkAllRead(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllRead", reflect.TypeOf((*MockNotificationRepository)(nil).MarkAllRead), ctx, userID)
+}
+
+// MarkRead mocks base method.
+func (m *MockNotificationRepository) MarkRead(ctx context.Context, userID, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockNotificationRepositoryMockRecorder) MarkRead(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockNotificationRepository)(nil).MarkRead), ctx, userID, id)
+}
+
+// MockSubscriptionRepository is a mock of SubscriptionRepository interface.
+type MockSubscriptionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubscriptionRepositoryMockRecorder
+}
+
+// MockSubscriptionRepositoryMockRecorder is the mock recorder for MockSubscriptionRepository.
+type MockSubscriptionRepositoryMockRecorder struct {
+	mock *MockSubscriptionRepository
+}
+
+// NewMockSubscriptionRepository creates a new mock instance.
+func NewMockSubscriptionRepository(ctrl *gomock.Controller) *MockSubscriptionRepository {
+	mock := &MockSubscriptionRepository{ctrl: ctrl}
+	mock.recorder = &MockSubscriptionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubscriptionRepository) EXPECT() *MockSubscriptionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DigestTopics mocks base method.
+func (m *MockSubscriptionRepository) DigestTopics(ctx context.Context, userID int64, since, until time.Time, limit, perTopic int) ([]*entity.DigestTopic, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DigestTopics", ctx, userID, since, until, limit, perTopic)
+	ret0, _ := ret[0].([]*entity.DigestTopic)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DigestTopics indicates an expected call of DigestTopics.
+func (mr *MockSubscriptionRepositoryMockRecorder) DigestTopics(ctx, userID, since, until, limit, perTopic interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DigestTopics", reflect.TypeOf((*MockSubscriptionRepository)(nil).DigestTopics), ctx, userID, since, until, limit, perTopic)
+}
+
+// DueRecipients mocks base method.
+func (m *MockSubscriptionRepository) DueRecipients(ctx context.Context, now time.Time, afterID int64, limit int) ([]*entity.DigestRecipient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DueRecipients", ctx, now, afterID, limit)
+	ret0, _ := ret[0].([]*entity.DigestRecipient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DueRecipients indicates an expected call of DueRecipients.
+func (mr *MockSubscriptionRepositoryMockRecorder) DueRecipients(ctx, now, afterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DueRecipients", reflect.TypeOf((*MockSubscriptionRepository)(nil).DueRecipients), ctx, now, afterID, limit)
+}
+
+// Follow mocks base method.
+func (m *MockSubscriptionRepository) Follow(ctx context.Context, s *entity.Subscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Follow", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Follow indicates an expected call of Follow.
+func (mr *MockSubscriptionRepositoryMockRecorder) Follow(ctx, s interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Follow", reflect.TypeOf((*MockSubscriptionRepository)(nil).Follow), ctx, s)
+}
+
+// GetSettings mocks base method.
+func (m *MockSubscriptionRepository) GetSettings(ctx context.Context, userID int64) (*entity.DigestSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettings", ctx, userID)
+	ret0, _ := ret[0].(*entity.DigestSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSettings indicates an expected call of GetSettings.
+func (mr *MockSubscriptionRepositoryMockRecorder) GetSettings(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettings", reflect.TypeOf((*MockSubscriptionRepository)(nil).GetSettings), ctx, userID)
+}
+
+// ListByUser mocks base method.
+func (m *MockSubscriptionRepository) ListByUser(ctx context.Context, userID int64) ([]*entity.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID)
+	ret0, _ := ret[0].([]*entity.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockSubscriptionRepositoryMockRecorder) ListByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockSubscriptionRepository)(nil).ListByUser), ctx, userID)
+}
+
+// MarkSent mocks base method.
+func (m *MockSubscriptionRepository) MarkSent(ctx context.Context, userID int64, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSent", ctx, userID, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkSent indicates an expected call of MarkSent.
+func (mr *MockSubscriptionRepositoryMockRecorder) MarkSent(ctx, userID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSent", reflect.TypeOf((*MockSubscriptionRepository)(nil).MarkSent), ctx, userID, at)
+}
+
+// SaveSettings mocks base method.
+func (m *MockSubscriptionRepository) SaveSettings(ctx context.Context, s *entity.DigestSettings) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSettings", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSettings indicates an expected call of SaveSettings.
+func (mr *MockSubscriptionRepositoryMockRecorder) SaveSettings(ctx, s interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSettings", reflect.TypeOf((*MockSubscriptionRepository)(nil).SaveSettings), ctx, s)
+}
+
+// Unfollow mocks base method.
+func (m *MockSubscriptionRepository) Unfollow(ctx context.Context, userID int64, topicID, categoryID *int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unfollow", ctx, userID, topicID, categoryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unfollow indicates an expected call of Unfollow.
+func (mr *MockSubscriptionRepositoryMockRecorder) Unfollow(ctx, userID, topicID, categoryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfollow", reflect.TypeOf((*MockSubscriptionRepository)(nil).Unfollow), ctx, userID, topicID, categoryID)
+}
+
+// Unsubscribe mocks base method.
+func (m *MockSubscriptionRepository) Unsubscribe(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsubscribe", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unsubscribe indicates an expected call of Unsubscribe.
+func (mr *MockSubscriptionRepositoryMockRecorder) Unsubscribe(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockSubscriptionRepository)(nil).Unsubscribe), ctx, token)
+}
+
+// MockReadRepository is a mock of ReadRepository interface.
+type MockReadRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReadRepositoryMockRecorder
+}
+
+// MockReadRepositoryMockRecorder is the mock recorder for MockReadRepository.
+type MockReadRepositoryMockRecorder struct {
+	mock *MockReadRepository
+}
+
+// NewMockReadRepository creates a new mock instance.
+func NewMockReadRepository(ctrl *gomock.Controller) *MockReadRepository {
+	mock := &MockReadRepository{ctrl: ctrl}
+	mock.recorder = &MockReadRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReadRepository) EXPECT() *MockReadRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByTopics mocks base method.
+func (m *MockReadRepository) GetByTopics(ctx context.Context, userID int64, topicIDs []int64) (map[int64]*entity.TopicRead, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByTopics", ctx, userID, topicIDs)
+	ret0, _ := ret[0].(map[int64]*entity.TopicRead)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByTopics indicates an expected call of GetByTopics.
+func (mr *MockReadRepositoryMockRecorder) GetByTopics(ctx, userID, topicIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTopics", reflect.TypeOf((*MockReadRepository)(nil).GetByTopics), ctx, userID, topicIDs)
+}
+
+// MarkRead mocks base method.
+func (m *MockReadRepository) MarkRead(ctx context.Context, userID, topicID int64, messageID *int64) (*entity.TopicRead, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ctx, userID, topicID, messageID)
+	ret0, _ := ret[0].(*entity.TopicRead)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockReadRepositoryMockRecorder) MarkRead(ctx, userID, topicID, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockReadRepository)(nil).MarkRead), ctx, userID, topicID, messageID)
+}
+
+// UnreadByCategory mocks base method.
+func (m *MockReadRepository) UnreadByCategory(ctx context.Context, userID int64) ([]*entity.CategoryUnread, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnreadByCategory", ctx, userID)
+	ret0, _ := ret[0].([]*entity.CategoryUnread)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnreadByCategory indicates an expected call of UnreadByCategory.
+func (mr *MockReadRepositoryMockRecorder) UnreadByCategory(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnreadByCategory", reflect.TypeOf((*MockReadRepository)(nil).UnreadByCategory), ctx, userID)
+}
+
+// UnreadTopics mocks base method.
+func (m *MockReadRepository) UnreadTopics(ctx context.Context, userID int64, limit int) ([]*entity.UnreadTopic, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnreadTopics", ctx, userID, limit)
+	ret0, _ := ret[0].([]*entity.UnreadTopic)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnreadTopics indicates an expected call of UnreadTopics.
+func (mr *MockReadRepositoryMockRecorder) UnreadTopics(ctx, userID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnreadTopics", reflect.TypeOf((*MockReadRepository)(nil).UnreadTopics), ctx, userID, limit)
+}
+
+// MockConversationRepository is a mock of ConversationRepository interface.
+type MockConversationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockConversationRepositoryMockRecorder
+}
+
+// MockConversationRepositoryMockRecorder is the mock recorder for MockConversationRepository.
+type MockConversationRepositoryMockRecorder struct {
+	mock *MockConversationRepository
+}
+
+// NewMockConversationRepository creates a new mock instance.
+func NewMockConversationRepository(ctrl *gomock.Controller) *MockConversationRepository {
+	mock := &MockConversationRepository{ctrl: ctrl}
+	mock.recorder = &MockConversationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConversationRepository) EXPECT() *MockConversationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddMember mocks base method.
+func (m *MockConversationRepository) AddMember(ctx context.Context, conversationID, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMember", ctx, conversationID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMember indicates an expected call of AddMember.
+func (mr *MockConversationRepositoryMockRecorder) AddMember(ctx, conversationID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*MockConversationRepository)(nil).AddMember), ctx, conversationID, userID)
+}
+
+// Block mocks base method.
+func (m *MockConversationRepository) Block(ctx context.Context, userID, blockedID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Block", ctx, userID, blockedID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Block indicates an expected call of Block.
+func (mr *MockConversationRepositoryMockRecorder) Block(ctx, userID, blockedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Block", reflect.TypeOf((*MockConversationRepository)(nil).Block), ctx, userID, blockedID)
+}
+
+// Correspondents mocks base method.
+func (m *MockConversationRepository) Correspondents(ctx context.Context, senderID int64, userIDs []int64) ([]*entity.Correspondent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Correspondents", ctx, senderID, userIDs)
+	ret0, _ := ret[0].([]*entity.Correspondent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Correspondents indicates an expected call of Correspondents.
+func (mr *MockConversationRepositoryMockRecorder) Correspondents(ctx, senderID, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Correspondents", reflect.TypeOf((*MockConversationRepository)(nil).Correspondents), ctx, senderID, userIDs)
+}
+
+// Create mocks base method.
+func (m *MockConversationRepository) Create(ctx context.Context, c *entity.Conversation, memberIDs []int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, c, memberIDs)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockConversationRepositoryMockRecorder) Create(ctx, c, memberIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockConversationRepository)(nil).Create), ctx, c, memberIDs)
+}
+
+// CreateMessage mocks base method.
+func (m_2 *MockConversationRepository) CreateMessage(ctx context.Context, m *entity.PrivateMessage) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "CreateMessage", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateMessage indicates an expected call of CreateMessage.
+func (mr *MockConversationRepositoryMockRecorder) CreateMessage(ctx, m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessage", reflect.TypeOf((*MockConversationRepository)(nil).CreateMessage), ctx, m)
+}
+
+// DeleteMessage mocks base method.
+func (m *MockConversationRepository) DeleteMessage(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessage", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMessage indicates an expected call of DeleteMessage.
+func (mr *MockConversationRepositoryMockRecorder) DeleteMessage(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessage", reflect.TypeOf((*MockConversationRepository)(nil).DeleteMessage), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockConversationRepository) GetByID(ctx context.Context, id int64) (*entity.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockConversationRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockConversationRepository)(nil).GetByID), ctx, id)
+}
+
+// GetMessage mocks base method.
+func (m *MockConversationRepository) GetMessage(ctx context.Context, id int64) (*entity.PrivateMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessage", ctx, id)
+	ret0, _ := ret[0].(*entity.PrivateMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessage indicates an expected call of GetMessage.
+func (mr *MockConversationRepositoryMockRecorder) GetMessage(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessage", reflect.TypeOf((*MockConversationRepository)(nil).GetMessage), ctx, id)
+}
+
+// IsBanned mocks base method.
+func (m *MockConversationRepository) IsBanned(ctx context.Context, userID int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsBanned", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsBanned indicates an expected call of IsBanned.
+func (mr *MockConversationRepositoryMockRecorder) IsBanned(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBanned", reflect.TypeOf((*MockConversationRepository)(nil).IsBanned), ctx, userID)
+}
+
+// ListBlocks mocks base method.
+func (m *MockConversationRepository) ListBlocks(ctx context.Context, userID int64) ([]*entity.UserBlock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBlocks", ctx, userID)
+	ret0, _ := ret[0].([]*entity.UserBlock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBlocks indicates an expected call of ListBlocks.
+func (mr *MockConversationRepositoryMockRecorder) ListBlocks(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBlocks", reflect.TypeOf((*MockConversationRepository)(nil).ListBlocks), ctx, userID)
+}
+
+// ListByUser mocks base method.
+func (m *MockConversationRepository) ListByUser(ctx context.Context, userID int64, limit, offset int) ([]*entity.Conversation, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID, limit, offset)
+	ret0, _ := ret[0].([]*entity.Conversation)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockConversationRepositoryMockRecorder) ListByUser(ctx, userID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockConversationRepository)(nil).ListByUser), ctx, userID, limit, offset)
+}
+
+// ListMessages mocks base method.
+func (m *MockConversationRepository) ListMessages(ctx context.Context, conversationID, beforeID int64, limit int) ([]*entity.PrivateMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMessages", ctx, conversationID, beforeID, limit)
+	ret0, _ := ret[0].([]*entity.PrivateMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMessages indicates an expected call of ListMessages.
+func (mr *MockConversationRepositoryMockRecorder) ListMessages(ctx, conversationID, beforeID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMessages", reflect.TypeOf((*MockConversationRepository)(nil).ListMessages), ctx, conversationID, beforeID, limit)
+}
+
+// MarkRead mocks base method.
+func (m *MockConversationRepository) MarkRead(ctx context.Context, conversationID, userID int64, messageID *int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ctx, conversationID, userID, messageID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockConversationRepositoryMockRecorder) MarkRead(ctx, conversationID, userID, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockConversationRepository)(nil).MarkRead), ctx, conversationID, userID, messageID)
+}
+
+// RemoveMember mocks base method.
+func (m *MockConversationRepository) RemoveMember(ctx context.Context, conversationID, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, conversationID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockConversationRepositoryMockRecorder) RemoveMember(ctx, conversationID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockConversationRepository)(nil).RemoveMember), ctx, conversationID, userID)
+}
+
+// Unblock mocks base method.
+func (m *MockConversationRepository) Unblock(ctx context.Context, userID, blockedID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unblock", ctx, userID, blockedID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unblock indicates an expected call of Unblock.
+func (mr *MockConversationRepositoryMockRecorder) Unblock(ctx, userID, blockedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unblock", reflect.TypeOf((*MockConversationRepository)(nil).Unblock), ctx, userID, blockedID)
+}
+
+// MockRoomRepository is a mock of RoomRepository interface.
+type MockRoomRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoomRepositoryMockRecorder
+}
+
+// MockRoomRepositoryMockRecorder is the mock recorder for MockRoomRepository.
+type MockRoomRepositoryMockRecorder struct {
+	mock *MockRoomRepository
+}
+
+// NewMockRoomRepository creates a new mock instance.
+func NewMockRoomRepository(ctrl *gomock.Controller) *MockRoomRepository {
+	mock := &MockRoomRepository{ctrl: ctrl}
+	mock.recorder = &MockRoomRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoomRepository) EXPECT() *MockRoomRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRoomRepository) Create(ctx context.Context, rm *entity.Room) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, rm)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRoomRepositoryMockRecorder) Create(ctx, rm interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRoomRepository)(nil).Create), ctx, rm)
+}
+
+// CreateMessage mocks base method.
+func (m_2 *MockRoomRepository) CreateMessage(ctx context.Context, m *entity.RoomMessage) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "CreateMessage", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateMessage indicates an expected call of CreateMessage.
+func (mr *MockRoomRepositoryMockRecorder) CreateMessage(ctx, m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessage", reflect.TypeOf((*MockRoomRepository)(nil).CreateMessage), ctx, m)
+}
+
+// Delete mocks base method.
+func (m *MockRoomRepository) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRoomRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRoomRepository)(nil).Delete), ctx, id)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockRoomRepository) DeleteExpired(ctx context.Context, threshold, now time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, threshold, now, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockRoomRepositoryMockRecorder) DeleteExpired(ctx, threshold, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockRoomRepository)(nil).DeleteExpired), ctx, threshold, now, limit)
+}
+
+// DeleteMessage mocks base method.
+func (m *MockRoomRepository) DeleteMessage(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessage", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMessage indicates an expected call of DeleteMessage.
+func (mr *MockRoomRepositoryMockRecorder) DeleteMessage(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessage", reflect.TypeOf((*MockRoomRepository)(nil).DeleteMessage), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockRoomRepository) GetByID(ctx context.Context, id int64) (*entity.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockRoomRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRoomRepository)(nil).GetByID), ctx, id)
+}
+
+// GetBySlug mocks base method.
+func (m *MockRoomRepository) GetBySlug(ctx context.Context, slug string) (*entity.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySlug", ctx, slug)
+	ret0, _ := ret[0].(*entity.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySlug indicates an expected call of GetBySlug.
+func (mr *MockRoomRepositoryMockRecorder) GetBySlug(ctx, slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySlug", reflect.TypeOf((*MockRoomRepository)(nil).GetBySlug), ctx, slug)
+}
+
+// GetMessage mocks base method.
+func (m *MockRoomRepository) GetMessage(ctx context.Context, id int64) (*entity.RoomMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessage", ctx, id)
+	ret0, _ := ret[0].(*entity.RoomMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessage indicates an expected call of GetMessage.
+func (mr *MockRoomRepositoryMockRecorder) GetMessage(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessage", reflect.TypeOf((*MockRoomRepository)(nil).GetMessage), ctx, id)
+}
+
+// IsBanned mocks base method.
+func (m *MockRoomRepository) IsBanned(ctx context.Context, userID int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsBanned", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsBanned indicates an expected call of IsBanned.
+func (mr *MockRoomRepositoryMockRecorder) IsBanned(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBanned", reflect.TypeOf((*MockRoomRepository)(nil).IsBanned), ctx, userID)
+}
+
+// List mocks base method.
+func (m *MockRoomRepository) List(ctx context.Context) ([]*entity.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]*entity.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRoomRepositoryMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRoomRepository)(nil).List), ctx)
+}
+
+// ListMessages mocks base method.
+func (m *MockRoomRepository) ListMessages(ctx context.Context, roomID, beforeID int64, limit int) ([]*entity.RoomMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMessages", ctx, roomID, beforeID, limit)
+	ret0, _ := ret[0].([]*entity.RoomMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMessages indicates an expected call of ListMessages.
+func (mr *MockRoomRepositoryMockRecorder) ListMessages(ctx, roomID, beforeID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMessages", reflect.TypeOf((*MockRoomRepository)(nil).ListMessages), ctx, roomID, beforeID, limit)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockRoomRepository) PurgeDeleted(ctx context.Context, threshold time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", ctx, threshold)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockRoomRepositoryMockRecorder) PurgeDeleted(ctx, threshold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockRoomRepository)(nil).PurgeDeleted), ctx, threshold)
+}
+
+// Update mocks base method.
+func (m *MockRoomRepository) Update(ctx context.Context, rm *entity.Room) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, rm)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRoomRepositoryMockRecorder) Update(ctx, rm interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRoomRepository)(nil).Update), ctx, rm)
+}
+
+// UpdateMessage mocks base method.
+func (m *MockRoomRepository) UpdateMessage(ctx context.Context, id int64, content string, updatedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMessage", ctx, id, content, updatedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMessage indicates an expected call of UpdateMessage.
+func (mr *MockRoomRepositoryMockRecorder) UpdateMessage(ctx, id, content, updatedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessage", reflect.TypeOf((*MockRoomRepository)(nil).UpdateMessage), ctx, id, content, updatedAt)
+}
+
+// UserNames mocks base method.
+func (m *MockRoomRepository) UserNames(ctx context.Context, ids []int64) (map[int64]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserNames", ctx, ids)
+	ret0, _ := ret[0].(map[int64]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserNames indicates an expected call of UserNames.
+func (mr *MockRoomRepositoryMockRecorder) UserNames(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserNames", reflect.TypeOf((*MockRoomRepository)(nil).UserNames), ctx, ids)
+}
+
+// MockAttachmentRepository is a mock of AttachmentRepository interface.
+type MockAttachmentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentRepositoryMockRecorder
+}
+
+// MockAttachmentRepositoryMockRecorder is the mock recorder for MockAttachmentRepository.
+type MockAttachmentRepositoryMockRecorder struct {
+	mock *MockAttachmentRepository
+}
+
+// NewMockAttachmentRepository creates a new mock instance.
+func NewMockAttachmentRepository(ctrl *gomock.Controller) *MockAttachmentRepository {
+	mock := &MockAttachmentRepository{ctrl: ctrl}
+	mock.recorder = &MockAttachmentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachmentRepository) EXPECT() *MockAttachmentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountPending mocks base method.
+func (m *MockAttachmentRepository) CountPending(ctx context.Context, uploaderID int64) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountPending", ctx, uploaderID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountPending indicates an expected call of CountPending.
+func (mr *MockAttachmentRepositoryMockRecorder) CountPending(ctx, uploaderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPending", reflect.TypeOf((*MockAttachmentRepository)(nil).CountPending), ctx, uploaderID)
+}
+
+// Create mocks base method.
+func (m *MockAttachmentRepository) Create(ctx context.Context, a *entity.Attachment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, a)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAttachmentRepositoryMockRecorder) Create(ctx, a interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAttachmentRepository)(nil).Create), ctx, a)
+}
+
+// Delete mocks base method.
+func (m *MockAttachmentRepository) Delete(ctx context.Context, ids []int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, ids)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAttachmentRepositoryMockRecorder) Delete(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAttachmentRepository)(nil).Delete), ctx, ids)
+}
+
+// GetByID mocks base method.
+func (m *MockAttachmentRepository) GetByID(ctx context.Context, id int64) (*entity.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockAttachmentRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAttachmentRepository)(nil).GetByID), ctx, id)
+}
+
+// ListByIDs mocks base method.
+func (m *MockAttachmentRepository) ListByIDs(ctx context.Context, ids []int64) ([]*entity.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByIDs", ctx, ids)
+	ret0, _ := ret[0].([]*entity.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByIDs indicates an expected call of ListByIDs.
+func (mr *MockAttachmentRepositoryMockRecorder) ListByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByIDs", reflect.TypeOf((*MockAttachmentRepository)(nil).ListByIDs), ctx, ids)
+}
+
+// ListByMessages mocks base method.
+func (m *MockAttachmentRepository) ListByMessages(ctx context.Context, messageIDs []int64) ([]*entity.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByMessages", ctx, messageIDs)
+	ret0, _ := ret[0].([]*entity.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByMessages indicates an expected call of ListByMessages.
+func (mr *MockAttachmentRepositoryMockRecorder) ListByMessages(ctx, messageIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByMessages", reflect.TypeOf((*MockAttachmentRepository)(nil).ListByMessages), ctx, messageIDs)
+}
+
+// ListOrphans mocks base method.
+func (m *MockAttachmentRepository) ListOrphans(ctx context.Context, before time.Time, limit int) ([]*entity.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrphans", ctx, before, limit)
+	ret0, _ := ret[0].([]*entity.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrphans indicates an expected call of ListOrphans.
+func (mr *MockAttachmentRepositoryMockRecorder) ListOrphans(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrphans", reflect.TypeOf((*MockAttachmentRepository)(nil).ListOrphans), ctx, before, limit)
+}
+
+// MockProfileRepository is a mock of ProfileRepository interface.
+type MockProfileRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockProfileRepositoryMockRecorder
+}
+
+// MockProfileRepositoryMockRecorder is the mock recorder for MockProfileRepository.
+type MockProfileRepositoryMockRecorder struct {
+	mock *MockProfileRepository
+}
+
+// NewMockProfileRepository creates a new mock instance.
+func NewMockProfileRepository(ctrl *gomock.Controller) *MockProfileRepository {
+	mock := &MockProfileRepository{ctrl: ctrl}
+	mock.recorder = &MockProfileRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProfileRepository) EXPECT() *MockProfileRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockProfileRepository) Get(ctx context.Context, userID int64) (*entity.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID)
+	ret0, _ := ret[0].(*entity.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockProfileRepositoryMockRecorder) Get(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockProfileRepository)(nil).Get), ctx, userID)
+}
+
+// RecentActivity mocks base method.
+func (m *MockProfileRepository) RecentActivity(ctx context.Context, userID int64, limit int) ([]*entity.ProfileActivity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecentActivity", ctx, userID, limit)
+	ret0, _ := ret[0].([]*entity.ProfileActivity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecentActivity indicates an expected call of RecentActivity.
+func (mr *MockProfileRepositoryMockRecorder) RecentActivity(ctx, userID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecentActivity", reflect.TypeOf((*MockProfileRepository)(nil).RecentActivity), ctx, userID, limit)
+}
+
+// Save mocks base method.
+func (m *MockProfileRepository) Save(ctx context.Context, p *entity.Profile) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockProfileRepositoryMockRecorder) Save(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockProfileRepository)(nil).Save), ctx, p)
+}
+
+// SetAvatar mocks base method.
+func (m *MockProfileRepository) SetAvatar(ctx context.Context, userID int64, key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAvatar", ctx, userID, key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetAvatar indicates an expected call of SetAvatar.
+func (mr *MockProfileRepositoryMockRecorder) SetAvatar(ctx, userID, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAvatar", reflect.TypeOf((*MockProfileRepository)(nil).SetAvatar), ctx, userID, key)
+}
+
+// Stats mocks base method.
+func (m *MockProfileRepository) Stats(ctx context.Context, userID int64) (*entity.ProfileStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", ctx, userID)
+	ret0, _ := ret[0].(*entity.ProfileStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockProfileRepositoryMockRecorder) Stats(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockProfileRepository)(nil).Stats), ctx, userID)
+}
+
+// UserIDByUsername mocks base method.
+func (m *MockProfileRepository) UserIDByUsername(ctx context.Context, username string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserIDByUsername", ctx, username)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserIDByUsername indicates an expected call of UserIDByUsername.
+func (mr *MockProfileRepositoryMockRecorder) UserIDByUsername(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserIDByUsername", reflect.TypeOf((*MockProfileRepository)(nil).UserIDByUsername), ctx, username)
+}
+
+// MockVoteRepository is a mock of VoteRepository interface.
+type MockVoteRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockVoteRepositoryMockRecorder
+}
+
+// MockVoteRepositoryMockRecorder is the mock recorder for MockVoteRepository.
+type MockVoteRepositoryMockRecorder struct {
+	mock *MockVoteRepository
+}
+
+// NewMockVoteRepository creates a new mock instance.
+func NewMockVoteRepository(ctrl *gomock.Controller) *MockVoteRepository {
+	mock := &MockVoteRepository{ctrl: ctrl}
+	mock.recorder = &MockVoteRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVoteRepository) EXPECT() *MockVoteRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ByUser mocks base method.
+func (m *MockVoteRepository) ByUser(ctx context.Context, userID int64, messageIDs []int64) (map[int64]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ByUser", ctx, userID, messageIDs)
+	ret0, _ := ret[0].(map[int64]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ByUser indicates an expected call of ByUser.
+func (mr *MockVoteRepositoryMockRecorder) ByUser(ctx, userID, messageIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ByUser", reflect.TypeOf((*MockVoteRepository)(nil).ByUser), ctx, userID, messageIDs)
+}
+
+// Reputation mocks base method.
+func (m *MockVoteRepository) Reputation(ctx context.Context, userID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reputation", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reputation indicates an expected call of Reputation.
+func (mr *MockVoteRepositoryMockRecorder) Reputation(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reputation", reflect.TypeOf((*MockVoteRepository)(nil).Reputation), ctx, userID)
+}
+
+// Vote mocks base method.
+func (m *MockVoteRepository) Vote(ctx context.Context, messageID, userID int64, value int) (*entity.MessageVote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Vote", ctx, messageID, userID, value)
+	ret0, _ := ret[0].(*entity.MessageVote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Vote indicates an expected call of Vote.
+func (mr *MockVoteRepositoryMockRecorder) Vote(ctx, messageID, userID, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Vote", reflect.TypeOf((*MockVoteRepository)(nil).Vote), ctx, messageID, userID, value)
+}
+
+// MockAuthWebAPI is a mock of AuthWebAPI interface.
+type MockAuthWebAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthWebAPIMockRecorder
+}
+
+// MockAuthWebAPIMockRecorder is the mock recorder for MockAuthWebAPI.
+type MockAuthWebAPIMockRecorder struct {
+	mock *MockAuthWebAPI
+}
+
+// NewMockAuthWebAPI creates a new mock instance.
+func NewMockAuthWebAPI(ctrl *gomock.Controller) *MockAuthWebAPI {
+	mock := &MockAuthWebAPI{ctrl: ctrl}
+	mock.recorder = &MockAuthWebAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthWebAPI) EXPECT() *MockAuthWebAPIMockRecorder {
+	return m.recorder
+}
+
+// BlockUser mocks base method.
+func (m *MockAuthWebAPI) BlockUser(ctx context.Context, userID int64, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUser", ctx, userID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockUser indicates an expected call of BlockUser.
+func (mr *MockAuthWebAPIMockRecorder) BlockUser(ctx, userID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUser", reflect.TypeOf((*MockAuthWebAPI)(nil).BlockUser), ctx, userID, reason)
+}
+
+// ResolveUsernames mocks base method.
+func (m *MockAuthWebAPI) ResolveUsernames(ctx context.Context, names []string) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveUsernames", ctx, names)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveUsernames indicates an expected call of ResolveUsernames.
+func (mr *MockAuthWebAPIMockRecorder) ResolveUsernames(ctx, names interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveUsernames", reflect.TypeOf((*MockAuthWebAPI)(nil).ResolveUsernames), ctx, names)
+}
@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: C:/Users/user/GolandProjects/forum/services/chat-service/internal/usecase/reputation.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPrivilegeChecker is a mock of PrivilegeChecker interface.
+type MockPrivilegeChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockPrivilegeCheckerMockRecorder
+}
+
+// MockPrivilegeCheckerMockRecorder is the mock recorder for MockPrivilegeChecker.
+type MockPrivilegeCheckerMockRecorder struct {
+	mock *MockPrivilegeChecker
+}
+
+// NewMockPrivilegeChecker creates a new mock instance.
+func NewMockPrivilegeChecker(ctrl *gomock.Controller) *MockPrivilegeChecker {
+	mock := &MockPrivilegeChecker{ctrl: ctrl}
+	mock.recorder = &MockPrivilegeCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPrivilegeChecker) EXPECT() *MockPrivilegeCheckerMockRecorder {
+	return m.recorder
+}
+
+// Require mocks base method.
+func (m *MockPrivilegeChecker) Require(ctx context.Context, privilege string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Require", ctx, privilege)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Require indicates an expected call of Require.
+func (mr *MockPrivilegeCheckerMockRecorder) Require(ctx, privilege interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Require", reflect.TypeOf((*MockPrivilegeChecker)(nil).Require), ctx, privilege)
+}
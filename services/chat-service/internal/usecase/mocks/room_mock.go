@@ -0,0 +1,62 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: C:/Users/user/GolandProjects/forum/services/chat-service/internal/usecase/room.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entity "chat-service/internal/entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRoomPublisher is a mock of RoomPublisher interface.
+type MockRoomPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoomPublisherMockRecorder
+}
+
+// MockRoomPublisherMockRecorder is the mock recorder for MockRoomPublisher.
+type MockRoomPublisherMockRecorder struct {
+	mock *MockRoomPublisher
+}
+
+// NewMockRoomPublisher creates a new mock instance.
+func NewMockRoomPublisher(ctrl *gomock.Controller) *MockRoomPublisher {
+	mock := &MockRoomPublisher{ctrl: ctrl}
+	mock.recorder = &MockRoomPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoomPublisher) EXPECT() *MockRoomPublisherMockRecorder {
+	return m.recorder
+}
+
+// PublishToRoom mocks base method.
+func (m *MockRoomPublisher) PublishToRoom(roomID int64, ev *entity.WSEvent) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PublishToRoom", roomID, ev)
+}
+
+// PublishToRoom indicates an expected call of PublishToRoom.
+func (mr *MockRoomPublisherMockRecorder) PublishToRoom(roomID, ev interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishToRoom", reflect.TypeOf((*MockRoomPublisher)(nil).PublishToRoom), roomID, ev)
+}
+
+// RoomViewers mocks base method.
+func (m *MockRoomPublisher) RoomViewers(roomID int64) ([]int64, int) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RoomViewers", roomID)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(int)
+	return ret0, ret1
+}
+
+// RoomViewers indicates an expected call of RoomViewers.
+func (mr *MockRoomPublisherMockRecorder) RoomViewers(roomID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RoomViewers", reflect.TypeOf((*MockRoomPublisher)(nil).RoomViewers), roomID)
+}
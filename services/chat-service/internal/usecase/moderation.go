@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	"chat-service/internal/repo"
+	"context"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/logger"
+)
+
+// Границы страницы журнала модерации
+const (
+	defaultModerationLimit = 50
+	maxModerationLimit     = 200
+)
+
+// modLog пишет действия модераторов в moderation_log. Сбой записи не отменяет само действие — он логируется.
+// Если у объекта есть автор, он получает уведомление (notify может быть nil).
+type modLog struct {
+	repo   repo.ModerationRepository
+	notify Notifier
+	log    logger.Interface
+}
+
+// record сохраняет запись; инициатор берётся из контекста
+func (l modLog) record(ctx context.Context, e *entity.ModerationEntry) {
+	if l.repo == nil {
+		return
+	}
+	if userID, _ := auth.FromContext(ctx); userID != 0 {
+		e.ActorID = &userID
+	}
+	if err := l.repo.Save(ctx, e); err != nil {
+		l.log.Error("moderation entry not saved", "action", e.Action, "target_id", e.TargetID, "err", err)
+	}
+
+	if l.notify == nil || e.TargetAuthorID == nil || (e.ActorID != nil && *e.ActorID == *e.TargetAuthorID) {
+		return
+	}
+	// кто из модераторов действовал, автору не сообщается — как и в его журнале
+	data := map[string]any{"action": e.Action, "target_type": e.TargetType, "target_id": e.TargetID}
+	if e.Reason != "" {
+		data["reason"] = e.Reason
+	}
+	if until, ok := e.Details["until"]; ok {
+		data["until"] = until
+	}
+	l.notify.Notify(ctx, &entity.Notification{UserID: *e.TargetAuthorID, Type: entity.NotifyModeration, Data: data})
+}
+
+type ModerationUC struct {
+	repo   repo.ModerationRepository
+	access access // журнал целиком — только глобальное право, модераторы не нужны
+	log    logger.Interface
+}
+
+func NewModerationUsecase(r repo.ModerationRepository, l logger.Interface) *ModerationUC {
+	return &ModerationUC{repo: r, log: l}
+}
+
+// ListModerationLog возвращает страницу журнала модерации по фильтру (только admin)
+func (uc *ModerationUC) ListModerationLog(ctx context.Context, f entity.ModerationFilter) ([]*entity.ModerationEntry, int64, error) {
+	uc.log.Debug("ListModerationLog called", "actor_id", f.ActorID, "action", f.Action)
+
+	if _, err := uc.access.check(ctx, auth.PermModerationLogView, nil); err != nil {
+		uc.log.Warn("list moderation log denied", "err", err)
+		return nil, 0, err
+	}
+
+	list, total, err := uc.repo.List(ctx, pageModeration(f))
+	if err != nil {
+		uc.log.Error("repo.List failed", "err", err)
+		return nil, 0, fmt.Errorf("ModerationUC.List: %w", err)
+	}
+
+	uc.log.Info("moderation log retrieved", "count", len(list), "total", total)
+	return list, total, nil
+}
+
+// ListMyModerationLog возвращает действия модераторов над контентом текущего пользователя.
+// Кто именно из модераторов действовал, автору не показывается.
+func (uc *ModerationUC) ListMyModerationLog(ctx context.Context, limit, offset int) ([]*entity.ModerationEntry, int64, error) {
+	uc.log.Debug("ListMyModerationLog called")
+
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 {
+		uc.log.Warn("unauthenticated user tried to read own moderation log")
+		return nil, 0, ErrUnauthenticated
+	}
+
+	f := pageModeration(entity.ModerationFilter{TargetAuthorID: userID, Limit: limit, Offset: offset})
+	list, total, err := uc.repo.List(ctx, f)
+	if err != nil {
+		uc.log.Error("repo.List failed", "err", err)
+		return nil, 0, fmt.Errorf("ModerationUC.ListMine: %w", err)
+	}
+	for _, e := range list {
+		e.ActorID = nil
+	}
+
+	uc.log.Info("own moderation log retrieved", "user_id", userID, "count", len(list))
+	return list, total, nil
+}
+
+// pageModeration приводит limit/offset к допустимым границам
+func pageModeration(f entity.ModerationFilter) entity.ModerationFilter {
+	if f.Limit <= 0 {
+		f.Limit = defaultModerationLimit
+	} else if f.Limit > maxModerationLimit {
+		f.Limit = maxModerationLimit
+	}
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+	return f
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	"chat-service/internal/usecase/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestModerationUC_ListModerationLog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockModerationRepository(ctrl)
+	uc := NewModerationUsecase(repo, mocks.FakeLogger{})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, _, err := uc.ListModerationLog(context.Background(), entity.ModerationFilter{})
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	t.Run("moderator is forbidden", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 3, "moderator")
+		_, _, err := uc.ListModerationLog(ctx, entity.ModerationFilter{})
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("admin, page is clamped", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "admin")
+		want := entity.ModerationFilter{ActorID: 3, Limit: maxModerationLimit}
+		entries := []*entity.ModerationEntry{{ID: 1, Action: entity.ModDeleteMessage}}
+		repo.EXPECT().List(ctx, want).Return(entries, int64(1), nil)
+
+		list, total, err := uc.ListModerationLog(ctx, entity.ModerationFilter{ActorID: 3, Limit: 1000, Offset: -5})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), total)
+		require.Equal(t, entries, list)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "admin")
+		repo.EXPECT().List(ctx, gomock.Any()).Return(nil, int64(0), errors.New("fail"))
+		_, _, err := uc.ListModerationLog(ctx, entity.ModerationFilter{})
+		require.ErrorContains(t, err, "ModerationUC.List")
+	})
+}
+
+func TestModerationUC_ListMyModerationLog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockModerationRepository(ctrl)
+	uc := NewModerationUsecase(repo, mocks.FakeLogger{})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, _, err := uc.ListMyModerationLog(context.Background(), 0, 0)
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	t.Run("own entries without actor", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 42, "user")
+		actor := int64(3)
+		want := entity.ModerationFilter{TargetAuthorID: 42, Limit: defaultModerationLimit}
+		repo.EXPECT().List(ctx, want).Return([]*entity.ModerationEntry{{ID: 7, ActorID: &actor, Reason: "spam"}}, int64(1), nil)
+
+		list, total, err := uc.ListMyModerationLog(ctx, 0, 0)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), total)
+		require.Len(t, list, 1)
+		require.Nil(t, list[0].ActorID)
+		require.Equal(t, "spam", list[0].Reason)
+	})
+}
+
+func TestMessageUC_DeleteMessage_ModerationLog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockMessageRepository(ctrl)
+	topics := mocks.NewMockTopicRepository(ctrl)
+	mods := mocks.NewMockModeratorRepository(ctrl)
+	modlog := mocks.NewMockModerationRepository(ctrl)
+	publisher := mocks.NewMockMessagePublisher(ctrl)
+	uc := NewMessageUsecase(repo, topics, mods, nil, modlog, MessageDeps{Publisher: publisher}, mocks.FakeLogger{}, retention)
+
+	t.Run("moderator delete is logged with snapshot and reason", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 3, "moderator")
+		repo.EXPECT().GetByID(ctx, int64(9)).Return(&entity.Message{ID: 9, TopicID: 10, AuthorID: 42, Content: "buy now"}, nil)
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(&entity.Topic{ID: 10, CategoryID: 5}, nil)
+		mods.EXPECT().IsModerator(ctx, int64(3), int64(5)).Return(true, nil)
+		repo.EXPECT().Delete(ctx, int64(9), int64(3), "spam").Return(nil)
+		publisher.EXPECT().Publish(int64(10), gomock.Any())
+		modlog.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e *entity.ModerationEntry) error {
+			require.Equal(t, entity.ModDeleteMessage, e.Action)
+			require.Equal(t, entity.TargetMessage, e.TargetType)
+			require.Equal(t, int64(9), e.TargetID)
+			require.Equal(t, int64(3), *e.ActorID)
+			require.Equal(t, int64(42), *e.TargetAuthorID)
+			require.Equal(t, "spam", e.Reason)
+			require.Equal(t, "buy now", e.Snapshot["content"])
+			return nil
+		})
+
+		require.NoError(t, uc.DeleteMessage(ctx, 9, "spam"))
+	})
+
+	t.Run("log failure does not fail delete", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "admin")
+		repo.EXPECT().GetByID(ctx, int64(9)).Return(&entity.Message{ID: 9, TopicID: 10, AuthorID: 42}, nil)
+		repo.EXPECT().Delete(ctx, int64(9), int64(1), "").Return(nil)
+		publisher.EXPECT().Publish(int64(10), gomock.Any())
+		modlog.EXPECT().Save(ctx, gomock.Any()).Return(errors.New("fail"))
+
+		require.NoError(t, uc.DeleteMessage(ctx, 9, ""))
+	})
+
+	t.Run("own message is not logged", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 42, "user")
+		repo.EXPECT().GetByID(ctx, int64(9)).Return(&entity.Message{ID: 9, TopicID: 10, AuthorID: 42}, nil)
+		repo.EXPECT().Delete(ctx, int64(9), int64(42), "").Return(nil)
+		publisher.EXPECT().Publish(int64(10), gomock.Any())
+		modlog.EXPECT().Save(gomock.Any(), gomock.Any()).Times(0)
+
+		require.NoError(t, uc.DeleteMessage(ctx, 9, ""))
+	})
+}
+
+func TestTopicUC_DeleteTopic_ModerationLog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockTopicRepository(ctrl)
+	modlog := mocks.NewMockModerationRepository(ctrl)
+	uc := NewTopicUsecase(repo, nil, nil, modlog, nil, nil, nil, nil, mocks.FakeLogger{}, retention)
+
+	ctx := auth.WithUser(context.Background(), 1, "admin")
+	topic := &entity.Topic{ID: 5, AuthorID: 42, CategoryID: 2, Title: "t"}
+	repo.EXPECT().GetByID(ctx, int64(5)).Return(topic, nil)
+	repo.EXPECT().Delete(ctx, int64(5), int64(1), "rules").Return(nil)
+	modlog.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e *entity.ModerationEntry) error {
+		require.Equal(t, entity.ModDeleteTopic, e.Action)
+		require.Equal(t, int64(42), *e.TargetAuthorID)
+		require.Equal(t, "rules", e.Reason)
+		require.Equal(t, "t", e.Snapshot["title"])
+		return nil
+	})
+
+	require.NoError(t, uc.DeleteTopic(ctx, 5, "rules"))
+}
@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	repoErr "chat-service/internal/errors"
+	"chat-service/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/logger"
+	"time"
+)
+
+var (
+	ErrMuted         = errors.New("user is muted")
+	ErrMuteNotFound  = errors.New("mute not found")
+	ErrInvalidMute   = errors.New("invalid mute: set user and exactly one of topic or category; duration must not be negative")
+	ErrMuteReference = errors.New("user, topic or category does not exist")
+)
+
+// MutedError — пользователю запрещено писать здесь; errors.Is(err, ErrMuted) == true.
+// Текст ошибки содержит срок окончания мьюта, чтобы клиент мог показать его как есть.
+type MutedError struct {
+	Mute *entity.Mute
+}
+
+func (e *MutedError) Error() string {
+	scope := "category"
+	if e.Mute.TopicID != nil {
+		scope = "topic"
+	}
+	if e.Mute.ExpiresAt == nil {
+		return fmt.Sprintf("you are muted in this %s permanently", scope)
+	}
+	return fmt.Sprintf("you are muted in this %s until %s", scope, e.Mute.ExpiresAt.UTC().Format(time.RFC3339))
+}
+
+func (e *MutedError) Unwrap() error { return ErrMuted }
+
+// muteGuard проверяет, не запрещено ли пользователю писать в топике или категории.
+// Без репозитория (в тестах, где мьюты не важны) ничего не проверяет.
+type muteGuard struct {
+	repo repo.MuteRepository
+}
+
+// check возвращает *MutedError, если действует мьют на топик topicID (0 — только категория)
+// или на категорию categoryID и её родителей.
+func (g muteGuard) check(ctx context.Context, userID, topicID, categoryID int64) error {
+	if g.repo == nil {
+		return nil
+	}
+	m, err := g.repo.FindActive(ctx, userID, topicID, categoryID)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("muteGuard.check: %w", err)
+	}
+	return &MutedError{Mute: m}
+}
+
+type MuteUC struct {
+	repo   repo.MuteRepository
+	topics repo.TopicRepository
+	access access
+	modlog modLog
+	log    logger.Interface
+}
+
+func NewMuteUsecase(r repo.MuteRepository, tr repo.TopicRepository, mods repo.ModeratorRepository, ml repo.ModerationRepository, n Notifier, l logger.Interface) *MuteUC {
+	return &MuteUC{repo: r, topics: tr, access: access{mods: mods}, modlog: modLog{repo: ml, notify: n, log: l}, log: l}
+}
+
+// scope — категория, в которой действует мьют: модератор может мьютить только у себя
+func (uc *MuteUC) scope(topicID, categoryID int64) categoryFunc {
+	if topicID != 0 {
+		return topicCategory(uc.topics, topicID)
+	}
+	return inCategory(categoryID)
+}
+
+// Mute запрещает пользователю писать в топике или категории (с подкатегориями) на p.Duration или бессрочно
+func (uc *MuteUC) Mute(ctx context.Context, p MuteParams) (*entity.Mute, error) {
+	uc.log.Debug("Mute called", "user_id", p.UserID, "topic_id", p.TopicID, "category_id", p.CategoryID)
+
+	if p.UserID <= 0 || (p.TopicID == 0) == (p.CategoryID == 0) || p.Duration < 0 {
+		return nil, ErrInvalidMute
+	}
+
+	actorID, err := uc.access.check(ctx, auth.PermUserMute, uc.scope(p.TopicID, p.CategoryID))
+	if err != nil {
+		uc.log.Warn("mute denied", "user_id", p.UserID, "err", err)
+		return nil, err
+	}
+
+	m := &entity.Mute{UserID: p.UserID, Reason: p.Reason, MutedBy: &actorID}
+	if p.TopicID != 0 {
+		m.TopicID = &p.TopicID
+	} else {
+		m.CategoryID = &p.CategoryID
+	}
+	if p.Duration > 0 {
+		until := time.Now().UTC().Add(p.Duration)
+		m.ExpiresAt = &until
+	}
+
+	err = uc.repo.Create(ctx, m)
+	if errors.Is(err, repoErr.ErrInvalidReference) {
+		uc.log.Info("mute references missing object", "user_id", p.UserID)
+		return nil, ErrMuteReference
+	} else if err != nil {
+		uc.log.Error("repo.Create failed", "err", err)
+		return nil, fmt.Errorf("MuteUC.Mute: %w", err)
+	}
+
+	details := map[string]any{"mute_id": m.ID}
+	if m.TopicID != nil {
+		details["topic_id"] = *m.TopicID
+	} else {
+		details["category_id"] = *m.CategoryID
+	}
+	if m.ExpiresAt != nil {
+		details["until"] = m.ExpiresAt.Format(time.RFC3339)
+	}
+	uc.modlog.record(ctx, &entity.ModerationEntry{
+		Action:         entity.ModMuteUser,
+		TargetType:     entity.TargetUser,
+		TargetID:       p.UserID,
+		TargetAuthorID: &m.UserID,
+		Reason:         p.Reason,
+		Details:        details,
+	})
+
+	uc.log.Info("user muted", "id", m.ID, "user_id", p.UserID, "until", m.ExpiresAt)
+	return m, nil
+}
+
+// Unmute снимает мьют досрочно; модератор — только в своих категориях
+func (uc *MuteUC) Unmute(ctx context.Context, id int64) error {
+	uc.log.Debug("Unmute called", "id", id)
+
+	if userID, _ := auth.FromContext(ctx); userID == 0 {
+		return ErrUnauthenticated
+	}
+
+	m, err := uc.repo.GetByID(ctx, id)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		uc.log.Info("mute not found", "id", id)
+		return ErrMuteNotFound
+	} else if err != nil {
+		uc.log.Error("repo.GetByID failed", "err", err)
+		return fmt.Errorf("MuteUC.Unmute#get: %w", err)
+	}
+
+	var topicID, categoryID int64
+	if m.TopicID != nil {
+		topicID = *m.TopicID
+	} else if m.CategoryID != nil {
+		categoryID = *m.CategoryID
+	}
+	if _, err := uc.access.check(ctx, auth.PermUserMute, uc.scope(topicID, categoryID)); err != nil {
+		uc.log.Warn("unmute denied", "id", id, "err", err)
+		return err
+	}
+
+	err = uc.repo.Delete(ctx, id)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		return ErrMuteNotFound
+	} else if err != nil {
+		uc.log.Error("repo.Delete failed", "err", err)
+		return fmt.Errorf("MuteUC.Unmute: %w", err)
+	}
+
+	uc.modlog.record(ctx, &entity.ModerationEntry{
+		Action:         entity.ModUnmuteUser,
+		TargetType:     entity.TargetUser,
+		TargetID:       m.UserID,
+		TargetAuthorID: &m.UserID,
+		Details:        map[string]any{"mute_id": id},
+	})
+
+	uc.log.Info("user unmuted", "id", id, "user_id", m.UserID)
+	return nil
+}
+
+// ListMutes возвращает действующие мьюты пользователя (userID = 0 — всех). Только admin:
+// модератору с областью категории без фильтра по категории список не отдаётся.
+func (uc *MuteUC) ListMutes(ctx context.Context, userID int64) ([]*entity.Mute, error) {
+	uc.log.Debug("ListMutes called", "user_id", userID)
+
+	if _, err := uc.access.check(ctx, auth.PermUserMute, nil); err != nil {
+		uc.log.Warn("list mutes denied", "err", err)
+		return nil, err
+	}
+
+	list, err := uc.repo.ListActive(ctx, userID)
+	if err != nil {
+		uc.log.Error("repo.ListActive failed", "err", err)
+		return nil, fmt.Errorf("MuteUC.List: %w", err)
+	}
+	return list, nil
+}
+
+// ListMyMutes возвращает действующие мьюты текущего пользователя
+func (uc *MuteUC) ListMyMutes(ctx context.Context) ([]*entity.Mute, error) {
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 {
+		return nil, ErrUnauthenticated
+	}
+
+	list, err := uc.repo.ListActive(ctx, userID)
+	if err != nil {
+		uc.log.Error("repo.ListActive failed", "err", err)
+		return nil, fmt.Errorf("MuteUC.ListMine: %w", err)
+	}
+	for _, m := range list {
+		m.MutedBy = nil
+	}
+	return list, nil
+}
+
+// PurgeExpiredMutes удаляет истёкшие мьюты (вызывается из cron). На проверку прав они
+// не влияют и без этого — чистка только освобождает таблицу.
+func (uc *MuteUC) PurgeExpiredMutes(ctx context.Context, before time.Time) error {
+	n, err := uc.repo.DeleteExpired(ctx, before)
+	if err != nil {
+		uc.log.Error("repo.DeleteExpired failed", "err", err)
+		return fmt.Errorf("MuteUC.PurgeExpired: %w", err)
+	}
+	uc.log.Info("expired mutes purged", "count", n)
+	return nil
+}
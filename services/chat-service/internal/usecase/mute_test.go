@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	customErr "chat-service/internal/errors"
+	"chat-service/internal/usecase/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestMessageUC_SendMessage_Muted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockMessageRepository(ctrl)
+	topics := mocks.NewMockTopicRepository(ctrl)
+	mutes := mocks.NewMockMuteRepository(ctrl)
+	publisher := mocks.NewMockMessagePublisher(ctrl)
+	uc := NewMessageUsecase(repo, topics, nil, mutes, nil, MessageDeps{Publisher: publisher}, mocks.FakeLogger{}, retention)
+
+	ctx := auth.WithUser(context.Background(), 1, "user")
+	params := SendMessageParams{TopicID: 10, AuthorID: 1, Content: "hi"}
+
+	t.Run("muted until", func(t *testing.T) {
+		until := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+		categoryID := int64(4)
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(&entity.Topic{ID: 10, CategoryID: 4}, nil)
+		mutes.EXPECT().FindActive(ctx, int64(1), int64(10), int64(4)).
+			Return(&entity.Mute{ID: 3, UserID: 1, CategoryID: &categoryID, ExpiresAt: &until}, nil)
+
+		_, err := uc.SendMessage(ctx, params)
+		require.ErrorIs(t, err, ErrMuted)
+		var muted *MutedError
+		require.True(t, errors.As(err, &muted))
+		require.Equal(t, &until, muted.Mute.ExpiresAt)
+		require.Contains(t, err.Error(), "until 2030-01-02T03:04:05Z")
+		require.Contains(t, err.Error(), "category")
+	})
+
+	t.Run("muted permanently in topic", func(t *testing.T) {
+		topicID := int64(10)
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(&entity.Topic{ID: 10, CategoryID: 4}, nil)
+		mutes.EXPECT().FindActive(ctx, int64(1), int64(10), int64(4)).
+			Return(&entity.Mute{ID: 3, UserID: 1, TopicID: &topicID}, nil)
+
+		_, err := uc.SendMessage(ctx, params)
+		require.ErrorIs(t, err, ErrMuted)
+		require.Contains(t, err.Error(), "topic permanently")
+	})
+
+	t.Run("not muted", func(t *testing.T) {
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(&entity.Topic{ID: 10, CategoryID: 4}, nil)
+		mutes.EXPECT().FindActive(ctx, int64(1), int64(10), int64(4)).Return(nil, customErr.ErrNotFound)
+		repo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		publisher.EXPECT().Publish(int64(10), gomock.Any())
+
+		_, err := uc.SendMessage(ctx, params)
+		require.NoError(t, err)
+	})
+
+	t.Run("mute check error", func(t *testing.T) {
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(&entity.Topic{ID: 10, CategoryID: 4}, nil)
+		mutes.EXPECT().FindActive(ctx, int64(1), int64(10), int64(4)).Return(nil, errors.New("db down"))
+
+		_, err := uc.SendMessage(ctx, params)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrMuted)
+	})
+}
+
+func TestTopicUC_CreateTopic_Muted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockTopicRepository(ctrl)
+	mutes := mocks.NewMockMuteRepository(ctrl)
+	uc := NewTopicUsecase(repo, nil, mutes, nil, nil, nil, nil, nil, mocks.FakeLogger{}, retention)
+
+	ctx := auth.WithUser(context.Background(), 1, "user")
+	params := TopicParams{CategoryID: 4, Title: "t", AuthorID: 1}
+
+	t.Run("muted", func(t *testing.T) {
+		until := time.Now().Add(time.Hour)
+		categoryID := int64(2)
+		mutes.EXPECT().FindActive(ctx, int64(1), int64(0), int64(4)).
+			Return(&entity.Mute{UserID: 1, CategoryID: &categoryID, ExpiresAt: &until}, nil)
+
+		_, err := uc.CreateTopic(ctx, params)
+		require.ErrorIs(t, err, ErrMuted)
+	})
+
+	t.Run("not muted", func(t *testing.T) {
+		mutes.EXPECT().FindActive(ctx, int64(1), int64(0), int64(4)).Return(nil, customErr.ErrNotFound)
+		repo.EXPECT().Create(ctx, gomock.Any()).Return(int64(7), nil)
+
+		id, err := uc.CreateTopic(ctx, params)
+		require.NoError(t, err)
+		require.Equal(t, int64(7), id)
+	})
+}
+
+func TestMuteUC_Mute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockMuteRepository(ctrl)
+	topics := mocks.NewMockTopicRepository(ctrl)
+	mods := mocks.NewMockModeratorRepository(ctrl)
+	modlog := mocks.NewMockModerationRepository(ctrl)
+	uc := NewMuteUsecase(repo, topics, mods, modlog, nil, mocks.FakeLogger{})
+
+	admin := auth.WithUser(context.Background(), 1, "admin")
+	moderator := auth.WithUser(context.Background(), 3, "moderator")
+
+	t.Run("invalid scope", func(t *testing.T) {
+		_, err := uc.Mute(admin, MuteParams{UserID: 5})
+		require.ErrorIs(t, err, ErrInvalidMute)
+		_, err = uc.Mute(admin, MuteParams{UserID: 5, TopicID: 1, CategoryID: 2})
+		require.ErrorIs(t, err, ErrInvalidMute)
+	})
+
+	t.Run("user is forbidden", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 7, "user")
+		_, err := uc.Mute(ctx, MuteParams{UserID: 5, CategoryID: 2})
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("moderator outside category", func(t *testing.T) {
+		topics.EXPECT().GetByID(moderator, int64(10)).Return(&entity.Topic{ID: 10, CategoryID: 4}, nil)
+		mods.EXPECT().IsModerator(moderator, int64(3), int64(4)).Return(false, nil)
+		_, err := uc.Mute(moderator, MuteParams{UserID: 5, TopicID: 10})
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("moderator mutes in own category", func(t *testing.T) {
+		mods.EXPECT().IsModerator(moderator, int64(3), int64(4)).Return(true, nil)
+		repo.EXPECT().Create(moderator, gomock.Any()).DoAndReturn(func(_ context.Context, m *entity.Mute) error {
+			require.Equal(t, int64(5), m.UserID)
+			require.Equal(t, int64(4), *m.CategoryID)
+			require.Nil(t, m.TopicID)
+			require.Equal(t, int64(3), *m.MutedBy)
+			require.NotNil(t, m.ExpiresAt)
+			require.WithinDuration(t, time.Now().Add(24*time.Hour), *m.ExpiresAt, time.Minute)
+			m.ID = 9
+			return nil
+		})
+		modlog.EXPECT().Save(moderator, gomock.Any()).DoAndReturn(func(_ context.Context, e *entity.ModerationEntry) error {
+			require.Equal(t, entity.ModMuteUser, e.Action)
+			require.Equal(t, int64(5), e.TargetID)
+			require.Equal(t, int64(4), e.Details["category_id"])
+			return nil
+		})
+
+		m, err := uc.Mute(moderator, MuteParams{UserID: 5, CategoryID: 4, Duration: 24 * time.Hour, Reason: "flood"})
+		require.NoError(t, err)
+		require.Equal(t, int64(9), m.ID)
+	})
+
+	t.Run("permanent mute", func(t *testing.T) {
+		repo.EXPECT().Create(admin, gomock.Any()).DoAndReturn(func(_ context.Context, m *entity.Mute) error {
+			require.Nil(t, m.ExpiresAt)
+			require.Equal(t, int64(10), *m.TopicID)
+			return nil
+		})
+		modlog.EXPECT().Save(admin, gomock.Any()).Return(nil)
+
+		_, err := uc.Mute(admin, MuteParams{UserID: 5, TopicID: 10})
+		require.NoError(t, err)
+	})
+
+	t.Run("missing reference", func(t *testing.T) {
+		repo.EXPECT().Create(admin, gomock.Any()).Return(customErr.ErrInvalidReference)
+		_, err := uc.Mute(admin, MuteParams{UserID: 5, CategoryID: 99})
+		require.ErrorIs(t, err, ErrMuteReference)
+	})
+}
+
+func TestMuteUC_Unmute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockMuteRepository(ctrl)
+	mods := mocks.NewMockModeratorRepository(ctrl)
+	modlog := mocks.NewMockModerationRepository(ctrl)
+	uc := NewMuteUsecase(repo, nil, mods, modlog, nil, mocks.FakeLogger{})
+
+	moderator := auth.WithUser(context.Background(), 3, "moderator")
+	categoryID := int64(4)
+
+	t.Run("not found", func(t *testing.T) {
+		repo.EXPECT().GetByID(moderator, int64(9)).Return(nil, customErr.ErrNotFound)
+		require.ErrorIs(t, uc.Unmute(moderator, 9), ErrMuteNotFound)
+	})
+
+	t.Run("moderator outside category", func(t *testing.T) {
+		repo.EXPECT().GetByID(moderator, int64(9)).Return(&entity.Mute{ID: 9, UserID: 5, CategoryID: &categoryID}, nil)
+		mods.EXPECT().IsModerator(moderator, int64(3), int64(4)).Return(false, nil)
+		require.ErrorIs(t, uc.Unmute(moderator, 9), ErrForbidden)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().GetByID(moderator, int64(9)).Return(&entity.Mute{ID: 9, UserID: 5, CategoryID: &categoryID}, nil)
+		mods.EXPECT().IsModerator(moderator, int64(3), int64(4)).Return(true, nil)
+		repo.EXPECT().Delete(moderator, int64(9)).Return(nil)
+		modlog.EXPECT().Save(moderator, gomock.Any()).DoAndReturn(func(_ context.Context, e *entity.ModerationEntry) error {
+			require.Equal(t, entity.ModUnmuteUser, e.Action)
+			return nil
+		})
+		require.NoError(t, uc.Unmute(moderator, 9))
+	})
+}
+
+func TestMuteUC_ListMutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockMuteRepository(ctrl)
+	uc := NewMuteUsecase(repo, nil, nil, nil, nil, mocks.FakeLogger{})
+
+	t.Run("moderator is forbidden", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 3, "moderator")
+		_, err := uc.ListMutes(ctx, 0)
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("own mutes hide moderator", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 5, "user")
+		by := int64(3)
+		repo.EXPECT().ListActive(ctx, int64(5)).Return([]*entity.Mute{{ID: 1, UserID: 5, MutedBy: &by}}, nil)
+		list, err := uc.ListMyMutes(ctx)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Nil(t, list[0].MutedBy)
+	})
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	repoErr "chat-service/internal/errors"
+	"chat-service/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/logger"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+const (
+	defaultNotificationLimit = 50 // границы страницы уведомлений
+	maxNotificationLimit     = 200
+	excerptLength            = 140 // столько символов сообщения попадает в уведомление
+)
+
+// UserPublisher доставляет события в личный канал пользователя
+type UserPublisher interface {
+	PublishToUser(userID int64, ev *entity.WSEvent)
+}
+
+// Notifier создаёт уведомления; реализуется NotificationUC.
+// Сбой не отменяет действие, вызвавшее уведомление, — он логируется.
+type Notifier interface {
+	Notify(ctx context.Context, list ...*entity.Notification)
+}
+
+type NotificationUC struct {
+	repo      repo.NotificationRepository
+	publisher UserPublisher
+	log       logger.Interface
+}
+
+func NewNotificationUsecase(r repo.NotificationRepository, p UserPublisher, l logger.Interface) *NotificationUC {
+	return &NotificationUC{repo: r, publisher: p, log: l}
+}
+
+// Notify сохраняет уведомления и сразу отправляет их в личные каналы получателей.
+// Уведомление о собственном действии не создаётся.
+func (uc *NotificationUC) Notify(ctx context.Context, list ...*entity.Notification) {
+	for _, n := range list {
+		if n.UserID == 0 || (n.ActorID != nil && *n.ActorID == n.UserID) {
+			continue
+		}
+		if err := uc.repo.Create(ctx, n); err != nil {
+			uc.log.Error("notification not saved", "type", n.Type, "user_id", n.UserID, "err", err)
+			continue
+		}
+		uc.publisher.PublishToUser(n.UserID, &entity.WSEvent{Action: entity.ActionNotification, Notification: n})
+	}
+}
+
+// ListNotifications возвращает страницу уведомлений текущего пользователя, общее число подходящих
+// и число непрочитанных
+func (uc *NotificationUC) ListNotifications(ctx context.Context, unreadOnly bool, limit, offset int) ([]*entity.Notification, int64, int64, error) {
+	uc.log.Debug("ListNotifications called", "unread_only", unreadOnly, "limit", limit, "offset", offset)
+
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 {
+		uc.log.Warn("unauthenticated user tried to read notifications")
+		return nil, 0, 0, ErrUnauthenticated
+	}
+
+	if limit <= 0 {
+		limit = defaultNotificationLimit
+	} else if limit > maxNotificationLimit {
+		limit = maxNotificationLimit
+	}
+	f := entity.NotificationFilter{UserID: userID, UnreadOnly: unreadOnly, Limit: limit, Offset: max(offset, 0)}
+	list, total, err := uc.repo.List(ctx, f)
+	if err != nil {
+		uc.log.Error("repo.List failed", "err", err)
+		return nil, 0, 0, fmt.Errorf("NotificationUC.List: %w", err)
+	}
+	unread, err := uc.repo.CountUnread(ctx, userID)
+	if err != nil {
+		uc.log.Error("repo.CountUnread failed", "err", err)
+		return nil, 0, 0, fmt.Errorf("NotificationUC.List#unread: %w", err)
+	}
+
+	uc.log.Info("notifications retrieved", "user_id", userID, "count", len(list), "unread", unread)
+	return list, total, unread, nil
+}
+
+// MarkNotificationRead отмечает уведомление текущего пользователя прочитанным
+func (uc *NotificationUC) MarkNotificationRead(ctx context.Context, id int64) error {
+	uc.log.Debug("MarkNotificationRead called", "id", id)
+
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 {
+		return ErrUnauthenticated
+	}
+
+	err := uc.repo.MarkRead(ctx, userID, id)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		uc.log.Info("notification not found", "id", id, "user_id", userID)
+		return ErrNotificationNotFound
+	} else if err != nil {
+		uc.log.Error("repo.MarkRead failed", "err", err)
+		return fmt.Errorf("NotificationUC.MarkRead: %w", err)
+	}
+	return nil
+}
+
+// MarkAllNotificationsRead отмечает прочитанными все уведомления текущего пользователя
+func (uc *NotificationUC) MarkAllNotificationsRead(ctx context.Context) (int64, error) {
+	uc.log.Debug("MarkAllNotificationsRead called")
+
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 {
+		return 0, ErrUnauthenticated
+	}
+
+	n, err := uc.repo.MarkAllRead(ctx, userID)
+	if err != nil {
+		uc.log.Error("repo.MarkAllRead failed", "err", err)
+		return 0, fmt.Errorf("NotificationUC.MarkAllRead: %w", err)
+	}
+
+	uc.log.Info("notifications marked read", "user_id", userID, "count", n)
+	return n, nil
+}
+
+// messageNotification — уведомление типа typ о сообщении m для userID
+func messageNotification(typ string, userID int64, m *entity.Message, topicTitle string) *entity.Notification {
+	data := map[string]any{"excerpt": excerpt(m.Content)}
+	if topicTitle != "" {
+		data["topic_title"] = topicTitle
+	}
+	return &entity.Notification{
+		UserID:    userID,
+		Type:      typ,
+		ActorID:   &m.AuthorID,
+		ActorName: m.AuthorName,
+		TopicID:   &m.TopicID,
+		MessageID: &m.ID,
+		Data:      data,
+	}
+}
+
+// excerpt — начало текста для уведомления
+func excerpt(s string) string {
+	r := []rune(s)
+	if len(r) <= excerptLength {
+		return s
+	}
+	return string(r[:excerptLength]) + "…"
+}
@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	customErr "chat-service/internal/errors"
+	"chat-service/internal/usecase/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestNotificationUC_Notify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockNotificationRepository(ctrl)
+	publisher := mocks.NewMockUserPublisher(ctrl)
+	uc := NewNotificationUsecase(repo, publisher, mocks.FakeLogger{})
+	ctx := context.Background()
+
+	t.Run("stored and pushed", func(t *testing.T) {
+		n := &entity.Notification{UserID: 2, Type: entity.NotifyReply}
+		repo.EXPECT().Create(ctx, n).DoAndReturn(func(_ context.Context, n *entity.Notification) error {
+			n.ID = 7
+			return nil
+		})
+		publisher.EXPECT().PublishToUser(int64(2), &entity.WSEvent{Action: entity.ActionNotification, Notification: n})
+
+		uc.Notify(ctx, n)
+		require.Equal(t, int64(7), n.ID)
+	})
+
+	t.Run("own action and missing recipient are skipped", func(t *testing.T) {
+		actor := int64(2)
+		uc.Notify(ctx,
+			&entity.Notification{UserID: 2, ActorID: &actor, Type: entity.NotifyQuote},
+			&entity.Notification{Type: entity.NotifyReply},
+		)
+	})
+
+	t.Run("repo error does not stop the rest", func(t *testing.T) {
+		repo.EXPECT().Create(ctx, gomock.Any()).Return(errors.New("db down"))
+		repo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		publisher.EXPECT().PublishToUser(int64(3), gomock.Any())
+
+		uc.Notify(ctx,
+			&entity.Notification{UserID: 2, Type: entity.NotifyReply},
+			&entity.Notification{UserID: 3, Type: entity.NotifyReply},
+		)
+	})
+}
+
+func TestNotificationUC_ListNotifications(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockNotificationRepository(ctrl)
+	uc := NewNotificationUsecase(repo, nil, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 2, "user")
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, _, _, err := uc.ListNotifications(context.Background(), false, 10, 0)
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	t.Run("page is clamped", func(t *testing.T) {
+		list := []*entity.Notification{{ID: 1, UserID: 2, Type: entity.NotifyMention}}
+		repo.EXPECT().List(ctx, entity.NotificationFilter{UserID: 2, UnreadOnly: true, Limit: maxNotificationLimit}).
+			Return(list, int64(1), nil)
+		repo.EXPECT().CountUnread(ctx, int64(2)).Return(int64(1), nil)
+
+		got, total, unread, err := uc.ListNotifications(ctx, true, 1000, -5)
+		require.NoError(t, err)
+		require.Equal(t, list, got)
+		require.Equal(t, int64(1), total)
+		require.Equal(t, int64(1), unread)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		repo.EXPECT().List(ctx, entity.NotificationFilter{UserID: 2, Limit: defaultNotificationLimit}).
+			Return(nil, int64(0), errors.New("db down"))
+
+		_, _, _, err := uc.ListNotifications(ctx, false, 0, 0)
+		require.Error(t, err)
+	})
+}
+
+func TestNotificationUC_MarkRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockNotificationRepository(ctrl)
+	uc := NewNotificationUsecase(repo, nil, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 2, "user")
+
+	t.Run("one", func(t *testing.T) {
+		repo.EXPECT().MarkRead(ctx, int64(2), int64(7)).Return(nil)
+		require.NoError(t, uc.MarkNotificationRead(ctx, 7))
+	})
+
+	t.Run("someone else's is not found", func(t *testing.T) {
+		repo.EXPECT().MarkRead(ctx, int64(2), int64(8)).Return(customErr.ErrNotFound)
+		require.ErrorIs(t, uc.MarkNotificationRead(ctx, 8), ErrNotificationNotFound)
+	})
+
+	t.Run("all", func(t *testing.T) {
+		repo.EXPECT().MarkAllRead(ctx, int64(2)).Return(int64(3), nil)
+		n, err := uc.MarkAllNotificationsRead(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), n)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		require.ErrorIs(t, uc.MarkNotificationRead(context.Background(), 7), ErrUnauthenticated)
+		_, err := uc.MarkAllNotificationsRead(context.Background())
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+}
+
+func TestMessageUC_SendMessage_Notifications(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockMessageRepository(ctrl)
+	topics := mocks.NewMockTopicRepository(ctrl)
+	publisher := mocks.NewMockMessagePublisher(ctrl)
+	mentions := mocks.NewMockMentionProcessor(ctrl)
+	notifier := mocks.NewMockNotifier(ctrl)
+	uc := NewMessageUsecase(repo, topics, nil, nil, nil, MessageDeps{Mentions: mentions, Notifier: notifier, Publisher: publisher}, mocks.FakeLogger{}, retention)
+	ctx := auth.WithUser(context.Background(), 1, "user")
+	quoteID := int64(4)
+	topic := &entity.Topic{ID: 10, AuthorID: 2, Title: "Новости"}
+
+	t.Run("reply to topic author", func(t *testing.T) {
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(topic, nil)
+		repo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		publisher.EXPECT().Publish(int64(10), gomock.Any())
+		mentions.EXPECT().ProcessMentions(ctx, gomock.Any(), false).Return(nil)
+		notifier.EXPECT().Notify(ctx, gomock.Any()).Do(func(_ context.Context, list ...*entity.Notification) {
+			require.Equal(t, int64(2), list[0].UserID)
+			require.Equal(t, entity.NotifyReply, list[0].Type)
+			require.Equal(t, int64(1), *list[0].ActorID)
+			require.Equal(t, "Новости", list[0].Data["topic_title"])
+		})
+
+		_, err := uc.SendMessage(ctx, SendMessageParams{TopicID: 10, AuthorID: 1, Content: "hi"})
+		require.NoError(t, err)
+	})
+
+	t.Run("quote comes before reply", func(t *testing.T) {
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(topic, nil)
+		repo.EXPECT().GetByID(ctx, quoteID).Return(&entity.Message{ID: 4, TopicID: 10, AuthorID: 3}, nil)
+		repo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, m *entity.Message) error {
+			require.Equal(t, &quoteID, m.QuoteID)
+			return nil
+		})
+		publisher.EXPECT().Publish(int64(10), gomock.Any())
+		mentions.EXPECT().ProcessMentions(ctx, gomock.Any(), false).Return(nil)
+		notifier.EXPECT().Notify(ctx, gomock.Any(), gomock.Any()).Do(func(_ context.Context, list ...*entity.Notification) {
+			require.Equal(t, int64(3), list[0].UserID)
+			require.Equal(t, entity.NotifyQuote, list[0].Type)
+			require.Equal(t, int64(4), list[0].Data["quote_id"])
+			require.Equal(t, int64(2), list[1].UserID)
+			require.Equal(t, entity.NotifyReply, list[1].Type)
+		})
+
+		_, err := uc.SendMessage(ctx, SendMessageParams{TopicID: 10, AuthorID: 1, Content: "> hi\n\nhello", QuoteID: &quoteID})
+		require.NoError(t, err)
+	})
+
+	t.Run("mentioned topic author gets only the mention", func(t *testing.T) {
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(topic, nil)
+		repo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		publisher.EXPECT().Publish(int64(10), gomock.Any())
+		mentions.EXPECT().ProcessMentions(ctx, gomock.Any(), false).Return([]int64{2})
+
+		_, err := uc.SendMessage(ctx, SendMessageParams{TopicID: 10, AuthorID: 1, Content: "@owner"})
+		require.NoError(t, err)
+	})
+
+	t.Run("own topic is not notified", func(t *testing.T) {
+		own := &entity.Topic{ID: 10, AuthorID: 1}
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(own, nil)
+		repo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		publisher.EXPECT().Publish(int64(10), gomock.Any())
+		mentions.EXPECT().ProcessMentions(ctx, gomock.Any(), false).Return(nil)
+
+		_, err := uc.SendMessage(ctx, SendMessageParams{TopicID: 10, AuthorID: 1, Content: "hi"})
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid quote", func(t *testing.T) {
+		cases := map[string]*entity.Message{
+			"other topic": {ID: 4, TopicID: 11, AuthorID: 3},
+			"hidden":      {ID: 4, TopicID: 10, AuthorID: 3, Hidden: true},
+		}
+		for name, q := range cases {
+			t.Run(name, func(t *testing.T) {
+				topics.EXPECT().GetByID(ctx, int64(10)).Return(topic, nil)
+				repo.EXPECT().GetByID(ctx, quoteID).Return(q, nil)
+
+				_, err := uc.SendMessage(ctx, SendMessageParams{TopicID: 10, AuthorID: 1, Content: "hi", QuoteID: &quoteID})
+				require.ErrorIs(t, err, ErrInvalidQuote)
+			})
+		}
+
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(topic, nil)
+		repo.EXPECT().GetByID(ctx, quoteID).Return(nil, customErr.ErrNotFound)
+		_, err := uc.SendMessage(ctx, SendMessageParams{TopicID: 10, AuthorID: 1, Content: "hi", QuoteID: &quoteID})
+		require.ErrorIs(t, err, ErrInvalidQuote)
+	})
+}
+
+func TestModLog_NotifiesTargetAuthor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockMessageRepository(ctrl)
+	modlog := mocks.NewMockModerationRepository(ctrl)
+	publisher := mocks.NewMockMessagePublisher(ctrl)
+	notifier := mocks.NewMockNotifier(ctrl)
+	uc := NewMessageUsecase(repo, nil, nil, nil, modlog, MessageDeps{Notifier: notifier, Publisher: publisher}, mocks.FakeLogger{}, retention)
+
+	t.Run("author learns of deletion but not who did it", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "admin")
+		repo.EXPECT().GetByID(ctx, int64(9)).Return(&entity.Message{ID: 9, TopicID: 10, AuthorID: 42}, nil)
+		repo.EXPECT().Delete(ctx, int64(9), int64(1), "spam").Return(nil)
+		publisher.EXPECT().Publish(int64(10), gomock.Any())
+		modlog.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+		notifier.EXPECT().Notify(ctx, gomock.Any()).Do(func(_ context.Context, list ...*entity.Notification) {
+			n := list[0]
+			require.Equal(t, int64(42), n.UserID)
+			require.Equal(t, entity.NotifyModeration, n.Type)
+			require.Nil(t, n.ActorID)
+			require.Equal(t, entity.ModDeleteMessage, n.Data["action"])
+			require.Equal(t, "spam", n.Data["reason"])
+		})
+
+		require.NoError(t, uc.DeleteMessage(ctx, 9, "spam"))
+	})
+
+	t.Run("own message is not notified", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 42, "user")
+		repo.EXPECT().GetByID(ctx, int64(9)).Return(&entity.Message{ID: 9, TopicID: 10, AuthorID: 42}, nil)
+		repo.EXPECT().Delete(ctx, int64(9), int64(42), "").Return(nil)
+		publisher.EXPECT().Publish(int64(10), gomock.Any())
+
+		require.NoError(t, uc.DeleteMessage(ctx, 9, ""))
+	})
+}
+
+func TestExcerpt(t *testing.T) {
+	require.Equal(t, "коротко", excerpt("коротко"))
+
+	long := strings.Repeat("я", excerptLength+10)
+	got := excerpt(long)
+	require.Equal(t, excerptLength+1, len([]rune(got)))
+	require.True(t, strings.HasSuffix(got, "…"))
+}
@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"bytes"
+	"chat-service/internal/auth"
+	"chat-service/internal/blob"
+	"chat-service/internal/entity"
+	repoErr "chat-service/internal/errors"
+	"chat-service/internal/media"
+	"chat-service/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/logger"
+	"io"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxBioLength       = 1000
+	maxLocationLength  = 100
+	maxWebsiteLength   = 255
+	maxSignatureLength = 300
+)
+
+var (
+	ErrProfileNotFound = errors.New("user not found")
+	ErrInvalidProfile  = errors.New("invalid profile")
+	ErrAvatarNotFound  = errors.New("user has no avatar")
+	ErrAvatarTooLarge  = errors.New("avatar image is too large")
+	ErrAvatarType      = errors.New("avatar must be a JPEG, PNG or GIF image")
+)
+
+// ProfileOptions — ограничения на аватар и размер ленты активности
+type ProfileOptions struct {
+	AvatarMaxSize  int64 // байт в загружаемом файле
+	AvatarSize     int   // сторона квадратного аватара после уменьшения
+	RecentActivity int   // сообщений в ленте последней активности
+}
+
+// ProfileUC — публичные профили. Профиль хранится отдельно от учётной записи в auth-service;
+// аватар уменьшается до квадрата и лежит в том же blob-хранилище, что и вложения.
+type ProfileUC struct {
+	repo    repo.ProfileRepository
+	store   blob.Store
+	content ContentChecker
+	opts    ProfileOptions
+	access  access
+	log     logger.Interface
+}
+
+func NewProfileUsecase(r repo.ProfileRepository, s blob.Store, cc ContentChecker, opts ProfileOptions, l logger.Interface) *ProfileUC {
+	return &ProfileUC{repo: r, store: s, content: cc, opts: opts, log: l}
+}
+
+// GetProfile возвращает публичный профиль со статистикой и последней активностью; доступен без входа
+func (uc *ProfileUC) GetProfile(ctx context.Context, userID int64) (*entity.Profile, error) {
+	uc.log.Debug("GetProfile called", "user_id", userID)
+
+	p, err := uc.get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := uc.repo.Stats(ctx, userID)
+	if err != nil {
+		uc.log.Error("repo.Stats failed", "err", err)
+		return nil, fmt.Errorf("ProfileUC.GetProfile#stats: %w", err)
+	}
+	p.Stats = *stats
+	if uc.opts.RecentActivity > 0 {
+		if p.Recent, err = uc.repo.RecentActivity(ctx, userID, uc.opts.RecentActivity); err != nil {
+			uc.log.Error("repo.RecentActivity failed", "err", err)
+			return nil, fmt.Errorf("ProfileUC.GetProfile#recent: %w", err)
+		}
+		for _, a := range p.Recent {
+			a.Excerpt = excerpt(a.Excerpt)
+		}
+	}
+	return p, nil
+}
+
+// GetProfileByUsername — то же, что GetProfile, но по username без учёта регистра; '@' в начале допускается
+func (uc *ProfileUC) GetProfileByUsername(ctx context.Context, username string) (*entity.Profile, error) {
+	uc.log.Debug("GetProfileByUsername called", "username", username)
+
+	username = strings.TrimPrefix(strings.TrimSpace(username), "@")
+	if username == "" {
+		return nil, ErrProfileNotFound
+	}
+	userID, err := uc.repo.UserIDByUsername(ctx, username)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		return nil, ErrProfileNotFound
+	} else if err != nil {
+		uc.log.Error("repo.UserIDByUsername failed", "err", err)
+		return nil, fmt.Errorf("ProfileUC.GetProfileByUsername: %w", err)
+	}
+	return uc.GetProfile(ctx, userID)
+}
+
+// UpdateProfile заменяет публичные поля профиля текущего пользователя. Текст проходит через фильтр
+// контента: маскировка применяется, отклонение возвращается как ErrContentRejected. Пометки для
+// модераторов не используются — профили в очередь жалоб не попадают.
+func (uc *ProfileUC) UpdateProfile(ctx context.Context, p ProfileParams) (*entity.Profile, error) {
+	uc.log.Debug("UpdateProfile called")
+
+	userID, err := uc.access.check(ctx, auth.PermMessageWrite, nil)
+	if err != nil {
+		return nil, err
+	}
+	prof, err := uc.get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if prof.Bio, prof.Location, prof.Website, prof.Signature, err = normalizeProfile(p); err != nil {
+		return nil, err
+	}
+	for _, field := range []*string{&prof.Bio, &prof.Location, &prof.Signature} {
+		if *field == "" {
+			continue
+		}
+		if *field, _, err = screen(ctx, uc.content, uc.log, userID, *field, false); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.repo.Save(ctx, prof); errors.Is(err, repoErr.ErrNotFound) {
+		return nil, ErrProfileNotFound
+	} else if err != nil {
+		uc.log.Error("repo.Save failed", "err", err)
+		return nil, fmt.Errorf("ProfileUC.UpdateProfile: %w", err)
+	}
+
+	uc.log.Info("profile updated", "user_id", userID)
+	return prof, nil
+}
+
+// SetAvatar заменяет аватар текущего пользователя: картинка обрезается до квадрата по центру
+// и уменьшается до AvatarSize. Прежний файл удаляется.
+func (uc *ProfileUC) SetAvatar(ctx context.Context, body io.Reader) (*entity.Profile, error) {
+	uc.log.Debug("SetAvatar called")
+
+	userID, err := uc.access.check(ctx, auth.PermMessageWrite, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, uc.opts.AvatarMaxSize+1))
+	if err != nil {
+		uc.log.Warn("avatar read failed", "err", err)
+		return nil, fmt.Errorf("ProfileUC.SetAvatar#read: %w", err)
+	}
+	if int64(len(data)) > uc.opts.AvatarMaxSize {
+		return nil, ErrAvatarTooLarge
+	}
+	if !media.CanThumbnail(media.Sniff(data)) {
+		return nil, ErrAvatarType
+	}
+	img, err := media.Square(data, uc.opts.AvatarSize)
+	if errors.Is(err, media.ErrTooManyPixels) {
+		return nil, ErrAvatarTooLarge
+	} else if err != nil {
+		uc.log.Info("avatar rejected: unreadable image", "user_id", userID, "err", err)
+		return nil, ErrAvatarType
+	}
+
+	key := newStorageKey("avatars")
+	if err := uc.store.Put(ctx, key, bytes.NewReader(img.Data), int64(len(img.Data)), img.ContentType); err != nil {
+		uc.log.Error("store.Put failed", "err", err)
+		return nil, fmt.Errorf("ProfileUC.SetAvatar#store: %w", err)
+	}
+	previous, err := uc.repo.SetAvatar(ctx, userID, key)
+	if err != nil {
+		uc.removeAvatar(ctx, key)
+		if errors.Is(err, repoErr.ErrNotFound) {
+			return nil, ErrProfileNotFound
+		}
+		uc.log.Error("repo.SetAvatar failed", "err", err)
+		return nil, fmt.Errorf("ProfileUC.SetAvatar: %w", err)
+	}
+	uc.removeAvatar(ctx, previous)
+
+	uc.log.Info("avatar updated", "user_id", userID, "size", len(img.Data))
+	return uc.get(ctx, userID)
+}
+
+// DeleteAvatar убирает аватар текущего пользователя; если его не было — не ошибка
+func (uc *ProfileUC) DeleteAvatar(ctx context.Context) error {
+	uc.log.Debug("DeleteAvatar called")
+
+	userID, err := uc.access.check(ctx, auth.PermMessageWrite, nil)
+	if err != nil {
+		return err
+	}
+	previous, err := uc.repo.SetAvatar(ctx, userID, "")
+	if errors.Is(err, repoErr.ErrNotFound) {
+		return ErrProfileNotFound
+	} else if err != nil {
+		uc.log.Error("repo.SetAvatar failed", "err", err)
+		return fmt.Errorf("ProfileUC.DeleteAvatar: %w", err)
+	}
+	uc.removeAvatar(ctx, previous)
+
+	uc.log.Info("avatar deleted", "user_id", userID)
+	return nil
+}
+
+// OpenAvatar отдаёт картинку аватара; закрыть поток — на вызывающем
+func (uc *ProfileUC) OpenAvatar(ctx context.Context, userID int64) (io.ReadCloser, error) {
+	p, err := uc.get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !p.HasAvatar() {
+		return nil, ErrAvatarNotFound
+	}
+	rc, err := uc.store.Open(ctx, p.AvatarKey)
+	if errors.Is(err, blob.ErrNotFound) {
+		uc.log.Warn("avatar file is missing", "user_id", userID, "key", p.AvatarKey)
+		return nil, ErrAvatarNotFound
+	} else if err != nil {
+		uc.log.Error("store.Open failed", "err", err)
+		return nil, fmt.Errorf("ProfileUC.OpenAvatar: %w", err)
+	}
+	return rc, nil
+}
+
+func (uc *ProfileUC) get(ctx context.Context, userID int64) (*entity.Profile, error) {
+	p, err := uc.repo.Get(ctx, userID)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		return nil, ErrProfileNotFound
+	} else if err != nil {
+		uc.log.Error("repo.Get failed", "err", err)
+		return nil, fmt.Errorf("ProfileUC.get: %w", err)
+	}
+	return p, nil
+}
+
+// removeAvatar удаляет файл аватара; не вышло — файл останется лишним, профилю это не мешает
+func (uc *ProfileUC) removeAvatar(ctx context.Context, key string) {
+	if key == "" {
+		return
+	}
+	if err := uc.store.Delete(ctx, key); err != nil {
+		uc.log.Warn("store.Delete failed", "key", key, "err", err)
+	}
+}
+
+// normalizeProfile обрезает пробелы и проверяет длины; сайт — только абсолютная http(s)-ссылка
+func normalizeProfile(p ProfileParams) (bio, location, website, signature string, err error) {
+	bio = strings.TrimSpace(p.Bio)
+	location = strings.TrimSpace(p.Location)
+	website = strings.TrimSpace(p.Website)
+	signature = strings.TrimSpace(p.Signature)
+
+	switch {
+	case utf8.RuneCountInString(bio) > maxBioLength:
+		err = fmt.Errorf("%w: bio is longer than %d characters", ErrInvalidProfile, maxBioLength)
+	case utf8.RuneCountInString(location) > maxLocationLength:
+		err = fmt.Errorf("%w: location is longer than %d characters", ErrInvalidProfile, maxLocationLength)
+	case strings.ContainsAny(location, "\r\n"):
+		err = fmt.Errorf("%w: location must be a single line", ErrInvalidProfile)
+	case utf8.RuneCountInString(signature) > maxSignatureLength:
+		err = fmt.Errorf("%w: signature is longer than %d characters", ErrInvalidProfile, maxSignatureLength)
+	case len(website) > maxWebsiteLength:
+		err = fmt.Errorf("%w: website is longer than %d characters", ErrInvalidProfile, maxWebsiteLength)
+	case website != "" && !isWebURL(website):
+		err = fmt.Errorf("%w: website must be an http or https link", ErrInvalidProfile)
+	}
+	return
+}
+
+func isWebURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && u.User == nil
+}
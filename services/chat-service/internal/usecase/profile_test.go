@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"bytes"
+	"chat-service/internal/auth"
+	"chat-service/internal/blob"
+	"chat-service/internal/entity"
+	customErr "chat-service/internal/errors"
+	"chat-service/internal/usecase/mocks"
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testProfileOptions = ProfileOptions{AvatarMaxSize: 1 << 20, AvatarSize: 16, RecentActivity: 5}
+
+func TestProfileUC_GetProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockProfileRepository(ctrl)
+	uc := NewProfileUsecase(repo, nil, nil, testProfileOptions, mocks.FakeLogger{})
+	ctx := context.Background()
+
+	t.Run("not found", func(t *testing.T) {
+		repo.EXPECT().Get(ctx, int64(9)).Return(nil, customErr.ErrNotFound)
+		_, err := uc.GetProfile(ctx, 9)
+		require.ErrorIs(t, err, ErrProfileNotFound)
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		last := time.Now()
+		repo.EXPECT().Get(ctx, int64(1)).Return(&entity.Profile{UserID: 1, Name: "alice", Bio: "hi"}, nil)
+		repo.EXPECT().Stats(ctx, int64(1)).Return(&entity.ProfileStats{Topics: 2, Messages: 7, LastActiveAt: &last}, nil)
+		repo.EXPECT().RecentActivity(ctx, int64(1), 5).Return([]*entity.ProfileActivity{
+			{MessageID: 3, TopicID: 1, Excerpt: strings.Repeat("а", excerptLength+10)},
+		}, nil)
+		p, err := uc.GetProfile(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, int64(7), p.Stats.Messages)
+		require.Len(t, []rune(p.Recent[0].Excerpt), excerptLength+1)
+	})
+}
+
+func TestProfileUC_GetProfileByUsername(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockProfileRepository(ctrl)
+	uc := NewProfileUsecase(repo, nil, nil, ProfileOptions{}, mocks.FakeLogger{})
+	ctx := context.Background()
+
+	t.Run("unknown", func(t *testing.T) {
+		repo.EXPECT().UserIDByUsername(ctx, "ghost").Return(int64(0), customErr.ErrNotFound)
+		_, err := uc.GetProfileByUsername(ctx, "ghost")
+		require.ErrorIs(t, err, ErrProfileNotFound)
+	})
+
+	t.Run("leading at is ignored", func(t *testing.T) {
+		repo.EXPECT().UserIDByUsername(ctx, "Alice").Return(int64(1), nil)
+		repo.EXPECT().Get(ctx, int64(1)).Return(&entity.Profile{UserID: 1, Username: "alice"}, nil)
+		repo.EXPECT().Stats(ctx, int64(1)).Return(&entity.ProfileStats{}, nil)
+		p, err := uc.GetProfileByUsername(ctx, "@Alice")
+		require.NoError(t, err)
+		require.Equal(t, "alice", p.Username)
+	})
+}
+
+func TestProfileUC_UpdateProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockProfileRepository(ctrl)
+	uc := NewProfileUsecase(repo, nil, nil, testProfileOptions, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 1, "user")
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := uc.UpdateProfile(context.Background(), ProfileParams{Bio: "hi"})
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	invalid := []ProfileParams{
+		{Website: "javascript:alert(1)"},
+		{Website: "example.com"},
+		{Bio: strings.Repeat("x", maxBioLength+1)},
+		{Location: "Москва\nгде-то"},
+	}
+	for _, p := range invalid {
+		repo.EXPECT().Get(ctx, int64(1)).Return(&entity.Profile{UserID: 1}, nil)
+		_, err := uc.UpdateProfile(ctx, p)
+		require.ErrorIs(t, err, ErrInvalidProfile, "%+v", p)
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		repo.EXPECT().Get(ctx, int64(1)).Return(&entity.Profile{UserID: 1, Bio: "old"}, nil)
+		repo.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, p *entity.Profile) error {
+			require.Equal(t, "Пишу на Go", p.Bio)
+			require.Equal(t, "https://example.com/me", p.Website)
+			return nil
+		})
+		p, err := uc.UpdateProfile(ctx, ProfileParams{Bio: "  Пишу на Go ", Website: "https://example.com/me"})
+		require.NoError(t, err)
+		require.Equal(t, "Пишу на Go", p.Bio)
+	})
+}
+
+func TestProfileUC_Avatar(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockProfileRepository(ctrl)
+	store, err := blob.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	uc := NewProfileUsecase(repo, store, nil, testProfileOptions, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 1, "user")
+
+	t.Run("not an image", func(t *testing.T) {
+		_, err := uc.SetAvatar(ctx, strings.NewReader("%PDF-1.4 not an avatar"))
+		require.ErrorIs(t, err, ErrAvatarType)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		_, err := uc.SetAvatar(ctx, bytes.NewReader(make([]byte, testProfileOptions.AvatarMaxSize+1)))
+		require.ErrorIs(t, err, ErrAvatarTooLarge)
+	})
+
+	require.NoError(t, store.Put(ctx, "avatars/old", strings.NewReader("old"), 3, "image/png"))
+	var key string
+
+	t.Run("replaces previous", func(t *testing.T) {
+		repo.EXPECT().SetAvatar(ctx, int64(1), gomock.Any()).DoAndReturn(func(_ context.Context, _ int64, k string) (string, error) {
+			key = k
+			return "avatars/old", nil
+		})
+		repo.EXPECT().Get(ctx, int64(1)).DoAndReturn(func(context.Context, int64) (*entity.Profile, error) {
+			return &entity.Profile{UserID: 1, AvatarKey: key}, nil
+		})
+		p, err := uc.SetAvatar(ctx, bytes.NewReader(testPNG(t, 64, 40)))
+		require.NoError(t, err)
+		require.True(t, p.HasAvatar())
+
+		rc, err := store.Open(ctx, key)
+		require.NoError(t, err)
+		data, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		cfg, err := png.DecodeConfig(bytes.NewReader(data))
+		require.NoError(t, err)
+		require.Equal(t, 16, cfg.Width, "cropped to a square and scaled down")
+		require.Equal(t, 16, cfg.Height)
+
+		_, err = store.Open(ctx, "avatars/old")
+		require.ErrorIs(t, err, blob.ErrNotFound)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		repo.EXPECT().SetAvatar(ctx, int64(1), "").Return(key, nil)
+		require.NoError(t, uc.DeleteAvatar(ctx))
+		_, err := store.Open(ctx, key)
+		require.ErrorIs(t, err, blob.ErrNotFound)
+	})
+
+	t.Run("no avatar", func(t *testing.T) {
+		repo.EXPECT().Get(context.Background(), int64(1)).Return(&entity.Profile{UserID: 1}, nil)
+		_, err := uc.OpenAvatar(context.Background(), 1)
+		require.ErrorIs(t, err, ErrAvatarNotFound)
+	})
+}
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/ratelimit"
+	"chat-service/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/logger"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var ErrRateLimited = errors.New("too many requests")
+
+// Действия с отдельными лимитами
+const (
+	ActionMessage = "message" // новое сообщение
+	ActionTopic   = "topic"   // новый топик
+	ActionEdit    = "edit"    // правка сообщения или топика
+)
+
+// standingTTL — сколько помнить, что пользователь новый или доверенный
+const standingTTL = 5 * time.Minute
+
+// RateLimitedError — лимит исчерпан; errors.Is(err, ErrRateLimited) == true
+type RateLimitedError struct {
+	Result ratelimit.Result
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("too many requests: limit %s, retry in %s", e.Result.Limit, e.Result.RetryAfter.Round(time.Second))
+}
+
+func (e *RateLimitedError) Unwrap() error { return ErrRateLimited }
+
+type standingEntry struct {
+	restricted bool
+	at         time.Time
+}
+
+// RateLimitUC ограничивает частоту действий по пользователю и по IP.
+// Новые аккаунты, пользователи почти без сообщений и с низкой репутацией получают лимиты Restricted.
+// Модераторы и админы не ограничиваются. Ошибка хранилища не мешает действию.
+type RateLimitUC struct {
+	store  ratelimit.Store
+	users  repo.UserRepository
+	policy RateLimitPolicy
+	log    logger.Interface
+	now    func() time.Time
+
+	mu        sync.Mutex
+	standings map[int64]standingEntry
+	custom    time.Duration // самый длинный период среди лимитов, переданных в AllowLimit
+}
+
+func NewRateLimitUsecase(s ratelimit.Store, ur repo.UserRepository, p RateLimitPolicy, l logger.Interface) *RateLimitUC {
+	return &RateLimitUC{
+		store:     s,
+		users:     ur,
+		policy:    p,
+		log:       l,
+		now:       time.Now,
+		standings: make(map[int64]standingEntry),
+	}
+}
+
+// Allow забирает по токену из ведра пользователя и ведра IP. Результат — самый строгий из двух,
+// для заголовков RateLimit-*; nil — действие не ограничивается.
+func (uc *RateLimitUC) Allow(ctx context.Context, action, ip string) (*ratelimit.Result, error) {
+	userID, role := auth.FromContext(ctx)
+	if role == auth.RoleModerator || role == auth.RoleAdmin {
+		return nil, nil
+	}
+	limits, ok := uc.policy.Actions[action]
+	if !ok {
+		return nil, nil
+	}
+	now := uc.now().UTC()
+
+	var result *ratelimit.Result
+	take := func(key string, l ratelimit.Limit) bool {
+		if !l.Enabled() {
+			return true
+		}
+		res, err := uc.store.Take(ctx, key, l, now)
+		if err != nil {
+			uc.log.Error("rate limit store failed", "key", key, "err", err)
+			return true
+		}
+		if result == nil || !res.Allowed || res.Remaining < result.Remaining {
+			result = &res
+		}
+		return res.Allowed
+	}
+
+	// сначала IP: запрос, отбитый по IP, не расходует лимит пользователя
+	if ip != "" && !take("ip:"+action+":"+ip, limits.IP) {
+		uc.log.Info("rate limited by ip", "action", action, "ip", ip, "user_id", userID)
+		return result, &RateLimitedError{Result: *result}
+	}
+	if userID != 0 {
+		l := limits.User
+		if uc.restricted(ctx, userID, now) {
+			l = limits.Restricted
+		}
+		if !take("user:"+action+":"+strconv.FormatInt(userID, 10), l) {
+			uc.log.Info("rate limited", "action", action, "user_id", userID)
+			return result, &RateLimitedError{Result: *result}
+		}
+	}
+	return result, nil
+}
+
+// AllowLimit забирает токен из ведра текущего пользователя с ключом key по лимиту l,
+// не связанному с политикой действий (например, лимит отдельной комнаты).
+// Анонимы, модераторы и админы, а также нулевой лимит не ограничиваются.
+func (uc *RateLimitUC) AllowLimit(ctx context.Context, key string, l ratelimit.Limit) (*ratelimit.Result, error) {
+	userID, role := auth.FromContext(ctx)
+	if userID == 0 || role == auth.RoleModerator || role == auth.RoleAdmin || !l.Enabled() {
+		return nil, nil
+	}
+	now := uc.now().UTC()
+
+	uc.mu.Lock()
+	uc.custom = max(uc.custom, l.Period)
+	uc.mu.Unlock()
+
+	res, err := uc.store.Take(ctx, key+":"+strconv.FormatInt(userID, 10), l, now)
+	if err != nil {
+		uc.log.Error("rate limit store failed", "key", key, "err", err)
+		return nil, nil
+	}
+	if !res.Allowed {
+		uc.log.Info("rate limited", "key", key, "user_id", userID)
+		return &res, &RateLimitedError{Result: res}
+	}
+	return &res, nil
+}
+
+// restricted — аккаунт моложе NewAccountAge, с числом сообщений меньше MinPosts или репутацией меньше MinReputation.
+// Если узнать не удалось, пользователь считается новым.
+func (uc *RateLimitUC) restricted(ctx context.Context, userID int64, now time.Time) bool {
+	uc.mu.Lock()
+	e, ok := uc.standings[userID]
+	uc.mu.Unlock()
+	if ok && now.Sub(e.at) < standingTTL {
+		return e.restricted
+	}
+
+	s, err := uc.users.GetStanding(ctx, userID)
+	if err != nil {
+		uc.log.Error("users.GetStanding failed", "user_id", userID, "err", err)
+		return true
+	}
+	restricted := now.Sub(s.CreatedAt) < uc.policy.NewAccountAge || s.Posts < uc.policy.MinPosts ||
+		s.Reputation < uc.policy.MinReputation
+
+	uc.mu.Lock()
+	uc.standings[userID] = standingEntry{restricted: restricted, at: now}
+	uc.mu.Unlock()
+	return restricted
+}
+
+// PurgeRateLimits удаляет вёдра, которые уже полностью наполнились, и устаревшие записи кэша
+func (uc *RateLimitUC) PurgeRateLimits(ctx context.Context) error {
+	now := uc.now().UTC()
+
+	uc.mu.Lock()
+	longest := uc.custom
+	uc.mu.Unlock()
+	for _, a := range uc.policy.Actions {
+		for _, l := range []ratelimit.Limit{a.User, a.Restricted, a.IP} {
+			longest = max(longest, l.Period)
+		}
+	}
+	n, err := uc.store.Sweep(ctx, now.Add(-longest))
+	if err != nil {
+		uc.log.Error("rate limit sweep failed", "err", err)
+		return fmt.Errorf("RateLimitUC.Purge: %w", err)
+	}
+
+	uc.mu.Lock()
+	for id, e := range uc.standings {
+		if now.Sub(e.at) >= standingTTL {
+			delete(uc.standings, id)
+		}
+	}
+	uc.mu.Unlock()
+
+	if n > 0 {
+		uc.log.Info("rate limit buckets purged", "count", n)
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	"chat-service/internal/ratelimit"
+	"chat-service/internal/usecase/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// brokenStore — хранилище лимитов, которое всегда отвечает ошибкой
+type brokenStore struct{}
+
+func (brokenStore) Take(context.Context, string, ratelimit.Limit, time.Time) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("db down")
+}
+
+func (brokenStore) Sweep(context.Context, time.Time) (int64, error) {
+	return 0, errors.New("db down")
+}
+
+func testRatePolicy() RateLimitPolicy {
+	return RateLimitPolicy{
+		Actions: map[string]ActionLimits{
+			ActionMessage: {
+				User:       ratelimit.Limit{Burst: 3, Period: time.Minute},
+				Restricted: ratelimit.Limit{Burst: 1, Period: time.Minute},
+				IP:         ratelimit.Limit{Burst: 4, Period: time.Minute},
+			},
+		},
+		NewAccountAge: 72 * time.Hour,
+		MinPosts:      5,
+	}
+}
+
+func TestRateLimitUC_Allow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	veteran := &entity.Standing{UserID: 1, CreatedAt: now.AddDate(-1, 0, 0), Posts: 100}
+
+	newUC := func(users *mocks.MockUserRepository) *RateLimitUC {
+		uc := NewRateLimitUsecase(ratelimit.NewMemoryStore(), users, testRatePolicy(), mocks.FakeLogger{})
+		uc.now = func() time.Time { return now }
+		return uc
+	}
+
+	t.Run("bucket runs out and refills", func(t *testing.T) {
+		users := mocks.NewMockUserRepository(ctrl)
+		uc := newUC(users)
+		ctx := auth.WithUser(context.Background(), 1, "user")
+		// статус пользователя читается один раз и кэшируется
+		users.EXPECT().GetStanding(ctx, int64(1)).Return(veteran, nil).Times(1)
+
+		for i := 2; i >= 0; i-- {
+			res, err := uc.Allow(ctx, ActionMessage, "10.0.0.1")
+			require.NoError(t, err)
+			require.Equal(t, i, res.Remaining)
+		}
+
+		res, err := uc.Allow(ctx, ActionMessage, "10.0.0.1")
+		require.ErrorIs(t, err, ErrRateLimited)
+		var limited *RateLimitedError
+		require.True(t, errors.As(err, &limited))
+		require.Equal(t, 20*time.Second, limited.Result.RetryAfter)
+		require.Equal(t, 0, res.Remaining)
+
+		now = now.Add(20 * time.Second)
+		_, err = uc.Allow(ctx, ActionMessage, "10.0.0.1")
+		require.NoError(t, err)
+	})
+
+	t.Run("new account gets restricted limit", func(t *testing.T) {
+		users := mocks.NewMockUserRepository(ctrl)
+		uc := newUC(users)
+		ctx := auth.WithUser(context.Background(), 2, "user")
+		users.EXPECT().GetStanding(ctx, int64(2)).
+			Return(&entity.Standing{UserID: 2, CreatedAt: now.Add(-time.Hour), Posts: 100}, nil)
+
+		res, err := uc.Allow(ctx, ActionMessage, "10.0.0.2")
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Limit.Burst)
+
+		_, err = uc.Allow(ctx, ActionMessage, "10.0.0.2")
+		require.ErrorIs(t, err, ErrRateLimited)
+	})
+
+	t.Run("few posts gets restricted limit", func(t *testing.T) {
+		users := mocks.NewMockUserRepository(ctrl)
+		uc := newUC(users)
+		ctx := auth.WithUser(context.Background(), 3, "user")
+		users.EXPECT().GetStanding(ctx, int64(3)).
+			Return(&entity.Standing{UserID: 3, CreatedAt: now.AddDate(-1, 0, 0), Posts: 4}, nil)
+
+		res, err := uc.Allow(ctx, ActionMessage, "10.0.0.3")
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Limit.Burst)
+	})
+
+	t.Run("low reputation gets restricted limit", func(t *testing.T) {
+		users := mocks.NewMockUserRepository(ctrl)
+		policy := testRatePolicy()
+		policy.MinReputation = 1
+		uc := NewRateLimitUsecase(ratelimit.NewMemoryStore(), users, policy, mocks.FakeLogger{})
+		uc.now = func() time.Time { return now }
+		ctx := auth.WithUser(context.Background(), 5, "user")
+		users.EXPECT().GetStanding(ctx, int64(5)).
+			Return(&entity.Standing{UserID: 5, CreatedAt: now.AddDate(-1, 0, 0), Posts: 100, Reputation: -3}, nil)
+
+		res, err := uc.Allow(ctx, ActionMessage, "10.0.0.5")
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Limit.Burst)
+	})
+
+	t.Run("standing error means restricted", func(t *testing.T) {
+		users := mocks.NewMockUserRepository(ctrl)
+		uc := newUC(users)
+		ctx := auth.WithUser(context.Background(), 4, "user")
+		users.EXPECT().GetStanding(ctx, int64(4)).Return(nil, errors.New("db down"))
+
+		res, err := uc.Allow(ctx, ActionMessage, "10.0.0.4")
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Limit.Burst)
+	})
+
+	t.Run("ip limit is shared between users", func(t *testing.T) {
+		users := mocks.NewMockUserRepository(ctrl)
+		uc := newUC(users)
+		users.EXPECT().GetStanding(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, id int64) (*entity.Standing, error) {
+				return &entity.Standing{UserID: id, CreatedAt: veteran.CreatedAt, Posts: 100}, nil
+			}).AnyTimes()
+
+		for id := int64(10); id < 14; id++ {
+			_, err := uc.Allow(auth.WithUser(context.Background(), id, "user"), ActionMessage, "10.0.0.5")
+			require.NoError(t, err)
+		}
+		res, err := uc.Allow(auth.WithUser(context.Background(), 14, "user"), ActionMessage, "10.0.0.5")
+		require.ErrorIs(t, err, ErrRateLimited)
+		require.Equal(t, 4, res.Limit.Burst)
+
+		// другой адрес того же пользователя не затронут
+		_, err = uc.Allow(auth.WithUser(context.Background(), 14, "user"), ActionMessage, "10.0.0.6")
+		require.NoError(t, err)
+	})
+
+	t.Run("staff and unknown actions are not limited", func(t *testing.T) {
+		uc := newUC(mocks.NewMockUserRepository(ctrl))
+
+		res, err := uc.Allow(auth.WithUser(context.Background(), 1, "moderator"), ActionMessage, "10.0.0.7")
+		require.NoError(t, err)
+		require.Nil(t, res)
+
+		res, err = uc.Allow(auth.WithUser(context.Background(), 1, "user"), "unknown", "10.0.0.7")
+		require.NoError(t, err)
+		require.Nil(t, res)
+	})
+
+	t.Run("store error does not block", func(t *testing.T) {
+		users := mocks.NewMockUserRepository(ctrl)
+		uc := NewRateLimitUsecase(brokenStore{}, users, testRatePolicy(), mocks.FakeLogger{})
+		ctx := auth.WithUser(context.Background(), 1, "user")
+		users.EXPECT().GetStanding(ctx, int64(1)).Return(veteran, nil)
+
+		res, err := uc.Allow(ctx, ActionMessage, "10.0.0.8")
+		require.NoError(t, err)
+		require.Nil(t, res)
+
+		require.Error(t, uc.PurgeRateLimits(ctx))
+	})
+}
+
+func TestRateLimitUC_PurgeRateLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := ratelimit.NewMemoryStore()
+	users := mocks.NewMockUserRepository(ctrl)
+	uc := NewRateLimitUsecase(store, users, testRatePolicy(), mocks.FakeLogger{})
+	uc.now = func() time.Time { return now }
+
+	ctx := auth.WithUser(context.Background(), 1, "user")
+	users.EXPECT().GetStanding(ctx, int64(1)).Return(&entity.Standing{UserID: 1, CreatedAt: now.AddDate(-1, 0, 0), Posts: 100}, nil)
+	_, err := uc.Allow(ctx, ActionMessage, "10.0.0.1")
+	require.NoError(t, err)
+
+	// через минуту ведро уже полное — его можно забыть
+	now = now.Add(time.Minute + time.Second)
+	require.NoError(t, uc.PurgeRateLimits(ctx))
+
+	n, err := store.Sweep(ctx, now)
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
+
+func TestRateLimitUC_AllowLimit(t *testing.T) {
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	uc := NewRateLimitUsecase(ratelimit.NewMemoryStore(), nil, testRatePolicy(), mocks.FakeLogger{})
+	uc.now = func() time.Time { return now }
+	l := ratelimit.Limit{Burst: 2, Period: time.Minute}
+	alice := auth.WithUser(context.Background(), 1, "user")
+	bob := auth.WithUser(context.Background(), 2, "user")
+
+	for i := 0; i < 2; i++ {
+		_, err := uc.AllowLimit(alice, "room:1", l)
+		require.NoError(t, err)
+	}
+	res, err := uc.AllowLimit(alice, "room:1", l)
+	var rl *RateLimitedError
+	require.ErrorAs(t, err, &rl)
+	require.False(t, res.Allowed)
+
+	// у другой комнаты и другого пользователя свои вёдра
+	_, err = uc.AllowLimit(alice, "room:2", l)
+	require.NoError(t, err)
+	_, err = uc.AllowLimit(bob, "room:1", l)
+	require.NoError(t, err)
+
+	// без лимита и для анонимов ограничений нет
+	res, err = uc.AllowLimit(alice, "room:1", ratelimit.Limit{})
+	require.NoError(t, err)
+	require.Nil(t, res)
+	res, err = uc.AllowLimit(context.Background(), "room:1", l)
+	require.NoError(t, err)
+	require.Nil(t, res)
+}
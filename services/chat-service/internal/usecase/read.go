@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	repoErr "chat-service/internal/errors"
+	"chat-service/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/logger"
+)
+
+const maxUnreadTopics = 200
+
+type ReadUC struct {
+	repo      repo.ReadRepository
+	topics    repo.TopicRepository
+	publisher UserPublisher
+	log       logger.Interface
+}
+
+func NewReadUsecase(r repo.ReadRepository, tr repo.TopicRepository, p UserPublisher, l logger.Interface) *ReadUC {
+	return &ReadUC{repo: r, topics: tr, publisher: p, log: l}
+}
+
+// MarkTopicRead сдвигает позицию чтения текущего пользователя до messageID, а при nil — до конца топика.
+// Позиция только растёт: отметка более старого сообщения ничего не меняет. Для слитого топика
+// отмечается топик, в который он слит. Другие вкладки пользователя узнают о новой позиции через личный канал.
+func (uc *ReadUC) MarkTopicRead(ctx context.Context, topicID int64, messageID *int64) (*entity.TopicRead, error) {
+	uc.log.Debug("MarkTopicRead called", "topic_id", topicID, "message_id", messageID)
+
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 {
+		return nil, ErrUnauthenticated
+	}
+
+	t, err := uc.topics.GetByID(ctx, topicID)
+	if err == nil && t.IsRedirect() {
+		t, err = uc.topics.GetByID(ctx, *t.RedirectTo)
+	}
+	if errors.Is(err, repoErr.ErrNotFound) || (err == nil && (t.IsDeleted() || t.IsRedirect())) {
+		uc.log.Info("topic not found for read", "topic_id", topicID)
+		return nil, ErrTopicNotFound
+	} else if err != nil {
+		uc.log.Error("topics.GetByID failed", "err", err)
+		return nil, fmt.Errorf("ReadUC.MarkTopicRead#topic: %w", err)
+	}
+
+	rd, err := uc.repo.MarkRead(ctx, userID, t.ID, messageID)
+	switch {
+	case errors.Is(err, repoErr.ErrNotFound):
+		uc.log.Info("message not found in topic", "topic_id", t.ID, "message_id", messageID)
+		return nil, ErrMessageNotFound
+	case errors.Is(err, repoErr.ErrInvalidReference):
+		return nil, ErrTopicNotFound
+	case err != nil:
+		uc.log.Error("repo.MarkRead failed", "err", err)
+		return nil, fmt.Errorf("ReadUC.MarkTopicRead: %w", err)
+	}
+
+	if uc.publisher != nil {
+		uc.publisher.PublishToUser(userID, &entity.WSEvent{
+			Action:    entity.ActionTopicRead,
+			TopicID:   rd.TopicID,
+			MessageID: rd.LastReadMessageID,
+		})
+	}
+
+	uc.log.Debug("topic read", "topic_id", rd.TopicID, "user_id", userID, "message_id", rd.LastReadMessageID)
+	return rd, nil
+}
+
+// Unread возвращает сводку непрочитанного текущего пользователя: итоги, разбивку по категориям
+// и не больше limit самых свежих топиков. Учитываются только топики, которые пользователь открывал.
+func (uc *ReadUC) Unread(ctx context.Context, limit int) (*entity.UnreadSummary, error) {
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 {
+		return nil, ErrUnauthenticated
+	}
+	limit = min(max(limit, 1), maxUnreadTopics)
+
+	cats, err := uc.repo.UnreadByCategory(ctx, userID)
+	if err != nil {
+		uc.log.Error("repo.UnreadByCategory failed", "err", err)
+		return nil, fmt.Errorf("ReadUC.Unread#categories: %w", err)
+	}
+	topics, err := uc.repo.UnreadTopics(ctx, userID, limit)
+	if err != nil {
+		uc.log.Error("repo.UnreadTopics failed", "err", err)
+		return nil, fmt.Errorf("ReadUC.Unread#topics: %w", err)
+	}
+
+	s := &entity.UnreadSummary{Categories: cats, TopicList: topics}
+	for _, c := range cats {
+		s.Topics += c.Topics
+		s.Messages += c.Messages
+	}
+	return s, nil
+}
+
+// AnnotateTopics заполняет Read у топиков для текущего пользователя; для анонима ничего не делает.
+// Ошибка не мешает отдать список — она логируется, а позиции остаются пустыми.
+func (uc *ReadUC) AnnotateTopics(ctx context.Context, list ...*entity.Topic) {
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 || len(list) == 0 {
+		return
+	}
+
+	ids := make([]int64, 0, len(list))
+	for _, t := range list {
+		ids = append(ids, t.ID)
+	}
+	reads, err := uc.repo.GetByTopics(ctx, userID, ids)
+	if err != nil {
+		uc.log.Error("repo.GetByTopics failed", "err", err)
+		return
+	}
+	for _, t := range list {
+		t.Read = reads[t.ID]
+	}
+}
+
+// AnnotateCategories заполняет Unread у категорий (включая Children) для текущего пользователя;
+// для анонима ничего не делает. Ошибка, как и в AnnotateTopics, только логируется.
+func (uc *ReadUC) AnnotateCategories(ctx context.Context, list []*entity.Category) {
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 || len(list) == 0 {
+		return
+	}
+
+	unread, err := uc.repo.UnreadByCategory(ctx, userID)
+	if err != nil {
+		uc.log.Error("repo.UnreadByCategory failed", "err", err)
+		return
+	}
+	byID := make(map[int64]*entity.CategoryUnread, len(unread))
+	for _, cu := range unread {
+		byID[cu.CategoryID] = cu
+	}
+
+	var walk func([]*entity.Category)
+	walk = func(cats []*entity.Category) {
+		for _, c := range cats {
+			if cu, ok := byID[c.ID]; ok {
+				c.Unread = cu
+			} else {
+				c.Unread = &entity.CategoryUnread{CategoryID: c.ID, Title: c.Title}
+			}
+			walk(c.Children)
+		}
+	}
+	walk(list)
+}
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	customErr "chat-service/internal/errors"
+	"chat-service/internal/usecase/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestReadUC_MarkTopicRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockReadRepository(ctrl)
+	topics := mocks.NewMockTopicRepository(ctrl)
+	pub := mocks.NewMockUserPublisher(ctrl)
+	uc := NewReadUsecase(repo, topics, pub, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 1, "user")
+	msgID := int64(55)
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := uc.MarkTopicRead(context.Background(), 10, nil)
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	t.Run("up to a message", func(t *testing.T) {
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(&entity.Topic{ID: 10}, nil)
+		repo.EXPECT().MarkRead(ctx, int64(1), int64(10), &msgID).
+			Return(&entity.TopicRead{UserID: 1, TopicID: 10, LastReadMessageID: 55, Unread: 3}, nil)
+		pub.EXPECT().PublishToUser(int64(1), &entity.WSEvent{Action: entity.ActionTopicRead, TopicID: 10, MessageID: 55})
+
+		rd, err := uc.MarkTopicRead(ctx, 10, &msgID)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), rd.Unread)
+	})
+
+	t.Run("merged topic marks the target", func(t *testing.T) {
+		target := int64(12)
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(&entity.Topic{ID: 10, RedirectTo: &target}, nil)
+		topics.EXPECT().GetByID(ctx, int64(12)).Return(&entity.Topic{ID: 12}, nil)
+		repo.EXPECT().MarkRead(ctx, int64(1), int64(12), nil).
+			Return(&entity.TopicRead{UserID: 1, TopicID: 12, LastReadMessageID: 70}, nil)
+		pub.EXPECT().PublishToUser(int64(1), gomock.Any())
+
+		rd, err := uc.MarkTopicRead(ctx, 10, nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(12), rd.TopicID)
+	})
+
+	t.Run("deleted topic", func(t *testing.T) {
+		deleted := time.Now()
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(&entity.Topic{ID: 10, DeletedAt: &deleted}, nil)
+		_, err := uc.MarkTopicRead(ctx, 10, nil)
+		require.ErrorIs(t, err, ErrTopicNotFound)
+	})
+
+	t.Run("missing topic", func(t *testing.T) {
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(nil, customErr.ErrNotFound)
+		_, err := uc.MarkTopicRead(ctx, 10, nil)
+		require.ErrorIs(t, err, ErrTopicNotFound)
+	})
+
+	t.Run("message from another topic", func(t *testing.T) {
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(&entity.Topic{ID: 10}, nil)
+		repo.EXPECT().MarkRead(ctx, int64(1), int64(10), &msgID).Return(nil, customErr.ErrNotFound)
+		_, err := uc.MarkTopicRead(ctx, 10, &msgID)
+		require.ErrorIs(t, err, ErrMessageNotFound)
+	})
+}
+
+func TestReadUC_Unread(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockReadRepository(ctrl)
+	uc := NewReadUsecase(repo, nil, nil, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 1, "user")
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := uc.Unread(context.Background(), 50)
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	t.Run("totals are summed over categories", func(t *testing.T) {
+		repo.EXPECT().UnreadByCategory(ctx, int64(1)).Return([]*entity.CategoryUnread{
+			{CategoryID: 1, Topics: 2, Messages: 7},
+			{CategoryID: 4, Topics: 1, Messages: 1},
+		}, nil)
+		repo.EXPECT().UnreadTopics(ctx, int64(1), maxUnreadTopics).Return([]*entity.UnreadTopic{{TopicID: 10, Unread: 5}}, nil)
+
+		s, err := uc.Unread(ctx, 1000)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), s.Topics)
+		require.Equal(t, int64(8), s.Messages)
+		require.Len(t, s.Categories, 2)
+		require.Len(t, s.TopicList, 1)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		repo.EXPECT().UnreadByCategory(ctx, int64(1)).Return(nil, errors.New("db down"))
+		_, err := uc.Unread(ctx, 50)
+		require.Error(t, err)
+	})
+}
+
+func TestReadUC_Annotate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockReadRepository(ctrl)
+	uc := NewReadUsecase(repo, nil, nil, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 1, "user")
+
+	t.Run("anonymous gets nothing", func(t *testing.T) {
+		list := []*entity.Topic{{ID: 10}}
+		uc.AnnotateTopics(context.Background(), list...)
+		uc.AnnotateCategories(context.Background(), []*entity.Category{{ID: 1}})
+		require.Nil(t, list[0].Read)
+	})
+
+	t.Run("topics", func(t *testing.T) {
+		list := []*entity.Topic{{ID: 10}, {ID: 11}}
+		repo.EXPECT().GetByTopics(ctx, int64(1), []int64{10, 11}).
+			Return(map[int64]*entity.TopicRead{10: {TopicID: 10, LastReadMessageID: 40, Unread: 2}}, nil)
+
+		uc.AnnotateTopics(ctx, list...)
+		require.Equal(t, int64(2), list[0].Read.Unread)
+		require.Nil(t, list[1].Read) // ещё не открывал
+	})
+
+	t.Run("topics keep working when the repo fails", func(t *testing.T) {
+		list := []*entity.Topic{{ID: 10}}
+		repo.EXPECT().GetByTopics(ctx, int64(1), []int64{10}).Return(nil, errors.New("db down"))
+		uc.AnnotateTopics(ctx, list...)
+		require.Nil(t, list[0].Read)
+	})
+
+	t.Run("category tree", func(t *testing.T) {
+		child := &entity.Category{ID: 2}
+		tree := []*entity.Category{{ID: 1, Children: []*entity.Category{child}}, {ID: 3}}
+		repo.EXPECT().UnreadByCategory(ctx, int64(1)).Return([]*entity.CategoryUnread{{CategoryID: 2, Topics: 1, Messages: 4}}, nil)
+
+		uc.AnnotateCategories(ctx, tree)
+		require.Equal(t, int64(4), child.Unread.Messages)
+		require.Zero(t, tree[0].Unread.Messages)
+		require.Zero(t, tree[1].Unread.Topics)
+	})
+}
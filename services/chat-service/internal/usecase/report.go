@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	repoErr "chat-service/internal/errors"
+	"chat-service/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/logger"
+)
+
+var (
+	ErrReportNotFound     = errors.New("report not found")
+	ErrReportResolved     = errors.New("report is already resolved")
+	ErrAlreadyReported    = errors.New("you have already reported this item")
+	ErrInvalidReport      = errors.New("invalid report: unknown target type or reason, or comment too long")
+	ErrInvalidResolution  = errors.New("invalid resolution: action must be dismiss, delete or block")
+	ErrReportUserNotFound = errors.New("author of the reported item not found")
+)
+
+const (
+	defaultReportLimit     = 50 // границы страницы очереди жалоб
+	maxReportLimit         = 200
+	maxReportCommentLength = 1000
+)
+
+type ReportUC struct {
+	repo      repo.ReportRepository
+	messages  repo.MessageRepository
+	topics    repo.TopicRepository
+	users     repo.AuthWebAPI
+	access    access
+	modlog    modLog
+	publisher MessagePublisher
+	log       logger.Interface
+	hideAfter int // после стольких открытых жалоб объект скрывается; 0 — не скрывать
+}
+
+func NewReportUsecase(
+	r repo.ReportRepository,
+	mr repo.MessageRepository,
+	tr repo.TopicRepository,
+	mods repo.ModeratorRepository,
+	ml repo.ModerationRepository,
+	users repo.AuthWebAPI,
+	n Notifier,
+	p MessagePublisher,
+	l logger.Interface,
+	hideAfter int,
+) *ReportUC {
+	return &ReportUC{
+		repo:      r,
+		messages:  mr,
+		topics:    tr,
+		users:     users,
+		access:    access{mods: mods},
+		modlog:    modLog{repo: ml, notify: n, log: l},
+		publisher: p,
+		log:       l,
+		hideAfter: hideAfter,
+	}
+}
+
+// CreateReport сохраняет жалобу текущего пользователя; повторная жалоба на тот же объект — ErrAlreadyReported.
+// Набрав hideAfter открытых жалоб, объект скрывается до решения модератора.
+func (uc *ReportUC) CreateReport(ctx context.Context, p ReportParams) error {
+	uc.log.Debug("CreateReport called", "target_type", p.TargetType, "target_id", p.TargetID, "reason", p.Reason)
+
+	userID, err := uc.access.check(ctx, auth.PermContentReport, nil)
+	if err != nil {
+		uc.log.Warn("report denied", "err", err)
+		return err
+	}
+	if (p.TargetType != entity.TargetMessage && p.TargetType != entity.TargetTopic) ||
+		!p.Reason.Valid() || len([]rune(p.Comment)) > maxReportCommentLength {
+		return ErrInvalidReport
+	}
+
+	rep := &entity.Report{
+		TargetType: p.TargetType,
+		TargetID:   p.TargetID,
+		ReporterID: userID,
+		Reason:     p.Reason,
+		Comment:    p.Comment,
+	}
+	hidden, err := uc.repo.Create(ctx, rep, uc.hideAfter)
+	switch {
+	case errors.Is(err, repoErr.ErrNotFound):
+		uc.log.Info("reported item not found", "target_type", p.TargetType, "target_id", p.TargetID)
+		if p.TargetType == entity.TargetMessage {
+			return ErrMessageNotFound
+		}
+		return ErrTopicNotFound
+	case errors.Is(err, repoErr.ErrConflict):
+		uc.log.Info("duplicate report", "target_type", p.TargetType, "target_id", p.TargetID, "user_id", userID)
+		return ErrAlreadyReported
+	case err != nil:
+		uc.log.Error("repo.Create failed", "err", err)
+		return fmt.Errorf("ReportUC.Create: %w", err)
+	}
+
+	if hidden {
+		if p.TargetType == entity.TargetMessage {
+			uc.publisher.Publish(rep.TopicID, &entity.WSEvent{Action: entity.ActionHidden, MessageID: p.TargetID})
+		}
+		uc.log.Info("item auto-hidden after reports",
+			"target_type", p.TargetType, "target_id", p.TargetID, "reports", rep.OpenReports)
+	}
+
+	uc.log.Info("report created", "id", rep.ID, "target_type", p.TargetType, "target_id", p.TargetID, "by", userID)
+	return nil
+}
+
+// ListReports возвращает очередь жалоб: admin видит все, модератор — только из своих категорий
+func (uc *ReportUC) ListReports(ctx context.Context, f entity.ReportFilter) ([]*entity.Report, int64, error) {
+	uc.log.Debug("ListReports called", "state", f.State, "target_type", f.TargetType)
+
+	userID, role := auth.FromContext(ctx)
+	if userID == 0 {
+		return nil, 0, ErrUnauthenticated
+	}
+	switch auth.ScopeOf(role, auth.PermReportReview) {
+	case auth.ScopeGlobal:
+		f.ModeratorID = 0
+	case auth.ScopeCategory:
+		f.ModeratorID = userID
+	default:
+		uc.log.Warn("list reports denied", "user_id", userID, "role", role)
+		return nil, 0, ErrForbidden
+	}
+
+	if f.Limit <= 0 {
+		f.Limit = defaultReportLimit
+	} else if f.Limit > maxReportLimit {
+		f.Limit = maxReportLimit
+	}
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+
+	list, total, err := uc.repo.List(ctx, f)
+	if err != nil {
+		uc.log.Error("repo.List failed", "err", err)
+		return nil, 0, fmt.Errorf("ReportUC.List: %w", err)
+	}
+
+	uc.log.Info("reports retrieved", "count", len(list), "total", total)
+	return list, total, nil
+}
+
+// ResolveReport разбирает жалобу (admin или модератор категории объекта). Решение закрывает все
+// открытые жалобы на тот же объект: dismiss снимает скрытие, delete мягко удаляет объект,
+// block блокирует автора в auth-service — это может только admin (auth.PermUserBlock).
+func (uc *ReportUC) ResolveReport(ctx context.Context, id int64, p ResolveReportParams) error {
+	uc.log.Debug("ResolveReport called", "id", id, "action", p.Action)
+
+	rep, err := uc.repo.GetByID(ctx, id)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		uc.log.Info("report not found", "id", id)
+		return ErrReportNotFound
+	} else if err != nil {
+		uc.log.Error("repo.GetByID failed", "err", err)
+		return fmt.Errorf("ReportUC.Resolve#get: %w", err)
+	}
+
+	userID, err := uc.access.check(ctx, auth.PermReportReview, inCategory(rep.CategoryID))
+	if err != nil {
+		uc.log.Warn("resolve report denied", "id", id, "err", err)
+		return err
+	}
+	if rep.State != entity.ReportOpen {
+		return ErrReportResolved
+	}
+	// блокирует auth-service, и только по слову admin: модератору отказываем до удаления и записи в журнал
+	if p.Action == ResolveBlock {
+		if _, err := uc.access.check(ctx, auth.PermUserBlock, nil); err != nil {
+			uc.log.Warn("block resolution denied", "id", id, "user_id", userID)
+			return err
+		}
+	}
+
+	state := entity.ReportActioned
+	switch p.Action {
+	case ResolveDismiss:
+		state = entity.ReportDismissed
+	case ResolveDelete:
+		if err := uc.deleteTarget(ctx, rep, userID, p.Note); err != nil {
+			return err
+		}
+	case ResolveBlock:
+		if err := uc.blockAuthor(ctx, rep, p.Note); err != nil {
+			return err
+		}
+	default:
+		return ErrInvalidResolution
+	}
+
+	_, err = uc.repo.Resolve(ctx, rep.TargetType, rep.TargetID, state, userID, p.Note)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		uc.log.Info("report resolved concurrently", "id", id)
+		return ErrReportResolved
+	} else if err != nil {
+		uc.log.Error("repo.Resolve failed", "err", err)
+		return fmt.Errorf("ReportUC.Resolve: %w", err)
+	}
+
+	if state == entity.ReportDismissed && rep.TargetHidden && rep.TargetType == entity.TargetMessage {
+		uc.publisher.Publish(rep.TopicID, &entity.WSEvent{Action: entity.ActionUnhidden, MessageID: rep.TargetID})
+	}
+
+	uc.log.Info("report resolved", "id", id, "action", p.Action, "reports", rep.OpenReports, "by", userID)
+	return nil
+}
+
+// deleteTarget мягко удаляет объект жалобы; уже удалённый объект не считается ошибкой
+func (uc *ReportUC) deleteTarget(ctx context.Context, rep *entity.Report, userID int64, reason string) error {
+	if reason == "" {
+		reason = "report: " + string(rep.Reason)
+	}
+
+	var err error
+	if rep.TargetType == entity.TargetMessage {
+		err = uc.messages.Delete(ctx, rep.TargetID, userID, reason)
+	} else {
+		err = uc.topics.Delete(ctx, rep.TargetID, userID, reason)
+	}
+	if errors.Is(err, repoErr.ErrNotFound) {
+		uc.log.Info("reported item already deleted", "target_type", rep.TargetType, "target_id", rep.TargetID)
+		return nil
+	} else if err != nil {
+		uc.log.Error("delete reported item failed", "err", err)
+		return fmt.Errorf("ReportUC.Resolve#delete: %w", err)
+	}
+
+	action := entity.ModDeleteTopic
+	if rep.TargetType == entity.TargetMessage {
+		action = entity.ModDeleteMessage
+		uc.publisher.Publish(rep.TopicID, &entity.WSEvent{Action: entity.ActionDeleted, MessageID: rep.TargetID})
+	}
+	uc.modlog.record(ctx, &entity.ModerationEntry{
+		Action:         action,
+		TargetType:     rep.TargetType,
+		TargetID:       rep.TargetID,
+		TargetAuthorID: &rep.TargetAuthorID,
+		Reason:         reason,
+		Snapshot:       map[string]any{"topic_id": rep.TopicID, "excerpt": rep.Excerpt},
+		Details:        map[string]any{"report_id": rep.ID},
+	})
+	return nil
+}
+
+// blockAuthor блокирует автора объекта жалобы через auth-service
+func (uc *ReportUC) blockAuthor(ctx context.Context, rep *entity.Report, reason string) error {
+	if reason == "" {
+		reason = "report: " + string(rep.Reason)
+	}
+
+	err := uc.users.BlockUser(ctx, rep.TargetAuthorID, reason)
+	switch {
+	case errors.Is(err, repoErr.ErrPermissionDenied):
+		uc.log.Warn("auth-service refused to block user", "user_id", rep.TargetAuthorID)
+		return ErrForbidden
+	case errors.Is(err, repoErr.ErrNotFound):
+		uc.log.Info("reported author not found", "user_id", rep.TargetAuthorID)
+		return ErrReportUserNotFound
+	case err != nil:
+		uc.log.Error("BlockUser failed", "err", err)
+		return fmt.Errorf("ReportUC.Resolve#block: %w", err)
+	}
+
+	uc.modlog.record(ctx, &entity.ModerationEntry{
+		Action:         entity.ModBlockUser,
+		TargetType:     entity.TargetUser,
+		TargetID:       rep.TargetAuthorID,
+		TargetAuthorID: &rep.TargetAuthorID,
+		Reason:         reason,
+		Snapshot:       map[string]any{"topic_id": rep.TopicID, "excerpt": rep.Excerpt},
+		Details:        map[string]any{"report_id": rep.ID, "target_type": rep.TargetType, "target_id": rep.TargetID},
+	})
+	return nil
+}
@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	customErr "chat-service/internal/errors"
+	"chat-service/internal/usecase/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const hideAfter = 3
+
+func TestReportUC_CreateReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockReportRepository(ctrl)
+	publisher := mocks.NewMockMessagePublisher(ctrl)
+	uc := NewReportUsecase(repo, nil, nil, nil, nil, nil, nil, publisher, mocks.FakeLogger{}, hideAfter)
+
+	ctx := auth.WithUser(context.Background(), 7, "user")
+	params := ReportParams{TargetType: entity.TargetMessage, TargetID: 9, Reason: entity.ReasonSpam}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		require.ErrorIs(t, uc.CreateReport(context.Background(), params), ErrUnauthenticated)
+	})
+
+	t.Run("unknown reason", func(t *testing.T) {
+		p := params
+		p.Reason = "boring"
+		require.ErrorIs(t, uc.CreateReport(ctx, p), ErrInvalidReport)
+	})
+
+	t.Run("message not found", func(t *testing.T) {
+		repo.EXPECT().Create(ctx, gomock.Any(), hideAfter).Return(false, customErr.ErrNotFound)
+		require.ErrorIs(t, uc.CreateReport(ctx, params), ErrMessageNotFound)
+	})
+
+	t.Run("topic not found", func(t *testing.T) {
+		p := ReportParams{TargetType: entity.TargetTopic, TargetID: 2, Reason: entity.ReasonOffTopic}
+		repo.EXPECT().Create(ctx, gomock.Any(), hideAfter).Return(false, customErr.ErrNotFound)
+		require.ErrorIs(t, uc.CreateReport(ctx, p), ErrTopicNotFound)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		repo.EXPECT().Create(ctx, gomock.Any(), hideAfter).Return(false, customErr.ErrConflict)
+		require.ErrorIs(t, uc.CreateReport(ctx, params), ErrAlreadyReported)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().Create(ctx, gomock.Any(), hideAfter).DoAndReturn(
+			func(_ context.Context, r *entity.Report, _ int) (bool, error) {
+				require.Equal(t, int64(7), r.ReporterID)
+				require.Equal(t, entity.ReasonSpam, r.Reason)
+				return false, nil
+			})
+		require.NoError(t, uc.CreateReport(ctx, params))
+	})
+
+	t.Run("auto-hide publishes event", func(t *testing.T) {
+		repo.EXPECT().Create(ctx, gomock.Any(), hideAfter).DoAndReturn(
+			func(_ context.Context, r *entity.Report, _ int) (bool, error) {
+				r.TopicID = 10
+				r.OpenReports = hideAfter
+				return true, nil
+			})
+		publisher.EXPECT().Publish(int64(10), &entity.WSEvent{Action: entity.ActionHidden, MessageID: 9})
+		require.NoError(t, uc.CreateReport(ctx, params))
+	})
+}
+
+func TestReportUC_ListReports(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockReportRepository(ctrl)
+	uc := NewReportUsecase(repo, nil, nil, nil, nil, nil, nil, nil, mocks.FakeLogger{}, hideAfter)
+
+	t.Run("user is forbidden", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 7, "user")
+		_, _, err := uc.ListReports(ctx, entity.ReportFilter{})
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("moderator sees own categories", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 3, "moderator")
+		want := entity.ReportFilter{State: entity.ReportOpen, ModeratorID: 3, Limit: defaultReportLimit}
+		repo.EXPECT().List(ctx, want).Return([]*entity.Report{{ID: 1}}, int64(1), nil)
+		list, total, err := uc.ListReports(ctx, entity.ReportFilter{State: entity.ReportOpen})
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Equal(t, int64(1), total)
+	})
+
+	t.Run("admin sees everything", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "admin")
+		want := entity.ReportFilter{Limit: maxReportLimit}
+		repo.EXPECT().List(ctx, want).Return(nil, int64(0), nil)
+		_, _, err := uc.ListReports(ctx, entity.ReportFilter{ModeratorID: 99, Limit: 1000})
+		require.NoError(t, err)
+	})
+}
+
+func TestReportUC_ResolveReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockReportRepository(ctrl)
+	messages := mocks.NewMockMessageRepository(ctrl)
+	mods := mocks.NewMockModeratorRepository(ctrl)
+	modlog := mocks.NewMockModerationRepository(ctrl)
+	users := mocks.NewMockAuthWebAPI(ctrl)
+	publisher := mocks.NewMockMessagePublisher(ctrl)
+	uc := NewReportUsecase(repo, messages, nil, mods, modlog, users, nil, publisher, mocks.FakeLogger{}, hideAfter)
+
+	admin := auth.WithUser(context.Background(), 1, "admin")
+	open := func() *entity.Report {
+		return &entity.Report{
+			ID: 5, TargetType: entity.TargetMessage, TargetID: 9, TopicID: 10, CategoryID: 4,
+			TargetAuthorID: 42, Reason: entity.ReasonSpam, State: entity.ReportOpen, TargetHidden: true,
+		}
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		repo.EXPECT().GetByID(admin, int64(5)).Return(nil, customErr.ErrNotFound)
+		require.ErrorIs(t, uc.ResolveReport(admin, 5, ResolveReportParams{Action: ResolveDismiss}), ErrReportNotFound)
+	})
+
+	t.Run("moderator outside category", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 3, "moderator")
+		repo.EXPECT().GetByID(ctx, int64(5)).Return(open(), nil)
+		mods.EXPECT().IsModerator(ctx, int64(3), int64(4)).Return(false, nil)
+		require.ErrorIs(t, uc.ResolveReport(ctx, 5, ResolveReportParams{Action: ResolveDismiss}), ErrForbidden)
+	})
+
+	t.Run("already resolved", func(t *testing.T) {
+		r := open()
+		r.State = entity.ReportDismissed
+		repo.EXPECT().GetByID(admin, int64(5)).Return(r, nil)
+		require.ErrorIs(t, uc.ResolveReport(admin, 5, ResolveReportParams{Action: ResolveDismiss}), ErrReportResolved)
+	})
+
+	t.Run("invalid action", func(t *testing.T) {
+		repo.EXPECT().GetByID(admin, int64(5)).Return(open(), nil)
+		require.ErrorIs(t, uc.ResolveReport(admin, 5, ResolveReportParams{Action: "ban"}), ErrInvalidResolution)
+	})
+
+	t.Run("dismiss unhides message", func(t *testing.T) {
+		repo.EXPECT().GetByID(admin, int64(5)).Return(open(), nil)
+		repo.EXPECT().Resolve(admin, entity.TargetMessage, int64(9), entity.ReportDismissed, int64(1), "fine").Return(int64(3), nil)
+		publisher.EXPECT().Publish(int64(10), &entity.WSEvent{Action: entity.ActionUnhidden, MessageID: 9})
+		require.NoError(t, uc.ResolveReport(admin, 5, ResolveReportParams{Action: ResolveDismiss, Note: "fine"}))
+	})
+
+	t.Run("moderator deletes message", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 3, "moderator")
+		repo.EXPECT().GetByID(ctx, int64(5)).Return(open(), nil)
+		mods.EXPECT().IsModerator(ctx, int64(3), int64(4)).Return(true, nil)
+		messages.EXPECT().Delete(ctx, int64(9), int64(3), "report: spam").Return(nil)
+		publisher.EXPECT().Publish(int64(10), &entity.WSEvent{Action: entity.ActionDeleted, MessageID: 9})
+		modlog.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e *entity.ModerationEntry) error {
+			require.Equal(t, entity.ModDeleteMessage, e.Action)
+			require.Equal(t, int64(42), *e.TargetAuthorID)
+			return nil
+		})
+		repo.EXPECT().Resolve(ctx, entity.TargetMessage, int64(9), entity.ReportActioned, int64(3), "").Return(int64(1), nil)
+		require.NoError(t, uc.ResolveReport(ctx, 5, ResolveReportParams{Action: ResolveDelete}))
+	})
+
+	t.Run("category moderator cannot block", func(t *testing.T) {
+		// ни BlockUser, ни Resolve: отказ до любых изменений
+		ctx := auth.WithUser(context.Background(), 3, "moderator")
+		repo.EXPECT().GetByID(ctx, int64(5)).Return(open(), nil)
+		mods.EXPECT().IsModerator(ctx, int64(3), int64(4)).Return(true, nil)
+		require.ErrorIs(t, uc.ResolveReport(ctx, 5, ResolveReportParams{Action: ResolveBlock}), ErrForbidden)
+	})
+
+	t.Run("block refused by auth-service", func(t *testing.T) {
+		repo.EXPECT().GetByID(admin, int64(5)).Return(open(), nil)
+		users.EXPECT().BlockUser(admin, int64(42), "report: spam").Return(customErr.ErrPermissionDenied)
+		require.ErrorIs(t, uc.ResolveReport(admin, 5, ResolveReportParams{Action: ResolveBlock}), ErrForbidden)
+	})
+
+	t.Run("admin blocks author", func(t *testing.T) {
+		repo.EXPECT().GetByID(admin, int64(5)).Return(open(), nil)
+		users.EXPECT().BlockUser(admin, int64(42), "spammer").Return(nil)
+		modlog.EXPECT().Save(admin, gomock.Any()).DoAndReturn(func(_ context.Context, e *entity.ModerationEntry) error {
+			require.Equal(t, entity.ModBlockUser, e.Action)
+			require.Equal(t, entity.TargetUser, e.TargetType)
+			require.Equal(t, int64(42), e.TargetID)
+			return nil
+		})
+		repo.EXPECT().Resolve(admin, entity.TargetMessage, int64(9), entity.ReportActioned, int64(1), "spammer").Return(int64(1), nil)
+		require.NoError(t, uc.ResolveReport(admin, 5, ResolveReportParams{Action: ResolveBlock, Note: "spammer"}))
+	})
+
+	t.Run("resolved concurrently", func(t *testing.T) {
+		repo.EXPECT().GetByID(admin, int64(5)).Return(open(), nil)
+		repo.EXPECT().Resolve(admin, entity.TargetMessage, int64(9), entity.ReportDismissed, int64(1), "").Return(int64(0), customErr.ErrNotFound)
+		require.ErrorIs(t, uc.ResolveReport(admin, 5, ResolveReportParams{Action: ResolveDismiss}), ErrReportResolved)
+	})
+
+	t.Run("repo error on resolve", func(t *testing.T) {
+		repo.EXPECT().GetByID(admin, int64(5)).Return(open(), nil)
+		repo.EXPECT().Resolve(admin, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(0), errors.New("fail"))
+		require.ErrorContains(t, uc.ResolveReport(admin, 5, ResolveReportParams{Action: ResolveDismiss}), "ReportUC.Resolve")
+	})
+}
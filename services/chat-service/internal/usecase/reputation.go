@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	"chat-service/internal/markdown"
+	"chat-service/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/logger"
+)
+
+var ErrInsufficientReputation = errors.New("not enough reputation")
+
+// privilegeNames — как привилегия называется в тексте ошибки
+var privilegeNames = map[string]string{
+	entity.PrivilegeCreateTopic: "create topics",
+	entity.PrivilegePostLinks:   "post links",
+	entity.PrivilegeDownvote:    "downvote",
+}
+
+// InsufficientReputationError — репутации не хватает на привилегию;
+// errors.Is(err, ErrInsufficientReputation) == true
+type InsufficientReputationError struct {
+	Privilege  string
+	Required   int64
+	Reputation int64
+}
+
+func (e *InsufficientReputationError) Error() string {
+	return fmt.Sprintf("not enough reputation to %s: %d required, you have %d",
+		privilegeNames[e.Privilege], e.Required, e.Reputation)
+}
+
+func (e *InsufficientReputationError) Unwrap() error { return ErrInsufficientReputation }
+
+// PrivilegeChecker — пороги репутации для usecase сообщений, топиков, комнат, переписок и голосов; реализуется ReputationUC
+type PrivilegeChecker interface {
+	// Require проверяет, хватает ли текущему пользователю репутации на privilege.
+	// Не хватает — *InsufficientReputationError.
+	Require(ctx context.Context, privilege string) error
+}
+
+// requirePrivilege проверяет порог репутации. Без PrivilegeChecker (в тестах, где он не важен) пороги не действуют.
+func requirePrivilege(ctx context.Context, pc PrivilegeChecker, privilege string) error {
+	if pc == nil {
+		return nil
+	}
+	return pc.Require(ctx, privilege)
+}
+
+// requireLinks требует привилегию на ссылки, если они есть хотя бы в одном из текстов.
+// Ссылка — то, что Render превратит в <a>: Markdown-ссылки, <автоссылки> и голые http(s)-адреса.
+func requireLinks(ctx context.Context, pc PrivilegeChecker, texts ...string) error {
+	for _, t := range texts {
+		if markdown.HasLinks(t) {
+			return requirePrivilege(ctx, pc, entity.PrivilegePostLinks)
+		}
+	}
+	return nil
+}
+
+// ReputationUC открывает привилегии по репутации. Репутация — сумма голосов за сообщения пользователя;
+// её ведёт VoteRepository при каждом голосе. Модераторы и админы порогов не проходят.
+type ReputationUC struct {
+	repo   repo.VoteRepository
+	policy ReputationPolicy
+	log    logger.Interface
+}
+
+func NewReputationUsecase(r repo.VoteRepository, p ReputationPolicy, l logger.Interface) *ReputationUC {
+	return &ReputationUC{repo: r, policy: p, log: l}
+}
+
+func (uc *ReputationUC) Require(ctx context.Context, privilege string) error {
+	userID, role := auth.FromContext(ctx)
+	if userID == 0 {
+		return ErrUnauthenticated
+	}
+	// нулевой порог — привилегия открыта всем, даже с отрицательной репутацией
+	required, ok := uc.policy.Thresholds[privilege]
+	if !ok || required <= 0 || role == auth.RoleModerator || role == auth.RoleAdmin {
+		return nil
+	}
+
+	rep, err := uc.repo.Reputation(ctx, userID)
+	if err != nil {
+		uc.log.Error("repo.Reputation failed", "user_id", userID, "err", err)
+		return fmt.Errorf("ReputationUC.Require: %w", err)
+	}
+	if rep < required {
+		uc.log.Info("privilege denied: not enough reputation",
+			"user_id", userID, "privilege", privilege, "reputation", rep, "required", required)
+		return &InsufficientReputationError{Privilege: privilege, Required: required, Reputation: rep}
+	}
+	return nil
+}
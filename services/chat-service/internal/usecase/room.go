@@ -0,0 +1,462 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	repoErr "chat-service/internal/errors"
+	"chat-service/internal/markdown"
+	"chat-service/internal/ratelimit"
+	"chat-service/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/logger"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxRoomPage       = 200
+	maxRoomRatePeriod = 24 * time.Hour
+)
+
+// slugPattern — постоянное имя комнаты: латиница в нижнем регистре, цифры и дефисы
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,63}$`)
+
+var (
+	ErrRoomNotFound     = errors.New("room not found")
+	ErrInvalidRoom      = errors.New("invalid room: slug must be 1-64 lowercase letters, digits or dashes and title must not be empty")
+	ErrInvalidRoomLimit = errors.New("invalid room rate limit: burst and period must both be set, period between 1s and 24h")
+	ErrRoomSlugTaken    = errors.New("room slug is already taken")
+	ErrBannedFromRooms  = errors.New("blocked users cannot write to rooms")
+)
+
+// RoomPublisher рассылает события в каналы комнат и знает, кто к ним подключён; реализуется ws.Hub
+type RoomPublisher interface {
+	PublishToRoom(roomID int64, ev *entity.WSEvent)
+	RoomViewers(roomID int64) (users []int64, anonymous int)
+}
+
+// RoomUC — постоянные общие чаты. Каждая комната может задать свой срок хранения сообщений
+// и свой лимит частоты сообщений поверх общего.
+type RoomUC struct {
+	repo      repo.RoomRepository
+	access    access
+	content   ContentChecker
+	privilege PrivilegeChecker
+	limiter   RateLimitUsecase
+	publisher RoomPublisher
+	log       logger.Interface
+}
+
+func NewRoomUsecase(r repo.RoomRepository, cc ContentChecker, pc PrivilegeChecker, rl RateLimitUsecase, p RoomPublisher, l logger.Interface) *RoomUC {
+	return &RoomUC{repo: r, content: cc, privilege: pc, limiter: rl, publisher: p, log: l}
+}
+
+// ListRooms возвращает все комнаты с числом подключённых сейчас
+func (uc *RoomUC) ListRooms(ctx context.Context) ([]*entity.Room, error) {
+	list, err := uc.repo.List(ctx)
+	if err != nil {
+		uc.log.Error("repo.List failed", "err", err)
+		return nil, fmt.Errorf("RoomUC.List: %w", err)
+	}
+	for _, rm := range list {
+		uc.annotateOnline(rm)
+	}
+	return list, nil
+}
+
+// GetRoom возвращает комнату по id
+func (uc *RoomUC) GetRoom(ctx context.Context, id int64) (*entity.Room, error) {
+	rm, err := uc.repo.GetByID(ctx, id)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		return nil, ErrRoomNotFound
+	} else if err != nil {
+		uc.log.Error("repo.GetByID failed", "err", err)
+		return nil, fmt.Errorf("RoomUC.Get: %w", err)
+	}
+	uc.annotateOnline(rm)
+	return rm, nil
+}
+
+// GetRoomBySlug возвращает комнату по постоянному имени, например "general"
+func (uc *RoomUC) GetRoomBySlug(ctx context.Context, slug string) (*entity.Room, error) {
+	rm, err := uc.repo.GetBySlug(ctx, slug)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		return nil, ErrRoomNotFound
+	} else if err != nil {
+		uc.log.Error("repo.GetBySlug failed", "err", err)
+		return nil, fmt.Errorf("RoomUC.GetBySlug: %w", err)
+	}
+	uc.annotateOnline(rm)
+	return rm, nil
+}
+
+func (uc *RoomUC) annotateOnline(rm *entity.Room) {
+	if uc.publisher == nil {
+		return
+	}
+	users, anonymous := uc.publisher.RoomViewers(rm.ID)
+	rm.Online = len(users) + anonymous
+}
+
+// CreateRoom заводит комнату (только администратор)
+func (uc *RoomUC) CreateRoom(ctx context.Context, p RoomParams) (*entity.Room, error) {
+	uc.log.Debug("CreateRoom called", "slug", p.Slug)
+
+	userID, err := uc.access.check(ctx, auth.PermRoomManage, nil)
+	if err != nil {
+		uc.log.Warn("create room denied", "err", err)
+		return nil, err
+	}
+	rm := &entity.Room{CreatedBy: &userID}
+	if err := applyRoomParams(rm, p); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Create(ctx, rm); errors.Is(err, repoErr.ErrConflict) {
+		return nil, ErrRoomSlugTaken
+	} else if err != nil {
+		uc.log.Error("repo.Create failed", "err", err)
+		return nil, fmt.Errorf("RoomUC.Create: %w", err)
+	}
+
+	uc.log.Info("room created", "id", rm.ID, "slug", rm.Slug, "by", userID)
+	return rm, nil
+}
+
+// UpdateRoom заменяет настройки комнаты (только администратор)
+func (uc *RoomUC) UpdateRoom(ctx context.Context, id int64, p RoomParams) (*entity.Room, error) {
+	uc.log.Debug("UpdateRoom called", "id", id)
+
+	userID, err := uc.access.check(ctx, auth.PermRoomManage, nil)
+	if err != nil {
+		uc.log.Warn("update room denied", "err", err)
+		return nil, err
+	}
+	rm, err := uc.GetRoom(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyRoomParams(rm, p); err != nil {
+		return nil, err
+	}
+	rm.Position = p.Position
+
+	err = uc.repo.Update(ctx, rm)
+	switch {
+	case errors.Is(err, repoErr.ErrNotFound):
+		return nil, ErrRoomNotFound
+	case errors.Is(err, repoErr.ErrConflict):
+		return nil, ErrRoomSlugTaken
+	case err != nil:
+		uc.log.Error("repo.Update failed", "err", err)
+		return nil, fmt.Errorf("RoomUC.Update: %w", err)
+	}
+
+	uc.log.Info("room updated", "id", id, "by", userID)
+	return rm, nil
+}
+
+// applyRoomParams проверяет параметры и переносит их в комнату
+func applyRoomParams(rm *entity.Room, p RoomParams) error {
+	slug, title := strings.TrimSpace(p.Slug), strings.TrimSpace(p.Title)
+	if !slugPattern.MatchString(slug) || title == "" {
+		return ErrInvalidRoom
+	}
+	if p.Retention.Mode == "" {
+		p.Retention.Mode = entity.RetentionDefault
+	}
+	if !p.Retention.Valid() {
+		return ErrInvalidRetention
+	}
+	l := p.RateLimit
+	if l.Burst < 0 || l.Period < 0 || (l.Burst > 0) != (l.Period > 0) ||
+		(l.Period > 0 && (l.Period < time.Second || l.Period > maxRoomRatePeriod)) {
+		return ErrInvalidRoomLimit
+	}
+
+	rm.Slug = slug
+	rm.Title = title
+	rm.Description = strings.TrimSpace(p.Description)
+	rm.Retention = p.Retention
+	rm.RateBurst = l.Burst
+	rm.RatePeriod = l.Period.Truncate(time.Second)
+	return nil
+}
+
+// DeleteRoom удаляет комнату со всеми сообщениями (только администратор)
+func (uc *RoomUC) DeleteRoom(ctx context.Context, id int64) error {
+	uc.log.Debug("DeleteRoom called", "id", id)
+
+	userID, err := uc.access.check(ctx, auth.PermRoomManage, nil)
+	if err != nil {
+		uc.log.Warn("delete room denied", "err", err)
+		return err
+	}
+
+	if err := uc.repo.Delete(ctx, id); errors.Is(err, repoErr.ErrNotFound) {
+		return ErrRoomNotFound
+	} else if err != nil {
+		uc.log.Error("repo.Delete failed", "err", err)
+		return fmt.Errorf("RoomUC.Delete: %w", err)
+	}
+
+	uc.log.Info("room deleted", "id", id, "by", userID)
+	return nil
+}
+
+// GetMessages возвращает не больше limit сообщений комнаты старше beforeID (0 — последние), новые сверху
+func (uc *RoomUC) GetMessages(ctx context.Context, roomID, beforeID int64, limit int) ([]*entity.RoomMessage, error) {
+	if _, err := uc.GetRoom(ctx, roomID); err != nil {
+		return nil, err
+	}
+	limit = min(max(limit, 1), maxRoomPage)
+
+	list, err := uc.repo.ListMessages(ctx, roomID, max(beforeID, 0), limit)
+	if err != nil {
+		uc.log.Error("repo.ListMessages failed", "err", err)
+		return nil, fmt.Errorf("RoomUC.GetMessages: %w", err)
+	}
+	for _, m := range list {
+		m.ContentHTML = markdown.Render(m.Content)
+	}
+	return list, nil
+}
+
+// SendMessage пишет в комнату от имени текущего пользователя. Сверх общего лимита на сообщения
+// действует лимит комнаты; заблокированные на форуме писать не могут.
+func (uc *RoomUC) SendMessage(ctx context.Context, roomID int64, content string) (*entity.RoomMessage, error) {
+	uc.log.Debug("RoomUC.SendMessage called", "room_id", roomID)
+
+	userID, err := uc.access.check(ctx, auth.PermRoomWrite, nil)
+	if err != nil {
+		return nil, err
+	}
+	rm, err := uc.GetRoom(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, ErrEmptyMessage
+	}
+	banned, err := uc.repo.IsBanned(ctx, userID)
+	if err != nil {
+		uc.log.Error("repo.IsBanned failed", "err", err)
+		return nil, fmt.Errorf("RoomUC.SendMessage#banned: %w", err)
+	}
+	if banned {
+		uc.log.Info("room message rejected: author is banned", "room_id", roomID, "user_id", userID)
+		return nil, ErrBannedFromRooms
+	}
+	if err := requireLinks(ctx, uc.privilege, content); err != nil {
+		return nil, err
+	}
+	if uc.limiter != nil {
+		if _, err := uc.limiter.AllowLimit(ctx, "room:"+strconv.FormatInt(rm.ID, 10), ratelimit.Limit{Burst: rm.RateBurst, Period: rm.RatePeriod}); err != nil {
+			return nil, err
+		}
+	}
+	// сообщения комнат проверяются фильтром, но в очередь жалоб не попадают
+	content, _, err = screen(ctx, uc.content, uc.log, userID, content, false)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &entity.RoomMessage{
+		RoomID:    rm.ID,
+		AuthorID:  userID,
+		Content:   content,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := uc.repo.CreateMessage(ctx, m); errors.Is(err, repoErr.ErrInvalidReference) {
+		// комнату удалили между проверкой и вставкой
+		return nil, ErrRoomNotFound
+	} else if err != nil {
+		uc.log.Error("repo.CreateMessage failed", "err", err)
+		return nil, fmt.Errorf("RoomUC.SendMessage: %w", err)
+	}
+	m.ContentHTML = markdown.Render(m.Content)
+
+	if uc.publisher != nil {
+		uc.publisher.PublishToRoom(rm.ID, &entity.WSEvent{Action: entity.ActionCreated, RoomID: rm.ID, RoomMessage: m})
+	}
+	uc.log.Info("room message sent", "id", m.ID, "room_id", rm.ID, "author_id", userID)
+	return m, nil
+}
+
+// roomMessage возвращает неудалённое сообщение комнаты roomID
+func (uc *RoomUC) roomMessage(ctx context.Context, roomID, id int64) (*entity.RoomMessage, error) {
+	m, err := uc.repo.GetMessage(ctx, id)
+	if errors.Is(err, repoErr.ErrNotFound) || (err == nil && (m.IsDeleted() || m.RoomID != roomID)) {
+		return nil, ErrMessageNotFound
+	} else if err != nil {
+		uc.log.Error("repo.GetMessage failed", "err", err)
+		return nil, fmt.Errorf("RoomUC.message: %w", err)
+	}
+	return m, nil
+}
+
+// UpdateMessage меняет текст своего сообщения в комнате
+func (uc *RoomUC) UpdateMessage(ctx context.Context, roomID, id int64, content string) (*entity.RoomMessage, error) {
+	uc.log.Debug("RoomUC.UpdateMessage called", "room_id", roomID, "id", id)
+
+	userID, err := uc.access.check(ctx, auth.PermRoomWrite, nil)
+	if err != nil {
+		return nil, err
+	}
+	m, err := uc.roomMessage(ctx, roomID, id)
+	if err != nil {
+		return nil, err
+	}
+	if m.AuthorID != userID {
+		return nil, ErrForbidden
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, ErrEmptyMessage
+	}
+	if err := requireLinks(ctx, uc.privilege, content); err != nil {
+		return nil, err
+	}
+	content, _, err = screen(ctx, uc.content, uc.log, userID, content, false)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if err := uc.repo.UpdateMessage(ctx, id, content, now); errors.Is(err, repoErr.ErrNotFound) {
+		return nil, ErrMessageNotFound
+	} else if err != nil {
+		uc.log.Error("repo.UpdateMessage failed", "err", err)
+		return nil, fmt.Errorf("RoomUC.UpdateMessage: %w", err)
+	}
+	m.Content = content
+	m.UpdatedAt = &now
+	m.ContentHTML = markdown.Render(m.Content)
+
+	if uc.publisher != nil {
+		uc.publisher.PublishToRoom(roomID, &entity.WSEvent{Action: entity.ActionUpdated, RoomID: roomID, RoomMessage: m})
+	}
+	uc.log.Info("room message updated", "id", id, "user_id", userID)
+	return m, nil
+}
+
+// DeleteMessage удаляет сообщение комнаты: своё — любой пишущий, чужое — модератор
+func (uc *RoomUC) DeleteMessage(ctx context.Context, roomID, id int64) error {
+	uc.log.Debug("RoomUC.DeleteMessage called", "room_id", roomID, "id", id)
+
+	userID, err := uc.access.check(ctx, auth.PermRoomWrite, nil)
+	if err != nil {
+		return err
+	}
+	m, err := uc.roomMessage(ctx, roomID, id)
+	if err != nil {
+		return err
+	}
+	if m.AuthorID != userID {
+		if _, err := uc.access.check(ctx, auth.PermRoomModerate, nil); err != nil {
+			uc.log.Warn("delete room message denied", "id", id, "user_id", userID)
+			return err
+		}
+	}
+
+	if err := uc.repo.DeleteMessage(ctx, id); errors.Is(err, repoErr.ErrNotFound) {
+		return ErrMessageNotFound
+	} else if err != nil {
+		uc.log.Error("repo.DeleteMessage failed", "err", err)
+		return fmt.Errorf("RoomUC.DeleteMessage: %w", err)
+	}
+
+	if uc.publisher != nil {
+		uc.publisher.PublishToRoom(roomID, &entity.WSEvent{Action: entity.ActionDeleted, RoomID: roomID, MessageID: id})
+	}
+	uc.log.Info("room message deleted", "id", id, "room_id", roomID, "by", userID)
+	return nil
+}
+
+// Presence возвращает, кто сейчас в комнате
+func (uc *RoomUC) Presence(ctx context.Context, roomID int64) (*entity.RoomPresence, error) {
+	if _, err := uc.GetRoom(ctx, roomID); err != nil {
+		return nil, err
+	}
+	return uc.presence(ctx, roomID)
+}
+
+func (uc *RoomUC) presence(ctx context.Context, roomID int64) (*entity.RoomPresence, error) {
+	p := &entity.RoomPresence{RoomID: roomID, Users: make([]*entity.RoomViewer, 0)}
+	if uc.publisher == nil {
+		return p, nil
+	}
+	ids, anonymous := uc.publisher.RoomViewers(roomID)
+	p.Anonymous = anonymous
+	if len(ids) == 0 {
+		return p, nil
+	}
+
+	names, err := uc.repo.UserNames(ctx, ids)
+	if err != nil {
+		uc.log.Error("repo.UserNames failed", "err", err)
+		return nil, fmt.Errorf("RoomUC.Presence: %w", err)
+	}
+	for _, id := range ids {
+		p.Users = append(p.Users, &entity.RoomViewer{UserID: id, Name: names[id]})
+	}
+	return p, nil
+}
+
+// BroadcastPresence рассылает в канал комнаты актуальный список присутствующих;
+// вызывается, когда кто-то подключился к комнате или ушёл из неё
+func (uc *RoomUC) BroadcastPresence(ctx context.Context, roomID int64) {
+	if uc.publisher == nil {
+		return
+	}
+	p, err := uc.presence(ctx, roomID)
+	if err != nil {
+		return // уже залогировано
+	}
+	uc.publisher.PublishToRoom(roomID, &entity.WSEvent{Action: entity.ActionPresence, RoomID: roomID, Presence: p})
+}
+
+// CleanupRoomMessages удаляет сообщения, вышедшие за политику хранения своей комнаты, пачками по batch (для cron).
+// Комнаты без своей политики чистятся по глобальному порогу threshold.
+func (uc *RoomUC) CleanupRoomMessages(ctx context.Context, threshold time.Time, batch int) error {
+	uc.log.Debug("CleanupRoomMessages called", "threshold", threshold)
+
+	if batch <= 0 {
+		batch = defaultCleanupBatch
+	}
+	now := time.Now().UTC()
+	var total int64
+	for {
+		n, err := uc.repo.DeleteExpired(ctx, threshold, now, batch)
+		if err != nil {
+			uc.log.Error("repo.DeleteExpired failed", "err", err, "deleted_so_far", total)
+			return fmt.Errorf("RoomUC.Cleanup: %w", err)
+		}
+		total += n
+		if n < int64(batch) {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			uc.log.Warn("room cleanup interrupted", "err", err, "deleted_so_far", total)
+			return fmt.Errorf("RoomUC.Cleanup: %w", err)
+		}
+	}
+
+	uc.log.Info("old room messages deleted", "threshold", threshold, "count", total)
+	return nil
+}
+
+// PurgeDeletedRoomMessages окончательно удаляет сообщения комнат, удалённые раньше threshold (для cron)
+func (uc *RoomUC) PurgeDeletedRoomMessages(ctx context.Context, threshold time.Time) error {
+	n, err := uc.repo.PurgeDeleted(ctx, threshold)
+	if err != nil {
+		uc.log.Error("repo.PurgeDeleted failed", "err", err)
+		return fmt.Errorf("RoomUC.PurgeDeleted: %w", err)
+	}
+
+	uc.log.Info("deleted room messages purged", "threshold", threshold, "count", n)
+	return nil
+}
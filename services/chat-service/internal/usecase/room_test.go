@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	customErr "chat-service/internal/errors"
+	"chat-service/internal/ratelimit"
+	"chat-service/internal/usecase/mocks"
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestRoomUC_CreateRoom(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRoomRepository(ctrl)
+	uc := NewRoomUsecase(repo, nil, nil, nil, nil, mocks.FakeLogger{})
+	admin := auth.WithUser(context.Background(), 1, "admin")
+
+	t.Run("moderator is forbidden", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 2, "moderator")
+		_, err := uc.CreateRoom(ctx, RoomParams{Slug: "offtopic", Title: "Оффтоп"})
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("invalid slug", func(t *testing.T) {
+		_, err := uc.CreateRoom(admin, RoomParams{Slug: "Off Topic", Title: "Оффтоп"})
+		require.ErrorIs(t, err, ErrInvalidRoom)
+	})
+
+	t.Run("half-set rate limit", func(t *testing.T) {
+		_, err := uc.CreateRoom(admin, RoomParams{Slug: "offtopic", Title: "Оффтоп", RateLimit: ratelimit.Limit{Burst: 5}})
+		require.ErrorIs(t, err, ErrInvalidRoomLimit)
+	})
+
+	t.Run("slug taken", func(t *testing.T) {
+		repo.EXPECT().Create(admin, gomock.Any()).Return(customErr.ErrConflict)
+		_, err := uc.CreateRoom(admin, RoomParams{Slug: "general", Title: "Общий чат"})
+		require.ErrorIs(t, err, ErrRoomSlugTaken)
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		repo.EXPECT().Create(admin, gomock.Any()).DoAndReturn(func(_ context.Context, rm *entity.Room) error {
+			require.Equal(t, "offtopic", rm.Slug)
+			require.Equal(t, entity.RetentionDefault, rm.Retention.Mode)
+			require.Equal(t, 5, rm.RateBurst)
+			require.Equal(t, 30*time.Second, rm.RatePeriod)
+			rm.ID = 7
+			return nil
+		})
+		rm, err := uc.CreateRoom(admin, RoomParams{
+			Slug:      " offtopic ",
+			Title:     "Оффтоп",
+			RateLimit: ratelimit.Limit{Burst: 5, Period: 30 * time.Second},
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(7), rm.ID)
+		require.Equal(t, int64(1), *rm.CreatedBy)
+	})
+}
+
+func TestRoomUC_SendMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRoomRepository(ctrl)
+	users := mocks.NewMockUserRepository(ctrl)
+	pub := mocks.NewMockRoomPublisher(ctrl)
+	rl := NewRateLimitUsecase(ratelimit.NewMemoryStore(), users, testRatePolicy(), mocks.FakeLogger{})
+	uc := NewRoomUsecase(repo, nil, nil, rl, pub, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 1, "user")
+	room := &entity.Room{ID: 3, Slug: "general", RateBurst: 1, RatePeriod: time.Minute}
+
+	pub.EXPECT().RoomViewers(int64(3)).Return(nil, 0).AnyTimes()
+
+	t.Run("unknown room", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(9)).Return(nil, customErr.ErrNotFound)
+		_, err := uc.SendMessage(ctx, 9, "привет")
+		require.ErrorIs(t, err, ErrRoomNotFound)
+	})
+
+	t.Run("banned author", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(3)).Return(room, nil)
+		repo.EXPECT().IsBanned(ctx, int64(1)).Return(true, nil)
+		_, err := uc.SendMessage(ctx, 3, "привет")
+		require.ErrorIs(t, err, ErrBannedFromRooms)
+	})
+
+	t.Run("sent and published", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(3)).Return(room, nil)
+		repo.EXPECT().IsBanned(ctx, int64(1)).Return(false, nil)
+		repo.EXPECT().CreateMessage(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, m *entity.RoomMessage) error {
+			m.ID = 11
+			return nil
+		})
+		pub.EXPECT().PublishToRoom(int64(3), gomock.Any()).Do(func(_ int64, ev *entity.WSEvent) {
+			require.Equal(t, entity.ActionCreated, ev.Action)
+			require.Equal(t, int64(11), ev.RoomMessage.ID)
+		})
+		m, err := uc.SendMessage(ctx, 3, "**привет**")
+		require.NoError(t, err)
+		require.Contains(t, m.ContentHTML, "<strong>")
+	})
+
+	t.Run("room limit exhausted", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(3)).Return(room, nil)
+		repo.EXPECT().IsBanned(ctx, int64(1)).Return(false, nil)
+		_, err := uc.SendMessage(ctx, 3, "ещё раз")
+		require.ErrorIs(t, err, ErrRateLimited)
+	})
+
+	t.Run("moderators are not limited", func(t *testing.T) {
+		mod := auth.WithUser(context.Background(), 2, "moderator")
+		for i := 0; i < 3; i++ {
+			repo.EXPECT().GetByID(mod, int64(3)).Return(room, nil)
+			repo.EXPECT().IsBanned(mod, int64(2)).Return(false, nil)
+			repo.EXPECT().CreateMessage(mod, gomock.Any()).Return(nil)
+			pub.EXPECT().PublishToRoom(int64(3), gomock.Any())
+			_, err := uc.SendMessage(mod, 3, "модерирую")
+			require.NoError(t, err)
+		}
+	})
+}
+
+func TestRoomUC_EditAndDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRoomRepository(ctrl)
+	pub := mocks.NewMockRoomPublisher(ctrl)
+	uc := NewRoomUsecase(repo, nil, nil, nil, pub, mocks.FakeLogger{})
+	author := auth.WithUser(context.Background(), 1, "user")
+	other := auth.WithUser(context.Background(), 2, "user")
+	mod := auth.WithUser(context.Background(), 3, "moderator")
+	msg := func() *entity.RoomMessage {
+		return &entity.RoomMessage{ID: 5, RoomID: 3, AuthorID: 1, Content: "текст"}
+	}
+
+	t.Run("edit someone else's message", func(t *testing.T) {
+		repo.EXPECT().GetMessage(other, int64(5)).Return(msg(), nil)
+		_, err := uc.UpdateMessage(other, 3, 5, "правка")
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("message from another room", func(t *testing.T) {
+		repo.EXPECT().GetMessage(author, int64(5)).Return(msg(), nil)
+		_, err := uc.UpdateMessage(author, 4, 5, "правка")
+		require.ErrorIs(t, err, ErrMessageNotFound)
+	})
+
+	t.Run("user cannot delete someone else's message", func(t *testing.T) {
+		repo.EXPECT().GetMessage(other, int64(5)).Return(msg(), nil)
+		err := uc.DeleteMessage(other, 3, 5)
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("moderator deletes someone else's message", func(t *testing.T) {
+		repo.EXPECT().GetMessage(mod, int64(5)).Return(msg(), nil)
+		repo.EXPECT().DeleteMessage(mod, int64(5)).Return(nil)
+		pub.EXPECT().PublishToRoom(int64(3), &entity.WSEvent{Action: entity.ActionDeleted, RoomID: 3, MessageID: 5})
+		require.NoError(t, uc.DeleteMessage(mod, 3, 5))
+	})
+}
+
+func TestRoomUC_Presence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRoomRepository(ctrl)
+	pub := mocks.NewMockRoomPublisher(ctrl)
+	uc := NewRoomUsecase(repo, nil, nil, nil, pub, mocks.FakeLogger{})
+	ctx := context.Background()
+
+	repo.EXPECT().GetByID(ctx, int64(3)).Return(&entity.Room{ID: 3}, nil)
+	pub.EXPECT().RoomViewers(int64(3)).Return([]int64{1, 2}, 4).Times(2)
+	repo.EXPECT().UserNames(ctx, []int64{1, 2}).Return(map[int64]string{1: "alice", 2: "bob"}, nil)
+
+	p, err := uc.Presence(ctx, 3)
+	require.NoError(t, err)
+	require.Equal(t, 4, p.Anonymous)
+	require.Equal(t, []*entity.RoomViewer{{UserID: 1, Name: "alice"}, {UserID: 2, Name: "bob"}}, p.Users)
+}
+
+func TestRoomUC_Links(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRoomRepository(ctrl)
+	privileges := mocks.NewMockPrivilegeChecker(ctrl)
+	pub := mocks.NewMockRoomPublisher(ctrl)
+	uc := NewRoomUsecase(repo, nil, privileges, nil, pub, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 1, "user")
+	room := &entity.Room{ID: 3, Slug: "general"}
+	denied := &InsufficientReputationError{Privilege: entity.PrivilegePostLinks, Required: 3}
+
+	pub.EXPECT().RoomViewers(int64(3)).Return(nil, 0).AnyTimes()
+
+	t.Run("message with a link needs reputation", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(3)).Return(room, nil)
+		repo.EXPECT().IsBanned(ctx, int64(1)).Return(false, nil)
+		privileges.EXPECT().Require(ctx, entity.PrivilegePostLinks).Return(denied)
+
+		_, err := uc.SendMessage(ctx, 3, "see https://example.com")
+		require.ErrorIs(t, err, ErrInsufficientReputation)
+	})
+
+	t.Run("message without links is not checked", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(3)).Return(room, nil)
+		repo.EXPECT().IsBanned(ctx, int64(1)).Return(false, nil)
+		repo.EXPECT().CreateMessage(ctx, gomock.Any()).Return(nil)
+		pub.EXPECT().PublishToRoom(int64(3), gomock.Any())
+
+		_, err := uc.SendMessage(ctx, 3, "see `https://example.com`")
+		require.NoError(t, err)
+	})
+
+	t.Run("link added by an edit needs reputation", func(t *testing.T) {
+		repo.EXPECT().GetMessage(ctx, int64(5)).Return(&entity.RoomMessage{ID: 5, RoomID: 3, AuthorID: 1, Content: "текст"}, nil)
+		privileges.EXPECT().Require(ctx, entity.PrivilegePostLinks).Return(denied)
+
+		_, err := uc.UpdateMessage(ctx, 3, 5, "[текст](https://example.com)")
+		require.ErrorIs(t, err, ErrInsufficientReputation)
+	})
+}
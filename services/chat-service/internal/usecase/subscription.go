@@ -0,0 +1,273 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	repoErr "chat-service/internal/errors"
+	"chat-service/internal/mailer"
+	"chat-service/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/logger"
+	"time"
+)
+
+var (
+	ErrNotFollowing           = errors.New("you are not following this")
+	ErrInvalidDigestFrequency = errors.New("invalid digest frequency: must be off, daily or weekly")
+	ErrInvalidUnsubscribe     = errors.New("unsubscribe link is invalid")
+)
+
+const digestBatch = 100 // столько подписчиков cron берёт из базы за раз
+
+// DigestOptions — откуда брать ссылки для писем и сколько в дайджест попадает
+type DigestOptions struct {
+	PublicURL        string // адрес chat-service: ссылка отписки
+	SiteURL          string // адрес фронтенда: ссылки на топики
+	MaxTopics        int
+	MessagesPerTopic int
+}
+
+type SubscriptionUC struct {
+	repo   repo.SubscriptionRepository
+	topics repo.TopicRepository
+	mailer mailer.Mailer
+	opts   DigestOptions
+	log    logger.Interface
+}
+
+func NewSubscriptionUsecase(r repo.SubscriptionRepository, tr repo.TopicRepository, m mailer.Mailer, opts DigestOptions, l logger.Interface) *SubscriptionUC {
+	return &SubscriptionUC{repo: r, topics: tr, mailer: m, opts: opts, log: l}
+}
+
+// FollowTopic подписывает текущего пользователя на топик; подписка на слитый топик переходит туда, куда он слит.
+// Повторная подписка ничего не меняет.
+func (uc *SubscriptionUC) FollowTopic(ctx context.Context, topicID int64) error {
+	uc.log.Debug("FollowTopic called", "topic_id", topicID)
+
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 {
+		return ErrUnauthenticated
+	}
+
+	t, err := uc.topics.GetByID(ctx, topicID)
+	if err == nil && t.IsRedirect() {
+		t, err = uc.topics.GetByID(ctx, *t.RedirectTo)
+	}
+	if errors.Is(err, repoErr.ErrNotFound) || (err == nil && (t.IsDeleted() || t.IsRedirect())) {
+		uc.log.Info("topic not found for follow", "topic_id", topicID)
+		return ErrTopicNotFound
+	} else if err != nil {
+		uc.log.Error("topics.GetByID failed", "err", err)
+		return fmt.Errorf("SubscriptionUC.FollowTopic#topic: %w", err)
+	}
+
+	err = uc.repo.Follow(ctx, &entity.Subscription{UserID: userID, TopicID: &t.ID})
+	if errors.Is(err, repoErr.ErrInvalidReference) {
+		return ErrTopicNotFound
+	} else if err != nil {
+		uc.log.Error("repo.Follow failed", "err", err)
+		return fmt.Errorf("SubscriptionUC.FollowTopic: %w", err)
+	}
+
+	uc.log.Info("topic followed", "topic_id", t.ID, "user_id", userID)
+	return nil
+}
+
+// UnfollowTopic отписывает текущего пользователя от топика
+func (uc *SubscriptionUC) UnfollowTopic(ctx context.Context, topicID int64) error {
+	return uc.unfollow(ctx, &topicID, nil)
+}
+
+// FollowCategory подписывает текущего пользователя на категорию вместе с подкатегориями
+func (uc *SubscriptionUC) FollowCategory(ctx context.Context, categoryID int64) error {
+	uc.log.Debug("FollowCategory called", "category_id", categoryID)
+
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 {
+		return ErrUnauthenticated
+	}
+
+	err := uc.repo.Follow(ctx, &entity.Subscription{UserID: userID, CategoryID: &categoryID})
+	if errors.Is(err, repoErr.ErrInvalidReference) {
+		uc.log.Info("category not found for follow", "category_id", categoryID)
+		return ErrCategoryNotFound
+	} else if err != nil {
+		uc.log.Error("repo.Follow failed", "err", err)
+		return fmt.Errorf("SubscriptionUC.FollowCategory: %w", err)
+	}
+
+	uc.log.Info("category followed", "category_id", categoryID, "user_id", userID)
+	return nil
+}
+
+// UnfollowCategory отписывает текущего пользователя от категории
+func (uc *SubscriptionUC) UnfollowCategory(ctx context.Context, categoryID int64) error {
+	return uc.unfollow(ctx, nil, &categoryID)
+}
+
+func (uc *SubscriptionUC) unfollow(ctx context.Context, topicID, categoryID *int64) error {
+	uc.log.Debug("Unfollow called", "topic_id", topicID, "category_id", categoryID)
+
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 {
+		return ErrUnauthenticated
+	}
+
+	err := uc.repo.Unfollow(ctx, userID, topicID, categoryID)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		return ErrNotFollowing
+	} else if err != nil {
+		uc.log.Error("repo.Unfollow failed", "err", err)
+		return fmt.Errorf("SubscriptionUC.Unfollow: %w", err)
+	}
+
+	uc.log.Info("unfollowed", "topic_id", topicID, "category_id", categoryID, "user_id", userID)
+	return nil
+}
+
+// ListFollows возвращает подписки текущего пользователя
+func (uc *SubscriptionUC) ListFollows(ctx context.Context) ([]*entity.Subscription, error) {
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 {
+		return nil, ErrUnauthenticated
+	}
+
+	list, err := uc.repo.ListByUser(ctx, userID)
+	if err != nil {
+		uc.log.Error("repo.ListByUser failed", "err", err)
+		return nil, fmt.Errorf("SubscriptionUC.ListFollows: %w", err)
+	}
+	return list, nil
+}
+
+// GetDigestSettings возвращает настройки дайджеста текущего пользователя.
+// До первой подписки настроек нет — возвращаются значения по умолчанию.
+func (uc *SubscriptionUC) GetDigestSettings(ctx context.Context) (*entity.DigestSettings, error) {
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 {
+		return nil, ErrUnauthenticated
+	}
+
+	s, err := uc.repo.GetSettings(ctx, userID)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		return &entity.DigestSettings{UserID: userID, Frequency: entity.DigestDaily}, nil
+	} else if err != nil {
+		uc.log.Error("repo.GetSettings failed", "err", err)
+		return nil, fmt.Errorf("SubscriptionUC.GetDigestSettings: %w", err)
+	}
+	return s, nil
+}
+
+// UpdateDigestSettings меняет частоту дайджеста текущего пользователя: off, daily или weekly
+func (uc *SubscriptionUC) UpdateDigestSettings(ctx context.Context, frequency string) (*entity.DigestSettings, error) {
+	uc.log.Debug("UpdateDigestSettings called", "frequency", frequency)
+
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 {
+		return nil, ErrUnauthenticated
+	}
+	switch frequency {
+	case entity.DigestOff, entity.DigestDaily, entity.DigestWeekly:
+	default:
+		return nil, ErrInvalidDigestFrequency
+	}
+
+	s := &entity.DigestSettings{UserID: userID, Frequency: frequency}
+	if err := uc.repo.SaveSettings(ctx, s); err != nil {
+		uc.log.Error("repo.SaveSettings failed", "err", err)
+		return nil, fmt.Errorf("SubscriptionUC.UpdateDigestSettings: %w", err)
+	}
+
+	uc.log.Info("digest settings updated", "user_id", userID, "frequency", frequency)
+	return s, nil
+}
+
+// Unsubscribe выключает дайджест по токену из ссылки в письме; вход не нужен
+func (uc *SubscriptionUC) Unsubscribe(ctx context.Context, token string) error {
+	if token == "" {
+		return ErrInvalidUnsubscribe
+	}
+
+	err := uc.repo.Unsubscribe(ctx, token)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		uc.log.Info("unknown unsubscribe token")
+		return ErrInvalidUnsubscribe
+	} else if err != nil {
+		uc.log.Error("repo.Unsubscribe failed", "err", err)
+		return fmt.Errorf("SubscriptionUC.Unsubscribe: %w", err)
+	}
+
+	uc.log.Info("digest unsubscribed by link")
+	return nil
+}
+
+// SendDigests отправляет дайджесты всем, кому к now пора, и возвращает число отправленных писем.
+// Если за период ничего нового нет, письмо не отправляется, но период всё равно считается пройденным.
+// Неудачная отправка повторится при следующем запуске.
+func (uc *SubscriptionUC) SendDigests(ctx context.Context, now time.Time) (int, error) {
+	var (
+		sent  int
+		after int64
+	)
+	for {
+		list, err := uc.repo.DueRecipients(ctx, now, after, digestBatch)
+		if err != nil {
+			uc.log.Error("repo.DueRecipients failed", "err", err)
+			return sent, fmt.Errorf("SubscriptionUC.SendDigests: %w", err)
+		}
+		for _, rc := range list {
+			after = rc.Settings.UserID
+			ok, err := uc.sendDigest(ctx, rc, now)
+			if ok {
+				sent++
+			}
+			if err != nil {
+				uc.log.Error("digest failed", "user_id", rc.Settings.UserID, "err", err)
+			}
+		}
+		if len(list) < digestBatch {
+			break
+		}
+	}
+
+	uc.log.Info("digests sent", "count", sent)
+	return sent, nil
+}
+
+// sendDigest собирает и отправляет дайджест одному подписчику; false — новостей не было
+func (uc *SubscriptionUC) sendDigest(ctx context.Context, rc *entity.DigestRecipient, now time.Time) (bool, error) {
+	topics, err := uc.repo.DigestTopics(ctx, rc.Settings.UserID, digestSince(&rc.Settings, now), now,
+		uc.opts.MaxTopics, uc.opts.MessagesPerTopic)
+	if err != nil {
+		return false, fmt.Errorf("topics: %w", err)
+	}
+	if len(topics) > 0 {
+		msg, err := uc.composeDigest(rc, topics)
+		if err != nil {
+			return false, fmt.Errorf("compose: %w", err)
+		}
+		if err := uc.mailer.Send(ctx, msg); err != nil {
+			return false, fmt.Errorf("send: %w", err)
+		}
+	}
+	if err := uc.repo.MarkSent(ctx, rc.Settings.UserID, now); err != nil {
+		// письмо уже ушло: в худшем случае следующий дайджест повторит часть новостей
+		return len(topics) > 0, fmt.Errorf("mark sent: %w", err)
+	}
+	return len(topics) > 0, nil
+}
+
+// digestSince — с какого момента собирать новости: с прошлого дайджеста, если cron пропускал запуски,
+// но не дальше двух периодов назад — после долгого перерыва старые новости не присылаются
+func digestSince(s *entity.DigestSettings, now time.Time) time.Time {
+	oldest := now.Add(-2 * s.Period())
+	if s.LastSentAt == nil {
+		return now.Add(-s.Period())
+	}
+	if s.LastSentAt.Before(oldest) {
+		return oldest
+	}
+	return *s.LastSentAt
+}
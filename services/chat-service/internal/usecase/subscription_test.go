@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	customErr "chat-service/internal/errors"
+	"chat-service/internal/mailer"
+	"chat-service/internal/usecase/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+var digestOpts = DigestOptions{PublicURL: "https://api.example.com", SiteURL: "https://forum.example.com", MaxTopics: 20, MessagesPerTopic: 3}
+
+func TestSubscriptionUC_Follow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockSubscriptionRepository(ctrl)
+	topics := mocks.NewMockTopicRepository(ctrl)
+	uc := NewSubscriptionUsecase(repo, topics, nil, digestOpts, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 1, "user")
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		require.ErrorIs(t, uc.FollowTopic(context.Background(), 10), ErrUnauthenticated)
+		require.ErrorIs(t, uc.FollowCategory(context.Background(), 4), ErrUnauthenticated)
+	})
+
+	t.Run("topic", func(t *testing.T) {
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(&entity.Topic{ID: 10}, nil)
+		repo.EXPECT().Follow(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, s *entity.Subscription) error {
+			require.Equal(t, int64(1), s.UserID)
+			require.Equal(t, int64(10), *s.TopicID)
+			require.Nil(t, s.CategoryID)
+			return nil
+		})
+		require.NoError(t, uc.FollowTopic(ctx, 10))
+	})
+
+	t.Run("merged topic follows the target", func(t *testing.T) {
+		target := int64(12)
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(&entity.Topic{ID: 10, RedirectTo: &target}, nil)
+		topics.EXPECT().GetByID(ctx, int64(12)).Return(&entity.Topic{ID: 12}, nil)
+		repo.EXPECT().Follow(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, s *entity.Subscription) error {
+			require.Equal(t, int64(12), *s.TopicID)
+			return nil
+		})
+		require.NoError(t, uc.FollowTopic(ctx, 10))
+	})
+
+	t.Run("deleted topic", func(t *testing.T) {
+		deleted := time.Now()
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(&entity.Topic{ID: 10, DeletedAt: &deleted}, nil)
+		require.ErrorIs(t, uc.FollowTopic(ctx, 10), ErrTopicNotFound)
+	})
+
+	t.Run("missing topic", func(t *testing.T) {
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(nil, customErr.ErrNotFound)
+		require.ErrorIs(t, uc.FollowTopic(ctx, 10), ErrTopicNotFound)
+	})
+
+	t.Run("category", func(t *testing.T) {
+		repo.EXPECT().Follow(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, s *entity.Subscription) error {
+			require.Nil(t, s.TopicID)
+			require.Equal(t, int64(4), *s.CategoryID)
+			return nil
+		})
+		require.NoError(t, uc.FollowCategory(ctx, 4))
+	})
+
+	t.Run("missing category", func(t *testing.T) {
+		repo.EXPECT().Follow(ctx, gomock.Any()).Return(customErr.ErrInvalidReference)
+		require.ErrorIs(t, uc.FollowCategory(ctx, 4), ErrCategoryNotFound)
+	})
+
+	t.Run("unfollow", func(t *testing.T) {
+		topicID, categoryID := int64(10), int64(4)
+		repo.EXPECT().Unfollow(ctx, int64(1), &topicID, nil).Return(nil)
+		require.NoError(t, uc.UnfollowTopic(ctx, 10))
+
+		repo.EXPECT().Unfollow(ctx, int64(1), nil, &categoryID).Return(customErr.ErrNotFound)
+		require.ErrorIs(t, uc.UnfollowCategory(ctx, 4), ErrNotFollowing)
+	})
+}
+
+func TestSubscriptionUC_DigestSettings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockSubscriptionRepository(ctrl)
+	uc := NewSubscriptionUsecase(repo, nil, nil, digestOpts, mocks.FakeLogger{})
+	ctx := auth.WithUser(context.Background(), 1, "user")
+
+	t.Run("daily before the first follow", func(t *testing.T) {
+		repo.EXPECT().GetSettings(ctx, int64(1)).Return(nil, customErr.ErrNotFound)
+		s, err := uc.GetDigestSettings(ctx)
+		require.NoError(t, err)
+		require.Equal(t, entity.DigestDaily, s.Frequency)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		repo.EXPECT().SaveSettings(ctx, &entity.DigestSettings{UserID: 1, Frequency: entity.DigestWeekly}).Return(nil)
+		s, err := uc.UpdateDigestSettings(ctx, entity.DigestWeekly)
+		require.NoError(t, err)
+		require.Equal(t, entity.DigestWeekly, s.Frequency)
+	})
+
+	t.Run("invalid frequency", func(t *testing.T) {
+		_, err := uc.UpdateDigestSettings(ctx, "hourly")
+		require.ErrorIs(t, err, ErrInvalidDigestFrequency)
+	})
+
+	t.Run("unsubscribe", func(t *testing.T) {
+		repo.EXPECT().Unsubscribe(gomock.Any(), "tok").Return(nil)
+		require.NoError(t, uc.Unsubscribe(context.Background(), "tok"))
+
+		repo.EXPECT().Unsubscribe(gomock.Any(), "bad").Return(customErr.ErrNotFound)
+		require.ErrorIs(t, uc.Unsubscribe(context.Background(), "bad"), ErrInvalidUnsubscribe)
+		require.ErrorIs(t, uc.Unsubscribe(context.Background(), ""), ErrInvalidUnsubscribe)
+	})
+}
+
+func TestSubscriptionUC_SendDigests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockSubscriptionRepository(ctrl)
+	mail := mocks.NewMockMailer(ctrl)
+	uc := NewSubscriptionUsecase(repo, nil, mail, digestOpts, mocks.FakeLogger{})
+	ctx := context.Background()
+	now := time.Date(2030, 1, 2, 8, 0, 0, 0, time.UTC)
+	lastSent := now.Add(-24 * time.Hour)
+
+	alice := &entity.DigestRecipient{
+		Settings: entity.DigestSettings{UserID: 2, Frequency: entity.DigestDaily, LastSentAt: &lastSent, UnsubscribeToken: "tok-2"},
+		Name:     "Алиса",
+		Email:    "alice@example.com",
+	}
+	bob := &entity.DigestRecipient{
+		Settings: entity.DigestSettings{UserID: 3, Frequency: entity.DigestWeekly, UnsubscribeToken: "tok-3"},
+		Name:     "bob",
+		Email:    "bob@example.com",
+	}
+
+	t.Run("sends only when there is news", func(t *testing.T) {
+		repo.EXPECT().DueRecipients(ctx, now, int64(0), digestBatch).Return([]*entity.DigestRecipient{alice, bob}, nil)
+		repo.EXPECT().DigestTopics(ctx, int64(2), lastSent, now, 20, 3).Return([]*entity.DigestTopic{{
+			TopicID:     10,
+			Title:       "Новости <и> слухи",
+			NewMessages: 5,
+			Messages:    []*entity.Message{{AuthorName: "carol", Content: "всем привет", CreatedAt: now.Add(-time.Hour)}},
+		}}, nil)
+		mail.EXPECT().Send(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, m *mailer.Message) error {
+			require.Contains(t, m.To, "alice@example.com")
+			require.Equal(t, "Дайджест форума за день", m.Subject)
+			require.Contains(t, m.Text, "Новости <и> слухи")
+			require.Contains(t, m.Text, "5 сообщений: https://forum.example.com/topics/10")
+			require.Contains(t, m.Text, "carol")
+			require.Contains(t, m.HTML, "Новости &lt;и&gt; слухи")
+			require.Contains(t, m.HTML, "всем привет")
+			require.Contains(t, m.HTML, "https://api.example.com/digest/unsubscribe?token=tok-2")
+			require.Equal(t, "<https://api.example.com/digest/unsubscribe?token=tok-2>", m.Headers["List-Unsubscribe"])
+			require.Equal(t, "List-Unsubscribe=One-Click", m.Headers["List-Unsubscribe-Post"])
+			return nil
+		})
+		repo.EXPECT().MarkSent(ctx, int64(2), now).Return(nil)
+		// первый недельный дайджест — за последнюю неделю; новостей нет, письмо не нужно
+		repo.EXPECT().DigestTopics(ctx, int64(3), now.Add(-7*24*time.Hour), now, 20, 3).Return(nil, nil)
+		repo.EXPECT().MarkSent(ctx, int64(3), now).Return(nil)
+
+		sent, err := uc.SendDigests(ctx, now)
+		require.NoError(t, err)
+		require.Equal(t, 1, sent)
+	})
+
+	t.Run("failed send is retried next time", func(t *testing.T) {
+		repo.EXPECT().DueRecipients(ctx, now, int64(0), digestBatch).Return([]*entity.DigestRecipient{alice}, nil)
+		repo.EXPECT().DigestTopics(ctx, int64(2), lastSent, now, 20, 3).Return([]*entity.DigestTopic{{TopicID: 10, Title: "x", IsNew: true}}, nil)
+		mail.EXPECT().Send(ctx, gomock.Any()).Return(errors.New("smtp down"))
+
+		sent, err := uc.SendDigests(ctx, now)
+		require.NoError(t, err)
+		require.Zero(t, sent)
+	})
+
+	t.Run("recipients are paged", func(t *testing.T) {
+		page := make([]*entity.DigestRecipient, digestBatch)
+		for i := range page {
+			page[i] = &entity.DigestRecipient{Settings: entity.DigestSettings{UserID: int64(i + 1), Frequency: entity.DigestDaily}}
+		}
+		repo.EXPECT().DueRecipients(ctx, now, int64(0), digestBatch).Return(page, nil)
+		repo.EXPECT().DigestTopics(ctx, gomock.Any(), gomock.Any(), now, 20, 3).Return(nil, nil).Times(digestBatch)
+		repo.EXPECT().MarkSent(ctx, gomock.Any(), now).Return(nil).Times(digestBatch)
+		repo.EXPECT().DueRecipients(ctx, now, int64(digestBatch), digestBatch).Return(nil, nil)
+
+		_, err := uc.SendDigests(ctx, now)
+		require.NoError(t, err)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		repo.EXPECT().DueRecipients(ctx, now, int64(0), digestBatch).Return(nil, errors.New("db down"))
+		_, err := uc.SendDigests(ctx, now)
+		require.Error(t, err)
+	})
+}
+
+func TestDigestSince(t *testing.T) {
+	now := time.Date(2030, 1, 10, 8, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	recent := now.Add(-30 * time.Hour)
+	old := now.Add(-30 * day)
+
+	require.Equal(t, now.Add(-day), digestSince(&entity.DigestSettings{Frequency: entity.DigestDaily}, now))
+	require.Equal(t, recent, digestSince(&entity.DigestSettings{Frequency: entity.DigestDaily, LastSentAt: &recent}, now))
+	require.Equal(t, now.Add(-14*day), digestSince(&entity.DigestSettings{Frequency: entity.DigestWeekly, LastSentAt: &old}, now))
+}
+
+func TestMessagesWord(t *testing.T) {
+	for n, want := range map[int64]string{1: "сообщение", 2: "сообщения", 5: "сообщений", 11: "сообщений", 21: "сообщение", 112: "сообщений", 104: "сообщения"} {
+		require.Equal(t, want, messagesWord(n), n)
+	}
+}
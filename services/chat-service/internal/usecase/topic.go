@@ -10,31 +10,53 @@ import (
 
 	"chat-service/internal/entity"
 	repoErr "chat-service/internal/errors"
+	"chat-service/internal/filter"
 	"chat-service/internal/repo"
 )
 
 var (
 	ErrTopicNotFound = errors.New("topic not found")
+	ErrTopicLocked   = errors.New("topic is locked: new messages are not accepted")
+	ErrInvalidMerge  = errors.New("invalid merge: topic cannot be merged into itself or into a merged topic")
 )
 
+// maxRedirectHops ограничивает переходы по заглушкам; цепочки схлопываются при слиянии, так что хватает одного
+const maxRedirectHops = 3
+
 type TopicUC struct {
-	repo repo.TopicRepository
-	log  logger.Interface
+	repo      repo.TopicRepository
+	access    access
+	mutes     muteGuard
+	modlog    modLog
+	content   ContentChecker
+	privilege PrivilegeChecker
+	publisher MessagePublisher
+	log       logger.Interface
+	retention time.Duration // окно, в течение которого удалённый топик можно восстановить
 }
 
-func NewTopicUsecase(r repo.TopicRepository, l logger.Interface) *TopicUC {
-	return &TopicUC{repo: r, log: l}
+func NewTopicUsecase(r repo.TopicRepository, mods repo.ModeratorRepository, mutes repo.MuteRepository, ml repo.ModerationRepository, cc ContentChecker, pc PrivilegeChecker, n Notifier, p MessagePublisher, l logger.Interface, retention time.Duration) *TopicUC {
+	return &TopicUC{repo: r, access: access{mods: mods}, mutes: muteGuard{repo: mutes}, modlog: modLog{repo: ml, notify: n, log: l}, content: cc, privilege: pc, publisher: p, log: l, retention: retention}
 }
 
-// ListTopics возвращает все топики в категории
-func (uc *TopicUC) ListTopics(ctx context.Context, categoryID int64) ([]*entity.Topic, error) {
-	uc.log.Debug("ListTopics called", "category_id", categoryID)
+// ListTopics возвращает топики категории; f.Unsolved оставляет только топики без принятого ответа
+func (uc *TopicUC) ListTopics(ctx context.Context, categoryID int64, f TopicFilter) ([]*entity.Topic, error) {
+	uc.log.Debug("ListTopics called", "category_id", categoryID, "unsolved", f.Unsolved)
 
 	list, err := uc.repo.GetByCategory(ctx, categoryID)
 	if err != nil {
 		uc.log.Error("repo.GetByCategory failed", "err", err)
 		return nil, fmt.Errorf("TopicUC.List: %w", err)
 	}
+	if f.Unsolved {
+		unsolved := list[:0]
+		for _, t := range list {
+			if !t.IsSolved() {
+				unsolved = append(unsolved, t)
+			}
+		}
+		list = unsolved
+	}
 
 	if len(list) == 0 {
 		uc.log.Warn("no topics found in category", "category_id", categoryID)
@@ -44,10 +66,16 @@ func (uc *TopicUC) ListTopics(ctx context.Context, categoryID int64) ([]*entity.
 	return list, nil
 }
 
+// GetTopic возвращает топик по ID; для заглушки слитого топика — топик, в который он слит.
+// Скрытый по жалобам топик видят только admin и модераторы категории.
 func (uc *TopicUC) GetTopic(ctx context.Context, id int64) (*entity.Topic, error) {
 	uc.log.Debug("GetTopic called", "id", id)
 
 	t, err := uc.repo.GetByID(ctx, id)
+	for hops := 0; err == nil && t.IsRedirect() && hops < maxRedirectHops; hops++ {
+		uc.log.Debug("following topic redirect", "from", t.ID, "to", *t.RedirectTo)
+		t, err = uc.repo.GetByID(ctx, *t.RedirectTo)
+	}
 	if err != nil {
 		if errors.Is(err, repoErr.ErrNotFound) {
 			uc.log.Info("topic not found", "id", id)
@@ -56,6 +84,17 @@ func (uc *TopicUC) GetTopic(ctx context.Context, id int64) (*entity.Topic, error
 		uc.log.Error("repo.GetByID failed", "err", err)
 		return nil, fmt.Errorf("TopicUC.Get: %w", err)
 	}
+	if t.IsRedirect() {
+		uc.log.Warn("topic redirect chain too long", "id", id)
+		return nil, ErrTopicNotFound
+	}
+	// скрытый по жалобам топик для остальных выглядит несуществующим
+	if t.Hidden {
+		if _, err := uc.access.check(ctx, auth.PermTopicModerate, inCategory(t.CategoryID)); err != nil {
+			uc.log.Info("hidden topic requested", "id", t.ID)
+			return nil, ErrTopicNotFound
+		}
+	}
 
 	uc.log.Info("topic retrieved", "id", t.ID, "title", t.Title)
 	return t, nil
@@ -65,20 +104,34 @@ func (uc *TopicUC) GetTopic(ctx context.Context, id int64) (*entity.Topic, error
 func (uc *TopicUC) CreateTopic(ctx context.Context, p TopicParams) (int64, error) {
 	uc.log.Debug("CreateTopic called", "title", p.Title, "category_id", p.CategoryID)
 
-	userID, role := auth.FromContext(ctx)
-	if userID == 0 {
-		uc.log.Warn("unauthenticated user tried to create topic")
-		return 0, ErrUnauthenticated
+	userID, err := uc.access.check(ctx, auth.PermTopicWrite, nil)
+	if err != nil {
+		uc.log.Warn("create topic denied", "err", err)
+		return 0, err
+	}
+	if err := uc.mutes.check(ctx, userID, 0, p.CategoryID); err != nil {
+		if errors.Is(err, ErrMuted) {
+			uc.log.Info("topic rejected: user is muted", "category_id", p.CategoryID, "user_id", userID)
+		} else {
+			uc.log.Error("mute check failed", "err", err)
+		}
+		return 0, err
+	}
+	if err := requirePrivilege(ctx, uc.privilege, entity.PrivilegeCreateTopic); err != nil {
+		return 0, err
 	}
-	if role != "user" && role != "admin" {
-		uc.log.Warn("unauthorized role tried to create topic", "role", role)
-		return 0, ErrForbidden
+	if err := requireLinks(ctx, uc.privilege, p.Title, p.Description); err != nil {
+		return 0, err
+	}
+	title, description, flags, err := uc.screenTopic(ctx, userID, p.Title, p.Description)
+	if err != nil {
+		return 0, err
 	}
 
 	t := &entity.Topic{
 		CategoryID:  p.CategoryID,
-		Title:       p.Title,
-		Description: p.Description,
+		Title:       title,
+		Description: description,
 		AuthorID:    p.AuthorID,
 		CreatedAt:   time.Now().UTC(),
 	}
@@ -87,23 +140,39 @@ func (uc *TopicUC) CreateTopic(ctx context.Context, p TopicParams) (int64, error
 		uc.log.Error("repo.Create failed", "err", err)
 		return 0, fmt.Errorf("TopicUC.Create: %w", err)
 	}
+	if len(flags) > 0 {
+		uc.content.FlagContent(ctx, entity.TargetTopic, id, flags)
+	}
 
 	uc.log.Info("topic created", "id", id, "category_id", p.CategoryID)
 	return id, nil
 }
 
+// screenTopic проверяет заголовок и описание фильтром контента по отдельности
+func (uc *TopicUC) screenTopic(ctx context.Context, userID int64, title, description string) (string, string, []filter.Hit, error) {
+	title, flags, err := screen(ctx, uc.content, uc.log, userID, title, false)
+	if err != nil {
+		return "", "", nil, err
+	}
+	description, more, err := screen(ctx, uc.content, uc.log, userID, description, false)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return title, description, append(flags, more...), nil
+}
+
 func (uc *TopicUC) UpdateTopic(ctx context.Context, id int64, params TopicParams,
 ) (int64, error) {
 	uc.log.Debug("UpdateTopic called", "id", id)
 
-	userID, role := auth.FromContext(ctx)
+	userID, _ := auth.FromContext(ctx)
 	if userID == 0 {
 		uc.log.Warn("unauthenticated user tried to update topic")
 		return 0, ErrUnauthenticated
 	}
 
 	t, err := uc.repo.GetByID(ctx, id)
-	if errors.Is(err, repoErr.ErrNotFound) {
+	if errors.Is(err, repoErr.ErrNotFound) || (err == nil && t.IsRedirect()) {
 		uc.log.Info("topic not found for update", "id", id)
 		return 0, ErrTopicNotFound
 	} else if err != nil {
@@ -111,35 +180,51 @@ func (uc *TopicUC) UpdateTopic(ctx context.Context, id int64, params TopicParams
 		return 0, fmt.Errorf("TopicUC.Update#get: %w", err)
 	}
 
-	if t.AuthorID != userID && role != "admin" {
-		uc.log.Warn("unauthorized user tried to update topic", "topic_id", id, "user_id", userID)
-		return 0, ErrForbidden
+	// свой топик — право автора, чужой — право модератора этой категории
+	perm := auth.PermTopicWrite
+	if t.AuthorID != userID {
+		perm = auth.PermTopicModerate
+	}
+	if _, err := uc.access.check(ctx, perm, inCategory(t.CategoryID)); err != nil {
+		uc.log.Warn("update topic denied", "topic_id", id, "user_id", userID, "err", err)
+		return 0, err
 	}
 
-	t.Title = params.Title
-	t.Description = params.Description
+	if err := requireLinks(ctx, uc.privilege, params.Title, params.Description); err != nil {
+		return 0, err
+	}
+	title, description, flags, err := uc.screenTopic(ctx, userID, params.Title, params.Description)
+	if err != nil {
+		return 0, err
+	}
+	t.Title = title
+	t.Description = description
 
 	newID, err := uc.repo.Update(ctx, t)
 	if err != nil {
 		uc.log.Error("repo.Update failed", "err", err)
 		return 0, fmt.Errorf("TopicUC.Update: %w", err)
 	}
+	if len(flags) > 0 {
+		uc.content.FlagContent(ctx, entity.TargetTopic, id, flags)
+	}
 
 	uc.log.Info("topic updated", "id", newID)
 	return newID, nil
 }
 
-func (uc *TopicUC) DeleteTopic(ctx context.Context, id int64) error {
+// DeleteTopic мягко удаляет топик. Сообщения не трогаются и вернутся вместе с топиком при восстановлении.
+func (uc *TopicUC) DeleteTopic(ctx context.Context, id int64, reason string) error {
 	uc.log.Debug("DeleteTopic called", "id", id)
 
-	userID, role := auth.FromContext(ctx)
+	userID, _ := auth.FromContext(ctx)
 	if userID == 0 {
-		uc.log.Warn("unauthenticated user tried to update topic")
+		uc.log.Warn("unauthenticated user tried to delete topic")
 		return ErrUnauthenticated
 	}
 
 	t, err := uc.repo.GetByID(ctx, id)
-	if errors.Is(err, repoErr.ErrNotFound) {
+	if errors.Is(err, repoErr.ErrNotFound) || (err == nil && t.IsRedirect()) {
 		uc.log.Info("topic not found for delete", "id", id)
 		return ErrTopicNotFound
 	} else if err != nil {
@@ -147,16 +232,281 @@ func (uc *TopicUC) DeleteTopic(ctx context.Context, id int64) error {
 		return fmt.Errorf("TopicUC.Delete#get: %w", err)
 	}
 
-	if t.AuthorID != userID && role != "admin" {
-		uc.log.Warn("unauthorized user tried to delete topic", "topic_id", id, "user_id", userID)
-		return ErrForbidden
+	// свой топик — право автора, чужой — право модератора этой категории
+	perm := auth.PermTopicWrite
+	if t.AuthorID != userID {
+		perm = auth.PermTopicModerate
+	}
+	if _, err := uc.access.check(ctx, perm, inCategory(t.CategoryID)); err != nil {
+		uc.log.Warn("delete topic denied", "topic_id", id, "user_id", userID, "err", err)
+		return err
 	}
 
-	if err := uc.repo.Delete(ctx, id); err != nil {
+	err = uc.repo.Delete(ctx, id, userID, reason)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		uc.log.Info("topic deleted concurrently", "id", id)
+		return ErrTopicNotFound
+	} else if err != nil {
 		uc.log.Error("repo.Delete failed", "err", err)
 		return fmt.Errorf("TopicUC.Delete: %w", err)
 	}
 
-	uc.log.Info("topic deleted", "id", id)
+	// удаление своего топика — не модерация
+	if t.AuthorID != userID {
+		uc.modlog.record(ctx, &entity.ModerationEntry{
+			Action:         entity.ModDeleteTopic,
+			TargetType:     entity.TargetTopic,
+			TargetID:       id,
+			TargetAuthorID: &t.AuthorID,
+			Reason:         reason,
+			Snapshot: map[string]any{
+				"title":       t.Title,
+				"description": t.Description,
+				"category_id": t.CategoryID,
+			},
+		})
+	}
+
+	uc.log.Info("topic deleted", "id", id, "deleted_by", userID)
+	return nil
+}
+
+// RestoreTopic восстанавливает мягко удалённый топик (только admin, в пределах окна хранения)
+func (uc *TopicUC) RestoreTopic(ctx context.Context, id int64) error {
+	uc.log.Debug("RestoreTopic called", "id", id)
+
+	userID, err := uc.access.check(ctx, auth.PermContentRestore, nil)
+	if err != nil {
+		uc.log.Warn("restore topic denied", "err", err)
+		return err
+	}
+
+	since := time.Now().UTC().Add(-uc.retention)
+	err = uc.repo.Restore(ctx, id, since)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		uc.log.Info("no restorable topic found", "id", id)
+		return ErrTopicNotFound
+	} else if err != nil {
+		uc.log.Error("repo.Restore failed", "err", err)
+		return fmt.Errorf("TopicUC.Restore: %w", err)
+	}
+
+	uc.modlog.record(ctx, &entity.ModerationEntry{
+		Action:     entity.ModRestoreTopic,
+		TargetType: entity.TargetTopic,
+		TargetID:   id,
+	})
+
+	uc.log.Info("topic restored", "id", id, "restored_by", userID)
+	return nil
+}
+
+// ListDeletedTopics возвращает топики, которые ещё можно восстановить (только admin)
+func (uc *TopicUC) ListDeletedTopics(ctx context.Context) ([]*entity.Topic, error) {
+	uc.log.Debug("ListDeletedTopics called")
+
+	_, err := uc.access.check(ctx, auth.PermContentRestore, nil)
+	if err != nil {
+		uc.log.Warn("list deleted topics denied", "err", err)
+		return nil, err
+	}
+
+	since := time.Now().UTC().Add(-uc.retention)
+	list, err := uc.repo.GetDeleted(ctx, since)
+	if err != nil {
+		uc.log.Error("repo.GetDeleted failed", "err", err)
+		return nil, fmt.Errorf("TopicUC.ListDeleted: %w", err)
+	}
+
+	uc.log.Info("deleted topics retrieved", "count", len(list))
+	return list, nil
+}
+
+// PurgeDeletedTopics окончательно удаляет топики, удалённые раньше threshold (для cron)
+func (uc *TopicUC) PurgeDeletedTopics(ctx context.Context, threshold time.Time) error {
+	uc.log.Debug("PurgeDeletedTopics called", "threshold", threshold)
+
+	n, err := uc.repo.PurgeDeleted(ctx, threshold)
+	if err != nil {
+		uc.log.Error("repo.PurgeDeleted failed", "err", err)
+		return fmt.Errorf("TopicUC.PurgeDeleted: %w", err)
+	}
+
+	uc.log.Info("deleted topics purged", "threshold", threshold, "count", n)
+	return nil
+}
+
+// SetTopicRetention задаёт политику хранения сообщений топика, перекрывая политику категории (только admin)
+func (uc *TopicUC) SetTopicRetention(ctx context.Context, id int64, p entity.RetentionPolicy) error {
+	uc.log.Debug("SetTopicRetention called", "id", id, "mode", p.Mode, "value", p.Value)
+
+	_, err := uc.access.check(ctx, auth.PermRetentionManage, nil)
+	if err != nil {
+		uc.log.Warn("set topic retention denied", "err", err)
+		return err
+	}
+	if !p.Valid() {
+		uc.log.Info("invalid retention policy", "mode", p.Mode, "value", p.Value)
+		return ErrInvalidRetention
+	}
+
+	err = uc.repo.SetRetention(ctx, id, p)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		uc.log.Info("topic not found during retention update", "id", id)
+		return ErrTopicNotFound
+	} else if err != nil {
+		uc.log.Error("repo.SetRetention failed", "err", err)
+		return fmt.Errorf("TopicUC.SetRetention: %w", err)
+	}
+
+	uc.log.Info("topic retention updated", "id", id, "mode", p.Mode, "value", p.Value)
+	return nil
+}
+
+// PinTopic закрепляет топик вверху категории или снимает закрепление (admin или модератор категории)
+func (uc *TopicUC) PinTopic(ctx context.Context, id int64, pinned bool) error {
+	uc.log.Debug("PinTopic called", "id", id, "pinned", pinned)
+
+	userID, err := uc.access.check(ctx, auth.PermTopicModerate, topicCategory(uc.repo, id))
+	if err != nil {
+		uc.log.Warn("pin topic denied", "id", id, "err", err)
+		return err
+	}
+
+	err = uc.repo.SetPinned(ctx, id, pinned)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		uc.log.Info("topic not found for pin", "id", id)
+		return ErrTopicNotFound
+	} else if err != nil {
+		uc.log.Error("repo.SetPinned failed", "err", err)
+		return fmt.Errorf("TopicUC.Pin: %w", err)
+	}
+
+	action := entity.ModPinTopic
+	if !pinned {
+		action = entity.ModUnpinTopic
+	}
+	uc.modlog.record(ctx, &entity.ModerationEntry{Action: action, TargetType: entity.TargetTopic, TargetID: id})
+
+	uc.log.Info("topic pin changed", "id", id, "pinned", pinned, "by", userID)
+	return nil
+}
+
+// LockTopic закрывает топик для новых сообщений или открывает его обратно (admin или модератор категории).
+// Подписчики топика получают событие topic_locked / topic_unlocked.
+func (uc *TopicUC) LockTopic(ctx context.Context, id int64, locked bool) error {
+	uc.log.Debug("LockTopic called", "id", id, "locked", locked)
+
+	userID, err := uc.access.check(ctx, auth.PermTopicModerate, topicCategory(uc.repo, id))
+	if err != nil {
+		uc.log.Warn("lock topic denied", "id", id, "err", err)
+		return err
+	}
+
+	err = uc.repo.SetLocked(ctx, id, locked)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		uc.log.Info("topic not found for lock", "id", id)
+		return ErrTopicNotFound
+	} else if err != nil {
+		uc.log.Error("repo.SetLocked failed", "err", err)
+		return fmt.Errorf("TopicUC.Lock: %w", err)
+	}
+
+	action := entity.ActionTopicLocked
+	if !locked {
+		action = entity.ActionTopicUnlocked
+	}
+	uc.publisher.Publish(id, &entity.WSEvent{Action: action, TopicID: id})
+
+	modAction := entity.ModLockTopic
+	if !locked {
+		modAction = entity.ModUnlockTopic
+	}
+	uc.modlog.record(ctx, &entity.ModerationEntry{Action: modAction, TargetType: entity.TargetTopic, TargetID: id})
+
+	uc.log.Info("topic lock changed", "id", id, "locked", locked, "by", userID)
+	return nil
+}
+
+// MoveTopic переносит топик в другую категорию (только admin). Перенос пишется в журнал модерации,
+// подписчики топика получают событие topic_moved.
+func (uc *TopicUC) MoveTopic(ctx context.Context, id, categoryID int64) error {
+	uc.log.Debug("MoveTopic called", "id", id, "category_id", categoryID)
+
+	userID, err := uc.access.check(ctx, auth.PermTopicMove, nil)
+	if err != nil {
+		uc.log.Warn("move topic denied", "err", err)
+		return err
+	}
+
+	from, err := uc.repo.Move(ctx, id, categoryID, userID)
+	switch {
+	case errors.Is(err, repoErr.ErrInvalidReference):
+		uc.log.Info("target category not found for move", "category_id", categoryID)
+		return ErrCategoryNotFound
+	case errors.Is(err, repoErr.ErrNotFound):
+		uc.log.Info("topic not found for move", "id", id)
+		return ErrTopicNotFound
+	case err != nil:
+		uc.log.Error("repo.Move failed", "err", err)
+		return fmt.Errorf("TopicUC.Move: %w", err)
+	}
+
+	uc.publisher.Publish(id, &entity.WSEvent{
+		Action:     entity.ActionTopicMoved,
+		TopicID:    id,
+		CategoryID: categoryID,
+	})
+
+	uc.log.Info("topic moved", "id", id, "from", from, "to", categoryID, "by", userID)
+	return nil
+}
+
+// MergeTopics сливает sourceID в targetID (только admin). Сообщения переезжают с сохранением дат,
+// на месте sourceID остаётся заглушка, по которой старый ID продолжает открываться.
+// Событие topic_merged получают подписчики обоих топиков.
+func (uc *TopicUC) MergeTopics(ctx context.Context, sourceID, targetID int64) error {
+	uc.log.Debug("MergeTopics called", "source_id", sourceID, "target_id", targetID)
+
+	userID, err := uc.access.check(ctx, auth.PermTopicMove, nil)
+	if err != nil {
+		uc.log.Warn("merge topics denied", "err", err)
+		return err
+	}
+	if sourceID == targetID {
+		return ErrInvalidMerge
+	}
+
+	target, err := uc.repo.GetByID(ctx, targetID)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		uc.log.Info("merge target not found", "target_id", targetID)
+		return ErrTopicNotFound
+	} else if err != nil {
+		uc.log.Error("repo.GetByID failed", "err", err)
+		return fmt.Errorf("TopicUC.Merge#get: %w", err)
+	}
+	if target.IsRedirect() {
+		uc.log.Info("merge target is a redirect stub", "target_id", targetID)
+		return ErrInvalidMerge
+	}
+
+	moved, err := uc.repo.Merge(ctx, sourceID, targetID, userID)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		uc.log.Info("merge source not found", "source_id", sourceID)
+		return ErrTopicNotFound
+	} else if err != nil {
+		uc.log.Error("repo.Merge failed", "err", err)
+		return fmt.Errorf("TopicUC.Merge: %w", err)
+	}
+
+	ev := &entity.WSEvent{
+		Action:        entity.ActionTopicMerged,
+		TopicID:       sourceID,
+		TargetTopicID: targetID,
+	}
+	uc.publisher.Publish(sourceID, ev)
+	uc.publisher.Publish(targetID, ev)
+
+	uc.log.Info("topics merged", "source_id", sourceID, "target_id", targetID, "messages", moved, "by", userID)
 	return nil
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
 func TestTopicUC_ListTopics(t *testing.T) {
@@ -17,20 +18,20 @@ func TestTopicUC_ListTopics(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mocks.NewMockTopicRepository(ctrl)
-	uc := NewTopicUsecase(repo, mocks.FakeLogger{})
+	uc := NewTopicUsecase(repo, nil, nil, nil, nil, nil, nil, nil, mocks.FakeLogger{}, retention)
 	ctx := context.Background()
 
 	t.Run("success", func(t *testing.T) {
 		expected := []*entity.Topic{{ID: 1}, {ID: 2}}
 		repo.EXPECT().GetByCategory(ctx, int64(1)).Return(expected, nil)
-		res, err := uc.ListTopics(ctx, 1)
+		res, err := uc.ListTopics(ctx, 1, TopicFilter{})
 		require.NoError(t, err)
 		require.Equal(t, expected, res)
 	})
 
 	t.Run("repo error", func(t *testing.T) {
 		repo.EXPECT().GetByCategory(ctx, int64(1)).Return(nil, errors.New("fail"))
-		res, err := uc.ListTopics(ctx, 1)
+		res, err := uc.ListTopics(ctx, 1, TopicFilter{})
 		require.Nil(t, res)
 		require.ErrorContains(t, err, "TopicUC.List")
 	})
@@ -38,12 +39,60 @@ func TestTopicUC_ListTopics(t *testing.T) {
 	t.Run("empty list", func(t *testing.T) {
 		repo.EXPECT().GetByCategory(ctx, int64(1)).Return([]*entity.Topic{}, nil)
 
-		result, err := uc.ListTopics(ctx, 1)
+		result, err := uc.ListTopics(ctx, 1, TopicFilter{})
 
 		require.NoError(t, err)
 		require.Empty(t, result)
 	})
 
+	t.Run("unsolved only", func(t *testing.T) {
+		answer := int64(9)
+		repo.EXPECT().GetByCategory(ctx, int64(1)).
+			Return([]*entity.Topic{{ID: 1, QA: true}, {ID: 2, QA: true, AcceptedID: &answer}, {ID: 3, QA: true}}, nil)
+
+		result, err := uc.ListTopics(ctx, 1, TopicFilter{Unsolved: true})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		require.Equal(t, int64(1), result[0].ID)
+		require.Equal(t, int64(3), result[1].ID)
+	})
+
+	t.Run("topic with a deleted or hidden answer is unsolved", func(t *testing.T) {
+		// репозиторий не отдаёт AcceptedID, пока принятый ответ удалён или скрыт
+		repo.EXPECT().GetByCategory(ctx, int64(1)).Return([]*entity.Topic{{ID: 1, QA: true}}, nil)
+
+		result, err := uc.ListTopics(ctx, 1, TopicFilter{Unsolved: true})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+	})
+
+	t.Run("old mark outside Q&A does not solve the topic", func(t *testing.T) {
+		answer := int64(9)
+		repo.EXPECT().GetByCategory(ctx, int64(1)).Return([]*entity.Topic{{ID: 1, AcceptedID: &answer}}, nil)
+
+		result, err := uc.ListTopics(ctx, 1, TopicFilter{Unsolved: true})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+	})
+}
+
+func TestTopic_IsSolved(t *testing.T) {
+	answer := int64(9)
+	cases := []struct {
+		name  string
+		topic entity.Topic
+		want  bool
+	}{
+		{name: "accepted answer in Q&A", topic: entity.Topic{QA: true, AcceptedID: &answer}, want: true},
+		{name: "no accepted answer", topic: entity.Topic{QA: true}},
+		{name: "accepted answer deleted or hidden", topic: entity.Topic{QA: true, AcceptedID: nil}},
+		{name: "category is no longer Q&A", topic: entity.Topic{AcceptedID: &answer}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.want, c.topic.IsSolved())
+		})
+	}
 }
 
 func TestTopicUC_GetTopic(t *testing.T) {
@@ -51,7 +100,7 @@ func TestTopicUC_GetTopic(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mocks.NewMockTopicRepository(ctrl)
-	uc := NewTopicUsecase(repo, mocks.FakeLogger{})
+	uc := NewTopicUsecase(repo, nil, nil, nil, nil, nil, nil, nil, mocks.FakeLogger{}, retention)
 	ctx := context.Background()
 
 	t.Run("success", func(t *testing.T) {
@@ -75,6 +124,25 @@ func TestTopicUC_GetTopic(t *testing.T) {
 		require.Nil(t, res)
 		require.ErrorContains(t, err, "TopicUC.Get")
 	})
+
+	t.Run("merged topic resolves to target", func(t *testing.T) {
+		targetID := int64(2)
+		target := &entity.Topic{ID: 2, Title: "target"}
+		repo.EXPECT().GetByID(ctx, int64(1)).Return(&entity.Topic{ID: 1, RedirectTo: &targetID}, nil)
+		repo.EXPECT().GetByID(ctx, int64(2)).Return(target, nil)
+		res, err := uc.GetTopic(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, target, res)
+	})
+
+	t.Run("redirect target gone", func(t *testing.T) {
+		targetID := int64(2)
+		repo.EXPECT().GetByID(ctx, int64(1)).Return(&entity.Topic{ID: 1, RedirectTo: &targetID}, nil)
+		repo.EXPECT().GetByID(ctx, int64(2)).Return(nil, repoErr.ErrNotFound)
+		res, err := uc.GetTopic(ctx, 1)
+		require.Nil(t, res)
+		require.ErrorIs(t, err, ErrTopicNotFound)
+	})
 }
 
 func TestTopicUC_CreateTopic(t *testing.T) {
@@ -82,7 +150,7 @@ func TestTopicUC_CreateTopic(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mocks.NewMockTopicRepository(ctrl)
-	uc := NewTopicUsecase(repo, mocks.FakeLogger{})
+	uc := NewTopicUsecase(repo, nil, nil, nil, nil, nil, nil, nil, mocks.FakeLogger{}, retention)
 	params := TopicParams{CategoryID: 10, Title: "x", Description: "y", AuthorID: 1}
 
 	t.Run("unauthenticated", func(t *testing.T) {
@@ -121,7 +189,7 @@ func TestTopicUC_UpdateTopic(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mocks.NewMockTopicRepository(ctrl)
-	uc := NewTopicUsecase(repo, mocks.FakeLogger{})
+	uc := NewTopicUsecase(repo, nil, nil, nil, nil, nil, nil, nil, mocks.FakeLogger{}, retention)
 	params := TopicParams{Title: "x", Description: "y"}
 
 	t.Run("unauthenticated", func(t *testing.T) {
@@ -175,6 +243,18 @@ func TestTopicUC_UpdateTopic(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, int64(1), id)
 	})
+	t.Run("moderator edits foreign topic in own category", func(t *testing.T) {
+		mods := mocks.NewMockModeratorRepository(ctrl)
+		uc := NewTopicUsecase(repo, mods, nil, nil, nil, nil, nil, nil, mocks.FakeLogger{}, retention)
+		topic := &entity.Topic{ID: 1, AuthorID: 42, CategoryID: 10}
+		ctx := auth.WithUser(context.Background(), 3, "moderator")
+		repo.EXPECT().GetByID(ctx, int64(1)).Return(topic, nil)
+		mods.EXPECT().IsModerator(ctx, int64(3), int64(10)).Return(true, nil)
+		repo.EXPECT().Update(ctx, topic).Return(int64(1), nil)
+		id, err := uc.UpdateTopic(ctx, 1, params)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), id)
+	})
 }
 
 func TestTopicUC_DeleteTopic(t *testing.T) {
@@ -182,17 +262,17 @@ func TestTopicUC_DeleteTopic(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mocks.NewMockTopicRepository(ctrl)
-	uc := NewTopicUsecase(repo, mocks.FakeLogger{})
+	uc := NewTopicUsecase(repo, nil, nil, nil, nil, nil, nil, nil, mocks.FakeLogger{}, retention)
 	ctx := auth.WithUser(context.Background(), 1, "admin")
 
 	t.Run("unauthenticated", func(t *testing.T) {
-		err := uc.DeleteTopic(context.Background(), 1)
+		err := uc.DeleteTopic(context.Background(), 1, "")
 		require.ErrorIs(t, err, ErrUnauthenticated)
 	})
 
 	t.Run("not found", func(t *testing.T) {
 		repo.EXPECT().GetByID(ctx, int64(1)).Return(nil, repoErr.ErrNotFound)
-		err := uc.DeleteTopic(ctx, 1)
+		err := uc.DeleteTopic(ctx, 1, "")
 		require.ErrorIs(t, err, ErrTopicNotFound)
 	})
 
@@ -201,31 +281,346 @@ func TestTopicUC_DeleteTopic(t *testing.T) {
 		ctx := auth.WithUser(context.Background(), 1, "user") // <= обязательно не admin
 		repo.EXPECT().GetByID(ctx, int64(1)).Return(topic, nil)
 
-		repo.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(0)
+		repo.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
-		err := uc.DeleteTopic(ctx, 1)
+		err := uc.DeleteTopic(ctx, 1, "")
 		require.ErrorIs(t, err, ErrForbidden)
 	})
 
 	t.Run("repo error on get", func(t *testing.T) {
 		repo.EXPECT().GetByID(ctx, int64(1)).Return(nil, errors.New("fail"))
-		err := uc.DeleteTopic(ctx, 1)
+		err := uc.DeleteTopic(ctx, 1, "")
 		require.ErrorContains(t, err, "TopicUC.Delete#get")
 	})
 
 	t.Run("repo error on delete", func(t *testing.T) {
 		topic := &entity.Topic{ID: 1, AuthorID: 1}
 		repo.EXPECT().GetByID(ctx, int64(1)).Return(topic, nil)
-		repo.EXPECT().Delete(ctx, int64(1)).Return(errors.New("fail"))
-		err := uc.DeleteTopic(ctx, 1)
+		repo.EXPECT().Delete(ctx, int64(1), int64(1), "").Return(errors.New("fail"))
+		err := uc.DeleteTopic(ctx, 1, "")
 		require.ErrorContains(t, err, "TopicUC.Delete")
 	})
 
 	t.Run("success", func(t *testing.T) {
 		topic := &entity.Topic{ID: 1, AuthorID: 1}
 		repo.EXPECT().GetByID(ctx, int64(1)).Return(topic, nil)
-		repo.EXPECT().Delete(ctx, int64(1)).Return(nil)
-		err := uc.DeleteTopic(ctx, 1)
+		repo.EXPECT().Delete(ctx, int64(1), int64(1), "").Return(nil)
+		err := uc.DeleteTopic(ctx, 1, "")
 		require.NoError(t, err)
 	})
 }
+
+func TestTopicUC_RestoreTopic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockTopicRepository(ctrl)
+	uc := NewTopicUsecase(repo, nil, nil, nil, nil, nil, nil, nil, mocks.FakeLogger{}, retention)
+	ctx := auth.WithUser(context.Background(), 1, "admin")
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		err := uc.RestoreTopic(context.Background(), 1)
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	t.Run("forbidden for user", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "user")
+		err := uc.RestoreTopic(ctx, 1)
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("outside retention window", func(t *testing.T) {
+		repo.EXPECT().Restore(ctx, int64(1), gomock.Any()).Return(repoErr.ErrNotFound)
+		err := uc.RestoreTopic(ctx, 1)
+		require.ErrorIs(t, err, ErrTopicNotFound)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		repo.EXPECT().Restore(ctx, int64(1), gomock.Any()).Return(errors.New("fail"))
+		err := uc.RestoreTopic(ctx, 1)
+		require.ErrorContains(t, err, "TopicUC.Restore")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().Restore(ctx, int64(1), gomock.Any()).Return(nil)
+		err := uc.RestoreTopic(ctx, 1)
+		require.NoError(t, err)
+	})
+}
+
+func TestTopicUC_ListDeletedTopics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockTopicRepository(ctrl)
+	uc := NewTopicUsecase(repo, nil, nil, nil, nil, nil, nil, nil, mocks.FakeLogger{}, retention)
+
+	t.Run("forbidden for user", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "user")
+		res, err := uc.ListDeletedTopics(ctx)
+		require.Nil(t, res)
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "admin")
+		expected := []*entity.Topic{{ID: 1}}
+		repo.EXPECT().GetDeleted(ctx, gomock.Any()).Return(expected, nil)
+		res, err := uc.ListDeletedTopics(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expected, res)
+	})
+}
+
+func TestTopicUC_PurgeDeletedTopics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockTopicRepository(ctrl)
+	uc := NewTopicUsecase(repo, nil, nil, nil, nil, nil, nil, nil, mocks.FakeLogger{}, retention)
+	threshold := time.Now().Add(-retention)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().PurgeDeleted(context.Background(), threshold).Return(int64(2), nil)
+		require.NoError(t, uc.PurgeDeletedTopics(context.Background(), threshold))
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		repo.EXPECT().PurgeDeleted(context.Background(), threshold).Return(int64(0), errors.New("fail"))
+		require.ErrorContains(t, uc.PurgeDeletedTopics(context.Background(), threshold), "TopicUC.PurgeDeleted")
+	})
+}
+
+func TestTopicUC_SetTopicRetention(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockTopicRepository(ctrl)
+	uc := NewTopicUsecase(repo, nil, nil, nil, nil, nil, nil, nil, mocks.FakeLogger{}, retention)
+	ctx := auth.WithUser(context.Background(), 1, "admin")
+	policy := entity.RetentionPolicy{Mode: entity.RetentionLastN, Value: 500}
+
+	t.Run("forbidden for user", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "user")
+		err := uc.SetTopicRetention(ctx, 1, policy)
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		err := uc.SetTopicRetention(ctx, 1, entity.RetentionPolicy{Mode: entity.RetentionDays})
+		require.ErrorIs(t, err, ErrInvalidRetention)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repo.EXPECT().SetRetention(ctx, int64(1), policy).Return(repoErr.ErrNotFound)
+		err := uc.SetTopicRetention(ctx, 1, policy)
+		require.ErrorIs(t, err, ErrTopicNotFound)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().SetRetention(ctx, int64(1), policy).Return(nil)
+		require.NoError(t, uc.SetTopicRetention(ctx, 1, policy))
+	})
+}
+
+func TestTopicUC_PinTopic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockTopicRepository(ctrl)
+	uc := NewTopicUsecase(repo, nil, nil, nil, nil, nil, nil, nil, mocks.FakeLogger{}, retention)
+	ctx := auth.WithUser(context.Background(), 1, "admin")
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		err := uc.PinTopic(context.Background(), 1, true)
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	t.Run("forbidden for user", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "user")
+		repo.EXPECT().SetPinned(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		err := uc.PinTopic(ctx, 1, true)
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repo.EXPECT().SetPinned(ctx, int64(1), true).Return(repoErr.ErrNotFound)
+		err := uc.PinTopic(ctx, 1, true)
+		require.ErrorIs(t, err, ErrTopicNotFound)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		repo.EXPECT().SetPinned(ctx, int64(1), false).Return(errors.New("fail"))
+		err := uc.PinTopic(ctx, 1, false)
+		require.ErrorContains(t, err, "TopicUC.Pin")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().SetPinned(ctx, int64(1), true).Return(nil)
+		require.NoError(t, uc.PinTopic(ctx, 1, true))
+	})
+	t.Run("moderator of topic category", func(t *testing.T) {
+		mods := mocks.NewMockModeratorRepository(ctrl)
+		uc := NewTopicUsecase(repo, mods, nil, nil, nil, nil, nil, nil, mocks.FakeLogger{}, retention)
+		ctx := auth.WithUser(context.Background(), 3, "moderator")
+		repo.EXPECT().GetByID(ctx, int64(1)).Return(&entity.Topic{ID: 1, CategoryID: 10}, nil)
+		mods.EXPECT().IsModerator(ctx, int64(3), int64(10)).Return(true, nil)
+		repo.EXPECT().SetPinned(ctx, int64(1), true).Return(nil)
+		require.NoError(t, uc.PinTopic(ctx, 1, true))
+	})
+
+	t.Run("moderator of another category", func(t *testing.T) {
+		mods := mocks.NewMockModeratorRepository(ctrl)
+		uc := NewTopicUsecase(repo, mods, nil, nil, nil, nil, nil, nil, mocks.FakeLogger{}, retention)
+		ctx := auth.WithUser(context.Background(), 3, "moderator")
+		repo.EXPECT().GetByID(ctx, int64(1)).Return(&entity.Topic{ID: 1, CategoryID: 11}, nil)
+		mods.EXPECT().IsModerator(ctx, int64(3), int64(11)).Return(false, nil)
+		require.ErrorIs(t, uc.PinTopic(ctx, 1, true), ErrForbidden)
+	})
+
+	t.Run("moderator - topic not found", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 3, "moderator")
+		uc := NewTopicUsecase(repo, mocks.NewMockModeratorRepository(ctrl), nil, nil, nil, nil, nil, nil, mocks.FakeLogger{}, retention)
+		repo.EXPECT().GetByID(ctx, int64(9)).Return(nil, repoErr.ErrNotFound)
+		require.ErrorIs(t, uc.PinTopic(ctx, 9, true), ErrTopicNotFound)
+	})
+}
+
+func TestTopicUC_LockTopic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockTopicRepository(ctrl)
+	publisher := mocks.NewMockMessagePublisher(ctrl)
+	uc := NewTopicUsecase(repo, nil, nil, nil, nil, nil, nil, publisher, mocks.FakeLogger{}, retention)
+	ctx := auth.WithUser(context.Background(), 1, "admin")
+
+	t.Run("forbidden for user", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "user")
+		err := uc.LockTopic(ctx, 1, true)
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("not found does not notify", func(t *testing.T) {
+		repo.EXPECT().SetLocked(ctx, int64(1), true).Return(repoErr.ErrNotFound)
+		publisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+		err := uc.LockTopic(ctx, 1, true)
+		require.ErrorIs(t, err, ErrTopicNotFound)
+	})
+
+	t.Run("lock notifies subscribers", func(t *testing.T) {
+		repo.EXPECT().SetLocked(ctx, int64(1), true).Return(nil)
+		publisher.EXPECT().Publish(int64(1), &entity.WSEvent{Action: entity.ActionTopicLocked, TopicID: 1})
+		require.NoError(t, uc.LockTopic(ctx, 1, true))
+	})
+
+	t.Run("unlock notifies subscribers", func(t *testing.T) {
+		repo.EXPECT().SetLocked(ctx, int64(1), false).Return(nil)
+		publisher.EXPECT().Publish(int64(1), &entity.WSEvent{Action: entity.ActionTopicUnlocked, TopicID: 1})
+		require.NoError(t, uc.LockTopic(ctx, 1, false))
+	})
+}
+
+func TestTopicUC_MoveTopic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockTopicRepository(ctrl)
+	publisher := mocks.NewMockMessagePublisher(ctrl)
+	uc := NewTopicUsecase(repo, nil, nil, nil, nil, nil, nil, publisher, mocks.FakeLogger{}, retention)
+	ctx := auth.WithUser(context.Background(), 1, "admin")
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		err := uc.MoveTopic(context.Background(), 1, 2)
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	t.Run("forbidden for author", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "user")
+		repo.EXPECT().Move(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		err := uc.MoveTopic(ctx, 1, 2)
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("topic not found", func(t *testing.T) {
+		repo.EXPECT().Move(ctx, int64(1), int64(2), int64(1)).Return(int64(0), repoErr.ErrNotFound)
+		err := uc.MoveTopic(ctx, 1, 2)
+		require.ErrorIs(t, err, ErrTopicNotFound)
+	})
+
+	t.Run("category not found", func(t *testing.T) {
+		repo.EXPECT().Move(ctx, int64(1), int64(99), int64(1)).Return(int64(0), repoErr.ErrInvalidReference)
+		err := uc.MoveTopic(ctx, 1, 99)
+		require.ErrorIs(t, err, ErrCategoryNotFound)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		repo.EXPECT().Move(ctx, int64(1), int64(2), int64(1)).Return(int64(0), errors.New("fail"))
+		err := uc.MoveTopic(ctx, 1, 2)
+		require.ErrorContains(t, err, "TopicUC.Move")
+	})
+
+	t.Run("success broadcasts move", func(t *testing.T) {
+		repo.EXPECT().Move(ctx, int64(1), int64(2), int64(1)).Return(int64(5), nil)
+		publisher.EXPECT().Publish(int64(1), &entity.WSEvent{Action: entity.ActionTopicMoved, TopicID: 1, CategoryID: 2})
+		require.NoError(t, uc.MoveTopic(ctx, 1, 2))
+	})
+}
+
+func TestTopicUC_MergeTopics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockTopicRepository(ctrl)
+	publisher := mocks.NewMockMessagePublisher(ctrl)
+	uc := NewTopicUsecase(repo, nil, nil, nil, nil, nil, nil, publisher, mocks.FakeLogger{}, retention)
+	ctx := auth.WithUser(context.Background(), 1, "admin")
+
+	t.Run("forbidden for user", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "user")
+		err := uc.MergeTopics(ctx, 1, 2)
+		require.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("merge into itself", func(t *testing.T) {
+		err := uc.MergeTopics(ctx, 1, 1)
+		require.ErrorIs(t, err, ErrInvalidMerge)
+	})
+
+	t.Run("target not found", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(2)).Return(nil, repoErr.ErrNotFound)
+		err := uc.MergeTopics(ctx, 1, 2)
+		require.ErrorIs(t, err, ErrTopicNotFound)
+	})
+
+	t.Run("target is a redirect stub", func(t *testing.T) {
+		other := int64(3)
+		repo.EXPECT().GetByID(ctx, int64(2)).Return(&entity.Topic{ID: 2, RedirectTo: &other}, nil)
+		repo.EXPECT().Merge(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		err := uc.MergeTopics(ctx, 1, 2)
+		require.ErrorIs(t, err, ErrInvalidMerge)
+	})
+
+	t.Run("source not found", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(2)).Return(&entity.Topic{ID: 2}, nil)
+		repo.EXPECT().Merge(ctx, int64(1), int64(2), int64(1)).Return(int64(0), repoErr.ErrNotFound)
+		err := uc.MergeTopics(ctx, 1, 2)
+		require.ErrorIs(t, err, ErrTopicNotFound)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		repo.EXPECT().GetByID(ctx, int64(2)).Return(&entity.Topic{ID: 2}, nil)
+		repo.EXPECT().Merge(ctx, int64(1), int64(2), int64(1)).Return(int64(0), errors.New("fail"))
+		err := uc.MergeTopics(ctx, 1, 2)
+		require.ErrorContains(t, err, "TopicUC.Merge")
+	})
+
+	t.Run("success notifies both topics", func(t *testing.T) {
+		ev := &entity.WSEvent{Action: entity.ActionTopicMerged, TopicID: 1, TargetTopicID: 2}
+		repo.EXPECT().GetByID(ctx, int64(2)).Return(&entity.Topic{ID: 2}, nil)
+		repo.EXPECT().Merge(ctx, int64(1), int64(2), int64(1)).Return(int64(12), nil)
+		publisher.EXPECT().Publish(int64(1), ev)
+		publisher.EXPECT().Publish(int64(2), ev)
+		require.NoError(t, uc.MergeTopics(ctx, 1, 2))
+	})
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	repoErr "chat-service/internal/errors"
+	"chat-service/internal/repo"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZoyaDenisova/go-common/logger"
+)
+
+var (
+	ErrInvalidVote = errors.New("vote must be 1 or -1")
+	ErrSelfVote    = errors.New("cannot vote for your own message")
+)
+
+// VoteUC — голоса за сообщения. Один голос пользователя за сообщение; повторный голос заменяет прежний.
+// Счёт сообщения и репутация автора меняются на разницу, без пересчёта всех голосов.
+type VoteUC struct {
+	repo       repo.VoteRepository
+	messages   repo.MessageRepository
+	topics     repo.TopicRepository
+	privileges PrivilegeChecker
+	access     access
+	log        logger.Interface
+}
+
+func NewVoteUsecase(r repo.VoteRepository, mr repo.MessageRepository, tr repo.TopicRepository, pc PrivilegeChecker, l logger.Interface) *VoteUC {
+	return &VoteUC{repo: r, messages: mr, topics: tr, privileges: pc, log: l}
+}
+
+// Vote ставит голос текущего пользователя: 1 — за, -1 — против, 0 — снять голос.
+// За свои сообщения голосовать нельзя; голос против требует репутации entity.PrivilegeDownvote.
+func (uc *VoteUC) Vote(ctx context.Context, messageID int64, value int) (*entity.MessageVote, error) {
+	uc.log.Debug("Vote called", "message_id", messageID, "value", value)
+
+	if value != entity.VoteUp && value != entity.VoteDown && value != 0 {
+		return nil, ErrInvalidVote
+	}
+	userID, err := uc.access.check(ctx, auth.PermMessageWrite, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := uc.messages.GetByID(ctx, messageID)
+	if errors.Is(err, repoErr.ErrNotFound) || (err == nil && (m.IsDeleted() || m.Hidden)) {
+		return nil, ErrMessageNotFound
+	} else if err != nil {
+		uc.log.Error("messages.GetByID failed", "err", err)
+		return nil, fmt.Errorf("VoteUC.Vote#message: %w", err)
+	}
+	t, err := uc.topics.GetByID(ctx, m.TopicID)
+	if errors.Is(err, repoErr.ErrNotFound) || (err == nil && (t.IsDeleted() || t.Hidden)) {
+		return nil, ErrMessageNotFound
+	} else if err != nil {
+		uc.log.Error("topics.GetByID failed", "err", err)
+		return nil, fmt.Errorf("VoteUC.Vote#topic: %w", err)
+	}
+	if m.AuthorID == userID && value != 0 {
+		return nil, ErrSelfVote
+	}
+	if value == entity.VoteDown {
+		if err := requirePrivilege(ctx, uc.privileges, entity.PrivilegeDownvote); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := uc.repo.Vote(ctx, messageID, userID, value)
+	if errors.Is(err, repoErr.ErrNotFound) {
+		// удалили или скрыли между проверкой и голосом
+		return nil, ErrMessageNotFound
+	} else if err != nil {
+		uc.log.Error("repo.Vote failed", "err", err)
+		return nil, fmt.Errorf("VoteUC.Vote: %w", err)
+	}
+
+	uc.log.Info("message voted", "message_id", messageID, "user_id", userID, "value", value, "score", res.Score)
+	return res, nil
+}
+
+// AnnotateMessages заполняет MyVote у сообщений для текущего пользователя; для анонима ничего не делает.
+// Ошибка только логируется: без отметки своего голоса список всё равно полезен.
+func (uc *VoteUC) AnnotateMessages(ctx context.Context, list ...*entity.Message) {
+	userID, _ := auth.FromContext(ctx)
+	if userID == 0 || len(list) == 0 {
+		return
+	}
+	ids := make([]int64, 0, len(list))
+	for _, m := range list {
+		ids = append(ids, m.ID)
+	}
+	votes, err := uc.repo.ByUser(ctx, userID, ids)
+	if err != nil {
+		uc.log.Error("repo.ByUser failed", "user_id", userID, "err", err)
+		return
+	}
+	for _, m := range list {
+		m.MyVote = votes[m.ID]
+	}
+}
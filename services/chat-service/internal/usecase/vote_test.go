@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"chat-service/internal/auth"
+	"chat-service/internal/entity"
+	customErr "chat-service/internal/errors"
+	"chat-service/internal/usecase/mocks"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestVoteUC_Vote(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	votes := mocks.NewMockVoteRepository(ctrl)
+	messages := mocks.NewMockMessageRepository(ctrl)
+	topics := mocks.NewMockTopicRepository(ctrl)
+	privileges := mocks.NewMockPrivilegeChecker(ctrl)
+	uc := NewVoteUsecase(votes, messages, topics, privileges, mocks.FakeLogger{})
+
+	ctx := auth.WithUser(context.Background(), 1, "user")
+	msg := &entity.Message{ID: 10, TopicID: 5, AuthorID: 2}
+	topic := &entity.Topic{ID: 5, CategoryID: 3}
+
+	t.Run("upvote", func(t *testing.T) {
+		messages.EXPECT().GetByID(ctx, int64(10)).Return(msg, nil)
+		topics.EXPECT().GetByID(ctx, int64(5)).Return(topic, nil)
+		votes.EXPECT().Vote(ctx, int64(10), int64(1), entity.VoteUp).
+			Return(&entity.MessageVote{MessageID: 10, Score: 3, Value: entity.VoteUp}, nil)
+
+		res, err := uc.Vote(ctx, 10, entity.VoteUp)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), res.Score)
+	})
+
+	t.Run("downvote needs reputation", func(t *testing.T) {
+		messages.EXPECT().GetByID(ctx, int64(10)).Return(msg, nil)
+		topics.EXPECT().GetByID(ctx, int64(5)).Return(topic, nil)
+		privileges.EXPECT().Require(ctx, entity.PrivilegeDownvote).
+			Return(&InsufficientReputationError{Privilege: entity.PrivilegeDownvote, Required: 10, Reputation: 2})
+
+		_, err := uc.Vote(ctx, 10, entity.VoteDown)
+		require.ErrorIs(t, err, ErrInsufficientReputation)
+	})
+
+	t.Run("self vote", func(t *testing.T) {
+		own := &entity.Message{ID: 11, TopicID: 5, AuthorID: 1}
+		messages.EXPECT().GetByID(ctx, int64(11)).Return(own, nil)
+		topics.EXPECT().GetByID(ctx, int64(5)).Return(topic, nil)
+
+		_, err := uc.Vote(ctx, 11, entity.VoteUp)
+		require.ErrorIs(t, err, ErrSelfVote)
+	})
+
+	t.Run("deleted message", func(t *testing.T) {
+		deletedAt := time.Now()
+		messages.EXPECT().GetByID(ctx, int64(12)).Return(&entity.Message{ID: 12, TopicID: 5, DeletedAt: &deletedAt}, nil)
+
+		_, err := uc.Vote(ctx, 12, entity.VoteUp)
+		require.ErrorIs(t, err, ErrMessageNotFound)
+	})
+
+	t.Run("message not found", func(t *testing.T) {
+		messages.EXPECT().GetByID(ctx, int64(13)).Return(nil, customErr.ErrNotFound)
+
+		_, err := uc.Vote(ctx, 13, entity.VoteUp)
+		require.ErrorIs(t, err, ErrMessageNotFound)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, err := uc.Vote(ctx, 10, 2)
+		require.ErrorIs(t, err, ErrInvalidVote)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := uc.Vote(context.Background(), 10, entity.VoteUp)
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	})
+}
+
+func TestVoteUC_AnnotateMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	votes := mocks.NewMockVoteRepository(ctrl)
+	uc := NewVoteUsecase(votes, nil, nil, nil, mocks.FakeLogger{})
+
+	t.Run("marks my votes", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "user")
+		list := []*entity.Message{{ID: 1}, {ID: 2}}
+		votes.EXPECT().ByUser(ctx, int64(1), []int64{1, 2}).Return(map[int64]int{2: entity.VoteDown}, nil)
+
+		uc.AnnotateMessages(ctx, list...)
+		require.Equal(t, 0, list[0].MyVote)
+		require.Equal(t, entity.VoteDown, list[1].MyVote)
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		list := []*entity.Message{{ID: 1}}
+		uc.AnnotateMessages(context.Background(), list...)
+		require.Equal(t, 0, list[0].MyVote)
+	})
+}
+
+func TestReputationUC_Require(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	votes := mocks.NewMockVoteRepository(ctrl)
+	uc := NewReputationUsecase(votes, ReputationPolicy{Thresholds: map[string]int64{
+		entity.PrivilegeCreateTopic: 5,
+		entity.PrivilegePostLinks:   0,
+	}}, mocks.FakeLogger{})
+
+	t.Run("below threshold", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "user")
+		votes.EXPECT().Reputation(ctx, int64(1)).Return(int64(4), nil)
+
+		err := uc.Require(ctx, entity.PrivilegeCreateTopic)
+		var e *InsufficientReputationError
+		require.True(t, errors.As(err, &e))
+		require.Equal(t, int64(5), e.Required)
+		require.Equal(t, int64(4), e.Reputation)
+		require.Contains(t, err.Error(), "create topics")
+	})
+
+	t.Run("at threshold", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "user")
+		votes.EXPECT().Reputation(ctx, int64(1)).Return(int64(5), nil)
+
+		require.NoError(t, uc.Require(ctx, entity.PrivilegeCreateTopic))
+	})
+
+	t.Run("moderator bypasses", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 2, "moderator")
+		require.NoError(t, uc.Require(ctx, entity.PrivilegeCreateTopic))
+	})
+
+	t.Run("no threshold configured", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "user")
+		require.NoError(t, uc.Require(ctx, entity.PrivilegeDownvote))
+	})
+
+	t.Run("zero threshold is open even with negative reputation", func(t *testing.T) {
+		// репутация не читается: при -7 и пороге 0 прежняя проверка rep < required отказывала
+		ctx := auth.WithUser(context.Background(), 1, "user")
+
+		require.NoError(t, uc.Require(ctx, entity.PrivilegePostLinks))
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), 1, "user")
+		votes.EXPECT().Reputation(ctx, int64(1)).Return(int64(0), errors.New("db down"))
+
+		err := uc.Require(ctx, entity.PrivilegeCreateTopic)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrInsufficientReputation)
+	})
+}
+
+func TestMessageUC_SendMessage_Links(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockMessageRepository(ctrl)
+	topics := mocks.NewMockTopicRepository(ctrl)
+	privileges := mocks.NewMockPrivilegeChecker(ctrl)
+	publisher := mocks.NewMockMessagePublisher(ctrl)
+	uc := NewMessageUsecase(repo, topics, nil, nil, nil, MessageDeps{Privileges: privileges, Publisher: publisher}, mocks.FakeLogger{}, retention)
+
+	ctx := auth.WithUser(context.Background(), 1, "user")
+	params := SendMessageParams{TopicID: 10, AuthorID: 1, Content: "see https://example.com"}
+	denied := &InsufficientReputationError{Privilege: entity.PrivilegePostLinks, Required: 3}
+
+	t.Run("link needs reputation", func(t *testing.T) {
+		topics.EXPECT().GetByID(ctx, int64(10)).Return(&entity.Topic{ID: 10, CategoryID: 4}, nil).AnyTimes()
+		privileges.EXPECT().Require(ctx, entity.PrivilegePostLinks).Return(denied)
+
+		_, err := uc.SendMessage(ctx, params)
+		require.ErrorIs(t, err, ErrInsufficientReputation)
+	})
+
+	t.Run("code is not a link", func(t *testing.T) {
+		repo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		publisher.EXPECT().Publish(int64(10), gomock.Any())
+
+		_, err := uc.SendMessage(ctx, SendMessageParams{TopicID: 10, AuthorID: 1, Content: "`https://example.com`"})
+		require.NoError(t, err)
+	})
+}